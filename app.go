@@ -1,6 +1,7 @@
 package apicat
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/url"
@@ -13,6 +14,11 @@ import (
 	"github.com/apicat/apicat/v2/backend/module/mock"
 	"github.com/apicat/apicat/v2/backend/module/storage"
 	"github.com/apicat/apicat/v2/backend/route"
+	embeddingservice "github.com/apicat/apicat/v2/backend/service/embedding"
+	"github.com/apicat/apicat/v2/backend/service/history"
+	jobservice "github.com/apicat/apicat/v2/backend/service/job"
+	"github.com/apicat/apicat/v2/backend/service/trash"
+	"github.com/apicat/apicat/v2/backend/service/webhook"
 	"github.com/apicat/apicat/v2/backend/utils/logger"
 )
 
@@ -57,6 +63,12 @@ func (a *App) Run() error {
 		return err
 	}
 
+	go webhook.Run(context.Background())
+	go history.Run(context.Background())
+	go trash.Run(context.Background())
+	go jobservice.Run(context.Background())
+	go embeddingservice.Run(context.Background())
+
 	if err := route.Init(); err != nil {
 		return fmt.Errorf("init route err: %v", err)
 	}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"time"
 )
 
 type App struct {
@@ -11,15 +12,22 @@ type App struct {
 	AppServerBind  string `yaml:"AppServerBind"`
 	MockUrl        string `yaml:"MockUrl"`
 	MockServerBind string `yaml:"MockServerBind"`
+	// 回收站保留天数，超过后自动彻底删除，默认0表示不自动删除
+	// 开启后首次清理会删除所有已超过保留期的内容，也可通过APICAT_TRASH_RETENTION_DAYS设置
+	TrashRetentionDays int `yaml:"TrashRetentionDays"`
+	// 后台任务并发数
+	JobConcurrency int `yaml:"JobConcurrency"`
 }
 
 func GetAppDefault() *App {
 	return &App{
-		AppName:        "ApiCat",
-		AppUrl:         "http://localhost:8000",
-		AppServerBind:  "0.0.0.0:8000",
-		MockUrl:        "http://localhost:8001",
-		MockServerBind: "0.0.0.0:8001",
+		AppName:            "ApiCat",
+		AppUrl:             "http://localhost:8000",
+		AppServerBind:      "0.0.0.0:8000",
+		MockUrl:            "http://localhost:8001",
+		MockServerBind:     "0.0.0.0:8001",
+		TrashRetentionDays: 0,
+		JobConcurrency:     2,
 	}
 }
 
@@ -29,6 +37,14 @@ func SetApp(appConfig *App) {
 	globalConf.App.MockUrl = appConfig.MockUrl
 }
 
+// TrashDeadline 回收站中早于该时间删除的内容已过期，不限制时返回零值
+func (a *App) TrashDeadline(now time.Time) time.Time {
+	if a.TrashRetentionDays <= 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -a.TrashRetentionDays)
+}
+
 func (a *App) ToMapInterface() map[string]interface{} {
 	var (
 		res      map[string]interface{}
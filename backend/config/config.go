@@ -42,6 +42,16 @@ func LoadFromEnv() {
 	if v, exists := os.LookupEnv("APICAT_MOCK_SERVER_BIND"); exists {
 		globalConf.App.MockServerBind = v
 	}
+	if v, exists := os.LookupEnv("APICAT_TRASH_RETENTION_DAYS"); exists {
+		if i, err := strconv.Atoi(v); err == nil {
+			globalConf.App.TrashRetentionDays = i
+		}
+	}
+	if v, exists := os.LookupEnv("APICAT_JOB_CONCURRENCY"); exists {
+		if i, err := strconv.Atoi(v); err == nil {
+			globalConf.App.JobConcurrency = i
+		}
+	}
 	if v, exists := os.LookupEnv("APICAT_DEBUG"); exists {
 		globalConf.Database.Debug = strings.ToLower(v) == "true"
 	}
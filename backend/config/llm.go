@@ -2,13 +2,18 @@ package config
 
 import (
 	"github.com/apicat/apicat/v2/backend/module/llm"
+	"github.com/apicat/apicat/v2/backend/module/llm/anthropic"
+	"github.com/apicat/apicat/v2/backend/module/llm/ollama"
 	"github.com/apicat/apicat/v2/backend/module/llm/openai"
 )
 
 type LLM struct {
-	Driver      string       `yaml:"Driver"`
-	OpenAI      *OpenAI      `yaml:"OpenAI"`
-	AzureOpenAI *AzureOpenAI `yaml:"AzureOpenAI"`
+	Driver           string            `yaml:"Driver"`
+	OpenAI           *OpenAI           `yaml:"OpenAI"`
+	AzureOpenAI      *AzureOpenAI      `yaml:"AzureOpenAI"`
+	OpenAICompatible *OpenAICompatible `yaml:"OpenAICompatible"`
+	Ollama           *Ollama           `yaml:"Ollama"`
+	Anthropic        *Anthropic        `yaml:"Anthropic"`
 }
 
 type OpenAI struct {
@@ -28,10 +33,53 @@ type AzureOpenAI struct {
 	Timeout       int    `yaml:"Timeout" json:"timeout"`
 }
 
+type OpenAICompatible struct {
+	ApiKey        string `yaml:"ApiKey" json:"apiKey"`
+	ApiBase       string `yaml:"ApiBase" json:"apiBase"`
+	LLMName       string `yaml:"LLMName" json:"llmName"`
+	EmbeddingName string `yaml:"EmbeddingName" json:"embeddingName"`
+	Timeout       int    `yaml:"Timeout" json:"timeout"`
+}
+
+type Ollama struct {
+	Host          string `yaml:"Host" json:"host"`
+	LLMName       string `yaml:"LLMName" json:"llmName"`
+	EmbeddingName string `yaml:"EmbeddingName" json:"embeddingName"`
+	Timeout       int    `yaml:"Timeout" json:"timeout"`
+}
+
+type Anthropic struct {
+	ApiKey  string `yaml:"ApiKey" json:"apiKey"`
+	ApiBase string `yaml:"ApiBase" json:"apiBase"`
+	LLMName string `yaml:"LLMName" json:"llmName"`
+	Timeout int    `yaml:"Timeout" json:"timeout"`
+}
+
 func SetLLM(c *LLM) {
 	globalConf.LLM = c
 }
 
+// ModelName 当前使用的模型，格式为 driver/model
+func (l *LLM) ModelName() string {
+	if l == nil {
+		return ""
+	}
+	var name string
+	switch {
+	case l.Driver == llm.OPENAI && l.OpenAI != nil:
+		name = l.OpenAI.LLMName
+	case l.Driver == llm.AZUREOPENAI && l.AzureOpenAI != nil:
+		name = l.AzureOpenAI.LLMName
+	case l.Driver == llm.OPENAICOMPATIBLE && l.OpenAICompatible != nil:
+		name = l.OpenAICompatible.LLMName
+	case l.Driver == llm.OLLAMA && l.Ollama != nil:
+		name = l.Ollama.LLMName
+	case l.Driver == llm.ANTHROPIC && l.Anthropic != nil:
+		name = l.Anthropic.LLMName
+	}
+	return l.Driver + "/" + name
+}
+
 func (l *LLM) ToCfg() llm.LLM {
 	if l == nil {
 		return llm.LLM{}
@@ -59,6 +107,46 @@ func (l *LLM) ToCfg() llm.LLM {
 				EmbeddingName: l.AzureOpenAI.EmbeddingName,
 			},
 		}
+	case llm.OPENAICOMPATIBLE:
+		if l.OpenAICompatible == nil {
+			return llm.LLM{}
+		}
+		return llm.LLM{
+			Driver: l.Driver,
+			OpenAICompatible: openai.OpenAICompatible{
+				ApiKey:        l.OpenAICompatible.ApiKey,
+				ApiBase:       l.OpenAICompatible.ApiBase,
+				LLMName:       l.OpenAICompatible.LLMName,
+				EmbeddingName: l.OpenAICompatible.EmbeddingName,
+				Timeout:       l.OpenAICompatible.Timeout,
+			},
+		}
+	case llm.OLLAMA:
+		if l.Ollama == nil {
+			return llm.LLM{}
+		}
+		return llm.LLM{
+			Driver: l.Driver,
+			Ollama: ollama.Ollama{
+				Host:          l.Ollama.Host,
+				LLMName:       l.Ollama.LLMName,
+				EmbeddingName: l.Ollama.EmbeddingName,
+				Timeout:       l.Ollama.Timeout,
+			},
+		}
+	case llm.ANTHROPIC:
+		if l.Anthropic == nil {
+			return llm.LLM{}
+		}
+		return llm.LLM{
+			Driver: l.Driver,
+			Anthropic: anthropic.Anthropic{
+				ApiKey:  l.Anthropic.ApiKey,
+				ApiBase: l.Anthropic.ApiBase,
+				LLMName: l.Anthropic.LLMName,
+				Timeout: l.Anthropic.Timeout,
+			},
+		}
 	default:
 		return llm.LLM{}
 	}
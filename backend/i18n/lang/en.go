@@ -69,6 +69,8 @@ var En = map[string]map[string]string{
 		"FailedToGetList":                  "Failed to get user list, please try again later.",
 		"DoesNotExist":                     "User does not exist.",
 		"FailedToDelete":                   "Failed to delete user, please try again later.",
+		"AccountLocked":                    "Your account has been temporarily locked due to too many failed login attempts, please try again later or contact the administrator.",
+		"FailedToUnlock":                   "Failed to unlock user, please try again later.",
 	},
 	"team": {
 		"CreationFailed":             "Team creation failed, please try again later.",
@@ -110,6 +112,7 @@ var En = map[string]map[string]string{
 		"FailedToUnfollowProject":  "Failed to unfollow project, please try again later.",
 		"ExportFailed":             "Project export failed, please try again later.",
 		"NotSupportFileType":       "%s is not supported.",
+		"RestoreFailed":            "Project restore failed, please try again later.",
 	},
 	"projectGroup": {
 		"DoesNotExist":          "Project group does not exist.",
@@ -130,6 +133,19 @@ var En = map[string]map[string]string{
 		"RemoveFailed":             "Project member remove failed, please try again later.",
 		"NotInTheProject":          "The member is not in the project.",
 	},
+	"customRole": {
+		"DoesNotExist":       "Role does not exist.",
+		"CreationFailed":     "Role creation failed, please try again later.",
+		"FailedToGet":        "Failed to get role, please try again later.",
+		"FailedToGetList":    "Failed to get role list, please try again later.",
+		"FailedToDelete":     "Failed to delete role, please try again later.",
+		"NameHasBeenUsed":    "This role name has already been used.",
+		"OnlyForWriteMember": "Custom roles can only be assigned to members with write permission.",
+	},
+	"auditLog": {
+		"FailedToGetList": "Failed to get audit log list, please try again later.",
+		"ExportFailed":    "Audit log export failed, please try again later.",
+	},
 	"projectServer": {
 		"CreationFailed":  "Project server URL creation failed, please try again later.",
 		"HasBeenUsed":     "This URL has already been used.",
@@ -137,6 +153,179 @@ var En = map[string]map[string]string{
 		"FailedToDelete":  "Failed to delete project server URL, please try again later.",
 		"SortingFailed":   "Project server URL sorting failed, please try again later.",
 	},
+	"webhook": {
+		"CreationFailed":        "Webhook creation failed, please try again later.",
+		"FailedToGet":           "Failed to get webhook, please try again later.",
+		"FailedToGetList":       "Failed to get webhook list, please try again later.",
+		"DoesNotExist":          "Webhook does not exist.",
+		"FailedToDelete":        "Failed to delete webhook, please try again later.",
+		"UnsupportedEvent":      "Event %s is not supported.",
+		"FailedToSend":          "Failed to send webhook, please try again later.",
+		"FailedToGetDeliveries": "Failed to get webhook deliveries, please try again later.",
+		"DeliveryDoesNotExist":  "Webhook delivery does not exist.",
+		"DeliveryIsPending":     "The delivery is waiting to be sent.",
+		"InvalidURL":            "The URL must use http or https and cannot point to a loopback, private or link-local address.",
+	},
+	"notificationChannel": {
+		"CreationFailed":  "Notification channel creation failed, please try again later.",
+		"FailedToGet":     "Failed to get notification channel, please try again later.",
+		"FailedToGetList": "Failed to get notification channel list, please try again later.",
+		"DoesNotExist":    "Notification channel does not exist.",
+		"FailedToDelete":  "Failed to delete notification channel, please try again later.",
+		"FailedToSend":    "Failed to send test message: %s",
+	},
+	"breakingChange": {
+		"EndpointPathChanged":         "Path changed from %[2]s to %[3]s.",
+		"EndpointMethodChanged":       "Method changed from %[2]s to %[3]s.",
+		"PathParamRenamed":            "Path parameter %s was renamed to %s.",
+		"RequestFieldAdded":           "Optional request field %s was added.",
+		"RequiredRequestFieldAdded":   "Required request field %s was added.",
+		"RequestFieldRemoved":         "Request field %s was removed.",
+		"RequestFieldBecameRequired":  "Request field %s became required.",
+		"RequestFieldBecameOptional":  "Request field %s became optional.",
+		"ResponseFieldAdded":          "Response field %s was added.",
+		"ResponseFieldRemoved":        "Response field %s was removed.",
+		"ResponseFieldBecameRequired": "Response field %s is now always returned.",
+		"ResponseFieldBecameOptional": "Response field %s may no longer be returned.",
+		"ResponseStatusAdded":         "Response %s was added.",
+		"ResponseStatusRemoved":       "Response %s was removed.",
+		"ContentTypeChanged":          "Content type of %s changed from %s to %s.",
+		"ReferenceChanged":            "%[1]s now references %[3]s instead of %[2]s.",
+		"TypeChanged":                 "Type of %s changed from %s to %s.",
+		"TypeNarrowed":                "Type of %s was narrowed from %s to %s.",
+		"TypeWidened":                 "Type of %s was widened from %s to %s.",
+		"EnumValueAdded":              "Enum value %[2]s was added to %[1]s.",
+		"EnumValueRemoved":            "Enum value %[2]s was removed from %[1]s.",
+		"ConstraintNarrowed":          "The %[2]s constraint of %[1]s was tightened from %[3]s to %[4]s.",
+		"ConstraintRelaxed":           "The %[2]s constraint of %[1]s was relaxed from %[3]s to %[4]s.",
+	},
+	"changelog": {
+		"DiffFailed":           "Failed to compare the project, please try again later.",
+		"ExportFailed":         "Failed to export the changelog, please try again later.",
+		"BaseRequired":         "Please choose a project or a start time to compare with.",
+		"InvalidTimeRange":     "The end time must be later than the start time.",
+		"Title":                "%s Changelog",
+		"Range":                "Changes from %s to %s.",
+		"Now":                  "now",
+		"NoChanges":            "No changes.",
+		"Breaking":             "This release contains breaking changes.",
+		"Summary":              "Endpoints: %s added, %s removed, %s changed. Models: %s added, %s removed, %s changed.",
+		"AddedEndpoints":       "Added endpoints",
+		"RemovedEndpoints":     "Removed endpoints",
+		"ChangedEndpoints":     "Changed endpoints",
+		"AddedModels":          "Added models",
+		"RemovedModels":        "Removed models",
+		"ChangedModels":        "Changed models",
+		"FieldAdded":           "Added %s",
+		"FieldRemoved":         "Removed %s",
+		"FieldUpdated":         "Updated %s",
+		"Compatibility":        "Compatibility",
+		"breaking":             "Breaking",
+		"potentially_breaking": "Potentially breaking",
+		"non_breaking":         "Non-breaking",
+	},
+	"release": {
+		"CreationFailed":  "Release creation failed, please try again later.",
+		"FailedToGet":     "Failed to get release, please try again later.",
+		"FailedToGetList": "Failed to get release list, please try again later.",
+		"DoesNotExist":    "Release does not exist.",
+		"FailedToDelete":  "Failed to delete release, please try again later.",
+		"AlreadyExists":   "Version %s already exists or was used by a deleted release.",
+		"InvalidVersion":  "The version can only contain letters, numbers and ._+-, and must start with a letter or number.",
+		"DiffFailed":      "Failed to compare releases, please try again later.",
+		"ExportFailed":    "Failed to export release, please try again later.",
+	},
+	"iterationBranch": {
+		"FailedToGet":         "Failed to get iteration branch, please try again later.",
+		"ModificationFailed":  "Failed to modify iteration branch, please try again later.",
+		"ItemDoesNotExist":    "The change does not exist in the iteration branch.",
+		"UnsupportedType":     "Only APIs, docs and schemas can be edited in an iteration branch.",
+		"Empty":               "There are no changes in the iteration branch.",
+		"OpenRequestExists":   "There is already an open merge request for this iteration.",
+		"RequestDoesNotExist": "Merge request does not exist.",
+		"RequestNotOpen":      "The merge request has already been merged or closed.",
+		"CreationFailed":      "Merge request creation failed, please try again later.",
+		"FailedToGetRequest":  "Failed to get merge request, please try again later.",
+		"MergeFailed":         "Merge failed, please try again later.",
+		"CloseFailed":         "Failed to close merge request, please try again later.",
+		"Conflicts":           "There are unresolved conflicts with the mainline, please resolve them before merging.",
+		"Mainline":            "Mainline",
+	},
+	"proposal": {
+		"Submitted":                "This item requires approval, your changes have been submitted as a change proposal.",
+		"SubmitFailed":             "Failed to submit change proposal, please try again later.",
+		"FailedToGet":              "Failed to get change proposal, please try again later.",
+		"DoesNotExist":             "Change proposal does not exist.",
+		"NotPending":               "The change proposal has already been approved, rejected or withdrawn.",
+		"TargetDoesNotExist":       "The API or schema of the change proposal no longer exists.",
+		"TargetProtected":          "This item requires approval to change and cannot be deleted, restored or overwritten directly, please remove its protection first.",
+		"Outdated":                 "The API or schema has been modified since the proposal was submitted, please submit a new proposal.",
+		"CannotReviewOwn":          "You cannot approve or reject your own change proposal.",
+		"ApproveFailed":            "Failed to approve change proposal, please try again later.",
+		"RejectFailed":             "Failed to reject change proposal, please try again later.",
+		"WithdrawFailed":           "Failed to withdraw change proposal, please try again later.",
+		"OnlyAuthorCanWithdraw":    "Only the author can withdraw the change proposal.",
+		"CommentFailed":            "Failed to add comment, please try again later.",
+		"DiffFailed":               "Failed to compare the change proposal, please try again later.",
+		"FailedToGetProtections":   "Failed to get protected items, please try again later.",
+		"ProtectionExists":         "This item already requires approval.",
+		"ProtectionDoesNotExist":   "Protected item does not exist.",
+		"ProtectionCreationFailed": "Failed to protect item, please try again later.",
+		"ProtectionDeleteFailed":   "Failed to remove protection, please try again later.",
+		"CategoryNotSupported":     "Categories cannot require approval.",
+	},
+	"definitionResponseHistory": {
+		"FailedToGetList": "Failed to get response history list, please try again later.",
+		"FailedToGet":     "Failed to get response history, please try again later.",
+		"DoesNotExist":    "Response history does not exist.",
+		"RestoreFailed":   "Response restore failed, please try again later.",
+		"DiffFailed":      "Response comparison failed, please try again later.",
+	},
+	"globalParameterHistory": {
+		"FailedToGetList": "Failed to get global parameter history list, please try again later.",
+		"FailedToGet":     "Failed to get global parameter history, please try again later.",
+		"DoesNotExist":    "Global parameter history does not exist.",
+		"RestoreFailed":   "Global parameter restore failed, please try again later.",
+		"DiffFailed":      "Global parameter comparison failed, please try again later.",
+	},
+	"timeline": {
+		"FailedToGetList": "Failed to get recent changes, please try again later.",
+	},
+	"historyRetention": {
+		"FailedToGet":    "Failed to get history retention policy, please try again later.",
+		"FailedToUpdate": "Failed to update history retention policy, please try again later.",
+		"InvalidDays":    "Daily snapshot days should be less than retention days.",
+	},
+	"job": {
+		"FailedToGet":     "Failed to get job, please try again later.",
+		"FailedToGetList": "Failed to get job list, please try again later.",
+		"DoesNotExist":    "Job does not exist.",
+		"CreationFailed":  "Job creation failed, please try again later.",
+		"CancelFailed":    "Failed to cancel job, please try again later.",
+		"AlreadyFinished": "The job has already finished.",
+		"NotSucceeded":    "The job has not completed successfully.",
+	},
+	"aiReview": {
+		"ReviewFailed":         "AI review failed, please try again later.",
+		"TargetDoesNotExist":   "The API or model to be modified does not exist.",
+		"PatchCannotBeApplied": "The suggested fix cannot be applied to the current content, please review again.",
+	},
+	"semanticSearch": {
+		"Failed":        "Search failed, please try again later.",
+		"NotConfigured": "Semantic search is not available, please configure an embedding model first.",
+	},
+	"assistant": {
+		"AnswerFailed":     "Failed to answer the question, please try again later.",
+		"TooManyQuestions": "Too many questions have been asked, please try again later.",
+	},
+	"aiUsage": {
+		"QuotaExceeded":         "Your team has used up its AI token quota, please try again later or contact the administrator.",
+		"QuotaDoesNotExist":     "Quota does not exist.",
+		"FailedToGetReport":     "Failed to get AI usage report.",
+		"FailedToUpdateQuota":   "Failed to update AI quota.",
+		"FailedToDeleteQuota":   "Failed to delete AI quota.",
+		"FailedToUpdatePricing": "Failed to update model pricing.",
+	},
 	"globalParameter": {
 		"CreationFailed":  "Global parameter creation failed, please try again later.",
 		"HasBeenUsed":     "This parameter has already been used.",
@@ -146,14 +335,16 @@ var En = map[string]map[string]string{
 		"FailedToSort":    "Failed to sort global parameter, please try again later.",
 	},
 	"definitionSchema": {
-		"CreationFailed":   "Schema creation failed, please try again later.",
-		"GenerationFailed": "Schema generation failed, please try again later.",
-		"FailedToGetList":  "Failed to get schema list, please try again later.",
-		"FailedToGet":      "Failed to get schema, please try again later.",
-		"DoesNotExist":     "Schema does not exist.",
-		"FailedToDelete":   "Failed to delete schema, please try again later.",
-		"FailedToMove":     "Failed to move schema, please try again later.",
-		"CopyFailed":       "Schema copy failed, please try again later.",
+		"CreationFailed":    "Schema creation failed, please try again later.",
+		"GenerationFailed":  "Schema generation failed, please try again later.",
+		"SourceParseFailed": "Unable to parse the source, please check its syntax.",
+		"FailedToGetList":   "Failed to get schema list, please try again later.",
+		"FailedToGet":       "Failed to get schema, please try again later.",
+		"DoesNotExist":      "Schema does not exist.",
+		"FailedToDelete":    "Failed to delete schema, please try again later.",
+		"FailedToMove":      "Failed to move schema, please try again later.",
+		"CopyFailed":        "Schema copy failed, please try again later.",
+		"RestoreFailed":     "Schema restore failed, please try again later.",
 	},
 	"definitionSchemaHistory": {
 		"FailedToGetList": "Failed to get schema history list, please try again later.",
@@ -170,13 +361,21 @@ var En = map[string]map[string]string{
 		"FailedToDelete":  "Failed to delete response, please try again later.",
 		"FailedToMove":    "Failed to move response, please try again later.",
 		"CopyFailed":      "Response copy failed, please try again later.",
+		"RestoreFailed":   "Response restore failed, please try again later.",
 	},
 	"iteration": {
-		"DoesNotExist":    "Iteration does not exist.",
-		"CreationFailed":  "Iteration creation failed, please try again later.",
-		"FailedToGetList": "Failed to get iteration list, please try again later.",
-		"FailedToGet":     "Failed to get iteration, please try again later.",
-		"FailedToDelete":  "Failed to delete iteration, please try again later.",
+		"DoesNotExist":         "Iteration does not exist.",
+		"CreationFailed":       "Iteration creation failed, please try again later.",
+		"FailedToGetList":      "Failed to get iteration list, please try again later.",
+		"FailedToGet":          "Failed to get iteration, please try again later.",
+		"FailedToDelete":       "Failed to delete iteration, please try again later.",
+		"InvalidDateRange":     "The due date cannot be earlier than the start date.",
+		"FailedToGetProgress":  "Failed to get iteration progress, please try again later.",
+		"ProgressUpdateFailed": "Failed to update API progress, please try again later.",
+		"ApiNotInIteration":    "The API is not planned in this iteration.",
+		"AssigneeNotInProject": "The assignee is not a member of the project.",
+		"FailedToGetReport":    "Failed to get the iteration change report, please try again later.",
+		"ReportExportFailed":   "Failed to export the iteration change report, please try again later.",
 	},
 	"collection": {
 		"FailedToGetList":  "Failed to get API list, please try again later.",
@@ -202,6 +401,11 @@ var En = map[string]map[string]string{
 		"DoesNotExist":       "Test case does not exist.",
 		"RegenerationFailed": "Test case regeneration failed, please try again later.",
 		"FailedToDelete":     "Failed to delete test case, please try again later.",
+		"InvalidSpec":        "Invalid test steps: %s.",
+		"FailedToUpdate":     "Failed to update test case, please try again later.",
+		"NotExecutable":      "This test case has no executable steps.",
+		"FailedToRun":        "Failed to run test case, please try again later.",
+		"ServerDoesNotExist": "Project server URL does not exist.",
 	},
 	"mock": {
 		"FailedToMock": "Failed to mock, please try again later.",
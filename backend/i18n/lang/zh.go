@@ -69,6 +69,8 @@ var Zh = map[string]map[string]string{
 		"FailedToGetList":                  "获取用户列表失败，请稍后重试。",
 		"DoesNotExist":                     "用户不存在。",
 		"FailedToDelete":                   "删除用户失败，请稍后重试。",
+		"AccountLocked":                    "登录失败次数过多，您的账号已被临时锁定，请稍后重试或联系管理员。",
+		"FailedToUnlock":                   "解除用户锁定失败，请稍后重试。",
 	},
 	"team": {
 		"CreationFailed":             "创建团队失败，请稍后重试。",
@@ -110,6 +112,7 @@ var Zh = map[string]map[string]string{
 		"FailedToUnfollowProject":  "无法取关项目，请稍后重试。",
 		"ExportFailed":             "项目导出失败，请稍后重试。",
 		"NotSupportFileType":       "不支持 %s。",
+		"RestoreFailed":            "项目恢复失败，请稍后重试。",
 	},
 	"projectGroup": {
 		"DoesNotExist":          "项目分组不存在。",
@@ -130,6 +133,19 @@ var Zh = map[string]map[string]string{
 		"RemoveFailed":             "项目成员删除失败，请稍后重试。",
 		"NotInTheProject":          "成员不在项目中。",
 	},
+	"customRole": {
+		"DoesNotExist":       "角色不存在。",
+		"CreationFailed":     "创建角色失败，请稍后重试。",
+		"FailedToGet":        "获取角色失败，请稍后重试。",
+		"FailedToGetList":    "获取角色列表失败，请稍后重试。",
+		"FailedToDelete":     "删除角色失败，请稍后重试。",
+		"NameHasBeenUsed":    "该角色名称已被使用。",
+		"OnlyForWriteMember": "自定义角色只能分配给拥有编辑权限的成员。",
+	},
+	"auditLog": {
+		"FailedToGetList": "获取审计日志列表失败，请稍后重试。",
+		"ExportFailed":    "审计日志导出失败，请稍后重试。",
+	},
 	"projectServer": {
 		"CreationFailed":  "服务器 URL 创建失败，请稍后重试。",
 		"HasBeenUsed":     "URL 已被使用。",
@@ -137,6 +153,179 @@ var Zh = map[string]map[string]string{
 		"FailedToDelete":  "删除服务器 URL 失败，请稍后重试。",
 		"SortingFailed":   "服务器 URL 排序失败，请稍后重试。",
 	},
+	"webhook": {
+		"CreationFailed":        "Webhook 创建失败，请稍后重试。",
+		"FailedToGet":           "获取 Webhook 失败，请稍后重试。",
+		"FailedToGetList":       "获取 Webhook 列表失败，请稍后重试。",
+		"DoesNotExist":          "Webhook 不存在。",
+		"FailedToDelete":        "删除 Webhook 失败，请稍后重试。",
+		"UnsupportedEvent":      "不支持事件 %s。",
+		"FailedToSend":          "Webhook 发送失败，请稍后重试。",
+		"FailedToGetDeliveries": "获取 Webhook 投递记录失败，请稍后重试。",
+		"DeliveryDoesNotExist":  "投递记录不存在。",
+		"DeliveryIsPending":     "该投递正在等待发送。",
+		"InvalidURL":            "URL 必须使用 http 或 https，且不能指向本机、内网或链路本地地址。",
+	},
+	"notificationChannel": {
+		"CreationFailed":  "通知渠道创建失败，请稍后重试。",
+		"FailedToGet":     "获取通知渠道失败，请稍后重试。",
+		"FailedToGetList": "获取通知渠道列表失败，请稍后重试。",
+		"DoesNotExist":    "通知渠道不存在。",
+		"FailedToDelete":  "删除通知渠道失败，请稍后重试。",
+		"FailedToSend":    "测试消息发送失败：%s",
+	},
+	"breakingChange": {
+		"EndpointPathChanged":         "请求路径由 %[2]s 改为 %[3]s。",
+		"EndpointMethodChanged":       "请求方法由 %[2]s 改为 %[3]s。",
+		"PathParamRenamed":            "路径参数 %s 被重命名为 %s。",
+		"RequestFieldAdded":           "新增了可选请求字段 %s。",
+		"RequiredRequestFieldAdded":   "新增了必填请求字段 %s。",
+		"RequestFieldRemoved":         "删除了请求字段 %s。",
+		"RequestFieldBecameRequired":  "请求字段 %s 变为必填。",
+		"RequestFieldBecameOptional":  "请求字段 %s 变为可选。",
+		"ResponseFieldAdded":          "新增了响应字段 %s。",
+		"ResponseFieldRemoved":        "删除了响应字段 %s。",
+		"ResponseFieldBecameRequired": "响应字段 %s 变为必定返回。",
+		"ResponseFieldBecameOptional": "响应字段 %s 可能不再返回。",
+		"ResponseStatusAdded":         "新增了响应 %s。",
+		"ResponseStatusRemoved":       "删除了响应 %s。",
+		"ContentTypeChanged":          "%s 的内容类型由 %s 改为 %s。",
+		"ReferenceChanged":            "%s 的引用由 %s 改为 %s。",
+		"TypeChanged":                 "%s 的类型由 %s 改为 %s。",
+		"TypeNarrowed":                "%s 的类型由 %s 收窄为 %s。",
+		"TypeWidened":                 "%s 的类型由 %s 放宽为 %s。",
+		"EnumValueAdded":              "%s 新增了枚举值 %s。",
+		"EnumValueRemoved":            "%s 删除了枚举值 %s。",
+		"ConstraintNarrowed":          "%s 的 %s 约束由 %s 收紧为 %s。",
+		"ConstraintRelaxed":           "%s 的 %s 约束由 %s 放宽为 %s。",
+	},
+	"changelog": {
+		"DiffFailed":           "项目对比失败，请稍后重试。",
+		"ExportFailed":         "变更日志导出失败，请稍后重试。",
+		"BaseRequired":         "请选择要对比的项目或开始时间。",
+		"InvalidTimeRange":     "结束时间必须晚于开始时间。",
+		"Title":                "%s 变更日志",
+		"Range":                "%s 至 %s 的变更。",
+		"Now":                  "当前",
+		"NoChanges":            "没有变更。",
+		"Breaking":             "本次发布包含不兼容的修改。",
+		"Summary":              "接口：新增 %s 个，删除 %s 个，修改 %s 个。模型：新增 %s 个，删除 %s 个，修改 %s 个。",
+		"AddedEndpoints":       "新增接口",
+		"RemovedEndpoints":     "删除接口",
+		"ChangedEndpoints":     "修改接口",
+		"AddedModels":          "新增模型",
+		"RemovedModels":        "删除模型",
+		"ChangedModels":        "修改模型",
+		"FieldAdded":           "新增 %s",
+		"FieldRemoved":         "删除 %s",
+		"FieldUpdated":         "修改 %s",
+		"Compatibility":        "兼容性",
+		"breaking":             "不兼容",
+		"potentially_breaking": "可能不兼容",
+		"non_breaking":         "兼容",
+	},
+	"release": {
+		"CreationFailed":  "版本发布失败，请稍后重试。",
+		"FailedToGet":     "获取发布版本失败，请稍后重试。",
+		"FailedToGetList": "获取发布版本列表失败，请稍后重试。",
+		"DoesNotExist":    "发布版本不存在。",
+		"FailedToDelete":  "删除发布版本失败，请稍后重试。",
+		"AlreadyExists":   "版本 %s 已存在或已被删除的发布版本使用。",
+		"InvalidVersion":  "版本号只能包含字母、数字和._+-，且必须以字母或数字开头。",
+		"DiffFailed":      "发布版本对比失败，请稍后重试。",
+		"ExportFailed":    "发布版本导出失败，请稍后重试。",
+	},
+	"iterationBranch": {
+		"FailedToGet":         "获取迭代分支失败，请稍后重试。",
+		"ModificationFailed":  "修改迭代分支失败，请稍后重试。",
+		"ItemDoesNotExist":    "迭代分支中不存在该修改。",
+		"UnsupportedType":     "迭代分支中只能编辑 API、文档和模型。",
+		"Empty":               "迭代分支中没有任何修改。",
+		"OpenRequestExists":   "该迭代已有未处理的合并请求。",
+		"RequestDoesNotExist": "合并请求不存在。",
+		"RequestNotOpen":      "合并请求已合并或已关闭。",
+		"CreationFailed":      "合并请求创建失败，请稍后重试。",
+		"FailedToGetRequest":  "获取合并请求失败，请稍后重试。",
+		"MergeFailed":         "合并失败，请稍后重试。",
+		"CloseFailed":         "关闭合并请求失败，请稍后重试。",
+		"Conflicts":           "与主线存在未解决的冲突，请解决后再合并。",
+		"Mainline":            "主线",
+	},
+	"proposal": {
+		"Submitted":                "该内容需要审批，修改已作为修改提案提交。",
+		"SubmitFailed":             "提交修改提案失败，请稍后重试。",
+		"FailedToGet":              "获取修改提案失败，请稍后重试。",
+		"DoesNotExist":             "修改提案不存在。",
+		"NotPending":               "修改提案已被批准、驳回或撤回。",
+		"TargetDoesNotExist":       "修改提案对应的 API 或模型已不存在。",
+		"TargetProtected":          "该内容需要审批才能修改，不能直接删除、恢复或覆盖，请先取消保护。",
+		"Outdated":                 "提交提案后 API 或模型已被修改，请重新提交修改提案。",
+		"CannotReviewOwn":          "不能审批自己提交的修改提案。",
+		"ApproveFailed":            "批准修改提案失败，请稍后重试。",
+		"RejectFailed":             "驳回修改提案失败，请稍后重试。",
+		"WithdrawFailed":           "撤回修改提案失败，请稍后重试。",
+		"OnlyAuthorCanWithdraw":    "只有提交人可以撤回修改提案。",
+		"CommentFailed":            "评论失败，请稍后重试。",
+		"DiffFailed":               "对比修改提案失败，请稍后重试。",
+		"FailedToGetProtections":   "获取需要审批的内容失败，请稍后重试。",
+		"ProtectionExists":         "该内容已需要审批。",
+		"ProtectionDoesNotExist":   "需要审批的内容不存在。",
+		"ProtectionCreationFailed": "设置审批失败，请稍后重试。",
+		"ProtectionDeleteFailed":   "取消审批失败，请稍后重试。",
+		"CategoryNotSupported":     "目录不能设置审批。",
+	},
+	"definitionResponseHistory": {
+		"FailedToGetList": "获取响应历史列表失败，请稍后重试。",
+		"FailedToGet":     "获取响应历史记录失败，请稍后重试。",
+		"DoesNotExist":    "响应历史不存在。",
+		"RestoreFailed":   "响应恢复失败，请稍后重试。",
+		"DiffFailed":      "响应对比失败，请稍后重试。",
+	},
+	"globalParameterHistory": {
+		"FailedToGetList": "获取全局参数历史列表失败，请稍后重试。",
+		"FailedToGet":     "获取全局参数历史记录失败，请稍后重试。",
+		"DoesNotExist":    "全局参数历史不存在。",
+		"RestoreFailed":   "全局参数恢复失败，请稍后重试。",
+		"DiffFailed":      "全局参数对比失败，请稍后重试。",
+	},
+	"timeline": {
+		"FailedToGetList": "获取项目动态失败，请稍后重试。",
+	},
+	"historyRetention": {
+		"FailedToGet":    "获取历史记录保留策略失败，请稍后重试。",
+		"FailedToUpdate": "修改历史记录保留策略失败，请稍后重试。",
+		"InvalidDays":    "每日快照天数应小于保留天数。",
+	},
+	"job": {
+		"FailedToGet":     "获取任务失败，请稍后再试。",
+		"FailedToGetList": "获取任务列表失败，请稍后再试。",
+		"DoesNotExist":    "任务不存在。",
+		"CreationFailed":  "创建任务失败，请稍后再试。",
+		"CancelFailed":    "取消任务失败，请稍后再试。",
+		"AlreadyFinished": "任务已结束。",
+		"NotSucceeded":    "任务尚未成功完成。",
+	},
+	"aiReview": {
+		"ReviewFailed":         "AI 评审失败，请稍后再试。",
+		"TargetDoesNotExist":   "要修改的接口或模型不存在。",
+		"PatchCannotBeApplied": "修改建议无法应用到当前内容，请重新评审。",
+	},
+	"semanticSearch": {
+		"Failed":        "搜索失败，请稍后再试。",
+		"NotConfigured": "语义搜索不可用，请先配置向量模型。",
+	},
+	"assistant": {
+		"AnswerFailed":     "回答问题失败，请稍后再试。",
+		"TooManyQuestions": "提问次数过多，请稍后再试。",
+	},
+	"aiUsage": {
+		"QuotaExceeded":         "团队的 AI token 配额已用完，请稍后再试或联系管理员。",
+		"QuotaDoesNotExist":     "配额不存在。",
+		"FailedToGetReport":     "获取 AI 用量统计失败。",
+		"FailedToUpdateQuota":   "更新 AI 配额失败。",
+		"FailedToDeleteQuota":   "删除 AI 配额失败。",
+		"FailedToUpdatePricing": "更新模型价格失败。",
+	},
 	"globalParameter": {
 		"CreationFailed":  "全局参数创建失败，请稍后重试。",
 		"HasBeenUsed":     "该参数已被使用。",
@@ -146,14 +335,16 @@ var Zh = map[string]map[string]string{
 		"FailedToSort":    "全局参数排序失败，请稍后重试。",
 	},
 	"definitionSchema": {
-		"CreationFailed":   "模型创建失败，请稍后重试。",
-		"GenerationFailed": "模型生成失败，请稍后重试。",
-		"FailedToGetList":  "获取模型列表失败，请稍后重试。",
-		"FailedToGet":      "获取模型失败，请稍后重试。",
-		"DoesNotExist":     "模型不存在。",
-		"FailedToDelete":   "删除模型失败，请稍后重试。",
-		"FailedToMove":     "移动模型失败，请稍后重试。",
-		"CopyFailed":       "模型复制失败，请稍后重试。",
+		"CreationFailed":    "模型创建失败，请稍后重试。",
+		"GenerationFailed":  "模型生成失败，请稍后重试。",
+		"SourceParseFailed": "无法解析源码，请检查语法是否正确。",
+		"FailedToGetList":   "获取模型列表失败，请稍后重试。",
+		"FailedToGet":       "获取模型失败，请稍后重试。",
+		"DoesNotExist":      "模型不存在。",
+		"FailedToDelete":    "删除模型失败，请稍后重试。",
+		"FailedToMove":      "移动模型失败，请稍后重试。",
+		"CopyFailed":        "模型复制失败，请稍后重试。",
+		"RestoreFailed":     "模型恢复失败，请稍后重试。",
 	},
 	"definitionSchemaHistory": {
 		"FailedToGetList": "获取模型历史列表失败，请稍后重试。",
@@ -170,13 +361,21 @@ var Zh = map[string]map[string]string{
 		"FailedToDelete":  "删除响应失败，请稍后重试。",
 		"FailedToMove":    "移动响应失败，请稍后重试。",
 		"CopyFailed":      "响应复制失败，请稍后重试。",
+		"RestoreFailed":   "响应恢复失败，请稍后重试。",
 	},
 	"iteration": {
-		"DoesNotExist":    "迭代不存在。",
-		"CreationFailed":  "迭代创建失败，请稍后重试。",
-		"FailedToGetList": "获取迭代列表失败，请稍后重试。",
-		"FailedToGet":     "获取迭代失败，请稍后重试。",
-		"FailedToDelete":  "删除迭代失败，请稍后重试。",
+		"DoesNotExist":         "迭代不存在。",
+		"CreationFailed":       "迭代创建失败，请稍后重试。",
+		"FailedToGetList":      "获取迭代列表失败，请稍后重试。",
+		"FailedToGet":          "获取迭代失败，请稍后重试。",
+		"FailedToDelete":       "删除迭代失败，请稍后重试。",
+		"InvalidDateRange":     "截止日期不能早于开始日期。",
+		"FailedToGetProgress":  "获取迭代进度失败，请稍后重试。",
+		"ProgressUpdateFailed": "更新接口进度失败，请稍后重试。",
+		"ApiNotInIteration":    "该接口不在此迭代中。",
+		"AssigneeNotInProject": "负责人不是项目成员。",
+		"FailedToGetReport":    "获取迭代变更报告失败，请稍后重试。",
+		"ReportExportFailed":   "导出迭代变更报告失败，请稍后重试。",
 	},
 	"collection": {
 		"FailedToGetList":  "获取 API 列表失败，请稍后重试。",
@@ -202,6 +401,11 @@ var Zh = map[string]map[string]string{
 		"DoesNotExist":       "测试用例不存在。",
 		"RegenerationFailed": "重新生成测试用例失败，请稍后重试。",
 		"FailedToDelete":     "删除测试用例失败，请稍后重试。",
+		"InvalidSpec":        "测试步骤不正确：%s。",
+		"FailedToUpdate":     "更新测试用例失败，请稍后重试。",
+		"NotExecutable":      "该测试用例没有可执行的测试步骤。",
+		"FailedToRun":        "执行测试用例失败，请稍后重试。",
+		"ServerDoesNotExist": "项目服务地址不存在。",
 	},
 	"mock": {
 		"FailedToMock": "Mock 失败，请稍后再试。",
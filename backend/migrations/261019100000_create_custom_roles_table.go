@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019100000",
+		Migrate: func(tx *gorm.DB) error {
+			type CustomRole struct {
+				ID           uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				TeamID       string `gorm:"type:varchar(24);index;not null;comment:team id"`
+				Name         string `gorm:"type:varchar(255);not null;comment:role name"`
+				Description  string `gorm:"type:varchar(255);comment:role description"`
+				Capabilities string `gorm:"type:varchar(1024);not null;comment:role capabilities"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&CustomRole{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&CustomRole{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019100100",
+		Migrate: func(tx *gorm.DB) error {
+			type ProjectMember struct {
+				RoleID uint `gorm:"type:bigint;not null;default:0;comment:custom role id"`
+			}
+			if tx.Migrator().HasTable(&ProjectMember{}) {
+				if !tx.Migrator().HasColumn(&ProjectMember{}, "role_id") {
+					return tx.Migrator().AddColumn(&ProjectMember{}, "RoleID")
+				}
+			}
+			return nil
+		},
+	}
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019100200",
+		Migrate: func(tx *gorm.DB) error {
+			type AuditLog struct {
+				ID         uint      `gorm:"type:bigint;primaryKey;autoIncrement"`
+				TeamID     string    `gorm:"type:varchar(24);index;not null;default:'';comment:team id, empty for system config"`
+				ProjectID  string    `gorm:"type:varchar(24);index;not null;default:'';comment:project id"`
+				UserID     uint      `gorm:"type:bigint;index;not null;comment:operator user id"`
+				UserName   string    `gorm:"type:varchar(255);comment:operator name"`
+				UserEmail  string    `gorm:"type:varchar(255);comment:operator email"`
+				Action     string    `gorm:"type:varchar(32);index;not null;comment:action"`
+				TargetType string    `gorm:"type:varchar(64);index;not null;comment:target type"`
+				TargetID   string    `gorm:"type:varchar(255);comment:target id"`
+				TargetName string    `gorm:"type:varchar(255);comment:target name"`
+				Changes    string    `gorm:"type:mediumtext;comment:before and after diff summary"`
+				IP         string    `gorm:"type:varchar(64);comment:client ip"`
+				CreatedAt  time.Time `gorm:"index"`
+			}
+
+			if tx.Migrator().HasTable(&AuditLog{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&AuditLog{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019100300",
+		Migrate: func(tx *gorm.DB) error {
+			type Webhook struct {
+				ID            uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				ProjectID     string `gorm:"type:varchar(24);index;not null;comment:project id"`
+				Name          string `gorm:"type:varchar(255);not null;comment:webhook name"`
+				URL           string `gorm:"type:varchar(1024);not null;comment:payload url"`
+				Secret        string `gorm:"type:varchar(255);comment:hmac secret"`
+				Events        string `gorm:"type:varchar(1024);not null;comment:subscribed events"`
+				Active        bool   `gorm:"type:tinyint;not null;default:1;comment:is active"`
+				MaxRetries    int    `gorm:"type:int(11);not null;default:5;comment:max retry times"`
+				RetryInterval int    `gorm:"type:int(11);not null;default:10;comment:first retry interval in seconds, doubled on each retry"`
+				CreatedBy     uint   `gorm:"type:bigint;not null;default:0;comment:team member id"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&Webhook{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&Webhook{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019100400",
+		Migrate: func(tx *gorm.DB) error {
+			type Delivery struct {
+				ID             uint      `gorm:"type:bigint;primaryKey;autoIncrement"`
+				WebhookID      uint      `gorm:"type:bigint;index;not null;comment:webhook id"`
+				ProjectID      string    `gorm:"type:varchar(24);index;not null;comment:project id"`
+				Event          string    `gorm:"type:varchar(64);not null;comment:event name"`
+				Payload        string    `gorm:"type:mediumtext;comment:request body"`
+				Status         string    `gorm:"type:varchar(32);index:idx_status_next;not null;comment:pending,success,failed"`
+				Attempts       int       `gorm:"type:int(11);not null;default:0;comment:attempt times"`
+				NextAttemptAt  time.Time `gorm:"type:datetime;index:idx_status_next;not null;comment:next attempt time"`
+				ResponseStatus int       `gorm:"type:int(11);not null;default:0;comment:last response status code"`
+				ResponseBody   string    `gorm:"type:text;comment:last response body"`
+				Error          string    `gorm:"type:varchar(1024);comment:last error"`
+				Duration       int64     `gorm:"type:bigint;not null;default:0;comment:last request duration in milliseconds"`
+				CreatedAt      time.Time
+				UpdatedAt      time.Time
+			}
+
+			if tx.Migrator().HasTable(&Delivery{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&Delivery{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019100500",
+		Migrate: func(tx *gorm.DB) error {
+			type Channel struct {
+				ID        uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				ProjectID string `gorm:"type:varchar(24);index;not null;comment:project id"`
+				Name      string `gorm:"type:varchar(255);not null;comment:channel name"`
+				Type      string `gorm:"type:varchar(32);not null;comment:slack,dingtalk,feishu,wecom,webhook"`
+				URL       string `gorm:"type:varchar(1024);not null;comment:incoming webhook url"`
+				Secret    string `gorm:"type:varchar(255);comment:signing secret"`
+				Events    string `gorm:"type:varchar(1024);not null;comment:subscribed events"`
+				Active    bool   `gorm:"type:tinyint;not null;default:1;comment:is active"`
+				CreatedBy uint   `gorm:"type:bigint;not null;default:0;comment:team member id"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&Channel{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&Channel{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
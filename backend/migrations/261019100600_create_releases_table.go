@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019100600",
+		Migrate: func(tx *gorm.DB) error {
+			type Release struct {
+				ID          uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				ProjectID   string `gorm:"type:varchar(24);uniqueIndex:ukey;not null;comment:project id"`
+				Version     string `gorm:"type:varchar(64);uniqueIndex:ukey;not null;comment:release version"`
+				Description string `gorm:"type:text;comment:release notes"`
+				Spec        string `gorm:"type:longtext;comment:frozen spec content"`
+				CreatedBy   uint   `gorm:"type:bigint;not null;default:0;comment:team member id"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&Release{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&Release{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
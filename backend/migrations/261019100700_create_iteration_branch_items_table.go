@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019100700",
+		Migrate: func(tx *gorm.DB) error {
+			type IterationBranchItem struct {
+				ID              uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				IterationID     string `gorm:"type:varchar(24);index;not null;comment:iteration id"`
+				TargetType      string `gorm:"type:varchar(32);not null;comment:target type:collection,schema"`
+				TargetID        uint   `gorm:"type:bigint;not null;default:0;comment:mainline collection or schema id, 0 for created in branch"`
+				ParentID        uint   `gorm:"type:bigint;not null;default:0;comment:parent id when created in branch"`
+				Type            string `gorm:"type:varchar(255);not null;comment:collection or schema type"`
+				BaseTitle       string `gorm:"type:varchar(255);comment:mainline title or name at branch point"`
+				BaseDescription string `gorm:"type:varchar(255);comment:mainline schema description at branch point"`
+				BaseContent     string `gorm:"type:mediumtext;comment:mainline content or schema at branch point"`
+				Title           string `gorm:"type:varchar(255);not null;comment:branch title or name"`
+				Description     string `gorm:"type:varchar(255);comment:branch schema description"`
+				Content         string `gorm:"type:mediumtext;comment:branch content or schema"`
+				Deleted         bool   `gorm:"type:tinyint;not null;default:0;comment:deleted in branch"`
+				CreatedBy       uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+				UpdatedBy       uint   `gorm:"type:bigint;not null;default:0;comment:updated by member id"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&IterationBranchItem{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&IterationBranchItem{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019100800",
+		Migrate: func(tx *gorm.DB) error {
+			type IterationMergeRequest struct {
+				ID          uint       `gorm:"type:bigint;primaryKey;autoIncrement"`
+				IterationID string     `gorm:"type:varchar(24);index;not null;comment:iteration id"`
+				Title       string     `gorm:"type:varchar(255);not null;comment:merge request title"`
+				Description string     `gorm:"type:text;comment:merge request description"`
+				Status      string     `gorm:"type:varchar(32);not null;comment:status:open,merged,closed"`
+				CreatedBy   uint       `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+				ClosedBy    uint       `gorm:"type:bigint;not null;default:0;comment:merged or closed by member id"`
+				ClosedAt    *time.Time `gorm:"type:datetime;comment:merged or closed time"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&IterationMergeRequest{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&IterationMergeRequest{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
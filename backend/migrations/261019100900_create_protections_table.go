@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019100900",
+		Migrate: func(tx *gorm.DB) error {
+			type Protection struct {
+				ID         uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				ProjectID  string `gorm:"type:varchar(24);uniqueIndex:ukey;not null;comment:project id"`
+				TargetType string `gorm:"type:varchar(32);uniqueIndex:ukey;not null;comment:target type:collection,schema"`
+				TargetID   uint   `gorm:"type:bigint;uniqueIndex:ukey;not null;comment:collection or schema id"`
+				CreatedBy  uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&Protection{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&Protection{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019101000",
+		Migrate: func(tx *gorm.DB) error {
+			type Proposal struct {
+				ID              uint       `gorm:"type:bigint;primaryKey;autoIncrement"`
+				ProjectID       string     `gorm:"type:varchar(24);index;not null;comment:project id"`
+				TargetType      string     `gorm:"type:varchar(32);not null;comment:target type:collection,schema"`
+				TargetID        uint       `gorm:"type:bigint;not null;comment:collection or schema id"`
+				BaseTitle       string     `gorm:"type:varchar(255);comment:title or name when submitted"`
+				BaseDescription string     `gorm:"type:varchar(255);comment:schema description when submitted"`
+				BaseContent     string     `gorm:"type:mediumtext;comment:content or schema when submitted"`
+				Title           string     `gorm:"type:varchar(255);not null;comment:proposed title or name"`
+				Description     string     `gorm:"type:varchar(255);comment:proposed schema description"`
+				Content         string     `gorm:"type:mediumtext;comment:proposed content or schema"`
+				Status          string     `gorm:"type:varchar(32);index;not null;comment:status:pending,approved,rejected,withdrawn"`
+				CreatedBy       uint       `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+				ClosedBy        uint       `gorm:"type:bigint;not null;default:0;comment:approved, rejected or withdrawn by member id"`
+				ClosedAt        *time.Time `gorm:"type:datetime;comment:approved, rejected or withdrawn time"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&Proposal{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&Proposal{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019101100",
+		Migrate: func(tx *gorm.DB) error {
+			type ProposalComment struct {
+				ID         uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				ProposalID uint   `gorm:"type:bigint;index;not null;comment:proposal id"`
+				Content    string `gorm:"type:text;not null;comment:comment content"`
+				CreatedBy  uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&ProposalComment{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&ProposalComment{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019101200",
+		Migrate: func(tx *gorm.DB) error {
+			type DefinitionResponseHistory struct {
+				ID          uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				ResponseID  uint   `gorm:"type:bigint;index;not null;comment:response id"`
+				Name        string `gorm:"type:varchar(255);not null;comment:response name"`
+				Description string `gorm:"type:varchar(255);comment:response description"`
+				Header      string `gorm:"type:mediumtext;comment:response header"`
+				Content     string `gorm:"type:mediumtext;comment:response content"`
+				CreatedBy   uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&DefinitionResponseHistory{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&DefinitionResponseHistory{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
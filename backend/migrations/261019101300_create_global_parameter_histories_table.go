@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019101300",
+		Migrate: func(tx *gorm.DB) error {
+			type GlobalParameterHistory struct {
+				ID          uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				ParameterID uint   `gorm:"type:bigint;index;not null;comment:global parameter id"`
+				Name        string `gorm:"type:varchar(255);not null;comment:param name"`
+				Required    bool   `gorm:"type:tinyint;not null;comment:is required"`
+				Schema      string `gorm:"type:mediumtext;comment:param schema"`
+				CreatedBy   uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&GlobalParameterHistory{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&GlobalParameterHistory{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019101400",
+		Migrate: func(tx *gorm.DB) error {
+			type Iteration struct {
+				StartDate *time.Time `gorm:"type:datetime;comment:start date"`
+				DueDate   *time.Time `gorm:"type:datetime;comment:due date"`
+				State     string     `gorm:"type:varchar(32);not null;default:planning;comment:state:planning,active,completed"`
+			}
+			if !tx.Migrator().HasTable(&Iteration{}) {
+				return nil
+			}
+			for _, c := range [][2]string{{"start_date", "StartDate"}, {"due_date", "DueDate"}, {"state", "State"}} {
+				if !tx.Migrator().HasColumn(&Iteration{}, c[0]) {
+					if err := tx.Migrator().AddColumn(&Iteration{}, c[1]); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+	MigrationHelper.Register(m)
+}
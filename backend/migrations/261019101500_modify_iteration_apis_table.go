@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019101500",
+		Migrate: func(tx *gorm.DB) error {
+			type IterationApi struct {
+				Status     string     `gorm:"type:varchar(32);not null;default:todo;comment:status:todo,designing,developing,testing,done"`
+				AssigneeID uint       `gorm:"type:bigint;not null;default:0;comment:assignee member id"`
+				Note       string     `gorm:"type:varchar(255);comment:note"`
+				DoneAt     *time.Time `gorm:"type:datetime;comment:time the status changed to done"`
+			}
+			if !tx.Migrator().HasTable(&IterationApi{}) {
+				return nil
+			}
+			for _, c := range [][2]string{{"status", "Status"}, {"assignee_id", "AssigneeID"}, {"note", "Note"}, {"done_at", "DoneAt"}} {
+				if !tx.Migrator().HasColumn(&IterationApi{}, c[0]) {
+					if err := tx.Migrator().AddColumn(&IterationApi{}, c[1]); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+	MigrationHelper.Register(m)
+}
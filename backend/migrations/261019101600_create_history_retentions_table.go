@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019101600",
+		Migrate: func(tx *gorm.DB) error {
+			type HistoryRetention struct {
+				ID             uint       `gorm:"type:bigint;primaryKey;autoIncrement"`
+				ProjectID      string     `gorm:"type:varchar(24);uniqueIndex;not null;comment:project id"`
+				KeepCount      int        `gorm:"type:int;not null;default:0;comment:keep the latest n histories of each doc, 0 means unlimited"`
+				KeepDays       int        `gorm:"type:int;not null;default:0;comment:keep histories for n days, 0 means unlimited"`
+				DailyAfterDays int        `gorm:"type:int;not null;default:0;comment:keep only daily snapshots for histories older than n days, 0 means disabled"`
+				DeltaStorage   bool       `gorm:"type:tinyint;not null;default:0;comment:store histories as deltas instead of full copies"`
+				CompactedAt    *time.Time `gorm:"type:datetime;comment:last compaction time"`
+				UpdatedBy      uint       `gorm:"type:bigint;not null;default:0;comment:updated by member id"`
+				model.TimeModel
+			}
+
+			if tx.Migrator().HasTable(&HistoryRetention{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&HistoryRetention{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019101800",
+		Migrate: func(tx *gorm.DB) error {
+			type DefinitionSchemaHistory struct {
+				BaseID uint `gorm:"type:bigint;not null;default:0;comment:delta base history id, 0 means full content"`
+			}
+			if !tx.Migrator().HasTable(&DefinitionSchemaHistory{}) {
+				return nil
+			}
+			for _, c := range [][2]string{{"base_id", "BaseID"}} {
+				if !tx.Migrator().HasColumn(&DefinitionSchemaHistory{}, c[0]) {
+					if err := tx.Migrator().AddColumn(&DefinitionSchemaHistory{}, c[1]); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019101900",
+		Migrate: func(tx *gorm.DB) error {
+			type Project struct {
+				DeletedBy uint `gorm:"type:bigint;default:null;comment:deleted by member id"`
+			}
+			if !tx.Migrator().HasTable(&Project{}) {
+				return nil
+			}
+			for _, c := range [][2]string{{"deleted_by", "DeletedBy"}} {
+				if !tx.Migrator().HasColumn(&Project{}, c[0]) {
+					if err := tx.Migrator().AddColumn(&Project{}, c[1]); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+	MigrationHelper.Register(m)
+}
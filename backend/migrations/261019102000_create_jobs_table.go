@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019102000",
+		Migrate: func(tx *gorm.DB) error {
+			type Job struct {
+				ID          uint       `gorm:"type:bigint;primaryKey;autoIncrement"`
+				Type        string     `gorm:"type:varchar(64);index;not null;comment:job type"`
+				ProjectID   string     `gorm:"type:varchar(24);index;not null;comment:project id"`
+				TargetID    uint       `gorm:"type:bigint;not null;default:0;comment:target id, e.g. collection id"`
+				MemberID    uint       `gorm:"type:bigint;not null;default:0;comment:created by team member id"`
+				Payload     string     `gorm:"type:mediumtext;comment:job arguments"`
+				Result      string     `gorm:"type:mediumtext;comment:job result"`
+				Status      string     `gorm:"type:varchar(32);index:idx_status_run;not null;comment:pending,running,succeeded,failed,canceled"`
+				Progress    int        `gorm:"type:int(11);not null;default:0;comment:progress percentage"`
+				Attempts    int        `gorm:"type:int(11);not null;default:0;comment:attempt times"`
+				MaxAttempts int        `gorm:"type:int(11);not null;default:1;comment:max attempt times"`
+				Error       string     `gorm:"type:varchar(1024);comment:last error"`
+				RunAt       time.Time  `gorm:"type:datetime;index:idx_status_run;not null;comment:next run time, or lease expiry while running"`
+				StartedAt   *time.Time `gorm:"type:datetime;comment:last started time"`
+				FinishedAt  *time.Time `gorm:"type:datetime;comment:finished time"`
+				CreatedAt   time.Time
+				UpdatedAt   time.Time
+			}
+
+			if tx.Migrator().HasTable(&Job{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&Job{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019102100",
+		Migrate: func(tx *gorm.DB) error {
+			type Embedding struct {
+				ID         uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				ProjectID  string `gorm:"type:varchar(24);index;not null;comment:project id"`
+				TargetType string `gorm:"type:varchar(32);uniqueIndex:ukey;not null;comment:collection,schema"`
+				TargetID   uint   `gorm:"type:bigint;uniqueIndex:ukey;not null;comment:collection or schema id"`
+				Model      string `gorm:"type:varchar(255);not null;comment:embedding model"`
+				Hash       string `gorm:"type:varchar(64);not null;comment:sha256 of embedded text"`
+				Vector     []byte `gorm:"type:mediumblob;comment:normalized float32 vector, little endian"`
+				CreatedAt  time.Time
+				UpdatedAt  time.Time
+			}
+
+			if tx.Migrator().HasTable(&Embedding{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&Embedding{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
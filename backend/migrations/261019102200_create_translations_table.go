@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019102200",
+		Migrate: func(tx *gorm.DB) error {
+			type Translation struct {
+				ID          uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				ProjectID   string `gorm:"type:varchar(24);index;not null;comment:project id"`
+				TargetType  string `gorm:"type:varchar(32);uniqueIndex:ukey;not null;comment:collection,schema"`
+				TargetID    uint   `gorm:"type:bigint;uniqueIndex:ukey;not null;comment:collection or schema id"`
+				Locale      string `gorm:"type:varchar(16);uniqueIndex:ukey;not null;comment:zh-CN,en-US"`
+				Title       string `gorm:"type:varchar(255);comment:translated collection title"`
+				Description string `gorm:"type:text;comment:translated schema description"`
+				Content     string `gorm:"type:mediumtext;comment:translated collection content or schema"`
+				SourceHash  string `gorm:"type:varchar(64);not null;comment:sha256 of the translated source"`
+				CreatedAt   time.Time
+				UpdatedAt   time.Time
+			}
+
+			if tx.Migrator().HasTable(&Translation{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&Translation{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019102300",
+		Migrate: func(tx *gorm.DB) error {
+			type TestCase struct {
+				Spec string `gorm:"type:mediumtext;comment:executable test case"`
+			}
+			if !tx.Migrator().HasTable(&TestCase{}) {
+				return nil
+			}
+			if !tx.Migrator().HasColumn(&TestCase{}, "spec") {
+				return tx.Migrator().AddColumn(&TestCase{}, "Spec")
+			}
+			return nil
+		},
+	}
+	MigrationHelper.Register(m)
+}
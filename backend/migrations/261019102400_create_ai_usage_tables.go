@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019102400",
+		Migrate: func(tx *gorm.DB) error {
+			type AIUsage struct {
+				ID               uint      `gorm:"type:bigint;primaryKey;autoIncrement"`
+				TeamID           string    `gorm:"type:varchar(24);index:idx_team_created;comment:team id"`
+				ProjectID        string    `gorm:"type:varchar(24);index;comment:project id"`
+				UserID           uint      `gorm:"type:bigint;index;comment:user id, 0 for guests and system"`
+				Feature          string    `gorm:"type:varchar(64);not null;comment:feature that called the model"`
+				Model            string    `gorm:"type:varchar(255);comment:llm driver and model name"`
+				PromptTokens     int       `gorm:"type:int;not null;default:0"`
+				CompletionTokens int       `gorm:"type:int;not null;default:0"`
+				Estimated        bool      `gorm:"type:tinyint;not null;default:0;comment:token counts are estimated from text length"`
+				CreatedAt        time.Time `gorm:"index:idx_team_created"`
+			}
+			type AIQuota struct {
+				ID            uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				TeamID        string `gorm:"type:varchar(24);uniqueIndex;not null;comment:team id, empty for the default quota"`
+				DailyTokens   int64  `gorm:"type:bigint;not null;default:0;comment:daily token limit"`
+				MonthlyTokens int64  `gorm:"type:bigint;not null;default:0;comment:monthly token limit"`
+				CreatedAt     time.Time
+				UpdatedAt     time.Time
+			}
+
+			for _, t := range []interface{}{&AIUsage{}, &AIQuota{}} {
+				if tx.Migrator().HasTable(t) {
+					continue
+				}
+				if err := tx.Migrator().CreateTable(t); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
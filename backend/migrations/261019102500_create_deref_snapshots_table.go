@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func init() {
+	m := &gormigrate.Migration{
+		ID: "261019102500",
+		Migrate: func(tx *gorm.DB) error {
+			type DerefSnapshot struct {
+				ID         uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+				RefType    string `gorm:"type:varchar(32);index:idx_ref;not null;comment:schema,response"`
+				RefID      uint   `gorm:"type:bigint;index:idx_ref;not null;comment:deleted definition schema or response id"`
+				TargetType string `gorm:"type:varchar(32);not null;comment:collection,schema,response"`
+				TargetID   uint   `gorm:"type:bigint;not null;comment:dereferenced collection, schema or response id"`
+				Before     string `gorm:"type:mediumtext;comment:content before deref"`
+				After      string `gorm:"type:mediumtext;comment:content after deref"`
+				CreatedAt  time.Time
+			}
+
+			if tx.Migrator().HasTable(&DerefSnapshot{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&DerefSnapshot{})
+		},
+	}
+
+	MigrationHelper.Register(m)
+}
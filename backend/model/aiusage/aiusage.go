@@ -0,0 +1,103 @@
+package aiusage
+
+import (
+	"context"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+)
+
+const (
+	GroupByTeam    = "team"
+	GroupByProject = "project"
+	GroupByUser    = "user"
+	GroupByFeature = "feature"
+	GroupByDay     = "day"
+)
+
+// AIUsage 一次 AI 调用消耗的 token，项目删除后仍保留用于统计
+type AIUsage struct {
+	ID               uint      `gorm:"type:bigint;primaryKey;autoIncrement"`
+	TeamID           string    `gorm:"type:varchar(24);index:idx_team_created;comment:team id"`
+	ProjectID        string    `gorm:"type:varchar(24);index;comment:project id"`
+	UserID           uint      `gorm:"type:bigint;index;comment:user id, 0 for guests and system"`
+	Feature          string    `gorm:"type:varchar(64);not null;comment:feature that called the model"`
+	Model            string    `gorm:"type:varchar(255);comment:llm driver and model name"`
+	PromptTokens     int       `gorm:"type:int;not null;default:0"`
+	CompletionTokens int       `gorm:"type:int;not null;default:0"`
+	Estimated        bool      `gorm:"type:tinyint;not null;default:0;comment:token counts are estimated from text length"`
+	CreatedAt        time.Time `gorm:"index:idx_team_created"`
+}
+
+// Summary 按维度汇总的用量
+type Summary struct {
+	Key              string
+	Calls            int64
+	PromptTokens     int64
+	CompletionTokens int64
+}
+
+// ReportOption 用量统计条件，零值表示不筛选
+type ReportOption struct {
+	TeamID    string
+	ProjectID string
+	UserID    uint
+	StartTime time.Time
+	EndTime   time.Time
+	GroupBy   string
+}
+
+func (u *AIUsage) Create(ctx context.Context) error {
+	return model.DB(ctx).Create(u).Error
+}
+
+// SumTeamTokens 统计团队自某一时间起消耗的 token 总数
+func SumTeamTokens(ctx context.Context, teamID string, since time.Time) (int64, error) {
+	var total int64
+	err := model.DB(ctx).Model(&AIUsage{}).
+		Select("COALESCE(SUM(prompt_tokens + completion_tokens), 0)").
+		Where("team_id = ? AND created_at >= ?", teamID, since).
+		Scan(&total).Error
+	return total, err
+}
+
+// Report 按维度汇总用量，按 token 总数倒序
+func Report(ctx context.Context, opt *ReportOption) ([]*Summary, error) {
+	var key string
+	switch opt.GroupBy {
+	case GroupByProject:
+		key = "project_id"
+	case GroupByUser:
+		key = "CAST(user_id AS CHAR)"
+	case GroupByFeature:
+		key = "feature"
+	case GroupByDay:
+		key = "DATE_FORMAT(created_at, '%Y-%m-%d')"
+	default:
+		key = "team_id"
+	}
+
+	tx := model.DB(ctx).Model(&AIUsage{})
+	if opt.TeamID != "" {
+		tx = tx.Where("team_id = ?", opt.TeamID)
+	}
+	if opt.ProjectID != "" {
+		tx = tx.Where("project_id = ?", opt.ProjectID)
+	}
+	if opt.UserID != 0 {
+		tx = tx.Where("user_id = ?", opt.UserID)
+	}
+	if !opt.StartTime.IsZero() {
+		tx = tx.Where("created_at >= ?", opt.StartTime)
+	}
+	if !opt.EndTime.IsZero() {
+		tx = tx.Where("created_at <= ?", opt.EndTime)
+	}
+
+	var list []*Summary
+	err := tx.Select(key + " AS `key`, COUNT(*) AS calls, SUM(prompt_tokens) AS prompt_tokens, SUM(completion_tokens) AS completion_tokens").
+		Group("`key`").
+		Order("SUM(prompt_tokens + completion_tokens) DESC").
+		Scan(&list).Error
+	return list, err
+}
@@ -0,0 +1,68 @@
+package aiusage
+
+import (
+	"context"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+)
+
+// AIQuota 团队的 token 配额，TeamID 为空的记录是所有团队的默认配额，限额为 0 表示不限制
+type AIQuota struct {
+	ID            uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	TeamID        string `gorm:"type:varchar(24);uniqueIndex;not null;comment:team id, empty for the default quota"`
+	DailyTokens   int64  `gorm:"type:bigint;not null;default:0;comment:daily token limit"`
+	MonthlyTokens int64  `gorm:"type:bigint;not null;default:0;comment:monthly token limit"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (q *AIQuota) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx).Take(q, "team_id = ?", q.TeamID)
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+// Save 保存配额，已存在时覆盖
+func (q *AIQuota) Save(ctx context.Context) error {
+	old := &AIQuota{TeamID: q.TeamID}
+	exist, err := old.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return model.DB(ctx).Create(q).Error
+	}
+
+	q.ID = old.ID
+	q.CreatedAt = old.CreatedAt
+	return model.DB(ctx).Model(old).Updates(map[string]interface{}{
+		"daily_tokens":   q.DailyTokens,
+		"monthly_tokens": q.MonthlyTokens,
+	}).Error
+}
+
+func (q *AIQuota) Delete(ctx context.Context) error {
+	return model.DB(ctx).Delete(q).Error
+}
+
+// GetTeamQuota 获取团队生效的配额，没有单独设置时使用默认配额，都没有时返回 nil
+func GetTeamQuota(ctx context.Context, teamID string) (*AIQuota, error) {
+	var list []*AIQuota
+	if err := model.DB(ctx).Where("team_id IN ?", []string{teamID, ""}).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	var res *AIQuota
+	for _, q := range list {
+		if q.TeamID == teamID {
+			return q, nil
+		}
+		res = q
+	}
+	return res, nil
+}
+
+func GetQuotas(ctx context.Context) ([]*AIQuota, error) {
+	var list []*AIQuota
+	return list, model.DB(ctx).Order("team_id asc").Find(&list).Error
+}
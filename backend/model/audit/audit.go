@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionMove    = "move"
+	ActionCopy    = "copy"
+	ActionRestore = "restore"
+	ActionReset   = "reset"
+	ActionMerge   = "merge"
+)
+
+const (
+	TargetCollection         = "collection"
+	TargetDefinitionSchema   = "definition_schema"
+	TargetDefinitionResponse = "definition_response"
+	TargetGlobalParameter    = "global_parameter"
+	TargetProjectMember      = "project_member"
+	TargetTeamMember         = "team_member"
+	TargetProjectShare       = "project_share"
+	TargetCollectionShare    = "collection_share"
+	TargetSysconfig          = "sysconfig"
+	TargetIteration          = "iteration"
+	TargetRelease            = "release"
+)
+
+// AuditLog 审计日志，只增不改，操作人信息冗余保存以免用户删除后无从追溯
+type AuditLog struct {
+	ID         uint      `gorm:"type:bigint;primaryKey;autoIncrement"`
+	TeamID     string    `gorm:"type:varchar(24);index;not null;default:'';comment:team id, empty for system config"`
+	ProjectID  string    `gorm:"type:varchar(24);index;not null;default:'';comment:project id"`
+	UserID     uint      `gorm:"type:bigint;index;not null;comment:operator user id"`
+	UserName   string    `gorm:"type:varchar(255);comment:operator name"`
+	UserEmail  string    `gorm:"type:varchar(255);comment:operator email"`
+	Action     string    `gorm:"type:varchar(32);index;not null;comment:action"`
+	TargetType string    `gorm:"type:varchar(64);index;not null;comment:target type"`
+	TargetID   string    `gorm:"type:varchar(255);comment:target id"`
+	TargetName string    `gorm:"type:varchar(255);comment:target name"`
+	Changes    string    `gorm:"type:mediumtext;comment:before and after diff summary"`
+	IP         string    `gorm:"type:varchar(64);comment:client ip"`
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+// Change 字段变更摘要
+type Change struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// ListOption 审计日志筛选条件，零值表示不筛选
+type ListOption struct {
+	TeamID     string
+	ProjectID  string
+	UserID     uint
+	Action     string
+	TargetType string
+	TargetID   string
+	StartTime  time.Time
+	EndTime    time.Time
+}
+
+func (a *AuditLog) Create(ctx context.Context) error {
+	return model.DB(ctx).Create(a).Error
+}
+
+// GetChanges 获取字段变更摘要
+func (a *AuditLog) GetChanges() []Change {
+	list := make([]Change, 0)
+	if a.Changes == "" {
+		return list
+	}
+	if err := json.Unmarshal([]byte(a.Changes), &list); err != nil {
+		return make([]Change, 0)
+	}
+	return list
+}
+
+func (o *ListOption) query(ctx context.Context) *gorm.DB {
+	tx := model.DB(ctx).Model(&AuditLog{}).Where("team_id = ?", o.TeamID)
+	if o.ProjectID != "" {
+		tx = tx.Where("project_id = ?", o.ProjectID)
+	}
+	if o.UserID != 0 {
+		tx = tx.Where("user_id = ?", o.UserID)
+	}
+	if o.Action != "" {
+		tx = tx.Where("action = ?", o.Action)
+	}
+	if o.TargetType != "" {
+		tx = tx.Where("target_type = ?", o.TargetType)
+	}
+	if o.TargetID != "" {
+		tx = tx.Where("target_id = ?", o.TargetID)
+	}
+	if !o.StartTime.IsZero() {
+		tx = tx.Where("created_at >= ?", o.StartTime)
+	}
+	if !o.EndTime.IsZero() {
+		tx = tx.Where("created_at <= ?", o.EndTime)
+	}
+	return tx
+}
+
+// GetAuditLogs 按时间倒序分页获取审计日志
+func GetAuditLogs(ctx context.Context, opt *ListOption, page, pageSize int) ([]*AuditLog, error) {
+	tx := opt.query(ctx).Order("id desc")
+	if page > 0 && pageSize > 0 {
+		tx = tx.Offset((page - 1) * pageSize).Limit(pageSize)
+	}
+	var list []*AuditLog
+	return list, tx.Find(&list).Error
+}
+
+func GetAuditLogsCount(ctx context.Context, opt *ListOption) (int64, error) {
+	var count int64
+	return count, opt.query(ctx).Count(&count).Error
+}
+
+const exportBatchSize = 500
+
+// EachAuditLogs 按时间倒序分批遍历符合条件的审计日志，用于导出
+func EachAuditLogs(ctx context.Context, opt *ListOption, fn func([]*AuditLog) error) error {
+	return eachBatch(func(lastID uint) ([]*AuditLog, error) {
+		tx := opt.query(ctx)
+		if lastID > 0 {
+			tx = tx.Where("id < ?", lastID)
+		}
+		var list []*AuditLog
+		return list, tx.Order("id desc").Limit(exportBatchSize).Find(&list).Error
+	}, fn)
+}
+
+// eachBatch 以上一批最小的 ID 作为游标获取下一批，直到不足一批
+func eachBatch(fetch func(lastID uint) ([]*AuditLog, error), fn func([]*AuditLog) error) error {
+	var lastID uint
+	for {
+		list, err := fetch(lastID)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		if err := fn(list); err != nil {
+			return err
+		}
+		if len(list) < exportBatchSize {
+			return nil
+		}
+		lastID = list[len(list)-1].ID
+	}
+}
@@ -0,0 +1,42 @@
+package audit
+
+import "testing"
+
+func TestEachBatch(t *testing.T) {
+	const total = exportBatchSize*2 + 7
+	fetch := func(lastID uint) ([]*AuditLog, error) {
+		var list []*AuditLog
+		for id := uint(total); id > 0 && len(list) < exportBatchSize; id-- {
+			if lastID == 0 || id < lastID {
+				list = append(list, &AuditLog{ID: id})
+			}
+		}
+		return list, nil
+	}
+
+	var (
+		seen    = make(map[uint]bool)
+		batches int
+		prev    = uint(total + 1)
+	)
+	err := eachBatch(fetch, func(list []*AuditLog) error {
+		batches++
+		if batches > 10 {
+			t.Fatal("pagination does not advance")
+		}
+		for _, l := range list {
+			if l.ID >= prev || seen[l.ID] {
+				t.Fatalf("id %d is out of order or repeated", l.ID)
+			}
+			seen[l.ID] = true
+			prev = l.ID
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batches != 3 || len(seen) != total {
+		t.Fatalf("batches = %d, rows = %d, want 3 and %d", batches, len(seen), total)
+	}
+}
@@ -105,7 +105,11 @@ func (c *Collection) Update(ctx context.Context, title, content string, memberID
 		}
 		h.Create(ctx, memberID)
 	}
+	return c.update(ctx, title, content, memberID)
+}
 
+// update 只写入修改，不记录历史
+func (c *Collection) update(ctx context.Context, title, content string, memberID uint) error {
 	// 获取文档的path
 	method, path := "", ""
 	if content != "" {
@@ -8,6 +8,9 @@ import (
 
 	"github.com/apicat/apicat/v2/backend/model"
 	"github.com/apicat/apicat/v2/backend/model/team"
+	"github.com/apicat/apicat/v2/backend/utils/delta"
+
+	"gorm.io/gorm"
 )
 
 type CollectionHistory struct {
@@ -15,6 +18,7 @@ type CollectionHistory struct {
 	CollectionID uint   `gorm:"type:bigint;index;not null;comment:collection id"`
 	Title        string `gorm:"type:varchar(255);not null;comment:collection title"`
 	Content      string `gorm:"type:mediumtext;comment:doc content"`
+	BaseID       uint   `gorm:"type:bigint;not null;default:0;comment:delta base history id, 0 means full content"`
 	CreatedBy    uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
 	model.TimeModel
 }
@@ -26,7 +30,10 @@ func (ch *CollectionHistory) Get(ctx context.Context) (bool, error) {
 	} else {
 		return false, errors.New("query condition error")
 	}
-	return tx.Error == nil, model.NotRecord(tx)
+	if err := model.NotRecord(tx); err != nil || tx.Error != nil {
+		return false, err
+	}
+	return true, resolveCollectionHistories(ctx, []*CollectionHistory{ch})
 }
 
 func (ch *CollectionHistory) Create(ctx context.Context, memberID uint) error {
@@ -53,5 +60,134 @@ func (ch *CollectionHistory) Restore(ctx context.Context, c *Collection, tm *tea
 		slog.ErrorContext(ctx, "CollectionHistory.Restore.Create", "err", err)
 	}
 
-	return c.Update(ctx, ch.Title, ch.Content, tm.ID)
+	if err := c.update(ctx, ch.Title, ch.Content, tm.ID); err != nil {
+		return err
+	}
+
+	// 恢复后的内容单独记录，不与恢复前的记录合并
+	restored := &CollectionHistory{
+		CollectionID: c.ID,
+		Title:        ch.Title,
+		Content:      ch.Content,
+		CreatedBy:    tm.ID,
+	}
+	return model.DB(ctx).Create(restored).Error
+}
+
+// saveStorage 压缩时改写记录的存储方式，baseID为0时保存完整内容，否则保存相对baseID的差量
+func (ch *CollectionHistory) saveStorage(tx *gorm.DB, baseID uint, content string) error {
+	return tx.Model(ch).UpdateColumns(map[string]interface{}{
+		"base_id": baseID,
+		"content": content,
+	}).Error
+}
+
+// resolveCollectionHistories 还原差量存储的历史记录内容，基准记录总是完整保存的
+func resolveCollectionHistories(ctx context.Context, list []*CollectionHistory) error {
+	var baseIDs []uint
+	for _, v := range list {
+		if v.BaseID != 0 {
+			baseIDs = append(baseIDs, v.BaseID)
+		}
+	}
+	if len(baseIDs) == 0 {
+		return nil
+	}
+
+	var bases []*CollectionHistory
+	if err := model.DB(ctx).Unscoped().Where("id IN ?", baseIDs).Find(&bases).Error; err != nil {
+		return err
+	}
+	baseMap := make(map[uint]*CollectionHistory, len(bases))
+	for _, v := range bases {
+		baseMap[v.ID] = v
+	}
+
+	for _, v := range list {
+		if v.BaseID == 0 {
+			continue
+		}
+		base, ok := baseMap[v.BaseID]
+		if !ok {
+			return errors.New("history delta base not found")
+		}
+		if base.BaseID != 0 {
+			return errors.New("history delta base is not stored in full")
+		}
+		content, err := delta.Patch(base.Content, v.Content)
+		if err != nil {
+			return err
+		}
+		v.Content = content
+		v.BaseID = 0
+	}
+	return nil
+}
+
+// GetHistoryCollectionIDs 获取项目中有历史记录的接口，包含已删除的接口
+func GetHistoryCollectionIDs(ctx context.Context, projectID string) ([]uint, error) {
+	var ids []uint
+	sub := model.DB(ctx).Unscoped().Model(&Collection{}).Select("id").Where("project_id = ?", projectID)
+	err := model.DB(ctx).Model(&CollectionHistory{}).Distinct("collection_id").
+		Where("collection_id IN (?)", sub).Pluck("collection_id", &ids).Error
+	return ids, err
+}
+
+// GetCollectionHistoryTimes 按时间升序获取接口所有历史记录的ID和创建时间，用于计算保留策略
+func GetCollectionHistoryTimes(ctx context.Context, collectionID uint) ([]*CollectionHistory, error) {
+	var list []*CollectionHistory
+	tx := model.DB(ctx).Select("id", "collection_id", "created_at").Where("collection_id = ?", collectionID)
+	return list, tx.Order("id asc").Find(&list).Error
+}
+
+// CompactCollectionHistories 删除removeIDs中的历史记录，并按是否使用差量存储改写保留的记录
+// 改写和删除在同一事务中完成，避免读到基准尚未改写完成的差量记录
+func CompactCollectionHistories(ctx context.Context, collectionID uint, removeIDs []uint, useDelta bool) error {
+	var list []*CollectionHistory
+	if err := model.DB(ctx).Where("collection_id = ?", collectionID).Order("id asc").Find(&list).Error; err != nil {
+		return err
+	}
+	storedBase := make(map[uint]uint, len(list))
+	for _, v := range list {
+		storedBase[v.ID] = v.BaseID
+	}
+	if err := resolveCollectionHistories(ctx, list); err != nil {
+		return err
+	}
+
+	removed := make(map[uint]bool, len(removeIDs))
+	for _, id := range removeIDs {
+		removed[id] = true
+	}
+	kept := make([]*CollectionHistory, 0, len(list))
+	for _, v := range list {
+		if !removed[v.ID] {
+			kept = append(kept, v)
+		}
+	}
+
+	return model.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, v := range kept {
+			var baseID uint
+			content := v.Content
+			if b := delta.BaseIndex(len(kept), i); useDelta && b != -1 {
+				d := delta.Diff(kept[b].Content, v.Content)
+				if delta.Worthwhile(d, v.Content) {
+					baseID, content = kept[b].ID, d
+				}
+			}
+			// 完整保存且原本就是完整保存的记录无需改写
+			if baseID == 0 && storedBase[v.ID] == 0 {
+				continue
+			}
+			if err := v.saveStorage(tx, baseID, content); err != nil {
+				return err
+			}
+		}
+
+		if len(removeIDs) == 0 {
+			return nil
+		}
+		return tx.Unscoped().Where("collection_id = ? AND id IN ?", collectionID, removeIDs).Delete(&CollectionHistory{}).Error
+	})
 }
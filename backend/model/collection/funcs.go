@@ -114,17 +114,84 @@ func GetCollectionHistories(ctx context.Context, c *Collection, start, end time.
 	if !start.IsZero() && !end.IsZero() {
 		tx = tx.Where("created_at BETWEEN ? AND ?", start, end)
 	}
-	err := tx.Where("collection_id = ?", c.ID).Order("created_at desc").Find(&list).Error
-	return list, err
+	if err := tx.Where("collection_id = ?", c.ID).Order("created_at desc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, resolveCollectionHistories(ctx, list)
+}
+
+// GetProjectCollectionHistories 获取项目中所有接口的历史记录，用于项目动态
+func GetProjectCollectionHistories(ctx context.Context, projectID string, start, end time.Time, limit int) ([]*CollectionHistory, error) {
+	var list []*CollectionHistory
+	sub := model.DB(ctx).Model(&Collection{}).Select("id").Where("project_id = ?", projectID)
+	tx := model.DB(ctx).Where("collection_id IN (?)", sub)
+	if !start.IsZero() && !end.IsZero() {
+		tx = tx.Where("created_at BETWEEN ? AND ?", start, end)
+	}
+	if err := tx.Order("created_at desc").Limit(limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, resolveCollectionHistories(ctx, list)
 }
 
-// GetDeletedCollections 获取删除了的 collection
-func GetDeletedCollections(ctx context.Context, projectID string) ([]*Collection, error) {
-	// 计算三十天前的时间
-	thirtyDaysAgo := time.Now().AddDate(0, 0, -30)
+// GetLatestCollectionHistory 获取文档最新的一条历史记录，没有记录时返回nil
+func GetLatestCollectionHistory(ctx context.Context, collectionID uint) (*CollectionHistory, error) {
+	var ch CollectionHistory
+	tx := model.DB(ctx).Where("collection_id = ?", collectionID).Order("id desc").Take(&ch)
+	if err := model.NotRecord(tx); err != nil {
+		return nil, err
+	}
+	if tx.Error != nil {
+		return nil, nil
+	}
+	return &ch, nil
+}
+
+// GetCollectionsAt 获取某一时间点项目中存在的接口，包含之后被删除的
+func GetCollectionsAt(ctx context.Context, projectID string, t time.Time) ([]*Collection, error) {
 	var list []*Collection
-	tx := model.DB(ctx).Unscoped()
-	return list, tx.Where("project_id = ? AND deleted_at >= ? AND type <> ?", projectID, thirtyDaysAgo, CategoryType).Order("deleted_at desc").Find(&list).Error
+	tx := model.DB(ctx).Unscoped().Where("project_id = ? AND type <> ? AND created_at <= ?", projectID, CategoryType, t)
+	tx = tx.Where("deleted_at IS NULL OR deleted_at > ?", t)
+	return list, tx.Order("display_order asc").Find(&list).Error
+}
+
+// GetCollectionHistoriesBefore 获取每个接口在某一时间点之前的最后一条历史记录
+func GetCollectionHistoriesBefore(ctx context.Context, collectionIDs []uint, t time.Time) (map[uint]*CollectionHistory, error) {
+	sub := model.DB(ctx).Model(&CollectionHistory{}).Select("MAX(id)").
+		Where("collection_id IN ? AND updated_at <= ?", collectionIDs, t).Group("collection_id")
+	return collectionHistoryMap(ctx, sub)
+}
+
+// GetFirstCollectionHistories 获取每个接口的第一条历史记录
+func GetFirstCollectionHistories(ctx context.Context, collectionIDs []uint) (map[uint]*CollectionHistory, error) {
+	sub := model.DB(ctx).Model(&CollectionHistory{}).Select("MIN(id)").
+		Where("collection_id IN ?", collectionIDs).Group("collection_id")
+	return collectionHistoryMap(ctx, sub)
+}
+
+func collectionHistoryMap(ctx context.Context, sub *gorm.DB) (map[uint]*CollectionHistory, error) {
+	var list []*CollectionHistory
+	if err := model.DB(ctx).Where("id IN (?)", sub).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	if err := resolveCollectionHistories(ctx, list); err != nil {
+		return nil, err
+	}
+	res := make(map[uint]*CollectionHistory, len(list))
+	for _, v := range list {
+		res[v.CollectionID] = v
+	}
+	return res, nil
+}
+
+// GetDeletedCollections 获取删除了的 collection，since不为零值时只返回该时间之后删除的
+func GetDeletedCollections(ctx context.Context, projectID string, since time.Time) ([]*Collection, error) {
+	var list []*Collection
+	tx := model.DB(ctx).Unscoped().Where("project_id = ? AND deleted_at IS NOT NULL AND type <> ?", projectID, CategoryType)
+	if !since.IsZero() {
+		tx = tx.Where("deleted_at >= ?", since)
+	}
+	return list, tx.Order("deleted_at desc").Find(&list).Error
 }
 
 // GetDeletedCollectionsByIDs 通过 ID 获取删除了的 collection
@@ -271,3 +338,13 @@ func UserInfo(ctx context.Context, memberID uint, unscoped bool) (*user.User, er
 		return tm.UserInfo(ctx, unscoped)
 	}
 }
+
+// GetCollectionsUpdatedAfter 按修改时间顺序获取(at, id)之后修改的接口和文档，用于增量同步
+func GetCollectionsUpdatedAfter(ctx context.Context, at time.Time, id uint, limit int) ([]*Collection, error) {
+	var list []*Collection
+	return list, model.DB(ctx).
+		Where("type != ? AND (updated_at > ? OR (updated_at = ? AND id > ?))", CategoryType, at, at, id).
+		Order("updated_at asc, id asc").
+		Limit(limit).
+		Find(&list).Error
+}
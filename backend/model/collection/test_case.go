@@ -14,6 +14,7 @@ type TestCase struct {
 	CollectionID uint   `gorm:"type:bigint;index:idx_pid_cid;not null;comment:collection id"`
 	Title        string `gorm:"type:varchar(255);not null;comment:test case title"`
 	Content      string `gorm:"type:mediumtext;comment:test case content"`
+	Spec         string `gorm:"type:mediumtext;comment:executable test case"`
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
@@ -35,13 +36,19 @@ func (t *TestCase) Get(ctx context.Context) (bool, error) {
 	return tx.Error == nil, err
 }
 
-func (t *TestCase) Update(ctx context.Context, title, content string) error {
+func (t *TestCase) Update(ctx context.Context, title, content, spec string) error {
 	return model.DB(ctx).Model(t).Updates(map[string]interface{}{
 		"title":   title,
 		"content": content,
+		"spec":    spec,
 	}).Error
 }
 
+// UpdateSpec 更新可执行的测试步骤
+func (t *TestCase) UpdateSpec(ctx context.Context, spec string) error {
+	return model.DB(ctx).Model(t).Update("spec", spec).Error
+}
+
 func (t *TestCase) Delete(ctx context.Context) error {
 	return model.DB(ctx).Delete(t).Error
 }
@@ -14,10 +14,22 @@ import (
 
 var defaultDB *gorm.DB
 
+type txKey struct{}
+
 func DB(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
 	return defaultDB.WithContext(ctx)
 }
 
+// Transaction 在同一事务中执行fn，fn中通过DB(ctx)进行的读写都使用该事务
+func Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return DB(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
 func DBWithoutCtx() *gorm.DB {
 	return defaultDB
 }
@@ -175,7 +175,85 @@ func GetDefinitionSchemaHistories(ctx context.Context, ds *DefinitionSchema, sta
 	if !start.IsZero() && !end.IsZero() {
 		tx = tx.Where("created_at BETWEEN ? AND ?", start, end)
 	}
-	return list, tx.Where("schema_id = ?", ds.ID).Order("created_at desc").Find(&list).Error
+	if err := tx.Where("schema_id = ?", ds.ID).Order("created_at desc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, resolveDefinitionSchemaHistories(ctx, list)
+}
+
+// GetProjectDefinitionSchemaHistories 获取项目中所有公共模型的历史记录，用于项目动态
+func GetProjectDefinitionSchemaHistories(ctx context.Context, projectID string, start, end time.Time, limit int) ([]*DefinitionSchemaHistory, error) {
+	var list []*DefinitionSchemaHistory
+	sub := model.DB(ctx).Model(&DefinitionSchema{}).Select("id").Where("project_id = ?", projectID)
+	tx := model.DB(ctx).Where("schema_id IN (?)", sub)
+	if !start.IsZero() && !end.IsZero() {
+		tx = tx.Where("created_at BETWEEN ? AND ?", start, end)
+	}
+	if err := tx.Order("created_at desc").Limit(limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, resolveDefinitionSchemaHistories(ctx, list)
+}
+
+func GetDefinitionResponseHistories(ctx context.Context, dr *DefinitionResponse, start, end time.Time) ([]*DefinitionResponseHistory, error) {
+	var list []*DefinitionResponseHistory
+	tx := model.DB(ctx)
+	if !start.IsZero() && !end.IsZero() {
+		tx = tx.Where("created_at BETWEEN ? AND ?", start, end)
+	}
+	return list, tx.Where("response_id = ?", dr.ID).Order("created_at desc").Find(&list).Error
+}
+
+// GetProjectDefinitionResponseHistories 获取项目中所有公共响应的历史记录，用于项目动态
+func GetProjectDefinitionResponseHistories(ctx context.Context, projectID string, start, end time.Time, limit int) ([]*DefinitionResponseHistory, error) {
+	var list []*DefinitionResponseHistory
+	sub := model.DB(ctx).Model(&DefinitionResponse{}).Select("id").Where("project_id = ?", projectID)
+	tx := model.DB(ctx).Where("response_id IN (?)", sub)
+	if !start.IsZero() && !end.IsZero() {
+		tx = tx.Where("created_at BETWEEN ? AND ?", start, end)
+	}
+	return list, tx.Order("created_at desc").Limit(limit).Find(&list).Error
+}
+
+// GetLatestDefinitionSchemaHistory 获取模型最新的一条历史记录，没有记录时返回nil
+func GetLatestDefinitionSchemaHistory(ctx context.Context, schemaID uint) (*DefinitionSchemaHistory, error) {
+	var dsh DefinitionSchemaHistory
+	tx := model.DB(ctx).Where("schema_id = ?", schemaID).Order("id desc").Take(&dsh)
+	if err := model.NotRecord(tx); err != nil {
+		return nil, err
+	}
+	if tx.Error != nil {
+		return nil, nil
+	}
+	return &dsh, nil
+}
+
+// GetDefinitionSchemasAt 获取某一时间点项目中存在的公共模型，包含之后被删除的
+func GetDefinitionSchemasAt(ctx context.Context, projectID string, t time.Time) ([]*DefinitionSchema, error) {
+	var list []*DefinitionSchema
+	tx := model.DB(ctx).Unscoped().Where("project_id = ? AND type = ? AND created_at <= ?", projectID, SchemaSchema, t)
+	tx = tx.Where("deleted_at IS NULL OR deleted_at > ?", t)
+	return list, tx.Order("display_order asc").Find(&list).Error
+}
+
+// GetDefinitionSchemaHistoriesAfter 获取每个公共模型在某一时间点之后的第一条历史记录
+// 模型历史保存的是修改前的内容，所以这条记录就是该时间点的模型
+func GetDefinitionSchemaHistoriesAfter(ctx context.Context, schemaIDs []uint, t time.Time) (map[uint]*DefinitionSchemaHistory, error) {
+	sub := model.DB(ctx).Model(&DefinitionSchemaHistory{}).Select("MIN(id)").
+		Where("schema_id IN ? AND created_at > ?", schemaIDs, t).Group("schema_id")
+
+	var list []*DefinitionSchemaHistory
+	if err := model.DB(ctx).Where("id IN (?)", sub).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	if err := resolveDefinitionSchemaHistories(ctx, list); err != nil {
+		return nil, err
+	}
+	res := make(map[uint]*DefinitionSchemaHistory, len(list))
+	for _, v := range list {
+		res[v.SchemaID] = v
+	}
+	return res, nil
 }
 
 func MemberInfo(ctx context.Context, memberID uint, unscoped bool) (*team.TeamMember, error) {
@@ -204,3 +282,13 @@ func UserInfo(ctx context.Context, memberID uint, unscoped bool) (*user.User, er
 		return tm.UserInfo(ctx, unscoped)
 	}
 }
+
+// GetDefinitionSchemasUpdatedAfter 按修改时间顺序获取(at, id)之后修改的模型，用于增量同步
+func GetDefinitionSchemasUpdatedAfter(ctx context.Context, at time.Time, id uint, limit int) ([]*DefinitionSchema, error) {
+	var list []*DefinitionSchema
+	return list, model.DB(ctx).
+		Where("type = ? AND (updated_at > ? OR (updated_at = ? AND id > ?))", SchemaSchema, at, at, id).
+		Order("updated_at asc, id asc").
+		Limit(limit).
+		Find(&list).Error
+}
@@ -77,6 +77,21 @@ func (dr *DefinitionResponse) Create(ctx context.Context, tm *team.TeamMember) e
 }
 
 func (dr *DefinitionResponse) Update(ctx context.Context, memberID uint) error {
+	if dr.Type != ResponseCategory {
+		h := &DefinitionResponseHistory{
+			ResponseID:  dr.ID,
+			Name:        dr.Name,
+			Description: dr.Description,
+			Header:      dr.Header,
+			Content:     dr.Content,
+		}
+		h.Create(ctx, memberID)
+	}
+	return dr.update(ctx, memberID)
+}
+
+// update 只写入修改，不记录历史
+func (dr *DefinitionResponse) update(ctx context.Context, memberID uint) error {
 	// 只能修改name、description、header、content
 	return model.DB(ctx).Model(dr).Updates(map[string]interface{}{
 		"name":        dr.Name,
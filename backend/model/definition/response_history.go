@@ -0,0 +1,91 @@
+package definition
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+)
+
+type DefinitionResponseHistory struct {
+	ID          uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	ResponseID  uint   `gorm:"type:bigint;index;not null;comment:response id"`
+	Name        string `gorm:"type:varchar(255);not null;comment:response name"`
+	Description string `gorm:"type:varchar(255);comment:response description"`
+	Header      string `gorm:"type:mediumtext;comment:response header"`
+	Content     string `gorm:"type:mediumtext;comment:response content"`
+	CreatedBy   uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+	model.TimeModel
+}
+
+func (drh *DefinitionResponseHistory) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx)
+	if drh.ID != 0 && drh.ResponseID != 0 {
+		tx = tx.Take(drh, "id = ? AND response_id = ?", drh.ID, drh.ResponseID)
+	} else {
+		return false, errors.New("query condition error")
+	}
+	return tx.Error == nil, model.NotRecord(tx)
+}
+
+func (drh *DefinitionResponseHistory) Create(ctx context.Context, memberID uint) error {
+	var latestRecord DefinitionResponseHistory
+	err := model.DB(ctx).Last(&latestRecord, "response_id = ?", drh.ResponseID).Error
+	if err == nil && latestRecord.CreatedBy == memberID && latestRecord.CreatedAt.Add(5*time.Minute).After(time.Now()) {
+		return model.DB(ctx).Model(latestRecord).Updates(map[string]interface{}{
+			"Name":        drh.Name,
+			"Description": drh.Description,
+			"Header":      drh.Header,
+			"Content":     drh.Content,
+		}).Error
+	}
+
+	drh.CreatedBy = memberID
+	return model.DB(ctx).Create(drh).Error
+}
+
+func (drh *DefinitionResponseHistory) Restore(ctx context.Context, dr *DefinitionResponse, memberID uint) error {
+	newDRH := &DefinitionResponseHistory{
+		ResponseID:  dr.ID,
+		Name:        dr.Name,
+		Description: dr.Description,
+		Header:      dr.Header,
+		Content:     dr.Content,
+	}
+	if err := newDRH.Create(ctx, memberID); err != nil {
+		slog.ErrorContext(ctx, "DefinitionResponseHistory.Restore.Create", "err", err)
+	}
+
+	dr.Name = drh.Name
+	dr.Description = drh.Description
+	dr.Header = drh.Header
+	dr.Content = drh.Content
+	// 恢复前的内容已记录，直接写入以免被合并覆盖
+	return dr.update(ctx, memberID)
+}
+
+// GetHistoryResponseIDs 获取项目中有历史记录的公共响应，包含已删除的响应
+func GetHistoryResponseIDs(ctx context.Context, projectID string) ([]uint, error) {
+	var ids []uint
+	sub := model.DB(ctx).Unscoped().Model(&DefinitionResponse{}).Select("id").Where("project_id = ?", projectID)
+	err := model.DB(ctx).Model(&DefinitionResponseHistory{}).Distinct("response_id").
+		Where("response_id IN (?)", sub).Pluck("response_id", &ids).Error
+	return ids, err
+}
+
+// GetDefinitionResponseHistoryTimes 按时间升序获取响应所有历史记录的ID和创建时间，用于计算保留策略
+func GetDefinitionResponseHistoryTimes(ctx context.Context, responseID uint) ([]*DefinitionResponseHistory, error) {
+	var list []*DefinitionResponseHistory
+	tx := model.DB(ctx).Select("id", "response_id", "created_at").Where("response_id = ?", responseID)
+	return list, tx.Order("id asc").Find(&list).Error
+}
+
+// DeleteDefinitionResponseHistories 彻底删除响应的历史记录
+func DeleteDefinitionResponseHistories(ctx context.Context, responseID uint, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return model.DB(ctx).Unscoped().Where("response_id = ? AND id IN ?", responseID, ids).Delete(&DefinitionResponseHistory{}).Error
+}
@@ -85,7 +85,11 @@ func (ds *DefinitionSchema) Update(ctx context.Context, name, description, schem
 		}
 		h.Create(ctx, memberID)
 	}
+	return ds.update(ctx, name, description, schema, memberID)
+}
 
+// update 只写入修改，不记录历史
+func (ds *DefinitionSchema) update(ctx context.Context, name, description, schema string, memberID uint) error {
 	// 只能修改name、description、schema
 	return model.DB(ctx).Model(ds).Updates(map[string]interface{}{
 		"name":        name,
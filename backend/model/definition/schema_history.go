@@ -7,6 +7,9 @@ import (
 	"time"
 
 	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/utils/delta"
+
+	"gorm.io/gorm"
 )
 
 type DefinitionSchemaHistory struct {
@@ -15,6 +18,7 @@ type DefinitionSchemaHistory struct {
 	Name        string `gorm:"type:varchar(255);not null;comment:schema name"`
 	Description string `gorm:"type:varchar(255);comment:schema description"`
 	Schema      string `gorm:"type:mediumtext;comment:schema content"`
+	BaseID      uint   `gorm:"type:bigint;not null;default:0;comment:delta base history id, 0 means full content"`
 	CreatedBy   uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
 	model.TimeModel
 }
@@ -26,7 +30,10 @@ func (dsh *DefinitionSchemaHistory) Get(ctx context.Context) (bool, error) {
 	} else {
 		return false, errors.New("query condition error")
 	}
-	return tx.Error == nil, model.NotRecord(tx)
+	if err := model.NotRecord(tx); err != nil || tx.Error != nil {
+		return false, err
+	}
+	return true, resolveDefinitionSchemaHistories(ctx, []*DefinitionSchemaHistory{dsh})
 }
 
 func (dsh *DefinitionSchemaHistory) Create(ctx context.Context, memberID uint) error {
@@ -55,5 +62,124 @@ func (dsh *DefinitionSchemaHistory) Restore(ctx context.Context, ds *DefinitionS
 		slog.ErrorContext(ctx, "DefinitionSchemaHistory.Restore.Create", "err", err)
 	}
 
-	return ds.Update(ctx, dsh.Name, dsh.Description, dsh.Schema, memberID)
+	// 恢复前的内容已记录，直接写入以免被合并覆盖
+	return ds.update(ctx, dsh.Name, dsh.Description, dsh.Schema, memberID)
+}
+
+// saveStorage 压缩时改写记录的存储方式，baseID为0时保存完整内容，否则保存相对baseID的差量
+func (dsh *DefinitionSchemaHistory) saveStorage(tx *gorm.DB, baseID uint, schema string) error {
+	return tx.Model(dsh).UpdateColumns(map[string]interface{}{
+		"base_id": baseID,
+		"schema":  schema,
+	}).Error
+}
+
+// resolveDefinitionSchemaHistories 还原差量存储的历史记录内容，基准记录总是完整保存的
+func resolveDefinitionSchemaHistories(ctx context.Context, list []*DefinitionSchemaHistory) error {
+	var baseIDs []uint
+	for _, v := range list {
+		if v.BaseID != 0 {
+			baseIDs = append(baseIDs, v.BaseID)
+		}
+	}
+	if len(baseIDs) == 0 {
+		return nil
+	}
+
+	var bases []*DefinitionSchemaHistory
+	if err := model.DB(ctx).Unscoped().Where("id IN ?", baseIDs).Find(&bases).Error; err != nil {
+		return err
+	}
+	baseMap := make(map[uint]*DefinitionSchemaHistory, len(bases))
+	for _, v := range bases {
+		baseMap[v.ID] = v
+	}
+
+	for _, v := range list {
+		if v.BaseID == 0 {
+			continue
+		}
+		base, ok := baseMap[v.BaseID]
+		if !ok {
+			return errors.New("history delta base not found")
+		}
+		if base.BaseID != 0 {
+			return errors.New("history delta base is not stored in full")
+		}
+		schema, err := delta.Patch(base.Schema, v.Schema)
+		if err != nil {
+			return err
+		}
+		v.Schema = schema
+		v.BaseID = 0
+	}
+	return nil
+}
+
+// GetHistorySchemaIDs 获取项目中有历史记录的公共模型，包含已删除的模型
+func GetHistorySchemaIDs(ctx context.Context, projectID string) ([]uint, error) {
+	var ids []uint
+	sub := model.DB(ctx).Unscoped().Model(&DefinitionSchema{}).Select("id").Where("project_id = ?", projectID)
+	err := model.DB(ctx).Model(&DefinitionSchemaHistory{}).Distinct("schema_id").
+		Where("schema_id IN (?)", sub).Pluck("schema_id", &ids).Error
+	return ids, err
+}
+
+// GetDefinitionSchemaHistoryTimes 按时间升序获取模型所有历史记录的ID和创建时间，用于计算保留策略
+func GetDefinitionSchemaHistoryTimes(ctx context.Context, schemaID uint) ([]*DefinitionSchemaHistory, error) {
+	var list []*DefinitionSchemaHistory
+	tx := model.DB(ctx).Select("id", "schema_id", "created_at").Where("schema_id = ?", schemaID)
+	return list, tx.Order("id asc").Find(&list).Error
+}
+
+// CompactDefinitionSchemaHistories 删除removeIDs中的历史记录，并按是否使用差量存储改写保留的记录
+// 改写和删除在同一事务中完成，避免读到基准尚未改写完成的差量记录
+func CompactDefinitionSchemaHistories(ctx context.Context, schemaID uint, removeIDs []uint, useDelta bool) error {
+	var list []*DefinitionSchemaHistory
+	if err := model.DB(ctx).Where("schema_id = ?", schemaID).Order("id asc").Find(&list).Error; err != nil {
+		return err
+	}
+	storedBase := make(map[uint]uint, len(list))
+	for _, v := range list {
+		storedBase[v.ID] = v.BaseID
+	}
+	if err := resolveDefinitionSchemaHistories(ctx, list); err != nil {
+		return err
+	}
+
+	removed := make(map[uint]bool, len(removeIDs))
+	for _, id := range removeIDs {
+		removed[id] = true
+	}
+	kept := make([]*DefinitionSchemaHistory, 0, len(list))
+	for _, v := range list {
+		if !removed[v.ID] {
+			kept = append(kept, v)
+		}
+	}
+
+	return model.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, v := range kept {
+			var baseID uint
+			schema := v.Schema
+			if b := delta.BaseIndex(len(kept), i); useDelta && b != -1 {
+				d := delta.Diff(kept[b].Schema, v.Schema)
+				if delta.Worthwhile(d, v.Schema) {
+					baseID, schema = kept[b].ID, d
+				}
+			}
+			// 完整保存且原本就是完整保存的记录无需改写
+			if baseID == 0 && storedBase[v.ID] == 0 {
+				continue
+			}
+			if err := v.saveStorage(tx, baseID, schema); err != nil {
+				return err
+			}
+		}
+
+		if len(removeIDs) == 0 {
+			return nil
+		}
+		return tx.Unscoped().Where("schema_id = ? AND id IN ?", schemaID, removeIDs).Delete(&DefinitionSchemaHistory{}).Error
+	})
 }
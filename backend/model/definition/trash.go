@@ -0,0 +1,164 @@
+package definition
+
+import (
+	"context"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	referencerelation "github.com/apicat/apicat/v2/backend/model/reference_relation"
+	"github.com/apicat/apicat/v2/backend/model/team"
+
+	"gorm.io/gorm"
+)
+
+// GetDeletedDefinitionSchemas 获取回收站中的公共模型，since不为零值时只返回该时间之后删除的
+func GetDeletedDefinitionSchemas(ctx context.Context, projectID string, since time.Time) ([]*DefinitionSchema, error) {
+	var list []*DefinitionSchema
+	tx := model.DB(ctx).Unscoped().Where("project_id = ? AND type = ? AND deleted_at IS NOT NULL", projectID, SchemaSchema)
+	if !since.IsZero() {
+		tx = tx.Where("deleted_at >= ?", since)
+	}
+	return list, tx.Order("deleted_at desc").Find(&list).Error
+}
+
+// GetDeletedDefinitionSchemasByIDs 通过ID获取回收站中的公共模型
+func GetDeletedDefinitionSchemasByIDs(ctx context.Context, projectID string, ids []uint) ([]*DefinitionSchema, error) {
+	var list []*DefinitionSchema
+	tx := model.DB(ctx).Unscoped().Where("id IN ? AND project_id = ? AND type = ? AND deleted_at IS NOT NULL", ids, projectID, SchemaSchema)
+	return list, tx.Find(&list).Error
+}
+
+// RestoreDefinitionSchemas 恢复回收站中的公共模型，所在分类已删除时恢复到根目录
+func RestoreDefinitionSchemas(ctx context.Context, member *team.TeamMember, schemas []*DefinitionSchema) ([]uint, error) {
+	var restoreIDs []uint
+	if len(schemas) == 0 {
+		return restoreIDs, nil
+	}
+
+	parentIDs := make([]uint, 0, len(schemas))
+	for _, s := range schemas {
+		parentIDs = append(parentIDs, s.ParentID)
+	}
+	var existParentIDs []uint
+	if err := model.DB(ctx).Model(&DefinitionSchema{}).Where("id IN ?", parentIDs).Pluck("id", &existParentIDs).Error; err != nil {
+		return restoreIDs, err
+	}
+	parentIDMap := make(map[uint]bool, len(existParentIDs))
+	for _, id := range existParentIDs {
+		parentIDMap[id] = true
+	}
+
+	for _, s := range schemas {
+		updateData := map[string]interface{}{
+			"display_order": 0,
+			"updated_by":    member.ID,
+			"updated_at":    time.Now(),
+			"deleted_by":    gorm.Expr("NULL"),
+			"deleted_at":    gorm.Expr("NULL"),
+		}
+		if !parentIDMap[s.ParentID] {
+			updateData["parent_id"] = 0
+			s.ParentID = 0
+		}
+		if err := model.DB(ctx).Unscoped().Model(s).Updates(updateData).Error; err == nil {
+			s.DeletedAt = gorm.DeletedAt{}
+			restoreIDs = append(restoreIDs, s.ID)
+		}
+	}
+	return restoreIDs, nil
+}
+
+// PurgeDefinitionSchemas 彻底删除before之前删除的公共模型及其历史记录
+func PurgeDefinitionSchemas(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := model.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		sub := tx.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&DefinitionSchema{}).
+			Select("id").Where("deleted_at < ?", before)
+		if err := tx.Unscoped().Where("schema_id IN (?)", sub).Delete(&DefinitionSchemaHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ref_type = ? AND ref_id IN (?)", referencerelation.SnapshotSchema, sub).Delete(&referencerelation.DerefSnapshot{}).Error; err != nil {
+			return err
+		}
+		ret := tx.Unscoped().Where("deleted_at < ?", before).Delete(&DefinitionSchema{})
+		count = ret.RowsAffected
+		return ret.Error
+	})
+	return count, err
+}
+
+// GetDeletedDefinitionResponses 获取回收站中的公共响应，since不为零值时只返回该时间之后删除的
+func GetDeletedDefinitionResponses(ctx context.Context, projectID string, since time.Time) ([]*DefinitionResponse, error) {
+	var list []*DefinitionResponse
+	tx := model.DB(ctx).Unscoped().Where("project_id = ? AND type = ? AND deleted_at IS NOT NULL", projectID, ResponseResponse)
+	if !since.IsZero() {
+		tx = tx.Where("deleted_at >= ?", since)
+	}
+	return list, tx.Order("deleted_at desc").Find(&list).Error
+}
+
+// GetDeletedDefinitionResponsesByIDs 通过ID获取回收站中的公共响应
+func GetDeletedDefinitionResponsesByIDs(ctx context.Context, projectID string, ids []uint) ([]*DefinitionResponse, error) {
+	var list []*DefinitionResponse
+	tx := model.DB(ctx).Unscoped().Where("id IN ? AND project_id = ? AND type = ? AND deleted_at IS NOT NULL", ids, projectID, ResponseResponse)
+	return list, tx.Find(&list).Error
+}
+
+// RestoreDefinitionResponses 恢复回收站中的公共响应，所在分类已删除时恢复到根目录
+func RestoreDefinitionResponses(ctx context.Context, member *team.TeamMember, responses []*DefinitionResponse) ([]uint, error) {
+	var restoreIDs []uint
+	if len(responses) == 0 {
+		return restoreIDs, nil
+	}
+
+	parentIDs := make([]uint, 0, len(responses))
+	for _, r := range responses {
+		parentIDs = append(parentIDs, r.ParentID)
+	}
+	var existParentIDs []uint
+	if err := model.DB(ctx).Model(&DefinitionResponse{}).Where("id IN ?", parentIDs).Pluck("id", &existParentIDs).Error; err != nil {
+		return restoreIDs, err
+	}
+	parentIDMap := make(map[uint]bool, len(existParentIDs))
+	for _, id := range existParentIDs {
+		parentIDMap[id] = true
+	}
+
+	for _, r := range responses {
+		updateData := map[string]interface{}{
+			"display_order": 0,
+			"updated_by":    member.ID,
+			"updated_at":    time.Now(),
+			"deleted_by":    gorm.Expr("NULL"),
+			"deleted_at":    gorm.Expr("NULL"),
+		}
+		if !parentIDMap[r.ParentID] {
+			updateData["parent_id"] = 0
+			r.ParentID = 0
+		}
+		if err := model.DB(ctx).Unscoped().Model(r).Updates(updateData).Error; err == nil {
+			r.DeletedAt = gorm.DeletedAt{}
+			restoreIDs = append(restoreIDs, r.ID)
+		}
+	}
+	return restoreIDs, nil
+}
+
+// PurgeDefinitionResponses 彻底删除before之前删除的公共响应及其历史记录
+func PurgeDefinitionResponses(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := model.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		sub := tx.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&DefinitionResponse{}).
+			Select("id").Where("deleted_at < ?", before)
+		if err := tx.Unscoped().Where("response_id IN (?)", sub).Delete(&DefinitionResponseHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ref_type = ? AND ref_id IN (?)", referencerelation.SnapshotResponse, sub).Delete(&referencerelation.DerefSnapshot{}).Error; err != nil {
+			return err
+		}
+		ret := tx.Unscoped().Where("deleted_at < ?", before).Delete(&DefinitionResponse{})
+		count = ret.RowsAffected
+		return ret.Error
+	})
+	return count, err
+}
@@ -0,0 +1,103 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+)
+
+const (
+	TargetCollection = "collection"
+	TargetSchema     = "schema"
+)
+
+// Embedding 接口、文档和模型的文本向量，用于语义搜索
+type Embedding struct {
+	ID         uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	ProjectID  string `gorm:"type:varchar(24);index;not null;comment:project id"`
+	TargetType string `gorm:"type:varchar(32);uniqueIndex:ukey;not null;comment:collection,schema"`
+	TargetID   uint   `gorm:"type:bigint;uniqueIndex:ukey;not null;comment:collection or schema id"`
+	Model      string `gorm:"type:varchar(255);not null;comment:embedding model"`
+	Hash       string `gorm:"type:varchar(64);not null;comment:sha256 of embedded text"`
+	Vector     []byte `gorm:"type:mediumblob;comment:normalized float32 vector, little endian"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Get 按目标获取向量
+func (e *Embedding) Get(ctx context.Context) (bool, error) {
+	if e.TargetType == "" || e.TargetID == 0 {
+		return false, errors.New("query condition error")
+	}
+	tx := model.DB(ctx).Take(e, "target_type = ? AND target_id = ?", e.TargetType, e.TargetID)
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+// Save 保存目标的向量，已存在时覆盖
+func (e *Embedding) Save(ctx context.Context) error {
+	old := &Embedding{TargetType: e.TargetType, TargetID: e.TargetID}
+	exist, err := old.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return model.DB(ctx).Create(e).Error
+	}
+
+	e.ID = old.ID
+	return model.DB(ctx).Model(old).Updates(map[string]interface{}{
+		"project_id": e.ProjectID,
+		"model":      e.Model,
+		"hash":       e.Hash,
+		"vector":     e.Vector,
+	}).Error
+}
+
+// GetTargetEmbeddings 获取目标已有的向量信息，不包含向量内容，用于判断是否需要重新生成
+func GetTargetEmbeddings(ctx context.Context, targetType string, targetIDs []uint) (map[uint]*Embedding, error) {
+	var list []*Embedding
+	if err := model.DB(ctx).Omit("vector").
+		Where("target_type = ? AND target_id IN ?", targetType, targetIDs).
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	res := make(map[uint]*Embedding, len(list))
+	for _, e := range list {
+		res[e.TargetID] = e
+	}
+	return res, nil
+}
+
+// GetProjectsEmbeddings 获取项目中指定模型生成的向量
+func GetProjectsEmbeddings(ctx context.Context, projectIDs []string, embeddingModel string) ([]*Embedding, error) {
+	var list []*Embedding
+	return list, model.DB(ctx).
+		Where("project_id IN ? AND model = ?", projectIDs, embeddingModel).
+		Find(&list).Error
+}
+
+// DeleteOrphanEmbeddings 删除目标已被彻底删除的向量，回收站中的目标保留以便恢复后继续使用
+func DeleteOrphanEmbeddings(ctx context.Context) (int64, error) {
+	var total int64
+	targets := map[string]interface{}{
+		TargetCollection: &collection.Collection{},
+		TargetSchema:     &definition.DefinitionSchema{},
+	}
+	for targetType, m := range targets {
+		sub := model.DB(ctx).Unscoped().Model(m).Select("id")
+		tx := model.DB(ctx).
+			Where("target_type = ? AND target_id NOT IN (?)", targetType, sub).
+			Delete(&Embedding{})
+		if tx.Error != nil {
+			return total, tx.Error
+		}
+		total += tx.RowsAffected
+	}
+	return total, nil
+}
@@ -2,6 +2,7 @@ package global
 
 import (
 	"context"
+	"time"
 
 	"github.com/apicat/apicat/v2/backend/model"
 	"github.com/apicat/apicat/v2/backend/module/spec"
@@ -73,3 +74,23 @@ func SortGlobalParameters(ctx context.Context, pID, in string, ids []uint) error
 		return nil
 	})
 }
+
+func GetGlobalParameterHistories(ctx context.Context, gp *GlobalParameter, start, end time.Time) ([]*GlobalParameterHistory, error) {
+	var list []*GlobalParameterHistory
+	tx := model.DB(ctx)
+	if !start.IsZero() && !end.IsZero() {
+		tx = tx.Where("created_at BETWEEN ? AND ?", start, end)
+	}
+	return list, tx.Where("parameter_id = ?", gp.ID).Order("created_at desc").Find(&list).Error
+}
+
+// GetProjectGlobalParameterHistories 获取项目中所有全局参数的历史记录，用于项目动态
+func GetProjectGlobalParameterHistories(ctx context.Context, projectID string, start, end time.Time, limit int) ([]*GlobalParameterHistory, error) {
+	var list []*GlobalParameterHistory
+	sub := model.DB(ctx).Model(&GlobalParameter{}).Select("id").Where("project_id = ?", projectID)
+	tx := model.DB(ctx).Where("parameter_id IN (?)", sub)
+	if !start.IsZero() && !end.IsZero() {
+		tx = tx.Where("created_at BETWEEN ? AND ?", start, end)
+	}
+	return list, tx.Order("created_at desc").Limit(limit).Find(&list).Error
+}
@@ -52,8 +52,20 @@ func (gp *GlobalParameter) Create(ctx context.Context) error {
 	return model.DB(ctx).Create(gp).Error
 }
 
-// Update 更新全局参数
-func (gp *GlobalParameter) Update(ctx context.Context) error {
+// Update 更新全局参数，同时记录历史
+func (gp *GlobalParameter) Update(ctx context.Context, memberID uint) error {
+	h := &GlobalParameterHistory{
+		ParameterID: gp.ID,
+		Name:        gp.Name,
+		Required:    gp.Required,
+		Schema:      gp.Schema,
+	}
+	h.Create(ctx, memberID)
+	return gp.update(ctx)
+}
+
+// update 只写入修改，不记录历史
+func (gp *GlobalParameter) update(ctx context.Context) error {
 	// 只能修改name、required、schema
 	return model.DB(ctx).Model(gp).Updates(map[string]interface{}{
 		"name":     gp.Name,
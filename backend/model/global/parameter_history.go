@@ -0,0 +1,87 @@
+package global
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+)
+
+type GlobalParameterHistory struct {
+	ID          uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	ParameterID uint   `gorm:"type:bigint;index;not null;comment:global parameter id"`
+	Name        string `gorm:"type:varchar(255);not null;comment:param name"`
+	Required    bool   `gorm:"type:tinyint;not null;comment:is required"`
+	Schema      string `gorm:"type:mediumtext;comment:param schema"`
+	CreatedBy   uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+	model.TimeModel
+}
+
+func (gph *GlobalParameterHistory) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx)
+	if gph.ID != 0 && gph.ParameterID != 0 {
+		tx = tx.Take(gph, "id = ? AND parameter_id = ?", gph.ID, gph.ParameterID)
+	} else {
+		return false, errors.New("query condition error")
+	}
+	return tx.Error == nil, model.NotRecord(tx)
+}
+
+func (gph *GlobalParameterHistory) Create(ctx context.Context, memberID uint) error {
+	var latestRecord GlobalParameterHistory
+	err := model.DB(ctx).Last(&latestRecord, "parameter_id = ?", gph.ParameterID).Error
+	if err == nil && latestRecord.CreatedBy == memberID && latestRecord.CreatedAt.Add(5*time.Minute).After(time.Now()) {
+		return model.DB(ctx).Model(latestRecord).Updates(map[string]interface{}{
+			"Name":     gph.Name,
+			"Required": gph.Required,
+			"Schema":   gph.Schema,
+		}).Error
+	}
+
+	gph.CreatedBy = memberID
+	return model.DB(ctx).Create(gph).Error
+}
+
+func (gph *GlobalParameterHistory) Restore(ctx context.Context, gp *GlobalParameter, memberID uint) error {
+	newGPH := &GlobalParameterHistory{
+		ParameterID: gp.ID,
+		Name:        gp.Name,
+		Required:    gp.Required,
+		Schema:      gp.Schema,
+	}
+	if err := newGPH.Create(ctx, memberID); err != nil {
+		slog.ErrorContext(ctx, "GlobalParameterHistory.Restore.Create", "err", err)
+	}
+
+	gp.Name = gph.Name
+	gp.Required = gph.Required
+	gp.Schema = gph.Schema
+	// 恢复前的内容已记录，直接写入以免被合并覆盖
+	return gp.update(ctx)
+}
+
+// GetHistoryParameterIDs 获取项目中有历史记录的全局参数，包含已删除的参数
+func GetHistoryParameterIDs(ctx context.Context, projectID string) ([]uint, error) {
+	var ids []uint
+	sub := model.DB(ctx).Unscoped().Model(&GlobalParameter{}).Select("id").Where("project_id = ?", projectID)
+	err := model.DB(ctx).Model(&GlobalParameterHistory{}).Distinct("parameter_id").
+		Where("parameter_id IN (?)", sub).Pluck("parameter_id", &ids).Error
+	return ids, err
+}
+
+// GetGlobalParameterHistoryTimes 按时间升序获取参数所有历史记录的ID和创建时间，用于计算保留策略
+func GetGlobalParameterHistoryTimes(ctx context.Context, parameterID uint) ([]*GlobalParameterHistory, error) {
+	var list []*GlobalParameterHistory
+	tx := model.DB(ctx).Select("id", "parameter_id", "created_at").Where("parameter_id = ?", parameterID)
+	return list, tx.Order("id asc").Find(&list).Error
+}
+
+// DeleteGlobalParameterHistories 彻底删除全局参数的历史记录
+func DeleteGlobalParameterHistories(ctx context.Context, parameterID uint, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return model.DB(ctx).Unscoped().Where("parameter_id = ? AND id IN ?", parameterID, ids).Delete(&GlobalParameterHistory{}).Error
+}
@@ -0,0 +1,90 @@
+package iteration
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/team"
+)
+
+const (
+	BranchTargetCollection = "collection"
+	BranchTargetSchema     = "schema"
+)
+
+// IterationBranchItem 迭代分支中修改过的接口或模型，首次修改时复制主线的内容作为基准，之后只修改分支
+// TargetID为0表示在分支中新建，Deleted表示在分支中删除
+// Title和BaseTitle对于模型是名称，Description只用于模型
+type IterationBranchItem struct {
+	ID              uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	IterationID     string `gorm:"type:varchar(24);index;not null;comment:iteration id"`
+	TargetType      string `gorm:"type:varchar(32);not null;comment:target type:collection,schema"`
+	TargetID        uint   `gorm:"type:bigint;not null;default:0;comment:mainline collection or schema id, 0 for created in branch"`
+	ParentID        uint   `gorm:"type:bigint;not null;default:0;comment:parent id when created in branch"`
+	Type            string `gorm:"type:varchar(255);not null;comment:collection or schema type"`
+	BaseTitle       string `gorm:"type:varchar(255);comment:mainline title or name at branch point"`
+	BaseDescription string `gorm:"type:varchar(255);comment:mainline schema description at branch point"`
+	BaseContent     string `gorm:"type:mediumtext;comment:mainline content or schema at branch point"`
+	Title           string `gorm:"type:varchar(255);not null;comment:branch title or name"`
+	Description     string `gorm:"type:varchar(255);comment:branch schema description"`
+	Content         string `gorm:"type:mediumtext;comment:branch content or schema"`
+	Deleted         bool   `gorm:"type:tinyint;not null;default:0;comment:deleted in branch"`
+	CreatedBy       uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+	UpdatedBy       uint   `gorm:"type:bigint;not null;default:0;comment:updated by member id"`
+	model.TimeModel
+}
+
+// Get 按ID或分支中对应的主线接口、模型查询
+func (bi *IterationBranchItem) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx)
+	if bi.ID != 0 && bi.IterationID != "" {
+		tx = tx.Take(bi, "id = ? AND iteration_id = ?", bi.ID, bi.IterationID)
+	} else if bi.IterationID != "" && bi.TargetType != "" && bi.TargetID != 0 {
+		tx = tx.Take(bi, "iteration_id = ? AND target_type = ? AND target_id = ?", bi.IterationID, bi.TargetType, bi.TargetID)
+	} else {
+		return false, errors.New("query condition error")
+	}
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+func (bi *IterationBranchItem) Create(ctx context.Context, member *team.TeamMember) error {
+	bi.CreatedBy = member.ID
+	bi.UpdatedBy = member.ID
+	return model.DB(ctx).Create(bi).Error
+}
+
+// Update 修改分支中的内容，修改后即不再是删除状态
+func (bi *IterationBranchItem) Update(ctx context.Context, title, description, content string, memberID uint) error {
+	bi.Title = title
+	bi.Description = description
+	bi.Content = content
+	bi.Deleted = false
+	return model.DB(ctx).Model(bi).Updates(map[string]interface{}{
+		"title":       title,
+		"description": description,
+		"content":     content,
+		"deleted":     false,
+		"updated_by":  memberID,
+	}).Error
+}
+
+// MarkDeleted 在分支中删除
+func (bi *IterationBranchItem) MarkDeleted(ctx context.Context, memberID uint) error {
+	bi.Deleted = true
+	return model.DB(ctx).Model(bi).Updates(map[string]interface{}{
+		"deleted":    true,
+		"updated_by": memberID,
+	}).Error
+}
+
+// Delete 放弃分支中的修改，直接删除
+func (bi *IterationBranchItem) Delete(ctx context.Context) error {
+	return model.DB(ctx).Unscoped().Delete(bi).Error
+}
+
+// IsCreated 是否在分支中新建
+func (bi *IterationBranchItem) IsCreated() bool {
+	return bi.TargetID == 0
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 
 	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/collection"
 
 	"gorm.io/gorm"
 )
 
-func GetIterations(ctx context.Context, teamID string, page, pageSize int, pIDs ...string) ([]*Iteration, error) {
+func GetIterations(ctx context.Context, teamID, state string, page, pageSize int, pIDs ...string) ([]*Iteration, error) {
 	tx := model.DB(ctx).Where("team_id = ?", teamID)
 	if len(pIDs) > 0 {
 		tx = tx.Where("project_id in (?)", pIDs)
 	}
+	if state != "" {
+		tx = tx.Where("state = ?", state)
+	}
 	if page > 0 && pageSize > 0 {
 		tx = tx.Offset((page - 1) * pageSize).Limit(pageSize)
 	}
@@ -21,12 +25,15 @@ func GetIterations(ctx context.Context, teamID string, page, pageSize int, pIDs
 	return iterations, err
 }
 
-func GetIterationsCount(ctx context.Context, teamID string, pIDs ...string) (int64, error) {
+func GetIterationsCount(ctx context.Context, teamID, state string, pIDs ...string) (int64, error) {
 	tx := model.DB(ctx)
 	tx = tx.Model(&Iteration{}).Where("team_id = ?", teamID)
 	if len(pIDs) > 0 {
 		tx = tx.Where("project_id in (?)", pIDs)
 	}
+	if state != "" {
+		tx = tx.Where("state = ?", state)
+	}
 	var count int64
 	err := tx.Count(&count).Error
 	return count, err
@@ -37,6 +44,19 @@ func GetIterationApi(ctx context.Context, i *Iteration) ([]*IterationApi, error)
 	return iterationApi, model.DB(ctx).Where("iteration_id = ?", i.ID).Find(&iterationApi).Error
 }
 
+// GetIterationApis 获取迭代中的接口，不包含目录，status和assigneeID不为空时按其筛选
+func GetIterationApis(ctx context.Context, iterationID, status string, assigneeID uint) ([]*IterationApi, error) {
+	tx := model.DB(ctx).Where("iteration_id = ? AND collection_type != ?", iterationID, collection.CategoryType)
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+	if assigneeID != 0 {
+		tx = tx.Where("assignee_id = ?", assigneeID)
+	}
+	var list []*IterationApi
+	return list, tx.Order("id asc").Find(&list).Error
+}
+
 func BatchDeleteIterationApi(ctx context.Context, cIDs ...uint) error {
 	if len(cIDs) == 0 {
 		return nil
@@ -51,3 +71,37 @@ func RestoreIterationApi(ctx context.Context, cIDs []uint) error {
 
 	return model.DB(ctx).Unscoped().Model(&IterationApi{}).Where("collection_id IN (?)", cIDs).Update("deleted_at", gorm.Expr("NULL")).Error
 }
+
+// GetBranchItems 获取迭代分支中修改过的接口和模型
+func GetBranchItems(ctx context.Context, iterationID string, targetType ...string) ([]*IterationBranchItem, error) {
+	tx := model.DB(ctx).Where("iteration_id = ?", iterationID)
+	if len(targetType) > 0 {
+		tx = tx.Where("target_type IN (?)", targetType)
+	}
+	var items []*IterationBranchItem
+	return items, tx.Order("id asc").Find(&items).Error
+}
+
+// ClearBranchItems 清空迭代分支，合并后分支与主线一致
+func ClearBranchItems(ctx context.Context, iterationID string) error {
+	return model.DB(ctx).Unscoped().Delete(&IterationBranchItem{}, "iteration_id = ?", iterationID).Error
+}
+
+// GetMergeRequests 获取迭代的合并请求列表
+func GetMergeRequests(ctx context.Context, iterationID string) ([]*IterationMergeRequest, error) {
+	var list []*IterationMergeRequest
+	return list, model.DB(ctx).Where("iteration_id = ?", iterationID).Order("id desc").Find(&list).Error
+}
+
+// GetOpenMergeRequest 获取迭代打开中的合并请求，没有时返回nil
+func GetOpenMergeRequest(ctx context.Context, iterationID string) (*IterationMergeRequest, error) {
+	mr := &IterationMergeRequest{}
+	tx := model.DB(ctx).Take(mr, "iteration_id = ? AND status = ?", iterationID, MergeRequestOpen)
+	if err := model.NotRecord(tx); err != nil {
+		return nil, err
+	}
+	if tx.Error != nil {
+		return nil, nil
+	}
+	return mr, nil
+}
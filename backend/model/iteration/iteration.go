@@ -2,6 +2,7 @@ package iteration
 
 import (
 	"context"
+	"time"
 
 	"github.com/apicat/apicat/v2/backend/model"
 	"github.com/apicat/apicat/v2/backend/model/collection"
@@ -13,15 +14,24 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	StatePlanning  = "planning"
+	StateActive    = "active"
+	StateCompleted = "completed"
+)
+
 type Iteration struct {
-	ID          string `gorm:"type:varchar(24);primarykey"`
-	TeamID      string `gorm:"type:varchar(24);not null;comment:team id"`
-	ProjectID   string `gorm:"type:varchar(24);index;not null;comment:project id"`
-	Title       string `gorm:"type:varchar(255);not null;comment:iteartion title"`
-	Description string `gorm:"type:varchar(255);comment:iteration description"`
-	CreatedBy   uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
-	UpdatedBy   uint   `gorm:"type:bigint;not null;default:0;comment:updated by member id"`
-	DeletedBy   uint   `gorm:"type:bigint;default:null;comment:deleted by member id"`
+	ID          string     `gorm:"type:varchar(24);primarykey"`
+	TeamID      string     `gorm:"type:varchar(24);not null;comment:team id"`
+	ProjectID   string     `gorm:"type:varchar(24);index;not null;comment:project id"`
+	Title       string     `gorm:"type:varchar(255);not null;comment:iteartion title"`
+	Description string     `gorm:"type:varchar(255);comment:iteration description"`
+	StartDate   *time.Time `gorm:"type:datetime;comment:start date"`
+	DueDate     *time.Time `gorm:"type:datetime;comment:due date"`
+	State       string     `gorm:"type:varchar(32);not null;default:planning;comment:state:planning,active,completed"`
+	CreatedBy   uint       `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+	UpdatedBy   uint       `gorm:"type:bigint;not null;default:0;comment:updated by member id"`
+	DeletedBy   uint       `gorm:"type:bigint;default:null;comment:deleted by member id"`
 	model.TimeModel
 }
 
@@ -36,14 +46,20 @@ func (i *Iteration) Create(ctx context.Context, member *team.TeamMember) error {
 	i.TeamID = member.TeamID
 	i.CreatedBy = member.ID
 	i.UpdatedBy = member.ID
+	if i.State == "" {
+		i.State = StatePlanning
+	}
 	return model.DB(ctx).Create(i).Error
 }
 
 func (i *Iteration) Update(ctx context.Context, member *team.TeamMember) error {
-	// 只能更新Title和Description
+	// 只能更新Title、Description、日期和状态
 	return model.DB(ctx).Model(i).Updates(map[string]interface{}{
 		"title":       i.Title,
 		"description": i.Description,
+		"start_date":  i.StartDate,
+		"due_date":    i.DueDate,
+		"state":       i.State,
 		"updated_by":  member.ID,
 	}).Error
 }
@@ -61,6 +77,14 @@ func (i *Iteration) Delete(ctx context.Context, member *team.TeamMember) error {
 				return err
 			}
 
+			if err := tx.Unscoped().Delete(&IterationBranchItem{}, "iteration_id = ?", i.ID).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Delete(&IterationMergeRequest{}, "iteration_id = ?", i.ID).Error; err != nil {
+				return err
+			}
+
 			return tx.Delete(i).Error
 		},
 	)
@@ -105,6 +129,11 @@ func (i *Iteration) PlanningIterationApi(ctx context.Context, collections []*col
 	return i.BatchDeleteCollection(ctx, wantPop)
 }
 
+// IsOverdue 已过截止日期但尚未完成
+func (i *Iteration) IsOverdue(now time.Time) bool {
+	return i.DueDate != nil && i.State != StateCompleted && now.After(*i.DueDate)
+}
+
 // GetIterationApiCount 获取迭代涉及的api数量
 func (i *Iteration) GetIterationApiCount(ctx context.Context) (int64, error) {
 	var count int64
@@ -112,6 +141,13 @@ func (i *Iteration) GetIterationApiCount(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+// GetIterationApiDoneCount 获取迭代中已完成的api数量
+func (i *Iteration) GetIterationApiDoneCount(ctx context.Context) (int64, error) {
+	var count int64
+	err := model.DB(ctx).Model(&IterationApi{}).Where("iteration_id = ? AND collection_type != ? AND status = ?", i.ID, collection.CategoryType, ApiStatusDone).Count(&count).Error
+	return count, err
+}
+
 func (i *Iteration) BatchCreateCollection(ctx context.Context, collections []*collection.Collection) error {
 	if len(collections) == 0 {
 		return nil
@@ -123,6 +159,7 @@ func (i *Iteration) BatchCreateCollection(ctx context.Context, collections []*co
 			IterationID:    i.ID,
 			CollectionID:   c.ID,
 			CollectionType: c.Type,
+			Status:         ApiStatusTodo,
 		})
 	}
 	return model.DB(ctx).Create(ias).Error
@@ -2,22 +2,71 @@ package iteration
 
 import (
 	"context"
+	"time"
 
 	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/user"
 )
 
+const (
+	ApiStatusTodo       = "todo"
+	ApiStatusDesigning  = "designing"
+	ApiStatusDeveloping = "developing"
+	ApiStatusTesting    = "testing"
+	ApiStatusDone       = "done"
+)
+
+// ApiStatuses 接口在迭代中的所有状态，按进度排列
+var ApiStatuses = []string{ApiStatusTodo, ApiStatusDesigning, ApiStatusDeveloping, ApiStatusTesting, ApiStatusDone}
+
 type IterationApi struct {
-	ID             uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
-	IterationID    string `gorm:"type:varchar(24);index;not null;comment:iteration id"`
-	CollectionID   uint   `gorm:"type:bigint;not null;comment:collection id"`
-	CollectionType string `gorm:"type:varchar(255);not null;comment:collection type:category,doc,http"`
+	ID             uint       `gorm:"type:bigint;primaryKey;autoIncrement"`
+	IterationID    string     `gorm:"type:varchar(24);index;not null;comment:iteration id"`
+	CollectionID   uint       `gorm:"type:bigint;not null;comment:collection id"`
+	CollectionType string     `gorm:"type:varchar(255);not null;comment:collection type:category,doc,http"`
+	Status         string     `gorm:"type:varchar(32);not null;default:todo;comment:status:todo,designing,developing,testing,done"`
+	AssigneeID     uint       `gorm:"type:bigint;not null;default:0;comment:assignee member id"`
+	Note           string     `gorm:"type:varchar(255);comment:note"`
+	DoneAt         *time.Time `gorm:"type:datetime;comment:time the status changed to done"`
 	model.TimeModel
 }
 
+func (ia *IterationApi) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx).Take(ia, "iteration_id = ? AND collection_id = ?", ia.IterationID, ia.CollectionID)
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
 func (ia *IterationApi) Create(ctx context.Context) error {
 	return model.DB(ctx).Create(ia).Error
 }
 
+// UpdateProgress 更新接口的进度，状态变为done时记录完成时间，用于计算燃尽图
+func (ia *IterationApi) UpdateProgress(ctx context.Context, status string, assigneeID uint, note string) error {
+	if status != ia.Status {
+		if status == ApiStatusDone {
+			now := time.Now()
+			ia.DoneAt = &now
+		} else {
+			ia.DoneAt = nil
+		}
+	}
+	ia.Status = status
+	ia.AssigneeID = assigneeID
+	ia.Note = note
+	return model.DB(ctx).Model(ia).Updates(map[string]interface{}{
+		"status":      ia.Status,
+		"assignee_id": ia.AssigneeID,
+		"note":        ia.Note,
+		"done_at":     ia.DoneAt,
+	}).Error
+}
+
 func (ia *IterationApi) Delete(ctx context.Context) error {
 	return model.DB(ctx).Delete(ia).Error
 }
+
+// AssigneeInfo 负责人的用户信息
+func (ia *IterationApi) AssigneeInfo(ctx context.Context) (*user.User, error) {
+	return memberUserInfo(ctx, ia.AssigneeID)
+}
@@ -0,0 +1,72 @@
+package iteration
+
+import (
+	"context"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/team"
+	"github.com/apicat/apicat/v2/backend/model/user"
+)
+
+const (
+	MergeRequestOpen   = "open"
+	MergeRequestMerged = "merged"
+	MergeRequestClosed = "closed"
+)
+
+// IterationMergeRequest 将迭代分支合并到主线的请求，同一迭代同时只能有一个打开的请求
+type IterationMergeRequest struct {
+	ID          uint       `gorm:"type:bigint;primaryKey;autoIncrement"`
+	IterationID string     `gorm:"type:varchar(24);index;not null;comment:iteration id"`
+	Title       string     `gorm:"type:varchar(255);not null;comment:merge request title"`
+	Description string     `gorm:"type:text;comment:merge request description"`
+	Status      string     `gorm:"type:varchar(32);not null;comment:status:open,merged,closed"`
+	CreatedBy   uint       `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+	ClosedBy    uint       `gorm:"type:bigint;not null;default:0;comment:merged or closed by member id"`
+	ClosedAt    *time.Time `gorm:"type:datetime;comment:merged or closed time"`
+	model.TimeModel
+}
+
+func (mr *IterationMergeRequest) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx).Take(mr, "id = ? AND iteration_id = ?", mr.ID, mr.IterationID)
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+func (mr *IterationMergeRequest) Create(ctx context.Context, member *team.TeamMember) error {
+	mr.Status = MergeRequestOpen
+	mr.CreatedBy = member.ID
+	return model.DB(ctx).Create(mr).Error
+}
+
+// Finish 合并或关闭请求
+func (mr *IterationMergeRequest) Finish(ctx context.Context, status string, memberID uint) error {
+	now := time.Now()
+	mr.Status = status
+	mr.ClosedBy = memberID
+	mr.ClosedAt = &now
+	return model.DB(ctx).Model(mr).Updates(map[string]interface{}{
+		"status":    status,
+		"closed_by": memberID,
+		"closed_at": now,
+	}).Error
+}
+
+// CreatorInfo 创建人的用户信息
+func (mr *IterationMergeRequest) CreatorInfo(ctx context.Context) (*user.User, error) {
+	return memberUserInfo(ctx, mr.CreatedBy)
+}
+
+// CloserInfo 合并或关闭人的用户信息
+func (mr *IterationMergeRequest) CloserInfo(ctx context.Context) (*user.User, error) {
+	return memberUserInfo(ctx, mr.ClosedBy)
+}
+
+func memberUserInfo(ctx context.Context, memberID uint) (*user.User, error) {
+	tm := &team.TeamMember{}
+	if err := model.DB(ctx).Unscoped().First(tm, memberID).Error; err != nil {
+		return nil, err
+	}
+	return tm.UserInfo(ctx, true)
+}
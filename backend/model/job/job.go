@@ -0,0 +1,211 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TypeTestCaseGenerate = "testcase_generate"
+	TypeProjectExport    = "project_export"
+	TypeProjectTranslate = "project_translate"
+	TypeTestCaseRun      = "testcase_run"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+)
+
+// Job 后台任务
+type Job struct {
+	ID          uint       `gorm:"type:bigint;primaryKey;autoIncrement"`
+	Type        string     `gorm:"type:varchar(64);index;not null;comment:job type"`
+	ProjectID   string     `gorm:"type:varchar(24);index;not null;comment:project id"`
+	TargetID    uint       `gorm:"type:bigint;not null;default:0;comment:target id, e.g. collection id"`
+	MemberID    uint       `gorm:"type:bigint;not null;default:0;comment:created by team member id"`
+	Payload     string     `gorm:"type:mediumtext;comment:job arguments"`
+	Result      string     `gorm:"type:mediumtext;comment:job result"`
+	Status      string     `gorm:"type:varchar(32);index:idx_status_run;not null;comment:pending,running,succeeded,failed,canceled"`
+	Progress    int        `gorm:"type:int(11);not null;default:0;comment:progress percentage"`
+	Attempts    int        `gorm:"type:int(11);not null;default:0;comment:attempt times"`
+	MaxAttempts int        `gorm:"type:int(11);not null;default:1;comment:max attempt times"`
+	Error       string     `gorm:"type:varchar(1024);comment:last error"`
+	RunAt       time.Time  `gorm:"type:datetime;index:idx_status_run;not null;comment:next run time, or lease expiry while running"`
+	StartedAt   *time.Time `gorm:"type:datetime;comment:last started time"`
+	FinishedAt  *time.Time `gorm:"type:datetime;comment:finished time"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Get 获取任务
+func (j *Job) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx)
+	if j.ID != 0 && j.ProjectID != "" {
+		tx = tx.Take(j, "id = ? AND project_id = ?", j.ID, j.ProjectID)
+	} else if j.ID != 0 {
+		tx = tx.Take(j, "id = ?", j.ID)
+	} else {
+		return false, errors.New("query condition error")
+	}
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+// Create 创建任务
+func (j *Job) Create(ctx context.Context) error {
+	if j.Status == "" {
+		j.Status = StatusPending
+	}
+	if j.MaxAttempts <= 0 {
+		j.MaxAttempts = 1
+	}
+	if j.RunAt.IsZero() {
+		j.RunAt = time.Now()
+	}
+	return model.DB(ctx).Create(j).Error
+}
+
+// Finished 任务是否已结束
+func (j *Job) Finished() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed || j.Status == StatusCanceled
+}
+
+// Claim 抢占任务，pending 到期或 running 租约过期(进程异常退出)的任务都可以被抢占
+func (j *Job) Claim(ctx context.Context, lease time.Duration) (bool, error) {
+	now := time.Now()
+	next := now.Add(lease)
+	tx := model.DB(ctx).Model(&Job{}).
+		Where("id = ? AND status IN ? AND run_at = ?", j.ID, []string{StatusPending, StatusRunning}, j.RunAt).
+		Updates(map[string]interface{}{
+			"status":     StatusRunning,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"run_at":     next,
+			"started_at": now,
+		})
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return false, nil
+	}
+	j.Status = StatusRunning
+	j.Attempts++
+	j.RunAt = next
+	j.StartedAt = &now
+	return true, nil
+}
+
+// Heartbeat 延长运行中任务的租约，任务已不在运行中(如已取消)时返回false
+func (j *Job) Heartbeat(ctx context.Context, lease time.Duration) (bool, error) {
+	next := time.Now().Add(lease)
+	tx := model.DB(ctx).Model(&Job{}).
+		Where("id = ? AND status = ?", j.ID, StatusRunning).
+		Update("run_at", next)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return false, nil
+	}
+	j.RunAt = next
+	return true, nil
+}
+
+// UpdateProgress 更新进度
+func (j *Job) UpdateProgress(ctx context.Context, progress int) error {
+	j.Progress = progress
+	return model.DB(ctx).Model(&Job{}).
+		Where("id = ? AND status = ?", j.ID, StatusRunning).
+		Update("progress", progress).Error
+}
+
+// Finish 保存运行结果，只有仍在运行中的任务会被更新，避免覆盖取消状态
+func (j *Job) Finish(ctx context.Context) error {
+	values := map[string]interface{}{
+		"status":   j.Status,
+		"progress": j.Progress,
+		"result":   j.Result,
+		"error":    j.Error,
+		"run_at":   j.RunAt,
+	}
+	if j.Finished() {
+		now := time.Now()
+		j.FinishedAt = &now
+		values["finished_at"] = now
+	}
+	return model.DB(ctx).Model(&Job{}).
+		Where("id = ? AND status = ?", j.ID, StatusRunning).
+		Updates(values).Error
+}
+
+// Cancel 取消未结束的任务
+func (j *Job) Cancel(ctx context.Context) (bool, error) {
+	now := time.Now()
+	tx := model.DB(ctx).Model(&Job{}).
+		Where("id = ? AND status IN ?", j.ID, []string{StatusPending, StatusRunning}).
+		Updates(map[string]interface{}{
+			"status":      StatusCanceled,
+			"finished_at": now,
+		})
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return false, nil
+	}
+	j.Status = StatusCanceled
+	j.FinishedAt = &now
+	return true, nil
+}
+
+// GetJobs 获取项目的任务列表，按时间倒序
+func GetJobs(ctx context.Context, projectID, jobType, status string, limit int) ([]*Job, error) {
+	tx := model.DB(ctx).Omit("result").Where("project_id = ?", projectID)
+	if jobType != "" {
+		tx = tx.Where("type = ?", jobType)
+	}
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+	var list []*Job
+	return list, tx.Order("id desc").Find(&list).Error
+}
+
+// GetActiveJob 获取目标上未结束的任务，用于避免重复创建
+func GetActiveJob(ctx context.Context, projectID, jobType string, targetID uint) (*Job, error) {
+	var j Job
+	tx := model.DB(ctx).Omit("result").
+		Where("project_id = ? AND type = ? AND target_id = ? AND status IN ?", projectID, jobType, targetID, []string{StatusPending, StatusRunning}).
+		Order("id desc").
+		Take(&j)
+	if err := model.NotRecord(tx); err != nil {
+		return nil, err
+	}
+	if tx.Error != nil {
+		return nil, nil
+	}
+	return &j, nil
+}
+
+// GetDueJobs 获取到期待运行的任务，包括租约已过期的运行中任务
+func GetDueJobs(ctx context.Context, limit int) ([]*Job, error) {
+	var list []*Job
+	return list, model.DB(ctx).
+		Omit("result").
+		Where("status IN ? AND run_at <= ?", []string{StatusPending, StatusRunning}, time.Now()).
+		Order("run_at asc").
+		Limit(limit).
+		Find(&list).Error
+}
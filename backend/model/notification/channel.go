@@ -0,0 +1,141 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/apicat/apicat/v2/backend/model"
+)
+
+const (
+	ChannelSlack    = "slack"
+	ChannelDingTalk = "dingtalk"
+	ChannelFeishu   = "feishu"
+	ChannelWeCom    = "wecom"
+	// ChannelWebhook 通用的incoming webhook，直接投递JSON消息
+	ChannelWebhook = "webhook"
+)
+
+var ChannelTypes = []string{
+	ChannelSlack,
+	ChannelDingTalk,
+	ChannelFeishu,
+	ChannelWeCom,
+	ChannelWebhook,
+}
+
+// Channel 项目的聊天通知渠道，Events为空表示订阅全部事件
+type Channel struct {
+	ID        uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	ProjectID string `gorm:"type:varchar(24);index;not null;comment:project id"`
+	Name      string `gorm:"type:varchar(255);not null;comment:channel name"`
+	Type      string `gorm:"type:varchar(32);not null;comment:slack,dingtalk,feishu,wecom,webhook"`
+	URL       string `gorm:"type:varchar(1024);not null;comment:incoming webhook url"`
+	Secret    string `gorm:"type:varchar(255);comment:signing secret"`
+	Events    string `gorm:"type:varchar(1024);not null;comment:subscribed events"`
+	Active    bool   `gorm:"type:tinyint;not null;default:1;comment:is active"`
+	CreatedBy uint   `gorm:"type:bigint;not null;default:0;comment:team member id"`
+	model.TimeModel
+}
+
+// Get 获取通知渠道
+func (c *Channel) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx)
+	if c.ID != 0 && c.ProjectID != "" {
+		tx = tx.Take(c, "id = ? AND project_id = ?", c.ID, c.ProjectID)
+	} else if c.ID != 0 {
+		tx = tx.Take(c, "id = ?", c.ID)
+	} else {
+		return false, errors.New("query condition error")
+	}
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+// Create 创建通知渠道
+func (c *Channel) Create(ctx context.Context) error {
+	return model.DB(ctx).Create(c).Error
+}
+
+// Update 更新通知渠道
+func (c *Channel) Update(ctx context.Context) error {
+	return model.DB(ctx).Model(c).Updates(map[string]interface{}{
+		"name":   c.Name,
+		"type":   c.Type,
+		"url":    c.URL,
+		"secret": c.Secret,
+		"events": c.Events,
+		"active": c.Active,
+	}).Error
+}
+
+// Delete 删除通知渠道
+func (c *Channel) Delete(ctx context.Context) error {
+	return model.DB(ctx).Delete(c).Error
+}
+
+// GetEvents 获取订阅的事件
+func (c *Channel) GetEvents() []string {
+	list := make([]string, 0)
+	if c.Events == "" {
+		return list
+	}
+	if err := json.Unmarshal([]byte(c.Events), &list); err != nil {
+		return make([]string, 0)
+	}
+	return list
+}
+
+// SetEvents 设置订阅的事件
+func (c *Channel) SetEvents(events []string) {
+	if events == nil {
+		events = make([]string, 0)
+	}
+	b, _ := json.Marshal(events)
+	c.Events = string(b)
+}
+
+// Subscribed 是否订阅了某个事件
+func (c *Channel) Subscribed(event string) bool {
+	events := c.GetEvents()
+	if len(events) == 0 {
+		return true
+	}
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidChannelType(typ string) bool {
+	for _, t := range ChannelTypes {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// GetChannels 获取项目的通知渠道列表
+func GetChannels(ctx context.Context, projectID string) ([]*Channel, error) {
+	var list []*Channel
+	return list, model.DB(ctx).Where("project_id = ?", projectID).Order("id asc").Find(&list).Error
+}
+
+// GetSubscribedChannels 获取项目中订阅了某个事件且启用的通知渠道
+func GetSubscribedChannels(ctx context.Context, projectID, event string) ([]*Channel, error) {
+	var list []*Channel
+	if err := model.DB(ctx).Where("project_id = ? AND active = ?", projectID, true).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	res := make([]*Channel, 0, len(list))
+	for _, c := range list {
+		if c.Subscribed(event) {
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
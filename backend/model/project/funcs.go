@@ -2,6 +2,7 @@ package project
 
 import (
 	"context"
+	"time"
 
 	"github.com/apicat/apicat/v2/backend/model"
 	"github.com/apicat/apicat/v2/backend/model/team"
@@ -55,7 +56,11 @@ func TransferProject(ctx context.Context, p *Project, originMember, targetMember
 
 			// 修改项目新拥有者为管理者
 			targetMember.Permission = ProjectMemberManage
-			if err := tx.Model(targetMember).Update("permission", ProjectMemberManage).Error; err != nil {
+			targetMember.RoleID = 0
+			if err := tx.Model(targetMember).Updates(map[string]interface{}{
+				"permission": ProjectMemberManage,
+				"role_id":    0,
+			}).Error; err != nil {
 				return err
 			}
 
@@ -66,12 +71,13 @@ func TransferProject(ctx context.Context, p *Project, originMember, targetMember
 }
 
 // BatchCreateMember 批量创建项目成员
-func BatchCreateMember(ctx context.Context, projectID string, members []*team.TeamMember, permission Permission) {
+func BatchCreateMember(ctx context.Context, projectID string, members []*team.TeamMember, permission Permission, roleID uint) {
 	for _, member := range members {
 		pm := &ProjectMember{
 			ProjectID:  projectID,
 			MemberID:   member.ID,
 			Permission: permission,
+			RoleID:     roleID,
 		}
 		pm.Create(ctx, nil)
 	}
@@ -221,3 +227,30 @@ func ExportServers(ctx context.Context, projectID string) []spec.Server {
 
 	return specServers
 }
+
+// GetDeletedProjects 获取团队回收站中的项目，since不为零值时只返回该时间之后删除的
+// memberID不为0时只返回该成员删除或负责的项目
+func GetDeletedProjects(ctx context.Context, teamID string, since time.Time, memberID uint) ([]*Project, error) {
+	var list []*Project
+	tx := model.DB(ctx).Unscoped().Where("team_id = ? AND deleted_at IS NOT NULL", teamID)
+	if !since.IsZero() {
+		tx = tx.Where("deleted_at >= ?", since)
+	}
+	if memberID != 0 {
+		tx = tx.Where("deleted_by = ? OR member_id = ?", memberID, memberID)
+	}
+	return list, tx.Order("deleted_at desc").Find(&list).Error
+}
+
+// GetDeletedProjectsByIDs 通过ID获取团队回收站中的项目
+func GetDeletedProjectsByIDs(ctx context.Context, teamID string, ids []string) ([]*Project, error) {
+	var list []*Project
+	tx := model.DB(ctx).Unscoped().Where("id IN ? AND team_id = ? AND deleted_at IS NOT NULL", ids, teamID)
+	return list, tx.Find(&list).Error
+}
+
+// GetExpiredDeletedProjects 获取before之前删除的项目，用于彻底删除
+func GetExpiredDeletedProjects(ctx context.Context, before time.Time) ([]*Project, error) {
+	var list []*Project
+	return list, model.DB(ctx).Unscoped().Where("deleted_at < ?", before).Find(&list).Error
+}
@@ -0,0 +1,55 @@
+package project
+
+import (
+	"context"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+)
+
+// HistoryRetention 项目历史记录的保留策略，各项为0表示不限制
+type HistoryRetention struct {
+	ID             uint       `gorm:"type:bigint;primaryKey;autoIncrement"`
+	ProjectID      string     `gorm:"type:varchar(24);uniqueIndex;not null;comment:project id"`
+	KeepCount      int        `gorm:"type:int;not null;default:0;comment:keep the latest n histories of each doc, 0 means unlimited"`
+	KeepDays       int        `gorm:"type:int;not null;default:0;comment:keep histories for n days, 0 means unlimited"`
+	DailyAfterDays int        `gorm:"type:int;not null;default:0;comment:keep only daily snapshots for histories older than n days, 0 means disabled"`
+	DeltaStorage   bool       `gorm:"type:tinyint;not null;default:0;comment:store histories as deltas instead of full copies"`
+	CompactedAt    *time.Time `gorm:"type:datetime;comment:last compaction time"`
+	UpdatedBy      uint       `gorm:"type:bigint;not null;default:0;comment:updated by member id"`
+	model.TimeModel
+}
+
+// Get 获取项目的保留策略
+func (hr *HistoryRetention) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx).Take(hr, "project_id = ?", hr.ProjectID)
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+// Save 保存项目的保留策略，不存在时创建
+func (hr *HistoryRetention) Save(ctx context.Context, memberID uint) error {
+	hr.UpdatedBy = memberID
+	if hr.ID == 0 {
+		return model.DB(ctx).Create(hr).Error
+	}
+	return model.DB(ctx).Model(hr).Updates(map[string]interface{}{
+		"keep_count":       hr.KeepCount,
+		"keep_days":        hr.KeepDays,
+		"daily_after_days": hr.DailyAfterDays,
+		"delta_storage":    hr.DeltaStorage,
+		"updated_by":       memberID,
+	}).Error
+}
+
+// Compacted 记录压缩完成时间
+func (hr *HistoryRetention) Compacted(ctx context.Context, t time.Time) error {
+	hr.CompactedAt = &t
+	return model.DB(ctx).Model(hr).UpdateColumn("compacted_at", t).Error
+}
+
+// GetHistoryRetentions 获取所有设置了保留策略的项目
+func GetHistoryRetentions(ctx context.Context) ([]*HistoryRetention, error) {
+	var list []*HistoryRetention
+	return list, model.DB(ctx).Order("id asc").Find(&list).Error
+}
@@ -3,6 +3,7 @@ package project
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"time"
 
 	"github.com/apicat/apicat/v2/backend/model"
@@ -27,6 +28,7 @@ type ProjectMember struct {
 	MemberID   uint       `gorm:"type:bigint;uniqueIndex:ukey;not null;comment:team member id"`
 	GroupID    uint       `gorm:"type:bigint;not null;default:0;comment:group id"`
 	Permission Permission `gorm:"type:varchar(255);not null;comment:project permission:manage,write,read"`
+	RoleID     uint       `gorm:"type:bigint;not null;default:0;comment:custom role id"`
 	FollowedAt *time.Time `gorm:"type:datetime;comment:follow the project timeline"` // 不为空表示关注，字段类型为指针是为了在取消关注时，可以设置为null
 	model.TimeModel
 }
@@ -58,6 +60,53 @@ func (p Permission) LowerOrEqual(other Permission) bool {
 	return permissionRanking[p] <= permissionRanking[other]
 }
 
+// GetCapabilities 获取成员拥有的能力
+// 管理者和没有分配自定义角色的可写成员拥有全部能力，分配了自定义角色的成员只拥有角色包含的能力
+// 角色不存在时不授予任何能力，避免失效的角色变成全部能力
+func (pm *ProjectMember) GetCapabilities(ctx context.Context) ([]Capability, error) {
+	if pm.Permission.Lower(ProjectMemberWrite) {
+		return make([]Capability, 0), nil
+	}
+	if pm.Permission.Equal(ProjectMemberManage) || pm.RoleID == 0 {
+		return Capabilities, nil
+	}
+
+	// 只使用项目所属团队的角色
+	p := &Project{ID: pm.ProjectID}
+	exist, err := p.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return make([]Capability, 0), nil
+	}
+
+	r := &CustomRole{ID: pm.RoleID, TeamID: p.TeamID}
+	exist, err = r.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		slog.WarnContext(ctx, "ProjectMember.GetCapabilities.RoleNotFound", "projectMemberID", pm.ID, "roleID", pm.RoleID)
+		return make([]Capability, 0), nil
+	}
+	return r.GetCapabilities(), nil
+}
+
+// HasCapability 成员是否拥有某项能力
+func (pm *ProjectMember) HasCapability(ctx context.Context, c Capability) (bool, error) {
+	caps, err := pm.GetCapabilities(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range caps {
+		if v == c {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Get 获取项目成员
 func (pm *ProjectMember) Get(ctx context.Context) (bool, error) {
 	tx := model.DB(ctx)
@@ -111,6 +160,7 @@ func (pm *ProjectMember) Create(ctx context.Context, tx *gorm.DB) (*ProjectMembe
 		// 如果存在但已经被删除则恢复
 		return projectMember, tx.Unscoped().Model(&projectMember).Updates(map[string]interface{}{
 			"permission": pm.Permission,
+			"role_id":    pm.RoleID,
 			"created_at": time.Now(),
 			"updated_at": time.Now(),
 			"deleted_at": nil,
@@ -123,14 +173,15 @@ func (pm *ProjectMember) Create(ctx context.Context, tx *gorm.DB) (*ProjectMembe
 	}
 }
 
-// Update 更新项目成员，目前只能更新permission和groupID
+// Update 更新项目成员，目前只能更新permission、roleID和groupID
 func (pm *ProjectMember) Update(ctx context.Context) error {
 	if pm.ID == 0 {
 		return nil
 	}
-	// 只能更新permission和groupID
+	// 只能更新permission、roleID和groupID
 	return model.DB(ctx).Model(&pm).Updates(map[string]interface{}{
 		"permission": pm.Permission,
+		"role_id":    pm.RoleID,
 		"group_id":   pm.GroupID,
 	}).Error
 }
@@ -2,6 +2,7 @@ package project
 
 import (
 	"context"
+	"time"
 
 	"github.com/apicat/apicat/v2/backend/model"
 	"github.com/apicat/apicat/v2/backend/model/team"
@@ -24,6 +25,7 @@ type Project struct {
 	ShareKey    string `gorm:"type:varchar(255);comment:project share key"`
 	Description string `gorm:"type:varchar(255);comment:project description"`
 	Cover       string `gorm:"type:varchar(255);comment:project cover"`
+	DeletedBy   uint   `gorm:"type:bigint;default:null;comment:deleted by member id"`
 	model.TimeModel
 }
 
@@ -83,17 +85,47 @@ func (p *Project) UpdateShareKey(ctx context.Context) error {
 	return model.DB(ctx).Model(p).Update("share_key", p.ShareKey).Error
 }
 
-// Delete 删除项目
-func (p *Project) Delete(ctx context.Context) error {
+// Delete 删除项目，项目和成员使用相同的删除时间，以便从回收站恢复时一并恢复成员
+func (p *Project) Delete(ctx context.Context, memberID uint) error {
 	if p.ID == "" {
 		return nil
 	}
+	now := time.Now()
 	return model.DB(ctx).Transaction(
 		func(tx *gorm.DB) error {
-			if err := tx.Where("project_id = ?", p.ID).Delete(&ProjectMember{}).Error; err != nil {
+			if err := tx.Model(&ProjectMember{}).Where("project_id = ?", p.ID).Update("deleted_at", now).Error; err != nil {
 				return err
 			}
-			return tx.Delete(p).Error
+			return tx.Model(p).Updates(map[string]interface{}{
+				"deleted_by": memberID,
+				"deleted_at": now,
+			}).Error
+		},
+	)
+}
+
+// Restore 从回收站恢复项目，同时恢复删除项目时一并删除的成员
+func (p *Project) Restore(ctx context.Context) error {
+	if !p.DeletedAt.Valid {
+		return nil
+	}
+	deletedAt := p.DeletedAt.Time
+	return model.DB(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Model(&ProjectMember{}).
+				Where("project_id = ? AND deleted_at = ?", p.ID, deletedAt).
+				Update("deleted_at", gorm.Expr("NULL")).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Model(p).Updates(map[string]interface{}{
+				"deleted_by": gorm.Expr("NULL"),
+				"deleted_at": gorm.Expr("NULL"),
+			}).Error; err != nil {
+				return err
+			}
+			p.DeletedBy = 0
+			p.DeletedAt = gorm.DeletedAt{}
+			return nil
 		},
 	)
 }
@@ -0,0 +1,145 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/apicat/apicat/v2/backend/model"
+
+	"gorm.io/gorm"
+)
+
+type Capability string
+
+const (
+	CapabilityEditCollection  Capability = "edit_collection"
+	CapabilityEditSchema      Capability = "edit_schema"
+	CapabilityManageShare     Capability = "manage_share"
+	CapabilityAIGenerate      Capability = "ai_generate"
+	CapabilityExport          Capability = "export"
+	CapabilityManageIteration Capability = "manage_iteration"
+	CapabilityDelete          Capability = "delete"
+	CapabilityRunTest         Capability = "run_test"
+)
+
+var Capabilities = []Capability{
+	CapabilityEditCollection,
+	CapabilityEditSchema,
+	CapabilityManageShare,
+	CapabilityAIGenerate,
+	CapabilityExport,
+	CapabilityManageIteration,
+	CapabilityDelete,
+	CapabilityRunTest,
+}
+
+// CustomRole 团队自定义角色，分配给项目成员后，成员的写权限仅限于角色包含的能力
+type CustomRole struct {
+	ID           uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	TeamID       string `gorm:"type:varchar(24);index;not null;comment:team id"`
+	Name         string `gorm:"type:varchar(255);not null;comment:role name"`
+	Description  string `gorm:"type:varchar(255);comment:role description"`
+	Capabilities string `gorm:"type:varchar(1024);not null;comment:role capabilities"`
+	model.TimeModel
+}
+
+// Get 获取自定义角色
+func (r *CustomRole) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx)
+	if r.ID != 0 && r.TeamID != "" {
+		tx = tx.Take(r, "id = ? AND team_id = ?", r.ID, r.TeamID)
+	} else if r.ID != 0 {
+		tx = tx.Take(r, "id = ?", r.ID)
+	} else {
+		return false, errors.New("query condition error")
+	}
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+// Create 创建自定义角色
+func (r *CustomRole) Create(ctx context.Context) error {
+	return model.DB(ctx).Create(r).Error
+}
+
+// CheckRepeat 检查同一团队内是否有重名角色，r.ID不为0时排除自身
+func (r *CustomRole) CheckRepeat(ctx context.Context) (bool, error) {
+	var count int64
+	tx := model.DB(ctx).Model(&CustomRole{}).Where("team_id = ? AND name = ?", r.TeamID, r.Name)
+	if r.ID != 0 {
+		tx = tx.Where("id != ?", r.ID)
+	}
+	err := tx.Count(&count).Error
+	return count > 0, err
+}
+
+// Update 更新自定义角色
+func (r *CustomRole) Update(ctx context.Context) error {
+	return model.DB(ctx).Model(r).Updates(map[string]interface{}{
+		"name":         r.Name,
+		"description":  r.Description,
+		"capabilities": r.Capabilities,
+	}).Error
+}
+
+// Delete 删除自定义角色，使用该角色的项目成员恢复为普通写权限
+func (r *CustomRole) Delete(ctx context.Context) error {
+	return model.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&ProjectMember{}).Where("role_id = ?", r.ID).Update("role_id", 0).Error; err != nil {
+			return err
+		}
+		return tx.Delete(r).Error
+	})
+}
+
+// GetCapabilities 获取角色包含的能力
+func (r *CustomRole) GetCapabilities() []Capability {
+	list := make([]Capability, 0)
+	if r.Capabilities == "" {
+		return list
+	}
+	if err := json.Unmarshal([]byte(r.Capabilities), &list); err != nil {
+		return make([]Capability, 0)
+	}
+	return list
+}
+
+// SetCapabilities 设置角色包含的能力，忽略不支持的能力
+func (r *CustomRole) SetCapabilities(caps []Capability) {
+	list := make([]Capability, 0, len(caps))
+	seen := make(map[Capability]bool)
+	for _, c := range caps {
+		if c.Valid() && !seen[c] {
+			seen[c] = true
+			list = append(list, c)
+		}
+	}
+	b, _ := json.Marshal(list)
+	r.Capabilities = string(b)
+}
+
+// Has 角色是否包含某项能力
+func (r *CustomRole) Has(c Capability) bool {
+	for _, v := range r.GetCapabilities() {
+		if v == c {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Capability) Valid() bool {
+	for _, v := range Capabilities {
+		if v == c {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCustomRoles 获取团队的自定义角色
+func GetCustomRoles(ctx context.Context, teamID string) ([]*CustomRole, error) {
+	var list []*CustomRole
+	return list, model.DB(ctx).Where("team_id = ?", teamID).Order("id asc").Find(&list).Error
+}
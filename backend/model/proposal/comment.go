@@ -0,0 +1,34 @@
+package proposal
+
+import (
+	"context"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/user"
+)
+
+// ProposalComment 审核人或提交人对提案的评论，驳回理由也作为评论保存
+type ProposalComment struct {
+	ID         uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	ProposalID uint   `gorm:"type:bigint;index;not null;comment:proposal id"`
+	Content    string `gorm:"type:text;not null;comment:comment content"`
+	CreatedBy  uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+	model.TimeModel
+}
+
+func (pc *ProposalComment) Create(ctx context.Context, memberID uint) error {
+	pc.CreatedBy = memberID
+	return model.DB(ctx).Create(pc).Error
+}
+
+// CreatorInfo 评论人的用户信息
+func (pc *ProposalComment) CreatorInfo(ctx context.Context) (*user.User, error) {
+	return memberUserInfo(ctx, pc.CreatedBy)
+}
+
+// GetComments 获取提案的评论，按时间正序
+func GetComments(ctx context.Context, proposalID uint) ([]*ProposalComment, error) {
+	var list []*ProposalComment
+	tx := model.DB(ctx).Where("proposal_id = ?", proposalID).Order("id asc")
+	return list, tx.Find(&list).Error
+}
@@ -0,0 +1,130 @@
+package proposal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/team"
+	"github.com/apicat/apicat/v2/backend/model/user"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusWithdrawn = "withdrawn"
+)
+
+// ErrNotPending 提案已被批准、驳回或撤回
+var ErrNotPending = errors.New("proposal is not pending")
+
+// Proposal 对受保护的接口或模型的修改提案，审批通过后才写入
+// Base开头的字段为提交时的内容，审批时与当前内容不一致说明提案已过期
+// Title对于模型是名称，Description只用于模型
+type Proposal struct {
+	ID              uint       `gorm:"type:bigint;primaryKey;autoIncrement"`
+	ProjectID       string     `gorm:"type:varchar(24);index;not null;comment:project id"`
+	TargetType      string     `gorm:"type:varchar(32);not null;comment:target type:collection,schema"`
+	TargetID        uint       `gorm:"type:bigint;not null;comment:collection or schema id"`
+	BaseTitle       string     `gorm:"type:varchar(255);comment:title or name when submitted"`
+	BaseDescription string     `gorm:"type:varchar(255);comment:schema description when submitted"`
+	BaseContent     string     `gorm:"type:mediumtext;comment:content or schema when submitted"`
+	Title           string     `gorm:"type:varchar(255);not null;comment:proposed title or name"`
+	Description     string     `gorm:"type:varchar(255);comment:proposed schema description"`
+	Content         string     `gorm:"type:mediumtext;comment:proposed content or schema"`
+	Status          string     `gorm:"type:varchar(32);index;not null;comment:status:pending,approved,rejected,withdrawn"`
+	CreatedBy       uint       `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+	ClosedBy        uint       `gorm:"type:bigint;not null;default:0;comment:approved, rejected or withdrawn by member id"`
+	ClosedAt        *time.Time `gorm:"type:datetime;comment:approved, rejected or withdrawn time"`
+	model.TimeModel
+}
+
+func (p *Proposal) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx).Take(p, "id = ? AND project_id = ?", p.ID, p.ProjectID)
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+func (p *Proposal) Create(ctx context.Context, member *team.TeamMember) error {
+	p.Status = StatusPending
+	p.CreatedBy = member.ID
+	return model.DB(ctx).Create(p).Error
+}
+
+// Update 提交人在审批前继续编辑，使用新提案的内容和基准
+func (p *Proposal) Update(ctx context.Context, np *Proposal) error {
+	p.BaseTitle, p.BaseDescription, p.BaseContent = np.BaseTitle, np.BaseDescription, np.BaseContent
+	p.Title, p.Description, p.Content = np.Title, np.Description, np.Content
+	return model.DB(ctx).Model(p).Updates(map[string]interface{}{
+		"base_title":       p.BaseTitle,
+		"base_description": p.BaseDescription,
+		"base_content":     p.BaseContent,
+		"title":            p.Title,
+		"description":      p.Description,
+		"content":          p.Content,
+	}).Error
+}
+
+// Finish 审批或撤回提案，只更新待审批的提案，并发处理时后到的返回ErrNotPending
+func (p *Proposal) Finish(ctx context.Context, status string, memberID uint) error {
+	now := time.Now()
+	tx := model.DB(ctx).Model(&Proposal{}).Where("id = ? AND status = ?", p.ID, StatusPending).Updates(map[string]interface{}{
+		"status":    status,
+		"closed_by": memberID,
+		"closed_at": now,
+	})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return ErrNotPending
+	}
+	p.Status = status
+	p.ClosedBy = memberID
+	p.ClosedAt = &now
+	return nil
+}
+
+// CreatorInfo 提交人的用户信息
+func (p *Proposal) CreatorInfo(ctx context.Context) (*user.User, error) {
+	return memberUserInfo(ctx, p.CreatedBy)
+}
+
+// CloserInfo 审批或撤回人的用户信息
+func (p *Proposal) CloserInfo(ctx context.Context) (*user.User, error) {
+	return memberUserInfo(ctx, p.ClosedBy)
+}
+
+// GetProposals 获取项目的修改提案，不包含内容，status为空时返回全部
+func GetProposals(ctx context.Context, projectID, status string) ([]*Proposal, error) {
+	var list []*Proposal
+	tx := model.DB(ctx).Omit("base_content", "content").Where("project_id = ?", projectID)
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+	return list, tx.Order("id desc").Find(&list).Error
+}
+
+// GetPendingProposal 成员对接口或模型待审批的提案，不存在时返回nil
+func GetPendingProposal(ctx context.Context, projectID, targetType string, targetID, memberID uint) (*Proposal, error) {
+	p := &Proposal{}
+	tx := model.DB(ctx).Where("project_id = ? AND target_type = ? AND target_id = ? AND created_by = ? AND status = ?",
+		projectID, targetType, targetID, memberID, StatusPending).Order("id desc").Take(p)
+	if err := model.NotRecord(tx); err != nil {
+		return nil, err
+	}
+	if tx.Error != nil {
+		return nil, nil
+	}
+	return p, nil
+}
+
+func memberUserInfo(ctx context.Context, memberID uint) (*user.User, error) {
+	tm := &team.TeamMember{}
+	if err := model.DB(ctx).Unscoped().First(tm, memberID).Error; err != nil {
+		return nil, err
+	}
+	return tm.UserInfo(ctx, true)
+}
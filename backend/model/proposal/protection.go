@@ -0,0 +1,68 @@
+package proposal
+
+import (
+	"context"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/user"
+)
+
+const (
+	TargetCollection = "collection"
+	TargetSchema     = "schema"
+)
+
+// Protection 需要审批才能修改的接口或模型
+type Protection struct {
+	ID         uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	ProjectID  string `gorm:"type:varchar(24);uniqueIndex:ukey;not null;comment:project id"`
+	TargetType string `gorm:"type:varchar(32);uniqueIndex:ukey;not null;comment:target type:collection,schema"`
+	TargetID   uint   `gorm:"type:bigint;uniqueIndex:ukey;not null;comment:collection or schema id"`
+	CreatedBy  uint   `gorm:"type:bigint;not null;default:0;comment:created by member id"`
+	model.TimeModel
+}
+
+func (p *Protection) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx).Take(p, "id = ? AND project_id = ?", p.ID, p.ProjectID)
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+func (p *Protection) Create(ctx context.Context, memberID uint) error {
+	p.CreatedBy = memberID
+	return model.DB(ctx).Create(p).Error
+}
+
+// Delete 取消保护，直接删除以便再次添加
+func (p *Protection) Delete(ctx context.Context) error {
+	return model.DB(ctx).Unscoped().Delete(p).Error
+}
+
+// CreatorInfo 设置人的用户信息
+func (p *Protection) CreatorInfo(ctx context.Context) (*user.User, error) {
+	return memberUserInfo(ctx, p.CreatedBy)
+}
+
+// IsProtected 接口或模型是否需要审批才能修改
+func IsProtected(ctx context.Context, projectID, targetType string, targetID uint) (bool, error) {
+	return AnyProtected(ctx, projectID, targetType, []uint{targetID})
+}
+
+// AnyProtected 多个接口或模型中是否有需要审批才能修改的
+func AnyProtected(ctx context.Context, projectID, targetType string, targetIDs []uint) (bool, error) {
+	if len(targetIDs) == 0 {
+		return false, nil
+	}
+	var count int64
+	err := model.DB(ctx).Model(&Protection{}).
+		Where("project_id = ? AND target_type = ? AND target_id IN ?", projectID, targetType, targetIDs).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetProtections 获取项目中需要审批的接口和模型
+func GetProtections(ctx context.Context, projectID string) ([]*Protection, error) {
+	var list []*Protection
+	tx := model.DB(ctx).Where("project_id = ?", projectID).Order("id desc")
+	return list, tx.Find(&list).Error
+}
@@ -0,0 +1,47 @@
+package referencerelation
+
+import (
+	"context"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+)
+
+const (
+	SnapshotCollection = "collection"
+	SnapshotSchema     = "schema"
+	SnapshotResponse   = "response"
+)
+
+// DerefSnapshot 删除公共模型或公共响应时被解引用条目的原内容，从回收站恢复时用于还原引用
+type DerefSnapshot struct {
+	ID         uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	RefType    string `gorm:"type:varchar(32);index:idx_ref;not null;comment:schema,response"`
+	RefID      uint   `gorm:"type:bigint;index:idx_ref;not null;comment:deleted definition schema or response id"`
+	TargetType string `gorm:"type:varchar(32);not null;comment:collection,schema,response"`
+	TargetID   uint   `gorm:"type:bigint;not null;comment:dereferenced collection, schema or response id"`
+	Before     string `gorm:"type:mediumtext;comment:content before deref"`
+	After      string `gorm:"type:mediumtext;comment:content after deref"`
+	CreatedAt  time.Time
+}
+
+func BatchCreateDerefSnapshots(ctx context.Context, list []*DerefSnapshot) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return model.DB(ctx).Create(list).Error
+}
+
+// GetDerefSnapshots 获取删除公共模型或公共响应时记录的解引用快照
+func GetDerefSnapshots(ctx context.Context, refType string, refID uint) ([]*DerefSnapshot, error) {
+	var list []*DerefSnapshot
+	tx := model.DB(ctx).Where("ref_type = ? AND ref_id = ?", refType, refID).Order("id asc").Find(&list)
+	return list, tx.Error
+}
+
+func DelDerefSnapshots(ctx context.Context, refType string, refIDs ...uint) error {
+	if len(refIDs) == 0 {
+		return nil
+	}
+	return model.DB(ctx).Where("ref_type = ? AND ref_id IN ?", refType, refIDs).Delete(&DerefSnapshot{}).Error
+}
@@ -0,0 +1,80 @@
+package release
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/team"
+	"github.com/apicat/apicat/v2/backend/model/user"
+	"github.com/apicat/apicat/v2/backend/module/spec"
+)
+
+// Release 项目的发布版本，保存发布时解引用后的完整spec，创建后内容不可修改
+type Release struct {
+	ID          uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	ProjectID   string `gorm:"type:varchar(24);uniqueIndex:ukey;not null;comment:project id"`
+	Version     string `gorm:"type:varchar(64);uniqueIndex:ukey;not null;comment:release version"`
+	Description string `gorm:"type:text;comment:release notes"`
+	Spec        string `gorm:"type:longtext;comment:frozen spec content"`
+	CreatedBy   uint   `gorm:"type:bigint;not null;default:0;comment:team member id"`
+	model.TimeModel
+}
+
+// Get 获取发布版本，按ID或版本号查询
+func (r *Release) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx)
+	if r.ID != 0 && r.ProjectID != "" {
+		tx = tx.Take(r, "id = ? AND project_id = ?", r.ID, r.ProjectID)
+	} else if r.ProjectID != "" && r.Version != "" {
+		tx = tx.Take(r, "project_id = ? AND version = ?", r.ProjectID, r.Version)
+	} else {
+		return false, errors.New("query condition error")
+	}
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+// Create 创建发布版本
+func (r *Release) Create(ctx context.Context, s *spec.Spec) error {
+	content, err := s.ToJSON(spec.JSONOption{})
+	if err != nil {
+		return err
+	}
+	r.Spec = string(content)
+	return model.DB(ctx).Create(r).Error
+}
+
+// VersionExists 版本号是否已被使用，包含已删除的版本，版本号不能重复使用
+func (r *Release) VersionExists(ctx context.Context) (bool, error) {
+	var count int64
+	err := model.DB(ctx).Unscoped().Model(&Release{}).
+		Where("project_id = ? AND version = ?", r.ProjectID, r.Version).Count(&count).Error
+	return count > 0, err
+}
+
+// Delete 删除发布版本，保留记录以免版本号被重新使用
+func (r *Release) Delete(ctx context.Context) error {
+	return model.DB(ctx).Delete(r).Error
+}
+
+// ToSpec 发布时保存的spec
+func (r *Release) ToSpec() (*spec.Spec, error) {
+	return spec.NewSpecFromJson([]byte(r.Spec))
+}
+
+// CreatorInfo 发布人的用户信息
+func (r *Release) CreatorInfo(ctx context.Context) (*user.User, error) {
+	tm := &team.TeamMember{}
+	if err := model.DB(ctx).Unscoped().First(tm, r.CreatedBy).Error; err != nil {
+		return nil, err
+	}
+	return tm.UserInfo(ctx, true)
+}
+
+// GetReleases 获取项目的发布版本列表，不包含spec内容
+func GetReleases(ctx context.Context, projectID string) ([]*Release, error) {
+	var list []*Release
+	tx := model.DB(ctx).Omit("spec").Where("project_id = ?", projectID).Order("id desc")
+	return list, tx.Find(&list).Error
+}
@@ -152,6 +152,21 @@ func initModelConfig() {
 				cfg.Driver = llm.AZUREOPENAI
 				config.SetLLM(&cfg)
 			}
+		case llm.OPENAICOMPATIBLE:
+			if err := json.Unmarshal([]byte(r.Config), &cfg.OpenAICompatible); err == nil {
+				cfg.Driver = llm.OPENAICOMPATIBLE
+				config.SetLLM(&cfg)
+			}
+		case llm.OLLAMA:
+			if err := json.Unmarshal([]byte(r.Config), &cfg.Ollama); err == nil {
+				cfg.Driver = llm.OLLAMA
+				config.SetLLM(&cfg)
+			}
+		case llm.ANTHROPIC:
+			if err := json.Unmarshal([]byte(r.Config), &cfg.Anthropic); err == nil {
+				cfg.Driver = llm.ANTHROPIC
+				config.SetLLM(&cfg)
+			}
 		}
 	}
 }
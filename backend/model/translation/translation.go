@@ -0,0 +1,98 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+)
+
+const (
+	TargetCollection = "collection"
+	TargetSchema     = "schema"
+)
+
+// Translation 接口和模型的译文，按语言保存一份完整的副本
+type Translation struct {
+	ID          uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	ProjectID   string `gorm:"type:varchar(24);index;not null;comment:project id"`
+	TargetType  string `gorm:"type:varchar(32);uniqueIndex:ukey;not null;comment:collection,schema"`
+	TargetID    uint   `gorm:"type:bigint;uniqueIndex:ukey;not null;comment:collection or schema id"`
+	Locale      string `gorm:"type:varchar(16);uniqueIndex:ukey;not null;comment:zh-CN,en-US"`
+	Title       string `gorm:"type:varchar(255);comment:translated collection title"`
+	Description string `gorm:"type:text;comment:translated schema description"`
+	Content     string `gorm:"type:mediumtext;comment:translated collection content or schema"`
+	SourceHash  string `gorm:"type:varchar(64);not null;comment:sha256 of the translated source"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Get 按目标和语言获取译文
+func (t *Translation) Get(ctx context.Context) (bool, error) {
+	if t.TargetType == "" || t.TargetID == 0 || t.Locale == "" {
+		return false, errors.New("query condition error")
+	}
+	tx := model.DB(ctx).Take(t, "target_type = ? AND target_id = ? AND locale = ?", t.TargetType, t.TargetID, t.Locale)
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+// Save 保存译文，已存在时覆盖
+func (t *Translation) Save(ctx context.Context) error {
+	old := &Translation{TargetType: t.TargetType, TargetID: t.TargetID, Locale: t.Locale}
+	exist, err := old.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return model.DB(ctx).Create(t).Error
+	}
+
+	t.ID = old.ID
+	return model.DB(ctx).Model(old).Updates(map[string]interface{}{
+		"project_id":  t.ProjectID,
+		"title":       t.Title,
+		"description": t.Description,
+		"content":     t.Content,
+		"source_hash": t.SourceHash,
+	}).Error
+}
+
+// GetSourceHashes 获取目标在指定语言下已有译文的源内容摘要，用于跳过未变化的内容
+func GetSourceHashes(ctx context.Context, projectID, targetType, locale string) (map[uint]string, error) {
+	var list []*Translation
+	if err := model.DB(ctx).Select("target_id", "source_hash").
+		Where("project_id = ? AND target_type = ? AND locale = ?", projectID, targetType, locale).
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	res := make(map[uint]string, len(list))
+	for _, t := range list {
+		res[t.TargetID] = t.SourceHash
+	}
+	return res, nil
+}
+
+// DeleteOrphanTranslations 删除目标已被彻底删除的译文，回收站中的目标保留以便恢复后继续使用
+func DeleteOrphanTranslations(ctx context.Context) (int64, error) {
+	var total int64
+	targets := map[string]interface{}{
+		TargetCollection: &collection.Collection{},
+		TargetSchema:     &definition.DefinitionSchema{},
+	}
+	for targetType, m := range targets {
+		sub := model.DB(ctx).Unscoped().Model(m).Select("id")
+		tx := model.DB(ctx).
+			Where("target_type = ? AND target_id NOT IN (?)", targetType, sub).
+			Delete(&Translation{})
+		if tx.Error != nil {
+			return total, tx.Error
+		}
+		total += tx.RowsAffected
+	}
+	return total, nil
+}
@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+)
+
+const (
+	DeliveryPending = "pending"
+	DeliverySuccess = "success"
+	DeliveryFailed  = "failed"
+)
+
+// Delivery webhook投递记录
+type Delivery struct {
+	ID             uint      `gorm:"type:bigint;primaryKey;autoIncrement"`
+	WebhookID      uint      `gorm:"type:bigint;index;not null;comment:webhook id"`
+	ProjectID      string    `gorm:"type:varchar(24);index;not null;comment:project id"`
+	Event          string    `gorm:"type:varchar(64);not null;comment:event name"`
+	Payload        string    `gorm:"type:mediumtext;comment:request body"`
+	Status         string    `gorm:"type:varchar(32);index:idx_status_next;not null;comment:pending,success,failed"`
+	Attempts       int       `gorm:"type:int(11);not null;default:0;comment:attempt times"`
+	NextAttemptAt  time.Time `gorm:"type:datetime;index:idx_status_next;not null;comment:next attempt time"`
+	ResponseStatus int       `gorm:"type:int(11);not null;default:0;comment:last response status code"`
+	ResponseBody   string    `gorm:"type:text;comment:last response body"`
+	Error          string    `gorm:"type:varchar(1024);comment:last error"`
+	Duration       int64     `gorm:"type:bigint;not null;default:0;comment:last request duration in milliseconds"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Get 获取投递记录
+func (d *Delivery) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx)
+	if d.ID != 0 && d.WebhookID != 0 {
+		tx = tx.Take(d, "id = ? AND webhook_id = ?", d.ID, d.WebhookID)
+	} else if d.ID != 0 {
+		tx = tx.Take(d, "id = ?", d.ID)
+	} else {
+		return false, errors.New("query condition error")
+	}
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+// Create 创建投递记录
+func (d *Delivery) Create(ctx context.Context) error {
+	if d.Status == "" {
+		d.Status = DeliveryPending
+	}
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = time.Now()
+	}
+	return model.DB(ctx).Create(d).Error
+}
+
+// Claim 抢占一次投递，lease时间内其他worker不会再处理该记录
+func (d *Delivery) Claim(ctx context.Context, lease time.Duration) (bool, error) {
+	next := time.Now().Add(lease)
+	tx := model.DB(ctx).Model(&Delivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", d.ID, DeliveryPending, d.NextAttemptAt).
+		Update("next_attempt_at", next)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return false, nil
+	}
+	d.NextAttemptAt = next
+	return true, nil
+}
+
+// SaveAttempt 保存一次投递的结果
+func (d *Delivery) SaveAttempt(ctx context.Context) error {
+	return model.DB(ctx).Model(d).Updates(map[string]interface{}{
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"next_attempt_at": d.NextAttemptAt,
+		"response_status": d.ResponseStatus,
+		"response_body":   d.ResponseBody,
+		"error":           d.Error,
+		"duration":        d.Duration,
+	}).Error
+}
+
+// Redeliver 重置为待投递
+func (d *Delivery) Redeliver(ctx context.Context) error {
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = time.Now()
+	return model.DB(ctx).Model(d).Updates(map[string]interface{}{
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"next_attempt_at": d.NextAttemptAt,
+	}).Error
+}
+
+// GetDeliveries 按时间倒序分页获取webhook的投递记录
+func GetDeliveries(ctx context.Context, webhookID uint, status string, page, pageSize int) ([]*Delivery, error) {
+	tx := model.DB(ctx).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+	if page > 0 && pageSize > 0 {
+		tx = tx.Offset((page - 1) * pageSize).Limit(pageSize)
+	}
+	var list []*Delivery
+	return list, tx.Order("id desc").Find(&list).Error
+}
+
+func GetDeliveriesCount(ctx context.Context, webhookID uint, status string) (int64, error) {
+	tx := model.DB(ctx).Model(&Delivery{}).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+	var count int64
+	return count, tx.Count(&count).Error
+}
+
+// GetDueDeliveries 获取到期待投递的记录
+func GetDueDeliveries(ctx context.Context, limit int) ([]*Delivery, error) {
+	var list []*Delivery
+	return list, model.DB(ctx).
+		Where("status = ? AND next_attempt_at <= ?", DeliveryPending, time.Now()).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		Find(&list).Error
+}
@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/apicat/apicat/v2/backend/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	EventCollectionCreated         = "collection.created"
+	EventCollectionUpdated         = "collection.updated"
+	EventCollectionDeleted         = "collection.deleted"
+	EventDefinitionSchemaCreated   = "definition_schema.created"
+	EventDefinitionSchemaUpdated   = "definition_schema.updated"
+	EventDefinitionSchemaDeleted   = "definition_schema.deleted"
+	EventDefinitionResponseCreated = "definition_response.created"
+	EventDefinitionResponseUpdated = "definition_response.updated"
+	EventDefinitionResponseDeleted = "definition_response.deleted"
+	EventGlobalParameterCreated    = "global_parameter.created"
+	EventGlobalParameterUpdated    = "global_parameter.updated"
+	EventGlobalParameterDeleted    = "global_parameter.deleted"
+	EventIterationCreated          = "iteration.created"
+	EventIterationUpdated          = "iteration.updated"
+	EventIterationDeleted          = "iteration.deleted"
+	EventIterationMerged           = "iteration.merged"
+	EventReleaseCreated            = "release.created"
+	EventReleaseDeleted            = "release.deleted"
+	// EventPing 测试webhook时发送，不需要订阅
+	EventPing = "ping"
+)
+
+var Events = []string{
+	EventCollectionCreated,
+	EventCollectionUpdated,
+	EventCollectionDeleted,
+	EventDefinitionSchemaCreated,
+	EventDefinitionSchemaUpdated,
+	EventDefinitionSchemaDeleted,
+	EventDefinitionResponseCreated,
+	EventDefinitionResponseUpdated,
+	EventDefinitionResponseDeleted,
+	EventGlobalParameterCreated,
+	EventGlobalParameterUpdated,
+	EventGlobalParameterDeleted,
+	EventIterationCreated,
+	EventIterationUpdated,
+	EventIterationDeleted,
+	EventIterationMerged,
+	EventReleaseCreated,
+	EventReleaseDeleted,
+}
+
+// Webhook 项目webhook配置，Events为空表示订阅全部事件
+type Webhook struct {
+	ID            uint   `gorm:"type:bigint;primaryKey;autoIncrement"`
+	ProjectID     string `gorm:"type:varchar(24);index;not null;comment:project id"`
+	Name          string `gorm:"type:varchar(255);not null;comment:webhook name"`
+	URL           string `gorm:"type:varchar(1024);not null;comment:payload url"`
+	Secret        string `gorm:"type:varchar(255);comment:hmac secret"`
+	Events        string `gorm:"type:varchar(1024);not null;comment:subscribed events"`
+	Active        bool   `gorm:"type:tinyint;not null;default:1;comment:is active"`
+	MaxRetries    int    `gorm:"type:int(11);not null;default:5;comment:max retry times"`
+	RetryInterval int    `gorm:"type:int(11);not null;default:10;comment:first retry interval in seconds, doubled on each retry"`
+	CreatedBy     uint   `gorm:"type:bigint;not null;default:0;comment:team member id"`
+	model.TimeModel
+}
+
+// Get 获取webhook
+func (w *Webhook) Get(ctx context.Context) (bool, error) {
+	tx := model.DB(ctx)
+	if w.ID != 0 && w.ProjectID != "" {
+		tx = tx.Take(w, "id = ? AND project_id = ?", w.ID, w.ProjectID)
+	} else if w.ID != 0 {
+		tx = tx.Take(w, "id = ?", w.ID)
+	} else {
+		return false, errors.New("query condition error")
+	}
+	err := model.NotRecord(tx)
+	return tx.Error == nil, err
+}
+
+// Create 创建webhook
+func (w *Webhook) Create(ctx context.Context) error {
+	return model.DB(ctx).Create(w).Error
+}
+
+// Update 更新webhook
+func (w *Webhook) Update(ctx context.Context) error {
+	return model.DB(ctx).Model(w).Updates(map[string]interface{}{
+		"name":           w.Name,
+		"url":            w.URL,
+		"secret":         w.Secret,
+		"events":         w.Events,
+		"active":         w.Active,
+		"max_retries":    w.MaxRetries,
+		"retry_interval": w.RetryInterval,
+	}).Error
+}
+
+// Delete 删除webhook及其投递记录
+func (w *Webhook) Delete(ctx context.Context) error {
+	return model.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", w.ID).Delete(&Delivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(w).Error
+	})
+}
+
+// GetEvents 获取订阅的事件
+func (w *Webhook) GetEvents() []string {
+	list := make([]string, 0)
+	if w.Events == "" {
+		return list
+	}
+	if err := json.Unmarshal([]byte(w.Events), &list); err != nil {
+		return make([]string, 0)
+	}
+	return list
+}
+
+// SetEvents 设置订阅的事件，忽略不支持的事件
+func (w *Webhook) SetEvents(events []string) {
+	list := make([]string, 0, len(events))
+	seen := make(map[string]bool)
+	for _, e := range events {
+		if ValidEvent(e) && !seen[e] {
+			seen[e] = true
+			list = append(list, e)
+		}
+	}
+	b, _ := json.Marshal(list)
+	w.Events = string(b)
+}
+
+// Subscribed 是否订阅了某个事件
+func (w *Webhook) Subscribed(event string) bool {
+	events := w.GetEvents()
+	if len(events) == 0 {
+		return true
+	}
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// GetWebhooks 获取项目的webhook列表
+func GetWebhooks(ctx context.Context, projectID string) ([]*Webhook, error) {
+	var list []*Webhook
+	return list, model.DB(ctx).Where("project_id = ?", projectID).Order("id asc").Find(&list).Error
+}
+
+// GetSubscribedWebhooks 获取项目中订阅了某个事件且启用的webhook
+func GetSubscribedWebhooks(ctx context.Context, projectID, event string) ([]*Webhook, error) {
+	var list []*Webhook
+	if err := model.DB(ctx).Where("project_id = ? AND active = ?", projectID, true).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	res := make([]*Webhook, 0, len(list))
+	for _, w := range list {
+		if w.Subscribed(event) {
+			res = append(res, w)
+		}
+	}
+	return res, nil
+}
@@ -7,4 +7,6 @@ type Cache interface {
 	Set(string, string, time.Duration) error
 	Get(string) (string, bool, error)
 	Del(string) error
+	// Incr 原子地将计数加一并刷新过期时间，返回加一后的值
+	Incr(string, time.Duration) (int64, error)
 }
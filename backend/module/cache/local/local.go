@@ -1,6 +1,7 @@
 package local
 
 import (
+	"strconv"
 	"sync"
 	"time"
 )
@@ -14,7 +15,11 @@ type cacheData struct {
 	expiredTime time.Time
 }
 
-var dataMap *sync.Map
+var (
+	dataMap *sync.Map
+	// incrMu 保证计数的读取和写回不被并发打断
+	incrMu sync.Mutex
+)
 
 func NewLocal() (*localCache, error) {
 	return &localCache{
@@ -54,6 +59,21 @@ func (l *localCache) Del(k string) error {
 	return nil
 }
 
+func (l *localCache) Incr(k string, du time.Duration) (int64, error) {
+	incrMu.Lock()
+	defer incrMu.Unlock()
+
+	var n int64
+	if v, ok, _ := l.Get(k); ok {
+		var err error
+		if n, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return 0, err
+		}
+	}
+	n++
+	return n, l.Set(k, strconv.FormatInt(n, 10), du)
+}
+
 // func NewLocalCache[T any]() cache.Cache[T] {
 // 	c := &localCache{}
 // 	go func() {
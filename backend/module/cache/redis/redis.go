@@ -8,6 +8,13 @@ import (
 	"github.com/go-redis/redis/v8"
 )
 
+// incrScript 计数加一并刷新过期时间，在一个脚本中执行保证原子性
+var incrScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+redis.call("PEXPIRE", KEYS[1], ARGV[1])
+return n
+`)
+
 type RedisOpt struct {
 	Host     string
 	Password string
@@ -73,3 +80,8 @@ func (r *redisCache) Del(key string) error {
 	}
 	return nil
 }
+
+func (r *redisCache) Incr(key string, du time.Duration) (int64, error) {
+	r.init()
+	return incrScript.Run(r.ctx, r.client, []string{key}, du.Milliseconds()).Int64()
+}
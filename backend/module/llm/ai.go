@@ -4,19 +4,27 @@ import (
 	"errors"
 	"log/slog"
 
+	"github.com/apicat/apicat/v2/backend/module/llm/anthropic"
 	"github.com/apicat/apicat/v2/backend/module/llm/common"
+	"github.com/apicat/apicat/v2/backend/module/llm/ollama"
 	"github.com/apicat/apicat/v2/backend/module/llm/openai"
 )
 
 const (
-	OPENAI      = "openai"
-	AZUREOPENAI = "azure-openai"
+	OPENAI           = "openai"
+	AZUREOPENAI      = "azure-openai"
+	OPENAICOMPATIBLE = "openai-compatible"
+	OLLAMA           = "ollama"
+	ANTHROPIC        = "anthropic"
 )
 
 type LLM struct {
-	Driver      string
-	OpenAI      openai.OpenAI
-	AzureOpenAI openai.AzureOpenAI
+	Driver           string
+	OpenAI           openai.OpenAI
+	AzureOpenAI      openai.AzureOpenAI
+	OpenAICompatible openai.OpenAICompatible
+	Ollama           ollama.Ollama
+	Anthropic        anthropic.Anthropic
 }
 
 func NewLLM(cfg LLM) (common.Provider, error) {
@@ -34,6 +42,36 @@ func NewLLM(cfg LLM) (common.Provider, error) {
 		} else {
 			return nil, errors.New("openai.NewAzureOpenAI failed")
 		}
+	} else if cfg.Driver == OPENAICOMPATIBLE {
+		if o := openai.NewOpenAICompatible(cfg.OpenAICompatible); o != nil {
+			return o, nil
+		} else {
+			return nil, errors.New("openai.NewOpenAICompatible failed")
+		}
+	} else if cfg.Driver == OLLAMA {
+		return ollama.NewOllama(cfg.Ollama), nil
+	} else if cfg.Driver == ANTHROPIC {
+		if o := anthropic.NewAnthropic(cfg.Anthropic); o != nil {
+			return o, nil
+		} else {
+			return nil, errors.New("anthropic.NewAnthropic failed")
+		}
 	}
 	return nil, errors.New("llm driver not found")
 }
+
+// NewEmbedder 获取向量模型，模型不支持或未配置向量模型时返回错误
+func NewEmbedder(cfg LLM) (common.Embedder, error) {
+	p, err := NewLLM(cfg)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := p.(common.Embedder)
+	if !ok {
+		return nil, errors.New("embedding is not supported by " + cfg.Driver)
+	}
+	if e.EmbeddingModel() == "" {
+		return nil, errors.New("embedding model not set")
+	}
+	return e, nil
+}
@@ -0,0 +1,284 @@
+package anthropic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/module/llm/common"
+)
+
+const (
+	defaultApiBase   = "https://api.anthropic.com"
+	apiVersion       = "2023-06-01"
+	defaultMaxTokens = 4096
+)
+
+type Anthropic struct {
+	ApiKey  string
+	ApiBase string
+	LLMName string
+	Timeout int
+}
+
+type anthropic struct {
+	apiKey  string
+	apiBase string
+	llmName string
+	client  *http.Client
+	// streamClient 流式请求耗时较长，不设置整体超时
+	streamClient *http.Client
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type messagesRequest struct {
+	Model       string    `json:"model"`
+	System      string    `json:"system,omitempty"`
+	Messages    []message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens"`
+	Temperature float32   `json:"temperature,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+type messagesResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage usage `json:"usage"`
+}
+
+type usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type streamEvent struct {
+	Type string `json:"type"`
+	// Message 在 message_start 事件中返回，包含输入 token 数量
+	Message struct {
+		Usage usage `json:"usage"`
+	} `json:"message"`
+	// Usage 在 message_delta 事件中返回累计的输出 token 数量
+	Usage usage `json:"usage"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func NewAnthropic(cfg Anthropic) *anthropic {
+	if cfg.ApiKey == "" {
+		return nil
+	}
+
+	apiBase := strings.TrimRight(cfg.ApiBase, "/")
+	if apiBase == "" {
+		apiBase = defaultApiBase
+	}
+
+	timeout := time.Second * 30
+	if cfg.Timeout > 0 {
+		timeout = time.Second * time.Duration(cfg.Timeout)
+	}
+
+	return &anthropic{
+		apiKey:       cfg.ApiKey,
+		apiBase:      apiBase,
+		llmName:      cfg.LLMName,
+		client:       &http.Client{Timeout: timeout},
+		streamClient: &http.Client{},
+	}
+}
+
+func (a *anthropic) Check() error {
+	if a.llmName == "" {
+		return errors.New("model name not set")
+	}
+	if err := a.do(context.Background(), http.MethodGet, "/v1/models/"+a.llmName, nil, nil); err != nil {
+		return fmt.Errorf("%s model not found: %w", a.llmName, err)
+	}
+	return nil
+}
+
+func (a *anthropic) ChatCompletionRequest(r *common.ChatCompletionRequest) (string, error) {
+	var resp messagesResponse
+	if err := a.do(context.Background(), http.MethodPost, "/v1/messages", a.messagesRequest(r, false), &resp); err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, c := range resp.Content {
+		if c.Type == "text" {
+			text.WriteString(c.Text)
+		}
+	}
+	r.Usage = common.Usage{PromptTokens: resp.Usage.InputTokens, CompletionTokens: resp.Usage.OutputTokens}
+	return text.String(), nil
+}
+
+// ChatCompletionStream 流式响应为 SSE，只处理文本增量、结束和错误事件
+func (a *anthropic) ChatCompletionStream(r *common.ChatCompletionRequest, onDelta func(delta string) error) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.StreamTimeout)
+	defer cancel()
+
+	req, err := a.newRequest(ctx, http.MethodPost, "/v1/messages", a.messagesRequest(r, true))
+	if err != nil {
+		return "", err
+	}
+	resp, err := a.streamClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", readError(resp)
+	}
+
+	var result strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+
+		var event streamEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			return "", err
+		}
+		switch event.Type {
+		case "message_start":
+			r.Usage.PromptTokens = event.Message.Usage.InputTokens
+		case "message_delta":
+			r.Usage.CompletionTokens = event.Usage.OutputTokens
+		case "content_block_delta":
+			if event.Delta.Type != "text_delta" || event.Delta.Text == "" {
+				continue
+			}
+			result.WriteString(event.Delta.Text)
+			if err := onDelta(event.Delta.Text); err != nil {
+				return "", err
+			}
+		case "message_stop":
+			return result.String(), nil
+		case "error":
+			return "", fmt.Errorf("anthropic: %s", event.Error.Message)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// messagesRequest Messages API 不接受 system 角色的消息，需合并到 system 字段
+func (a *anthropic) messagesRequest(r *common.ChatCompletionRequest, stream bool) messagesRequest {
+	req := messagesRequest{
+		Model:       a.llmName,
+		MaxTokens:   r.MaxTokens,
+		Temperature: r.Temperature,
+		Stream:      stream,
+	}
+	if req.MaxTokens <= 0 {
+		req.MaxTokens = defaultMaxTokens
+	}
+
+	var system []string
+	for _, v := range r.Messages {
+		if v.Role == a.ChatMessageRoleSystem() {
+			system = append(system, v.Content)
+			continue
+		}
+		req.Messages = append(req.Messages, message{Role: v.Role, Content: v.Content})
+	}
+	req.System = strings.Join(system, "\n\n")
+	return req
+}
+
+func (a *anthropic) ChatMessageRoleSystem() string {
+	return "system"
+}
+
+func (a *anthropic) ChatMessageRoleAssistant() string {
+	return "assistant"
+}
+
+func (a *anthropic) ChatMessageRoleUser() string {
+	return "user"
+}
+
+func (a *anthropic) do(ctx context.Context, method, path string, body, out interface{}) error {
+	req, err := a.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return readError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (a *anthropic) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.apiBase+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-api-key", a.apiKey)
+	req.Header.Set("anthropic-version", apiVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+func readError(resp *http.Response) error {
+	var e errorResponse
+	if body, err := io.ReadAll(resp.Body); err == nil && json.Unmarshal(body, &e) == nil && e.Error.Message != "" {
+		return fmt.Errorf("anthropic: %s", e.Error.Message)
+	}
+	return fmt.Errorf("anthropic: unexpected status %d", resp.StatusCode)
+}
@@ -0,0 +1,124 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/module/llm/common"
+)
+
+func TestChatCompletionRequest(t *testing.T) {
+	var got messagesRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Fatalf("path = %s, want /v1/messages", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "key" || r.Header.Get("anthropic-version") != apiVersion {
+			t.Fatalf("unexpected headers: %v", r.Header)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"content":[{"type":"text","text":"hel"},{"type":"text","text":"lo"}],"stop_reason":"end_turn","usage":{"input_tokens":12,"output_tokens":3}}`))
+	}))
+	defer srv.Close()
+
+	a := NewAnthropic(Anthropic{ApiKey: "key", ApiBase: srv.URL, LLMName: "claude"})
+	req := &common.ChatCompletionRequest{
+		Messages: []common.ChatCompletionMessage{
+			{Role: a.ChatMessageRoleSystem(), Content: "be brief"},
+			{Role: a.ChatMessageRoleUser(), Content: "hi"},
+		},
+	}
+	result, err := a.ChatCompletionRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "hello" {
+		t.Fatalf("result = %q, want hello", result)
+	}
+	if got.System != "be brief" {
+		t.Fatalf("system = %q, want be brief", got.System)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Role != "user" {
+		t.Fatalf("messages = %+v, want only the user message", got.Messages)
+	}
+	if got.MaxTokens != defaultMaxTokens {
+		t.Fatalf("max_tokens = %d, want %d", got.MaxTokens, defaultMaxTokens)
+	}
+	if req.Usage != (common.Usage{PromptTokens: 12, CompletionTokens: 3}) {
+		t.Fatalf("usage = %+v", req.Usage)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models/claude" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type":"error","error":{"type":"not_found_error","message":"model not found"}}`))
+			return
+		}
+		w.Write([]byte(`{"id":"claude","type":"model"}`))
+	}))
+	defer srv.Close()
+
+	if err := NewAnthropic(Anthropic{ApiKey: "key", ApiBase: srv.URL, LLMName: "claude"}).Check(); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewAnthropic(Anthropic{ApiKey: "key", ApiBase: srv.URL, LLMName: "other"}).Check(); err == nil {
+		t.Fatal("expected error for missing model")
+	}
+	if NewAnthropic(Anthropic{LLMName: "claude"}) != nil {
+		t.Fatal("expected nil provider without api key")
+	}
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req messagesRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Errorf("stream = false, want true")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"usage\":{\"input_tokens\":8}}}\n\n" +
+			"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"hel\"}}\n\n" +
+			"event: ping\ndata: {\"type\":\"ping\"}\n\n" +
+			"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"lo\"}}\n\n" +
+			"event: message_delta\ndata: {\"type\":\"message_delta\",\"usage\":{\"output_tokens\":2}}\n\n" +
+			"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n"))
+	}))
+	defer srv.Close()
+
+	var deltas []string
+	a := NewAnthropic(Anthropic{ApiKey: "key", ApiBase: srv.URL, LLMName: "claude"})
+	req := &common.ChatCompletionRequest{
+		Messages: []common.ChatCompletionMessage{{Role: a.ChatMessageRoleUser(), Content: "hi"}},
+	}
+	result, err := a.ChatCompletionStream(req, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "hello" || len(deltas) != 2 {
+		t.Fatalf("result = %q, deltas = %v", result, deltas)
+	}
+	if req.Usage != (common.Usage{PromptTokens: 8, CompletionTokens: 2}) {
+		t.Fatalf("usage = %+v", req.Usage)
+	}
+}
+
+func TestChatCompletionStreamError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n"))
+	}))
+	defer srv.Close()
+
+	a := NewAnthropic(Anthropic{ApiKey: "key", ApiBase: srv.URL, LLMName: "claude"})
+	_, err := a.ChatCompletionStream(&common.ChatCompletionRequest{}, func(string) error { return nil })
+	if err == nil {
+		t.Fatal("expected error event to fail the stream")
+	}
+}
@@ -1,5 +1,10 @@
 package common
 
+import "time"
+
+// StreamTimeout 流式请求的最长耗时
+const StreamTimeout = 5 * time.Minute
+
 type ChatCompletionMessage struct {
 	Role    string
 	Content string
@@ -10,6 +15,21 @@ type ChatCompletionRequest struct {
 	Temperature float32
 	MaxTokens   int
 	Messages    []ChatCompletionMessage
+	// Usage 请求完成后由模型填充实际消耗的 token，模型未返回时为零值
+	Usage Usage
+}
+
+// EmbeddingRequest 生成向量的请求
+type EmbeddingRequest struct {
+	Input []string
+	// Usage 请求完成后由模型填充实际消耗的 token，模型未返回时为零值
+	Usage Usage
+}
+
+// Usage 一次请求消耗的 token 数量
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
 }
 
 type Provider interface {
@@ -17,5 +37,13 @@ type Provider interface {
 	ChatMessageRoleAssistant() string
 	ChatMessageRoleUser() string
 	ChatCompletionRequest(r *ChatCompletionRequest) (string, error)
+	// ChatCompletionStream 流式请求，每收到一段内容调用一次 onDelta，返回完整内容
+	ChatCompletionStream(r *ChatCompletionRequest, onDelta func(delta string) error) (string, error)
 	Check() error
 }
+
+// Embedder 支持生成文本向量的模型，未配置向量模型时 EmbeddingModel 返回空
+type Embedder interface {
+	EmbeddingModel() string
+	Embeddings(r *EmbeddingRequest) ([][]float32, error)
+}
@@ -0,0 +1,257 @@
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/module/llm/common"
+)
+
+const defaultHost = "http://localhost:11434"
+
+type Ollama struct {
+	Host          string
+	LLMName       string
+	EmbeddingName string
+	Timeout       int
+}
+
+type ollama struct {
+	host          string
+	llmName       string
+	embeddingName string
+	client        *http.Client
+	streamClient  *http.Client
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model    string                 `json:"model"`
+	Messages []message              `json:"messages"`
+	Stream   bool                   `json:"stream"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+}
+
+type embedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embedResponse struct {
+	Embeddings      [][]float32 `json:"embeddings"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+}
+
+type chatResponse struct {
+	Message message `json:"message"`
+	Done    bool    `json:"done"`
+	Error   string  `json:"error"`
+	// PromptEvalCount 和 EvalCount 只在最后一条响应中返回
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+func (c *chatResponse) usage() common.Usage {
+	return common.Usage{PromptTokens: c.PromptEvalCount, CompletionTokens: c.EvalCount}
+}
+
+func NewOllama(cfg Ollama) *ollama {
+	host := strings.TrimRight(cfg.Host, "/")
+	if host == "" {
+		host = defaultHost
+	}
+
+	timeout := time.Second * 30
+	if cfg.Timeout > 0 {
+		timeout = time.Second * time.Duration(cfg.Timeout)
+	}
+
+	return &ollama{
+		host:          host,
+		llmName:       cfg.LLMName,
+		embeddingName: cfg.EmbeddingName,
+		client:        &http.Client{Timeout: timeout},
+		streamClient:  &http.Client{},
+	}
+}
+
+// Check 检查模型是否已在 Ollama 中拉取
+func (o *ollama) Check() error {
+	if o.llmName == "" {
+		return errors.New("model name not set")
+	}
+	if err := o.post(context.Background(), "/api/show", map[string]string{"model": o.llmName}, nil); err != nil {
+		return fmt.Errorf("%s model not found: %w", o.llmName, err)
+	}
+	return nil
+}
+
+func (o *ollama) ChatCompletionRequest(r *common.ChatCompletionRequest) (string, error) {
+	var resp chatResponse
+	if err := o.post(context.Background(), "/api/chat", o.chatRequest(r, false), &resp); err != nil {
+		return "", err
+	}
+	r.Usage = resp.usage()
+	return resp.Message.Content, nil
+}
+
+// ChatCompletionStream Ollama 的流式响应为每行一个 JSON 对象
+func (o *ollama) ChatCompletionStream(r *common.ChatCompletionRequest, onDelta func(delta string) error) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.StreamTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(o.chatRequest(r, true))
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.host+"/api/chat", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.streamClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", readError(resp)
+	}
+
+	var result strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk chatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return "", err
+		}
+		if chunk.Error != "" {
+			return "", fmt.Errorf("ollama: %s", chunk.Error)
+		}
+		if chunk.Message.Content != "" {
+			result.WriteString(chunk.Message.Content)
+			if err := onDelta(chunk.Message.Content); err != nil {
+				return "", err
+			}
+		}
+		if chunk.Done {
+			r.Usage = chunk.usage()
+			return result.String(), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+func (o *ollama) chatRequest(r *common.ChatCompletionRequest, stream bool) chatRequest {
+	req := chatRequest{
+		Model:    o.llmName,
+		Messages: make([]message, len(r.Messages)),
+		Stream:   stream,
+	}
+	for k, v := range r.Messages {
+		req.Messages[k] = message{Role: v.Role, Content: v.Content}
+	}
+
+	options := make(map[string]interface{})
+	if r.Temperature > 0 {
+		options["temperature"] = r.Temperature
+	}
+	if r.MaxTokens > 0 {
+		options["num_predict"] = r.MaxTokens
+	}
+	if len(options) > 0 {
+		req.Options = options
+	}
+	return req
+}
+
+func (o *ollama) EmbeddingModel() string {
+	return o.embeddingName
+}
+
+func (o *ollama) Embeddings(r *common.EmbeddingRequest) ([][]float32, error) {
+	if o.embeddingName == "" {
+		return nil, errors.New("embedding model name not set")
+	}
+
+	var resp embedResponse
+	if err := o.post(context.Background(), "/api/embed", embedRequest{Model: o.embeddingName, Input: r.Input}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Embeddings) != len(r.Input) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(r.Input), len(resp.Embeddings))
+	}
+	r.Usage = common.Usage{PromptTokens: resp.PromptEvalCount}
+	return resp.Embeddings, nil
+}
+
+func (o *ollama) ChatMessageRoleSystem() string {
+	return "system"
+}
+
+func (o *ollama) ChatMessageRoleAssistant() string {
+	return "assistant"
+}
+
+func (o *ollama) ChatMessageRoleUser() string {
+	return "user"
+}
+
+func (o *ollama) post(ctx context.Context, path string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.host+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return readError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func readError(resp *http.Response) error {
+	var e chatResponse
+	if body, err := io.ReadAll(resp.Body); err == nil && json.Unmarshal(body, &e) == nil && e.Error != "" {
+		return fmt.Errorf("ollama: %s", e.Error)
+	}
+	return fmt.Errorf("ollama: unexpected status %d", resp.StatusCode)
+}
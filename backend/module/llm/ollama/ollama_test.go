@@ -0,0 +1,126 @@
+package ollama
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/module/llm/common"
+)
+
+func TestChatCompletionRequest(t *testing.T) {
+	var got chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Fatalf("path = %s, want /api/chat", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"model":"llama3","message":{"role":"assistant","content":"hello"},"done":true,"prompt_eval_count":10,"eval_count":4}`))
+	}))
+	defer srv.Close()
+
+	o := NewOllama(Ollama{Host: srv.URL + "/", LLMName: "llama3"})
+	req := &common.ChatCompletionRequest{
+		MaxTokens: 100,
+		Messages: []common.ChatCompletionMessage{
+			{Role: o.ChatMessageRoleSystem(), Content: "be brief"},
+			{Role: o.ChatMessageRoleUser(), Content: "hi"},
+		},
+	}
+	result, err := o.ChatCompletionRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "hello" {
+		t.Fatalf("result = %q, want hello", result)
+	}
+	if got.Model != "llama3" || got.Stream || len(got.Messages) != 2 {
+		t.Fatalf("unexpected request: %+v", got)
+	}
+	if got.Options["num_predict"] != float64(100) {
+		t.Fatalf("num_predict = %v, want 100", got.Options["num_predict"])
+	}
+	if req.Usage != (common.Usage{PromptTokens: 10, CompletionTokens: 4}) {
+		t.Fatalf("usage = %+v", req.Usage)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["model"] != "llama3" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"model not found"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	if err := NewOllama(Ollama{Host: srv.URL, LLMName: "llama3"}).Check(); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewOllama(Ollama{Host: srv.URL, LLMName: "mistral"}).Check(); err == nil {
+		t.Fatal("expected error for missing model")
+	}
+	if err := NewOllama(Ollama{Host: srv.URL}).Check(); err == nil {
+		t.Fatal("expected error for empty model name")
+	}
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Errorf("stream = false, want true")
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"hel"},"done":false}
+{"message":{"role":"assistant","content":"lo"},"done":false}
+{"message":{"role":"assistant","content":""},"done":true}
+`))
+	}))
+	defer srv.Close()
+
+	var deltas []string
+	o := NewOllama(Ollama{Host: srv.URL, LLMName: "llama3"})
+	result, err := o.ChatCompletionStream(&common.ChatCompletionRequest{
+		Messages: []common.ChatCompletionMessage{{Role: o.ChatMessageRoleUser(), Content: "hi"}},
+	}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "hello" || len(deltas) != 2 {
+		t.Fatalf("result = %q, deltas = %v", result, deltas)
+	}
+}
+
+func TestEmbeddings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body embedRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/embed" || body.Model != "nomic-embed-text" || len(body.Input) != 2 {
+			t.Fatalf("unexpected request: %s %+v", r.URL.Path, body)
+		}
+		w.Write([]byte(`{"model":"nomic-embed-text","embeddings":[[0.1,0.2],[0.3,0.4]],"prompt_eval_count":6}`))
+	}))
+	defer srv.Close()
+
+	o := NewOllama(Ollama{Host: srv.URL, EmbeddingName: "nomic-embed-text"})
+	r := &common.EmbeddingRequest{Input: []string{"a", "b"}}
+	result, err := o.Embeddings(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 || result[1][0] != 0.3 {
+		t.Fatalf("unexpected embeddings: %v", result)
+	}
+	if r.Usage.PromptTokens != 6 {
+		t.Fatalf("usage = %+v, want 6 prompt tokens", r.Usage)
+	}
+}
@@ -0,0 +1,62 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	oai "github.com/sashabaranov/go-openai"
+)
+
+// OpenAICompatible 兼容 OpenAI 接口的服务，如 vLLM、LM Studio 等
+type OpenAICompatible struct {
+	ApiKey        string
+	ApiBase       string
+	LLMName       string
+	EmbeddingName string
+	Timeout       int
+}
+
+type compatible struct {
+	openai
+}
+
+func NewOpenAICompatible(cfg OpenAICompatible) *compatible {
+	if cfg.ApiBase == "" {
+		return nil
+	}
+
+	clientConfig := oai.DefaultConfig(cfg.ApiKey)
+	clientConfig.BaseURL = strings.TrimRight(cfg.ApiBase, "/")
+
+	if cfg.Timeout > 0 {
+		clientConfig.HTTPClient.Timeout = time.Second * time.Duration(cfg.Timeout)
+	} else {
+		clientConfig.HTTPClient.Timeout = time.Second * 30
+	}
+
+	return &compatible{
+		openai: *newOpenAI(clientConfig, cfg.LLMName, cfg.EmbeddingName),
+	}
+}
+
+// Check 部分兼容服务不支持获取单个模型，这里通过模型列表判断
+func (c *compatible) Check() error {
+	if c.llmName == "" {
+		return errors.New("model name not set")
+	}
+	list, err := c.client.ListModels(context.Background())
+	if err != nil {
+		slog.Error("openai.compatible.Check", "err", err)
+		return fmt.Errorf("failed to list models: %w", err)
+	}
+	for _, m := range list.Models {
+		if m.ID == c.llmName {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s model not found", c.llmName)
+}
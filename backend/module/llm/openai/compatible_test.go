@@ -0,0 +1,88 @@
+package openai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/module/llm/common"
+)
+
+func newCompatibleServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("OpenAI-Organization") != "" {
+			t.Fatalf("unexpected organization header")
+		}
+		switch r.URL.Path {
+		case "/v1/models":
+			w.Write([]byte(`{"object":"list","data":[{"id":"qwen2","object":"model"}]}`))
+		case "/v1/chat/completions":
+			var req struct {
+				Stream bool `json:"stream"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			if !req.Stream {
+				w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"hello"}}]}`))
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hel\"}}]}\n\n" +
+				"data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"lo\"}}]}\n\n" +
+				"data: [DONE]\n\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestCompatibleChatCompletionRequest(t *testing.T) {
+	srv := newCompatibleServer(t)
+	defer srv.Close()
+
+	c := NewOpenAICompatible(OpenAICompatible{ApiBase: srv.URL + "/v1/", LLMName: "qwen2"})
+	result, err := c.ChatCompletionRequest(&common.ChatCompletionRequest{
+		Messages: []common.ChatCompletionMessage{{Role: c.ChatMessageRoleUser(), Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "hello" {
+		t.Fatalf("result = %q, want hello", result)
+	}
+}
+
+func TestCompatibleCheck(t *testing.T) {
+	srv := newCompatibleServer(t)
+	defer srv.Close()
+
+	if err := NewOpenAICompatible(OpenAICompatible{ApiBase: srv.URL + "/v1", LLMName: "qwen2"}).Check(); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewOpenAICompatible(OpenAICompatible{ApiBase: srv.URL + "/v1", LLMName: "llama3"}).Check(); err == nil {
+		t.Fatal("expected error for missing model")
+	}
+	if NewOpenAICompatible(OpenAICompatible{LLMName: "qwen2"}) != nil {
+		t.Fatal("expected nil provider without api base")
+	}
+}
+
+func TestCompatibleChatCompletionStream(t *testing.T) {
+	srv := newCompatibleServer(t)
+	defer srv.Close()
+
+	var deltas []string
+	c := NewOpenAICompatible(OpenAICompatible{ApiBase: srv.URL + "/v1", LLMName: "qwen2"})
+	result, err := c.ChatCompletionStream(&common.ChatCompletionRequest{
+		Messages: []common.ChatCompletionMessage{{Role: c.ChatMessageRoleUser(), Content: "hi"}},
+	}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "hello" || len(deltas) != 2 {
+		t.Fatalf("result = %q, deltas = %v", result, deltas)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/apicat/apicat/v2/backend/module/llm/common"
@@ -33,6 +36,8 @@ type openai struct {
 	llmName       string
 	embeddingName string
 	client        *oai.Client
+	// streamClient 流式请求可能超过普通请求的超时时间，不设置整体超时
+	streamClient *oai.Client
 }
 
 func NewOpenAI(cfg OpenAI) *openai {
@@ -44,11 +49,7 @@ func NewOpenAI(cfg OpenAI) *openai {
 		clientConfig.HTTPClient.Timeout = time.Second * 30
 	}
 
-	return &openai{
-		llmName:       cfg.LLMName,
-		embeddingName: cfg.EmbeddingName,
-		client:        oai.NewClientWithConfig(clientConfig),
-	}
+	return newOpenAI(clientConfig, cfg.LLMName, cfg.EmbeddingName)
 }
 
 func NewAzureOpenAI(cfg AzureOpenAI) *openai {
@@ -60,10 +61,18 @@ func NewAzureOpenAI(cfg AzureOpenAI) *openai {
 		clientConfig.HTTPClient.Timeout = time.Second * 30
 	}
 
+	return newOpenAI(clientConfig, cfg.LLMName, cfg.EmbeddingName)
+}
+
+func newOpenAI(clientConfig oai.ClientConfig, llmName, embeddingName string) *openai {
+	streamConfig := clientConfig
+	streamConfig.HTTPClient = &http.Client{}
+
 	return &openai{
-		llmName:       cfg.LLMName,
-		embeddingName: cfg.EmbeddingName,
+		llmName:       llmName,
+		embeddingName: embeddingName,
 		client:        oai.NewClientWithConfig(clientConfig),
+		streamClient:  oai.NewClientWithConfig(streamConfig),
 	}
 }
 
@@ -91,9 +100,51 @@ func (o *openai) ChatCompletionRequest(r *common.ChatCompletionRequest) (string,
 		return "", err
 	}
 
+	r.Usage = common.Usage{
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+	}
 	return resp.Choices[0].Message.Content, nil
 }
 
+func (o *openai) ChatCompletionStream(r *common.ChatCompletionRequest, onDelta func(delta string) error) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.StreamTimeout)
+	defer cancel()
+
+	stream, err := o.streamClient.CreateChatCompletionStream(
+		ctx,
+		oai.ChatCompletionRequest{
+			Model:    o.llmName,
+			Messages: compileMessages(r.Messages),
+			Stream:   true,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var result strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return result.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := resp.Choices[0].Delta.Content
+		result.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return "", err
+		}
+	}
+}
+
 func (o *openai) ChatMessageRoleSystem() string {
 	return oai.ChatMessageRoleSystem
 }
@@ -116,3 +167,35 @@ func compileMessages(m []common.ChatCompletionMessage) []oai.ChatCompletionMessa
 	}
 	return messages
 }
+
+func (o *openai) EmbeddingModel() string {
+	return o.embeddingName
+}
+
+func (o *openai) Embeddings(r *common.EmbeddingRequest) ([][]float32, error) {
+	if o.embeddingName == "" {
+		return nil, errors.New("embedding model name not set")
+	}
+
+	input := r.Input
+	resp, err := o.client.CreateEmbeddings(context.Background(), oai.EmbeddingRequestStrings{
+		Input: input,
+		Model: oai.EmbeddingModel(o.embeddingName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) != len(input) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(input), len(resp.Data))
+	}
+	r.Usage = common.Usage{PromptTokens: resp.Usage.PromptTokens}
+
+	result := make([][]float32, len(input))
+	for _, v := range resp.Data {
+		if v.Index < 0 || v.Index >= len(input) {
+			return nil, fmt.Errorf("embedding index %d out of range", v.Index)
+		}
+		result[v.Index] = v.Embedding
+	}
+	return result, nil
+}
@@ -0,0 +1,609 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+
+	"golang.org/x/exp/slices"
+)
+
+// 差异的兼容性级别
+const (
+	LevelBreaking            = "breaking"
+	LevelPotentiallyBreaking = "potentially_breaking"
+	LevelNonBreaking         = "non_breaking"
+)
+
+// 规则名，同时也是i18n中breakingChange下的key
+const (
+	RuleEndpointPathChanged         = "EndpointPathChanged"
+	RuleEndpointMethodChanged       = "EndpointMethodChanged"
+	RulePathParamRenamed            = "PathParamRenamed"
+	RuleRequestFieldAdded           = "RequestFieldAdded"
+	RuleRequiredRequestFieldAdded   = "RequiredRequestFieldAdded"
+	RuleRequestFieldRemoved         = "RequestFieldRemoved"
+	RuleRequestFieldBecameRequired  = "RequestFieldBecameRequired"
+	RuleRequestFieldBecameOptional  = "RequestFieldBecameOptional"
+	RuleResponseFieldAdded          = "ResponseFieldAdded"
+	RuleResponseFieldRemoved        = "ResponseFieldRemoved"
+	RuleResponseFieldBecameRequired = "ResponseFieldBecameRequired"
+	RuleResponseFieldBecameOptional = "ResponseFieldBecameOptional"
+	RuleResponseStatusAdded         = "ResponseStatusAdded"
+	RuleResponseStatusRemoved       = "ResponseStatusRemoved"
+	RuleContentTypeChanged          = "ContentTypeChanged"
+	RuleReferenceChanged            = "ReferenceChanged"
+	RuleTypeChanged                 = "TypeChanged"
+	RuleTypeNarrowed                = "TypeNarrowed"
+	RuleTypeWidened                 = "TypeWidened"
+	RuleEnumValueAdded              = "EnumValueAdded"
+	RuleEnumValueRemoved            = "EnumValueRemoved"
+	RuleConstraintNarrowed          = "ConstraintNarrowed"
+	RuleConstraintRelaxed           = "ConstraintRelaxed"
+)
+
+// ClassifiedChange 一处带兼容性级别的差异
+// Pointer为差异位置的JSON Pointer，Params为生成提示信息的参数，第一个参数总是字段路径
+type ClassifiedChange struct {
+	Level   string   `json:"level"`
+	Rule    string   `json:"rule"`
+	Pointer string   `json:"pointer"`
+	Params  []string `json:"params"`
+}
+
+// Report 兼容性检查报告
+type Report struct {
+	Breaking            int                 `json:"breaking"`
+	PotentiallyBreaking int                 `json:"potentiallyBreaking"`
+	NonBreaking         int                 `json:"nonBreaking"`
+	Changes             []*ClassifiedChange `json:"changes"`
+}
+
+// HasBreaking 是否包含不兼容的修改
+func (r *Report) HasBreaking() bool {
+	return r.Breaking > 0
+}
+
+func (r *Report) add(level, rule, pointer string, params ...string) {
+	switch level {
+	case LevelBreaking:
+		r.Breaking++
+	case LevelPotentiallyBreaking:
+		r.PotentiallyBreaking++
+	case LevelNonBreaking:
+		r.NonBreaking++
+	}
+	r.Changes = append(r.Changes, &ClassifiedChange{
+		Level:   level,
+		Rule:    rule,
+		Pointer: pointer,
+		Params:  params,
+	})
+}
+
+// direction 数据的流向，决定同一种修改的兼容性
+// 请求由客户端发出，收紧约束会导致旧客户端出错；响应由服务端返回，放宽约束会导致旧客户端出错
+type direction int
+
+const (
+	dirRequest direction = iota
+	dirResponse
+	// dirBoth 公共模型可能同时用于请求和响应，取两者中较严重的级别
+	dirBoth
+)
+
+var levelWeight = map[string]int{
+	LevelNonBreaking:         0,
+	LevelPotentiallyBreaking: 1,
+	LevelBreaking:            2,
+}
+
+// pick 按流向选择级别
+func (d direction) pick(request, response string) string {
+	switch d {
+	case dirRequest:
+		return request
+	case dirResponse:
+		return response
+	}
+	if levelWeight[request] >= levelWeight[response] {
+		return request
+	}
+	return response
+}
+
+func newReport() *Report {
+	return &Report{Changes: make([]*ClassifiedChange, 0)}
+}
+
+// Classify 对比两个版本的接口，按兼容性对每处差异分类
+// 传入的接口应当已经解引用，不会修改传入的内容
+func Classify(original, target *spec.Collection) *Report {
+	r := newReport()
+	if original == nil || target == nil {
+		return r
+	}
+
+	c := &classifier{report: r}
+	c.url(original.Content.GetUrl(), target.Content.GetUrl())
+	c.request(original.Content.GetRequest(), target.Content.GetRequest())
+	c.responses(original.Content.GetResponse(), target.Content.GetResponse())
+	return r
+}
+
+// ClassifyModel 对比两个版本的公共模型，模型可能用于请求和响应，按较严重的情况分类
+func ClassifyModel(original, target *spec.DefinitionModel) *Report {
+	r := newReport()
+	if original == nil || target == nil {
+		return r
+	}
+
+	c := &classifier{report: r}
+	c.schema("schema", "/schema", original.Schema, target.Schema, dirBoth)
+	return r
+}
+
+type classifier struct {
+	report *Report
+}
+
+var pathParamRegexp = regexp.MustCompile(`\{[^}]*\}|:[^/]+`)
+
+func (c *classifier) url(a, b *spec.CollectionHttpUrl) {
+	if a == nil || b == nil {
+		return
+	}
+	if a.Attrs.Path != b.Attrs.Path {
+		// 只修改了路径参数名称时由路径参数给出提示
+		if pathParamRegexp.ReplaceAllString(a.Attrs.Path, "{}") != pathParamRegexp.ReplaceAllString(b.Attrs.Path, "{}") {
+			c.report.add(LevelBreaking, RuleEndpointPathChanged, "/url/path", "url", a.Attrs.Path, b.Attrs.Path)
+		}
+	}
+	if !strings.EqualFold(a.Attrs.Method, b.Attrs.Method) {
+		c.report.add(LevelBreaking, RuleEndpointMethodChanged, "/url/method", "url", strings.ToUpper(a.Attrs.Method), strings.ToUpper(b.Attrs.Method))
+	}
+}
+
+func (c *classifier) request(a, b *spec.CollectionHttpRequest) {
+	if a == nil || b == nil || a.Attrs == nil || b.Attrs == nil {
+		return
+	}
+
+	ap, bp := &spec.HTTPParameters{}, &spec.HTTPParameters{}
+	if a.Attrs.Parameters != nil {
+		ap = a.Attrs.Parameters
+	}
+	if b.Attrs.Parameters != nil {
+		bp = b.Attrs.Parameters
+	}
+	am, bm := ap.ToMap(), bp.ToMap()
+	for _, typ := range spec.HttpParameterType {
+		if typ == "path" {
+			c.pathParameters(am[typ], bm[typ])
+			continue
+		}
+		c.parameters("request."+typ, "/request/parameters/"+typ, am[typ], bm[typ], dirRequest)
+	}
+
+	c.content("request.body", "/request/content", a.Attrs.Content, b.Attrs.Content, dirRequest)
+}
+
+// pathParameters 路径参数总是必填，删除一个同时新增一个视为重命名
+func (c *classifier) pathParameters(a, b spec.ParameterList) {
+	removed, added := make([]*spec.Parameter, 0), make([]*spec.Parameter, 0)
+	for _, p := range a {
+		if bp := b.FindByName(p.Name); bp == nil {
+			removed = append(removed, p)
+		} else {
+			path := "request.path." + p.Name
+			c.schema(path, "/request/parameters/path/"+escapePointer(p.Name)+"/schema", p.Schema, bp.Schema, dirRequest)
+		}
+	}
+	for _, p := range b {
+		if a.FindByName(p.Name) == nil {
+			added = append(added, p)
+		}
+	}
+
+	n := len(removed)
+	if len(added) < n {
+		n = len(added)
+	}
+	for i := 0; i < n; i++ {
+		c.report.add(LevelBreaking, RulePathParamRenamed, "/request/parameters/path/"+escapePointer(added[i].Name),
+			"request.path."+removed[i].Name, added[i].Name)
+	}
+	for _, p := range removed[n:] {
+		c.report.add(LevelBreaking, RuleRequestFieldRemoved, "/request/parameters/path/"+escapePointer(p.Name), "request.path."+p.Name)
+	}
+	for _, p := range added[n:] {
+		c.report.add(LevelBreaking, RuleRequiredRequestFieldAdded, "/request/parameters/path/"+escapePointer(p.Name), "request.path."+p.Name)
+	}
+}
+
+func (c *classifier) parameters(path, pointer string, a, b spec.ParameterList, dir direction) {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, list := range []spec.ParameterList{a, b} {
+		for _, p := range list {
+			if !seen[p.Name] {
+				seen[p.Name] = true
+				names = append(names, p.Name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		ap, bp := a.FindByName(name), b.FindByName(name)
+		c.field(path+"."+name, pointer+"/"+escapePointer(name), ap != nil, bp != nil,
+			ap != nil && ap.Required, bp != nil && bp.Required, dir)
+		if ap != nil && bp != nil {
+			c.schema(path+"."+name, pointer+"/"+escapePointer(name)+"/schema", ap.Schema, bp.Schema, dir)
+		}
+	}
+}
+
+func (c *classifier) responses(a, b *spec.CollectionHttpResponse) {
+	if a == nil || b == nil || a.Attrs == nil || b.Attrs == nil {
+		return
+	}
+
+	codes := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, list := range []spec.Responses{a.Attrs.List, b.Attrs.List} {
+		for _, v := range list {
+			if !seen[v.Code] {
+				seen[v.Code] = true
+				codes = append(codes, v.Code)
+			}
+		}
+	}
+	sort.Ints(codes)
+
+	for _, code := range codes {
+		ar, br := a.Attrs.List.FindByCode(code), b.Attrs.List.FindByCode(code)
+		path := "response." + strconv.Itoa(code)
+		pointer := "/responses/" + strconv.Itoa(code)
+		switch {
+		case ar == nil:
+			c.report.add(LevelNonBreaking, RuleResponseStatusAdded, pointer, path)
+		case br == nil:
+			c.report.add(LevelBreaking, RuleResponseStatusRemoved, pointer, path)
+		default:
+			c.parameters(path+".header", pointer+"/header", ar.Header, br.Header, dirResponse)
+			c.content(path+".body", pointer+"/content", ar.Content, br.Content, dirResponse)
+		}
+	}
+}
+
+func (c *classifier) content(path, pointer string, a, b spec.HTTPBody, dir direction) {
+	at, bt := contentTypes(a), contentTypes(b)
+	if len(at) == 0 && len(bt) == 0 {
+		return
+	}
+
+	common := make([]string, 0)
+	for _, t := range at {
+		if slices.Contains(bt, t) {
+			common = append(common, t)
+		}
+	}
+
+	// 一方没有内容时与空结构比较，新增的必填字段等依然可以被发现
+	if len(at) == 0 || len(bt) == 0 {
+		var as, bs *jsonschema.Schema
+		ct := ""
+		if len(at) == 0 {
+			ct = bt[0]
+			bs = b[ct].Schema
+			as = emptyLike(bs)
+		} else {
+			ct = at[0]
+			as = a[ct].Schema
+			bs = emptyLike(as)
+		}
+		c.schema(path, pointer+"/"+escapePointer(ct)+"/schema", as, bs, dir)
+		return
+	}
+
+	if len(common) == 0 {
+		c.report.add(LevelBreaking, RuleContentTypeChanged, pointer, path, strings.Join(at, ", "), strings.Join(bt, ", "))
+		return
+	}
+	for _, ct := range common {
+		if a[ct] != nil && b[ct] != nil {
+			c.schema(path, pointer+"/"+escapePointer(ct)+"/schema", a[ct].Schema, b[ct].Schema, dir)
+		}
+	}
+}
+
+// field 字段的新增、删除和必填变化
+func (c *classifier) field(path, pointer string, aExist, bExist, aRequired, bRequired bool, dir direction) {
+	switch {
+	case !aExist && bExist:
+		if bRequired {
+			c.classify(dir, RuleRequiredRequestFieldAdded, LevelBreaking, RuleResponseFieldAdded, LevelNonBreaking, pointer, path)
+		} else {
+			c.classify(dir, RuleRequestFieldAdded, LevelNonBreaking, RuleResponseFieldAdded, LevelNonBreaking, pointer, path)
+		}
+	case aExist && !bExist:
+		c.classify(dir, RuleRequestFieldRemoved, LevelPotentiallyBreaking, RuleResponseFieldRemoved, LevelBreaking, pointer, path)
+	case aExist && bExist && !aRequired && bRequired:
+		c.classify(dir, RuleRequestFieldBecameRequired, LevelBreaking, RuleResponseFieldBecameRequired, LevelNonBreaking, pointer, path)
+	case aExist && bExist && aRequired && !bRequired:
+		c.classify(dir, RuleRequestFieldBecameOptional, LevelNonBreaking, RuleResponseFieldBecameOptional, LevelPotentiallyBreaking, pointer, path)
+	}
+}
+
+// classify 按流向记录请求或响应对应的规则，dirBoth时规则按请求记录、级别取较严重者
+func (c *classifier) classify(dir direction, requestRule, requestLevel, responseRule, responseLevel, pointer string, params ...string) {
+	rule := requestRule
+	if dir == dirResponse {
+		rule = responseRule
+	}
+	level := dir.pick(requestLevel, responseLevel)
+	if dir == dirBoth && levelWeight[responseLevel] > levelWeight[requestLevel] {
+		rule = responseRule
+	}
+	c.report.add(level, rule, pointer, params...)
+}
+
+func (c *classifier) schema(path, pointer string, a, b *jsonschema.Schema, dir direction) {
+	if a == nil || b == nil {
+		return
+	}
+
+	if a.Reference != nil || b.Reference != nil {
+		ar, br := "", ""
+		if a.Reference != nil {
+			ar = *a.Reference
+		}
+		if b.Reference != nil {
+			br = *b.Reference
+		}
+		if ar != br {
+			c.report.add(LevelPotentiallyBreaking, RuleReferenceChanged, pointer, path, ar, br)
+		}
+		return
+	}
+
+	if !c.types(path, pointer, a, b, dir) {
+		return
+	}
+	c.enum(path, pointer, a, b, dir)
+	c.constraints(path, pointer, a, b, dir)
+
+	for _, name := range mergedPropertyNames(a, b) {
+		as, aExist := a.Properties[name]
+		bs, bExist := b.Properties[name]
+		p, ptr := path+"."+name, pointer+"/properties/"+escapePointer(name)
+		c.field(p, ptr, aExist, bExist, slices.Contains(a.Required, name), slices.Contains(b.Required, name), dir)
+		if aExist && bExist {
+			c.schema(p, ptr, as, bs, dir)
+		}
+	}
+
+	if a.Items != nil && b.Items != nil && !a.Items.IsBool() && !b.Items.IsBool() {
+		c.schema(path+"[]", pointer+"/items", a.Items.Value(), b.Items.Value(), dir)
+	}
+
+	for _, of := range []struct {
+		name string
+		a, b jsonschema.Of
+	}{{"allOf", a.AllOf, b.AllOf}, {"anyOf", a.AnyOf, b.AnyOf}, {"oneOf", a.OneOf, b.OneOf}} {
+		n := len(of.a)
+		if len(of.b) < n {
+			n = len(of.b)
+		}
+		for i := 0; i < n; i++ {
+			c.schema(fmt.Sprintf("%s.%s[%d]", path, of.name, i), fmt.Sprintf("%s/%s/%d", pointer, of.name, i), of.a[i], of.b[i], dir)
+		}
+		if len(of.a) != len(of.b) {
+			c.report.add(LevelPotentiallyBreaking, RuleTypeChanged, pointer+"/"+of.name, path, of.name+"("+strconv.Itoa(len(of.a))+")", of.name+"("+strconv.Itoa(len(of.b))+")")
+		}
+	}
+}
+
+// types 比较类型，类型不兼容时返回false，不再比较子结构
+func (c *classifier) types(path, pointer string, a, b *jsonschema.Schema, dir direction) bool {
+	at, bt := a.Type.List(), b.Type.List()
+	if len(at) == 0 || len(bt) == 0 || sameSet(at, bt) {
+		return true
+	}
+
+	before, after := strings.Join(at, "|"), strings.Join(bt, "|")
+	switch {
+	case acceptsAll(at, bt):
+		// 新类型能接受的值是旧类型的子集
+		c.classify(dir, RuleTypeNarrowed, LevelBreaking, RuleTypeNarrowed, LevelNonBreaking, pointer+"/type", path, before, after)
+		return true
+	case acceptsAll(bt, at):
+		c.classify(dir, RuleTypeWidened, LevelNonBreaking, RuleTypeWidened, LevelPotentiallyBreaking, pointer+"/type", path, before, after)
+		return true
+	}
+	c.report.add(LevelBreaking, RuleTypeChanged, pointer+"/type", path, before, after)
+	return false
+}
+
+func (c *classifier) enum(path, pointer string, a, b *jsonschema.Schema, dir direction) {
+	if len(a.Enum) == 0 && len(b.Enum) == 0 {
+		return
+	}
+	if len(a.Enum) == 0 {
+		c.constraint(path, pointer+"/enum", "enum", "", stringifyEnum(b.Enum), true, dir)
+		return
+	}
+	if len(b.Enum) == 0 {
+		c.constraint(path, pointer+"/enum", "enum", stringifyEnum(a.Enum), "", false, dir)
+		return
+	}
+
+	av, bv := enumValues(a.Enum), enumValues(b.Enum)
+	for _, v := range av {
+		if !slices.Contains(bv, v) {
+			c.classify(dir, RuleEnumValueRemoved, LevelBreaking, RuleEnumValueRemoved, LevelNonBreaking, pointer+"/enum", path, v)
+		}
+	}
+	for _, v := range bv {
+		if !slices.Contains(av, v) {
+			c.classify(dir, RuleEnumValueAdded, LevelNonBreaking, RuleEnumValueAdded, LevelPotentiallyBreaking, pointer+"/enum", path, v)
+		}
+	}
+}
+
+func (c *classifier) constraints(path, pointer string, a, b *jsonschema.Schema, dir direction) {
+	// 上限变小或新增上限视为收紧
+	for _, m := range []struct {
+		name string
+		a, b *float64
+	}{
+		{"maxLength", intToFloat(a.MaxLength), intToFloat(b.MaxLength)},
+		{"maxItems", intToFloat(a.MaxItems), intToFloat(b.MaxItems)},
+		{"maxProperties", intToFloat(a.MaxProperties), intToFloat(b.MaxProperties)},
+		{"maximum", a.Maximum, b.Maximum},
+	} {
+		c.bound(path, pointer, m.name, m.a, m.b, true, dir)
+	}
+	// 下限变大或新增下限视为收紧
+	for _, m := range []struct {
+		name string
+		a, b *float64
+	}{
+		{"minLength", intToFloat(a.MinLength), intToFloat(b.MinLength)},
+		{"minItems", intToFloat(a.MinItems), intToFloat(b.MinItems)},
+		{"minProperties", intToFloat(a.MinProperties), intToFloat(b.MinProperties)},
+		{"minimum", a.Minimum, b.Minimum},
+	} {
+		c.bound(path, pointer, m.name, m.a, m.b, false, dir)
+	}
+
+	if a.Pattern != b.Pattern {
+		c.constraint(path, pointer+"/pattern", "pattern", a.Pattern, b.Pattern, b.Pattern != "", dir)
+	}
+
+	an, bn := a.Nullable != nil && *a.Nullable, b.Nullable != nil && *b.Nullable
+	if an != bn {
+		c.constraint(path, pointer+"/nullable", "nullable", strconv.FormatBool(an), strconv.FormatBool(bn), an, dir)
+	}
+}
+
+func (c *classifier) bound(path, pointer, name string, a, b *float64, upper bool, dir direction) {
+	if a == nil && b == nil {
+		return
+	}
+	if a != nil && b != nil && *a == *b {
+		return
+	}
+
+	var narrowed bool
+	switch {
+	case a == nil:
+		narrowed = true
+	case b == nil:
+		narrowed = false
+	case upper:
+		narrowed = *b < *a
+	default:
+		narrowed = *b > *a
+	}
+	c.constraint(path, pointer+"/"+name, name, formatBound(a), formatBound(b), narrowed, dir)
+}
+
+func (c *classifier) constraint(path, pointer, name, before, after string, narrowed bool, dir direction) {
+	if before == "" {
+		before = "-"
+	}
+	if after == "" {
+		after = "-"
+	}
+	if narrowed {
+		c.classify(dir, RuleConstraintNarrowed, LevelBreaking, RuleConstraintNarrowed, LevelNonBreaking, pointer, path, name, before, after)
+	} else {
+		c.classify(dir, RuleConstraintRelaxed, LevelNonBreaking, RuleConstraintRelaxed, LevelPotentiallyBreaking, pointer, path, name, before, after)
+	}
+}
+
+// acceptsAll 类型列表a能接受的值是否包含b能接受的全部值，integer是number的子集
+func acceptsAll(a, b []string) bool {
+	for _, t := range b {
+		if slices.Contains(a, t) {
+			continue
+		}
+		if t == jsonschema.T_INT && slices.Contains(a, jsonschema.T_NUM) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func sameSet(a, b []string) bool {
+	return acceptsAll(a, b) && acceptsAll(b, a) && len(a) == len(b)
+}
+
+func contentTypes(b spec.HTTPBody) []string {
+	list := make([]string, 0, len(b))
+	for k, v := range b {
+		if v != nil && v.Schema != nil {
+			list = append(list, k)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// emptyLike 与s同类型的空结构
+func emptyLike(s *jsonschema.Schema) *jsonschema.Schema {
+	e := &jsonschema.Schema{}
+	if s != nil && s.Type != nil {
+		e.Type = jsonschema.NewSchemaType(s.Type.List()...)
+	}
+	return e
+}
+
+func mergedPropertyNames(a, b *jsonschema.Schema) []string {
+	names := propertyNames(a)
+	for _, v := range propertyNames(b) {
+		if !slices.Contains(names, v) {
+			names = append(names, v)
+		}
+	}
+	return names
+}
+
+func enumValues(list []any) []string {
+	res := make([]string, len(list))
+	for i, v := range list {
+		res[i] = fmt.Sprint(v)
+	}
+	return res
+}
+
+func stringifyEnum(list []any) string {
+	return strings.Join(enumValues(list), ", ")
+}
+
+func intToFloat(v *int64) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+func formatBound(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// escapePointer 按RFC 6901转义JSON Pointer中的特殊字符
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,106 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+)
+
+const breakingOriginal = `[
+	{"type":"apicat-http-url","attrs":{"path":"/users/{id}","method":"get"}},
+	{"type":"apicat-http-request","attrs":{"parameters":{
+		"path":[{"name":"id","required":true,"schema":{"type":"integer"}}],
+		"query":[{"name":"fields","required":false,"schema":{"type":"string"}}]
+	},"content":{"application/json":{"schema":{
+		"type":"object","properties":{
+			"status":{"type":"string","enum":["active","disabled","deleted"]},
+			"age":{"type":"number"}
+		}
+	}}}}},
+	{"type":"apicat-http-response","attrs":{"list":[
+		{"code":200,"name":"ok","content":{"application/json":{"schema":{
+			"type":"object","required":["id"],"properties":{"id":{"type":"integer"},"name":{"type":"string"}}
+		}}}},
+		{"code":404,"name":"not found","content":{"application/json":{"schema":{"type":"object"}}}}
+	]}}
+]`
+
+const breakingTarget = `[
+	{"type":"apicat-http-url","attrs":{"path":"/users/{userID}","method":"get"}},
+	{"type":"apicat-http-request","attrs":{"parameters":{
+		"path":[{"name":"userID","required":true,"schema":{"type":"integer"}}],
+		"query":[{"name":"fields","required":false,"schema":{"type":"string"}}]
+	},"content":{"application/json":{"schema":{
+		"type":"object","required":["email"],"properties":{
+			"status":{"type":"string","enum":["active","disabled"]},
+			"age":{"type":"integer"},
+			"email":{"type":"string"}
+		}
+	}}}}},
+	{"type":"apicat-http-response","attrs":{"list":[
+		{"code":200,"name":"ok","content":{"application/json":{"schema":{
+			"type":"object","properties":{"id":{"type":"integer"},"avatar":{"type":"string"}}
+		}}}}
+	]}}
+]`
+
+func mustCollection(t *testing.T, content string) *spec.Collection {
+	t.Helper()
+	nodes, err := spec.NewCollectionNodesFromJson(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &spec.Collection{Content: nodes}
+}
+
+func TestClassify(t *testing.T) {
+	r := Classify(mustCollection(t, breakingOriginal), mustCollection(t, breakingTarget))
+
+	want := map[string][2]string{
+		"/request/parameters/path/userID":                                   {RulePathParamRenamed, LevelBreaking},
+		"/request/content/application~1json/schema/properties/status/enum":  {RuleEnumValueRemoved, LevelBreaking},
+		"/request/content/application~1json/schema/properties/age/type":     {RuleTypeNarrowed, LevelBreaking},
+		"/request/content/application~1json/schema/properties/email":        {RuleRequiredRequestFieldAdded, LevelBreaking},
+		"/responses/200/content/application~1json/schema/properties/id":     {RuleResponseFieldBecameOptional, LevelPotentiallyBreaking},
+		"/responses/200/content/application~1json/schema/properties/name":   {RuleResponseFieldRemoved, LevelBreaking},
+		"/responses/200/content/application~1json/schema/properties/avatar": {RuleResponseFieldAdded, LevelNonBreaking},
+		"/responses/404": {RuleResponseStatusRemoved, LevelBreaking},
+	}
+
+	got := make(map[string][2]string)
+	for _, c := range r.Changes {
+		got[c.Pointer] = [2]string{c.Rule, c.Level}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d changes, want %d: %v", len(got), len(want), got)
+	}
+	for pointer, w := range want {
+		if got[pointer] != w {
+			t.Errorf("change at %s = %v, want %v", pointer, got[pointer], w)
+		}
+	}
+	if r.Breaking != 6 || r.PotentiallyBreaking != 1 || r.NonBreaking != 1 {
+		t.Errorf("counts = %d/%d/%d, want 6/1/1", r.Breaking, r.PotentiallyBreaking, r.NonBreaking)
+	}
+}
+
+func TestClassifyResponseDirection(t *testing.T) {
+	a := `{"type":"object","properties":{"status":{"type":"string","enum":["a","b"]},"score":{"type":"integer"}}}`
+	b := `{"type":"object","properties":{"status":{"type":"string","enum":["a","b","c"]},"score":{"type":"number"}}}`
+
+	as, _ := jsonschema.NewSchemaFromJson(a)
+	bs, _ := jsonschema.NewSchemaFromJson(b)
+	r := newReport()
+	c := &classifier{report: r}
+	c.schema("response.200.body", "", as, bs, dirResponse)
+
+	if r.Breaking != 0 || r.PotentiallyBreaking != 2 {
+		t.Fatalf("widening a response should be potentially breaking, got %+v", r)
+	}
+
+	r = ClassifyModel(&spec.DefinitionModel{Schema: as}, &spec.DefinitionModel{Schema: bs})
+	if r.PotentiallyBreaking != 2 {
+		t.Fatalf("shared model should use the worse level, got %+v", r)
+	}
+}
@@ -0,0 +1,73 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+)
+
+// DiffResponse 比较两个公共响应的差异，与Diff一样只在target中标记
+func DiffResponse(original, target *spec.DefinitionResponse) error {
+	if original == nil || target == nil {
+		return errors.New("response is nil")
+	}
+	copyOriginal := &spec.DefinitionResponse{}
+	b, err := json.Marshal(original)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, copyOriginal); err != nil {
+		return err
+	}
+
+	if copyOriginal.Name != target.Name || copyOriginal.Description != target.Description {
+		target.XDiff = DIFF_UPDATE
+	}
+	target.Header = diffParameterList(copyOriginal.Header, target.Header)
+	target.Content = diffContent(copyOriginal.Content, target.Content)
+	return nil
+}
+
+// DiffParameter 比较两个全局参数的差异，只在target中标记
+func DiffParameter(original, target *spec.Parameter) error {
+	if original == nil || target == nil {
+		return errors.New("parameter is nil")
+	}
+	copyOriginal := &spec.Parameter{}
+	b, err := json.Marshal(original)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, copyOriginal); err != nil {
+		return err
+	}
+
+	diffParameter(copyOriginal, target)
+	return nil
+}
+
+// ClassifyResponse 对比两个版本的公共响应，规则与接口中的响应一致
+func ClassifyResponse(original, target *spec.DefinitionResponse) *Report {
+	r := newReport()
+	if original == nil || target == nil {
+		return r
+	}
+
+	c := &classifier{report: r}
+	c.parameters("response.header", "/header", original.Header, target.Header, dirResponse)
+	c.content("response.body", "/content", original.Content, target.Content, dirResponse)
+	return r
+}
+
+// ClassifyParameter 对比两个版本的全局参数，规则与接口中的请求参数一致，修改名称视为删除后新增
+func ClassifyParameter(in string, original, target *spec.Parameter) *Report {
+	r := newReport()
+	if original == nil || target == nil {
+		return r
+	}
+
+	c := &classifier{report: r}
+	c.parameters("request."+in, "/"+in, spec.ParameterList{original}, spec.ParameterList{target}, dirRequest)
+	return r
+}
@@ -0,0 +1,59 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+)
+
+func TestClassifyResponse(t *testing.T) {
+	original, err := spec.NewDefinitionResponseFromJson(`{"name":"Error","header":[{"name":"X-Request-ID","schema":{"type":"string"}}],
+		"content":{"application/json":{"schema":{"type":"object","required":["code"],"properties":{"code":{"type":"integer"},"message":{"type":"string"}}}}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := spec.NewDefinitionResponseFromJson(`{"name":"Error",
+		"content":{"application/json":{"schema":{"type":"object","required":["code"],"properties":{"code":{"type":"integer"}}}}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := ClassifyResponse(original, target)
+	want := map[string]string{
+		"/header/X-Request-ID":                                 RuleResponseFieldRemoved,
+		"/content/application~1json/schema/properties/message": RuleResponseFieldRemoved,
+	}
+	if len(r.Changes) != len(want) || r.Breaking != 2 {
+		t.Fatalf("got %+v, want 2 breaking changes", r.Changes)
+	}
+	for _, c := range r.Changes {
+		if want[c.Pointer] != c.Rule {
+			t.Errorf("change at %s = %s, want %s", c.Pointer, c.Rule, want[c.Pointer])
+		}
+	}
+
+	if err := DiffResponse(original, target); err != nil {
+		t.Fatal(err)
+	}
+	if p := target.Header.FindByName("X-Request-ID"); p == nil || p.XDiff != DIFF_REMOVE {
+		t.Errorf("removed header should be kept in target and marked, got %+v", target.Header)
+	}
+}
+
+func TestClassifyParameter(t *testing.T) {
+	schema, _ := jsonschema.NewSchemaFromJson(`{"type":"string"}`)
+	original := &spec.Parameter{Name: "Authorization", Schema: schema}
+	target := &spec.Parameter{Name: "Authorization", Required: true, Schema: schema}
+
+	r := ClassifyParameter("header", original, target)
+	if len(r.Changes) != 1 || r.Changes[0].Rule != RuleRequestFieldBecameRequired || r.Changes[0].Pointer != "/header/Authorization" {
+		t.Fatalf("got %+v, want a parameter that became required", r.Changes)
+	}
+
+	renamed := &spec.Parameter{Name: "X-Token", Schema: schema}
+	r = ClassifyParameter("header", original, renamed)
+	if r.Breaking != 0 || len(r.Changes) != 2 {
+		t.Fatalf("renaming an optional parameter should be removed and added, got %+v", r.Changes)
+	}
+}
@@ -0,0 +1,292 @@
+package diff
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+)
+
+// 项目级差异中接口和模型的状态
+const (
+	StatusAdded   = "added"
+	StatusRemoved = "removed"
+	StatusChanged = "changed"
+)
+
+// EndpointChange 一个接口的差异
+type EndpointChange struct {
+	Status        string   `json:"status"`
+	Method        string   `json:"method"`
+	Path          string   `json:"path"`
+	Title         string   `json:"title"`
+	Changes       []Change `json:"changes"`
+	Compatibility *Report  `json:"compatibility"`
+}
+
+// ModelChange 一个公共模型的差异
+type ModelChange struct {
+	Status        string   `json:"status"`
+	Name          string   `json:"name"`
+	Changes       []Change `json:"changes"`
+	Compatibility *Report  `json:"compatibility"`
+}
+
+// SpecReport 两个项目快照的差异报告
+type SpecReport struct {
+	Endpoints []*EndpointChange `json:"endpoints"`
+	Models    []*ModelChange    `json:"models"`
+}
+
+// HasBreaking 删除接口或接口中有不兼容的修改
+func (r *SpecReport) HasBreaking() bool {
+	for _, e := range r.Endpoints {
+		if e.Status == StatusRemoved || e.Compatibility.HasBreaking() {
+			return true
+		}
+	}
+	return false
+}
+
+// Count 按状态统计接口和模型的数量
+func (r *SpecReport) Count(status string) (endpoints, models int) {
+	for _, e := range r.Endpoints {
+		if e.Status == status {
+			endpoints++
+		}
+	}
+	for _, m := range r.Models {
+		if m.Status == status {
+			models++
+		}
+	}
+	return
+}
+
+// DiffSpec 对比两个项目快照
+// 接口按请求方法和路径匹配，公共模型按名称匹配，不会修改传入的内容
+func DiffSpec(original, target *spec.Spec) (*SpecReport, error) {
+	a, err := flattenSpec(original)
+	if err != nil {
+		return nil, err
+	}
+	b, err := flattenSpec(target)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &SpecReport{
+		Endpoints: make([]*EndpointChange, 0),
+		Models:    make([]*ModelChange, 0),
+	}
+	if err := r.diffEndpoints(a.collections, b.collections); err != nil {
+		return nil, err
+	}
+	if err := r.diffModels(a.models, b.models); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+type flatSpec struct {
+	collections map[string]*spec.Collection
+	models      map[string]*spec.DefinitionModel
+}
+
+// flattenSpec 复制并展开快照，接口和模型都已解引用
+// 同一请求方法和路径有多个接口时只保留第一个
+func flattenSpec(s *spec.Spec) (*flatSpec, error) {
+	res := &flatSpec{
+		collections: make(map[string]*spec.Collection),
+		models:      make(map[string]*spec.DefinitionModel),
+	}
+	if s == nil {
+		return res, nil
+	}
+
+	c := &spec.Spec{}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+
+	definitions := &spec.Definitions{
+		Schemas:   make(spec.DefinitionModels, 0),
+		Responses: make(spec.DefinitionResponses, 0),
+	}
+	var params *spec.GlobalParameters
+	if c.Definitions != nil {
+		for _, m := range c.Definitions.Schemas {
+			definitions.Schemas = append(definitions.Schemas, m.ItemsTreeToList()...)
+		}
+		for _, r := range c.Definitions.Responses {
+			definitions.Responses = append(definitions.Responses, r.ItemsTreeToList()...)
+		}
+	}
+	if c.Globals != nil {
+		params = c.Globals.Parameters
+	}
+
+	collections := make(spec.Collections, 0)
+	for _, v := range c.Collections {
+		for _, item := range v.ItemsTreeToList() {
+			if item.Type == spec.TYPE_HTTP {
+				collections = append(collections, item)
+			}
+		}
+	}
+	if err := collections.DeepDerefAll(params, definitions); err != nil {
+		return nil, err
+	}
+	for _, v := range collections {
+		key := endpointKey(v)
+		if _, ok := res.collections[key]; !ok {
+			res.collections[key] = v
+		}
+	}
+
+	for _, m := range definitions.Schemas {
+		if _, ok := res.models[m.Name]; ok || m.Schema == nil {
+			continue
+		}
+		d := &spec.DefinitionModel{}
+		b, err := json.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, d); err != nil {
+			return nil, err
+		}
+		if err := d.DeepDeref(definitions.Schemas); err != nil {
+			return nil, err
+		}
+		res.models[m.Name] = d
+	}
+	return res, nil
+}
+
+func endpointKey(c *spec.Collection) string {
+	method, path := endpoint(c)
+	return method + " " + path
+}
+
+func endpoint(c *spec.Collection) (method, path string) {
+	if u := c.Content.GetUrl(); u != nil {
+		return strings.ToUpper(u.Attrs.Method), u.Attrs.Path
+	}
+	return "", ""
+}
+
+func (r *SpecReport) diffEndpoints(a, b map[string]*spec.Collection) error {
+	for key, bc := range b {
+		method, path := endpoint(bc)
+		ac, ok := a[key]
+		if !ok {
+			r.Endpoints = append(r.Endpoints, &EndpointChange{
+				Status:        StatusAdded,
+				Method:        method,
+				Path:          path,
+				Title:         bc.Title,
+				Changes:       make([]Change, 0),
+				Compatibility: newReport(),
+			})
+			continue
+		}
+
+		// Classify需要在Diff之前，Diff会修改target
+		compatibility := Classify(ac, bc)
+		if err := Diff(ac, bc); err != nil {
+			return err
+		}
+		changes := Summarize(bc)
+		if ac.Title != bc.Title {
+			changes = append([]Change{{Op: DIFF_UPDATE, Path: "title"}}, changes...)
+		}
+		if len(changes) == 0 && len(compatibility.Changes) == 0 {
+			continue
+		}
+		r.Endpoints = append(r.Endpoints, &EndpointChange{
+			Status:        StatusChanged,
+			Method:        method,
+			Path:          path,
+			Title:         bc.Title,
+			Changes:       changes,
+			Compatibility: compatibility,
+		})
+	}
+	for key, ac := range a {
+		if _, ok := b[key]; ok {
+			continue
+		}
+		method, path := endpoint(ac)
+		r.Endpoints = append(r.Endpoints, &EndpointChange{
+			Status:        StatusRemoved,
+			Method:        method,
+			Path:          path,
+			Title:         ac.Title,
+			Changes:       make([]Change, 0),
+			Compatibility: newReport(),
+		})
+	}
+
+	sort.Slice(r.Endpoints, func(i, j int) bool {
+		if r.Endpoints[i].Path != r.Endpoints[j].Path {
+			return r.Endpoints[i].Path < r.Endpoints[j].Path
+		}
+		return r.Endpoints[i].Method < r.Endpoints[j].Method
+	})
+	return nil
+}
+
+func (r *SpecReport) diffModels(a, b map[string]*spec.DefinitionModel) error {
+	for name, bm := range b {
+		am, ok := a[name]
+		if !ok {
+			r.Models = append(r.Models, &ModelChange{
+				Status:        StatusAdded,
+				Name:          name,
+				Changes:       make([]Change, 0),
+				Compatibility: newReport(),
+			})
+			continue
+		}
+
+		compatibility := ClassifyModel(am, bm)
+		if err := DiffModel(am, bm); err != nil {
+			return err
+		}
+		changes := SummarizeModel(bm)
+		if am.Description != bm.Description {
+			changes = append([]Change{{Op: DIFF_UPDATE, Path: "description"}}, changes...)
+		}
+		if len(changes) == 0 && len(compatibility.Changes) == 0 {
+			continue
+		}
+		r.Models = append(r.Models, &ModelChange{
+			Status:        StatusChanged,
+			Name:          name,
+			Changes:       changes,
+			Compatibility: compatibility,
+		})
+	}
+	for name := range a {
+		if _, ok := b[name]; ok {
+			continue
+		}
+		r.Models = append(r.Models, &ModelChange{
+			Status:        StatusRemoved,
+			Name:          name,
+			Changes:       make([]Change, 0),
+			Compatibility: newReport(),
+		})
+	}
+
+	sort.Slice(r.Models, func(i, j int) bool {
+		return r.Models[i].Name < r.Models[j].Name
+	})
+	return nil
+}
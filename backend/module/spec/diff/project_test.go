@@ -0,0 +1,117 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+)
+
+const projectOriginal = `{
+	"definitions":{"schemas":[
+		{"id":1,"name":"models","type":"category","items":[
+			{"id":2,"name":"User","type":"schema","schema":{"type":"object","x-apicat-orders":["id","name"],"properties":{"id":{"type":"integer"},"name":{"type":"string"}}}}
+		]}
+	]},
+	"collections":[
+		{"id":1,"title":"users","type":"category","items":[
+			{"id":2,"title":"list users","type":"http","content":[
+				{"type":"apicat-http-url","attrs":{"path":"/users","method":"get"}},
+				{"type":"apicat-http-request","attrs":{"parameters":{},"content":{}}},
+				{"type":"apicat-http-response","attrs":{"list":[{"code":200,"name":"ok","content":{"application/json":{"schema":{"$ref":"#/definitions/schemas/2"}}}}]}}
+			]},
+			{"id":3,"title":"delete user","type":"http","content":[
+				{"type":"apicat-http-url","attrs":{"path":"/users/{id}","method":"delete"}},
+				{"type":"apicat-http-request","attrs":{"parameters":{},"content":{}}},
+				{"type":"apicat-http-response","attrs":{"list":[{"code":204,"name":"ok"}]}}
+			]}
+		]},
+		{"id":4,"title":"about","type":"doc","content":[]}
+	]
+}`
+
+const projectTarget = `{
+	"definitions":{"schemas":[
+		{"id":7,"name":"User","type":"schema","schema":{"type":"object","x-apicat-orders":["id","email"],"properties":{"id":{"type":"integer"},"email":{"type":"string"}}}},
+		{"id":8,"name":"Order","type":"schema","schema":{"type":"object","properties":{"id":{"type":"integer"}}}}
+	]},
+	"collections":[
+		{"id":10,"title":"list users","type":"http","content":[
+			{"type":"apicat-http-url","attrs":{"path":"/users","method":"GET"}},
+			{"type":"apicat-http-request","attrs":{"parameters":{},"content":{}}},
+			{"type":"apicat-http-response","attrs":{"list":[{"code":200,"name":"ok","content":{"application/json":{"schema":{"$ref":"#/definitions/schemas/7"}}}}]}}
+		]},
+		{"id":11,"title":"create user","type":"http","content":[
+			{"type":"apicat-http-url","attrs":{"path":"/users","method":"post"}},
+			{"type":"apicat-http-request","attrs":{"parameters":{},"content":{}}},
+			{"type":"apicat-http-response","attrs":{"list":[{"code":201,"name":"ok"}]}}
+		]}
+	]
+}`
+
+func TestDiffSpec(t *testing.T) {
+	original, err := spec.NewSpecFromJson([]byte(projectOriginal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := spec.NewSpecFromJson([]byte(projectTarget))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := DiffSpec(original, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints := make(map[string]*EndpointChange)
+	for _, e := range r.Endpoints {
+		endpoints[e.Method+" "+e.Path] = e
+	}
+	if len(endpoints) != 3 {
+		t.Fatalf("endpoints = %d, want 3", len(r.Endpoints))
+	}
+	if e := endpoints["POST /users"]; e == nil || e.Status != StatusAdded {
+		t.Errorf("POST /users = %+v, want added", e)
+	}
+	if e := endpoints["DELETE /users/{id}"]; e == nil || e.Status != StatusRemoved {
+		t.Errorf("DELETE /users/{id} = %+v, want removed", e)
+	}
+	e := endpoints["GET /users"]
+	if e == nil || e.Status != StatusChanged {
+		t.Fatalf("GET /users = %+v, want changed", e)
+	}
+	changes := make(map[string]string)
+	for _, c := range e.Changes {
+		changes[c.Path] = c.Op
+	}
+	if changes["response.200.body.email"] != DIFF_NEW || changes["response.200.body.name"] != DIFF_REMOVE {
+		t.Errorf("GET /users changes = %v", e.Changes)
+	}
+	if !e.Compatibility.HasBreaking() {
+		t.Error("removed response field should be breaking")
+	}
+
+	models := make(map[string]string)
+	for _, m := range r.Models {
+		models[m.Name] = m.Status
+	}
+	if len(models) != 2 || models["User"] != StatusChanged || models["Order"] != StatusAdded {
+		t.Errorf("models = %v", models)
+	}
+
+	if !r.HasBreaking() {
+		t.Error("report should be breaking")
+	}
+	if added, _ := r.Count(StatusAdded); added != 1 {
+		t.Errorf("added endpoints = %d, want 1", added)
+	}
+
+	// 同一个快照对比没有差异
+	same, err := DiffSpec(target, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(same.Endpoints) != 0 || len(same.Models) != 0 {
+		t.Errorf("diff with self = %+v", same)
+	}
+}
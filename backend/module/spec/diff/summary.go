@@ -0,0 +1,146 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+)
+
+// Change 一处差异，Op为DIFF_NEW、DIFF_REMOVE或DIFF_UPDATE，Path为字段路径
+type Change struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+}
+
+type summary struct {
+	changes []Change
+	seen    map[string]bool
+}
+
+func (s *summary) add(op, path string) {
+	if op == "" || s.seen[path] {
+		return
+	}
+	s.seen[path] = true
+	s.changes = append(s.changes, Change{Op: op, Path: path})
+}
+
+// Summarize 汇总经过Diff标记后的接口差异
+// 需要先调用Diff，新增和删除的字段只记录最外层
+func Summarize(target *spec.Collection) []Change {
+	s := &summary{changes: make([]Change, 0), seen: make(map[string]bool)}
+	if target == nil {
+		return s.changes
+	}
+
+	for _, node := range target.Content {
+		switch node.Node.NodeType() {
+		case spec.NODE_HTTP_URL:
+			s.add(node.ToHttpUrl().Attrs.XDiff, "url")
+		case spec.NODE_HTTP_REQUEST:
+			req := node.ToHttpRequest()
+			if req.Attrs == nil {
+				continue
+			}
+			if req.Attrs.Parameters != nil {
+				params := req.Attrs.Parameters.ToMap()
+				for _, typ := range spec.HttpParameterType {
+					s.parameters("request."+typ, params[typ])
+				}
+			}
+			s.body("request.body", req.Attrs.Content)
+		case spec.NODE_HTTP_RESPONSE:
+			res := node.ToHttpResponse()
+			if res.Attrs == nil {
+				continue
+			}
+			for _, r := range res.Attrs.List {
+				prefix := "response." + strconv.Itoa(r.Code)
+				s.add(r.XDiff, prefix)
+				s.parameters(prefix+".header", r.Header)
+				s.body(prefix+".body", r.Content)
+			}
+		}
+	}
+	return s.changes
+}
+
+// SummarizeModel 汇总经过DiffModel标记后的模型差异
+func SummarizeModel(target *spec.DefinitionModel) []Change {
+	s := &summary{changes: make([]Change, 0), seen: make(map[string]bool)}
+	if target == nil {
+		return s.changes
+	}
+	s.schema("schema", target.Schema)
+	return s.changes
+}
+
+func (s *summary) parameters(prefix string, list spec.ParameterList) {
+	for _, p := range list {
+		path := prefix + "." + p.Name
+		s.add(p.XDiff, path)
+		if p.XDiff == DIFF_NEW || p.XDiff == DIFF_REMOVE {
+			continue
+		}
+		s.schema(path, p.Schema)
+	}
+}
+
+func (s *summary) body(prefix string, b spec.HTTPBody) {
+	for _, v := range b {
+		if v != nil {
+			s.schema(prefix, v.Schema)
+		}
+	}
+}
+
+func (s *summary) schema(path string, js *jsonschema.Schema) {
+	if js == nil {
+		return
+	}
+	if js.XDiff != "" {
+		s.add(js.XDiff, path)
+		if js.XDiff != DIFF_UPDATE {
+			return
+		}
+	}
+
+	for i, v := range js.AllOf {
+		s.schema(fmt.Sprintf("%s.allOf[%d]", path, i), v)
+	}
+	for i, v := range js.AnyOf {
+		s.schema(fmt.Sprintf("%s.anyOf[%d]", path, i), v)
+	}
+	for i, v := range js.OneOf {
+		s.schema(fmt.Sprintf("%s.oneOf[%d]", path, i), v)
+	}
+	for _, name := range propertyNames(js) {
+		s.schema(path+"."+name, js.Properties[name])
+	}
+	if js.Items != nil && !js.Items.IsBool() {
+		s.schema(path+"[]", js.Items.Value())
+	}
+}
+
+// propertyNames 按x-apicat-orders排序属性名，不在排序中的属性按字母序追加
+func propertyNames(js *jsonschema.Schema) []string {
+	names := make([]string, 0, len(js.Properties))
+	seen := make(map[string]bool)
+	for _, v := range js.XOrder {
+		if _, ok := js.Properties[v]; ok && !seen[v] {
+			seen[v] = true
+			names = append(names, v)
+		}
+	}
+	rest := make([]string, 0)
+	for k := range js.Properties {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
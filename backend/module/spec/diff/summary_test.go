@@ -0,0 +1,69 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+)
+
+const summaryOriginal = `[
+	{"type":"apicat-http-url","attrs":{"path":"/users","method":"get"}},
+	{"type":"apicat-http-request","attrs":{"parameters":{"query":[
+		{"name":"page","required":false,"schema":{"type":"integer"}},
+		{"name":"size","required":false,"schema":{"type":"integer"}}
+	]},"content":{}}},
+	{"type":"apicat-http-response","attrs":{"list":[{"code":200,"name":"ok","content":{"application/json":{"schema":{
+		"type":"object","x-apicat-orders":["id","name"],
+		"properties":{"id":{"type":"integer"},"name":{"type":"string"}}
+	}}}}]}}
+]`
+
+const summaryTarget = `[
+	{"type":"apicat-http-url","attrs":{"path":"/members","method":"get"}},
+	{"type":"apicat-http-request","attrs":{"parameters":{"query":[
+		{"name":"page","required":true,"schema":{"type":"integer"}},
+		{"name":"keyword","required":false,"schema":{"type":"string"}}
+	]},"content":{}}},
+	{"type":"apicat-http-response","attrs":{"list":[{"code":200,"name":"ok","content":{"application/json":{"schema":{
+		"type":"object","x-apicat-orders":["id","email"],
+		"properties":{"id":{"type":"integer"},"email":{"type":"string"}}
+	}}}}]}}
+]`
+
+func TestSummarize(t *testing.T) {
+	original, err := spec.NewCollectionNodesFromJson(summaryOriginal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := spec.NewCollectionNodesFromJson(summaryTarget)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &spec.Collection{Content: original}
+	b := &spec.Collection{Content: target}
+	if err := Diff(a, b); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, c := range Summarize(b) {
+		got[c.Path] = c.Op
+	}
+	want := map[string]string{
+		"url":                     DIFF_UPDATE,
+		"request.query.page":      DIFF_UPDATE,
+		"request.query.keyword":   DIFF_NEW,
+		"request.query.size":      DIFF_REMOVE,
+		"response.200.body.email": DIFF_NEW,
+		"response.200.body.name":  DIFF_REMOVE,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Summarize() = %v, want %v", got, want)
+	}
+	for path, op := range want {
+		if got[path] != op {
+			t.Errorf("change of %s = %q, want %q", path, got[path], op)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package schemaconv
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+
+	"golang.org/x/exp/slices"
+)
+
+type goConverter struct {
+	types    map[string]*ast.TypeSpec
+	visiting map[string]bool
+}
+
+// FromGo 解析源码中的第一个结构体，字段名和必填按 json tag 推断
+func FromGo(src string) (*jsonschema.Schema, error) {
+	if !strings.HasPrefix(strings.TrimSpace(src), "package ") {
+		src = "package p\n" + src
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &goConverter{
+		types:    make(map[string]*ast.TypeSpec),
+		visiting: make(map[string]bool),
+	}
+	var (
+		root    *ast.TypeSpec
+		rootDoc *ast.CommentGroup
+	)
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			c.types[ts.Name.Name] = ts
+			if _, ok := ts.Type.(*ast.StructType); ok && root == nil {
+				root = ts
+				rootDoc = ts.Doc
+				if rootDoc == nil {
+					rootDoc = gd.Doc
+				}
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("struct type not found")
+	}
+
+	c.visiting[root.Name.Name] = true
+	s := c.convert(root.Type)
+	s.Title = root.Name.Name
+	s.Description = strings.TrimPrefix(commentText(rootDoc), root.Name.Name+" ")
+	return s, nil
+}
+
+func (c *goConverter) convert(expr ast.Expr) *jsonschema.Schema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return c.ident(t.Name)
+	case *ast.StarExpr:
+		return c.convert(t.X)
+	case *ast.ParenExpr:
+		return c.convert(t.X)
+	case *ast.ArrayType:
+		if id, ok := t.Elt.(*ast.Ident); ok && id.Name == "byte" {
+			return newFormat(jsonschema.T_STR, "byte", "")
+		}
+		return newArray(c.convert(t.Elt))
+	case *ast.MapType:
+		return newMap(c.convert(t.Value))
+	case *ast.SelectorExpr:
+		return selector(t)
+	case *ast.StructType:
+		return c.structType(t)
+	}
+	return jsonschema.NewSchema(jsonschema.T_OBJ)
+}
+
+func (c *goConverter) ident(name string) *jsonschema.Schema {
+	switch name {
+	case "string":
+		return jsonschema.NewSchema(jsonschema.T_STR)
+	case "bool":
+		return jsonschema.NewSchema(jsonschema.T_BOOL)
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32", "byte", "rune", "uintptr":
+		return jsonschema.NewSchema(jsonschema.T_INT)
+	case "int64", "uint64":
+		return newFormat(jsonschema.T_INT, "int64", "")
+	case "float32", "float64":
+		return jsonschema.NewSchema(jsonschema.T_NUM)
+	}
+
+	ts, ok := c.types[name]
+	if !ok || c.visiting[name] {
+		// 未知类型或循环引用
+		return jsonschema.NewSchema(jsonschema.T_OBJ)
+	}
+	c.visiting[name] = true
+	defer delete(c.visiting, name)
+	return c.convert(ts.Type)
+}
+
+func selector(t *ast.SelectorExpr) *jsonschema.Schema {
+	pkg, _ := t.X.(*ast.Ident)
+	if pkg == nil {
+		return jsonschema.NewSchema(jsonschema.T_OBJ)
+	}
+	switch pkg.Name + "." + t.Sel.Name {
+	case "time.Time", "sql.NullTime":
+		return newFormat(jsonschema.T_STR, "date-time", "datetime")
+	case "time.Duration", "sql.NullInt64", "sql.NullInt32", "sql.NullInt16":
+		return jsonschema.NewSchema(jsonschema.T_INT)
+	case "sql.NullFloat64", "decimal.Decimal":
+		return jsonschema.NewSchema(jsonschema.T_NUM)
+	case "sql.NullString":
+		return jsonschema.NewSchema(jsonschema.T_STR)
+	case "sql.NullBool":
+		return jsonschema.NewSchema(jsonschema.T_BOOL)
+	case "uuid.UUID":
+		return newFormat(jsonschema.T_STR, "uuid", "uuid")
+	}
+	return jsonschema.NewSchema(jsonschema.T_OBJ)
+}
+
+func (c *goConverter) structType(st *ast.StructType) *jsonschema.Schema {
+	obj := newObject()
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			if v, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(v)
+			}
+		}
+		jsonName, opts, _ := strings.Cut(tag.Get("json"), ",")
+		if jsonName == "-" && opts == "" {
+			continue
+		}
+
+		// 匿名嵌入且未指定 json 名称时，展开其字段
+		if len(field.Names) == 0 {
+			if jsonName == "" {
+				embedded := c.convert(field.Type)
+				for _, name := range embedded.XOrder {
+					addProperty(obj, name, embedded.Properties[name], slices.Contains(embedded.Required, name))
+				}
+				continue
+			}
+			field.Names = []*ast.Ident{ast.NewIdent(embeddedName(field.Type))}
+		}
+
+		required := !strings.Contains(opts, "omitempty") ||
+			hasRequiredTag(tag.Get("binding")) || hasRequiredTag(tag.Get("validate"))
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			propName := jsonName
+			if propName == "" {
+				propName = name.Name
+			}
+			prop := c.convert(field.Type)
+			if doc := commentText(field.Doc); doc != "" {
+				prop.Description = strings.TrimPrefix(doc, name.Name+" ")
+			} else {
+				prop.Description = commentText(field.Comment)
+			}
+			addProperty(obj, propName, prop, required)
+		}
+	}
+	return obj
+}
+
+func embeddedName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+func hasRequiredTag(v string) bool {
+	for _, rule := range strings.Split(v, ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func commentText(g *ast.CommentGroup) string {
+	if g == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(g.Text()), " ")
+}
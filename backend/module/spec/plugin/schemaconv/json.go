@@ -0,0 +1,172 @@
+package schemaconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+)
+
+var (
+	emailRegexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[a-zA-Z]{2,}$`)
+	uuidRegexp  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// FromJSON 根据示例数据推断结构，数组元素会合并为同一个 items
+func FromJSON(raw []byte) (*jsonschema.Schema, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	s, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected content after JSON value")
+	}
+	if t := s.Type.First(); t != jsonschema.T_OBJ && t != jsonschema.T_ARR {
+		return nil, errors.New("JSON sample must be an object or an array")
+	}
+	resolveNull(s)
+	return s, nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (*jsonschema.Schema, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			obj := newObject()
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				prop, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				addProperty(obj, key.(string), prop, true)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return obj, nil
+		case '[':
+			var items *jsonschema.Schema
+			for dec.More() {
+				item, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				items = mergeSample(items, item)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			if items == nil {
+				items = jsonschema.NewSchema(jsonschema.T_STR)
+			}
+			return newArray(items), nil
+		}
+	case string:
+		return stringSample(v), nil
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return jsonschema.NewSchema(jsonschema.T_INT), nil
+		}
+		return jsonschema.NewSchema(jsonschema.T_NUM), nil
+	case bool:
+		return jsonschema.NewSchema(jsonschema.T_BOOL), nil
+	case nil:
+		return jsonschema.NewSchema(jsonschema.T_NULL), nil
+	}
+	return nil, errors.New("unexpected JSON token")
+}
+
+func stringSample(v string) *jsonschema.Schema {
+	switch {
+	case uuidRegexp.MatchString(v):
+		return newFormat(jsonschema.T_STR, "uuid", "uuid")
+	case emailRegexp.MatchString(v):
+		return newFormat(jsonschema.T_STR, "email", "email")
+	case strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://"):
+		return newFormat(jsonschema.T_STR, "uri", "url")
+	}
+	if _, err := time.Parse(time.RFC3339, v); err == nil {
+		return newFormat(jsonschema.T_STR, "date-time", "datetime")
+	}
+	if _, err := time.Parse(time.DateOnly, v); err == nil {
+		return newFormat(jsonschema.T_STR, "date", "date")
+	}
+	return jsonschema.NewSchema(jsonschema.T_STR)
+}
+
+// mergeSample 合并数组中的两个元素，对象取属性并集、必填取交集
+func mergeSample(a, b *jsonschema.Schema) *jsonschema.Schema {
+	if a == nil {
+		return b
+	}
+	if a.Type.First() == jsonschema.T_NULL {
+		b.Nullable = boolPtr(true)
+		return b
+	}
+	if b.Type.First() == jsonschema.T_NULL {
+		a.Nullable = boolPtr(true)
+		return a
+	}
+
+	at, bt := a.Type.First(), b.Type.First()
+	switch {
+	case at == jsonschema.T_OBJ && bt == jsonschema.T_OBJ:
+		required := make([]string, 0, len(a.Required))
+		for _, name := range a.Required {
+			if _, ok := b.Properties[name]; ok {
+				required = append(required, name)
+			}
+		}
+		for _, name := range b.XOrder {
+			if prop, ok := a.Properties[name]; ok {
+				a.Properties[name] = mergeSample(prop, b.Properties[name])
+			} else {
+				addProperty(a, name, b.Properties[name], false)
+			}
+		}
+		a.Required = required
+	case at == jsonschema.T_ARR && bt == jsonschema.T_ARR:
+		a.Items.SetValue(mergeSample(a.Items.Value(), b.Items.Value()))
+	case at == jsonschema.T_INT && bt == jsonschema.T_NUM:
+		a.Type = b.Type
+	case at == bt && a.Format != b.Format:
+		a.Format, a.XMock = "", ""
+	}
+	return a
+}
+
+// resolveNull 示例中始终为 null 的字段无法推断类型，按可空字符串处理
+func resolveNull(s *jsonschema.Schema) {
+	if s == nil {
+		return
+	}
+	if s.Type.First() == jsonschema.T_NULL {
+		s.Type = jsonschema.NewSchemaType(jsonschema.T_STR)
+		s.Nullable = boolPtr(true)
+	}
+	for _, prop := range s.Properties {
+		resolveNull(prop)
+	}
+	if s.Items != nil && !s.Items.IsBool() {
+		resolveNull(s.Items.Value())
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,106 @@
+// Package schemaconv 将示例 JSON、SQL 建表语句、Go 结构体和 TypeScript 接口确定性地转换为 JSON Schema
+package schemaconv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+)
+
+const (
+	FormatJSON       = "json"
+	FormatSQL        = "sql"
+	FormatGo         = "go"
+	FormatTypeScript = "typescript"
+)
+
+var ErrInvalidSource = errors.New("invalid source")
+
+// Convert 按格式转换源码，解析失败的错误都包装了 ErrInvalidSource
+func Convert(format, source string) (*jsonschema.Schema, error) {
+	var (
+		s   *jsonschema.Schema
+		err error
+	)
+	switch format {
+	case FormatJSON:
+		s, err = FromJSON([]byte(source))
+	case FormatSQL:
+		s, err = FromSQL(source)
+	case FormatGo:
+		s, err = FromGo(source)
+	case FormatTypeScript:
+		s, err = FromTypeScript(source)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSource, err.Error())
+	}
+	return s, nil
+}
+
+func newObject() *jsonschema.Schema {
+	s := jsonschema.NewSchema(jsonschema.T_OBJ)
+	s.Properties = make(map[string]*jsonschema.Schema)
+	return s
+}
+
+func newArray(items *jsonschema.Schema) *jsonschema.Schema {
+	s := jsonschema.NewSchema(jsonschema.T_ARR)
+	s.Items = &jsonschema.ValueOrBoolean[*jsonschema.Schema]{}
+	s.Items.SetValue(items)
+	return s
+}
+
+func newMap(value *jsonschema.Schema) *jsonschema.Schema {
+	s := jsonschema.NewSchema(jsonschema.T_OBJ)
+	s.AdditionalProperties = &jsonschema.ValueOrBoolean[*jsonschema.Schema]{}
+	s.AdditionalProperties.SetValue(value)
+	return s
+}
+
+func newFormat(typ, format, mock string) *jsonschema.Schema {
+	s := jsonschema.NewSchema(typ)
+	s.Format = format
+	s.XMock = mock
+	return s
+}
+
+// addProperty 按声明顺序添加属性，同名属性后者覆盖前者
+func addProperty(obj *jsonschema.Schema, name string, prop *jsonschema.Schema, required bool) {
+	if obj.Properties == nil {
+		obj.Properties = make(map[string]*jsonschema.Schema)
+	}
+	if _, ok := obj.Properties[name]; ok {
+		obj.DelXOrderByName(name)
+		obj.DelRequiredByName(name)
+	}
+	obj.Properties[name] = prop
+	obj.XOrder = append(obj.XOrder, name)
+	if required {
+		obj.Required = append(obj.Required, name)
+	}
+}
+
+// pascalCase user_accounts -> UserAccounts
+func pascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' || r == ' ' || r == '.' {
+			upper = true
+			continue
+		}
+		if upper {
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package schemaconv
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	cases := []struct {
+		format string
+		source string
+		want   string
+	}{
+		{
+			FormatJSON,
+			`{"id": 1, "email": "a@b.io", "price": 9.9, "tags": ["x"], "items": [{"n": 1, "m": null}, {"n": 2.5}], "extra": null}`,
+			`{"properties":{"email":{"type":"string","format":"email","x-apicat-mock":"email"},"extra":{"type":"string","nullable":true},"id":{"type":"integer"},"items":{"items":{"properties":{"m":{"type":"string","nullable":true},"n":{"type":"number"}},"type":"object","required":["n"],"x-apicat-orders":["n","m"]},"type":"array"},"price":{"type":"number"},"tags":{"items":{"type":"string"},"type":"array"}},"type":"object","required":["id","email","price","tags","items","extra"],"x-apicat-orders":["id","email","price","tags","items","extra"]}`,
+		},
+		{
+			FormatSQL,
+			"CREATE TABLE IF NOT EXISTS `user_accounts` (\n" +
+				"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+				"  `name` varchar(64) NOT NULL DEFAULT '' COMMENT 'user''s name',\n" +
+				"  `status` enum('active','banned') DEFAULT 'active',\n" +
+				"  `is_admin` tinyint(1) NOT NULL,\n" +
+				"  `balance` decimal(10, 2),\n" +
+				"  `created_at` datetime,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  KEY `idx_name` (`name`)\n" +
+				") ENGINE=InnoDB COMMENT='accounts';",
+			`{"title":"UserAccounts","description":"accounts","properties":{"balance":{"type":"number"},"created_at":{"type":"string","format":"date-time","x-apicat-mock":"datetime"},"id":{"type":"integer","format":"int64","x-apicat-mock":"autoincrement"},"is_admin":{"type":"boolean"},"name":{"description":"user's name","type":"string","maxLength":64},"status":{"type":"string","enum":["active","banned"]}},"type":"object","required":["id","name","is_admin"],"x-apicat-orders":["id","name","status","is_admin","balance","created_at"]}`,
+		},
+		{
+			FormatGo,
+			"// User 用户\n" +
+				"type User struct {\n" +
+				"\tBase\n" +
+				"\t// Name 名称\n" +
+				"\tName     string            `json:\"name\" binding:\"required\"`\n" +
+				"\tNickname *string           `json:\"nickname,omitempty\"`\n" +
+				"\tTags     []string          `json:\"tags\"`\n" +
+				"\tMeta     map[string]int    `json:\"meta,omitempty\"`\n" +
+				"\tParent   *User             `json:\"parent,omitempty\"`\n" +
+				"\tsecret   string\n" +
+				"\tIgnored  string            `json:\"-\"`\n" +
+				"}\n" +
+				"type Base struct {\n" +
+				"\tID        uint      `json:\"id\"`\n" +
+				"\tCreatedAt time.Time `json:\"created_at\"` // 创建时间\n" +
+				"}",
+			`{"title":"User","description":"用户","properties":{"created_at":{"description":"创建时间","type":"string","format":"date-time","x-apicat-mock":"datetime"},"id":{"type":"integer"},"meta":{"additionalProperties":{"type":"integer"},"type":"object"},"name":{"description":"名称","type":"string"},"nickname":{"type":"string"},"parent":{"type":"object"},"tags":{"items":{"type":"string"},"type":"array"}},"type":"object","required":["id","created_at","name","tags"],"x-apicat-orders":["id","created_at","name","nickname","tags","meta","parent"]}`,
+		},
+		{
+			FormatTypeScript,
+			"type Role = 'admin' | 'member';\n" +
+				"/** A team member */\n" +
+				"export interface Member extends Named {\n" +
+				"  id: number;\n" +
+				"  // member role\n" +
+				"  role: Role;\n" +
+				"  avatar?: string | null;\n" +
+				"  joinedAt: Date;\n" +
+				"  labels: Array<string>;\n" +
+				"  scores: Record<string, number>;\n" +
+				"  address: { city: string; zip?: string };\n" +
+				"  active: true | false;\n" +
+				"}\n" +
+				"interface Named { name: string }",
+			`{"title":"Member","description":"A team member","properties":{"active":{"type":"boolean"},"address":{"properties":{"city":{"type":"string"},"zip":{"type":"string"}},"type":"object","required":["city"],"x-apicat-orders":["city","zip"]},"avatar":{"type":"string","nullable":true},"id":{"type":"number"},"joinedAt":{"type":"string","format":"date-time","x-apicat-mock":"datetime"},"labels":{"items":{"type":"string"},"type":"array"},"name":{"type":"string"},"role":{"description":"member role","type":"string","enum":["admin","member"]},"scores":{"additionalProperties":{"type":"number"},"type":"object"}},"type":"object","required":["name","id","role","joinedAt","labels","scores","address","active"],"x-apicat-orders":["name","id","role","avatar","joinedAt","labels","scores","address","active"]}`,
+		},
+	}
+	for _, c := range cases {
+		s, err := Convert(c.format, c.source)
+		if err != nil {
+			t.Fatalf("Convert(%s) error: %s", c.format, err)
+		}
+		if got := s.ToJson(); got != c.want {
+			t.Errorf("Convert(%s) =\n%s\nwant\n%s", c.format, got, c.want)
+		}
+	}
+
+	invalid := map[string]string{
+		FormatJSON:       `"text"`,
+		FormatSQL:        "SELECT 1",
+		FormatGo:         "func main() {}",
+		FormatTypeScript: "const a = 1",
+	}
+	for format, source := range invalid {
+		if _, err := Convert(format, source); !errors.Is(err, ErrInvalidSource) {
+			t.Errorf("Convert(%s, %q) should fail with ErrInvalidSource, got %v", format, source, err)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package schemaconv
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	createTableRegexp   = regexp.MustCompile("(?is)create\\s+(?:temporary\\s+)?table\\s+(?:if\\s+not\\s+exists\\s+)?([\\w.\"`\\[\\]]+)\\s*\\(")
+	columnCommentRegexp = regexp.MustCompile(`(?is)\bcomment\s+'((?:[^']|'')*)'`)
+	tableCommentRegexp  = regexp.MustCompile(`(?is)\bcomment\s*=?\s*'((?:[^']|'')*)'`)
+	primaryKeyRegexp    = regexp.MustCompile(`(?is)^primary\s+key\s*\(([^)]*)\)`)
+)
+
+// FromSQL 解析第一个 CREATE TABLE 语句，NOT NULL 和主键列视为必填
+func FromSQL(ddl string) (*jsonschema.Schema, error) {
+	loc := createTableRegexp.FindStringSubmatchIndex(ddl)
+	if loc == nil {
+		return nil, errors.New("CREATE TABLE statement not found")
+	}
+	table := unquoteIdent(ddl[loc[2]:loc[3]])
+	if i := strings.LastIndex(table, "."); i != -1 {
+		table = table[i+1:]
+	}
+
+	body, rest, ok := cutParen(ddl[loc[1]:])
+	if !ok {
+		return nil, errors.New("unbalanced parentheses in CREATE TABLE statement")
+	}
+
+	obj := newObject()
+	obj.Title = pascalCase(table)
+	if m := tableCommentRegexp.FindStringSubmatch(strings.SplitN(rest, ";", 2)[0]); m != nil {
+		obj.Description = strings.ReplaceAll(m[1], "''", "'")
+	}
+
+	var primaryKeys []string
+	for _, def := range splitTopLevel(body, ',') {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+		if m := primaryKeyRegexp.FindStringSubmatch(def); m != nil {
+			for _, name := range strings.Split(m[1], ",") {
+				primaryKeys = append(primaryKeys, unquoteIdent(strings.Fields(name)[0]))
+			}
+			continue
+		}
+		if isTableConstraint(def) {
+			continue
+		}
+		name, prop, required := sqlColumn(def)
+		if name == "" {
+			continue
+		}
+		addProperty(obj, name, prop, required)
+	}
+	if len(obj.Properties) == 0 {
+		return nil, errors.New("no column found in CREATE TABLE statement")
+	}
+
+	for _, name := range primaryKeys {
+		if _, ok := obj.Properties[name]; ok && !slices.Contains(obj.Required, name) {
+			obj.Required = append(obj.Required, name)
+		}
+	}
+	return obj, nil
+}
+
+func sqlColumn(def string) (string, *jsonschema.Schema, bool) {
+	fields := strings.Fields(def)
+	if len(fields) < 2 {
+		return "", nil, false
+	}
+	name := unquoteIdent(fields[0])
+	rest := strings.TrimSpace(def[len(fields[0]):])
+
+	// 类型可能带参数，例如 varchar(64)、decimal(10, 2)、enum('a','b')
+	typ := rest
+	if i := strings.IndexFunc(rest, func(r rune) bool { return r == ' ' || r == '(' || r == '\t' || r == '\n' }); i != -1 {
+		typ = rest[:i]
+		rest = strings.TrimSpace(rest[i:])
+	} else {
+		rest = ""
+	}
+	var args string
+	if strings.HasPrefix(rest, "(") {
+		if a, r, ok := cutParen(rest[1:]); ok {
+			args, rest = a, r
+		}
+	}
+
+	upper := strings.ToUpper(rest)
+	prop := sqlType(strings.ToLower(typ), args)
+	if m := columnCommentRegexp.FindStringSubmatch(rest); m != nil {
+		prop.Description = strings.ReplaceAll(m[1], "''", "'")
+	}
+	if strings.Contains(upper, "AUTO_INCREMENT") || strings.Contains(upper, "AUTOINCREMENT") || strings.Contains(upper, "IDENTITY") {
+		prop.XMock = "autoincrement"
+	}
+	required := strings.Contains(upper, "NOT NULL") || strings.Contains(upper, "PRIMARY KEY")
+	return name, prop, required
+}
+
+func sqlType(typ, args string) *jsonschema.Schema {
+	switch typ {
+	case "tinyint":
+		if strings.TrimSpace(args) == "1" {
+			return jsonschema.NewSchema(jsonschema.T_BOOL)
+		}
+		return jsonschema.NewSchema(jsonschema.T_INT)
+	case "bool", "boolean", "bit":
+		return jsonschema.NewSchema(jsonschema.T_BOOL)
+	case "int", "integer", "smallint", "mediumint", "int2", "int4", "serial", "smallserial", "year":
+		return jsonschema.NewSchema(jsonschema.T_INT)
+	case "bigint", "int8", "bigserial":
+		return newFormat(jsonschema.T_INT, "int64", "")
+	case "decimal", "numeric", "float", "float4", "float8", "double", "real", "money":
+		return jsonschema.NewSchema(jsonschema.T_NUM)
+	case "date":
+		return newFormat(jsonschema.T_STR, "date", "date")
+	case "datetime", "timestamp", "timestamptz":
+		return newFormat(jsonschema.T_STR, "date-time", "datetime")
+	case "time", "timetz":
+		return newFormat(jsonschema.T_STR, "time", "time")
+	case "uuid", "uniqueidentifier":
+		return newFormat(jsonschema.T_STR, "uuid", "uuid")
+	case "json", "jsonb":
+		return jsonschema.NewSchema(jsonschema.T_OBJ)
+	case "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary", "bytea":
+		return newFormat(jsonschema.T_STR, "binary", "")
+	case "enum", "set":
+		s := jsonschema.NewSchema(jsonschema.T_STR)
+		for _, v := range splitTopLevel(args, ',') {
+			v = strings.TrimSpace(v)
+			if len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'' {
+				s.Enum = append(s.Enum, strings.ReplaceAll(v[1:len(v)-1], "''", "'"))
+			}
+		}
+		return s
+	}
+
+	s := jsonschema.NewSchema(jsonschema.T_STR)
+	if strings.HasSuffix(typ, "char") || typ == "character" {
+		if n, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64); err == nil && n > 0 {
+			s.MaxLength = &n
+		}
+	}
+	return s
+}
+
+func isTableConstraint(def string) bool {
+	word := strings.ToLower(strings.Fields(def)[0])
+	switch word {
+	case "primary", "key", "index", "unique", "constraint", "foreign", "check", "fulltext", "spatial", "exclude":
+		return true
+	}
+	return false
+}
+
+// cutParen 从左括号之后开始查找匹配的右括号，返回括号内内容和剩余部分
+func cutParen(s string) (string, string, bool) {
+	depth := 1
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[:i], s[i+1:], true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// splitTopLevel 按分隔符切分，忽略括号和引号内的分隔符
+func splitTopLevel(s string, sep byte) []string {
+	var (
+		parts []string
+		depth int
+		quote byte
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func unquoteIdent(s string) string {
+	return strings.Trim(s, "`\"[]")
+}
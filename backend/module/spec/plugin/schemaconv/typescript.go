@@ -0,0 +1,718 @@
+package schemaconv
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+
+	"golang.org/x/exp/slices"
+)
+
+type tsTokenKind int
+
+const (
+	tsIdent tsTokenKind = iota
+	tsString
+	tsNumber
+	tsPunct
+)
+
+type tsToken struct {
+	kind tsTokenKind
+	text string
+	// 紧邻在该 token 之前的注释
+	comment string
+}
+
+// tsType 是解析后的类型表达式，延迟到所有声明收集完成后再转换
+type tsType struct {
+	name    string     // 引用的类型名或内置类型
+	args    []*tsType  // 泛型参数
+	members []tsMember // 对象字面量
+	index   *tsType    // 索引签名的值类型
+	union   []*tsType
+	inter   []*tsType
+	literal any
+	array   bool
+	elem    *tsType
+}
+
+type tsMember struct {
+	name     string
+	typ      *tsType
+	optional bool
+	comment  string
+}
+
+type tsDecl struct {
+	name    string
+	comment string
+	typ     *tsType
+	enum    []any
+}
+
+type tsParser struct {
+	tokens []tsToken
+	pos    int
+}
+
+type tsConverter struct {
+	decls    map[string]*tsDecl
+	visiting map[string]bool
+}
+
+// FromTypeScript 解析源码中的第一个 interface 或对象类型别名，同一源码中的其他声明用于解析引用
+func FromTypeScript(src string) (*jsonschema.Schema, error) {
+	tokens, err := tsTokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &tsParser{tokens: tokens}
+	decls, err := p.parseDecls()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &tsConverter{
+		decls:    make(map[string]*tsDecl),
+		visiting: make(map[string]bool),
+	}
+	var root *tsDecl
+	for _, d := range decls {
+		c.decls[d.name] = d
+		if root == nil && d.typ != nil && (d.typ.members != nil || d.typ.inter != nil) {
+			root = d
+		}
+	}
+	if root == nil {
+		return nil, errors.New("interface or type declaration not found")
+	}
+
+	c.visiting[root.name] = true
+	s := c.convert(root.typ)
+	s.Title = root.name
+	s.Description = root.comment
+	return s, nil
+}
+
+func tsTokenize(src string) ([]tsToken, error) {
+	var (
+		tokens  []tsToken
+		comment string
+		rs      = []rune(src)
+	)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '/':
+			j := i
+			for j < len(rs) && rs[j] != '\n' {
+				j++
+			}
+			comment = cleanComment(string(rs[i+2 : j]))
+			i = j
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			end := strings.Index(string(rs[i+2:]), "*/")
+			if end == -1 {
+				return nil, errors.New("unterminated comment")
+			}
+			body := string(rs[i+2:])[:end]
+			comment = cleanComment(body)
+			i += 2 + len([]rune(body)) + 2
+		case r == '"' || r == '\'' || r == '`':
+			j := i + 1
+			for j < len(rs) && rs[j] != r {
+				if rs[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(rs) {
+				return nil, errors.New("unterminated string")
+			}
+			tokens = append(tokens, tsToken{kind: tsString, text: string(rs[i+1 : j]), comment: comment})
+			comment = ""
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i + 1
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, tsToken{kind: tsNumber, text: string(rs[i:j]), comment: comment})
+			comment = ""
+			i = j
+		case r == '_' || r == '$' || unicode.IsLetter(r):
+			j := i + 1
+			for j < len(rs) && (rs[j] == '_' || rs[j] == '$' || unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j])) {
+				j++
+			}
+			tokens = append(tokens, tsToken{kind: tsIdent, text: string(rs[i:j]), comment: comment})
+			comment = ""
+			i = j
+		default:
+			tokens = append(tokens, tsToken{kind: tsPunct, text: string(r), comment: comment})
+			comment = ""
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func cleanComment(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		l = strings.TrimSpace(l)
+		l = strings.TrimLeft(l, "*")
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.Join(strings.Fields(strings.Join(lines, " ")), " ")
+}
+
+func (p *tsParser) peek() *tsToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *tsParser) next() *tsToken {
+	t := p.peek()
+	if t != nil {
+		p.pos++
+	}
+	return t
+}
+
+func (p *tsParser) is(text string) bool {
+	t := p.peek()
+	return t != nil && t.kind != tsString && t.text == text
+}
+
+func (p *tsParser) accept(text string) bool {
+	if p.is(text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *tsParser) expect(text string) error {
+	if !p.accept(text) {
+		if t := p.peek(); t != nil {
+			return fmt.Errorf("expected %q but found %q", text, t.text)
+		}
+		return fmt.Errorf("expected %q but reached the end", text)
+	}
+	return nil
+}
+
+// skipBalanced 跳过一对成对的符号及其内容
+func (p *tsParser) skipBalanced(open, close string) {
+	depth := 0
+	for t := p.peek(); t != nil; t = p.peek() {
+		p.pos++
+		if t.kind != tsPunct {
+			continue
+		}
+		switch t.text {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth <= 0 {
+				return
+			}
+		}
+	}
+}
+
+func (p *tsParser) parseDecls() ([]*tsDecl, error) {
+	var decls []*tsDecl
+	for t := p.peek(); t != nil; t = p.peek() {
+		if t.kind != tsIdent {
+			p.pos++
+			continue
+		}
+		comment := t.comment
+		for p.is("export") || p.is("declare") || p.is("default") {
+			p.pos++
+		}
+		var (
+			d   *tsDecl
+			err error
+		)
+		if !p.isDecl() {
+			p.pos++
+			continue
+		}
+		switch p.next().text {
+		case "interface":
+			d, err = p.parseInterface()
+		case "type":
+			d, err = p.parseTypeAlias()
+		case "enum":
+			d, err = p.parseEnum()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if d.comment == "" {
+			d.comment = comment
+		}
+		decls = append(decls, d)
+	}
+	return decls, nil
+}
+
+// isDecl 判断当前位置是否为 interface、type 或 enum 声明，避免把属性名当作关键字
+func (p *tsParser) isDecl() bool {
+	if p.pos+2 >= len(p.tokens) || p.tokens[p.pos+1].kind != tsIdent {
+		return false
+	}
+	next := p.tokens[p.pos+2].text
+	switch p.tokens[p.pos].text {
+	case "interface":
+		return next == "{" || next == "<" || next == "extends"
+	case "type":
+		return next == "=" || next == "<"
+	case "enum":
+		return next == "{"
+	}
+	return false
+}
+
+func (p *tsParser) parseInterface() (*tsDecl, error) {
+	name := p.next()
+	if name == nil || name.kind != tsIdent {
+		return nil, errors.New("interface name expected")
+	}
+	if p.is("<") {
+		p.skipBalanced("<", ">")
+	}
+
+	typ := &tsType{}
+	if p.accept("extends") {
+		for {
+			base, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			typ.inter = append(typ.inter, base)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	body, err := p.parseObject()
+	if err != nil {
+		return nil, err
+	}
+	if len(typ.inter) > 0 {
+		typ.inter = append(typ.inter, body)
+	} else {
+		typ = body
+	}
+	return &tsDecl{name: name.text, comment: name.comment, typ: typ}, nil
+}
+
+func (p *tsParser) parseTypeAlias() (*tsDecl, error) {
+	name := p.next()
+	if name == nil || name.kind != tsIdent {
+		return nil, errors.New("type name expected")
+	}
+	if p.is("<") {
+		p.skipBalanced("<", ">")
+	}
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	typ, err := p.parseUnion()
+	if err != nil {
+		return nil, err
+	}
+	p.accept(";")
+	return &tsDecl{name: name.text, comment: name.comment, typ: typ}, nil
+}
+
+func (p *tsParser) parseEnum() (*tsDecl, error) {
+	name := p.next()
+	if name == nil || name.kind != tsIdent {
+		return nil, errors.New("enum name expected")
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	d := &tsDecl{name: name.text, comment: name.comment}
+	next := 0
+	for !p.accept("}") {
+		member := p.next()
+		if member == nil {
+			return nil, errors.New("unterminated enum")
+		}
+		if p.accept("=") {
+			v := p.next()
+			if v == nil {
+				return nil, errors.New("enum value expected")
+			}
+			if v.kind == tsNumber {
+				n, _ := strconv.Atoi(v.text)
+				d.enum = append(d.enum, n)
+				next = n + 1
+			} else {
+				d.enum = append(d.enum, v.text)
+			}
+		} else {
+			d.enum = append(d.enum, next)
+			next++
+		}
+		p.accept(",")
+	}
+	return d, nil
+}
+
+func (p *tsParser) parseObject() (*tsType, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	obj := &tsType{members: []tsMember{}}
+	for !p.accept("}") {
+		t := p.peek()
+		if t == nil {
+			return nil, errors.New("unterminated object type")
+		}
+		comment := t.comment
+		p.accept("readonly")
+
+		// 索引签名 [key: string]: T
+		if p.accept("[") {
+			for !p.accept("]") {
+				if p.next() == nil {
+					return nil, errors.New("unterminated index signature")
+				}
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			v, err := p.parseUnion()
+			if err != nil {
+				return nil, err
+			}
+			obj.index = v
+			p.accept(";")
+			p.accept(",")
+			continue
+		}
+
+		name := p.next()
+		if name == nil || (name.kind != tsIdent && name.kind != tsString && name.kind != tsNumber) {
+			return nil, errors.New("property name expected")
+		}
+		optional := p.accept("?")
+		if p.is("(") || p.is("<") {
+			// 方法签名不属于数据结构
+			for !p.is(";") && !p.is(",") && !p.is("}") && p.peek() != nil {
+				if p.is("{") {
+					p.skipBalanced("{", "}")
+					continue
+				}
+				p.pos++
+			}
+			p.accept(";")
+			p.accept(",")
+			continue
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		typ, err := p.parseUnion()
+		if err != nil {
+			return nil, err
+		}
+		obj.members = append(obj.members, tsMember{name: name.text, typ: typ, optional: optional, comment: comment})
+		p.accept(";")
+		p.accept(",")
+	}
+	return obj, nil
+}
+
+func (p *tsParser) parseUnion() (*tsType, error) {
+	p.accept("|")
+	var types []*tsType
+	for {
+		t, err := p.parseIntersection()
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+		if !p.accept("|") {
+			break
+		}
+	}
+	if len(types) == 1 {
+		return types[0], nil
+	}
+	return &tsType{union: types}, nil
+}
+
+func (p *tsParser) parseIntersection() (*tsType, error) {
+	p.accept("&")
+	var types []*tsType
+	for {
+		t, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+		if !p.accept("&") {
+			break
+		}
+	}
+	if len(types) == 1 {
+		return types[0], nil
+	}
+	return &tsType{inter: types}, nil
+}
+
+func (p *tsParser) parsePostfix() (*tsType, error) {
+	t, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.is("[") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].text == "]" {
+		p.pos += 2
+		t = &tsType{array: true, elem: t}
+	}
+	return t, nil
+}
+
+func (p *tsParser) parsePrimary() (*tsType, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, errors.New("type expected")
+	}
+	switch {
+	case t.kind == tsPunct && t.text == "{":
+		return p.parseObject()
+	case t.kind == tsPunct && t.text == "(":
+		p.pos++
+		inner, err := p.parseUnion()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case t.kind == tsPunct && t.text == "[":
+		// 元组按数组处理，元素类型取第一个
+		p.pos++
+		var elem *tsType
+		for !p.accept("]") {
+			e, err := p.parseUnion()
+			if err != nil {
+				return nil, err
+			}
+			if elem == nil {
+				elem = e
+			}
+			p.accept(",")
+		}
+		if elem == nil {
+			elem = &tsType{name: "any"}
+		}
+		return &tsType{array: true, elem: elem}, nil
+	case t.kind == tsString:
+		p.pos++
+		return &tsType{literal: t.text}, nil
+	case t.kind == tsNumber:
+		p.pos++
+		if n, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return &tsType{literal: n}, nil
+		}
+		f, _ := strconv.ParseFloat(t.text, 64)
+		return &tsType{literal: f}, nil
+	case t.kind == tsIdent:
+		p.pos++
+		name := t.text
+		for p.is(".") {
+			p.pos++
+			if n := p.next(); n != nil {
+				name += "." + n.text
+			}
+		}
+		typ := &tsType{name: name}
+		if p.accept("<") {
+			for !p.accept(">") {
+				arg, err := p.parseUnion()
+				if err != nil {
+					return nil, err
+				}
+				typ.args = append(typ.args, arg)
+				p.accept(",")
+			}
+		}
+		return typ, nil
+	}
+	return nil, fmt.Errorf("unexpected token %q", t.text)
+}
+
+func (c *tsConverter) convert(t *tsType) *jsonschema.Schema {
+	switch {
+	case t.array:
+		return newArray(c.convert(t.elem))
+	case t.members != nil || t.index != nil:
+		obj := newObject()
+		for _, m := range t.members {
+			prop := c.convert(m.typ)
+			if m.comment != "" {
+				prop.Description = m.comment
+			}
+			addProperty(obj, m.name, prop, !m.optional)
+		}
+		if t.index != nil {
+			obj.AdditionalProperties = &jsonschema.ValueOrBoolean[*jsonschema.Schema]{}
+			obj.AdditionalProperties.SetValue(c.convert(t.index))
+		}
+		return obj
+	case t.union != nil:
+		return c.convertUnion(t.union)
+	case t.inter != nil:
+		obj := newObject()
+		for _, part := range t.inter {
+			s := c.convert(part)
+			for _, name := range s.XOrder {
+				addProperty(obj, name, s.Properties[name], slices.Contains(s.Required, name))
+			}
+		}
+		return obj
+	case t.literal != nil:
+		s := literalSchema(t.literal)
+		s.Enum = []any{t.literal}
+		return s
+	}
+	return c.named(t)
+}
+
+func (c *tsConverter) named(t *tsType) *jsonschema.Schema {
+	arg := func(i int) *jsonschema.Schema {
+		if i < len(t.args) {
+			return c.convert(t.args[i])
+		}
+		return jsonschema.NewSchema(jsonschema.T_OBJ)
+	}
+
+	switch t.name {
+	case "string":
+		return jsonschema.NewSchema(jsonschema.T_STR)
+	case "number":
+		return jsonschema.NewSchema(jsonschema.T_NUM)
+	case "bigint":
+		return newFormat(jsonschema.T_INT, "int64", "")
+	case "boolean", "true", "false":
+		return jsonschema.NewSchema(jsonschema.T_BOOL)
+	case "null", "undefined", "void":
+		return jsonschema.NewSchema(jsonschema.T_NULL)
+	case "Date":
+		return newFormat(jsonschema.T_STR, "date-time", "datetime")
+	case "Array", "ReadonlyArray", "Set":
+		return newArray(arg(0))
+	case "Record", "Map":
+		return newMap(arg(1))
+	case "Partial":
+		s := arg(0)
+		s.Required = nil
+		return s
+	case "Required", "Readonly":
+		return arg(0)
+	case "any", "unknown", "object", "Object":
+		return jsonschema.NewSchema(jsonschema.T_OBJ)
+	}
+
+	d, ok := c.decls[t.name]
+	if !ok || c.visiting[t.name] {
+		// 未知类型或循环引用
+		return jsonschema.NewSchema(jsonschema.T_OBJ)
+	}
+	if d.enum != nil {
+		s := literalSchema(d.enum[0])
+		s.Enum = d.enum
+		return s
+	}
+	c.visiting[t.name] = true
+	defer delete(c.visiting, t.name)
+	return c.convert(d.typ)
+}
+
+// convertUnion null 和 undefined 转为 nullable，字面量联合转为 enum
+func (c *tsConverter) convertUnion(types []*tsType) *jsonschema.Schema {
+	var (
+		nullable bool
+		schemas  []*jsonschema.Schema
+	)
+	for _, t := range types {
+		s := c.convert(t)
+		if s.Type.First() == jsonschema.T_NULL {
+			nullable = true
+			continue
+		}
+		schemas = append(schemas, s)
+	}
+
+	var s *jsonschema.Schema
+	switch {
+	case len(schemas) == 0:
+		s = jsonschema.NewSchema(jsonschema.T_STR)
+	case len(schemas) == 1:
+		s = schemas[0]
+	case sameLiteralType(schemas):
+		s = jsonschema.NewSchema(schemas[0].Type.First())
+		for _, v := range schemas {
+			s.Enum = append(s.Enum, v.Enum...)
+		}
+	case samePrimitiveType(schemas):
+		// 例如 true | false
+		s = schemas[0]
+	default:
+		s = &jsonschema.Schema{OneOf: schemas}
+	}
+	if nullable {
+		s.Nullable = boolPtr(true)
+	}
+	return s
+}
+
+func sameLiteralType(schemas []*jsonschema.Schema) bool {
+	typ := schemas[0].Type.First()
+	for _, s := range schemas {
+		if len(s.Enum) == 0 || s.Type.First() != typ {
+			return false
+		}
+	}
+	return true
+}
+
+func samePrimitiveType(schemas []*jsonschema.Schema) bool {
+	typ := schemas[0].Type.First()
+	if typ == jsonschema.T_OBJ || typ == jsonschema.T_ARR {
+		return false
+	}
+	for _, s := range schemas {
+		if len(s.Enum) > 0 || s.Type.First() != typ {
+			return false
+		}
+	}
+	return true
+}
+
+func literalSchema(v any) *jsonschema.Schema {
+	switch v.(type) {
+	case int, int64:
+		return jsonschema.NewSchema(jsonschema.T_INT)
+	case float64:
+		return jsonschema.NewSchema(jsonschema.T_NUM)
+	}
+	return jsonschema.NewSchema(jsonschema.T_STR)
+}
@@ -0,0 +1,96 @@
+package testcase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lookup 按 JSON path 取值，支持 $.a.b、$.list[0]、$.list[-1] 和 $['a.b']
+func lookup(v any, path string) (any, bool, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, seg := range segments {
+		switch x := v.(type) {
+		case map[string]any:
+			if seg.index != nil {
+				return nil, false, nil
+			}
+			val, ok := x[seg.key]
+			if !ok {
+				return nil, false, nil
+			}
+			v = val
+		case []any:
+			if seg.index == nil {
+				if seg.key == "length" {
+					v = float64(len(x))
+					continue
+				}
+				return nil, false, nil
+			}
+			i := *seg.index
+			if i < 0 {
+				i += len(x)
+			}
+			if i < 0 || i >= len(x) {
+				return nil, false, nil
+			}
+			v = x[i]
+		default:
+			return nil, false, nil
+		}
+	}
+	return v, true, nil
+}
+
+type pathSegment struct {
+	key   string
+	index *int
+}
+
+func parsePath(path string) ([]pathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("json path must start with $: %q", path)
+	}
+	var (
+		res []pathSegment
+		s   = path[1:]
+	)
+	for s != "" {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			end := strings.IndexAny(s, ".[")
+			if end == -1 {
+				end = len(s)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid json path %q", path)
+			}
+			res = append(res, pathSegment{key: s[:end]})
+			s = s[end:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid json path %q", path)
+			}
+			inner := s[1:end]
+			s = s[end+1:]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				res = append(res, pathSegment{key: inner[1 : len(inner)-1]})
+				continue
+			}
+			i, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid json path %q", path)
+			}
+			res = append(res, pathSegment{index: &i})
+		default:
+			return nil, fmt.Errorf("invalid json path %q", path)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,402 @@
+package testcase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+)
+
+const (
+	maxResponseBody = 1 << 20
+	maxResultBody   = 4096
+)
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][\w.-]*)\s*\}\}`)
+
+// Endpoint 步骤请求的接口定义
+type Endpoint struct {
+	Method    string
+	Path      string
+	Responses spec.Responses
+}
+
+// Runner 在指定服务地址上执行测试用例
+type Runner struct {
+	Client  *http.Client
+	BaseURL string
+	// Endpoint 根据接口 ID 获取接口定义
+	Endpoint func(ctx context.Context, collectionID uint) (*Endpoint, error)
+}
+
+// Result 测试用例执行结果
+type Result struct {
+	Passed   bool          `json:"passed"`
+	Duration int64         `json:"duration"`
+	Skipped  int           `json:"skipped"`
+	Steps    []*StepResult `json:"steps"`
+}
+
+// StepResult 步骤执行结果，请求失败时 Error 不为空
+type StepResult struct {
+	Name       string             `json:"name"`
+	Method     string             `json:"method"`
+	URL        string             `json:"url"`
+	Status     int                `json:"status,omitempty"`
+	Duration   int64              `json:"duration"`
+	Passed     bool               `json:"passed"`
+	Error      string             `json:"error,omitempty"`
+	Body       string             `json:"body,omitempty"`
+	Assertions []*AssertionResult `json:"assertions"`
+	Variables  map[string]any     `json:"variables,omitempty"`
+}
+
+type AssertionResult struct {
+	Assertion *Assertion `json:"assertion"`
+	Passed    bool       `json:"passed"`
+	Actual    any        `json:"actual,omitempty"`
+	Message   string     `json:"message,omitempty"`
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   any
+	// json 响应体是否为合法 JSON
+	json     bool
+	endpoint *Endpoint
+}
+
+// Run 按顺序执行步骤，某一步失败后跳过后续步骤
+func (r *Runner) Run(ctx context.Context, c *Case) *Result {
+	start := time.Now()
+	vars := make(map[string]any, len(c.Variables))
+	for k, v := range c.Variables {
+		vars[k] = v
+	}
+
+	res := &Result{Passed: true, Steps: make([]*StepResult, 0, len(c.Steps))}
+	for i, step := range c.Steps {
+		sr := r.runStep(ctx, step, vars)
+		res.Steps = append(res.Steps, sr)
+		if !sr.Passed {
+			res.Passed = false
+			res.Skipped = len(c.Steps) - i - 1
+			break
+		}
+	}
+	res.Duration = time.Since(start).Milliseconds()
+	return res
+}
+
+func (r *Runner) runStep(ctx context.Context, step *Step, vars map[string]any) *StepResult {
+	sr := &StepResult{Name: step.Name, Assertions: make([]*AssertionResult, 0, len(step.Assertions))}
+
+	ep, err := r.Endpoint(ctx, step.CollectionID)
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+	if ep == nil {
+		sr.Error = fmt.Sprintf("collection %d does not exist", step.CollectionID)
+		return sr
+	}
+	req, err := r.newRequest(ctx, ep, &step.Request, vars)
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+	sr.Method = req.Method
+	sr.URL = req.URL.String()
+
+	start := time.Now()
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		sr.Duration = time.Since(start).Milliseconds()
+		sr.Error = err.Error()
+		return sr
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	resp.Body.Close()
+	sr.Duration = time.Since(start).Milliseconds()
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+	sr.Status = resp.StatusCode
+	if len(raw) > maxResultBody {
+		sr.Body = string(raw[:maxResultBody])
+	} else {
+		sr.Body = string(raw)
+	}
+
+	rs := &response{status: resp.StatusCode, header: resp.Header, endpoint: ep}
+	if len(bytes.TrimSpace(raw)) > 0 {
+		rs.json = json.Unmarshal(raw, &rs.body) == nil
+	}
+
+	sr.Passed = true
+	for _, a := range step.Assertions {
+		ar := rs.assert(a, vars)
+		sr.Assertions = append(sr.Assertions, ar)
+		if !ar.Passed {
+			sr.Passed = false
+		}
+	}
+
+	for _, e := range step.Extract {
+		v, ok := rs.value(e.From, e.Target)
+		if !ok {
+			sr.Passed = false
+			sr.Error = fmt.Sprintf("variable %q not found in response", e.Name)
+			continue
+		}
+		if sr.Variables == nil {
+			sr.Variables = make(map[string]any)
+		}
+		sr.Variables[e.Name] = v
+		vars[e.Name] = v
+	}
+	return sr
+}
+
+func (r *Runner) newRequest(ctx context.Context, ep *Endpoint, opt *Request, vars map[string]any) (*http.Request, error) {
+	path := ep.Path
+	for k, v := range opt.Path {
+		path = strings.ReplaceAll(path, "{"+k+"}", url.PathEscape(substituteString(v, vars)))
+	}
+	u := strings.TrimSuffix(r.BaseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+	if len(opt.Query) > 0 {
+		q := url.Values{}
+		for k, v := range opt.Query {
+			q.Set(k, substituteString(v, vars))
+		}
+		u += "?" + q.Encode()
+	}
+
+	var body io.Reader
+	if opt.Body != nil {
+		switch b := substitute(opt.Body, vars).(type) {
+		case string:
+			body = strings.NewReader(b)
+		default:
+			raw, err := json.Marshal(b)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(raw)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(ep.Method), u, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range opt.Header {
+		req.Header.Set(k, substituteString(v, vars))
+	}
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+func (rs *response) value(from, target string) (any, bool) {
+	switch from {
+	case AssertStatus:
+		return float64(rs.status), true
+	case AssertHeader:
+		if v := rs.header.Values(target); len(v) > 0 {
+			return v[0], true
+		}
+		return nil, false
+	case AssertJSON:
+		if !rs.json {
+			return nil, false
+		}
+		v, ok, _ := lookup(rs.body, target)
+		return v, ok
+	}
+	return nil, false
+}
+
+func (rs *response) assert(a *Assertion, vars map[string]any) *AssertionResult {
+	ar := &AssertionResult{Assertion: a}
+	if a.Type == AssertSchema {
+		ar.Passed, ar.Message = rs.conform()
+		return ar
+	}
+	if a.Type == AssertJSON && !rs.json {
+		ar.Message = "response body is not JSON"
+		return ar
+	}
+
+	actual, exists := rs.value(a.Type, a.Target)
+	ar.Actual = actual
+	expected := substitute(a.Value, vars)
+	switch a.Operator {
+	case OpExists:
+		ar.Passed = exists
+	case OpNotExists:
+		ar.Passed = !exists
+	default:
+		if !exists {
+			ar.Message = "value not found"
+			return ar
+		}
+		ar.Passed = compare(a.Operator, actual, expected)
+	}
+	if !ar.Passed && ar.Message == "" {
+		ar.Message = fmt.Sprintf("expected %s %v", a.Operator, expected)
+	}
+	return ar
+}
+
+// conform 校验响应体是否符合接口文档中对应状态码的定义
+func (rs *response) conform() (bool, string) {
+	res := rs.endpoint.Responses.FindByCode(rs.status)
+	if res == nil {
+		return false, fmt.Sprintf("status %d is not documented", rs.status)
+	}
+	if len(res.Content) == 0 {
+		return true, ""
+	}
+	for contentType, body := range res.Content {
+		if !strings.Contains(contentType, "json") || body == nil {
+			continue
+		}
+		if !rs.json {
+			return false, "response body is not JSON"
+		}
+		if err := conform(body.Schema, rs.body, "$"); err != nil {
+			return false, err.Error()
+		}
+	}
+	return true, ""
+}
+
+func compare(op string, actual, expected any) bool {
+	switch op {
+	case OpEq:
+		return equal(actual, expected)
+	case OpNe:
+		return !equal(actual, expected)
+	case OpGt, OpGte, OpLt, OpLte:
+		a, ok1 := toFloat(actual)
+		b, ok2 := toFloat(expected)
+		if !ok1 || !ok2 {
+			return false
+		}
+		switch op {
+		case OpGt:
+			return a > b
+		case OpGte:
+			return a >= b
+		case OpLt:
+			return a < b
+		default:
+			return a <= b
+		}
+	case OpContains:
+		switch x := actual.(type) {
+		case string:
+			return strings.Contains(x, fmt.Sprint(expected))
+		case []any:
+			for _, v := range x {
+				if equal(v, expected) {
+					return true
+				}
+			}
+		case map[string]any:
+			_, ok := x[fmt.Sprint(expected)]
+			return ok
+		}
+		return false
+	case OpMatches:
+		re, err := regexp.Compile(fmt.Sprint(expected))
+		return err == nil && re.MatchString(stringify(actual))
+	}
+	return false
+}
+
+func equal(a, b any) bool {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			return x == y
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func stringify(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// substitute 替换值中的变量，字符串恰好为单个变量时保留变量原类型
+func substitute(v any, vars map[string]any) any {
+	switch x := v.(type) {
+	case string:
+		if m := variablePattern.FindStringSubmatch(x); m != nil && m[0] == x {
+			if val, ok := vars[m[1]]; ok {
+				return val
+			}
+		}
+		return substituteString(x, vars)
+	case map[string]any:
+		res := make(map[string]any, len(x))
+		for k, val := range x {
+			res[k] = substitute(val, vars)
+		}
+		return res
+	case []any:
+		res := make([]any, len(x))
+		for i, val := range x {
+			res[i] = substitute(val, vars)
+		}
+		return res
+	}
+	return v
+}
+
+func substituteString(s string, vars map[string]any) string {
+	return variablePattern.ReplaceAllStringFunc(s, func(m string) string {
+		name := variablePattern.FindStringSubmatch(m)[1]
+		if val, ok := vars[name]; ok {
+			if f, ok := val.(float64); ok {
+				return strconv.FormatFloat(f, 'f', -1, 64)
+			}
+			return stringify(val)
+		}
+		return m
+	})
+}
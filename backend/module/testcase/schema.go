@@ -0,0 +1,162 @@
+package testcase
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+)
+
+// conform 校验值是否符合模型定义，返回第一处不符合的位置和原因
+func conform(s *jsonschema.Schema, v any, path string) error {
+	if s == nil || s.Ref() {
+		return nil
+	}
+	if v == nil && s.Nullable != nil && *s.Nullable {
+		return nil
+	}
+
+	for _, sub := range s.AllOf {
+		if err := conform(sub, v, path); err != nil {
+			return err
+		}
+	}
+	for _, of := range []jsonschema.Of{s.AnyOf, s.OneOf} {
+		if len(of) == 0 {
+			continue
+		}
+		var firstErr error
+		for _, sub := range of {
+			if firstErr = conform(sub, v, path); firstErr == nil {
+				break
+			}
+		}
+		if firstErr != nil {
+			return firstErr
+		}
+	}
+
+	if types := s.Type.List(); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if isType(t, v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: expected %v, got %s", path, types, typeOf(v))
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if equal(v, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, v, s.Enum)
+		}
+	}
+
+	switch x := v.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := x[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		names := make([]string, 0, len(x))
+		for name := range x {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok && s.AdditionalProperties != nil && !s.AdditionalProperties.IsBool() {
+				prop = s.AdditionalProperties.Value()
+			}
+			if err := conform(prop, x[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if s.Items != nil && !s.Items.IsBool() {
+			for i, item := range x {
+				if err := conform(s.Items.Value(), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		n := int64(len([]rune(x)))
+		if s.MinLength != nil && n < *s.MinLength {
+			return fmt.Errorf("%s: length %d is less than %d", path, n, *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return fmt.Errorf("%s: length %d is greater than %d", path, n, *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(x) {
+				return fmt.Errorf("%s: %q does not match %s", path, x, s.Pattern)
+			}
+		}
+	case float64:
+		if s.Minimum != nil && x < *s.Minimum {
+			return fmt.Errorf("%s: %v is less than %v", path, x, *s.Minimum)
+		}
+		if s.Maximum != nil && x > *s.Maximum {
+			return fmt.Errorf("%s: %v is greater than %v", path, x, *s.Maximum)
+		}
+	}
+	return nil
+}
+
+func isType(t string, v any) bool {
+	switch t {
+	case jsonschema.T_NULL:
+		return v == nil
+	case jsonschema.T_STR:
+		_, ok := v.(string)
+		return ok
+	case jsonschema.T_BOOL:
+		_, ok := v.(bool)
+		return ok
+	case jsonschema.T_NUM:
+		_, ok := v.(float64)
+		return ok
+	case jsonschema.T_INT:
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case jsonschema.T_OBJ:
+		_, ok := v.(map[string]any)
+		return ok
+	case jsonschema.T_ARR:
+		_, ok := v.([]any)
+		return ok
+	}
+	return true
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return jsonschema.T_NULL
+	case string:
+		return jsonschema.T_STR
+	case bool:
+		return jsonschema.T_BOOL
+	case float64:
+		return jsonschema.T_NUM
+	case map[string]any:
+		return jsonschema.T_OBJ
+	case []any:
+		return jsonschema.T_ARR
+	}
+	return fmt.Sprintf("%T", v)
+}
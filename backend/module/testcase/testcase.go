@@ -0,0 +1,174 @@
+package testcase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	AssertStatus = "status"
+	AssertHeader = "header"
+	AssertJSON   = "json"
+	AssertSchema = "schema"
+)
+
+const (
+	OpEq        = "eq"
+	OpNe        = "ne"
+	OpGt        = "gt"
+	OpGte       = "gte"
+	OpLt        = "lt"
+	OpLte       = "lte"
+	OpContains  = "contains"
+	OpExists    = "exists"
+	OpNotExists = "notExists"
+	OpMatches   = "matches"
+)
+
+var operators = map[string]bool{
+	OpEq: true, OpNe: true, OpGt: true, OpGte: true, OpLt: true, OpLte: true,
+	OpContains: true, OpExists: true, OpNotExists: true, OpMatches: true,
+}
+
+// Case 可执行的测试用例，步骤按顺序执行，提取的变量可在后续步骤中以 {{name}} 引用
+type Case struct {
+	Variables map[string]any `json:"variables,omitempty"`
+	Steps     []*Step        `json:"steps"`
+}
+
+// Step 一次接口请求及其断言
+type Step struct {
+	Name string `json:"name"`
+	// CollectionID 请求的接口，为 0 时使用测试用例所属的接口
+	CollectionID uint          `json:"collectionID,omitempty"`
+	Request      Request       `json:"request"`
+	Assertions   []*Assertion  `json:"assertions"`
+	Extract      []*Extraction `json:"extract,omitempty"`
+}
+
+// Request 覆盖接口定义的请求参数
+type Request struct {
+	Path   map[string]string `json:"path,omitempty"`
+	Query  map[string]string `json:"query,omitempty"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   any               `json:"body,omitempty"`
+}
+
+// Assertion 响应断言，Target 为 header 名称或 JSON path，schema 断言校验响应是否符合对应状态码的文档定义
+type Assertion struct {
+	Type     string `json:"type"`
+	Target   string `json:"target,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	Value    any    `json:"value,omitempty"`
+}
+
+// Extraction 从响应中提取变量，From 为 status、header 或 json
+type Extraction struct {
+	Name   string `json:"name"`
+	From   string `json:"from"`
+	Target string `json:"target,omitempty"`
+}
+
+// Parse 解析并校验测试用例
+func Parse(content string) (*Case, error) {
+	var c Case
+	if err := json.Unmarshal([]byte(content), &c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *Case) String() string {
+	b, _ := json.Marshal(c)
+	return string(b)
+}
+
+// Validate 校验测试用例结构，缺省的操作符补为 eq
+func (c *Case) Validate() error {
+	if len(c.Steps) == 0 {
+		return errors.New("steps is empty")
+	}
+	for i, step := range c.Steps {
+		if step == nil {
+			return fmt.Errorf("step %d is empty", i+1)
+		}
+		if strings.TrimSpace(step.Name) == "" {
+			step.Name = fmt.Sprintf("step %d", i+1)
+		}
+		for _, a := range step.Assertions {
+			if err := a.validate(); err != nil {
+				return fmt.Errorf("%s: %w", step.Name, err)
+			}
+		}
+		for _, e := range step.Extract {
+			if err := e.validate(); err != nil {
+				return fmt.Errorf("%s: %w", step.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Assertion) validate() error {
+	if a == nil {
+		return errors.New("assertion is empty")
+	}
+	switch a.Type {
+	case AssertStatus, AssertSchema:
+	case AssertHeader:
+		if a.Target == "" {
+			return errors.New("header assertion requires target")
+		}
+	case AssertJSON:
+		if _, err := parsePath(a.Target); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown assertion type %q", a.Type)
+	}
+	if a.Type == AssertSchema {
+		return nil
+	}
+	if a.Operator == "" {
+		a.Operator = OpEq
+	}
+	if !operators[a.Operator] {
+		return fmt.Errorf("unknown operator %q", a.Operator)
+	}
+	if a.Operator == OpMatches {
+		s, ok := a.Value.(string)
+		if !ok {
+			return errors.New("matches requires a string pattern")
+		}
+		if _, err := regexp.Compile(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Extraction) validate() error {
+	if e == nil || e.Name == "" {
+		return errors.New("extraction requires name")
+	}
+	switch e.From {
+	case AssertStatus:
+	case AssertHeader:
+		if e.Target == "" {
+			return errors.New("header extraction requires target")
+		}
+	case AssertJSON:
+		if _, err := parsePath(e.Target); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown extraction source %q", e.From)
+	}
+	return nil
+}
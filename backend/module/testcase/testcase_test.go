@@ -0,0 +1,154 @@
+package testcase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+)
+
+func TestParse(t *testing.T) {
+	invalid := []string{
+		`{}`,
+		`{"steps": [{"assertions": [{"type": "body"}]}]}`,
+		`{"steps": [{"assertions": [{"type": "json", "target": "data.id"}]}]}`,
+		`{"steps": [{"assertions": [{"type": "status", "operator": "like"}]}]}`,
+		`{"steps": [{"assertions": [{"type": "header", "operator": "matches", "value": "("}]}]}`,
+		`{"steps": [{"extract": [{"name": "id", "from": "body"}]}]}`,
+	}
+	for _, s := range invalid {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%s) should fail", s)
+		}
+	}
+
+	c, err := Parse(`{"steps": [{"assertions": [{"type": "status", "value": 200}]}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Steps[0].Name != "step 1" || c.Steps[0].Assertions[0].Operator != OpEq {
+		t.Errorf("defaults not applied: %s", c)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	var v any
+	json.Unmarshal([]byte(`{"data": {"list": [{"id": 1}, {"id": 2}], "a.b": true}}`), &v)
+
+	cases := map[string]any{
+		"$.data.list[0].id":  float64(1),
+		"$.data.list[-1].id": float64(2),
+		"$.data.list.length": float64(2),
+		"$.data['a.b']":      true,
+	}
+	for path, want := range cases {
+		got, ok, err := lookup(v, path)
+		if err != nil || !ok || got != want {
+			t.Errorf("lookup(%s) = %v, %v, %v, want %v", path, got, ok, err, want)
+		}
+	}
+	if _, ok, _ := lookup(v, "$.data.list[5]"); ok {
+		t.Error("lookup out of range should not exist")
+	}
+}
+
+func TestRunner(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/users":
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Header().Set("X-Token", "t-1")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]any{"id": 7, "name": body["name"]})
+		case r.Method == http.MethodGet && r.URL.Path == "/users/7":
+			if r.Header.Get("Authorization") != "Bearer t-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"id": 7, "name": "Tom", "age": "unknown"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	user := jsonschema.NewSchema(jsonschema.T_OBJ)
+	user.Properties = map[string]*jsonschema.Schema{
+		"id":   jsonschema.NewSchema(jsonschema.T_INT),
+		"name": jsonschema.NewSchema(jsonschema.T_STR),
+		"age":  jsonschema.NewSchema(jsonschema.T_INT),
+	}
+	user.Required = []string{"id", "name"}
+	responses := spec.Responses{{Code: 200, BasicResponse: spec.BasicResponse{Content: spec.HTTPBody{"application/json": {Schema: user}}}}}
+	endpoints := map[uint]*Endpoint{
+		1: {Method: "post", Path: "/users"},
+		2: {Method: "get", Path: "/users/{id}", Responses: responses},
+	}
+	r := &Runner{
+		Client:  srv.Client(),
+		BaseURL: srv.URL,
+		Endpoint: func(ctx context.Context, collectionID uint) (*Endpoint, error) {
+			return endpoints[collectionID], nil
+		},
+	}
+
+	c, err := Parse(`{
+		"variables": {"name": "Tom"},
+		"steps": [
+			{
+				"name": "create",
+				"collectionID": 1,
+				"request": {"body": {"name": "{{name}}"}},
+				"assertions": [
+					{"type": "status", "value": 201},
+					{"type": "json", "target": "$.name", "value": "{{name}}"},
+					{"type": "header", "target": "X-Token", "operator": "exists"}
+				],
+				"extract": [
+					{"name": "id", "from": "json", "target": "$.id"},
+					{"name": "token", "from": "header", "target": "X-Token"}
+				]
+			},
+			{
+				"name": "get",
+				"collectionID": 2,
+				"request": {"path": {"id": "{{id}}"}, "header": {"Authorization": "Bearer {{token}}"}},
+				"assertions": [
+					{"type": "status", "operator": "lt", "value": 300},
+					{"type": "json", "target": "$.id", "value": "{{id}}"},
+					{"type": "schema"}
+				]
+			},
+			{"name": "never", "collectionID": 1}
+		]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := r.Run(context.Background(), c)
+	if res.Passed || res.Skipped != 1 || len(res.Steps) != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if !res.Steps[0].Passed || res.Steps[0].Variables["id"] != float64(7) {
+		t.Errorf("step 1 should pass and extract id: %+v", res.Steps[0])
+	}
+	second := res.Steps[1]
+	if second.URL != srv.URL+"/users/7" || second.Status != 200 {
+		t.Errorf("step 2 request mismatch: %s %d", second.URL, second.Status)
+	}
+	for i, passed := range []bool{true, true, false} {
+		if second.Assertions[i].Passed != passed {
+			t.Errorf("step 2 assertion %d passed = %v, want %v (%s)", i, !passed, passed, second.Assertions[i].Message)
+		}
+	}
+	if msg := second.Assertions[2].Message; msg != `$.age: expected [integer], got string` {
+		t.Errorf("unexpected schema message: %s", msg)
+	}
+}
@@ -0,0 +1,229 @@
+package aiusage
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/aiusage"
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/team"
+	protoaiusage "github.com/apicat/apicat/v2/backend/route/proto/aiusage"
+	aiusagerequest "github.com/apicat/apicat/v2/backend/route/proto/aiusage/request"
+	aiusageresponse "github.com/apicat/apicat/v2/backend/route/proto/aiusage/response"
+	aiusageservice "github.com/apicat/apicat/v2/backend/service/aiusage"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	quotaAuditID   = "ai_quota"
+	pricingAuditID = "ai_pricing"
+)
+
+type aiUsageApiImpl struct{}
+
+func NewAIUsageApi() protoaiusage.AIUsageApi {
+	return &aiUsageApiImpl{}
+}
+
+func (a *aiUsageApiImpl) Report(ctx *gin.Context, opt *aiusagerequest.GetAIUsageReportOption) (*aiusageresponse.AIUsageReport, error) {
+	reportOpt := &aiusage.ReportOption{
+		TeamID:    opt.TeamID,
+		ProjectID: opt.ProjectID,
+		UserID:    opt.UserID,
+		GroupBy:   opt.GroupBy,
+	}
+	if reportOpt.GroupBy == "" {
+		reportOpt.GroupBy = aiusage.GroupByTeam
+	}
+	if opt.StartTime > 0 {
+		reportOpt.StartTime = time.Unix(opt.StartTime, 0)
+	}
+	if opt.EndTime > 0 {
+		reportOpt.EndTime = time.Unix(opt.EndTime, 0)
+	}
+
+	list, err := aiusage.Report(ctx, reportOpt)
+	if err != nil {
+		slog.ErrorContext(ctx, "aiusage.Report", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiUsage.FailedToGetReport"))
+	}
+	pricing, err := aiusageservice.GetPricing(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "aiusageservice.GetPricing", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiUsage.FailedToGetReport"))
+	}
+
+	res := &aiusageresponse.AIUsageReport{
+		GroupBy:  reportOpt.GroupBy,
+		Currency: pricing.Currency,
+		Items:    make([]*aiusageresponse.AIUsageSummary, 0, len(list)),
+	}
+	for _, s := range list {
+		res.Items = append(res.Items, &aiusageresponse.AIUsageSummary{
+			Key:              s.Key,
+			Calls:            s.Calls,
+			PromptTokens:     s.PromptTokens,
+			CompletionTokens: s.CompletionTokens,
+			TotalTokens:      s.PromptTokens + s.CompletionTokens,
+			Cost:             pricing.Cost(s.PromptTokens, s.CompletionTokens),
+		})
+		res.Total.Calls += s.Calls
+		res.Total.PromptTokens += s.PromptTokens
+		res.Total.CompletionTokens += s.CompletionTokens
+	}
+	res.Total.TotalTokens = res.Total.PromptTokens + res.Total.CompletionTokens
+	res.Total.Cost = pricing.Cost(res.Total.PromptTokens, res.Total.CompletionTokens)
+	return res, nil
+}
+
+func (a *aiUsageApiImpl) GetPricing(ctx *gin.Context, _ *ginrpc.Empty) (*aiusageresponse.AIPricing, error) {
+	p, err := aiusageservice.GetPricing(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "aiusageservice.GetPricing", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.GenericError"))
+	}
+	return &aiusageresponse.AIPricing{
+		Currency:        p.Currency,
+		PromptPrice:     p.PromptPrice,
+		CompletionPrice: p.CompletionPrice,
+	}, nil
+}
+
+func (a *aiUsageApiImpl) UpdatePricing(ctx *gin.Context, opt *aiusagerequest.AIPricingOption) (*ginrpc.Empty, error) {
+	before, err := aiusageservice.GetPricing(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "aiusageservice.GetPricing", "err", err)
+		before = &aiusageservice.Pricing{}
+	}
+
+	p := &aiusageservice.Pricing{
+		Currency:        opt.Currency,
+		PromptPrice:     opt.PromptPrice,
+		CompletionPrice: opt.CompletionPrice,
+	}
+	if err := aiusageservice.SetPricing(ctx, p); err != nil {
+		slog.ErrorContext(ctx, "aiusageservice.SetPricing", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiUsage.FailedToUpdatePricing"))
+	}
+
+	auditservice.Record(ctx, &auditservice.Entry{
+		Action:     audit.ActionUpdate,
+		TargetType: audit.TargetSysconfig,
+		TargetID:   pricingAuditID,
+		TargetName: pricingAuditID,
+		Before:     pricingAuditFields(before),
+		After:      pricingAuditFields(p),
+	})
+	return &ginrpc.Empty{}, nil
+}
+
+func (a *aiUsageApiImpl) QuotaList(ctx *gin.Context, _ *ginrpc.Empty) (*aiusageresponse.AIQuotaList, error) {
+	list, err := aiusage.GetQuotas(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "aiusage.GetQuotas", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.GenericError"))
+	}
+
+	res := make(aiusageresponse.AIQuotaList, 0, len(list))
+	for _, q := range list {
+		res = append(res, &aiusageresponse.AIQuota{
+			TeamID:        q.TeamID,
+			DailyTokens:   q.DailyTokens,
+			MonthlyTokens: q.MonthlyTokens,
+			UpdatedAt:     q.UpdatedAt.Unix(),
+		})
+	}
+	return &res, nil
+}
+
+func (a *aiUsageApiImpl) UpdateQuota(ctx *gin.Context, opt *aiusagerequest.AIQuotaOption) (*ginrpc.Empty, error) {
+	if opt.TeamID != "" {
+		t := &team.Team{ID: opt.TeamID}
+		exist, err := t.Get(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "t.Get", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiUsage.FailedToUpdateQuota"))
+		}
+		if !exist {
+			return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("team.DoesNotExist"))
+		}
+	}
+
+	old := &aiusage.AIQuota{TeamID: opt.TeamID}
+	exist, err := old.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "old.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiUsage.FailedToUpdateQuota"))
+	}
+
+	q := &aiusage.AIQuota{
+		TeamID:        opt.TeamID,
+		DailyTokens:   opt.DailyTokens,
+		MonthlyTokens: opt.MonthlyTokens,
+	}
+	if err := q.Save(ctx); err != nil {
+		slog.ErrorContext(ctx, "q.Save", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiUsage.FailedToUpdateQuota"))
+	}
+
+	e := &auditservice.Entry{
+		Action:     audit.ActionCreate,
+		TargetType: audit.TargetSysconfig,
+		TargetID:   quotaAuditID,
+		TargetName: quotaAuditID,
+		After:      quotaAuditFields(q),
+	}
+	if exist {
+		e.Action = audit.ActionUpdate
+		e.Before = quotaAuditFields(old)
+	}
+	auditservice.Record(ctx, e)
+	return &ginrpc.Empty{}, nil
+}
+
+func (a *aiUsageApiImpl) DeleteQuota(ctx *gin.Context, opt *aiusagerequest.DeleteAIQuotaOption) (*ginrpc.Empty, error) {
+	q := &aiusage.AIQuota{TeamID: opt.TeamID}
+	exist, err := q.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "q.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiUsage.FailedToDeleteQuota"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("aiUsage.QuotaDoesNotExist"))
+	}
+
+	if err := q.Delete(ctx); err != nil {
+		slog.ErrorContext(ctx, "q.Delete", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiUsage.FailedToDeleteQuota"))
+	}
+
+	auditservice.Record(ctx, &auditservice.Entry{
+		Action:     audit.ActionDelete,
+		TargetType: audit.TargetSysconfig,
+		TargetID:   quotaAuditID,
+		TargetName: quotaAuditID,
+		Before:     quotaAuditFields(q),
+	})
+	return &ginrpc.Empty{}, nil
+}
+
+func quotaAuditFields(q *aiusage.AIQuota) auditservice.Fields {
+	return auditservice.Fields{
+		"teamID":        q.TeamID,
+		"dailyTokens":   q.DailyTokens,
+		"monthlyTokens": q.MonthlyTokens,
+	}
+}
+
+func pricingAuditFields(p *aiusageservice.Pricing) auditservice.Fields {
+	return auditservice.Fields{
+		"currency":        p.Currency,
+		"promptPrice":     p.PromptPrice,
+		"completionPrice": p.CompletionPrice,
+	}
+}
@@ -0,0 +1,243 @@
+package audit
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/team"
+	"github.com/apicat/apicat/v2/backend/module/cache"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
+	protoaudit "github.com/apicat/apicat/v2/backend/route/proto/audit"
+	auditrequest "github.com/apicat/apicat/v2/backend/route/proto/audit/request"
+	auditresponse "github.com/apicat/apicat/v2/backend/route/proto/audit/response"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+	"github.com/apicat/apicat/v2/backend/utils/onetime_token"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type auditLogApiImpl struct{}
+
+func NewAuditLogApi() protoaudit.AuditLogApi {
+	return &auditLogApiImpl{}
+}
+
+// exportToken 导出链接中保存的筛选条件，TeamID为空表示系统配置日志
+type exportToken struct {
+	TeamID string
+	Filter auditrequest.AuditLogFilterOption
+}
+
+func toListOption(teamID string, f *auditrequest.AuditLogFilterOption) *audit.ListOption {
+	opt := &audit.ListOption{
+		TeamID:     teamID,
+		ProjectID:  f.ProjectID,
+		UserID:     f.UserID,
+		Action:     f.Action,
+		TargetType: f.TargetType,
+		TargetID:   f.TargetID,
+	}
+	if f.StartTime > 0 {
+		opt.StartTime = time.Unix(f.StartTime, 0)
+	}
+	if f.EndTime > 0 {
+		opt.EndTime = time.Unix(f.EndTime, 0)
+	}
+	return opt
+}
+
+// resolveMemberFilter 将团队成员ID转换为用户ID
+func resolveMemberFilter(ctx *gin.Context, teamID string, f *auditrequest.TeamAuditLogFilterOption) error {
+	if f.MemberID == 0 {
+		return nil
+	}
+	tm, err := team.GetMember(ctx, f.MemberID)
+	if err != nil {
+		slog.ErrorContext(ctx, "team.GetMember", "err", err)
+		return ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("auditLog.FailedToGetList"))
+	}
+	if tm.TeamID != teamID {
+		return ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("teamMember.NotInTheTeam"))
+	}
+	f.UserID = tm.UserID
+	return nil
+}
+
+func convertModelAuditLog(l *audit.AuditLog) *auditresponse.AuditLog {
+	changes := l.GetChanges()
+	res := &auditresponse.AuditLog{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        l.ID,
+			CreatedAt: l.CreatedAt.Unix(),
+		},
+		ProjectID: l.ProjectID,
+		Operator: auditresponse.AuditLogOperator{
+			ID:    l.UserID,
+			Name:  l.UserName,
+			Email: l.UserEmail,
+		},
+		Action:     l.Action,
+		TargetType: l.TargetType,
+		TargetID:   l.TargetID,
+		TargetName: l.TargetName,
+		Changes:    make([]*auditresponse.AuditLogChange, len(changes)),
+		IP:         l.IP,
+	}
+	for i, c := range changes {
+		res.Changes[i] = &auditresponse.AuditLogChange{
+			Field:  c.Field,
+			Before: c.Before,
+			After:  c.After,
+		}
+	}
+	return res
+}
+
+func list(ctx *gin.Context, opt *audit.ListOption, page protobase.PaginationOption) (*auditresponse.AuditLogList, error) {
+	if page.Page <= 0 {
+		page.Page = 1
+	}
+	if page.PageSize <= 0 {
+		page.PageSize = 15
+	}
+
+	logs, err := audit.GetAuditLogs(ctx, opt, page.Page, page.PageSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "audit.GetAuditLogs", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("auditLog.FailedToGetList"))
+	}
+	count, err := audit.GetAuditLogsCount(ctx, opt)
+	if err != nil {
+		slog.ErrorContext(ctx, "audit.GetAuditLogsCount", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("auditLog.FailedToGetList"))
+	}
+
+	res := &auditresponse.AuditLogList{
+		PaginationInfo: protobase.PaginationInfo{
+			Count:       int(count),
+			TotalPage:   int(math.Ceil(float64(count) / float64(page.PageSize))),
+			CurrentPage: page.Page,
+		},
+		Items: make([]*auditresponse.AuditLog, len(logs)),
+	}
+	for i, l := range logs {
+		res.Items[i] = convertModelAuditLog(l)
+	}
+	return res, nil
+}
+
+func exportPath(ctx *gin.Context, key string, t *exportToken) (string, error) {
+	ca, err := cache.NewCache(config.Get().Cache.ToCfg())
+	if err != nil {
+		slog.ErrorContext(ctx, "cache.NewCache", "err", err)
+		return "", ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("auditLog.ExportFailed"))
+	}
+	token, err := onetime_token.NewTokenHelper(ca).GenerateToken(key, t, time.Minute)
+	if err != nil {
+		slog.ErrorContext(ctx, "onetime_token.GenerateToken", "err", err)
+		return "", ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("auditLog.ExportFailed"))
+	}
+	return token, nil
+}
+
+// TeamList 团队审计日志列表，仅团队管理员可查看
+func (a *auditLogApiImpl) TeamList(ctx *gin.Context, opt *auditrequest.GetTeamAuditLogListOption) (*auditresponse.AuditLogList, error) {
+	selfTeam := access.GetSelfTeam(ctx)
+	selfTM := access.GetSelfTeamMember(ctx)
+	if selfTM.Role.Lower(team.RoleAdmin) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	if err := resolveMemberFilter(ctx, selfTeam.ID, &opt.TeamAuditLogFilterOption); err != nil {
+		return nil, err
+	}
+	return list(ctx, toListOption(selfTeam.ID, &opt.AuditLogFilterOption), opt.PaginationOption)
+}
+
+// TeamExportPath 获取团队审计日志CSV导出地址
+func (a *auditLogApiImpl) TeamExportPath(ctx *gin.Context, opt *auditrequest.GetTeamAuditLogExportPathOption) (*auditresponse.ExportAuditLog, error) {
+	selfTeam := access.GetSelfTeam(ctx)
+	selfTM := access.GetSelfTeamMember(ctx)
+	if selfTM.Role.Lower(team.RoleAdmin) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	if err := resolveMemberFilter(ctx, selfTeam.ID, &opt.TeamAuditLogFilterOption); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("ExportTeamAuditLog-%d-%d", selfTM.ID, time.Now().Unix())
+	token, err := exportPath(ctx, key, &exportToken{TeamID: selfTeam.ID, Filter: opt.AuditLogFilterOption})
+	if err != nil {
+		return nil, err
+	}
+	return &auditresponse.ExportAuditLog{
+		Path: fmt.Sprintf("/api/teams/%s/audit-logs/export/%s", selfTeam.ID, token),
+	}, nil
+}
+
+// SysList 系统配置审计日志列表
+func (a *auditLogApiImpl) SysList(ctx *gin.Context, opt *auditrequest.GetSysAuditLogListOption) (*auditresponse.AuditLogList, error) {
+	return list(ctx, toListOption("", &opt.AuditLogFilterOption), opt.PaginationOption)
+}
+
+// SysExportPath 获取系统配置审计日志CSV导出地址
+func (a *auditLogApiImpl) SysExportPath(ctx *gin.Context, opt *auditrequest.AuditLogFilterOption) (*auditresponse.ExportAuditLog, error) {
+	key := fmt.Sprintf("ExportSysAuditLog-%d-%d", jwt.GetUser(ctx).ID, time.Now().Unix())
+	token, err := exportPath(ctx, key, &exportToken{Filter: *opt})
+	if err != nil {
+		return nil, err
+	}
+	return &auditresponse.ExportAuditLog{
+		Path: fmt.Sprintf("/api/sysconfigs/audit-logs/export/%s", token),
+	}, nil
+}
+
+// Export 导出审计日志CSV，需返回不同的 Content-Type，单独处理
+func Export(ctx *gin.Context) {
+	opt := &auditrequest.ExportAuditLogCodeOption{}
+	if err := ctx.ShouldBindUri(opt); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ca, err := cache.NewCache(config.Get().Cache.ToCfg())
+	if err != nil {
+		slog.ErrorContext(ctx, "cache.NewCache", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewErr("auditLog.ExportFailed").Error(),
+		})
+		return
+	}
+	tokenHelper := onetime_token.NewTokenHelper(ca)
+
+	t := exportToken{}
+	if !tokenHelper.CheckToken(opt.Code, &t) || t.TeamID != ctx.Param("teamID") {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": i18n.NewErr("auditLog.ExportFailed").Error(),
+		})
+		return
+	}
+	if err := tokenHelper.DelToken(opt.Code); err != nil {
+		slog.ErrorContext(ctx, "tokenHelper.DelToken", "err", err)
+	}
+
+	filename := fmt.Sprintf("audit-logs-%s.csv", time.Now().Format("20060102150405"))
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", "attachment; filename="+filename)
+	ctx.Status(http.StatusOK)
+	if err := auditservice.WriteCSV(ctx, ctx.Writer, toListOption(t.TeamID, &t.Filter)); err != nil {
+		slog.ErrorContext(ctx, "auditservice.WriteCSV", "err", err)
+	}
+}
@@ -2,6 +2,7 @@ package collection
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -9,11 +10,14 @@ import (
 
 	"github.com/apicat/apicat/v2/backend/config"
 	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/collection"
 	"github.com/apicat/apicat/v2/backend/model/iteration"
 	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/proposal"
 	"github.com/apicat/apicat/v2/backend/module/cache"
 	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	"github.com/apicat/apicat/v2/backend/route/middleware/dump"
 	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
 	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
 	collection_proto "github.com/apicat/apicat/v2/backend/route/proto/collection"
@@ -21,9 +25,13 @@ import (
 	collectionrequest "github.com/apicat/apicat/v2/backend/route/proto/collection/request"
 	collectionresponse "github.com/apicat/apicat/v2/backend/route/proto/collection/response"
 	"github.com/apicat/apicat/v2/backend/service/ai"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
 	"github.com/apicat/apicat/v2/backend/service/except"
+	proposalservice "github.com/apicat/apicat/v2/backend/service/proposal"
 	"github.com/apicat/apicat/v2/backend/service/reference"
 	"github.com/apicat/apicat/v2/backend/service/relations"
+	translationservice "github.com/apicat/apicat/v2/backend/service/translation"
 	"github.com/apicat/apicat/v2/backend/utils/onetime_token"
 
 	"github.com/apicat/apicat/v2/backend/module/spec"
@@ -110,6 +118,7 @@ func (cai *collectionApiImpl) Create(ctx *gin.Context, opt *collectionrequest.Cr
 		}
 	}
 
+	recordCollectionAudit(ctx, audit.ActionCreate, c, nil, collectionAuditFields(c))
 	userInfo := jwt.GetUser(ctx)
 	return convertModelCollection(c, userInfo, userInfo), nil
 }
@@ -148,7 +157,7 @@ func (cai *collectionApiImpl) List(ctx *gin.Context, opt *collectionrequest.GetC
 	return &tree, nil
 }
 
-func (cai *collectionApiImpl) Get(ctx *gin.Context, opt *collectionbase.ProjectCollectionIDOption) (*collectionresponse.Collection, error) {
+func (cai *collectionApiImpl) Get(ctx *gin.Context, opt *collectionrequest.GetCollectionOption) (*collectionresponse.Collection, error) {
 	selfP := access.GetSelfProject(ctx)
 
 	c := &collection.Collection{ID: opt.CollectionID, ProjectID: selfP.ID}
@@ -184,10 +193,19 @@ func (cai *collectionApiImpl) Get(ctx *gin.Context, opt *collectionbase.ProjectC
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collection.FailedToGet"))
 	}
 
-	return convertModelCollection(c, cUserInfo, uUserInfo), nil
+	res := convertModelCollection(c, cUserInfo, uUserInfo)
+	t, err := translationservice.GetCollectionTranslation(ctx, c, translationservice.Locale(ctx, opt.Lang))
+	if err != nil {
+		slog.ErrorContext(ctx, "translationservice.GetCollectionTranslation", "err", err)
+	} else if t != nil {
+		res.Title = t.Title
+		res.Content = t.Content
+		res.Lang = t.Locale
+	}
+	return res, nil
 }
 
-func (cai *collectionApiImpl) Update(ctx *gin.Context, opt *collectionrequest.UpdateCollectionOption) (*ginrpc.Empty, error) {
+func (cai *collectionApiImpl) Update(ctx *gin.Context, opt *collectionrequest.UpdateCollectionOption) (*protobase.WriteResult, error) {
 	selfTM := access.GetSelfTeamMember(ctx)
 	selfPM := access.GetSelfProjectMember(ctx)
 	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
@@ -204,6 +222,7 @@ func (cai *collectionApiImpl) Update(ctx *gin.Context, opt *collectionrequest.Up
 		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("collection.DoesNotExist"))
 	}
 
+	before := collectionAuditFields(c)
 	oldRefSchemaIDs, err := reference.ParseRefSchemasFromCollection(c)
 	if err != nil {
 		slog.ErrorContext(ctx, "reference.ParseRefSchemasFromCollection", "err", err)
@@ -229,10 +248,19 @@ func (cai *collectionApiImpl) Update(ctx *gin.Context, opt *collectionrequest.Up
 		}
 	}
 
+	t := &proposalservice.Target{Collection: c}
+	if protected, err := proposalservice.IsProtected(ctx, t); err != nil {
+		slog.ErrorContext(ctx, "proposalservice.IsProtected", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
+	} else if protected {
+		return submitProposal(ctx, t, opt.Title, opt.Content)
+	}
+
 	if err := c.Update(ctx, opt.Title, opt.Content, selfTM.ID); err != nil {
 		slog.ErrorContext(ctx, "c.Update", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
 	}
+	recordCollectionAudit(ctx, audit.ActionUpdate, c, before, collectionAuditFields(c))
 
 	// 编辑文档时更新文档引用关系
 	if c.Type != collection.CategoryType {
@@ -241,7 +269,7 @@ func (cai *collectionApiImpl) Update(ctx *gin.Context, opt *collectionrequest.Up
 		}
 	}
 
-	return &ginrpc.Empty{}, nil
+	return &protobase.WriteResult{Status: protobase.WriteStatusUpdated}, nil
 }
 
 func (cai *collectionApiImpl) Delete(ctx *gin.Context, opt *collectionrequest.DeleteCollectionOption) (*ginrpc.Empty, error) {
@@ -264,6 +292,14 @@ func (cai *collectionApiImpl) Delete(ctx *gin.Context, opt *collectionrequest.De
 		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("collection.DoesNotExist"))
 	}
 
+	if err := proposalservice.CheckCollectionDelete(ctx, c); err != nil {
+		if errors.Is(err, proposalservice.ErrProtected) {
+			return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("proposal.TargetProtected"))
+		}
+		slog.ErrorContext(ctx, "proposalservice.CheckCollectionDelete", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collection.FailedToDelete"))
+	}
+
 	if err := relations.DeleteCollections(ctx, selfPM.ProjectID, c, selfTM); err != nil {
 		slog.ErrorContext(ctx, "relations.DeleteCollections", "err", err)
 		if c.Type == collection.CategoryType {
@@ -271,6 +307,7 @@ func (cai *collectionApiImpl) Delete(ctx *gin.Context, opt *collectionrequest.De
 		}
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collection.FailedToDelete"))
 	}
+	recordCollectionAudit(ctx, audit.ActionDelete, c, collectionAuditFields(c), nil)
 
 	return &ginrpc.Empty{}, nil
 }
@@ -319,10 +356,14 @@ func (cai *collectionApiImpl) Move(ctx *gin.Context, opt *collectionrequest.Move
 			continue
 		}
 
+		before := c.ParentID
 		if err := c.Sort(ctx, opt.Target.ParentID, i+1); err != nil {
 			slog.ErrorContext(ctx, "c.Sort", "err", err)
 			continue
 		}
+		if before != opt.Target.ParentID {
+			recordCollectionAudit(ctx, audit.ActionMove, c, auditservice.Fields{"parentID": before}, auditservice.Fields{"parentID": opt.Target.ParentID})
+		}
 	}
 
 	if opt.Origin.ParentID != opt.Target.ParentID {
@@ -395,6 +436,7 @@ func (cai *collectionApiImpl) Copy(ctx *gin.Context, opt *collectionrequest.Copy
 		}
 	}
 
+	recordCollectionAudit(ctx, audit.ActionCopy, newC, auditservice.Fields{"copyFrom": c.ID}, collectionAuditFields(newC))
 	userInfo := jwt.GetUser(ctx)
 	return convertModelCollection(newC, userInfo, userInfo), nil
 }
@@ -409,7 +451,7 @@ func (cai *collectionApiImpl) Trashes(ctx *gin.Context, opt *protobase.ProjectId
 	}
 
 	// 获取回收站列表
-	collections, err := collection.GetDeletedCollections(ctx, pm.ProjectID)
+	collections, err := collection.GetDeletedCollections(ctx, pm.ProjectID, config.Get().App.TrashDeadline(time.Now()))
 	if err != nil {
 		slog.ErrorContext(ctx, "collection.GetDeletedCollections", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collection.FailedToGetList"))
@@ -455,6 +497,18 @@ func (cai *collectionApiImpl) Restore(ctx *gin.Context, opt *collectionrequest.R
 		}, nil
 	}
 
+	ids := make([]uint, 0, len(deletedCollections))
+	for _, c := range deletedCollections {
+		ids = append(ids, c.ID)
+	}
+	if err := proposalservice.CheckProtected(ctx, selfPM.ProjectID, proposal.TargetCollection, ids...); err != nil {
+		if errors.Is(err, proposalservice.ErrProtected) {
+			return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("proposal.TargetProtected"))
+		}
+		slog.ErrorContext(ctx, "proposalservice.CheckProtected", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collectionHistory.RestoreFailed"))
+	}
+
 	restoreIDs, err := collection.RestoreCollections(ctx, selfTM, deletedCollections)
 	if err != nil {
 		slog.ErrorContext(ctx, "collection.RestoreCollections", "err", err)
@@ -464,6 +518,15 @@ func (cai *collectionApiImpl) Restore(ctx *gin.Context, opt *collectionrequest.R
 	if err := iteration.RestoreIterationApi(ctx, restoreIDs); err != nil {
 		slog.ErrorContext(ctx, "iteration.RestoreIterationApi", "err", err)
 	}
+	restored := make(map[uint]bool, len(restoreIDs))
+	for _, id := range restoreIDs {
+		restored[id] = true
+	}
+	for _, c := range deletedCollections {
+		if restored[c.ID] {
+			recordCollectionAudit(ctx, audit.ActionRestore, c, nil, collectionAuditFields(c))
+		}
+	}
 
 	return &collectionresponse.RestoreNum{
 		Num: len(restoreIDs),
@@ -512,28 +575,102 @@ func (cai *collectionApiImpl) GetExportPath(ctx *gin.Context, opt *collectionreq
 }
 
 func (cai *collectionApiImpl) AIGenerate(ctx *gin.Context, opt *collectionrequest.AIGenerateCollectionOption) (*collectionresponse.Collection, error) {
-	selfTM := access.GetSelfTeamMember(ctx)
+	if err := checkAIGenerateCollection(ctx, opt); err != nil {
+		return nil, err
+	}
+
+	var (
+		c   *collection.Collection
+		err error
+	)
+	if opt.Mode == "code" {
+		c, err = ai.DocGenerateFromCode(ctx, opt.Prompt, nil)
+	} else {
+		c, err = ai.DocGenerate(ctx, opt.Prompt)
+	}
+	if err != nil {
+		if errors.Is(err, aiusage.ErrQuotaExceeded) {
+			return nil, ginrpc.NewError(http.StatusTooManyRequests, i18n.NewErr("aiUsage.QuotaExceeded"))
+		}
+		slog.ErrorContext(ctx, "ai.CreateAPI", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collection.GenerationFailed"))
+	}
+
+	res, rerr := createAIGeneratedCollection(ctx, opt, c)
+	if rerr != nil {
+		return nil, rerr
+	}
+	return res, nil
+}
+
+// AIGenerateStream 以 SSE 流式返回 AI 生成的内容，完成后创建集合并通过 result 事件返回
+func AIGenerateStream(ctx *gin.Context) {
+	opt := &collectionrequest.AIGenerateCollectionOption{}
+	opt.ProjectID = ctx.Param("projectID")
+	if err := ctx.ShouldBindJSON(opt); err != nil {
+		dump.Response(ctx, nil, ginrpc.NewError(http.StatusBadRequest, err))
+		return
+	}
+	if err := checkAIGenerateCollection(ctx, opt); err != nil {
+		dump.Response(ctx, nil, err)
+		return
+	}
+
+	dump.StartEvents(ctx)
+	onDelta := func(delta string) error {
+		return dump.Event(ctx, dump.EventDelta, gin.H{"content": delta})
+	}
+	var (
+		c   *collection.Collection
+		err error
+	)
+	if opt.Mode == "code" {
+		c, err = ai.DocGenerateFromCode(ctx, opt.Prompt, onDelta)
+	} else {
+		c, err = ai.DocGenerateStream(ctx, opt.Prompt, onDelta)
+	}
+	if err != nil {
+		if errors.Is(err, aiusage.ErrQuotaExceeded) {
+			dump.EventErr(ctx, ginrpc.NewError(http.StatusTooManyRequests, i18n.NewErr("aiUsage.QuotaExceeded")))
+			return
+		}
+		slog.ErrorContext(ctx, "ai.DocGenerateStream", "err", err)
+		dump.EventErr(ctx, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collection.GenerationFailed")))
+		return
+	}
+
+	res, rerr := createAIGeneratedCollection(ctx, opt, c)
+	if rerr != nil {
+		dump.EventErr(ctx, rerr)
+		return
+	}
+	dump.Event(ctx, dump.EventResult, res)
+}
+
+// checkAIGenerateCollection 生成前检查权限和父级分类
+func checkAIGenerateCollection(ctx *gin.Context, opt *collectionrequest.AIGenerateCollectionOption) *ginrpc.Error {
 	selfPM := access.GetSelfProjectMember(ctx)
 	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
-		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+		return ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
 	}
 
 	if opt.ParentID != 0 {
 		parentC := &collection.Collection{ID: opt.ParentID, ProjectID: selfPM.ProjectID}
 		exist, err := parentC.Get(ctx)
 		if err != nil {
-			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collection.GenerationFailed"))
+			return ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collection.GenerationFailed"))
 		}
 		if !exist {
-			return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("category.DoesNotExist"))
+			return ginrpc.NewError(http.StatusNotFound, i18n.NewErr("category.DoesNotExist"))
 		}
 	}
+	return nil
+}
 
-	c, err := ai.DocGenerate(ctx, opt.Prompt)
-	if err != nil {
-		slog.ErrorContext(ctx, "ai.CreateAPI", "err", err)
-		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collection.GenerationFailed"))
-	}
+// createAIGeneratedCollection 保存生成的集合，并按需加入迭代
+func createAIGeneratedCollection(ctx *gin.Context, opt *collectionrequest.AIGenerateCollectionOption, c *collection.Collection) (*collectionresponse.Collection, *ginrpc.Error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
 
 	c.ProjectID = selfPM.ProjectID
 	c.ParentID = opt.ParentID
@@ -565,6 +702,7 @@ func (cai *collectionApiImpl) AIGenerate(ctx *gin.Context, opt *collectionreques
 		}
 	}
 
+	recordCollectionAudit(ctx, audit.ActionCreate, c, nil, collectionAuditFields(c))
 	userInfo := jwt.GetUser(ctx)
 	return convertModelCollection(c, userInfo, userInfo), nil
 }
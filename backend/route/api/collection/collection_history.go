@@ -8,6 +8,7 @@ import (
 
 	"github.com/apicat/apicat/v2/backend/i18n"
 	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/collection"
 	"github.com/apicat/apicat/v2/backend/model/project"
 	"github.com/apicat/apicat/v2/backend/module/spec/diff"
@@ -17,6 +18,8 @@ import (
 	collectionbase "github.com/apicat/apicat/v2/backend/route/proto/collection/base"
 	collectionrequest "github.com/apicat/apicat/v2/backend/route/proto/collection/request"
 	collectionresponse "github.com/apicat/apicat/v2/backend/route/proto/collection/response"
+	"github.com/apicat/apicat/v2/backend/service/compatibility"
+	proposalservice "github.com/apicat/apicat/v2/backend/service/proposal"
 	"github.com/apicat/apicat/v2/backend/service/relations"
 
 	"github.com/apicat/ginrpc"
@@ -118,7 +121,7 @@ func (srv *collectionHistoryApiImpl) Get(ctx *gin.Context, opt *collectionreques
 	return convertModelCollectionHistory(ch, userInfo), nil
 }
 
-func (srv *collectionHistoryApiImpl) Restore(ctx *gin.Context, opt *collectionrequest.CollectionHistoryIDOption) (*ginrpc.Empty, error) {
+func (srv *collectionHistoryApiImpl) Restore(ctx *gin.Context, opt *collectionrequest.CollectionHistoryIDOption) (*protobase.WriteResult, error) {
 	selfTM := access.GetSelfTeamMember(ctx)
 	selfPM := access.GetSelfProjectMember(ctx)
 	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
@@ -151,12 +154,24 @@ func (srv *collectionHistoryApiImpl) Restore(ctx *gin.Context, opt *collectionre
 		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("collectionHistory.DoesNotExist"))
 	}
 
+	t := &proposalservice.Target{Collection: c}
+	if protected, err := proposalservice.IsProtected(ctx, t); err != nil {
+		slog.ErrorContext(ctx, "proposalservice.IsProtected", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collectionHistory.RestoreFailed"))
+	} else if protected {
+		return submitProposal(ctx, t, ch.Title, ch.Content)
+	}
+
+	before := collectionAuditFields(c)
 	if err := ch.Restore(ctx, c, selfTM); err != nil {
 		slog.ErrorContext(ctx, "ch.Restore", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collectionHistory.RestoreFailed"))
 	}
+	after := collectionAuditFields(c)
+	after["historyID"] = ch.ID
+	recordCollectionAudit(ctx, audit.ActionRestore, c, before, after)
 
-	return &ginrpc.Empty{}, nil
+	return &protobase.WriteResult{Status: protobase.WriteStatusUpdated}, nil
 }
 
 func (srv *collectionHistoryApiImpl) Diff(ctx *gin.Context, opt *collectionrequest.DiffCollectionHistoriesOption) (*collectionresponse.DiffCollectionHistories, error) {
@@ -257,6 +272,8 @@ func (srv *collectionHistoryApiImpl) Diff(ctx *gin.Context, opt *collectionreque
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collectionHistory.DiffFailed"))
 	}
 
+	// diff.Diff会修改targetDoc，需要先做兼容性检查
+	breakingChanges := compatibility.Collection(ctx, originalDoc, targetDoc)
 	if err := diff.Diff(originalDoc, targetDoc); err != nil {
 		slog.ErrorContext(ctx, "diff.Diff", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collectionHistory.DiffFailed"))
@@ -277,7 +294,8 @@ func (srv *collectionHistoryApiImpl) Diff(ctx *gin.Context, opt *collectionreque
 	targetCH.Content = string(targetContentStr)
 
 	return &collectionresponse.DiffCollectionHistories{
-		Doc1: convertModelCollectionHistory(originalCH, originalCHUserInfo),
-		Doc2: convertModelCollectionHistory(targetCH, targetCHUserInfo),
+		Doc1:            convertModelCollectionHistory(originalCH, originalCHUserInfo),
+		Doc2:            convertModelCollectionHistory(targetCH, targetCHUserInfo),
+		BreakingChanges: breakingChanges,
 	}, nil
 }
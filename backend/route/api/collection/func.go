@@ -1,12 +1,29 @@
 package collection
 
 import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/collection"
 	"github.com/apicat/apicat/v2/backend/model/user"
+	"github.com/apicat/apicat/v2/backend/module/testcase"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
 	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
 	collectionbase "github.com/apicat/apicat/v2/backend/route/proto/collection/base"
 	collectionresponse "github.com/apicat/apicat/v2/backend/route/proto/collection/response"
 	projectbase "github.com/apicat/apicat/v2/backend/route/proto/project/base"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+	notifyservice "github.com/apicat/apicat/v2/backend/service/notification"
+	proposalservice "github.com/apicat/apicat/v2/backend/service/proposal"
+	"github.com/apicat/apicat/v2/backend/service/testrun"
+	webhookservice "github.com/apicat/apicat/v2/backend/service/webhook"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
 )
 
 func convertModelCollection(c *collection.Collection, cUserInfo, uUserInfo *user.User) *collectionresponse.Collection {
@@ -96,3 +113,110 @@ func buildTree(parentID uint, collections []*collection.Collection, selectCIDs [
 
 	return result
 }
+
+func collectionAuditFields(c *collection.Collection) auditservice.Fields {
+	return auditservice.Fields{
+		"title":    c.Title,
+		"type":     c.Type,
+		"parentID": c.ParentID,
+		"content":  c.Content,
+	}
+}
+
+func recordCollectionAudit(ctx *gin.Context, action string, c *collection.Collection, before, after auditservice.Fields) {
+	e := &auditservice.Entry{
+		Action:     action,
+		TargetType: audit.TargetCollection,
+		TargetID:   c.ID,
+		TargetName: c.Title,
+		Before:     before,
+		After:      after,
+	}
+	auditservice.Record(ctx, e)
+	webhookservice.Notify(ctx, c.ProjectID, e)
+	notifyservice.Notify(ctx, c.ProjectID, e, func() *notifyservice.Detail {
+		return collectionNotifyDetail(ctx, action, c, before)
+	})
+}
+
+// collectionNotifyDetail 文档修改时通知中附带接口差异和历史记录链接
+func collectionNotifyDetail(ctx *gin.Context, action string, c *collection.Collection, before auditservice.Fields) *notifyservice.Detail {
+	if action != audit.ActionUpdate || c.Type == collection.CategoryType {
+		return nil
+	}
+
+	d := &notifyservice.Detail{Link: notifyservice.CollectionHistoryLink(c.ProjectID, c.ID, 0)}
+	if ch, err := collection.GetLatestCollectionHistory(ctx, c.ID); err != nil {
+		slog.ErrorContext(ctx, "collection.GetLatestCollectionHistory", "err", err)
+	} else if ch != nil {
+		d.Link = notifyservice.CollectionHistoryLink(c.ProjectID, c.ID, ch.ID)
+	}
+
+	if old, ok := before["content"].(string); ok {
+		changes, err := notifyservice.CollectionChanges(old, c.Content)
+		if err != nil {
+			slog.ErrorContext(ctx, "notifyservice.CollectionChanges", "err", err)
+		} else {
+			d.Changes = changes
+		}
+	}
+	return d
+}
+
+// submitProposal 修改需要审批的文档时提交为修改提案，返回提案ID
+func submitProposal(ctx *gin.Context, t *proposalservice.Target, title, content string) (*protobase.WriteResult, error) {
+	p, err := proposalservice.Submit(ctx, access.GetSelfProject(ctx), t, access.GetSelfTeamMember(ctx), title, "", content)
+	if err != nil {
+		slog.ErrorContext(ctx, "proposalservice.Submit", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.SubmitFailed"))
+	}
+	if p == nil {
+		return &protobase.WriteResult{Status: protobase.WriteStatusUpdated}, nil
+	}
+	return &protobase.WriteResult{
+		Status:     protobase.WriteStatusProposalSubmitted,
+		ProposalID: p.ID,
+		Message:    i18n.NewTran("proposal.Submitted").Translate(ctx),
+	}, nil
+}
+
+func convertModelTestCase(t *collection.TestCase) *collectionresponse.TestCaseDetail {
+	res := &collectionresponse.TestCaseDetail{
+		ID:      t.ID,
+		Title:   t.Title,
+		Content: t.Content,
+	}
+	if t.Spec != "" {
+		var c testcase.Case
+		if err := json.Unmarshal([]byte(t.Spec), &c); err == nil {
+			res.Spec = &c
+		}
+	}
+	return res
+}
+
+// getTestCase 获取属于指定接口的测试用例
+func getTestCase(ctx context.Context, projectID string, collectionID, testCaseID uint) (*collection.TestCase, error) {
+	t := &collection.TestCase{ID: testCaseID}
+	exist, err := t.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "t.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.FailedToGet"))
+	}
+	if !exist || t.ProjectID != projectID || t.CollectionID != collectionID {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("testCase.DoesNotExist"))
+	}
+	return t, nil
+}
+
+func testCaseTarget(ctx context.Context, projectID string, serverID uint) (*testrun.Target, error) {
+	t, exist, err := testrun.GetTarget(ctx, projectID, serverID)
+	if err != nil {
+		slog.ErrorContext(ctx, "testrun.GetTarget", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.FailedToRun"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("testCase.ServerDoesNotExist"))
+	}
+	return t, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/apicat/apicat/v2/backend/config"
 	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/collection"
 	"github.com/apicat/apicat/v2/backend/model/project"
 	"github.com/apicat/apicat/v2/backend/model/share"
@@ -20,6 +21,7 @@ import (
 	collectionbase "github.com/apicat/apicat/v2/backend/route/proto/collection/base"
 	collectionrequest "github.com/apicat/apicat/v2/backend/route/proto/collection/request"
 	collectionresponse "github.com/apicat/apicat/v2/backend/route/proto/collection/response"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
 	"github.com/apicat/apicat/v2/backend/utils/password"
 
 	"github.com/apicat/ginrpc"
@@ -115,6 +117,7 @@ func (csai *collectionShareApiImpl) Switch(ctx *gin.Context, opt *collectionrequ
 		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("collection.DoesNotExist"))
 	}
 
+	before := auditservice.Fields{"shared": c.ShareKey != ""}
 	if opt.Status {
 		if c.ShareKey == "" {
 			c.ShareKey = password.RandomPassword(4)
@@ -138,6 +141,15 @@ func (csai *collectionShareApiImpl) Switch(ctx *gin.Context, opt *collectionrequ
 		}
 	}
 
+	auditservice.Record(ctx, &auditservice.Entry{
+		Action:     audit.ActionUpdate,
+		TargetType: audit.TargetCollectionShare,
+		TargetID:   c.ID,
+		TargetName: c.Title,
+		Before:     before,
+		After:      auditservice.Fields{"shared": c.ShareKey != ""},
+	})
+
 	return &collectionresponse.CollectionShareData{
 		CollectionPublicIDOption: collectionbase.CollectionPublicIDOption{
 			CollectionPublicID: c.PublicID,
@@ -177,11 +189,20 @@ func (csai *collectionShareApiImpl) Reset(ctx *gin.Context, opt *collectionbase.
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("share.SharedKeyResetFailed"))
 	}
 
+	before := auditservice.Fields{"secretKey": c.ShareKey}
 	c.ShareKey = password.RandomPassword(4)
 	if err := c.UpdateShareKey(ctx); err != nil {
 		slog.ErrorContext(ctx, "c.UpdateShareKey", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("share.SharedKeyResetFailed"))
 	}
+	auditservice.Record(ctx, &auditservice.Entry{
+		Action:     audit.ActionReset,
+		TargetType: audit.TargetCollectionShare,
+		TargetID:   c.ID,
+		TargetName: c.Title,
+		Before:     before,
+		After:      auditservice.Fields{"secretKey": c.ShareKey},
+	})
 
 	return &protobase.SecretKeyOption{
 		SecretKey: c.ShareKey,
@@ -1,12 +1,14 @@
 package collection
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 
 	"github.com/apicat/apicat/v2/backend/i18n"
 	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/job"
 	"github.com/apicat/apicat/v2/backend/model/project"
 	"github.com/apicat/apicat/v2/backend/route/middleware/access"
 	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
@@ -16,7 +18,10 @@ import (
 	"github.com/apicat/apicat/v2/backend/route/proto/collection/request"
 	"github.com/apicat/apicat/v2/backend/route/proto/collection/response"
 	"github.com/apicat/apicat/v2/backend/service/ai"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+	jobservice "github.com/apicat/apicat/v2/backend/service/job"
 	"github.com/apicat/apicat/v2/backend/service/relations"
+	"github.com/apicat/apicat/v2/backend/service/testrun"
 
 	"github.com/apicat/ginrpc"
 	"github.com/gin-gonic/gin"
@@ -28,7 +33,8 @@ func NewTestCaseApi() collection_proto.TestCaseApi {
 	return &testCaseApiImpl{}
 }
 
-func (ts *testCaseApiImpl) Generate(ctx *gin.Context, opt *request.GenerateTestCaseOption) (*ginrpc.Empty, error) {
+func (ts *testCaseApiImpl) Generate(ctx *gin.Context, opt *request.GenerateTestCaseOption) (*response.TestCaseGeneration, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
 	selfPM := access.GetSelfProjectMember(ctx)
 	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
 		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
@@ -43,38 +49,36 @@ func (ts *testCaseApiImpl) Generate(ctx *gin.Context, opt *request.GenerateTestC
 	if !exist {
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("collection.DoesNotExist"))
 	}
-	sc, err := relations.CollectionDerefWithSpec(ctx, c)
-	if err != nil {
-		slog.ErrorContext(ctx, "CollectionDerefWithSpec", "err", err)
-		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.GenerationFailed"))
-	}
 
-	apiSummary, err := ai.APISummarize(ctx, sc)
+	// 同一接口同时只运行一个生成任务
+	active, err := job.GetActiveJob(ctx, selfPM.ProjectID, job.TypeTestCaseGenerate, c.ID)
 	if err != nil {
-		slog.ErrorContext(ctx, "ai.APISummarize", "err", err)
+		slog.ErrorContext(ctx, "job.GetActiveJob", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.GenerationFailed"))
 	}
+	if active != nil {
+		return &response.TestCaseGeneration{JobID: active.ID}, nil
+	}
 
-	TestCaseRecordList := make([]string, 0)
 	if opt.Regenerate {
 		collection.DelAllTestCases(ctx, selfPM.ProjectID)
-	} else {
-		if testCases, err := collection.GetTestCases(ctx, selfPM.ProjectID, opt.CollectionID); err == nil {
-			for _, testCase := range testCases {
-				TestCaseRecordList = append(TestCaseRecordList, testCase.Title)
-			}
-		}
 	}
 
-	testCaseList, err := ai.TestCaseListGenerate(ctx, apiSummary, TestCaseRecordList, opt.Prompt)
-	if err != nil {
-		slog.ErrorContext(ctx, "ai.TestCaseListGenerate", "err", err)
+	j := &job.Job{
+		Type:      job.TypeTestCaseGenerate,
+		ProjectID: selfPM.ProjectID,
+		TargetID:  c.ID,
+		MemberID:  selfTM.ID,
+	}
+	if err := jobservice.Enqueue(ctx, j, jobservice.TestCasePayload{
+		Language: jwt.GetUser(ctx).Language,
+		Prompt:   opt.Prompt,
+	}); err != nil {
+		slog.ErrorContext(ctx, "jobservice.Enqueue", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.GenerationFailed"))
 	}
 
-	go ai.TestCaseGenerate(jwt.GetUser(ctx).Language, selfPM.ProjectID, apiSummary, c.ID, testCaseList)
-
-	return nil, nil
+	return &response.TestCaseGeneration{JobID: j.ID}, nil
 }
 
 func (ts *testCaseApiImpl) List(ctx *gin.Context, opt *base.ProjectCollectionIDOption) (*response.TestCaseList, error) {
@@ -83,7 +87,8 @@ func (ts *testCaseApiImpl) List(ctx *gin.Context, opt *base.ProjectCollectionIDO
 	if testCases, err := collection.GetTestCases(ctx, selfPM.ProjectID, opt.CollectionID); err == nil {
 		for _, testCase := range testCases {
 			testCaseList = append(testCaseList, &response.TestCase{
-				Title: testCase.Title,
+				Title:      testCase.Title,
+				Executable: testCase.Spec != "",
 				IdCreateTimeInfo: protobase.IdCreateTimeInfo{
 					ID:        testCase.ID,
 					CreatedAt: testCase.CreatedAt.Unix(),
@@ -91,10 +96,16 @@ func (ts *testCaseApiImpl) List(ctx *gin.Context, opt *base.ProjectCollectionIDO
 			})
 		}
 	}
-	return &response.TestCaseList{
-		Generating: ai.TestCaseGeneratingStatus(selfPM.ProjectID, opt.CollectionID),
-		Records:    testCaseList,
-	}, nil
+	res := &response.TestCaseList{
+		Records: testCaseList,
+	}
+	if active, err := job.GetActiveJob(ctx, selfPM.ProjectID, job.TypeTestCaseGenerate, opt.CollectionID); err != nil {
+		slog.ErrorContext(ctx, "job.GetActiveJob", "err", err)
+	} else if active != nil {
+		res.Generating = true
+		res.JobID = active.ID
+	}
+	return res, nil
 }
 
 func (ts *testCaseApiImpl) Get(ctx *gin.Context, opt *request.GetTestCaseOption) (*response.TestCaseDetail, error) {
@@ -109,11 +120,7 @@ func (ts *testCaseApiImpl) Get(ctx *gin.Context, opt *request.GetTestCaseOption)
 	if !exist {
 		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("testCase.DoesNotExist"))
 	}
-	return &response.TestCaseDetail{
-		ID:      t.ID,
-		Title:   t.Title,
-		Content: t.Content,
-	}, nil
+	return convertModelTestCase(t), nil
 }
 
 func (ts *testCaseApiImpl) Regenerate(ctx *gin.Context, opt *request.RegenerateTestCaseOption) (*response.TestCaseDetail, error) {
@@ -156,8 +163,11 @@ func (ts *testCaseApiImpl) Regenerate(ctx *gin.Context, opt *request.RegenerateT
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.RegenerationFailed"))
 	}
 
-	result, err := ai.TestCaseDetailRegenerate(t, jwt.GetUser(ctx).Language, apiSummary, opt.Prompt)
+	result, err := ai.TestCaseDetailRegenerate(ctx, t, jwt.GetUser(ctx).Language, apiSummary, opt.Prompt)
 	if err != nil {
+		if errors.Is(err, aiusage.ErrQuotaExceeded) {
+			return nil, ginrpc.NewError(http.StatusTooManyRequests, i18n.NewErr("aiUsage.QuotaExceeded"))
+		}
 		slog.ErrorContext(ctx, "ai.TestCaseDetailRegenerate", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.RegenerationFailed"))
 	}
@@ -174,16 +184,17 @@ func (ts *testCaseApiImpl) Regenerate(ctx *gin.Context, opt *request.RegenerateT
 		result.Output,
 	)
 
-	if err := t.Update(ctx, result.Purpose, content); err != nil {
+	// 重新生成的可执行步骤不合法时保留原有步骤
+	spec := result.Spec
+	if spec == "" {
+		spec = t.Spec
+	}
+	if err := t.Update(ctx, result.Purpose, content, spec); err != nil {
 		slog.ErrorContext(ctx, "t.Update", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.RegenerationFailed"))
 	}
 
-	return &response.TestCaseDetail{
-		ID:      t.ID,
-		Title:   t.Title,
-		Content: t.Content,
-	}, nil
+	return convertModelTestCase(t), nil
 }
 
 func (ts *testCaseApiImpl) Delete(ctx *gin.Context, opt *request.DeleteTestCaseOption) (*ginrpc.Empty, error) {
@@ -210,3 +221,82 @@ func (ts *testCaseApiImpl) Delete(ctx *gin.Context, opt *request.DeleteTestCaseO
 	}
 	return &ginrpc.Empty{}, nil
 }
+
+func (ts *testCaseApiImpl) UpdateSpec(ctx *gin.Context, opt *request.UpdateTestCaseSpecOption) (*response.TestCaseDetail, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	t, err := getTestCase(ctx, selfPM.ProjectID, opt.CollectionID, opt.TestCaseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := opt.Spec.Validate(); err != nil {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("testCase.InvalidSpec", err.Error()))
+	}
+	if err := t.UpdateSpec(ctx, opt.Spec.String()); err != nil {
+		slog.ErrorContext(ctx, "t.UpdateSpec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.FailedToUpdate"))
+	}
+	t.Spec = opt.Spec.String()
+	return convertModelTestCase(t), nil
+}
+
+func (ts *testCaseApiImpl) Run(ctx *gin.Context, opt *request.RunTestCaseOption) (*response.TestCaseRun, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	t, err := getTestCase(ctx, selfPM.ProjectID, opt.CollectionID, opt.TestCaseID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Spec == "" {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("testCase.NotExecutable"))
+	}
+
+	target, err := testCaseTarget(ctx, selfPM.ProjectID, opt.ServerID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := testrun.Run(ctx, t, target)
+	if err != nil {
+		slog.ErrorContext(ctx, "testrun.Run", "err", err, "testCase", t.ID)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.FailedToRun"))
+	}
+	return &response.TestCaseRun{ID: t.ID, Title: t.Title, Result: result}, nil
+}
+
+func (ts *testCaseApiImpl) RunAll(ctx *gin.Context, opt *request.RunTestCasesOption) (*response.TestCaseRunJob, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+	if _, err := testCaseTarget(ctx, selfPM.ProjectID, opt.ServerID); err != nil {
+		return nil, err
+	}
+
+	// 同一接口同时只运行一个执行任务
+	active, err := job.GetActiveJob(ctx, selfPM.ProjectID, job.TypeTestCaseRun, opt.CollectionID)
+	if err != nil {
+		slog.ErrorContext(ctx, "job.GetActiveJob", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.FailedToRun"))
+	}
+	if active != nil {
+		return &response.TestCaseRunJob{JobID: active.ID}, nil
+	}
+
+	j := &job.Job{
+		Type:      job.TypeTestCaseRun,
+		ProjectID: selfPM.ProjectID,
+		TargetID:  opt.CollectionID,
+		MemberID:  access.GetSelfTeamMember(ctx).ID,
+	}
+	if err := jobservice.Enqueue(ctx, j, jobservice.TestRunPayload{ServerID: opt.ServerID}); err != nil {
+		slog.ErrorContext(ctx, "jobservice.Enqueue", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("testCase.FailedToRun"))
+	}
+	return &response.TestCaseRunJob{JobID: j.ID}, nil
+}
@@ -0,0 +1,452 @@
+package iteration
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/iteration"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protoiteration "github.com/apicat/apicat/v2/backend/route/proto/iteration"
+	iterationbase "github.com/apicat/apicat/v2/backend/route/proto/iteration/base"
+	iterationrequest "github.com/apicat/apicat/v2/backend/route/proto/iteration/request"
+	iterationresponse "github.com/apicat/apicat/v2/backend/route/proto/iteration/response"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type iterationBranchApiImpl struct{}
+
+func NewIterationBranchApi() protoiteration.IterationBranchApi {
+	return &iterationBranchApiImpl{}
+}
+
+// branchCapabilities 编辑分支中的条目与编辑主线需要相同的能力，撤销分支中的删除需要删除能力
+func branchCapabilities(targetType string, deleted bool) []project.Capability {
+	c := project.CapabilityEditCollection
+	if targetType == iteration.BranchTargetSchema {
+		c = project.CapabilityEditSchema
+	}
+	if deleted {
+		return []project.Capability{c, project.CapabilityDelete}
+	}
+	return []project.Capability{c}
+}
+
+// checkBranchPermission 编辑分支与编辑主线需要相同的权限
+func checkBranchPermission(ctx *gin.Context, caps ...project.Capability) error {
+	pm := access.GetSelfProjectMember(ctx)
+	if pm.Permission.Lower(project.ProjectMemberWrite) {
+		return ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	for _, c := range caps {
+		ok, err := pm.HasCapability(ctx, c)
+		if err != nil {
+			slog.ErrorContext(ctx, "pm.HasCapability", "err", err)
+			return ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+		}
+		if !ok {
+			return ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+		}
+	}
+	return nil
+}
+
+// getBranchItem 获取分支中对应主线接口或模型的修改，没有修改时返回nil
+func getBranchItem(ctx *gin.Context, iterationID, targetType string, targetID uint) (*iteration.IterationBranchItem, error) {
+	item := &iteration.IterationBranchItem{IterationID: iterationID, TargetType: targetType, TargetID: targetID}
+	exist, err := item.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "item.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGet"))
+	}
+	if !exist {
+		return nil, nil
+	}
+	return item, nil
+}
+
+// getMainlineCollection 获取主线的接口，目录不能在分支中编辑
+func getMainlineCollection(ctx *gin.Context, cID uint) (*collection.Collection, error) {
+	c := &collection.Collection{ID: cID, ProjectID: access.GetSelfProject(ctx).ID}
+	exist, err := c.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "c.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("collection.DoesNotExist"))
+	}
+	if c.Type == collection.CategoryType {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("iterationBranch.UnsupportedType"))
+	}
+	return c, nil
+}
+
+// getMainlineSchema 获取主线的模型，目录不能在分支中编辑
+func getMainlineSchema(ctx *gin.Context, sID uint) (*definition.DefinitionSchema, error) {
+	ds := &definition.DefinitionSchema{ID: sID, ProjectID: access.GetSelfProject(ctx).ID}
+	exist, err := ds.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "ds.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("definitionSchema.DoesNotExist"))
+	}
+	if ds.Type == definition.SchemaCategory {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("iterationBranch.UnsupportedType"))
+	}
+	return ds, nil
+}
+
+// branchCollection 获取分支中的接口，分支中还没有时复制主线的内容作为基准
+func branchCollection(ctx *gin.Context, iterationID string, cID uint) (*iteration.IterationBranchItem, error) {
+	item, err := getBranchItem(ctx, iterationID, iteration.BranchTargetCollection, cID)
+	if err != nil || item != nil {
+		return item, err
+	}
+
+	c, err := getMainlineCollection(ctx, cID)
+	if err != nil {
+		return nil, err
+	}
+	item = &iteration.IterationBranchItem{
+		IterationID: iterationID,
+		TargetType:  iteration.BranchTargetCollection,
+		TargetID:    c.ID,
+		ParentID:    c.ParentID,
+		Type:        c.Type,
+		BaseTitle:   c.Title,
+		BaseContent: c.Content,
+		Title:       c.Title,
+		Content:     c.Content,
+	}
+	if err := item.Create(ctx, access.GetSelfTeamMember(ctx)); err != nil {
+		slog.ErrorContext(ctx, "item.Create", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+	}
+	return item, nil
+}
+
+// branchSchema 获取分支中的模型，分支中还没有时复制主线的内容作为基准
+func branchSchema(ctx *gin.Context, iterationID string, sID uint) (*iteration.IterationBranchItem, error) {
+	item, err := getBranchItem(ctx, iterationID, iteration.BranchTargetSchema, sID)
+	if err != nil || item != nil {
+		return item, err
+	}
+
+	ds, err := getMainlineSchema(ctx, sID)
+	if err != nil {
+		return nil, err
+	}
+	item = &iteration.IterationBranchItem{
+		IterationID:     iterationID,
+		TargetType:      iteration.BranchTargetSchema,
+		TargetID:        ds.ID,
+		ParentID:        ds.ParentID,
+		Type:            ds.Type,
+		BaseTitle:       ds.Name,
+		BaseDescription: ds.Description,
+		BaseContent:     ds.Schema,
+		Title:           ds.Name,
+		Description:     ds.Description,
+		Content:         ds.Schema,
+	}
+	if err := item.Create(ctx, access.GetSelfTeamMember(ctx)); err != nil {
+		slog.ErrorContext(ctx, "item.Create", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+	}
+	return item, nil
+}
+
+// sortCollectionContent 与主线编辑接口一样对响应排序
+func sortCollectionContent(content string) string {
+	if cs, err := spec.NewCollectionNodesFromJson(content); err == nil {
+		cs.SortResponses()
+		if s, err := cs.ToJson(); err == nil {
+			return s
+		}
+	}
+	return content
+}
+
+func (ibai *iterationBranchApiImpl) List(ctx *gin.Context, opt *iterationbase.IterationIDOption) (*iterationresponse.BranchItemList, error) {
+	items, err := iteration.GetBranchItems(ctx, opt.IterationID)
+	if err != nil {
+		slog.ErrorContext(ctx, "iteration.GetBranchItems", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGet"))
+	}
+
+	list := make(iterationresponse.BranchItemList, len(items))
+	for i, item := range items {
+		list[i] = convertModelBranchItem(item, false)
+	}
+	return &list, nil
+}
+
+func (ibai *iterationBranchApiImpl) GetCollection(ctx *gin.Context, opt *iterationrequest.BranchCollectionIDOption) (*iterationresponse.BranchItem, error) {
+	item, err := getBranchItem(ctx, opt.IterationID, iteration.BranchTargetCollection, opt.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	if item != nil {
+		return convertModelBranchItem(item, true), nil
+	}
+
+	c, err := getMainlineCollection(ctx, opt.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	return &iterationresponse.BranchItem{
+		TargetType: iteration.BranchTargetCollection,
+		TargetID:   c.ID,
+		ParentID:   c.ParentID,
+		Type:       c.Type,
+		Title:      c.Title,
+		Content:    c.Content,
+		UpdatedAt:  c.UpdatedAt.Unix(),
+	}, nil
+}
+
+func (ibai *iterationBranchApiImpl) UpdateCollection(ctx *gin.Context, opt *iterationrequest.UpdateBranchCollectionOption) (*iterationresponse.BranchItem, error) {
+	if err := checkBranchPermission(ctx, project.CapabilityEditCollection); err != nil {
+		return nil, err
+	}
+
+	item, err := branchCollection(ctx, opt.IterationID, opt.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := item.Update(ctx, opt.Title, "", sortCollectionContent(opt.Content), access.GetSelfTeamMember(ctx).ID); err != nil {
+		slog.ErrorContext(ctx, "item.Update", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+	}
+	return convertModelBranchItem(item, true), nil
+}
+
+func (ibai *iterationBranchApiImpl) DeleteCollection(ctx *gin.Context, opt *iterationrequest.BranchCollectionIDOption) (*ginrpc.Empty, error) {
+	if err := checkBranchPermission(ctx, project.CapabilityDelete); err != nil {
+		return nil, err
+	}
+
+	item, err := branchCollection(ctx, opt.IterationID, opt.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := item.MarkDeleted(ctx, access.GetSelfTeamMember(ctx).ID); err != nil {
+		slog.ErrorContext(ctx, "item.MarkDeleted", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+	}
+	return &ginrpc.Empty{}, nil
+}
+
+func (ibai *iterationBranchApiImpl) GetSchema(ctx *gin.Context, opt *iterationrequest.BranchSchemaIDOption) (*iterationresponse.BranchItem, error) {
+	item, err := getBranchItem(ctx, opt.IterationID, iteration.BranchTargetSchema, opt.SchemaID)
+	if err != nil {
+		return nil, err
+	}
+	if item != nil {
+		return convertModelBranchItem(item, true), nil
+	}
+
+	ds, err := getMainlineSchema(ctx, opt.SchemaID)
+	if err != nil {
+		return nil, err
+	}
+	return &iterationresponse.BranchItem{
+		TargetType:  iteration.BranchTargetSchema,
+		TargetID:    ds.ID,
+		ParentID:    ds.ParentID,
+		Type:        ds.Type,
+		Title:       ds.Name,
+		Description: ds.Description,
+		Content:     ds.Schema,
+		UpdatedAt:   ds.UpdatedAt.Unix(),
+	}, nil
+}
+
+func (ibai *iterationBranchApiImpl) UpdateSchema(ctx *gin.Context, opt *iterationrequest.UpdateBranchSchemaOption) (*iterationresponse.BranchItem, error) {
+	if err := checkBranchPermission(ctx, project.CapabilityEditSchema); err != nil {
+		return nil, err
+	}
+
+	item, err := branchSchema(ctx, opt.IterationID, opt.SchemaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := item.Update(ctx, opt.Name, opt.Description, opt.Schema, access.GetSelfTeamMember(ctx).ID); err != nil {
+		slog.ErrorContext(ctx, "item.Update", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+	}
+	return convertModelBranchItem(item, true), nil
+}
+
+func (ibai *iterationBranchApiImpl) DeleteSchema(ctx *gin.Context, opt *iterationrequest.BranchSchemaIDOption) (*ginrpc.Empty, error) {
+	if err := checkBranchPermission(ctx, project.CapabilityDelete); err != nil {
+		return nil, err
+	}
+
+	item, err := branchSchema(ctx, opt.IterationID, opt.SchemaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := item.MarkDeleted(ctx, access.GetSelfTeamMember(ctx).ID); err != nil {
+		slog.ErrorContext(ctx, "item.MarkDeleted", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+	}
+	return &ginrpc.Empty{}, nil
+}
+
+func (ibai *iterationBranchApiImpl) CreateItem(ctx *gin.Context, opt *iterationrequest.CreateBranchItemOption) (*iterationresponse.BranchItem, error) {
+	if err := checkBranchPermission(ctx, branchCapabilities(opt.TargetType, false)...); err != nil {
+		return nil, err
+	}
+
+	pID := access.GetSelfProject(ctx).ID
+	switch opt.TargetType {
+	case iteration.BranchTargetCollection:
+		if opt.Type == definition.SchemaSchema {
+			return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("iterationBranch.UnsupportedType"))
+		}
+		if opt.ParentID != 0 {
+			parent := &collection.Collection{ID: opt.ParentID, ProjectID: pID}
+			if exist, err := parent.Get(ctx); err != nil {
+				slog.ErrorContext(ctx, "parent.Get", "err", err)
+				return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+			} else if !exist || parent.Type != collection.CategoryType {
+				return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("category.DoesNotExist"))
+			}
+		}
+		// 与主线一样，创建http文档时如果content为空则补充默认结构
+		if opt.Type == collection.HttpType && opt.Content == "" {
+			nodes := spec.NewHttpCollectionNodes()
+			content, err := nodes.ToJson()
+			if err != nil {
+				return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+			}
+			opt.Content = content
+		}
+		opt.Content = sortCollectionContent(opt.Content)
+		opt.Description = ""
+	case iteration.BranchTargetSchema:
+		if opt.Type != definition.SchemaSchema {
+			return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("iterationBranch.UnsupportedType"))
+		}
+		if opt.ParentID != 0 {
+			parent := &definition.DefinitionSchema{ID: opt.ParentID, ProjectID: pID}
+			if exist, err := parent.Get(ctx); err != nil {
+				slog.ErrorContext(ctx, "parent.Get", "err", err)
+				return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+			} else if !exist || parent.Type != definition.SchemaCategory {
+				return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("category.DoesNotExist"))
+			}
+		}
+		if opt.Content == "" {
+			opt.Content = jsonschema.NewSchema(jsonschema.T_OBJ).ToJson()
+		}
+	}
+
+	item := &iteration.IterationBranchItem{
+		IterationID: opt.IterationID,
+		TargetType:  opt.TargetType,
+		ParentID:    opt.ParentID,
+		Type:        opt.Type,
+		Title:       opt.Title,
+		Description: opt.Description,
+		Content:     opt.Content,
+	}
+	if err := item.Create(ctx, access.GetSelfTeamMember(ctx)); err != nil {
+		slog.ErrorContext(ctx, "item.Create", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+	}
+	return convertModelBranchItem(item, true), nil
+}
+
+func getBranchItemByID(ctx *gin.Context, opt *iterationrequest.BranchItemIDOption) (*iteration.IterationBranchItem, error) {
+	item := &iteration.IterationBranchItem{ID: opt.ItemID, IterationID: opt.IterationID}
+	exist, err := item.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "item.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("iterationBranch.ItemDoesNotExist"))
+	}
+	return item, nil
+}
+
+func (ibai *iterationBranchApiImpl) GetItem(ctx *gin.Context, opt *iterationrequest.BranchItemIDOption) (*iterationresponse.BranchItem, error) {
+	item, err := getBranchItemByID(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	return convertModelBranchItem(item, true), nil
+}
+
+func (ibai *iterationBranchApiImpl) UpdateItem(ctx *gin.Context, opt *iterationrequest.UpdateBranchItemOption) (*iterationresponse.BranchItem, error) {
+	item, err := getBranchItemByID(ctx, &opt.BranchItemIDOption)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBranchPermission(ctx, branchCapabilities(item.TargetType, item.Deleted)...); err != nil {
+		return nil, err
+	}
+
+	if item.TargetType == iteration.BranchTargetCollection {
+		opt.Content = sortCollectionContent(opt.Content)
+		opt.Description = ""
+	}
+	if err := item.Update(ctx, opt.Title, opt.Description, opt.Content, access.GetSelfTeamMember(ctx).ID); err != nil {
+		slog.ErrorContext(ctx, "item.Update", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+	}
+	return convertModelBranchItem(item, true), nil
+}
+
+func (ibai *iterationBranchApiImpl) DiscardItem(ctx *gin.Context, opt *iterationrequest.BranchItemIDOption) (*ginrpc.Empty, error) {
+	item, err := getBranchItemByID(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBranchPermission(ctx, branchCapabilities(item.TargetType, item.Deleted)...); err != nil {
+		return nil, err
+	}
+
+	if err := item.Delete(ctx); err != nil {
+		slog.ErrorContext(ctx, "item.Delete", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.ModificationFailed"))
+	}
+	return &ginrpc.Empty{}, nil
+}
+
+func convertModelBranchItem(item *iteration.IterationBranchItem, withContent bool) *iterationresponse.BranchItem {
+	res := &iterationresponse.BranchItem{
+		ID:          item.ID,
+		TargetType:  item.TargetType,
+		TargetID:    item.TargetID,
+		ParentID:    item.ParentID,
+		Type:        item.Type,
+		Title:       item.Title,
+		Description: item.Description,
+		Status:      diff.StatusChanged,
+		UpdatedAt:   item.UpdatedAt.Unix(),
+	}
+	if withContent {
+		res.Content = item.Content
+	}
+	if item.IsCreated() {
+		res.Status = diff.StatusAdded
+	} else if item.Deleted {
+		res.Status = diff.StatusRemoved
+	}
+	return res
+}
@@ -4,8 +4,10 @@ import (
 	"log/slog"
 	"math"
 	"net/http"
+	"time"
 
 	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/collection"
 	"github.com/apicat/apicat/v2/backend/model/iteration"
 	"github.com/apicat/apicat/v2/backend/model/project"
@@ -16,6 +18,8 @@ import (
 	iterationrequest "github.com/apicat/apicat/v2/backend/route/proto/iteration/request"
 	iterationresponse "github.com/apicat/apicat/v2/backend/route/proto/iteration/response"
 	projectbase "github.com/apicat/apicat/v2/backend/route/proto/project/base"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+	webhookservice "github.com/apicat/apicat/v2/backend/service/webhook"
 
 	"github.com/apicat/ginrpc"
 	"github.com/gin-gonic/gin"
@@ -50,12 +54,22 @@ func (iai *iterationApiImpl) Create(ctx *gin.Context, opt *iterationrequest.Crea
 	if pm.Permission.Lower(project.ProjectMemberWrite) {
 		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
 	}
+	// 创建迭代的路由不包含项目，无法通过中间件检查，在此检查自定义角色的能力
+	if ok, err := pm.HasCapability(ctx, project.CapabilityManageIteration); err != nil {
+		slog.ErrorContext(ctx, "pm.HasCapability", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.CreationFailed"))
+	} else if !ok {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
 
 	it := &iteration.Iteration{
 		ProjectID:   p.ID,
 		Title:       opt.Title,
 		Description: opt.Description,
 	}
+	if err := setIterationSchedule(it, opt.IterationScheduleData); err != nil {
+		return nil, err
+	}
 	if err = it.Create(ctx, access.GetSelfTeamMember(ctx)); err != nil {
 		slog.ErrorContext(ctx, "it.Create", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.CreationFailed"))
@@ -72,6 +86,8 @@ func (iai *iterationApiImpl) Create(ctx *gin.Context, opt *iterationrequest.Crea
 			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.CreationFailed"))
 		}
 	}
+
+	notifyIterationChange(ctx, audit.ActionCreate, it, nil, iterationWebhookFields(it))
 	return &ginrpc.Empty{}, nil
 }
 
@@ -118,12 +134,12 @@ func (iai *iterationApiImpl) List(ctx *gin.Context, opt *iterationrequest.GetIte
 		pmDict[v.ProjectID] = v
 	}
 
-	iterations, err := iteration.GetIterations(ctx, selfTeam.ID, opt.Page, opt.PageSize, projectIDs...)
+	iterations, err := iteration.GetIterations(ctx, selfTeam.ID, opt.State, opt.Page, opt.PageSize, projectIDs...)
 	if err != nil {
 		slog.ErrorContext(ctx, "iteration.GetIterations", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.FailedToGetList"))
 	}
-	count, err := iteration.GetIterationsCount(ctx, selfTeam.ID, projectIDs...)
+	count, err := iteration.GetIterationsCount(ctx, selfTeam.ID, opt.State, projectIDs...)
 	if err != nil {
 		slog.ErrorContext(ctx, "iteration.GetIterationsCount", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.FailedToGetList"))
@@ -143,6 +159,10 @@ func (iai *iterationApiImpl) List(ctx *gin.Context, opt *iterationrequest.GetIte
 			if err != nil {
 				slog.ErrorContext(ctx, "i.GetIterationApiCount", "err", err)
 			}
+			doneCount, err := i.GetIterationApiDoneCount(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "i.GetIterationApiDoneCount", "err", err)
+			}
 
 			permission := project.ProjectMemberNone
 			if pm, ok := pmDict[i.ProjectID]; ok {
@@ -158,7 +178,10 @@ func (iai *iterationApiImpl) List(ctx *gin.Context, opt *iterationrequest.GetIte
 					Title:       i.Title,
 					Description: i.Description,
 				},
-				ApisCount: count,
+				IterationScheduleData: convertIterationSchedule(i),
+				ApisCount:             count,
+				DoneApisCount:         doneCount,
+				Overdue:               i.IsOverdue(time.Now()) && (count == 0 || doneCount < count),
 				Project: &iterationresponse.IterationListProject{
 					ID:    p.ID,
 					Title: p.Title,
@@ -195,6 +218,7 @@ func (iai *iterationApiImpl) Get(ctx *gin.Context, opt *iterationbase.IterationI
 			Title:       i.Title,
 			Description: i.Description,
 		},
+		IterationScheduleData: convertIterationSchedule(i),
 		Project: &iterationresponse.IterationProject{
 			OnlyIdInfo: protobase.OnlyIdInfo{
 				ID: p.ID,
@@ -228,8 +252,12 @@ func (iai *iterationApiImpl) Update(ctx *gin.Context, opt *iterationrequest.Upda
 		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
 	}
 
+	before := iterationWebhookFields(i)
 	i.Title = opt.Title
 	i.Description = opt.Description
+	if err := setIterationSchedule(i, opt.IterationScheduleData); err != nil {
+		return nil, err
+	}
 	if err := i.Update(ctx, access.GetSelfTeamMember(ctx)); err != nil {
 		slog.ErrorContext(ctx, "i.Update", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
@@ -249,6 +277,7 @@ func (iai *iterationApiImpl) Update(ctx *gin.Context, opt *iterationrequest.Upda
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
 	}
 
+	notifyIterationChange(ctx, audit.ActionUpdate, i, before, iterationWebhookFields(i))
 	return &ginrpc.Empty{}, nil
 }
 
@@ -272,5 +301,30 @@ func (iai *iterationApiImpl) Delete(ctx *gin.Context, opt *iterationbase.Iterati
 		slog.ErrorContext(ctx, "selfI.Delete", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.FailedToDelete"))
 	}
+
+	notifyIterationChange(ctx, audit.ActionDelete, i, iterationWebhookFields(i), nil)
 	return &ginrpc.Empty{}, nil
 }
+
+func iterationWebhookFields(i *iteration.Iteration) auditservice.Fields {
+	s := convertIterationSchedule(i)
+	return auditservice.Fields{
+		"title":       i.Title,
+		"description": i.Description,
+		"startDate":   s.StartDate,
+		"dueDate":     s.DueDate,
+		"state":       s.State,
+	}
+}
+
+// notifyIterationChange 触发迭代相关的项目webhook事件
+func notifyIterationChange(ctx *gin.Context, action string, i *iteration.Iteration, before, after auditservice.Fields) {
+	webhookservice.Notify(ctx, i.ProjectID, &auditservice.Entry{
+		Action:     action,
+		TargetType: audit.TargetIteration,
+		TargetID:   i.ID,
+		TargetName: i.Title,
+		Before:     before,
+		After:      after,
+	})
+}
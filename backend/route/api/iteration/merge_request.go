@@ -0,0 +1,368 @@
+package iteration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/iteration"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	protoiteration "github.com/apicat/apicat/v2/backend/route/proto/iteration"
+	iterationbase "github.com/apicat/apicat/v2/backend/route/proto/iteration/base"
+	iterationrequest "github.com/apicat/apicat/v2/backend/route/proto/iteration/request"
+	iterationresponse "github.com/apicat/apicat/v2/backend/route/proto/iteration/response"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+	branchservice "github.com/apicat/apicat/v2/backend/service/branch"
+	changelogservice "github.com/apicat/apicat/v2/backend/service/changelog"
+	notifyservice "github.com/apicat/apicat/v2/backend/service/notification"
+	proposalservice "github.com/apicat/apicat/v2/backend/service/proposal"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+	webhookservice "github.com/apicat/apicat/v2/backend/service/webhook"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type iterationMergeRequestApiImpl struct{}
+
+func NewIterationMergeRequestApi() protoiteration.IterationMergeRequestApi {
+	return &iterationMergeRequestApiImpl{}
+}
+
+func getIteration(ctx *gin.Context, iterationID string) (*iteration.Iteration, error) {
+	i := &iteration.Iteration{ID: iterationID}
+	exist, err := i.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "i.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("iteration.DoesNotExist"))
+	}
+	return i, nil
+}
+
+func getMergeRequest(ctx *gin.Context, opt *iterationrequest.MergeRequestIDOption) (*iteration.IterationMergeRequest, error) {
+	mr := &iteration.IterationMergeRequest{ID: opt.MergeRequestID, IterationID: opt.IterationID}
+	exist, err := mr.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "mr.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGetRequest"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("iterationBranch.RequestDoesNotExist"))
+	}
+	return mr, nil
+}
+
+func (imrai *iterationMergeRequestApiImpl) Create(ctx *gin.Context, opt *iterationrequest.CreateMergeRequestOption) (*iterationresponse.MergeRequest, error) {
+	if access.GetSelfProjectMember(ctx).Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	open, err := iteration.GetOpenMergeRequest(ctx, opt.IterationID)
+	if err != nil {
+		slog.ErrorContext(ctx, "iteration.GetOpenMergeRequest", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.CreationFailed"))
+	}
+	if open != nil {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("iterationBranch.OpenRequestExists"))
+	}
+
+	items, err := iteration.GetBranchItems(ctx, opt.IterationID)
+	if err != nil {
+		slog.ErrorContext(ctx, "iteration.GetBranchItems", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.CreationFailed"))
+	}
+	if len(items) == 0 {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("iterationBranch.Empty"))
+	}
+
+	mr := &iteration.IterationMergeRequest{
+		IterationID: opt.IterationID,
+		Title:       opt.Title,
+		Description: opt.Description,
+	}
+	if err := mr.Create(ctx, access.GetSelfTeamMember(ctx)); err != nil {
+		slog.ErrorContext(ctx, "mr.Create", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.CreationFailed"))
+	}
+	return convertModelMergeRequest(ctx, mr), nil
+}
+
+func (imrai *iterationMergeRequestApiImpl) List(ctx *gin.Context, opt *iterationbase.IterationIDOption) (*iterationresponse.MergeRequestList, error) {
+	list, err := iteration.GetMergeRequests(ctx, opt.IterationID)
+	if err != nil {
+		slog.ErrorContext(ctx, "iteration.GetMergeRequests", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGetRequest"))
+	}
+
+	res := make(iterationresponse.MergeRequestList, len(list))
+	for i, mr := range list {
+		res[i] = convertModelMergeRequest(ctx, mr)
+	}
+	return &res, nil
+}
+
+// Get 合并请求详情，打开中的请求实时计算分支与主线的对比和冲突，已合并或关闭的请求分支已不存在，只返回基本信息
+func (imrai *iterationMergeRequestApiImpl) Get(ctx *gin.Context, opt *iterationrequest.MergeRequestIDOption) (*iterationresponse.MergeRequestDetail, error) {
+	mr, err := getMergeRequest(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &iterationresponse.MergeRequestDetail{
+		MergeRequest: *convertModelMergeRequest(ctx, mr),
+		Conflicts:    make([]*iterationresponse.MergeConflict, 0),
+	}
+	if mr.Status != iteration.MergeRequestOpen {
+		return res, nil
+	}
+
+	i, err := getIteration(ctx, opt.IterationID)
+	if err != nil {
+		return nil, err
+	}
+	p := access.GetSelfProject(ctx)
+	items, err := iteration.GetBranchItems(ctx, i.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "iteration.GetBranchItems", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGetRequest"))
+	}
+
+	mainline, err := relations.ProjectSpec(ctx, p)
+	if err != nil {
+		slog.ErrorContext(ctx, "relations.ProjectSpec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGetRequest"))
+	}
+	branch, err := branchservice.Spec(ctx, p, items)
+	if err != nil {
+		slog.ErrorContext(ctx, "branchservice.Spec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGetRequest"))
+	}
+	report, err := diff.DiffSpec(mainline, branch)
+	if err != nil {
+		slog.ErrorContext(ctx, "diff.DiffSpec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGetRequest"))
+	}
+	c := &changelogservice.Changelog{
+		Title:  p.Title,
+		From:   i18n.NewTran("iterationBranch.Mainline").Translate(ctx),
+		To:     i.Title,
+		Report: report,
+	}
+	res.Diff = c.Response(ctx)
+
+	changes, err := branchservice.Prepare(ctx, p.ID, items, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "branchservice.Prepare", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.FailedToGetRequest"))
+	}
+	res.Conflicts = convertMergeConflicts(changes)
+	return res, nil
+}
+
+// Merge 将分支合并到主线
+// 先合并模型再合并接口，以便新建的接口可以引用新建的模型，所有条目在同一事务中写入主线，失败时全部回滚
+func (imrai *iterationMergeRequestApiImpl) Merge(ctx *gin.Context, opt *iterationrequest.MergeOption) (*iterationresponse.MergeRequest, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	if access.GetSelfProjectMember(ctx).Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	mr, err := getMergeRequest(ctx, &opt.MergeRequestIDOption)
+	if err != nil {
+		return nil, err
+	}
+	if mr.Status != iteration.MergeRequestOpen {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("iterationBranch.RequestNotOpen"))
+	}
+	i, err := getIteration(ctx, opt.IterationID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := access.GetSelfProject(ctx)
+	items, err := iteration.GetBranchItems(ctx, i.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "iteration.GetBranchItems", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.MergeFailed"))
+	}
+	sort.SliceStable(items, func(a, b int) bool {
+		return items[a].TargetType == iteration.BranchTargetSchema && items[b].TargetType != iteration.BranchTargetSchema
+	})
+
+	resolutions := make(map[uint]map[string]string)
+	for _, r := range opt.Resolutions {
+		if resolutions[r.ItemID] == nil {
+			resolutions[r.ItemID] = make(map[string]string)
+		}
+		resolutions[r.ItemID][r.Field] = r.Choice
+	}
+	changes, err := branchservice.Prepare(ctx, p.ID, items, resolutions)
+	if err != nil {
+		slog.ErrorContext(ctx, "branchservice.Prepare", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.MergeFailed"))
+	}
+	if branchservice.HasConflicts(changes) {
+		return nil, ginrpc.NewError(http.StatusConflict, i18n.NewErr("iterationBranch.Conflicts"))
+	}
+
+	// 先检查所有条目，避免合并到一半才发现没有权限或受保护的条目
+	pm := access.GetSelfProjectMember(ctx)
+	for _, c := range changes {
+		if c.Action() == "" {
+			continue
+		}
+		if ok, err := pm.HasCapability(ctx, c.Capability()); err != nil {
+			slog.ErrorContext(ctx, "pm.HasCapability", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.MergeFailed"))
+		} else if !ok {
+			return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+		}
+		if err := c.CheckProtected(ctx, p.ID); err != nil {
+			if errors.Is(err, proposalservice.ErrProtected) {
+				return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("proposal.TargetProtected"))
+			}
+			slog.ErrorContext(ctx, "c.CheckProtected", "err", err, "item", c.Item.ID)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.MergeFailed"))
+		}
+	}
+
+	actions := make([]string, len(changes))
+	err = model.Transaction(ctx, func(txCtx context.Context) error {
+		for k, c := range changes {
+			actions[k] = c.Action()
+			if err := c.Apply(txCtx, p.ID, selfTM); err != nil {
+				return fmt.Errorf("apply item %d: %w", c.Item.ID, err)
+			}
+			if err := c.Item.Delete(txCtx); err != nil {
+				return fmt.Errorf("delete item %d: %w", c.Item.ID, err)
+			}
+		}
+		return mr.Finish(txCtx, iteration.MergeRequestMerged, selfTM.ID)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "merge", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.MergeFailed"))
+	}
+	for k, c := range changes {
+		if actions[k] != "" {
+			recordMergeChange(ctx, actions[k], c)
+		}
+	}
+
+	e := &auditservice.Entry{
+		Action:     audit.ActionMerge,
+		TargetType: audit.TargetIteration,
+		TargetID:   i.ID,
+		TargetName: i.Title,
+		After:      auditservice.Fields{"mergeRequest": mr.Title},
+	}
+	auditservice.Record(ctx, e)
+	notifyIterationChange(ctx, audit.ActionMerge, i, nil, e.After)
+	return convertModelMergeRequest(ctx, mr), nil
+}
+
+func (imrai *iterationMergeRequestApiImpl) Close(ctx *gin.Context, opt *iterationrequest.MergeRequestIDOption) (*iterationresponse.MergeRequest, error) {
+	if access.GetSelfProjectMember(ctx).Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	mr, err := getMergeRequest(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	if mr.Status != iteration.MergeRequestOpen {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("iterationBranch.RequestNotOpen"))
+	}
+
+	if err := mr.Finish(ctx, iteration.MergeRequestClosed, access.GetSelfTeamMember(ctx).ID); err != nil {
+		slog.ErrorContext(ctx, "mr.Finish", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iterationBranch.CloseFailed"))
+	}
+	return convertModelMergeRequest(ctx, mr), nil
+}
+
+// recordMergeChange 合并写入主线的每处修改与直接编辑一样记录审计日志，并触发webhook和聊天通知
+func recordMergeChange(ctx *gin.Context, action string, c *branchservice.Change) {
+	e := &auditservice.Entry{
+		Action: action,
+		Before: mergeAuditFields(c.Mainline),
+		After:  mergeAuditFields(c.Merged),
+	}
+	if c.Item.TargetType == iteration.BranchTargetSchema {
+		e.TargetType = audit.TargetDefinitionSchema
+		e.TargetID = c.Schema.ID
+		e.TargetName = c.Schema.Name
+	} else {
+		e.TargetType = audit.TargetCollection
+		e.TargetID = c.Collection.ID
+		e.TargetName = c.Collection.Title
+	}
+
+	pID := access.GetSelfProject(ctx).ID
+	auditservice.Record(ctx, e)
+	webhookservice.Notify(ctx, pID, e)
+	notifyservice.Notify(ctx, pID, e, nil)
+}
+
+func mergeAuditFields(f branchservice.Fields) auditservice.Fields {
+	if f == nil {
+		return nil
+	}
+	res := make(auditservice.Fields, len(f))
+	for k, v := range f {
+		res[k] = v
+	}
+	return res
+}
+
+func convertMergeConflicts(changes []*branchservice.Change) []*iterationresponse.MergeConflict {
+	res := make([]*iterationresponse.MergeConflict, 0)
+	for _, c := range changes {
+		for _, conflict := range c.Conflicts {
+			res = append(res, &iterationresponse.MergeConflict{
+				ItemID:     c.Item.ID,
+				TargetType: c.Item.TargetType,
+				Title:      c.Item.Title,
+				Field:      conflict.Field,
+				Base:       conflict.Base,
+				Mainline:   conflict.Mainline,
+				Branch:     conflict.Branch,
+			})
+		}
+	}
+	return res
+}
+
+func convertModelMergeRequest(ctx *gin.Context, mr *iteration.IterationMergeRequest) *iterationresponse.MergeRequest {
+	res := &iterationresponse.MergeRequest{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        mr.ID,
+			CreatedAt: mr.CreatedAt.Unix(),
+		},
+		Title:       mr.Title,
+		Description: mr.Description,
+		Status:      mr.Status,
+	}
+	if u, err := mr.CreatorInfo(ctx); err == nil {
+		res.CreatedBy = u.Name
+	}
+	if mr.ClosedAt != nil {
+		res.ClosedAt = mr.ClosedAt.Unix()
+		if u, err := mr.CloserInfo(ctx); err == nil {
+			res.ClosedBy = u.Name
+		}
+	}
+	return res
+}
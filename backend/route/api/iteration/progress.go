@@ -0,0 +1,211 @@
+package iteration
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/iteration"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protoiteration "github.com/apicat/apicat/v2/backend/route/proto/iteration"
+	iterationbase "github.com/apicat/apicat/v2/backend/route/proto/iteration/base"
+	iterationrequest "github.com/apicat/apicat/v2/backend/route/proto/iteration/request"
+	iterationresponse "github.com/apicat/apicat/v2/backend/route/proto/iteration/response"
+	progressservice "github.com/apicat/apicat/v2/backend/service/progress"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type iterationProgressApiImpl struct{}
+
+func NewIterationProgressApi() protoiteration.IterationProgressApi {
+	return &iterationProgressApiImpl{}
+}
+
+// setIterationSchedule 设置迭代的日期和状态，未指定状态时保持不变
+func setIterationSchedule(i *iteration.Iteration, opt iterationbase.IterationScheduleData) error {
+	if opt.StartDate > 0 && opt.DueDate > 0 && opt.DueDate < opt.StartDate {
+		return ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("iteration.InvalidDateRange"))
+	}
+	i.StartDate = unixToTime(opt.StartDate)
+	i.DueDate = unixToTime(opt.DueDate)
+	if opt.State != "" {
+		i.State = opt.State
+	}
+	return nil
+}
+
+func convertIterationSchedule(i *iteration.Iteration) iterationbase.IterationScheduleData {
+	return iterationbase.IterationScheduleData{
+		StartDate: timeToUnix(i.StartDate),
+		DueDate:   timeToUnix(i.DueDate),
+		State:     i.State,
+	}
+}
+
+func unixToTime(v int64) *time.Time {
+	if v <= 0 {
+		return nil
+	}
+	t := time.Unix(v, 0)
+	return &t
+}
+
+func timeToUnix(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
+
+func (ipai *iterationProgressApiImpl) ListApis(ctx *gin.Context, opt *iterationrequest.GetIterationApiListOption) (*iterationresponse.IterationApiList, error) {
+	i, err := getIteration(ctx, opt.IterationID)
+	if err != nil {
+		return nil, err
+	}
+
+	apis, err := iteration.GetIterationApis(ctx, i.ID, opt.Status, opt.AssigneeID)
+	if err != nil {
+		slog.ErrorContext(ctx, "iteration.GetIterationApis", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.FailedToGetProgress"))
+	}
+
+	cIDs := make([]uint, len(apis))
+	for k, v := range apis {
+		cIDs[k] = v.CollectionID
+	}
+	collections := make(map[uint]*collection.Collection)
+	if len(cIDs) > 0 {
+		list, err := collection.GetCollections(ctx, i.ProjectID, cIDs...)
+		if err != nil {
+			slog.ErrorContext(ctx, "collection.GetCollections", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.FailedToGetProgress"))
+		}
+		for _, c := range list {
+			collections[c.ID] = c
+		}
+	}
+
+	assignees := make(map[uint]string)
+	res := make(iterationresponse.IterationApiList, 0, len(apis))
+	for _, v := range apis {
+		c, ok := collections[v.CollectionID]
+		if !ok {
+			continue
+		}
+		res = append(res, convertModelIterationApi(ctx, v, c, assignees))
+	}
+	return &res, nil
+}
+
+func (ipai *iterationProgressApiImpl) UpdateApi(ctx *gin.Context, opt *iterationrequest.UpdateIterationApiOption) (*iterationresponse.IterationApi, error) {
+	if access.GetSelfProjectMember(ctx).Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	i, err := getIteration(ctx, opt.IterationID)
+	if err != nil {
+		return nil, err
+	}
+
+	ia := &iteration.IterationApi{IterationID: i.ID, CollectionID: opt.CollectionID}
+	exist, err := ia.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "ia.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.ProgressUpdateFailed"))
+	}
+	if !exist || ia.CollectionType == collection.CategoryType {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("iteration.ApiNotInIteration"))
+	}
+
+	c := &collection.Collection{ID: ia.CollectionID, ProjectID: i.ProjectID}
+	exist, err = c.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "c.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.ProgressUpdateFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("iteration.ApiNotInIteration"))
+	}
+
+	// 负责人必须是项目成员
+	if opt.AssigneeID != 0 {
+		pm := &project.ProjectMember{ProjectID: i.ProjectID, MemberID: opt.AssigneeID}
+		exist, err := pm.Get(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "pm.Get", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.ProgressUpdateFailed"))
+		}
+		if !exist {
+			return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("iteration.AssigneeNotInProject"))
+		}
+	}
+
+	if err := ia.UpdateProgress(ctx, opt.Status, opt.AssigneeID, opt.Note); err != nil {
+		slog.ErrorContext(ctx, "ia.UpdateProgress", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.ProgressUpdateFailed"))
+	}
+
+	return convertModelIterationApi(ctx, ia, c, make(map[uint]string)), nil
+}
+
+func (ipai *iterationProgressApiImpl) Burndown(ctx *gin.Context, opt *iterationbase.IterationIDOption) (*iterationresponse.Burndown, error) {
+	i, err := getIteration(ctx, opt.IterationID)
+	if err != nil {
+		return nil, err
+	}
+
+	apis, err := iteration.GetIterationApis(ctx, i.ID, "", 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "iteration.GetIterationApis", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.FailedToGetProgress"))
+	}
+
+	s := progressservice.Burndown(i, apis, time.Now())
+	res := &iterationresponse.Burndown{
+		Total:     s.Total,
+		Done:      s.Done,
+		Statuses:  s.Statuses,
+		Overdue:   s.Overdue,
+		StartDate: timeToUnix(i.StartDate),
+		DueDate:   timeToUnix(i.DueDate),
+		Days:      make([]*iterationresponse.BurndownDay, len(s.Days)),
+	}
+	for k, v := range s.Days {
+		res.Days[k] = &iterationresponse.BurndownDay{
+			Date:      v.Date.Format(time.DateOnly),
+			Remaining: v.Remaining,
+			Ideal:     v.Ideal,
+		}
+	}
+	return res, nil
+}
+
+// convertModelIterationApi assignees缓存负责人名称，避免重复查询
+func convertModelIterationApi(ctx *gin.Context, ia *iteration.IterationApi, c *collection.Collection, assignees map[uint]string) *iterationresponse.IterationApi {
+	res := &iterationresponse.IterationApi{
+		CollectionID: ia.CollectionID,
+		Title:        c.Title,
+		Type:         c.Type,
+		Status:       ia.Status,
+		AssigneeID:   ia.AssigneeID,
+		Note:         ia.Note,
+		DoneAt:       timeToUnix(ia.DoneAt),
+		UpdatedAt:    ia.UpdatedAt.Unix(),
+	}
+	if ia.AssigneeID != 0 {
+		name, ok := assignees[ia.AssigneeID]
+		if !ok {
+			if u, err := ia.AssigneeInfo(ctx); err == nil {
+				name = u.Name
+			}
+			assignees[ia.AssigneeID] = name
+		}
+		res.Assignee = name
+	}
+	return res
+}
@@ -0,0 +1,206 @@
+package iteration
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/iteration"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/module/cache"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
+	protoiteration "github.com/apicat/apicat/v2/backend/route/proto/iteration"
+	iterationbase "github.com/apicat/apicat/v2/backend/route/proto/iteration/base"
+	iterationrequest "github.com/apicat/apicat/v2/backend/route/proto/iteration/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	changelogservice "github.com/apicat/apicat/v2/backend/service/changelog"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+	"github.com/apicat/apicat/v2/backend/utils/onetime_token"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type iterationReportApiImpl struct{}
+
+func NewIterationReportApi() protoiteration.IterationReportApi {
+	return &iterationReportApiImpl{}
+}
+
+// iterationReportToken 迭代变更报告导出token中保存的内容，导出时没有登录信息，需要记录语言
+type iterationReportToken struct {
+	Option iterationrequest.GetIterationReportExportPathOption `json:"option"`
+	Lang   string                                              `json:"lang"`
+}
+
+const reportTimeLayout = "2006-01-02 15:04:05"
+
+// iterationReport 对比迭代中的接口在迭代创建时与当前的内容
+// 接口引用的公共模型会展开后参与对比，报告中只保留迭代前后被这些接口引用的模型
+func iterationReport(ctx *gin.Context, i *iteration.Iteration, lang string) (*changelogservice.Changelog, error) {
+	p, err := i.ProjectInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := i.GetCollectionIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := relations.ProjectSpecAt(ctx, p, i.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	target, err := relations.ProjectSpec(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	models := changelogservice.ScopeSpec(original, ids)
+	for name := range changelogservice.ScopeSpec(target, ids) {
+		models[name] = true
+	}
+	report, err := diff.DiffSpec(original, target)
+	if err != nil {
+		return nil, err
+	}
+	changelogservice.ScopeReport(report, models)
+
+	return &changelogservice.Changelog{
+		Title:  i.Title,
+		From:   i.CreatedAt.Format(reportTimeLayout),
+		To:     i18n.NewTran("changelog.Now").TranslateIn(lang),
+		Report: report,
+	}, nil
+}
+
+func userLanguage(ctx *gin.Context) string {
+	if u := jwt.GetUser(ctx); u != nil && u.Language != "" {
+		return u.Language
+	}
+	return "en-US"
+}
+
+// Get 迭代变更报告
+func (irai *iterationReportApiImpl) Get(ctx *gin.Context, opt *iterationbase.IterationIDOption) (*projectresponse.ProjectDiff, error) {
+	i, err := getIteration(ctx, opt.IterationID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := iterationReport(ctx, i, userLanguage(ctx))
+	if err != nil {
+		slog.ErrorContext(ctx, "iterationReport", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.FailedToGetReport"))
+	}
+	return c.Response(ctx), nil
+}
+
+// GetExportPath 获取迭代变更报告导出path
+func (irai *iterationReportApiImpl) GetExportPath(ctx *gin.Context, opt *iterationrequest.GetIterationReportExportPathOption) (*projectresponse.ExportProject, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	if access.GetSelfProjectMember(ctx).Permission.Equal(project.ProjectMemberRead) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	tokenKey := fmt.Sprintf(
+		"ExportIterationReport-%d-%d",
+		selfTM.ID,
+		time.Now().Unix(),
+	)
+	c, err := cache.NewCache(config.Get().Cache.ToCfg())
+	if err != nil {
+		slog.ErrorContext(ctx, "cache.NewCache", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.ReportExportFailed"))
+	}
+	token, err := onetime_token.NewTokenHelper(c).GenerateToken(tokenKey, &iterationReportToken{Option: *opt, Lang: userLanguage(ctx)}, time.Minute)
+	if err != nil {
+		slog.ErrorContext(ctx, "onetime_token.GenerateToken", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("iteration.ReportExportFailed"))
+	}
+
+	return &projectresponse.ExportProject{
+		Path: fmt.Sprintf("/api/iterations/%s/report/export/%s", opt.IterationID, token),
+	}, nil
+}
+
+// ExportReport 导出迭代变更报告，需返回不同的 Content-Type，单独处理
+func ExportReport(ctx *gin.Context) {
+	opt := &iterationrequest.IterationReportExportCodeOption{}
+	if err := ctx.ShouldBindUri(opt); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ca, err := cache.NewCache(config.Get().Cache.ToCfg())
+	if err != nil {
+		slog.ErrorContext(ctx, "cache.NewCache", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("iteration.ReportExportFailed").Translate(ctx),
+		})
+		return
+	}
+	tokenHelper := onetime_token.NewTokenHelper(ca)
+
+	t := iterationReportToken{}
+	if !tokenHelper.CheckToken(opt.Code, &t) || t.Option.IterationID != opt.IterationID {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": i18n.NewTran("iteration.ReportExportFailed").Translate(ctx),
+		})
+		return
+	}
+	if err := tokenHelper.DelToken(opt.Code); err != nil {
+		slog.ErrorContext(ctx, "tokenHelper.DelToken", "err", err)
+	}
+
+	i := &iteration.Iteration{ID: t.Option.IterationID}
+	exist, err := i.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "i.Get", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("iteration.ReportExportFailed").TranslateIn(t.Lang),
+		})
+		return
+	}
+	if !exist {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"message": i18n.NewTran("iteration.DoesNotExist").TranslateIn(t.Lang),
+		})
+		return
+	}
+
+	c, err := iterationReport(ctx, i, t.Lang)
+	if err != nil {
+		slog.ErrorContext(ctx, "iterationReport", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("iteration.ReportExportFailed").TranslateIn(t.Lang),
+		})
+		return
+	}
+
+	var (
+		content     []byte
+		contentType string
+		ext         string
+	)
+	switch t.Option.Type {
+	case "HTML":
+		content, contentType, ext = c.HTML(t.Lang), "text/html; charset=utf-8", ".html"
+	default:
+		content, contentType, ext = c.Markdown(t.Lang), "text/markdown; charset=utf-8", ".md"
+	}
+
+	if t.Option.Download {
+		ctx.Header("Content-Disposition", "attachment; filename="+fmt.Sprintf("%s-changelog%s", i.Title, ext))
+		ctx.Data(http.StatusOK, "application/octet-stream", content)
+		return
+	}
+	ctx.Data(http.StatusOK, contentType, content)
+}
@@ -0,0 +1,244 @@
+package project
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/proposal"
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	"github.com/apicat/apicat/v2/backend/service/ai"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+	proposalservice "github.com/apicat/apicat/v2/backend/service/proposal"
+	"github.com/apicat/apicat/v2/backend/service/reference"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+	"github.com/apicat/apicat/v2/backend/utils/jsonpatch"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+// 评审整个项目时，受模型上下文长度限制只取前面的接口和模型
+const (
+	maxReviewAPIs    = 20
+	maxReviewSchemas = 30
+)
+
+type projectAIReviewApiImpl struct{}
+
+func NewProjectAIReviewApi() protoproject.ProjectAIReviewApi {
+	return &projectAIReviewApiImpl{}
+}
+
+// Review AI 按设计规范评审接口和模型，只评审单个接口时带上接口引用的模型
+func (parai *projectAIReviewApiImpl) Review(ctx *gin.Context, opt *projectrequest.AIReviewOption) (*projectresponse.AIReview, error) {
+	selfP := access.GetSelfProject(ctx)
+	res := &projectresponse.AIReview{Findings: make([]*projectresponse.AIReviewFinding, 0)}
+
+	var (
+		collections []*collection.Collection
+		schemas     []*definition.DefinitionSchema
+		err         error
+	)
+	if opt.CollectionID != 0 {
+		c := &collection.Collection{ID: opt.CollectionID, ProjectID: selfP.ID}
+		exist, err := c.Get(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "c.Get", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiReview.ReviewFailed"))
+		}
+		if !exist || c.Type == collection.CategoryType {
+			return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("collection.DoesNotExist"))
+		}
+		collections = append(collections, c)
+
+		refIDs, err := reference.ParseRefSchemasFromCollection(c)
+		if err != nil {
+			slog.ErrorContext(ctx, "reference.ParseRefSchemasFromCollection", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiReview.ReviewFailed"))
+		}
+		if len(refIDs) > 0 {
+			if schemas, err = definition.GetDefinitionSchemas(ctx, selfP.ID, refIDs...); err != nil {
+				slog.ErrorContext(ctx, "definition.GetDefinitionSchemas", "err", err)
+				return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiReview.ReviewFailed"))
+			}
+		}
+	} else {
+		if collections, err = collection.GetCollections(ctx, selfP.ID); err != nil {
+			slog.ErrorContext(ctx, "collection.GetCollections", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiReview.ReviewFailed"))
+		}
+		if schemas, err = definition.GetDefinitionSchemas(ctx, selfP.ID); err != nil {
+			slog.ErrorContext(ctx, "definition.GetDefinitionSchemas", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiReview.ReviewFailed"))
+		}
+	}
+
+	names := map[string]map[uint]string{
+		ai.ReviewTargetCollection: make(map[uint]string),
+		ai.ReviewTargetSchema:     make(map[uint]string),
+	}
+	reviewOpt := &ai.ReviewOption{Rules: opt.Rules}
+	for _, c := range collections {
+		if c.Type == collection.CategoryType || c.Content == "" {
+			continue
+		}
+		if len(reviewOpt.APIs) >= maxReviewAPIs {
+			res.Truncated = true
+			break
+		}
+		reviewOpt.APIs = append(reviewOpt.APIs, reviewAPI(ctx, c))
+		names[ai.ReviewTargetCollection][c.ID] = c.Title
+	}
+	for _, ds := range schemas {
+		if ds.Type == definition.SchemaCategory || ds.Schema == "" {
+			continue
+		}
+		if len(reviewOpt.Schemas) >= maxReviewSchemas {
+			res.Truncated = true
+			break
+		}
+		reviewOpt.Schemas = append(reviewOpt.Schemas, ai.ReviewSchema{ID: ds.ID, Name: ds.Name, Schema: ds.Schema})
+		names[ai.ReviewTargetSchema][ds.ID] = ds.Name
+	}
+	if len(reviewOpt.APIs) == 0 && len(reviewOpt.Schemas) == 0 {
+		return res, nil
+	}
+
+	findings, err := ai.APIReview(ctx, jwt.GetUser(ctx).Language, reviewOpt)
+	if err != nil {
+		if errors.Is(err, aiusage.ErrQuotaExceeded) {
+			return nil, ginrpc.NewError(http.StatusTooManyRequests, i18n.NewErr("aiUsage.QuotaExceeded"))
+		}
+		slog.ErrorContext(ctx, "ai.APIReview", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("aiReview.ReviewFailed"))
+	}
+	for _, f := range findings {
+		res.Findings = append(res.Findings, &projectresponse.AIReviewFinding{
+			Category:   f.Category,
+			Severity:   f.Severity,
+			TargetType: f.TargetType,
+			TargetID:   f.TargetID,
+			TargetName: names[f.TargetType][f.TargetID],
+			Pointer:    f.Pointer,
+			Message:    f.Message,
+			Suggestion: f.Suggestion,
+			Patch:      f.Patch,
+		})
+	}
+	return res, nil
+}
+
+// reviewAPI 接口摘要帮助模型理解接口，无法生成摘要的不完整接口只提供原始内容
+func reviewAPI(ctx *gin.Context, c *collection.Collection) ai.ReviewAPI {
+	api := ai.ReviewAPI{ID: c.ID, Title: c.Title, Content: c.Content}
+	sc, err := relations.CollectionDerefWithSpec(ctx, c)
+	if err != nil {
+		slog.WarnContext(ctx, "relations.CollectionDerefWithSpec", "err", err, "collection", c.ID)
+		return api
+	}
+	if summary, err := ai.APISummarize(ctx, sc); err == nil {
+		api.Summary = summary
+	}
+	return api
+}
+
+// Apply 将评审建议的修改应用到接口或模型，与直接编辑一样记录历史，需要审批的目标提交为修改提案
+func (parai *projectAIReviewApiImpl) Apply(ctx *gin.Context, opt *projectrequest.ApplyAIReviewPatchOption) (*protobase.WriteResult, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	// 应用评审建议与直接编辑接口或模型需要相同的能力
+	capability := project.CapabilityEditCollection
+	if opt.TargetType == proposal.TargetSchema {
+		capability = project.CapabilityEditSchema
+	}
+	if ok, err := selfPM.HasCapability(ctx, capability); err != nil {
+		slog.ErrorContext(ctx, "selfPM.HasCapability", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
+	} else if !ok {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	t, err := proposalservice.GetTarget(ctx, selfPM.ProjectID, opt.TargetType, opt.TargetID)
+	if err != nil {
+		slog.ErrorContext(ctx, "proposalservice.GetTarget", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
+	}
+	if t == nil || t.IsCategory() {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("aiReview.TargetDoesNotExist"))
+	}
+
+	var doc string
+	if t.Schema != nil {
+		doc = t.Schema.Schema
+	} else {
+		doc = t.Collection.Content
+	}
+	patched, err := jsonpatch.Apply([]byte(doc), opt.Patch)
+	if err != nil {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("aiReview.PatchCannotBeApplied"))
+	}
+	content := string(patched)
+
+	if t.Schema != nil {
+		if _, err := jsonschema.NewSchemaFromJson(content); err != nil {
+			return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("aiReview.PatchCannotBeApplied"))
+		}
+	} else {
+		cs, err := spec.NewCollectionNodesFromJson(content)
+		if err != nil {
+			return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("aiReview.PatchCannotBeApplied"))
+		}
+		cs.SortResponses()
+		if s, err := cs.ToJson(); err == nil {
+			content = s
+		}
+	}
+
+	if protected, err := proposalservice.IsProtected(ctx, t); err != nil {
+		slog.ErrorContext(ctx, "proposalservice.IsProtected", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
+	} else if protected {
+		if t.Schema != nil {
+			return submitProposal(ctx, t, t.Schema.Name, t.Schema.Description, content)
+		}
+		return submitProposal(ctx, t, t.Collection.Title, "", content)
+	}
+
+	var before auditservice.Fields
+	if t.Schema != nil {
+		before = schemaAuditFields(t.Schema)
+		err = relations.UpdateSchema(ctx, t.Schema, t.Schema.Name, t.Schema.Description, content, selfTM.ID)
+	} else {
+		before = proposalCollectionAuditFields(t)
+		err = relations.UpdateCollection(ctx, t.Collection, t.Collection.Title, content, selfTM.ID)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "relations.Update", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
+	}
+
+	if t.Schema != nil {
+		recordSchemaAudit(ctx, audit.ActionUpdate, t.Schema, before, schemaAuditFields(t.Schema))
+	} else {
+		recordAudit(ctx, audit.ActionUpdate, audit.TargetCollection, t.Collection.ID, t.Collection.Title, before, proposalCollectionAuditFields(t))
+	}
+	return &protobase.WriteResult{Status: protobase.WriteStatusUpdated}, nil
+}
@@ -0,0 +1,164 @@
+package project
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/share"
+	"github.com/apicat/apicat/v2/backend/module/cache"
+	"github.com/apicat/apicat/v2/backend/module/cache/common"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	"github.com/apicat/apicat/v2/backend/service/ai"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+	"github.com/apicat/apicat/v2/backend/service/assistant"
+	"github.com/apicat/apicat/v2/backend/utils/limiter"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+// guestLanguage 访客没有语言设置，使用提问的语言回答
+const guestLanguage = "the same language as the question"
+
+type projectAssistantApiImpl struct{}
+
+func NewProjectAssistantApi() protoproject.ProjectAssistantApi {
+	return &projectAssistantApiImpl{}
+}
+
+// newAssistantShareLimiter 按分享限制访客的提问次数，公开项目按项目限制
+func newAssistantShareLimiter(c common.Cache) *limiter.Limiter {
+	return limiter.NewLimiter(c, "assistant-share", limiter.Rule{
+		FreeAttempts: 60,
+		BaseDelay:    time.Second * 10,
+		MaxDelay:     time.Minute * 10,
+		Window:       time.Hour,
+	})
+}
+
+// newAssistantIPLimiter 按ip限制访客的提问次数，避免单个访客用完整个分享的次数
+func newAssistantIPLimiter(c common.Cache) *limiter.Limiter {
+	return limiter.NewLimiter(c, "assistant-ip", limiter.Rule{
+		FreeAttempts: 20,
+		BaseDelay:    time.Second * 10,
+		MaxDelay:     time.Minute * 10,
+		Window:       time.Hour,
+	})
+}
+
+// Ask 根据项目文档回答问题，访客通过单个文档的分享码只能询问该文档
+func (paai *projectAssistantApiImpl) Ask(ctx *gin.Context, opt *projectrequest.AskAssistantOption) (*projectresponse.AssistantAnswer, error) {
+	selfP := access.GetSelfProject(ctx)
+	language := userLanguage(ctx)
+
+	var collectionID uint
+	if access.GetSelfProjectMember(ctx) == nil {
+		language = guestLanguage
+		shareKey := "project-" + selfP.ID
+		if shareCode := ctx.Query("shareCode"); shareCode != "" {
+			stt := &share.ShareTmpToken{ShareToken: fmt.Sprintf("%x", md5.Sum([]byte(shareCode)))}
+			exist, err := stt.Get(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "stt.Get", "err", err)
+				return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("assistant.AnswerFailed"))
+			}
+			if exist {
+				shareKey = stt.ShareToken
+				collectionID = stt.CollectionID
+			}
+		}
+
+		c, err := cache.NewCache(config.Get().Cache.ToCfg())
+		if err != nil {
+			slog.ErrorContext(ctx, "cache.NewCache", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("assistant.AnswerFailed"))
+		}
+		shareLimiter := newAssistantShareLimiter(c)
+		ipLimiter := newAssistantIPLimiter(c)
+		if wait := max(shareLimiter.Wait(shareKey), ipLimiter.Wait(ctx.ClientIP())); wait > 0 {
+			return nil, tooManyQuestions(ctx, wait)
+		}
+		if _, err := shareLimiter.Fail(shareKey); err != nil {
+			slog.ErrorContext(ctx, "shareLimiter.Fail", "err", err)
+		}
+		if _, err := ipLimiter.Fail(ctx.ClientIP()); err != nil {
+			slog.ErrorContext(ctx, "ipLimiter.Fail", "err", err)
+		}
+	}
+
+	docs, err := assistant.Retrieve(ctx, selfP.ID, collectionID, opt.Question)
+	if err != nil {
+		slog.ErrorContext(ctx, "assistant.Retrieve", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("assistant.AnswerFailed"))
+	}
+
+	askOpt := assistant.BuildAskOption(ctx, docs)
+	askOpt.ProjectTitle = selfP.Title
+	askOpt.Question = opt.Question
+	for _, m := range opt.History {
+		askOpt.History = append(askOpt.History, ai.DocsMessage{Role: m.Role, Content: m.Content})
+	}
+
+	answer, err := ai.DocsAsk(ctx, language, askOpt)
+	if err != nil {
+		if errors.Is(err, aiusage.ErrQuotaExceeded) {
+			return nil, ginrpc.NewError(http.StatusTooManyRequests, i18n.NewErr("aiUsage.QuotaExceeded"))
+		}
+		slog.ErrorContext(ctx, "ai.DocsAsk", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("assistant.AnswerFailed"))
+	}
+
+	res := &projectresponse.AssistantAnswer{
+		Answer:    answer.Content,
+		Citations: make([]*projectresponse.AssistantCitation, 0),
+	}
+	for _, id := range answer.CollectionIDs {
+		for _, c := range docs.Collections {
+			if c.ID == id {
+				res.Citations = append(res.Citations, &projectresponse.AssistantCitation{
+					TargetType: "collection",
+					TargetID:   c.ID,
+					Title:      c.Title,
+					Method:     c.Method,
+					Path:       c.Path,
+				})
+			}
+		}
+	}
+	for _, id := range answer.SchemaIDs {
+		for _, ds := range docs.Schemas {
+			if ds.ID == id {
+				res.Citations = append(res.Citations, &projectresponse.AssistantCitation{
+					TargetType: "schema",
+					TargetID:   ds.ID,
+					Title:      ds.Name,
+				})
+			}
+		}
+	}
+	return res, nil
+}
+
+// tooManyQuestions 返回需要等待的错误，并告知客户端重试时间
+func tooManyQuestions(ctx *gin.Context, wait time.Duration) error {
+	seconds := int(math.Ceil(wait.Seconds()))
+	ctx.Header("Retry-After", strconv.Itoa(seconds))
+	return &ginrpc.Error{
+		Code: http.StatusTooManyRequests,
+		Err:  i18n.NewErr("assistant.TooManyQuestions"),
+		Attrs: map[string]any{
+			"retryAfter": seconds,
+		},
+	}
+}
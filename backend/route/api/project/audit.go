@@ -0,0 +1,138 @@
+package project
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/global"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+	notifyservice "github.com/apicat/apicat/v2/backend/service/notification"
+	webhookservice "github.com/apicat/apicat/v2/backend/service/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+func schemaAuditFields(ds *definition.DefinitionSchema) auditservice.Fields {
+	return auditservice.Fields{
+		"name":        ds.Name,
+		"description": ds.Description,
+		"type":        ds.Type,
+		"parentID":    ds.ParentID,
+		"schema":      ds.Schema,
+	}
+}
+
+func responseAuditFields(dr *definition.DefinitionResponse) auditservice.Fields {
+	return auditservice.Fields{
+		"name":        dr.Name,
+		"description": dr.Description,
+		"type":        dr.Type,
+		"parentID":    dr.ParentID,
+		"header":      dr.Header,
+		"content":     dr.Content,
+	}
+}
+
+func globalParameterAuditFields(gp *global.GlobalParameter) auditservice.Fields {
+	return auditservice.Fields{
+		"in":       gp.In,
+		"name":     gp.Name,
+		"required": gp.Required,
+		"schema":   gp.Schema,
+	}
+}
+
+func projectMemberAuditFields(pm *project.ProjectMember) auditservice.Fields {
+	return auditservice.Fields{
+		"memberID":   pm.MemberID,
+		"permission": pm.Permission,
+		"roleID":     pm.RoleID,
+	}
+}
+
+// projectMemberName 获取项目成员对应的用户名，用于审计日志展示
+func projectMemberName(ctx *gin.Context, pm *project.ProjectMember) string {
+	tm, err := pm.MemberInfo(ctx, true)
+	if err != nil {
+		return ""
+	}
+	u, err := tm.UserInfo(ctx, true)
+	if err != nil {
+		return ""
+	}
+	return u.Name
+}
+
+func recordAudit(ctx *gin.Context, action, targetType string, targetID any, targetName string, before, after auditservice.Fields) {
+	notifyChange(ctx, &auditservice.Entry{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		TargetName: targetName,
+		Before:     before,
+		After:      after,
+	}, nil)
+}
+
+// notifyChange 记录审计日志，并触发项目webhook和聊天通知
+func notifyChange(ctx *gin.Context, e *auditservice.Entry, detail func() *notifyservice.Detail) {
+	auditservice.Record(ctx, e)
+	notifySubscribers(ctx, e, detail)
+}
+
+// notifySubscribers 触发项目webhook和聊天通知，审计日志在事务中记录时在提交后单独调用
+func notifySubscribers(ctx *gin.Context, e *auditservice.Entry, detail func() *notifyservice.Detail) {
+	webhookservice.Notify(ctx, "", e)
+	notifyservice.Notify(ctx, "", e, detail)
+}
+
+// withTx 返回使用txCtx中事务的请求上下文，用于在事务中记录审计日志
+func withTx(ctx *gin.Context, txCtx context.Context) *gin.Context {
+	c := ctx.Copy()
+	c.Request = ctx.Request.WithContext(txCtx)
+	return c
+}
+
+func recordSchemaAudit(ctx *gin.Context, action string, ds *definition.DefinitionSchema, before, after auditservice.Fields) {
+	notifyChange(ctx, &auditservice.Entry{
+		Action:     action,
+		TargetType: audit.TargetDefinitionSchema,
+		TargetID:   ds.ID,
+		TargetName: ds.Name,
+		Before:     before,
+		After:      after,
+	}, func() *notifyservice.Detail {
+		return schemaNotifyDetail(ctx, action, ds, before)
+	})
+}
+
+func recordResponseAudit(ctx *gin.Context, action string, dr *definition.DefinitionResponse, before, after auditservice.Fields) {
+	recordAudit(ctx, action, audit.TargetDefinitionResponse, dr.ID, dr.Name, before, after)
+}
+
+// schemaNotifyDetail 模型修改时通知中附带模型差异和历史记录链接
+func schemaNotifyDetail(ctx *gin.Context, action string, ds *definition.DefinitionSchema, before auditservice.Fields) *notifyservice.Detail {
+	if action != audit.ActionUpdate || ds.Type == definition.SchemaCategory {
+		return nil
+	}
+
+	d := &notifyservice.Detail{Link: notifyservice.SchemaHistoryLink(ds.ProjectID, ds.ID, 0)}
+	if dsh, err := definition.GetLatestDefinitionSchemaHistory(ctx, ds.ID); err != nil {
+		slog.ErrorContext(ctx, "definition.GetLatestDefinitionSchemaHistory", "err", err)
+	} else if dsh != nil {
+		d.Link = notifyservice.SchemaHistoryLink(ds.ProjectID, ds.ID, dsh.ID)
+	}
+
+	if old, ok := before["schema"].(string); ok {
+		changes, err := notifyservice.SchemaChanges(old, ds.Schema)
+		if err != nil {
+			slog.ErrorContext(ctx, "notifyservice.SchemaChanges", "err", err)
+		} else {
+			d.Changes = changes
+		}
+	}
+	return d
+}
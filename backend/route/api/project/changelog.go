@@ -0,0 +1,258 @@
+package project
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/module/cache"
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	changelogservice "github.com/apicat/apicat/v2/backend/service/changelog"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+	"github.com/apicat/apicat/v2/backend/utils/onetime_token"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type projectChangelogApiImpl struct{}
+
+func NewProjectChangelogApi() protoproject.ProjectChangelogApi {
+	return &projectChangelogApiImpl{}
+}
+
+// changelogToken 变更日志导出token中保存的内容，导出时没有登录信息，需要记录语言
+type changelogToken struct {
+	Option projectrequest.GetChangelogExportPathOption `json:"option"`
+	Lang   string                                      `json:"lang"`
+}
+
+const changelogTimeLayout = "2006-01-02 15:04:05"
+
+// checkProjectDiffOption 校验对比参数，返回用于对比的另一个项目
+// 另一个项目需要和当前项目在同一团队，且当前成员是该项目的成员
+func checkProjectDiffOption(ctx *gin.Context, opt *projectrequest.ProjectDiffOption) (*project.Project, error) {
+	if opt.BaseProjectID == "" {
+		if opt.StartTime == 0 {
+			return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("changelog.BaseRequired"))
+		}
+		if opt.EndTime != 0 && opt.EndTime <= opt.StartTime {
+			return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("changelog.InvalidTimeRange"))
+		}
+		return nil, nil
+	}
+
+	base := &project.Project{ID: opt.BaseProjectID}
+	exist, err := base.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "base.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("changelog.DiffFailed"))
+	}
+	if !exist || base.TeamID != access.GetSelfProject(ctx).TeamID {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("project.DoesNotExist"))
+	}
+
+	pm := &project.ProjectMember{ProjectID: base.ID, MemberID: access.GetSelfTeamMember(ctx).ID}
+	exist, err = pm.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "pm.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("changelog.DiffFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+	return base, nil
+}
+
+// projectDiff 生成对比双方的快照并对比
+// base不为空时与base项目的当前内容对比，否则与当前项目StartTime时的内容对比
+func projectDiff(ctx *gin.Context, p, base *project.Project, opt *projectrequest.ProjectDiffOption, lang string) (*changelogservice.Changelog, error) {
+	var (
+		original, target *spec.Spec
+		err              error
+	)
+	res := &changelogservice.Changelog{
+		Title: p.Title,
+		To:    i18n.NewTran("changelog.Now").TranslateIn(lang),
+	}
+
+	if base != nil {
+		res.From = base.Title
+		original, err = relations.ProjectSpec(ctx, base)
+	} else {
+		start := time.Unix(opt.StartTime, 0)
+		res.From = start.Format(changelogTimeLayout)
+		original, err = relations.ProjectSpecAt(ctx, p, start)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.EndTime != 0 {
+		end := time.Unix(opt.EndTime, 0)
+		res.To = end.Format(changelogTimeLayout)
+		target, err = relations.ProjectSpecAt(ctx, p, end)
+	} else {
+		target, err = relations.ProjectSpec(ctx, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Report, err = diff.DiffSpec(original, target); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func userLanguage(ctx *gin.Context) string {
+	if u := jwt.GetUser(ctx); u != nil && u.Language != "" {
+		return u.Language
+	}
+	return "en-US"
+}
+
+// Diff 项目对比
+func (pcai *projectChangelogApiImpl) Diff(ctx *gin.Context, opt *projectrequest.ProjectDiffOption) (*projectresponse.ProjectDiff, error) {
+	base, err := checkProjectDiffOption(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := projectDiff(ctx, access.GetSelfProject(ctx), base, opt, userLanguage(ctx))
+	if err != nil {
+		slog.ErrorContext(ctx, "projectDiff", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("changelog.DiffFailed"))
+	}
+	return c.Response(ctx), nil
+}
+
+// GetExportPath 获取变更日志导出path
+func (pcai *projectChangelogApiImpl) GetExportPath(ctx *gin.Context, opt *projectrequest.GetChangelogExportPathOption) (*projectresponse.ExportProject, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	selfTM := access.GetSelfTeamMember(ctx)
+	if selfPM.Permission.Equal(project.ProjectMemberRead) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+	if _, err := checkProjectDiffOption(ctx, &opt.ProjectDiffOption); err != nil {
+		return nil, err
+	}
+
+	tokenKey := fmt.Sprintf(
+		"ExportChangelog-%d-%d",
+		selfTM.ID,
+		time.Now().Unix(),
+	)
+	c, err := cache.NewCache(config.Get().Cache.ToCfg())
+	if err != nil {
+		slog.ErrorContext(ctx, "cache.NewCache", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("changelog.ExportFailed"))
+	}
+	token, err := onetime_token.NewTokenHelper(c).GenerateToken(tokenKey, &changelogToken{Option: *opt, Lang: userLanguage(ctx)}, time.Minute)
+	if err != nil {
+		slog.ErrorContext(ctx, "onetime_token.GenerateToken", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("changelog.ExportFailed"))
+	}
+
+	return &projectresponse.ExportProject{
+		Path: fmt.Sprintf("/api/projects/%s/changelog/export/%s", selfPM.ProjectID, token),
+	}, nil
+}
+
+// ExportChangelog 导出变更日志，需返回不同的 Content-Type，单独处理
+func ExportChangelog(ctx *gin.Context) {
+	opt := &projectrequest.ExportCodeOption{}
+	if err := ctx.ShouldBindUri(opt); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ca, err := cache.NewCache(config.Get().Cache.ToCfg())
+	if err != nil {
+		slog.ErrorContext(ctx, "cache.NewCache", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("changelog.ExportFailed").Translate(ctx),
+		})
+		return
+	}
+	tokenHelper := onetime_token.NewTokenHelper(ca)
+
+	t := changelogToken{}
+	if !tokenHelper.CheckToken(opt.Code, &t) || t.Option.ProjectID != opt.ProjectID {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": i18n.NewTran("changelog.ExportFailed").Translate(ctx),
+		})
+		return
+	}
+	if err := tokenHelper.DelToken(opt.Code); err != nil {
+		slog.ErrorContext(ctx, "tokenHelper.DelToken", "err", err)
+	}
+
+	p := &project.Project{ID: t.Option.ProjectID}
+	exist, err := p.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "p.Get", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("changelog.ExportFailed").TranslateIn(t.Lang),
+		})
+		return
+	}
+	if !exist {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"message": i18n.NewTran("project.DoesNotExist").TranslateIn(t.Lang),
+		})
+		return
+	}
+
+	// 生成token时已校验过对比项目的权限
+	var base *project.Project
+	if t.Option.BaseProjectID != "" {
+		base = &project.Project{ID: t.Option.BaseProjectID}
+		if exist, err := base.Get(ctx); err != nil || !exist {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"message": i18n.NewTran("project.DoesNotExist").TranslateIn(t.Lang),
+			})
+			return
+		}
+	}
+
+	c, err := projectDiff(ctx, p, base, &t.Option.ProjectDiffOption, t.Lang)
+	if err != nil {
+		slog.ErrorContext(ctx, "projectDiff", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("changelog.ExportFailed").TranslateIn(t.Lang),
+		})
+		return
+	}
+
+	var (
+		content     []byte
+		contentType string
+		ext         string
+	)
+	switch t.Option.Type {
+	case "HTML":
+		content, contentType, ext = c.HTML(t.Lang), "text/html; charset=utf-8", ".html"
+	default:
+		content, contentType, ext = c.Markdown(t.Lang), "text/markdown; charset=utf-8", ".md"
+	}
+
+	if t.Option.Download {
+		ctx.Header("Content-Disposition", "attachment; filename="+fmt.Sprintf("%s-changelog%s", p.Title, ext))
+		ctx.Data(http.StatusOK, "application/octet-stream", content)
+		return
+	}
+	ctx.Data(http.StatusOK, contentType, content)
+}
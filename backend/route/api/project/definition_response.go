@@ -1,11 +1,15 @@
 package project
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
+	"github.com/apicat/apicat/v2/backend/config"
 	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/definition"
 	"github.com/apicat/apicat/v2/backend/model/project"
 	"github.com/apicat/apicat/v2/backend/module/spec"
@@ -15,6 +19,8 @@ import (
 	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
 	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
 	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+	proposalservice "github.com/apicat/apicat/v2/backend/service/proposal"
 	"github.com/apicat/apicat/v2/backend/service/reference"
 
 	"github.com/apicat/ginrpc"
@@ -73,6 +79,7 @@ func (drai *definitionResponseApiImpl) Create(ctx *gin.Context, opt *projectrequ
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponse.CreationFailed"))
 	}
 
+	recordResponseAudit(ctx, audit.ActionCreate, dr, nil, responseAuditFields(dr))
 	userInfo := jwt.GetUser(ctx)
 	return convertModelDefinitionResponse(dr, userInfo, userInfo), nil
 }
@@ -150,6 +157,7 @@ func (drai *definitionResponseApiImpl) Update(ctx *gin.Context, opt *projectrequ
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
 	}
 
+	before := responseAuditFields(dr)
 	dr.Name = opt.Name
 	dr.Description = opt.Description
 	dr.Header = opt.Header
@@ -166,6 +174,7 @@ func (drai *definitionResponseApiImpl) Update(ctx *gin.Context, opt *projectrequ
 		slog.ErrorContext(ctx, "dr.Update", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
 	}
+	recordResponseAudit(ctx, audit.ActionUpdate, dr, before, responseAuditFields(dr))
 
 	return &ginrpc.Empty{}, nil
 }
@@ -201,6 +210,14 @@ func (drai *definitionResponseApiImpl) Delete(ctx *gin.Context, opt *projectrequ
 		}
 	}
 
+	if err := proposalservice.CheckResponseDelete(ctx, dr); err != nil {
+		if errors.Is(err, proposalservice.ErrProtected) {
+			return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("proposal.TargetProtected"))
+		}
+		slog.ErrorContext(ctx, "proposalservice.CheckResponseDelete", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponse.FailedToDelete"))
+	}
+
 	if err := dr.Delete(ctx, selfTM); err != nil {
 		slog.ErrorContext(ctx, "dr.Delete", "err", err)
 		if dr.Type == definition.ResponseCategory {
@@ -208,6 +225,7 @@ func (drai *definitionResponseApiImpl) Delete(ctx *gin.Context, opt *projectrequ
 		}
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponse.FailedToDelete"))
 	}
+	recordResponseAudit(ctx, audit.ActionDelete, dr, responseAuditFields(dr), nil)
 
 	if dr.Type != definition.ResponseCategory {
 		if err := reference.DerefResponse(ctx, dr, opt.Deref); err != nil {
@@ -262,10 +280,14 @@ func (drai *definitionResponseApiImpl) Move(ctx *gin.Context, opt *projectreques
 			continue
 		}
 
+		before := dr.ParentID
 		if err := dr.Sort(ctx, opt.Target.ParentID, uint(i+1)); err != nil {
 			slog.ErrorContext(ctx, "Target.dr.Sort", "err", err)
 			continue
 		}
+		if before != opt.Target.ParentID {
+			recordResponseAudit(ctx, audit.ActionMove, dr, auditservice.Fields{"parentID": before}, auditservice.Fields{"parentID": opt.Target.ParentID})
+		}
 	}
 
 	if opt.Target.ParentID != opt.Origin.ParentID {
@@ -324,6 +346,74 @@ func (drai *definitionResponseApiImpl) Copy(ctx *gin.Context, opt *projectreques
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponse.CopyFailed"))
 	}
 
+	recordResponseAudit(ctx, audit.ActionCopy, newDR, auditservice.Fields{"copyFrom": dr.ID}, responseAuditFields(newDR))
 	userInfo := jwt.GetUser(ctx)
 	return convertModelDefinitionResponse(newDR, userInfo, userInfo), nil
 }
+
+func (drai *definitionResponseApiImpl) Trashes(ctx *gin.Context, opt *protobase.ProjectIdOption) (*projectresponse.DefinitionTrashList, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	responses, err := definition.GetDeletedDefinitionResponses(ctx, selfPM.ProjectID, config.Get().App.TrashDeadline(time.Now()))
+	if err != nil {
+		slog.ErrorContext(ctx, "definition.GetDeletedDefinitionResponses", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponse.FailedToGetList"))
+	}
+
+	list := make(projectresponse.DefinitionTrashList, 0, len(responses))
+	for _, dr := range responses {
+		if userInfo, err := definition.UserInfo(ctx, dr.DeletedBy, true); err == nil {
+			list = append(list, &projectresponse.DefinitionTrash{
+				ID:   dr.ID,
+				Name: dr.Name,
+				TrashDeleteInfo: projectresponse.TrashDeleteInfo{
+					DeletedAt: dr.DeletedAt.Time,
+					DeletedBy: userInfo.Name,
+				},
+			})
+		}
+	}
+	return &list, nil
+}
+
+func (drai *definitionResponseApiImpl) Restore(ctx *gin.Context, opt *projectrequest.RestoreDefinitionResponsesOption) (*projectresponse.RestoreNum, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	responses, err := definition.GetDeletedDefinitionResponsesByIDs(ctx, selfPM.ProjectID, opt.ResponseIDs)
+	if err != nil {
+		slog.ErrorContext(ctx, "definition.GetDeletedDefinitionResponsesByIDs", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponse.RestoreFailed"))
+	}
+	if len(responses) == 0 {
+		return &projectresponse.RestoreNum{Num: 0}, nil
+	}
+
+	restoreIDs, err := definition.RestoreDefinitionResponses(ctx, selfTM, responses)
+	if err != nil {
+		slog.ErrorContext(ctx, "definition.RestoreDefinitionResponses", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponse.RestoreFailed"))
+	}
+
+	restored := make(map[uint]bool, len(restoreIDs))
+	for _, id := range restoreIDs {
+		restored[id] = true
+	}
+	for _, dr := range responses {
+		if !restored[dr.ID] {
+			continue
+		}
+		if err := reference.RestoreResponseRef(ctx, dr); err != nil {
+			slog.ErrorContext(ctx, "reference.RestoreResponseRef", "err", err)
+		}
+		recordResponseAudit(ctx, audit.ActionRestore, dr, nil, responseAuditFields(dr))
+	}
+
+	return &projectresponse.RestoreNum{Num: len(restoreIDs)}, nil
+}
@@ -0,0 +1,303 @@
+package project
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	"github.com/apicat/apicat/v2/backend/service/compatibility"
+	"github.com/apicat/apicat/v2/backend/service/reference"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type definitionResponseHistoryApiImpl struct{}
+
+func NewDefinitionResponseHistoryApi() protoproject.DefinitionResponseHistoryApi {
+	return &definitionResponseHistoryApiImpl{}
+}
+
+func (impl *definitionResponseHistoryApiImpl) List(ctx *gin.Context, opt *projectrequest.GetDefinitionResponseHistoryListOption) (*projectresponse.DefinitionResponseHistoryList, error) {
+	selfP := access.GetSelfProject(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	dr := &definition.DefinitionResponse{
+		ID:        opt.ResponseID,
+		ProjectID: selfP.ID,
+	}
+	exist, err := dr.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "dr.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.FailedToGetList"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("definitionResponse.DoesNotExist"))
+	}
+
+	startTime := time.Unix(opt.StartTime, 0)
+	endTime := time.Unix(opt.EndTime, 0)
+	list, err := definition.GetDefinitionResponseHistories(ctx, dr, startTime, endTime)
+	if err != nil {
+		slog.ErrorContext(ctx, "definition.GetDefinitionResponseHistories", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.FailedToGetList"))
+	}
+
+	res := make(projectresponse.DefinitionResponseHistoryList, 0, len(list))
+	for _, v := range list {
+		if userInfo, err := definition.UserInfo(ctx, v.CreatedBy, true); err == nil {
+			res = append(res, &projectresponse.DefinitionResponseHistoryItem{
+				IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+					ID:        v.ID,
+					CreatedAt: v.CreatedAt.Unix(),
+				},
+				CreatedBy: userInfo.Name,
+			})
+		}
+	}
+
+	return &res, nil
+}
+
+func (impl *definitionResponseHistoryApiImpl) Get(ctx *gin.Context, opt *projectrequest.DefinitionResponseHistoryIDOption) (*projectresponse.DefinitionResponseHistory, error) {
+	selfP := access.GetSelfProject(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	dr := &definition.DefinitionResponse{
+		ID:        opt.ResponseID,
+		ProjectID: selfP.ID,
+	}
+	exist, err := dr.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "dr.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("definitionResponse.DoesNotExist"))
+	}
+
+	h := &definition.DefinitionResponseHistory{
+		ID:         opt.HistoryID,
+		ResponseID: dr.ID,
+	}
+	exist, err = h.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "h.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("definitionResponseHistory.DoesNotExist"))
+	}
+
+	userInfo, err := definition.UserInfo(ctx, h.CreatedBy, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "h.CreatedMember.UserInfo", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.FailedToGet"))
+	}
+
+	return convertModelDefinitionResponseHistory(h, userInfo), nil
+}
+
+func (impl *definitionResponseHistoryApiImpl) Restore(ctx *gin.Context, opt *projectrequest.DefinitionResponseHistoryIDOption) (*ginrpc.Empty, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	dr := &definition.DefinitionResponse{
+		ID:        opt.ResponseID,
+		ProjectID: selfPM.ProjectID,
+	}
+	exist, err := dr.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "dr.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.RestoreFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("definitionResponse.DoesNotExist"))
+	}
+
+	h := &definition.DefinitionResponseHistory{
+		ID:         opt.HistoryID,
+		ResponseID: dr.ID,
+	}
+	exist, err = h.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "h.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.RestoreFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("definitionResponseHistory.DoesNotExist"))
+	}
+
+	oldRefSchemaIDs, err := reference.ParseRefSchemasFromResponse(dr)
+	if err != nil {
+		slog.ErrorContext(ctx, "reference.ParseRefSchemasFromResponse", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.RestoreFailed"))
+	}
+
+	before := responseAuditFields(dr)
+	if err := h.Restore(ctx, dr, selfTM.ID); err != nil {
+		slog.ErrorContext(ctx, "h.Restore", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.RestoreFailed"))
+	}
+
+	// 恢复后的响应引用的模型可能不同，需要更新引用关系
+	if err := reference.UpdateResponseRef(ctx, dr, oldRefSchemaIDs); err != nil {
+		slog.ErrorContext(ctx, "reference.UpdateResponseRef", "err", err)
+	}
+
+	after := responseAuditFields(dr)
+	after["historyID"] = h.ID
+	recordResponseAudit(ctx, audit.ActionRestore, dr, before, after)
+
+	return &ginrpc.Empty{}, nil
+}
+
+func (impl *definitionResponseHistoryApiImpl) Diff(ctx *gin.Context, opt *projectrequest.DiffDefinitionResponseHistoriesOption) (*projectresponse.DiffDefinitionResponseHistories, error) {
+	selfP := access.GetSelfProject(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	dr := &definition.DefinitionResponse{
+		ID:        opt.ResponseID,
+		ProjectID: selfP.ID,
+	}
+	exist, err := dr.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "dr.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.DiffFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("definitionResponse.DoesNotExist"))
+	}
+	if dr.Type == definition.ResponseCategory {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("definitionResponseHistory.DiffFailed"))
+	}
+
+	originalDRH := &definition.DefinitionResponseHistory{
+		ID:         opt.OriginalID,
+		ResponseID: dr.ID,
+	}
+	exist, err = originalDRH.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "originalDRH.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.DiffFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("definitionResponseHistory.DoesNotExist"))
+	}
+	originalDRHUserInfo, err := definition.UserInfo(ctx, originalDRH.CreatedBy, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "originalDRH.CreatedMember.UserInfo", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.DiffFailed"))
+	}
+
+	var targetDRH *definition.DefinitionResponseHistory
+	if opt.TargetID == 0 {
+		targetDRH = &definition.DefinitionResponseHistory{
+			ResponseID:  dr.ID,
+			Name:        dr.Name,
+			Description: dr.Description,
+			Header:      dr.Header,
+			Content:     dr.Content,
+			CreatedBy:   dr.UpdatedBy,
+			TimeModel: model.TimeModel{
+				CreatedAt: dr.UpdatedAt,
+				UpdatedAt: dr.UpdatedAt,
+			},
+		}
+	} else {
+		targetDRH = &definition.DefinitionResponseHistory{
+			ID:         opt.TargetID,
+			ResponseID: dr.ID,
+		}
+		exist, err = targetDRH.Get(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "targetDRH.Get", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.DiffFailed"))
+		}
+		if !exist {
+			return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("definitionResponseHistory.DoesNotExist"))
+		}
+	}
+	targetDRHUserInfo, err := definition.UserInfo(ctx, targetDRH.CreatedBy, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "targetDRH.CreatedMember.UserInfo", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.DiffFailed"))
+	}
+
+	// 对响应中引用的模型进行解引用
+	dr.Name, dr.Description, dr.Header, dr.Content = originalDRH.Name, originalDRH.Description, originalDRH.Header, originalDRH.Content
+	originalResponse, err := drDerefWithSpec(ctx, dr)
+	if err != nil {
+		slog.ErrorContext(ctx, "original.drDerefWithSpec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.DiffFailed"))
+	}
+
+	dr.Name, dr.Description, dr.Header, dr.Content = targetDRH.Name, targetDRH.Description, targetDRH.Header, targetDRH.Content
+	targetResponse, err := drDerefWithSpec(ctx, dr)
+	if err != nil {
+		slog.ErrorContext(ctx, "target.drDerefWithSpec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.DiffFailed"))
+	}
+
+	// diff.DiffResponse会修改targetResponse，需要先做兼容性检查
+	breakingChanges := compatibility.Response(ctx, originalResponse, targetResponse)
+	if err := diff.DiffResponse(originalResponse, targetResponse); err != nil {
+		slog.ErrorContext(ctx, "diff.DiffResponse", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.DiffFailed"))
+	}
+
+	if err := marshalResponseHistory(originalDRH, originalResponse); err != nil {
+		slog.ErrorContext(ctx, "originalResponse.json.Marshal", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.DiffFailed"))
+	}
+	if err := marshalResponseHistory(targetDRH, targetResponse); err != nil {
+		slog.ErrorContext(ctx, "targetResponse.json.Marshal", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionResponseHistory.DiffFailed"))
+	}
+
+	return &projectresponse.DiffDefinitionResponseHistories{
+		Response1:       convertModelDefinitionResponseHistory(originalDRH, originalDRHUserInfo),
+		Response2:       convertModelDefinitionResponseHistory(targetDRH, targetDRHUserInfo),
+		BreakingChanges: breakingChanges,
+	}, nil
+}
+
+// marshalResponseHistory 用解引用并标记差异后的响应替换历史记录中的header和content
+func marshalResponseHistory(h *definition.DefinitionResponseHistory, r *spec.DefinitionResponse) error {
+	header, err := json.Marshal(r.Header)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(r.Content)
+	if err != nil {
+		return err
+	}
+	h.Header = string(header)
+	h.Content = string(content)
+	return nil
+}
@@ -1,22 +1,33 @@
 package project
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
+	"github.com/apicat/apicat/v2/backend/config"
 	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/definition"
 	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/proposal"
 	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+	"github.com/apicat/apicat/v2/backend/module/spec/plugin/schemaconv"
 	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	"github.com/apicat/apicat/v2/backend/route/middleware/dump"
 	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
 	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
 	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
 	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
 	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
 	"github.com/apicat/apicat/v2/backend/service/ai"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+	proposalservice "github.com/apicat/apicat/v2/backend/service/proposal"
 	"github.com/apicat/apicat/v2/backend/service/reference"
+	translationservice "github.com/apicat/apicat/v2/backend/service/translation"
 
 	"github.com/apicat/ginrpc"
 	"github.com/gin-gonic/gin"
@@ -72,6 +83,7 @@ func (dsai *definitionSchemaApiImpl) Create(ctx *gin.Context, opt *projectreques
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.CreationFailed"))
 	}
 
+	recordSchemaAudit(ctx, audit.ActionCreate, ds, nil, schemaAuditFields(ds))
 	userInfo := jwt.GetUser(ctx)
 	return convertModelDefinitionSchema(ds, userInfo, userInfo), nil
 }
@@ -88,7 +100,7 @@ func (dsai *definitionSchemaApiImpl) List(ctx *gin.Context, opt *protobase.Proje
 	return &tree, nil
 }
 
-func (dsai *definitionSchemaApiImpl) Get(ctx *gin.Context, opt *projectrequest.GetDefinitionSchemaOption) (*projectresponse.DefinitionSchema, error) {
+func (dsai *definitionSchemaApiImpl) Get(ctx *gin.Context, opt *projectrequest.GetDefinitionSchemaDetailOption) (*projectresponse.DefinitionSchema, error) {
 	selfP := access.GetSelfProject(ctx)
 	ds := &definition.DefinitionSchema{ID: opt.SchemaID, ProjectID: selfP.ID}
 	exist, err := ds.Get(ctx)
@@ -123,10 +135,19 @@ func (dsai *definitionSchemaApiImpl) Get(ctx *gin.Context, opt *projectrequest.G
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.FailedToGet"))
 	}
 
-	return convertModelDefinitionSchema(ds, cUserInfo, uUserInfo), nil
+	res := convertModelDefinitionSchema(ds, cUserInfo, uUserInfo)
+	t, err := translationservice.GetSchemaTranslation(ctx, ds, translationservice.Locale(ctx, opt.Lang))
+	if err != nil {
+		slog.ErrorContext(ctx, "translationservice.GetSchemaTranslation", "err", err)
+	} else if t != nil {
+		res.Description = t.Description
+		res.Schema = t.Content
+		res.Lang = t.Locale
+	}
+	return res, nil
 }
 
-func (dsai *definitionSchemaApiImpl) Update(ctx *gin.Context, opt *projectrequest.UpdateDefinitionSchemaOption) (*ginrpc.Empty, error) {
+func (dsai *definitionSchemaApiImpl) Update(ctx *gin.Context, opt *projectrequest.UpdateDefinitionSchemaOption) (*protobase.WriteResult, error) {
 	selfTM := access.GetSelfTeamMember(ctx)
 	selfPM := access.GetSelfProjectMember(ctx)
 	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
@@ -143,12 +164,21 @@ func (dsai *definitionSchemaApiImpl) Update(ctx *gin.Context, opt *projectreques
 		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("definitionSchema.DoesNotExist"))
 	}
 
+	t := &proposalservice.Target{Schema: ds}
+	if protected, err := proposalservice.IsProtected(ctx, t); err != nil {
+		slog.ErrorContext(ctx, "proposalservice.IsProtected", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
+	} else if protected {
+		return submitProposal(ctx, t, opt.Name, opt.Description, opt.Schema)
+	}
+
 	oldRefSchemaIDs, err := reference.ParseRefSchemasFromSchema(ds)
 	if err != nil {
 		slog.ErrorContext(ctx, "reference.ParseRefSchemasFromSchema", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
 	}
 
+	before := schemaAuditFields(ds)
 	ds.Name = opt.Name
 	ds.Description = opt.Description
 	ds.Schema = opt.Schema
@@ -165,8 +195,9 @@ func (dsai *definitionSchemaApiImpl) Update(ctx *gin.Context, opt *projectreques
 		slog.ErrorContext(ctx, "ds.Update", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
 	}
+	recordSchemaAudit(ctx, audit.ActionUpdate, ds, before, schemaAuditFields(ds))
 
-	return &ginrpc.Empty{}, nil
+	return &protobase.WriteResult{Status: protobase.WriteStatusUpdated}, nil
 }
 
 func (dsai *definitionSchemaApiImpl) Delete(ctx *gin.Context, opt *projectrequest.DeleteDefinitionSchemaOption) (*ginrpc.Empty, error) {
@@ -200,6 +231,14 @@ func (dsai *definitionSchemaApiImpl) Delete(ctx *gin.Context, opt *projectreques
 		}
 	}
 
+	if err := proposalservice.CheckSchemaDelete(ctx, ds); err != nil {
+		if errors.Is(err, proposalservice.ErrProtected) {
+			return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("proposal.TargetProtected"))
+		}
+		slog.ErrorContext(ctx, "proposalservice.CheckSchemaDelete", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.FailedToDelete"))
+	}
+
 	if err := ds.Delete(ctx, selfTM); err != nil {
 		slog.ErrorContext(ctx, "ds.Delete", "err", err)
 		if ds.Type == definition.SchemaCategory {
@@ -207,6 +246,7 @@ func (dsai *definitionSchemaApiImpl) Delete(ctx *gin.Context, opt *projectreques
 		}
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.FailedToDelete"))
 	}
+	recordSchemaAudit(ctx, audit.ActionDelete, ds, schemaAuditFields(ds), nil)
 
 	if ds.Type != definition.SchemaCategory {
 		if err := reference.DerefSchema(ctx, ds, opt.Deref); err != nil {
@@ -261,10 +301,14 @@ func (dsai *definitionSchemaApiImpl) Move(ctx *gin.Context, opt *projectrequest.
 			continue
 		}
 
+		before := ds.ParentID
 		if err := ds.Sort(ctx, opt.Target.ParentID, uint(i+1)); err != nil {
 			slog.ErrorContext(ctx, "ds.Sort", "err", err)
 			continue
 		}
+		if before != opt.Target.ParentID {
+			recordSchemaAudit(ctx, audit.ActionMove, ds, auditservice.Fields{"parentID": before}, auditservice.Fields{"parentID": opt.Target.ParentID})
+		}
 	}
 
 	if opt.Target.ParentID != opt.Origin.ParentID {
@@ -322,15 +366,98 @@ func (dsai *definitionSchemaApiImpl) Copy(ctx *gin.Context, opt *projectrequest.
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.CopyFailed"))
 	}
 
+	recordSchemaAudit(ctx, audit.ActionCopy, newDS, auditservice.Fields{"copyFrom": ds.ID}, schemaAuditFields(newDS))
 	userInfo := jwt.GetUser(ctx)
 	return convertModelDefinitionSchema(newDS, userInfo, userInfo), nil
 }
 
 func (dsai *definitionSchemaApiImpl) AIGenerate(ctx *gin.Context, opt *projectrequest.AIGenerateSchemaOption) (*projectresponse.DefinitionSchema, error) {
-	selfTM := access.GetSelfTeamMember(ctx)
+	if err := checkAIGenerateSchema(ctx, opt); err != nil {
+		return nil, err
+	}
+
+	var ds *definition.DefinitionSchema
+	if isSchemaSourceMode(opt.Mode) {
+		var rerr *ginrpc.Error
+		if ds, rerr = generateSchemaFromSource(ctx, opt); rerr != nil {
+			return nil, rerr
+		}
+	} else {
+		var err error
+		if ds, err = ai.SchemaGenerate(ctx, opt.Prompt); err != nil {
+			if errors.Is(err, aiusage.ErrQuotaExceeded) {
+				return nil, ginrpc.NewError(http.StatusTooManyRequests, i18n.NewErr("aiUsage.QuotaExceeded"))
+			}
+			slog.ErrorContext(ctx, "ai.SchemaGenerate", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.GenerationFailed"))
+		}
+	}
+
+	res, rerr := createAIGeneratedSchema(ctx, opt, ds)
+	if rerr != nil {
+		return nil, rerr
+	}
+	return res, nil
+}
+
+// AIGenerateSchemaStream 以 SSE 流式返回 AI 生成的内容，完成后创建模型并通过 result 事件返回
+func AIGenerateSchemaStream(ctx *gin.Context) {
+	opt := &projectrequest.AIGenerateSchemaOption{}
+	opt.ProjectID = ctx.Param("projectID")
+	if err := ctx.ShouldBindJSON(opt); err != nil {
+		dump.Response(ctx, nil, ginrpc.NewError(http.StatusBadRequest, err))
+		return
+	}
+	if err := checkAIGenerateSchema(ctx, opt); err != nil {
+		dump.Response(ctx, nil, err)
+		return
+	}
+
+	// 源码模式由转换结果直接生成，一次性返回完整内容
+	if isSchemaSourceMode(opt.Mode) {
+		ds, rerr := generateSchemaFromSource(ctx, opt)
+		if rerr != nil {
+			dump.Response(ctx, nil, rerr)
+			return
+		}
+		res, rerr := createAIGeneratedSchema(ctx, opt, ds)
+		if rerr != nil {
+			dump.Response(ctx, nil, rerr)
+			return
+		}
+		dump.StartEvents(ctx)
+		dump.Event(ctx, dump.EventDelta, gin.H{"content": ds.Schema})
+		dump.Event(ctx, dump.EventResult, res)
+		return
+	}
+
+	dump.StartEvents(ctx)
+	ds, err := ai.SchemaGenerateStream(ctx, opt.Prompt, func(delta string) error {
+		return dump.Event(ctx, dump.EventDelta, gin.H{"content": delta})
+	})
+	if err != nil {
+		if errors.Is(err, aiusage.ErrQuotaExceeded) {
+			dump.EventErr(ctx, ginrpc.NewError(http.StatusTooManyRequests, i18n.NewErr("aiUsage.QuotaExceeded")))
+			return
+		}
+		slog.ErrorContext(ctx, "ai.SchemaGenerateStream", "err", err)
+		dump.EventErr(ctx, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.GenerationFailed")))
+		return
+	}
+
+	res, rerr := createAIGeneratedSchema(ctx, opt, ds)
+	if rerr != nil {
+		dump.EventErr(ctx, rerr)
+		return
+	}
+	dump.Event(ctx, dump.EventResult, res)
+}
+
+// checkAIGenerateSchema 生成前检查权限和父级分类
+func checkAIGenerateSchema(ctx *gin.Context, opt *projectrequest.AIGenerateSchemaOption) *ginrpc.Error {
 	selfPM := access.GetSelfProjectMember(ctx)
 	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
-		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+		return ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
 	}
 
 	if opt.ParentID != 0 {
@@ -338,18 +465,39 @@ func (dsai *definitionSchemaApiImpl) AIGenerate(ctx *gin.Context, opt *projectre
 		exist, err := parentDS.Get(ctx)
 		if err != nil {
 			slog.ErrorContext(ctx, "parentDS.Get", "err", err)
-			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.GenerationFailed"))
+			return ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.GenerationFailed"))
 		}
 		if !exist {
-			return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("category.DoesNotExist"))
+			return ginrpc.NewError(http.StatusNotFound, i18n.NewErr("category.DoesNotExist"))
 		}
 	}
+	return nil
+}
+
+func isSchemaSourceMode(mode string) bool {
+	return mode != "" && mode != "prompt"
+}
 
-	ds, err := ai.SchemaGenerate(ctx, opt.Prompt)
+// generateSchemaFromSource 从示例 JSON、SQL、Go 或 TypeScript 源码生成模型
+func generateSchemaFromSource(ctx *gin.Context, opt *projectrequest.AIGenerateSchemaOption) (*definition.DefinitionSchema, *ginrpc.Error) {
+	ds, err := ai.SchemaGenerateFromSource(ctx, opt.Mode, opt.Prompt)
 	if err != nil {
-		slog.ErrorContext(ctx, "ai.SchemaGenerate", "err", err)
+		if errors.Is(err, schemaconv.ErrInvalidSource) {
+			return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("definitionSchema.SourceParseFailed"))
+		}
+		if errors.Is(err, aiusage.ErrQuotaExceeded) {
+			return nil, ginrpc.NewError(http.StatusTooManyRequests, i18n.NewErr("aiUsage.QuotaExceeded"))
+		}
+		slog.ErrorContext(ctx, "ai.SchemaGenerateFromSource", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.GenerationFailed"))
 	}
+	return ds, nil
+}
+
+// createAIGeneratedSchema 保存生成的模型
+func createAIGeneratedSchema(ctx *gin.Context, opt *projectrequest.AIGenerateSchemaOption, ds *definition.DefinitionSchema) (*projectresponse.DefinitionSchema, *ginrpc.Error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
 
 	ds.ProjectID = selfPM.ProjectID
 	ds.ParentID = opt.ParentID
@@ -361,6 +509,86 @@ func (dsai *definitionSchemaApiImpl) AIGenerate(ctx *gin.Context, opt *projectre
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.GenerationFailed"))
 	}
 
+	recordSchemaAudit(ctx, audit.ActionCreate, ds, nil, schemaAuditFields(ds))
 	userInfo := jwt.GetUser(ctx)
 	return convertModelDefinitionSchema(ds, userInfo, userInfo), nil
 }
+
+func (dsai *definitionSchemaApiImpl) Trashes(ctx *gin.Context, opt *protobase.ProjectIdOption) (*projectresponse.DefinitionTrashList, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	schemas, err := definition.GetDeletedDefinitionSchemas(ctx, selfPM.ProjectID, config.Get().App.TrashDeadline(time.Now()))
+	if err != nil {
+		slog.ErrorContext(ctx, "definition.GetDeletedDefinitionSchemas", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.FailedToGetList"))
+	}
+
+	list := make(projectresponse.DefinitionTrashList, 0, len(schemas))
+	for _, ds := range schemas {
+		if userInfo, err := definition.UserInfo(ctx, ds.DeletedBy, true); err == nil {
+			list = append(list, &projectresponse.DefinitionTrash{
+				ID:   ds.ID,
+				Name: ds.Name,
+				TrashDeleteInfo: projectresponse.TrashDeleteInfo{
+					DeletedAt: ds.DeletedAt.Time,
+					DeletedBy: userInfo.Name,
+				},
+			})
+		}
+	}
+	return &list, nil
+}
+
+func (dsai *definitionSchemaApiImpl) Restore(ctx *gin.Context, opt *projectrequest.RestoreDefinitionSchemasOption) (*projectresponse.RestoreNum, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	schemas, err := definition.GetDeletedDefinitionSchemasByIDs(ctx, selfPM.ProjectID, opt.SchemaIDs)
+	if err != nil {
+		slog.ErrorContext(ctx, "definition.GetDeletedDefinitionSchemasByIDs", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.RestoreFailed"))
+	}
+	if len(schemas) == 0 {
+		return &projectresponse.RestoreNum{Num: 0}, nil
+	}
+
+	ids := make([]uint, 0, len(schemas))
+	for _, ds := range schemas {
+		ids = append(ids, ds.ID)
+	}
+	if err := proposalservice.CheckProtected(ctx, selfPM.ProjectID, proposal.TargetSchema, ids...); err != nil {
+		if errors.Is(err, proposalservice.ErrProtected) {
+			return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("proposal.TargetProtected"))
+		}
+		slog.ErrorContext(ctx, "proposalservice.CheckProtected", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.RestoreFailed"))
+	}
+
+	restoreIDs, err := definition.RestoreDefinitionSchemas(ctx, selfTM, schemas)
+	if err != nil {
+		slog.ErrorContext(ctx, "definition.RestoreDefinitionSchemas", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchema.RestoreFailed"))
+	}
+
+	restored := make(map[uint]bool, len(restoreIDs))
+	for _, id := range restoreIDs {
+		restored[id] = true
+	}
+	for _, ds := range schemas {
+		if !restored[ds.ID] {
+			continue
+		}
+		if err := reference.RestoreSchemaRef(ctx, ds); err != nil {
+			slog.ErrorContext(ctx, "reference.RestoreSchemaRef", "err", err)
+		}
+		recordSchemaAudit(ctx, audit.ActionRestore, ds, nil, schemaAuditFields(ds))
+	}
+
+	return &projectresponse.RestoreNum{Num: len(restoreIDs)}, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/apicat/apicat/v2/backend/i18n"
 	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/definition"
 	"github.com/apicat/apicat/v2/backend/model/project"
 	"github.com/apicat/apicat/v2/backend/route/middleware/access"
@@ -15,6 +16,8 @@ import (
 	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
 	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
 	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	"github.com/apicat/apicat/v2/backend/service/compatibility"
+	proposalservice "github.com/apicat/apicat/v2/backend/service/proposal"
 
 	"github.com/apicat/apicat/v2/backend/module/spec/diff"
 
@@ -115,7 +118,7 @@ func (impl *definitionSchemaHistoryApiImpl) Get(ctx *gin.Context, opt *projectre
 	return convertModelDefinitionSchemaHistory(h, userInfo), nil
 }
 
-func (impl *definitionSchemaHistoryApiImpl) Restore(ctx *gin.Context, opt *projectrequest.DefinitionSchemaHistoryIDOption) (*ginrpc.Empty, error) {
+func (impl *definitionSchemaHistoryApiImpl) Restore(ctx *gin.Context, opt *projectrequest.DefinitionSchemaHistoryIDOption) (*protobase.WriteResult, error) {
 	selfTM := access.GetSelfTeamMember(ctx)
 	selfPM := access.GetSelfProjectMember(ctx)
 	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
@@ -148,12 +151,24 @@ func (impl *definitionSchemaHistoryApiImpl) Restore(ctx *gin.Context, opt *proje
 		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("definitionSchemaHistory.DoesNotExist"))
 	}
 
+	t := &proposalservice.Target{Schema: ds}
+	if protected, err := proposalservice.IsProtected(ctx, t); err != nil {
+		slog.ErrorContext(ctx, "proposalservice.IsProtected", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchemaHistory.RestoreFailed"))
+	} else if protected {
+		return submitProposal(ctx, t, h.Name, h.Description, h.Schema)
+	}
+
+	before := schemaAuditFields(ds)
 	if err := h.Restore(ctx, ds, selfTM.ID); err != nil {
 		slog.ErrorContext(ctx, "h.Restore", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchemaHistory.RestoreFailed"))
 	}
+	after := schemaAuditFields(ds)
+	after["historyID"] = h.ID
+	recordSchemaAudit(ctx, audit.ActionRestore, ds, before, after)
 
-	return &ginrpc.Empty{}, nil
+	return &protobase.WriteResult{Status: protobase.WriteStatusUpdated}, nil
 }
 
 func (impl *definitionSchemaHistoryApiImpl) Diff(ctx *gin.Context, opt *projectrequest.DiffDefinitionSchemaHistoriesOption) (*projectresponse.DiffDefinitionSchemaHistories, error) {
@@ -245,6 +260,8 @@ func (impl *definitionSchemaHistoryApiImpl) Diff(ctx *gin.Context, opt *projectr
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchemaHistory.DiffFailed"))
 	}
 
+	// diff.DiffModel会修改targetSchema，需要先做兼容性检查
+	breakingChanges := compatibility.Model(ctx, originalSchema, targetSchema)
 	if err := diff.DiffModel(originalSchema, targetSchema); err != nil {
 		slog.ErrorContext(ctx, "diff.DiffModel", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("definitionSchemaHistory.DiffFailed"))
@@ -265,7 +282,8 @@ func (impl *definitionSchemaHistoryApiImpl) Diff(ctx *gin.Context, opt *projectr
 	targetDSH.Schema = string(targetSchemaStr)
 
 	return &projectresponse.DiffDefinitionSchemaHistories{
-		Schema1: convertModelDefinitionSchemaHistory(originalDSH, originalDSHUserInfo),
-		Schema2: convertModelDefinitionSchemaHistory(targetDSH, targetDSHUserInfo),
+		Schema1:         convertModelDefinitionSchemaHistory(originalDSH, originalDSHUserInfo),
+		Schema2:         convertModelDefinitionSchemaHistory(targetDSH, targetDSHUserInfo),
+		BreakingChanges: breakingChanges,
 	}, nil
 }
@@ -7,9 +7,12 @@ import (
 
 	"github.com/apicat/apicat/v2/backend/model/definition"
 	"github.com/apicat/apicat/v2/backend/model/global"
+	"github.com/apicat/apicat/v2/backend/model/notification"
 	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/release"
 	"github.com/apicat/apicat/v2/backend/model/team"
 	"github.com/apicat/apicat/v2/backend/model/user"
+	"github.com/apicat/apicat/v2/backend/model/webhook"
 	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
 	projectbase "github.com/apicat/apicat/v2/backend/route/proto/project/base"
 	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
@@ -51,6 +54,67 @@ func convertModelProjectServer(s *project.Server) *projectresponse.ProjectServer
 	}
 }
 
+func convertModelWebhook(w *webhook.Webhook) *projectresponse.ProjectWebhook {
+	return &projectresponse.ProjectWebhook{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        w.ID,
+			CreatedAt: w.CreatedAt.Unix(),
+		},
+		Name:          w.Name,
+		URL:           w.URL,
+		HasSecret:     w.Secret != "",
+		Events:        w.GetEvents(),
+		Active:        w.Active,
+		MaxRetries:    w.MaxRetries,
+		RetryInterval: w.RetryInterval,
+	}
+}
+
+func convertModelRelease(ctx *gin.Context, r *release.Release) *projectresponse.ReleaseItem {
+	res := &projectresponse.ReleaseItem{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        r.ID,
+			CreatedAt: r.CreatedAt.Unix(),
+		},
+		Version:     r.Version,
+		Description: r.Description,
+	}
+	if u, err := r.CreatorInfo(ctx); err == nil {
+		res.CreatedBy = u.Name
+	}
+	return res
+}
+
+func convertModelNotificationChannel(ch *notification.Channel) *projectresponse.NotificationChannel {
+	return &projectresponse.NotificationChannel{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        ch.ID,
+			CreatedAt: ch.CreatedAt.Unix(),
+		},
+		Name:      ch.Name,
+		Type:      ch.Type,
+		URL:       ch.URL,
+		HasSecret: ch.Secret != "",
+		Events:    ch.GetEvents(),
+		Active:    ch.Active,
+	}
+}
+
+func convertModelWebhookDelivery(d *webhook.Delivery) *projectresponse.WebhookDelivery {
+	return &projectresponse.WebhookDelivery{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        d.ID,
+			CreatedAt: d.CreatedAt.Unix(),
+		},
+		Event:          d.Event,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt.Unix(),
+		ResponseStatus: d.ResponseStatus,
+		Duration:       d.Duration,
+	}
+}
+
 func convertModelProjectMember(pm *project.ProjectMember, memberInfo *team.TeamMember, userInfo *user.User) *projectresponse.ProjectMember {
 	return &projectresponse.ProjectMember{
 		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
@@ -63,6 +127,9 @@ func convertModelProjectMember(pm *project.ProjectMember, memberInfo *team.TeamM
 		ProjectMemberPermission: protobase.ProjectMemberPermission{
 			Permission: pm.Permission,
 		},
+		ProjectMemberRoleOption: protobase.ProjectMemberRoleOption{
+			RoleID: pm.RoleID,
+		},
 		User: protouserresponse.UserData{
 			EmailOption: protouserbase.EmailOption{
 				Email: userInfo.Email,
@@ -157,6 +224,44 @@ func convertModelDefinitionSchemaHistory(h *definition.DefinitionSchemaHistory,
 	}
 }
 
+func convertModelDefinitionResponseHistory(h *definition.DefinitionResponseHistory, userInfo *user.User) *projectresponse.DefinitionResponseHistory {
+	return &projectresponse.DefinitionResponseHistory{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        h.ID,
+			CreatedAt: h.CreatedAt.Unix(),
+		},
+		DefinitionResponseHistoryData: projectresponse.DefinitionResponseHistoryData{
+			DefinitionResponseDataOption: projectbase.DefinitionResponseDataOption{
+				Name:        h.Name,
+				Description: h.Description,
+				Header:      h.Header,
+				Content:     h.Content,
+			},
+			ResponseID: h.ResponseID,
+		},
+		CreatedBy: userInfo.Name,
+	}
+}
+
+func convertModelGlobalParameterHistory(gp *global.GlobalParameter, h *global.GlobalParameterHistory, userInfo *user.User) *projectresponse.GlobalParameterHistory {
+	return &projectresponse.GlobalParameterHistory{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        h.ID,
+			CreatedAt: h.CreatedAt.Unix(),
+		},
+		GlobalParameterHistoryData: projectresponse.GlobalParameterHistoryData{
+			GlobalParameterDataOption: projectbase.GlobalParameterDataOption{
+				In:       gp.In,
+				Name:     h.Name,
+				Required: h.Required,
+				Schema:   h.Schema,
+			},
+			ParameterID: h.ParameterID,
+		},
+		CreatedBy: userInfo.Name,
+	}
+}
+
 func buildDefinitionSchemaTree(parentID uint, schemas []*definition.DefinitionSchema) projectresponse.DefinitionSchemaTree {
 	result := make(projectresponse.DefinitionSchemaTree, 0)
 
@@ -309,3 +414,34 @@ func dsDerefWithSpec(ctx *gin.Context, ds *definition.DefinitionSchema) (*spec.D
 
 	return schemaSpec, nil
 }
+
+func drDerefWithSpec(ctx *gin.Context, dr *definition.DefinitionResponse) (*spec.DefinitionResponse, error) {
+	responseSpec, err := dr.ToSpec()
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := definition.GetDefinitionSchemasWithSpec(ctx, dr.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := responseSpec.DeepDeref(schemas); err != nil {
+		return nil, err
+	}
+
+	return responseSpec, nil
+}
+
+func convertModelHistoryRetention(hr *project.HistoryRetention) *projectresponse.HistoryRetention {
+	res := &projectresponse.HistoryRetention{
+		KeepCount:      hr.KeepCount,
+		KeepDays:       hr.KeepDays,
+		DailyAfterDays: hr.DailyAfterDays,
+		DeltaStorage:   hr.DeltaStorage,
+	}
+	if hr.CompactedAt != nil {
+		res.CompactedAt = hr.CompactedAt.Unix()
+	}
+	return res
+}
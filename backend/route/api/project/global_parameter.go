@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/global"
 	"github.com/apicat/apicat/v2/backend/model/project"
 	"github.com/apicat/apicat/v2/backend/route/middleware/access"
@@ -51,6 +52,7 @@ func (gpai *globalParameterApiImpl) Create(ctx *gin.Context, data *projectreques
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameter.CreationFailed"))
 	}
 
+	recordAudit(ctx, audit.ActionCreate, audit.TargetGlobalParameter, gp.ID, gp.Name, nil, globalParameterAuditFields(&gp))
 	return convertModelGlobalparameter(&gp), nil
 }
 
@@ -105,6 +107,7 @@ func (gpai *globalParameterApiImpl) Update(ctx *gin.Context, opt *projectrequest
 		)
 	}
 
+	before := globalParameterAuditFields(&gp)
 	gp.In = opt.In
 	gp.Name = opt.Name
 	gp.Required = opt.Required
@@ -121,10 +124,11 @@ func (gpai *globalParameterApiImpl) Update(ctx *gin.Context, opt *projectrequest
 		)
 	}
 
-	if err := gp.Update(ctx); err != nil {
+	if err := gp.Update(ctx, access.GetSelfTeamMember(ctx).ID); err != nil {
 		slog.ErrorContext(ctx, "gp.Update", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
 	}
+	recordAudit(ctx, audit.ActionUpdate, audit.TargetGlobalParameter, gp.ID, gp.Name, before, globalParameterAuditFields(&gp))
 
 	return &ginrpc.Empty{}, nil
 }
@@ -152,6 +156,7 @@ func (gpai *globalParameterApiImpl) Delete(ctx *gin.Context, opt *projectrequest
 		slog.ErrorContext(ctx, "gp.Delete", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameter.FailedToDelete"))
 	}
+	recordAudit(ctx, audit.ActionDelete, audit.TargetGlobalParameter, gp.ID, gp.Name, globalParameterAuditFields(gp), nil)
 
 	if err := except.DerefExceptParam(ctx, gp, opt.Deref); err != nil {
 		slog.ErrorContext(ctx, "except.DerefExceptParam", "err", err)
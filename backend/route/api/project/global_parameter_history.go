@@ -0,0 +1,276 @@
+package project
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/global"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	"github.com/apicat/apicat/v2/backend/service/compatibility"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type globalParameterHistoryApiImpl struct{}
+
+func NewGlobalParameterHistoryApi() protoproject.GlobalParameterHistoryApi {
+	return &globalParameterHistoryApiImpl{}
+}
+
+// getProjectGlobalParameter 获取当前项目中的全局参数，全局参数只能按ID查询，需要检查所属项目
+func getProjectGlobalParameter(ctx *gin.Context, parameterID uint) (*global.GlobalParameter, bool, error) {
+	gp := &global.GlobalParameter{ID: parameterID}
+	exist, err := gp.Get(ctx)
+	if err != nil || !exist {
+		return nil, exist, err
+	}
+	if gp.ProjectID != access.GetSelfProject(ctx).ID {
+		return nil, false, nil
+	}
+	return gp, true, nil
+}
+
+func (impl *globalParameterHistoryApiImpl) List(ctx *gin.Context, opt *projectrequest.GetGlobalParameterHistoryListOption) (*projectresponse.GlobalParameterHistoryList, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	gp, exist, err := getProjectGlobalParameter(ctx, opt.ParameterID)
+	if err != nil {
+		slog.ErrorContext(ctx, "gp.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.FailedToGetList"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("globalParameter.DoesNotExist"))
+	}
+
+	startTime := time.Unix(opt.StartTime, 0)
+	endTime := time.Unix(opt.EndTime, 0)
+	list, err := global.GetGlobalParameterHistories(ctx, gp, startTime, endTime)
+	if err != nil {
+		slog.ErrorContext(ctx, "global.GetGlobalParameterHistories", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.FailedToGetList"))
+	}
+
+	res := make(projectresponse.GlobalParameterHistoryList, 0, len(list))
+	for _, v := range list {
+		if userInfo, err := definition.UserInfo(ctx, v.CreatedBy, true); err == nil {
+			res = append(res, &projectresponse.GlobalParameterHistoryItem{
+				IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+					ID:        v.ID,
+					CreatedAt: v.CreatedAt.Unix(),
+				},
+				CreatedBy: userInfo.Name,
+			})
+		}
+	}
+
+	return &res, nil
+}
+
+func (impl *globalParameterHistoryApiImpl) Get(ctx *gin.Context, opt *projectrequest.GlobalParameterHistoryIDOption) (*projectresponse.GlobalParameterHistory, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	gp, exist, err := getProjectGlobalParameter(ctx, opt.ParameterID)
+	if err != nil {
+		slog.ErrorContext(ctx, "gp.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("globalParameter.DoesNotExist"))
+	}
+
+	h := &global.GlobalParameterHistory{
+		ID:          opt.HistoryID,
+		ParameterID: gp.ID,
+	}
+	exist, err = h.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "h.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("globalParameterHistory.DoesNotExist"))
+	}
+
+	userInfo, err := definition.UserInfo(ctx, h.CreatedBy, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "h.CreatedMember.UserInfo", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.FailedToGet"))
+	}
+
+	return convertModelGlobalParameterHistory(gp, h, userInfo), nil
+}
+
+func (impl *globalParameterHistoryApiImpl) Restore(ctx *gin.Context, opt *projectrequest.GlobalParameterHistoryIDOption) (*ginrpc.Empty, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	gp, exist, err := getProjectGlobalParameter(ctx, opt.ParameterID)
+	if err != nil {
+		slog.ErrorContext(ctx, "gp.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.RestoreFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("globalParameter.DoesNotExist"))
+	}
+
+	h := &global.GlobalParameterHistory{
+		ID:          opt.HistoryID,
+		ParameterID: gp.ID,
+	}
+	exist, err = h.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "h.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.RestoreFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("globalParameterHistory.DoesNotExist"))
+	}
+
+	// 恢复的名称不能与同一位置的其他参数重复
+	restored := *gp
+	restored.Name = h.Name
+	exist, err = restored.CheckRepeat(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "gp.CheckRepeat", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.RestoreFailed"))
+	}
+	if exist {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("globalParameter.HasBeenUsed"))
+	}
+
+	before := globalParameterAuditFields(gp)
+	if err := h.Restore(ctx, gp, selfTM.ID); err != nil {
+		slog.ErrorContext(ctx, "h.Restore", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.RestoreFailed"))
+	}
+	after := globalParameterAuditFields(gp)
+	after["historyID"] = h.ID
+	recordAudit(ctx, audit.ActionRestore, audit.TargetGlobalParameter, gp.ID, gp.Name, before, after)
+
+	return &ginrpc.Empty{}, nil
+}
+
+func (impl *globalParameterHistoryApiImpl) Diff(ctx *gin.Context, opt *projectrequest.DiffGlobalParameterHistoriesOption) (*projectresponse.DiffGlobalParameterHistories, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	gp, exist, err := getProjectGlobalParameter(ctx, opt.ParameterID)
+	if err != nil {
+		slog.ErrorContext(ctx, "gp.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.DiffFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("globalParameter.DoesNotExist"))
+	}
+
+	originalGPH := &global.GlobalParameterHistory{
+		ID:          opt.OriginalID,
+		ParameterID: gp.ID,
+	}
+	exist, err = originalGPH.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "originalGPH.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.DiffFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("globalParameterHistory.DoesNotExist"))
+	}
+	originalGPHUserInfo, err := definition.UserInfo(ctx, originalGPH.CreatedBy, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "originalGPH.CreatedMember.UserInfo", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.DiffFailed"))
+	}
+
+	var targetGPH *global.GlobalParameterHistory
+	if opt.TargetID == 0 {
+		targetGPH = &global.GlobalParameterHistory{
+			ParameterID: gp.ID,
+			Name:        gp.Name,
+			Required:    gp.Required,
+			Schema:      gp.Schema,
+			TimeModel: model.TimeModel{
+				CreatedAt: gp.UpdatedAt,
+				UpdatedAt: gp.UpdatedAt,
+			},
+		}
+		// 全局参数不记录修改人，当前版本的修改人取最近一条历史记录的创建人
+		if latest, err := global.GetGlobalParameterHistories(ctx, gp, time.Time{}, time.Time{}); err == nil && len(latest) > 0 {
+			targetGPH.CreatedBy = latest[0].CreatedBy
+		}
+	} else {
+		targetGPH = &global.GlobalParameterHistory{
+			ID:          opt.TargetID,
+			ParameterID: gp.ID,
+		}
+		exist, err = targetGPH.Get(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "targetGPH.Get", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.DiffFailed"))
+		}
+		if !exist {
+			return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("globalParameterHistory.DoesNotExist"))
+		}
+	}
+	targetGPHUserInfo, err := definition.UserInfo(ctx, targetGPH.CreatedBy, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "targetGPH.CreatedMember.UserInfo", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.DiffFailed"))
+	}
+
+	original := &global.GlobalParameter{ID: gp.ID, In: gp.In, Name: originalGPH.Name, Required: originalGPH.Required, Schema: originalGPH.Schema}
+	originalParam, err := original.ToSpec()
+	if err != nil {
+		slog.ErrorContext(ctx, "original.ToSpec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.DiffFailed"))
+	}
+	target := &global.GlobalParameter{ID: gp.ID, In: gp.In, Name: targetGPH.Name, Required: targetGPH.Required, Schema: targetGPH.Schema}
+	targetParam, err := target.ToSpec()
+	if err != nil {
+		slog.ErrorContext(ctx, "target.ToSpec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.DiffFailed"))
+	}
+
+	// diff.DiffParameter会修改targetParam，需要先做兼容性检查
+	breakingChanges := compatibility.Parameter(ctx, gp.In, originalParam, targetParam)
+	if err := diff.DiffParameter(originalParam, targetParam); err != nil {
+		slog.ErrorContext(ctx, "diff.DiffParameter", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.DiffFailed"))
+	}
+
+	targetSchemaStr, err := json.Marshal(targetParam.Schema)
+	if err != nil {
+		slog.ErrorContext(ctx, "targetParam.json.Marshal", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("globalParameterHistory.DiffFailed"))
+	}
+	targetGPH.Schema = string(targetSchemaStr)
+
+	return &projectresponse.DiffGlobalParameterHistories{
+		Parameter1:      convertModelGlobalParameterHistory(gp, originalGPH, originalGPHUserInfo),
+		Parameter2:      convertModelGlobalParameterHistory(gp, targetGPH, targetGPHUserInfo),
+		BreakingChanges: breakingChanges,
+	}, nil
+}
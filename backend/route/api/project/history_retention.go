@@ -0,0 +1,63 @@
+package project
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	historyservice "github.com/apicat/apicat/v2/backend/service/history"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type projectHistoryRetentionApiImpl struct{}
+
+func NewProjectHistoryRetentionApi() protoproject.ProjectHistoryRetentionApi {
+	return &projectHistoryRetentionApiImpl{}
+}
+
+func (impl *projectHistoryRetentionApiImpl) Get(ctx *gin.Context, opt *protobase.ProjectIdOption) (*projectresponse.HistoryRetention, error) {
+	selfP := access.GetSelfProject(ctx)
+
+	// 未设置时返回全部为0，即历史记录永久保留
+	hr := &project.HistoryRetention{ProjectID: selfP.ID}
+	if _, err := hr.Get(ctx); err != nil {
+		slog.ErrorContext(ctx, "hr.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("historyRetention.FailedToGet"))
+	}
+	return convertModelHistoryRetention(hr), nil
+}
+
+func (impl *projectHistoryRetentionApiImpl) Update(ctx *gin.Context, opt *projectrequest.UpdateHistoryRetentionOption) (*projectresponse.HistoryRetention, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberManage) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+	if opt.KeepDays > 0 && opt.DailyAfterDays >= opt.KeepDays {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("historyRetention.InvalidDays"))
+	}
+
+	hr := &project.HistoryRetention{ProjectID: selfPM.ProjectID}
+	if _, err := hr.Get(ctx); err != nil {
+		slog.ErrorContext(ctx, "hr.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("historyRetention.FailedToUpdate"))
+	}
+	hr.KeepCount = opt.KeepCount
+	hr.KeepDays = opt.KeepDays
+	hr.DailyAfterDays = opt.DailyAfterDays
+	hr.DeltaStorage = opt.DeltaStorage
+	if err := hr.Save(ctx, selfPM.MemberID); err != nil {
+		slog.ErrorContext(ctx, "hr.Save", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("historyRetention.FailedToUpdate"))
+	}
+
+	historyservice.Wake()
+	return convertModelHistoryRetention(hr), nil
+}
@@ -0,0 +1,205 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/job"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	jobservice "github.com/apicat/apicat/v2/backend/service/job"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+// jobListLimit 任务列表只返回最近的任务
+const jobListLimit = 50
+
+type projectJobApiImpl struct{}
+
+func NewProjectJobApi() protoproject.ProjectJobApi {
+	return &projectJobApiImpl{}
+}
+
+func getJob(ctx *gin.Context, jobID uint) (*job.Job, error) {
+	j := &job.Job{ID: jobID, ProjectID: access.GetSelfProject(ctx).ID}
+	exist, err := j.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "j.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("job.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("job.DoesNotExist"))
+	}
+	return j, nil
+}
+
+// List 获取项目最近的后台任务
+func (pjai *projectJobApiImpl) List(ctx *gin.Context, opt *projectrequest.GetProjectJobListOption) (*projectresponse.ProjectJobList, error) {
+	list, err := job.GetJobs(ctx, access.GetSelfProject(ctx).ID, opt.Type, opt.Status, jobListLimit)
+	if err != nil {
+		slog.ErrorContext(ctx, "job.GetJobs", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("job.FailedToGetList"))
+	}
+
+	res := make(projectresponse.ProjectJobList, 0, len(list))
+	for _, j := range list {
+		res = append(res, convertModelJob(j))
+	}
+	return &res, nil
+}
+
+// Get 获取后台任务状态
+func (pjai *projectJobApiImpl) Get(ctx *gin.Context, opt *projectrequest.GetProjectJobOption) (*projectresponse.ProjectJobDetail, error) {
+	j, err := getJob(ctx, opt.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &projectresponse.ProjectJobDetail{ProjectJob: *convertModelJob(j)}
+	if j.Type != job.TypeProjectExport {
+		res.Result = j.Result
+	}
+	return res, nil
+}
+
+// Cancel 取消后台任务，只有创建人和项目管理员可以取消
+func (pjai *projectJobApiImpl) Cancel(ctx *gin.Context, opt *projectrequest.GetProjectJobOption) (*ginrpc.Empty, error) {
+	j, err := getJob(ctx, opt.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	selfPM := access.GetSelfProjectMember(ctx)
+	if j.MemberID != selfPM.MemberID && selfPM.Permission.Lower(project.ProjectMemberManage) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+	if j.Finished() {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("job.AlreadyFinished"))
+	}
+
+	ok, err := jobservice.Cancel(ctx, j)
+	if err != nil {
+		slog.ErrorContext(ctx, "jobservice.Cancel", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("job.CancelFailed"))
+	}
+	if !ok {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("job.AlreadyFinished"))
+	}
+	return &ginrpc.Empty{}, nil
+}
+
+// Export 创建项目导出任务
+func (pjai *projectJobApiImpl) Export(ctx *gin.Context, opt *projectrequest.CreateProjectExportJobOption) (*projectresponse.ProjectJob, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Equal(project.ProjectMemberRead) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	j := &job.Job{
+		Type:      job.TypeProjectExport,
+		ProjectID: selfPM.ProjectID,
+		MemberID:  selfPM.MemberID,
+	}
+	if err := jobservice.Enqueue(ctx, j, jobservice.ExportPayload{Type: opt.Type}); err != nil {
+		slog.ErrorContext(ctx, "jobservice.Enqueue", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("project.ExportFailed"))
+	}
+	return convertModelJob(j), nil
+}
+
+// Translate 创建项目翻译任务，已翻译且内容未变化的部分会跳过
+func (pjai *projectJobApiImpl) Translate(ctx *gin.Context, opt *projectrequest.CreateProjectTranslateJobOption) (*projectresponse.ProjectJob, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	j := &job.Job{
+		Type:      job.TypeProjectTranslate,
+		ProjectID: selfPM.ProjectID,
+		MemberID:  selfPM.MemberID,
+	}
+	if err := jobservice.Enqueue(ctx, j, jobservice.TranslatePayload{Locale: opt.Lang}); err != nil {
+		slog.ErrorContext(ctx, "jobservice.Enqueue", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("job.CreationFailed"))
+	}
+	return convertModelJob(j), nil
+}
+
+// JobResult 下载导出任务的结果，需返回不同的 Content-Type，单独处理
+func JobResult(ctx *gin.Context) {
+	jobID, err := strconv.ParseUint(ctx.Param("jobID"), 10, 64)
+	if err != nil || jobID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": i18n.NewTran("common.RequestParameterIncorrect").Translate(ctx),
+		})
+		return
+	}
+
+	j := &job.Job{ID: uint(jobID), ProjectID: access.GetSelfProject(ctx).ID}
+	exist, err := j.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "j.Get", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("job.FailedToGet").Translate(ctx),
+		})
+		return
+	}
+	if !exist || j.Type != job.TypeProjectExport {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"message": i18n.NewTran("job.DoesNotExist").Translate(ctx),
+		})
+		return
+	}
+	if j.Status != job.StatusSucceeded {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": i18n.NewTran("job.NotSucceeded").Translate(ctx),
+		})
+		return
+	}
+
+	var payload jobservice.ExportPayload
+	if err := json.Unmarshal([]byte(j.Payload), &payload); err != nil {
+		slog.ErrorContext(ctx, "json.Unmarshal", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("project.ExportFailed").Translate(ctx),
+		})
+		return
+	}
+
+	p := access.GetSelfProject(ctx)
+	writeExport(ctx, fmt.Sprintf("%s-%s", p.Title, payload.Type), payload.Type, true, []byte(j.Result))
+}
+
+func convertModelJob(j *job.Job) *projectresponse.ProjectJob {
+	res := &projectresponse.ProjectJob{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        j.ID,
+			CreatedAt: j.CreatedAt.Unix(),
+		},
+		Type:        j.Type,
+		TargetID:    j.TargetID,
+		Status:      j.Status,
+		Progress:    j.Progress,
+		Attempts:    j.Attempts,
+		MaxAttempts: j.MaxAttempts,
+		Error:       j.Error,
+	}
+	if j.StartedAt != nil {
+		res.StartedAt = j.StartedAt.Unix()
+	}
+	if j.FinishedAt != nil {
+		res.FinishedAt = j.FinishedAt.Unix()
+	}
+	return res
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/project"
 	"github.com/apicat/apicat/v2/backend/model/team"
 	"github.com/apicat/apicat/v2/backend/route/middleware/access"
@@ -46,6 +47,10 @@ func (pgai *projectMemberApiImpl) Create(ctx *gin.Context, opt *projectrequest.C
 		)
 	}
 
+	if err := checkMemberRole(ctx, t.ID, opt.Permission, opt.RoleID); err != nil {
+		return nil, err
+	}
+
 	tms, err := team.GetMembers(ctx, t.ID, 0, 0, "")
 	if err != nil {
 		slog.ErrorContext(ctx, "team.GetMembers", "err", err)
@@ -60,7 +65,15 @@ func (pgai *projectMemberApiImpl) Create(ctx *gin.Context, opt *projectrequest.C
 		}
 	}
 
-	project.BatchCreateMember(ctx, p.ID, teamMembers, opt.Permission)
+	project.BatchCreateMember(ctx, p.ID, teamMembers, opt.Permission, opt.RoleID)
+	for _, tm := range teamMembers {
+		name := ""
+		if u, err := tm.UserInfo(ctx, true); err == nil {
+			name = u.Name
+		}
+		after := projectMemberAuditFields(&project.ProjectMember{MemberID: tm.ID, Permission: opt.Permission, RoleID: opt.RoleID})
+		recordAudit(ctx, audit.ActionCreate, audit.TargetProjectMember, tm.ID, name, nil, after)
+	}
 	return &ginrpc.Empty{}, nil
 }
 
@@ -173,11 +186,18 @@ func (pgai *projectMemberApiImpl) Update(ctx *gin.Context, opt *projectrequest.U
 		)
 	}
 
+	if err := checkMemberRole(ctx, access.GetSelfTeam(ctx).ID, opt.Permission, opt.RoleID); err != nil {
+		return nil, err
+	}
+
+	before := projectMemberAuditFields(&targetPm)
 	targetPm.Permission = opt.Permission
+	targetPm.RoleID = opt.RoleID
 	if err := targetPm.Update(ctx); err != nil {
 		slog.ErrorContext(ctx, "targetPm.Get", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
 	}
+	recordAudit(ctx, audit.ActionUpdate, audit.TargetProjectMember, targetPm.MemberID, projectMemberName(ctx, &targetPm), before, projectMemberAuditFields(&targetPm))
 
 	return &ginrpc.Empty{}, nil
 }
@@ -223,6 +243,29 @@ func (pgai *projectMemberApiImpl) Delete(ctx *gin.Context, opt *projectrequest.P
 		slog.ErrorContext(ctx, "targetPm.Delete", "err", err)
 		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("projectMember.RemoveFailed"))
 	}
+	recordAudit(ctx, audit.ActionDelete, audit.TargetProjectMember, targetPm.MemberID, projectMemberName(ctx, &targetPm), projectMemberAuditFields(&targetPm), nil)
 
 	return &ginrpc.Empty{}, nil
 }
+
+// checkMemberRole 检查分配给项目成员的自定义角色
+func checkMemberRole(ctx *gin.Context, teamID string, permission project.Permission, roleID uint) error {
+	if roleID == 0 {
+		return nil
+	}
+	// 自定义角色用于限制可写成员的操作范围
+	if permission != project.ProjectMemberWrite {
+		return ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("customRole.OnlyForWriteMember"))
+	}
+
+	r := &project.CustomRole{ID: roleID, TeamID: teamID}
+	exist, err := r.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "r.Get", "err", err)
+		return ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("customRole.FailedToGet"))
+	}
+	if !exist {
+		return ginrpc.NewError(http.StatusNotFound, i18n.NewErr("customRole.DoesNotExist"))
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package project
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/notification"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	notifyservice "github.com/apicat/apicat/v2/backend/service/notification"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type projectNotificationChannelApiImpl struct{}
+
+func NewProjectNotificationChannelApi() protoproject.ProjectNotificationChannelApi {
+	return &projectNotificationChannelApiImpl{}
+}
+
+func getNotificationChannel(ctx *gin.Context, channelID uint) (*notification.Channel, error) {
+	ch := &notification.Channel{ID: channelID, ProjectID: access.GetSelfProject(ctx).ID}
+	exist, err := ch.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "ch.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("notificationChannel.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("notificationChannel.DoesNotExist"))
+	}
+	return ch, nil
+}
+
+// Create 创建项目聊天通知渠道
+func (pncai *projectNotificationChannelApiImpl) Create(ctx *gin.Context, opt *projectrequest.CreateNotificationChannelOption) (*projectresponse.NotificationChannel, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookEvents(opt.Events); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookURL(opt.URL); err != nil {
+		return nil, err
+	}
+
+	ch := &notification.Channel{
+		ProjectID: access.GetSelfProject(ctx).ID,
+		Name:      opt.Name,
+		Type:      opt.Type,
+		URL:       opt.URL,
+		Secret:    opt.Secret,
+		Active:    opt.Active,
+		CreatedBy: access.GetSelfTeamMember(ctx).ID,
+	}
+	ch.SetEvents(opt.Events)
+	if err := ch.Create(ctx); err != nil {
+		slog.ErrorContext(ctx, "ch.Create", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("notificationChannel.CreationFailed"))
+	}
+	return convertModelNotificationChannel(ch), nil
+}
+
+// List 获取项目聊天通知渠道列表
+func (pncai *projectNotificationChannelApiImpl) List(ctx *gin.Context, opt *protobase.ProjectIdOption) (*projectresponse.NotificationChannelList, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	list, err := notification.GetChannels(ctx, access.GetSelfProject(ctx).ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "notification.GetChannels", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("notificationChannel.FailedToGetList"))
+	}
+
+	ret := make(projectresponse.NotificationChannelList, len(list))
+	for i, v := range list {
+		ret[i] = convertModelNotificationChannel(v)
+	}
+	return &ret, nil
+}
+
+// Update 修改项目聊天通知渠道，secret为空时保留原secret
+func (pncai *projectNotificationChannelApiImpl) Update(ctx *gin.Context, opt *projectrequest.UpdateNotificationChannelOption) (*ginrpc.Empty, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookEvents(opt.Events); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookURL(opt.URL); err != nil {
+		return nil, err
+	}
+
+	ch, err := getNotificationChannel(ctx, opt.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	ch.Name = opt.Name
+	ch.Type = opt.Type
+	ch.URL = opt.URL
+	if opt.Secret != "" {
+		ch.Secret = opt.Secret
+	}
+	ch.Active = opt.Active
+	ch.SetEvents(opt.Events)
+	if err := ch.Update(ctx); err != nil {
+		slog.ErrorContext(ctx, "ch.Update", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
+	}
+	return &ginrpc.Empty{}, nil
+}
+
+// Delete 删除项目聊天通知渠道
+func (pncai *projectNotificationChannelApiImpl) Delete(ctx *gin.Context, opt *projectrequest.GetNotificationChannelOption) (*ginrpc.Empty, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	ch, err := getNotificationChannel(ctx, opt.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ch.Delete(ctx); err != nil {
+		slog.ErrorContext(ctx, "ch.Delete", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("notificationChannel.FailedToDelete"))
+	}
+	return &ginrpc.Empty{}, nil
+}
+
+// Test 同步发送一条测试消息，便于检查渠道配置
+func (pncai *projectNotificationChannelApiImpl) Test(ctx *gin.Context, opt *projectrequest.GetNotificationChannelOption) (*ginrpc.Empty, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	ch, err := getNotificationChannel(ctx, opt.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := access.GetSelfProject(ctx)
+	msg := &notifyservice.Message{
+		Event:        "ping",
+		ProjectID:    p.ID,
+		ProjectTitle: p.Title,
+		Action:       "tested",
+		TargetType:   "notification channel",
+		TargetID:     ch.Name,
+		TargetName:   ch.Name,
+		Link:         notifyservice.ProjectLink(p.ID),
+		Time:         time.Now(),
+	}
+	if u := jwt.GetUser(ctx); u != nil {
+		msg.Operator = u.Name
+	}
+	if err := notifyservice.Send(ctx, ch, msg); err != nil {
+		slog.ErrorContext(ctx, "notifyservice.Send", "err", err, "channel", ch.ID)
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("notificationChannel.FailedToSend", err.Error()))
+	}
+	return &ginrpc.Empty{}, nil
+}
@@ -26,8 +26,6 @@ import (
 	"time"
 
 	"github.com/apicat/apicat/v2/backend/module/spec"
-	"github.com/apicat/apicat/v2/backend/module/spec/plugin/export"
-	"github.com/apicat/apicat/v2/backend/module/spec/plugin/openapi"
 
 	"github.com/apicat/ginrpc"
 	"github.com/gin-gonic/gin"
@@ -168,6 +166,11 @@ func (pai *projectApiImpl) Get(ctx *gin.Context, opt *projectrequest.GetProjectD
 		pm = &project.ProjectMember{ProjectID: p.ID, Permission: project.ProjectMemberNone}
 	}
 
+	capabilities, err := pm.GetCapabilities(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "pm.GetCapabilities", "err", err)
+	}
+
 	cfg := config.Get().App
 	return &projectresponse.ProjectDetail{
 		ProjectListItem: projectresponse.ProjectListItem{
@@ -188,6 +191,10 @@ func (pai *projectApiImpl) Get(ctx *gin.Context, opt *projectrequest.GetProjectD
 				ProjectMemberPermission: protobase.ProjectMemberPermission{
 					Permission: pm.Permission,
 				},
+				ProjectMemberRoleOption: protobase.ProjectMemberRoleOption{
+					RoleID: pm.RoleID,
+				},
+				Capabilities: capabilities,
 			},
 		},
 		MockURL: fmt.Sprintf("%s/mock/%s", strings.TrimSuffix(cfg.MockUrl, "/"), p.ID),
@@ -379,7 +386,7 @@ func (pai *projectApiImpl) Delete(ctx *gin.Context, opt *protobase.ProjectIdOpti
 	}
 
 	p := access.GetSelfProject(ctx)
-	if err := p.Delete(ctx); err != nil {
+	if err := p.Delete(ctx, pm.MemberID); err != nil {
 		slog.ErrorContext(ctx, "p.Delete", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("project.FailedToDelete"))
 	}
@@ -387,6 +394,67 @@ func (pai *projectApiImpl) Delete(ctx *gin.Context, opt *protobase.ProjectIdOpti
 	return &ginrpc.Empty{}, nil
 }
 
+// Trashes 回收站中的项目，团队管理员可查看全部，其他成员只能查看自己删除或管理的项目
+func (pai *projectApiImpl) Trashes(ctx *gin.Context, opt *protobase.TeamIdOption) (*projectresponse.ProjectTrashList, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+
+	var memberID uint
+	if selfTM.Role.Lower(team.RoleAdmin) {
+		memberID = selfTM.ID
+	}
+
+	projects, err := project.GetDeletedProjects(ctx, selfTM.TeamID, config.Get().App.TrashDeadline(time.Now()), memberID)
+	if err != nil {
+		slog.ErrorContext(ctx, "project.GetDeletedProjects", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("project.FailedToGetList"))
+	}
+
+	list := make(projectresponse.ProjectTrashList, 0, len(projects))
+	for _, p := range projects {
+		item := &projectresponse.ProjectTrash{
+			ID:    p.ID,
+			Title: p.Title,
+			TrashDeleteInfo: projectresponse.TrashDeleteInfo{
+				DeletedAt: p.DeletedAt.Time,
+			},
+		}
+		if p.DeletedBy != 0 {
+			if userInfo, err := collection.UserInfo(ctx, p.DeletedBy, true); err == nil {
+				item.DeletedBy = userInfo.Name
+			}
+		}
+		list = append(list, item)
+	}
+	return &list, nil
+}
+
+// Restore 从回收站恢复项目
+func (pai *projectApiImpl) Restore(ctx *gin.Context, opt *projectrequest.RestoreProjectsOption) (*projectresponse.RestoreNum, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+
+	projects, err := project.GetDeletedProjectsByIDs(ctx, selfTM.TeamID, opt.ProjectIDs)
+	if err != nil {
+		slog.ErrorContext(ctx, "project.GetDeletedProjectsByIDs", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("project.RestoreFailed"))
+	}
+
+	num := 0
+	for _, p := range projects {
+		if selfTM.Role.Lower(team.RoleAdmin) && p.DeletedBy != selfTM.ID && p.MemberID != selfTM.ID {
+			return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+		}
+	}
+	for _, p := range projects {
+		if err := p.Restore(ctx); err != nil {
+			slog.ErrorContext(ctx, "p.Restore", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("project.RestoreFailed"))
+		}
+		num++
+	}
+
+	return &projectresponse.RestoreNum{Num: num}, nil
+}
+
 // Transfer 移交项目
 func (pai *projectApiImpl) Transfer(ctx *gin.Context, opt *projectrequest.ProjectMemberIDOption) (*ginrpc.Empty, error) {
 	selfP := access.GetSelfProject(ctx)
@@ -530,37 +598,12 @@ func Export(ctx *gin.Context) {
 		return
 	}
 
-	apicatData := spec.NewEmptySpec()
-	relations.SpecFillInfo(ctx, apicatData, p)
-	relations.SpecFillServers(ctx, apicatData, p.ID)
-	relations.SpecFillGlobals(ctx, apicatData, p.ID)
-	relations.SpecFillDefinitions(ctx, apicatData, p.ID)
-	relations.SpecFillCollections(ctx, apicatData, p.ID)
+	apicatData := relations.ProjectExportSpec(ctx, p)
 	if _, err := json.Marshal(apicatData); err != nil {
 		slog.ErrorContext(ctx, "export", "marshalErr", err)
 	}
 
-	var content []byte
-	switch t.Type {
-	case "swagger":
-		content, err = openapi.Generate(apicatData, "2.0", "json")
-	case "openapi3.0.0":
-		content, err = openapi.Generate(apicatData, "3.0.0", "json")
-	case "openapi3.0.1":
-		content, err = openapi.Generate(apicatData, "3.0.1", "json")
-	case "openapi3.0.2":
-		content, err = openapi.Generate(apicatData, "3.0.2", "json")
-	case "openapi3.1.0":
-		content, err = openapi.Generate(apicatData, "3.1.0", "json")
-	case "HTML":
-		content, err = export.HTML(apicatData)
-	case "md":
-		content, err = export.Markdown(apicatData)
-	case "apicat":
-		content, err = apicatData.ToJSON(spec.JSONOption{Indent: "  "})
-	default:
-		content, err = apicatData.ToJSON(spec.JSONOption{Indent: "  "})
-	}
+	content, err := relations.ExportContent(apicatData, t.Type)
 	if err != nil {
 		slog.ErrorContext(ctx, "export", "err", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
@@ -571,10 +614,16 @@ func Export(ctx *gin.Context) {
 
 	slog.InfoContext(ctx, "export", t.Type, content)
 
-	switch t.Download {
+	writeExport(ctx, fmt.Sprintf("%s-%s", p.Title, t.Type), t.Type, t.Download, content)
+
+	tokenHelper.DelToken(opt.Code)
+}
+
+// writeExport 按导出格式返回内容，download为true时作为附件下载
+func writeExport(ctx *gin.Context, filename, typ string, download bool, content []byte) {
+	switch download {
 	case true:
-		filename := fmt.Sprintf("%s-%s", p.Title, t.Type)
-		switch t.Type {
+		switch typ {
 		case "HTML":
 			ctx.Header("Content-Disposition", "attachment; filename="+filename+".html")
 		case "md":
@@ -584,7 +633,7 @@ func Export(ctx *gin.Context) {
 		}
 		ctx.Data(http.StatusOK, "application/octet-stream", content)
 	default:
-		switch t.Type {
+		switch typ {
 		case "HTML":
 			ctx.Data(http.StatusOK, "text/html; charset=utf-8", content)
 		case "md":
@@ -593,6 +642,4 @@ func Export(ctx *gin.Context) {
 			ctx.Data(http.StatusOK, "application/json", content)
 		}
 	}
-
-	tokenHelper.DelToken(opt.Code)
 }
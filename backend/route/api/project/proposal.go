@@ -0,0 +1,469 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/proposal"
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+	"github.com/apicat/apicat/v2/backend/service/compatibility"
+	notifyservice "github.com/apicat/apicat/v2/backend/service/notification"
+	proposalservice "github.com/apicat/apicat/v2/backend/service/proposal"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type projectProposalApiImpl struct{}
+
+func NewProjectProposalApi() protoproject.ProjectProposalApi {
+	return &projectProposalApiImpl{}
+}
+
+// submitProposal 修改需要审批的模型时提交为修改提案，返回提案ID
+func submitProposal(ctx *gin.Context, t *proposalservice.Target, name, description, schema string) (*protobase.WriteResult, error) {
+	p, err := proposalservice.Submit(ctx, access.GetSelfProject(ctx), t, access.GetSelfTeamMember(ctx), name, description, schema)
+	if err != nil {
+		slog.ErrorContext(ctx, "proposalservice.Submit", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.SubmitFailed"))
+	}
+	if p == nil {
+		return &protobase.WriteResult{Status: protobase.WriteStatusUpdated}, nil
+	}
+	return &protobase.WriteResult{
+		Status:     protobase.WriteStatusProposalSubmitted,
+		ProposalID: p.ID,
+		Message:    i18n.NewTran("proposal.Submitted").Translate(ctx),
+	}, nil
+}
+
+func getProposal(ctx *gin.Context, proposalID uint) (*proposal.Proposal, error) {
+	p := &proposal.Proposal{ID: proposalID, ProjectID: access.GetSelfProject(ctx).ID}
+	exist, err := p.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "p.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("proposal.DoesNotExist"))
+	}
+	return p, nil
+}
+
+// getPendingProposal 获取待审批的提案，用于审批和撤回
+func getPendingProposal(ctx *gin.Context, proposalID uint) (*proposal.Proposal, error) {
+	p, err := getProposal(ctx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != proposal.StatusPending {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("proposal.NotPending"))
+	}
+	return p, nil
+}
+
+// checkReviewer 审核人需要有写权限，且不能审批自己的提案
+func checkReviewer(ctx *gin.Context, p *proposal.Proposal) error {
+	if !proposalservice.IsReviewer(access.GetSelfProjectMember(ctx)) {
+		return ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+	if p.CreatedBy == access.GetSelfTeamMember(ctx).ID {
+		return ginrpc.NewError(http.StatusForbidden, i18n.NewErr("proposal.CannotReviewOwn"))
+	}
+	return nil
+}
+
+func (ppai *projectProposalApiImpl) ListProtections(ctx *gin.Context, opt *protobase.ProjectIdOption) (*projectresponse.ProtectionList, error) {
+	selfP := access.GetSelfProject(ctx)
+	list, err := proposal.GetProtections(ctx, selfP.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "proposal.GetProtections", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.FailedToGetProtections"))
+	}
+
+	res := make(projectresponse.ProtectionList, 0, len(list))
+	for _, p := range list {
+		t, err := proposalservice.GetTarget(ctx, selfP.ID, p.TargetType, p.TargetID)
+		if err != nil {
+			slog.ErrorContext(ctx, "proposalservice.GetTarget", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.FailedToGetProtections"))
+		}
+		// 已删除的接口或模型不再展示
+		if t == nil {
+			continue
+		}
+		res = append(res, convertModelProtection(ctx, p, t))
+	}
+	return &res, nil
+}
+
+func (ppai *projectProposalApiImpl) CreateProtection(ctx *gin.Context, opt *projectrequest.CreateProtectionOption) (*projectresponse.Protection, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberManage) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	t, err := proposalservice.GetTarget(ctx, selfPM.ProjectID, opt.TargetType, opt.TargetID)
+	if err != nil {
+		slog.ErrorContext(ctx, "proposalservice.GetTarget", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.ProtectionCreationFailed"))
+	}
+	if t == nil {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("proposal.TargetDoesNotExist"))
+	}
+	if t.IsCategory() {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("proposal.CategoryNotSupported"))
+	}
+
+	exist, err := proposal.IsProtected(ctx, selfPM.ProjectID, opt.TargetType, opt.TargetID)
+	if err != nil {
+		slog.ErrorContext(ctx, "proposal.IsProtected", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.ProtectionCreationFailed"))
+	}
+	if exist {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("proposal.ProtectionExists"))
+	}
+
+	p := &proposal.Protection{
+		ProjectID:  selfPM.ProjectID,
+		TargetType: opt.TargetType,
+		TargetID:   opt.TargetID,
+	}
+	if err := p.Create(ctx, selfPM.MemberID); err != nil {
+		slog.ErrorContext(ctx, "p.Create", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.ProtectionCreationFailed"))
+	}
+	return convertModelProtection(ctx, p, t), nil
+}
+
+func (ppai *projectProposalApiImpl) DeleteProtection(ctx *gin.Context, opt *projectrequest.ProtectionIDOption) (*ginrpc.Empty, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberManage) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	p := &proposal.Protection{ID: opt.ProtectionID, ProjectID: selfPM.ProjectID}
+	exist, err := p.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "p.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.ProtectionDeleteFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("proposal.ProtectionDoesNotExist"))
+	}
+
+	if err := p.Delete(ctx); err != nil {
+		slog.ErrorContext(ctx, "p.Delete", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.ProtectionDeleteFailed"))
+	}
+	return &ginrpc.Empty{}, nil
+}
+
+func (ppai *projectProposalApiImpl) List(ctx *gin.Context, opt *projectrequest.GetProposalListOption) (*projectresponse.ProposalList, error) {
+	list, err := proposal.GetProposals(ctx, access.GetSelfProject(ctx).ID, opt.Status)
+	if err != nil {
+		slog.ErrorContext(ctx, "proposal.GetProposals", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.FailedToGet"))
+	}
+
+	res := make(projectresponse.ProposalList, len(list))
+	for i, p := range list {
+		res[i] = convertModelProposal(ctx, p)
+	}
+	return &res, nil
+}
+
+// Get 提案详情，包含提交时与提案内容的差异和兼容性检查
+func (ppai *projectProposalApiImpl) Get(ctx *gin.Context, opt *projectrequest.ProposalIDOption) (*projectresponse.ProposalDetail, error) {
+	p, err := getProposal(ctx, opt.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &projectresponse.ProposalDetail{
+		Proposal:        *convertModelProposal(ctx, p),
+		BaseTitle:       p.BaseTitle,
+		BaseDescription: p.BaseDescription,
+		BaseContent:     p.BaseContent,
+		Description:     p.Description,
+		Content:         p.Content,
+	}
+
+	var changes []diff.Change
+	if p.TargetType == proposal.TargetSchema {
+		changes, res.BreakingChanges, err = proposalSchemaDiff(ctx, p)
+	} else {
+		changes, res.BreakingChanges, err = proposalCollectionDiff(ctx, p)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "proposalDiff", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.DiffFailed"))
+	}
+	res.Changes = make([]*projectresponse.ProjectDiffChange, len(changes))
+	for i, c := range changes {
+		res.Changes[i] = &projectresponse.ProjectDiffChange{Op: c.Op, Path: c.Path}
+	}
+
+	comments, err := proposal.GetComments(ctx, p.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "proposal.GetComments", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.FailedToGet"))
+	}
+	res.Comments = make([]*projectresponse.ProposalComment, len(comments))
+	for i, c := range comments {
+		res.Comments[i] = convertModelProposalComment(ctx, c)
+	}
+	return res, nil
+}
+
+// Comment 审核人和提交人可以评论，评论会邮件通知对方
+func (ppai *projectProposalApiImpl) Comment(ctx *gin.Context, opt *projectrequest.CommentProposalOption) (*projectresponse.ProposalComment, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	p, err := getProposal(ctx, opt.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if p.CreatedBy != selfTM.ID && !proposalservice.IsReviewer(access.GetSelfProjectMember(ctx)) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	c := &proposal.ProposalComment{ProposalID: p.ID, Content: opt.Content}
+	if err := c.Create(ctx, selfTM.ID); err != nil {
+		slog.ErrorContext(ctx, "c.Create", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.CommentFailed"))
+	}
+	proposalservice.Notify(ctx, access.GetSelfProject(ctx), p, selfTM, proposalservice.ActionComment, opt.Content)
+	return convertModelProposalComment(ctx, c), nil
+}
+
+// Approve 批准提案并写入接口或模型，提交后目标已被修改时需要重新提交
+func (ppai *projectProposalApiImpl) Approve(ctx *gin.Context, opt *projectrequest.ProposalIDOption) (*ginrpc.Empty, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	p, err := getPendingProposal(ctx, opt.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReviewer(ctx, p); err != nil {
+		return nil, err
+	}
+	// 自定义角色需要包含修改目标的能力
+	capability := project.CapabilityEditCollection
+	if p.TargetType == proposal.TargetSchema {
+		capability = project.CapabilityEditSchema
+	}
+	if ok, err := access.GetSelfProjectMember(ctx).HasCapability(ctx, capability); err != nil {
+		slog.ErrorContext(ctx, "pm.HasCapability", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.ApproveFailed"))
+	} else if !ok {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	t, err := proposalservice.GetTarget(ctx, p.ProjectID, p.TargetType, p.TargetID)
+	if err != nil {
+		slog.ErrorContext(ctx, "proposalservice.GetTarget", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.ApproveFailed"))
+	}
+	if t == nil {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("proposal.TargetDoesNotExist"))
+	}
+	if t.Outdated(p) {
+		return nil, ginrpc.NewError(http.StatusConflict, i18n.NewErr("proposal.Outdated"))
+	}
+
+	e := &auditservice.Entry{Action: audit.ActionUpdate}
+	var detail func() *notifyservice.Detail
+	if t.Schema != nil {
+		e.Before = schemaAuditFields(t.Schema)
+	} else {
+		e.Before = proposalCollectionAuditFields(t)
+	}
+
+	// 写入内容、关闭提案和审计日志在同一事务中，提案已被其他人处理时全部回滚
+	err = model.Transaction(ctx, func(txCtx context.Context) error {
+		if err := t.Apply(txCtx, p, selfTM.ID); err != nil {
+			return err
+		}
+		if err := p.Finish(txCtx, proposal.StatusApproved, selfTM.ID); err != nil {
+			return err
+		}
+
+		if t.Schema != nil {
+			e.TargetType, e.TargetID, e.TargetName = audit.TargetDefinitionSchema, t.Schema.ID, t.Schema.Name
+			e.After = schemaAuditFields(t.Schema)
+			detail = func() *notifyservice.Detail {
+				return schemaNotifyDetail(ctx, e.Action, t.Schema, e.Before)
+			}
+		} else {
+			e.TargetType, e.TargetID, e.TargetName = audit.TargetCollection, t.Collection.ID, t.Collection.Title
+			e.After = proposalCollectionAuditFields(t)
+		}
+		auditservice.Record(withTx(ctx, txCtx), e)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, proposal.ErrNotPending) {
+			return nil, ginrpc.NewError(http.StatusConflict, i18n.NewErr("proposal.NotPending"))
+		}
+		slog.ErrorContext(ctx, "proposal.Approve", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.ApproveFailed"))
+	}
+
+	notifySubscribers(ctx, e, detail)
+	proposalservice.Notify(ctx, access.GetSelfProject(ctx), p, selfTM, proposalservice.ActionApprove, "")
+	return &ginrpc.Empty{}, nil
+}
+
+// Reject 驳回提案，驳回理由作为评论保存
+func (ppai *projectProposalApiImpl) Reject(ctx *gin.Context, opt *projectrequest.RejectProposalOption) (*ginrpc.Empty, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	p, err := getPendingProposal(ctx, opt.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReviewer(ctx, p); err != nil {
+		return nil, err
+	}
+
+	if err := p.Finish(ctx, proposal.StatusRejected, selfTM.ID); err != nil {
+		if errors.Is(err, proposal.ErrNotPending) {
+			return nil, ginrpc.NewError(http.StatusConflict, i18n.NewErr("proposal.NotPending"))
+		}
+		slog.ErrorContext(ctx, "p.Finish", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.RejectFailed"))
+	}
+	if opt.Reason != "" {
+		c := &proposal.ProposalComment{ProposalID: p.ID, Content: opt.Reason}
+		if err := c.Create(ctx, selfTM.ID); err != nil {
+			slog.ErrorContext(ctx, "c.Create", "err", err)
+		}
+	}
+	proposalservice.Notify(ctx, access.GetSelfProject(ctx), p, selfTM, proposalservice.ActionReject, opt.Reason)
+	return &ginrpc.Empty{}, nil
+}
+
+// Withdraw 提交人撤回提案
+func (ppai *projectProposalApiImpl) Withdraw(ctx *gin.Context, opt *projectrequest.ProposalIDOption) (*ginrpc.Empty, error) {
+	selfTM := access.GetSelfTeamMember(ctx)
+	p, err := getPendingProposal(ctx, opt.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if p.CreatedBy != selfTM.ID {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("proposal.OnlyAuthorCanWithdraw"))
+	}
+
+	if err := p.Finish(ctx, proposal.StatusWithdrawn, selfTM.ID); err != nil {
+		if errors.Is(err, proposal.ErrNotPending) {
+			return nil, ginrpc.NewError(http.StatusConflict, i18n.NewErr("proposal.NotPending"))
+		}
+		slog.ErrorContext(ctx, "p.Finish", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("proposal.WithdrawFailed"))
+	}
+	return &ginrpc.Empty{}, nil
+}
+
+func proposalCollectionAuditFields(t *proposalservice.Target) auditservice.Fields {
+	return auditservice.Fields{
+		"title":    t.Collection.Title,
+		"type":     t.Collection.Type,
+		"parentID": t.Collection.ParentID,
+		"content":  t.Collection.Content,
+	}
+}
+
+func proposalCollectionDiff(ctx *gin.Context, p *proposal.Proposal) ([]diff.Change, *protobase.BreakingChangeReport, error) {
+	original, err := spec.NewCollectionNodesFromJson(p.BaseContent)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := spec.NewCollectionNodesFromJson(p.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	breakingChanges := compatibility.Collection(ctx, &spec.Collection{Content: original}, &spec.Collection{Content: target})
+
+	changes, err := notifyservice.CollectionChanges(p.BaseContent, p.Content)
+	return changes, breakingChanges, err
+}
+
+func proposalSchemaDiff(ctx *gin.Context, p *proposal.Proposal) ([]diff.Change, *protobase.BreakingChangeReport, error) {
+	original, err := jsonschema.NewSchemaFromJson(p.BaseContent)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := jsonschema.NewSchemaFromJson(p.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	breakingChanges := compatibility.Model(ctx, &spec.DefinitionModel{Schema: original}, &spec.DefinitionModel{Schema: target})
+
+	changes, err := notifyservice.SchemaChanges(p.BaseContent, p.Content)
+	return changes, breakingChanges, err
+}
+
+func convertModelProtection(ctx *gin.Context, p *proposal.Protection, t *proposalservice.Target) *projectresponse.Protection {
+	res := &projectresponse.Protection{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        p.ID,
+			CreatedAt: p.CreatedAt.Unix(),
+		},
+		TargetType: p.TargetType,
+		TargetID:   p.TargetID,
+		Title:      t.Name(),
+	}
+	if u, err := p.CreatorInfo(ctx); err == nil {
+		res.CreatedBy = u.Name
+	}
+	return res
+}
+
+func convertModelProposal(ctx *gin.Context, p *proposal.Proposal) *projectresponse.Proposal {
+	res := &projectresponse.Proposal{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        p.ID,
+			CreatedAt: p.CreatedAt.Unix(),
+		},
+		TargetType: p.TargetType,
+		TargetID:   p.TargetID,
+		Title:      p.Title,
+		Status:     p.Status,
+	}
+	if u, err := p.CreatorInfo(ctx); err == nil {
+		res.CreatedBy = u.Name
+	}
+	if p.ClosedAt != nil {
+		res.ClosedAt = p.ClosedAt.Unix()
+		if u, err := p.CloserInfo(ctx); err == nil {
+			res.ClosedBy = u.Name
+		}
+	}
+	return res
+}
+
+func convertModelProposalComment(ctx *gin.Context, c *proposal.ProposalComment) *projectresponse.ProposalComment {
+	res := &projectresponse.ProposalComment{
+		IdCreateTimeInfo: protobase.IdCreateTimeInfo{
+			ID:        c.ID,
+			CreatedAt: c.CreatedAt.Unix(),
+		},
+		Content: c.Content,
+	}
+	if u, err := c.CreatorInfo(ctx); err == nil {
+		res.CreatedBy = u.Name
+	}
+	return res
+}
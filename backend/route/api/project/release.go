@@ -0,0 +1,334 @@
+package project
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/release"
+	"github.com/apicat/apicat/v2/backend/module/cache"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+	changelogservice "github.com/apicat/apicat/v2/backend/service/changelog"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+	"github.com/apicat/apicat/v2/backend/utils/onetime_token"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type projectReleaseApiImpl struct{}
+
+func NewProjectReleaseApi() protoproject.ProjectReleaseApi {
+	return &projectReleaseApiImpl{}
+}
+
+// 版本号会出现在分享链接中，只允许字母、数字和._+-
+var releaseVersionRegexp = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z._+-]*$`)
+
+// releaseExportToken 导出token中保存导出参数和发起导出的项目成员，下载时重新检查导出能力
+type releaseExportToken struct {
+	projectrequest.GetReleaseExportPathOption
+	ProjectMemberID uint `json:"projectMemberID"`
+}
+
+func getRelease(ctx *gin.Context, version string) (*release.Release, error) {
+	r := &release.Release{ProjectID: access.GetSelfProject(ctx).ID, Version: version}
+	exist, err := r.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "r.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("release.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("release.DoesNotExist"))
+	}
+	return r, nil
+}
+
+func releaseAuditFields(r *release.Release) auditservice.Fields {
+	return auditservice.Fields{
+		"version":     r.Version,
+		"description": r.Description,
+	}
+}
+
+// Create 发布版本，冻结项目当前的完整内容
+func (prai *projectReleaseApiImpl) Create(ctx *gin.Context, opt *projectrequest.CreateReleaseOption) (*projectresponse.ReleaseItem, error) {
+	selfP := access.GetSelfProject(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+	if !releaseVersionRegexp.MatchString(opt.Version) {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("release.InvalidVersion"))
+	}
+
+	r := &release.Release{ProjectID: selfP.ID, Version: opt.Version}
+	exist, err := r.VersionExists(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "r.VersionExists", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("release.CreationFailed"))
+	}
+	if exist {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("release.AlreadyExists", opt.Version))
+	}
+
+	s, err := relations.ProjectReleaseSpec(ctx, selfP)
+	if err != nil {
+		slog.ErrorContext(ctx, "relations.ProjectReleaseSpec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("release.CreationFailed"))
+	}
+
+	r.Description = opt.Description
+	r.CreatedBy = access.GetSelfTeamMember(ctx).ID
+	if err := r.Create(ctx, s); err != nil {
+		slog.ErrorContext(ctx, "r.Create", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("release.CreationFailed"))
+	}
+
+	recordAudit(ctx, audit.ActionCreate, audit.TargetRelease, r.ID, r.Version, nil, releaseAuditFields(r))
+	return convertModelRelease(ctx, r), nil
+}
+
+// List 获取发布版本列表
+func (prai *projectReleaseApiImpl) List(ctx *gin.Context, opt *protobase.ProjectIdOption) (*projectresponse.ReleaseList, error) {
+	list, err := release.GetReleases(ctx, access.GetSelfProject(ctx).ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "release.GetReleases", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("release.FailedToGetList"))
+	}
+
+	res := make(projectresponse.ReleaseList, len(list))
+	for i, r := range list {
+		res[i] = convertModelRelease(ctx, r)
+	}
+	return &res, nil
+}
+
+// Get 获取发布版本详情，版本号作为稳定的分享地址
+func (prai *projectReleaseApiImpl) Get(ctx *gin.Context, opt *projectrequest.ReleaseVersionOption) (*projectresponse.Release, error) {
+	r, err := getRelease(ctx, opt.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &projectresponse.Release{
+		ReleaseItem: *convertModelRelease(ctx, r),
+		Spec:        r.Spec,
+	}, nil
+}
+
+// Delete 删除发布版本
+func (prai *projectReleaseApiImpl) Delete(ctx *gin.Context, opt *projectrequest.ReleaseVersionOption) (*ginrpc.Empty, error) {
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberManage) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	r, err := getRelease(ctx, opt.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Delete(ctx); err != nil {
+		slog.ErrorContext(ctx, "r.Delete", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("release.FailedToDelete"))
+	}
+
+	recordAudit(ctx, audit.ActionDelete, audit.TargetRelease, r.ID, r.Version, releaseAuditFields(r), nil)
+	return &ginrpc.Empty{}, nil
+}
+
+// Diff 与另一个发布版本对比，BaseVersion为对比的基准
+func (prai *projectReleaseApiImpl) Diff(ctx *gin.Context, opt *projectrequest.DiffReleaseOption) (*projectresponse.ProjectDiff, error) {
+	target, err := getRelease(ctx, opt.Version)
+	if err != nil {
+		return nil, err
+	}
+	base, err := getRelease(ctx, opt.BaseVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	targetSpec, err := target.ToSpec()
+	if err != nil {
+		slog.ErrorContext(ctx, "target.ToSpec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("release.DiffFailed"))
+	}
+	baseSpec, err := base.ToSpec()
+	if err != nil {
+		slog.ErrorContext(ctx, "base.ToSpec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("release.DiffFailed"))
+	}
+
+	report, err := diff.DiffSpec(baseSpec, targetSpec)
+	if err != nil {
+		slog.ErrorContext(ctx, "diff.DiffSpec", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("release.DiffFailed"))
+	}
+
+	c := &changelogservice.Changelog{
+		Title:  access.GetSelfProject(ctx).Title,
+		From:   base.Version,
+		To:     target.Version,
+		Report: report,
+	}
+	return c.Response(ctx), nil
+}
+
+// GetExportPath 获取发布版本导出path
+func (prai *projectReleaseApiImpl) GetExportPath(ctx *gin.Context, opt *projectrequest.GetReleaseExportPathOption) (*projectresponse.ExportProject, error) {
+	r, err := getRelease(ctx, opt.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	// 用纳秒时间避免同一秒内的请求生成相同的token
+	tokenKey := fmt.Sprintf(
+		"ExportRelease-%d-%d",
+		r.ID,
+		time.Now().UnixNano(),
+	)
+	c, err := cache.NewCache(config.Get().Cache.ToCfg())
+	if err != nil {
+		slog.ErrorContext(ctx, "cache.NewCache", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("release.ExportFailed"))
+	}
+	t := releaseExportToken{
+		GetReleaseExportPathOption: *opt,
+		ProjectMemberID:            access.GetSelfProjectMember(ctx).ID,
+	}
+	token, err := onetime_token.NewTokenHelper(c).GenerateToken(tokenKey, t, time.Minute)
+	if err != nil {
+		slog.ErrorContext(ctx, "onetime_token.GenerateToken", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("release.ExportFailed"))
+	}
+
+	return &projectresponse.ExportProject{
+		Path: fmt.Sprintf("/api/projects/%s/releases/%s/export/%s", r.ProjectID, r.Version, token),
+	}, nil
+}
+
+// ExportRelease 导出发布版本，需返回不同的 Content-Type，单独处理
+func ExportRelease(ctx *gin.Context) {
+	opt := &projectrequest.ReleaseExportCodeOption{}
+	if err := ctx.ShouldBindUri(opt); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c, err := cache.NewCache(config.Get().Cache.ToCfg())
+	if err != nil {
+		slog.ErrorContext(ctx, "cache.NewCache", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("release.ExportFailed").Translate(ctx),
+		})
+		return
+	}
+	tokenHelper := onetime_token.NewTokenHelper(c)
+
+	t := releaseExportToken{}
+	if !tokenHelper.CheckToken(opt.Code, &t) || t.ProjectID != opt.ProjectID || t.Version != opt.Version {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": i18n.NewTran("release.ExportFailed").Translate(ctx),
+		})
+		return
+	}
+	if err := tokenHelper.DelToken(opt.Code); err != nil {
+		slog.ErrorContext(ctx, "tokenHelper.DelToken", "err", err)
+	}
+
+	p := &project.Project{ID: t.ProjectID}
+	exist, err := p.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "p.Get", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("release.ExportFailed").Translate(ctx),
+		})
+		return
+	}
+	if !exist {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"message": i18n.NewTran("project.DoesNotExist").Translate(ctx),
+		})
+		return
+	}
+
+	// 发起导出后成员可能已被移除或调整了角色
+	pm := &project.ProjectMember{ID: t.ProjectMemberID}
+	exist, err = pm.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "pm.Get", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("release.ExportFailed").Translate(ctx),
+		})
+		return
+	}
+	if !exist || pm.ProjectID != p.ID {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"message": i18n.NewTran("common.PermissionDenied").Translate(ctx),
+		})
+		return
+	}
+	ok, err := pm.HasCapability(ctx, project.CapabilityExport)
+	if err != nil {
+		slog.ErrorContext(ctx, "pm.HasCapability", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("release.ExportFailed").Translate(ctx),
+		})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"message": i18n.NewTran("common.PermissionDenied").Translate(ctx),
+		})
+		return
+	}
+
+	r := &release.Release{ProjectID: p.ID, Version: t.Version}
+	exist, err = r.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "r.Get", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("release.ExportFailed").Translate(ctx),
+		})
+		return
+	}
+	if !exist {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"message": i18n.NewTran("release.DoesNotExist").Translate(ctx),
+		})
+		return
+	}
+
+	s, err := r.ToSpec()
+	if err != nil {
+		slog.ErrorContext(ctx, "r.ToSpec", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("release.ExportFailed").Translate(ctx),
+		})
+		return
+	}
+	content, err := relations.ExportContent(s, t.Type)
+	if err != nil {
+		slog.ErrorContext(ctx, "export", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": i18n.NewTran("release.ExportFailed").Translate(ctx),
+		})
+		return
+	}
+
+	writeExport(ctx, fmt.Sprintf("%s-%s-%s", p.Title, r.Version, t.Type), t.Type, t.Download, content)
+}
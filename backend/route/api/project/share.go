@@ -10,6 +10,7 @@ import (
 
 	"github.com/apicat/apicat/v2/backend/config"
 	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/project"
 	"github.com/apicat/apicat/v2/backend/model/share"
 	"github.com/apicat/apicat/v2/backend/model/team"
@@ -20,6 +21,7 @@ import (
 	projectbase "github.com/apicat/apicat/v2/backend/route/proto/project/base"
 	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
 	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
 	"github.com/apicat/apicat/v2/backend/utils/password"
 
 	"github.com/apicat/ginrpc"
@@ -89,6 +91,7 @@ func (psai *projectShareApiImpl) Switch(ctx *gin.Context, opt *projectrequest.Pr
 		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("share.PublicProjectShare"))
 	}
 
+	before := auditservice.Fields{"shared": selfP.ShareKey != ""}
 	if opt.Status {
 		if selfP.ShareKey == "" {
 			selfP.ShareKey = password.RandomPassword(4)
@@ -112,6 +115,8 @@ func (psai *projectShareApiImpl) Switch(ctx *gin.Context, opt *projectrequest.Pr
 		}
 	}
 
+	recordAudit(ctx, audit.ActionUpdate, audit.TargetProjectShare, selfP.ID, selfP.Title, before, auditservice.Fields{"shared": selfP.ShareKey != ""})
+
 	return &protobase.SecretKeyOption{
 		SecretKey: selfP.ShareKey,
 	}, nil
@@ -137,11 +142,13 @@ func (psai *projectShareApiImpl) Reset(ctx *gin.Context, opt *protobase.ProjectI
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("share.SharedKeyResetFailed"))
 	}
 
+	before := auditservice.Fields{"secretKey": selfP.ShareKey}
 	selfP.ShareKey = password.RandomPassword(4)
 	if err := selfP.UpdateShareKey(ctx); err != nil {
 		slog.ErrorContext(ctx, "selfP.UpdateShareKey", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("share.SharedKeyResetFailed"))
 	}
+	recordAudit(ctx, audit.ActionReset, audit.TargetProjectShare, selfP.ID, selfP.Title, before, auditservice.Fields{"secretKey": selfP.ShareKey})
 
 	return &protobase.SecretKeyOption{
 		SecretKey: selfP.ShareKey,
@@ -0,0 +1,131 @@
+package project
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/global"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	TimelineCollection = "collection"
+	TimelineSchema     = "schema"
+	TimelineResponse   = "response"
+	TimelineParameter  = "parameter"
+
+	defaultTimelineLimit = 50
+)
+
+type projectTimelineApiImpl struct{}
+
+func NewProjectTimelineApi() protoproject.ProjectTimelineApi {
+	return &projectTimelineApiImpl{}
+}
+
+func (impl *projectTimelineApiImpl) List(ctx *gin.Context, opt *projectrequest.GetProjectTimelineOption) (*projectresponse.ProjectTimeline, error) {
+	selfP := access.GetSelfProject(ctx)
+	selfPM := access.GetSelfProjectMember(ctx)
+	if selfPM.Permission.Lower(project.ProjectMemberWrite) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	var startTime, endTime time.Time
+	if opt.StartTime > 0 && opt.EndTime > 0 {
+		startTime = time.Unix(opt.StartTime, 0)
+		endTime = time.Unix(opt.EndTime, 0)
+	}
+	limit := opt.Limit
+	if limit == 0 {
+		limit = defaultTimelineLimit
+	}
+
+	// 每类历史各取最近的limit条，合并后再截取，保证结果是整体最近的limit条
+	var items []*timelineEntry
+	collectionHistories, err := collection.GetProjectCollectionHistories(ctx, selfP.ID, startTime, endTime, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "collection.GetProjectCollectionHistories", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("timeline.FailedToGetList"))
+	}
+	for _, v := range collectionHistories {
+		items = append(items, &timelineEntry{TimelineCollection, v.CollectionID, v.ID, v.Title, v.CreatedAt, v.CreatedBy})
+	}
+
+	schemaHistories, err := definition.GetProjectDefinitionSchemaHistories(ctx, selfP.ID, startTime, endTime, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "definition.GetProjectDefinitionSchemaHistories", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("timeline.FailedToGetList"))
+	}
+	for _, v := range schemaHistories {
+		items = append(items, &timelineEntry{TimelineSchema, v.SchemaID, v.ID, v.Name, v.CreatedAt, v.CreatedBy})
+	}
+
+	responseHistories, err := definition.GetProjectDefinitionResponseHistories(ctx, selfP.ID, startTime, endTime, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "definition.GetProjectDefinitionResponseHistories", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("timeline.FailedToGetList"))
+	}
+	for _, v := range responseHistories {
+		items = append(items, &timelineEntry{TimelineResponse, v.ResponseID, v.ID, v.Name, v.CreatedAt, v.CreatedBy})
+	}
+
+	parameterHistories, err := global.GetProjectGlobalParameterHistories(ctx, selfP.ID, startTime, endTime, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "global.GetProjectGlobalParameterHistories", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("timeline.FailedToGetList"))
+	}
+	for _, v := range parameterHistories {
+		items = append(items, &timelineEntry{TimelineParameter, v.ParameterID, v.ID, v.Name, v.CreatedAt, v.CreatedBy})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].createdAt.After(items[j].createdAt)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	userNames := make(map[uint]string)
+	res := make(projectresponse.ProjectTimeline, 0, len(items))
+	for _, v := range items {
+		name, ok := userNames[v.createdBy]
+		if !ok {
+			if userInfo, err := definition.UserInfo(ctx, v.createdBy, true); err == nil {
+				name = userInfo.Name
+			}
+			userNames[v.createdBy] = name
+		}
+		res = append(res, &projectresponse.ProjectTimelineItem{
+			Type:      v.typ,
+			TargetID:  v.targetID,
+			HistoryID: v.historyID,
+			Title:     v.title,
+			CreatedAt: v.createdAt.Unix(),
+			CreatedBy: name,
+		})
+	}
+
+	return &res, nil
+}
+
+// timelineEntry 不同类型的历史记录合并排序时的统一结构
+type timelineEntry struct {
+	typ       string
+	targetID  uint
+	historyID uint
+	title     string
+	createdAt time.Time
+	createdBy uint
+}
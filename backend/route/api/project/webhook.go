@@ -0,0 +1,282 @@
+package project
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/webhook"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	protoproject "github.com/apicat/apicat/v2/backend/route/proto/project"
+	projectrequest "github.com/apicat/apicat/v2/backend/route/proto/project/request"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	webhookservice "github.com/apicat/apicat/v2/backend/service/webhook"
+	"github.com/apicat/apicat/v2/backend/utils/safehttp"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type projectWebhookApiImpl struct{}
+
+func NewProjectWebhookApi() protoproject.ProjectWebhookApi {
+	return &projectWebhookApiImpl{}
+}
+
+func checkWebhookPermission(ctx *gin.Context) error {
+	pm := access.GetSelfProjectMember(ctx)
+	if pm.Permission.Lower(project.ProjectMemberManage) {
+		return ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+	return nil
+}
+
+func getWebhook(ctx *gin.Context, webhookID uint) (*webhook.Webhook, error) {
+	w := &webhook.Webhook{ID: webhookID, ProjectID: access.GetSelfProject(ctx).ID}
+	exist, err := w.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "w.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("webhook.FailedToGet"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("webhook.DoesNotExist"))
+	}
+	return w, nil
+}
+
+func checkWebhookEvents(events []string) error {
+	for _, e := range events {
+		if !webhook.ValidEvent(e) {
+			return ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("webhook.UnsupportedEvent", e))
+		}
+	}
+	return nil
+}
+
+// checkWebhookURL 只允许投递到公网的http或https地址
+func checkWebhookURL(rawURL string) error {
+	if err := safehttp.CheckURL(rawURL); err != nil {
+		return ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("webhook.InvalidURL"))
+	}
+	return nil
+}
+
+// Create 创建项目webhook
+func (pwai *projectWebhookApiImpl) Create(ctx *gin.Context, opt *projectrequest.CreateProjectWebhookOption) (*projectresponse.ProjectWebhook, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookEvents(opt.Events); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookURL(opt.URL); err != nil {
+		return nil, err
+	}
+
+	w := &webhook.Webhook{
+		ProjectID:     access.GetSelfProject(ctx).ID,
+		Name:          opt.Name,
+		URL:           opt.URL,
+		Secret:        opt.Secret,
+		Active:        opt.Active,
+		MaxRetries:    opt.MaxRetries,
+		RetryInterval: opt.RetryInterval,
+		CreatedBy:     access.GetSelfTeamMember(ctx).ID,
+	}
+	if w.RetryInterval == 0 {
+		w.RetryInterval = 10
+	}
+	w.SetEvents(opt.Events)
+	if err := w.Create(ctx); err != nil {
+		slog.ErrorContext(ctx, "w.Create", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("webhook.CreationFailed"))
+	}
+	return convertModelWebhook(w), nil
+}
+
+// List 获取项目webhook列表
+func (pwai *projectWebhookApiImpl) List(ctx *gin.Context, opt *protobase.ProjectIdOption) (*projectresponse.ProjectWebhookList, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	list, err := webhook.GetWebhooks(ctx, access.GetSelfProject(ctx).ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook.GetWebhooks", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("webhook.FailedToGetList"))
+	}
+
+	ret := make(projectresponse.ProjectWebhookList, len(list))
+	for i, v := range list {
+		ret[i] = convertModelWebhook(v)
+	}
+	return &ret, nil
+}
+
+// Update 修改项目webhook，secret为空时保留原secret
+func (pwai *projectWebhookApiImpl) Update(ctx *gin.Context, opt *projectrequest.UpdateProjectWebhookOption) (*ginrpc.Empty, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookEvents(opt.Events); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookURL(opt.URL); err != nil {
+		return nil, err
+	}
+
+	w, err := getWebhook(ctx, opt.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Name = opt.Name
+	w.URL = opt.URL
+	if opt.Secret != "" {
+		w.Secret = opt.Secret
+	}
+	w.Active = opt.Active
+	w.MaxRetries = opt.MaxRetries
+	if opt.RetryInterval > 0 {
+		w.RetryInterval = opt.RetryInterval
+	}
+	w.SetEvents(opt.Events)
+	if err := w.Update(ctx); err != nil {
+		slog.ErrorContext(ctx, "w.Update", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
+	}
+	return &ginrpc.Empty{}, nil
+}
+
+// Delete 删除项目webhook
+func (pwai *projectWebhookApiImpl) Delete(ctx *gin.Context, opt *projectrequest.GetProjectWebhookOption) (*ginrpc.Empty, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	w, err := getWebhook(ctx, opt.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Delete(ctx); err != nil {
+		slog.ErrorContext(ctx, "w.Delete", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("webhook.FailedToDelete"))
+	}
+	return &ginrpc.Empty{}, nil
+}
+
+// Test 发送一条测试事件
+func (pwai *projectWebhookApiImpl) Test(ctx *gin.Context, opt *projectrequest.GetProjectWebhookOption) (*projectresponse.WebhookDelivery, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	w, err := getWebhook(ctx, opt.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	d, err := webhookservice.Ping(ctx, w)
+	if err != nil {
+		slog.ErrorContext(ctx, "webhookservice.Ping", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("webhook.FailedToSend"))
+	}
+	return convertModelWebhookDelivery(d), nil
+}
+
+// DeliveryList 获取投递记录
+func (pwai *projectWebhookApiImpl) DeliveryList(ctx *gin.Context, opt *projectrequest.GetWebhookDeliveryListOption) (*projectresponse.WebhookDeliveryList, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	w, err := getWebhook(ctx, opt.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page <= 0 {
+		opt.Page = 1
+	}
+	if opt.PageSize <= 0 {
+		opt.PageSize = 15
+	}
+
+	count, err := webhook.GetDeliveriesCount(ctx, w.ID, opt.Status)
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook.GetDeliveriesCount", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("webhook.FailedToGetDeliveries"))
+	}
+	list, err := webhook.GetDeliveries(ctx, w.ID, opt.Status, opt.Page, opt.PageSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook.GetDeliveries", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("webhook.FailedToGetDeliveries"))
+	}
+
+	items := make([]*projectresponse.WebhookDelivery, len(list))
+	for i, v := range list {
+		items[i] = convertModelWebhookDelivery(v)
+	}
+	return &projectresponse.WebhookDeliveryList{
+		PaginationInfo: protobase.PaginationInfo{
+			Count:       int(count),
+			TotalPage:   int(math.Ceil(float64(count) / float64(opt.PageSize))),
+			CurrentPage: opt.Page,
+		},
+		Items: items,
+	}, nil
+}
+
+// GetDelivery 获取投递详情
+func (pwai *projectWebhookApiImpl) GetDelivery(ctx *gin.Context, opt *projectrequest.GetWebhookDeliveryOption) (*projectresponse.WebhookDeliveryDetail, error) {
+	d, err := getWebhookDelivery(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	return &projectresponse.WebhookDeliveryDetail{
+		WebhookDelivery: *convertModelWebhookDelivery(d),
+		Payload:         d.Payload,
+		ResponseBody:    d.ResponseBody,
+		Error:           d.Error,
+	}, nil
+}
+
+// Redeliver 重新投递
+func (pwai *projectWebhookApiImpl) Redeliver(ctx *gin.Context, opt *projectrequest.GetWebhookDeliveryOption) (*ginrpc.Empty, error) {
+	d, err := getWebhookDelivery(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status == webhook.DeliveryPending {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("webhook.DeliveryIsPending"))
+	}
+	if err := d.Redeliver(ctx); err != nil {
+		slog.ErrorContext(ctx, "d.Redeliver", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("webhook.FailedToSend"))
+	}
+	webhookservice.Wake()
+	return &ginrpc.Empty{}, nil
+}
+
+func getWebhookDelivery(ctx *gin.Context, opt *projectrequest.GetWebhookDeliveryOption) (*webhook.Delivery, error) {
+	if err := checkWebhookPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	w, err := getWebhook(ctx, opt.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	d := &webhook.Delivery{ID: opt.DeliveryID, WebhookID: w.ID}
+	exist, err := d.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "d.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("webhook.FailedToGetDeliveries"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("webhook.DeliveryDoesNotExist"))
+	}
+	return d, nil
+}
@@ -92,8 +92,8 @@ func (e *emailApiImpl) UpdateSMTP(ctx *gin.Context, opt *sysconfigrequest.SMTPOp
 		Config:    string(jsonData),
 	}
 
-	if err := sysconfig.UpdateOrCreate(ctx, email); err != nil {
-		slog.ErrorContext(ctx, "sysconfig.UpdateOrCreate", "err", err)
+	if err := updateOrCreate(ctx, email); err != nil {
+		slog.ErrorContext(ctx, "updateOrCreate", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.EmailUpdateFailed"))
 	}
 	config.SetEmail(emailConfig)
@@ -124,8 +124,8 @@ func (e *emailApiImpl) UpdateSendCloud(ctx *gin.Context, opt *sysconfigrequest.S
 		Config:    string(jsonData),
 	}
 
-	if err := sysconfig.UpdateOrCreate(ctx, email); err != nil {
-		slog.ErrorContext(ctx, "sysconfig.UpdateOrCreate", "err", err)
+	if err := updateOrCreate(ctx, email); err != nil {
+		slog.ErrorContext(ctx, "updateOrCreate", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.EmailUpdateFailed"))
 	}
 	config.SetEmail(emailConfig)
@@ -2,8 +2,13 @@ package sysconfig
 
 import (
 	"encoding/json"
+	"log/slog"
 
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/sysconfig"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+
+	"github.com/gin-gonic/gin"
 )
 
 func cfgFormat(cfg *sysconfig.Sysconfig) map[string]interface{} {
@@ -11,3 +16,36 @@ func cfgFormat(cfg *sysconfig.Sysconfig) map[string]interface{} {
 	json.Unmarshal([]byte(cfg.Config), &configMap)
 	return configMap
 }
+
+func cfgAuditFields(cfg *sysconfig.Sysconfig) auditservice.Fields {
+	fields := auditservice.Fields{"driver": cfg.Driver}
+	for k, v := range cfgFormat(cfg) {
+		fields[k] = v
+	}
+	return fields
+}
+
+// updateOrCreate 更新系统配置并记录审计日志
+func updateOrCreate(ctx *gin.Context, sc *sysconfig.Sysconfig) error {
+	var before auditservice.Fields
+	old := &sysconfig.Sysconfig{Type: sc.Type}
+	if exist, err := old.GetByUse(ctx); err != nil {
+		slog.ErrorContext(ctx, "old.GetByUse", "err", err)
+	} else if exist {
+		before = cfgAuditFields(old)
+	}
+
+	if err := sysconfig.UpdateOrCreate(ctx, sc); err != nil {
+		return err
+	}
+
+	auditservice.Record(ctx, &auditservice.Entry{
+		Action:     audit.ActionUpdate,
+		TargetType: audit.TargetSysconfig,
+		TargetID:   sc.Type,
+		TargetName: sc.Type,
+		Before:     before,
+		After:      cfgAuditFields(sc),
+	})
+	return nil
+}
@@ -50,36 +50,7 @@ func (s *modelApiImpl) UpdateOpenAI(ctx *gin.Context, opt *sysconfigrequest.Open
 			LLMName:        opt.LLMName,
 		},
 	}
-
-	if ai, err := llm.NewLLM(modelConfig.ToCfg()); err != nil {
-		slog.ErrorContext(ctx, "openai.NewOpenAI", "err", err)
-		return nil, ginrpc.NewError(http.StatusBadRequest, err)
-	} else {
-		if err := ai.Check(); err != nil {
-			slog.ErrorContext(ctx, "ai.Check", "err", err)
-			return nil, ginrpc.NewError(http.StatusBadRequest, err)
-		}
-	}
-
-	jsonData, err := json.Marshal(opt)
-	if err != nil {
-		slog.ErrorContext(ctx, "json.Marshal", "err", err)
-		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.ModelUpdateFailed"))
-	}
-
-	storage := &sysconfig.Sysconfig{
-		Type:      "model",
-		Driver:    llm.OPENAI,
-		BeingUsed: true,
-		Config:    string(jsonData),
-	}
-
-	if err := sysconfig.UpdateOrCreate(ctx, storage); err != nil {
-		slog.ErrorContext(ctx, "sysconfig.UpdateOrCreate", "err", err)
-		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.ModelUpdateFailed"))
-	}
-	config.SetLLM(modelConfig)
-	return nil, nil
+	return nil, updateModel(ctx, modelConfig, opt)
 }
 
 func (s *modelApiImpl) UpdateAzureOpenAI(ctx *gin.Context, opt *sysconfigrequest.AzureOpenAIOption) (*ginrpc.Empty, error) {
@@ -91,34 +62,75 @@ func (s *modelApiImpl) UpdateAzureOpenAI(ctx *gin.Context, opt *sysconfigrequest
 			LLMName:  opt.LLMName,
 		},
 	}
+	return nil, updateModel(ctx, modelConfig, opt)
+}
+
+func (s *modelApiImpl) UpdateOpenAICompatible(ctx *gin.Context, opt *sysconfigrequest.OpenAICompatibleOption) (*ginrpc.Empty, error) {
+	modelConfig := &config.LLM{
+		Driver: llm.OPENAICOMPATIBLE,
+		OpenAICompatible: &config.OpenAICompatible{
+			ApiKey:        opt.ApiKey,
+			ApiBase:       opt.ApiBase,
+			LLMName:       opt.LLMName,
+			EmbeddingName: opt.EmbeddingName,
+		},
+	}
+	return nil, updateModel(ctx, modelConfig, opt)
+}
+
+func (s *modelApiImpl) UpdateOllama(ctx *gin.Context, opt *sysconfigrequest.OllamaOption) (*ginrpc.Empty, error) {
+	modelConfig := &config.LLM{
+		Driver: llm.OLLAMA,
+		Ollama: &config.Ollama{
+			Host:          opt.Host,
+			LLMName:       opt.LLMName,
+			EmbeddingName: opt.EmbeddingName,
+		},
+	}
+	return nil, updateModel(ctx, modelConfig, opt)
+}
+
+func (s *modelApiImpl) UpdateAnthropic(ctx *gin.Context, opt *sysconfigrequest.AnthropicOption) (*ginrpc.Empty, error) {
+	modelConfig := &config.LLM{
+		Driver: llm.ANTHROPIC,
+		Anthropic: &config.Anthropic{
+			ApiKey:  opt.ApiKey,
+			ApiBase: opt.ApiBase,
+			LLMName: opt.LLMName,
+		},
+	}
+	return nil, updateModel(ctx, modelConfig, opt)
+}
 
+// updateModel 检查模型配置可用后保存并启用
+func updateModel(ctx *gin.Context, modelConfig *config.LLM, opt interface{}) error {
 	if ai, err := llm.NewLLM(modelConfig.ToCfg()); err != nil {
-		slog.ErrorContext(ctx, "openai.NewOpenAI", "err", err)
-		return nil, ginrpc.NewError(http.StatusBadRequest, err)
+		slog.ErrorContext(ctx, "llm.NewLLM", "driver", modelConfig.Driver, "err", err)
+		return ginrpc.NewError(http.StatusBadRequest, err)
 	} else {
 		if err := ai.Check(); err != nil {
-			slog.ErrorContext(ctx, "ai.Check", "err", err)
-			return nil, ginrpc.NewError(http.StatusBadRequest, err)
+			slog.ErrorContext(ctx, "ai.Check", "driver", modelConfig.Driver, "err", err)
+			return ginrpc.NewError(http.StatusBadRequest, err)
 		}
 	}
 
 	jsonData, err := json.Marshal(opt)
 	if err != nil {
 		slog.ErrorContext(ctx, "json.Marshal", "err", err)
-		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.ModelUpdateFailed"))
+		return ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.ModelUpdateFailed"))
 	}
 
 	storage := &sysconfig.Sysconfig{
 		Type:      "model",
-		Driver:    llm.AZUREOPENAI,
+		Driver:    modelConfig.Driver,
 		BeingUsed: true,
 		Config:    string(jsonData),
 	}
 
-	if err := sysconfig.UpdateOrCreate(ctx, storage); err != nil {
-		slog.ErrorContext(ctx, "sysconfig.UpdateOrCreate", "err", err)
-		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.ModelUpdateFailed"))
+	if err := updateOrCreate(ctx, storage); err != nil {
+		slog.ErrorContext(ctx, "updateOrCreate", "err", err)
+		return ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.ModelUpdateFailed"))
 	}
 	config.SetLLM(modelConfig)
-	return nil, nil
+	return nil
 }
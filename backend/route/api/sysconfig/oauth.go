@@ -68,8 +68,8 @@ func (o *oauthApiImpl) Update(ctx *gin.Context, opt *sysconfigbase.GitHubOption)
 		Config:    string(jsonData),
 	}
 
-	if err := sysconfig.UpdateOrCreate(ctx, oauth); err != nil {
-		slog.ErrorContext(ctx, "sysconfig.UpdateOrCreate", "err", err)
+	if err := updateOrCreate(ctx, oauth); err != nil {
+		slog.ErrorContext(ctx, "updateOrCreate", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.OauthUpdateFailed"))
 	}
 
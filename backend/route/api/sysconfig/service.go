@@ -50,8 +50,8 @@ func (s *serviceApiImpl) Update(ctx *gin.Context, opt *sysconfigbase.ServiceOpti
 		Config:    string(jsonData),
 	}
 
-	if err := sysconfig.UpdateOrCreate(ctx, app); err != nil {
-		slog.ErrorContext(ctx, "sysconfig.UpdateOrCreate", "err", err)
+	if err := updateOrCreate(ctx, app); err != nil {
+		slog.ErrorContext(ctx, "updateOrCreate", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.ServiceUpdateFailed"))
 	}
 	config.SetApp(appConfig)
@@ -69,8 +69,8 @@ func (s *storageApiImpl) UpdateDisk(ctx *gin.Context, opt *sysconfigrequest.Disk
 		Config:    string(jsonData),
 	}
 
-	if err := sysconfig.UpdateOrCreate(ctx, storage); err != nil {
-		slog.ErrorContext(ctx, "sysconfig.UpdateOrCreate", "err", err)
+	if err := updateOrCreate(ctx, storage); err != nil {
+		slog.ErrorContext(ctx, "updateOrCreate", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.StorageUpdateFailed"))
 	}
 	config.SetStorage(storageConfig)
@@ -109,8 +109,8 @@ func (s *storageApiImpl) UpdateCloudflare(ctx *gin.Context, opt *sysconfigreques
 		Config:    string(jsonData),
 	}
 
-	if err := sysconfig.UpdateOrCreate(ctx, storage); err != nil {
-		slog.ErrorContext(ctx, "sysconfig.UpdateOrCreate", "err", err)
+	if err := updateOrCreate(ctx, storage); err != nil {
+		slog.ErrorContext(ctx, "updateOrCreate", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.StorageUpdateFailed"))
 	}
 	config.SetStorage(storageConfig)
@@ -148,8 +148,8 @@ func (s *storageApiImpl) UpdateQiniu(ctx *gin.Context, opt *sysconfigrequest.Qin
 		Config:    string(jsonData),
 	}
 
-	if err := sysconfig.UpdateOrCreate(ctx, storage); err != nil {
-		slog.ErrorContext(ctx, "sysconfig.UpdateOrCreate", "err", err)
+	if err := updateOrCreate(ctx, storage); err != nil {
+		slog.ErrorContext(ctx, "updateOrCreate", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("sysConfig.StorageUpdateFailed"))
 	}
 	config.SetStorage(storageConfig)
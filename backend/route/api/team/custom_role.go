@@ -0,0 +1,149 @@
+package team
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/team"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	prototeam "github.com/apicat/apicat/v2/backend/route/proto/team"
+	prototeambase "github.com/apicat/apicat/v2/backend/route/proto/team/base"
+	prototeamrequest "github.com/apicat/apicat/v2/backend/route/proto/team/request"
+	prototeamresponse "github.com/apicat/apicat/v2/backend/route/proto/team/response"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type customRoleApiImpl struct{}
+
+func NewCustomRoleApi() prototeam.CustomRoleApi {
+	return &customRoleApiImpl{}
+}
+
+func convertModelCustomRole(r *project.CustomRole) *prototeamresponse.CustomRole {
+	return &prototeamresponse.CustomRole{
+		EmbedInfo: protobase.EmbedInfo{
+			ID:        r.ID,
+			CreatedAt: r.CreatedAt.Unix(),
+			UpdatedAt: r.UpdatedAt.Unix(),
+		},
+		CustomRoleDataOption: prototeambase.CustomRoleDataOption{
+			Name:         r.Name,
+			Description:  r.Description,
+			Capabilities: r.GetCapabilities(),
+		},
+	}
+}
+
+// List 团队的自定义角色列表
+func (cr *customRoleApiImpl) List(ctx *gin.Context, opt *protobase.TeamIdOption) (*prototeamresponse.CustomRoles, error) {
+	roles, err := project.GetCustomRoles(ctx, access.GetSelfTeam(ctx).ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "project.GetCustomRoles", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("customRole.FailedToGetList"))
+	}
+
+	list := make(prototeamresponse.CustomRoles, len(roles))
+	for i, r := range roles {
+		list[i] = convertModelCustomRole(r)
+	}
+	return &list, nil
+}
+
+// Create 创建自定义角色
+func (cr *customRoleApiImpl) Create(ctx *gin.Context, opt *prototeamrequest.CreateCustomRoleOption) (*prototeamresponse.CustomRole, error) {
+	selfTeam := access.GetSelfTeam(ctx)
+	selfMember := access.GetSelfTeamMember(ctx)
+	if selfMember.Role.Lower(team.RoleAdmin) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	r := &project.CustomRole{
+		TeamID:      selfTeam.ID,
+		Name:        opt.Name,
+		Description: opt.Description,
+	}
+	r.SetCapabilities(opt.Capabilities)
+
+	repeat, err := r.CheckRepeat(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "r.CheckRepeat", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("customRole.CreationFailed"))
+	}
+	if repeat {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("customRole.NameHasBeenUsed"))
+	}
+
+	if err := r.Create(ctx); err != nil {
+		slog.ErrorContext(ctx, "r.Create", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("customRole.CreationFailed"))
+	}
+	return convertModelCustomRole(r), nil
+}
+
+// Update 编辑自定义角色
+func (cr *customRoleApiImpl) Update(ctx *gin.Context, opt *prototeamrequest.UpdateCustomRoleOption) (*ginrpc.Empty, error) {
+	selfTeam := access.GetSelfTeam(ctx)
+	selfMember := access.GetSelfTeamMember(ctx)
+	if selfMember.Role.Lower(team.RoleAdmin) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	r := &project.CustomRole{ID: opt.RoleID, TeamID: selfTeam.ID}
+	exist, err := r.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "r.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("customRole.DoesNotExist"))
+	}
+
+	r.Name = opt.Name
+	r.Description = opt.Description
+	r.SetCapabilities(opt.Capabilities)
+
+	repeat, err := r.CheckRepeat(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "r.CheckRepeat", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
+	}
+	if repeat {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("customRole.NameHasBeenUsed"))
+	}
+
+	if err := r.Update(ctx); err != nil {
+		slog.ErrorContext(ctx, "r.Update", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.ModificationFailed"))
+	}
+	return &ginrpc.Empty{}, nil
+}
+
+// Delete 删除自定义角色
+func (cr *customRoleApiImpl) Delete(ctx *gin.Context, opt *prototeamrequest.CustomRoleIDOption) (*ginrpc.Empty, error) {
+	selfTeam := access.GetSelfTeam(ctx)
+	selfMember := access.GetSelfTeamMember(ctx)
+	if selfMember.Role.Lower(team.RoleAdmin) {
+		return nil, ginrpc.NewError(http.StatusForbidden, i18n.NewErr("common.PermissionDenied"))
+	}
+
+	r := &project.CustomRole{ID: opt.RoleID, TeamID: selfTeam.ID}
+	exist, err := r.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "r.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("customRole.FailedToDelete"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("customRole.DoesNotExist"))
+	}
+
+	if err := r.Delete(ctx); err != nil {
+		slog.ErrorContext(ctx, "r.Delete", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("customRole.FailedToDelete"))
+	}
+	return &ginrpc.Empty{}, nil
+}
@@ -6,12 +6,14 @@ import (
 	"net/http"
 
 	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/audit"
 	"github.com/apicat/apicat/v2/backend/model/team"
 	"github.com/apicat/apicat/v2/backend/route/middleware/access"
 	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
 	prototeam "github.com/apicat/apicat/v2/backend/route/proto/team"
 	prototeamrequest "github.com/apicat/apicat/v2/backend/route/proto/team/request"
 	prototeamresponse "github.com/apicat/apicat/v2/backend/route/proto/team/response"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
 	"github.com/apicat/apicat/v2/backend/service/relations"
 
 	"github.com/apicat/ginrpc"
@@ -85,6 +87,7 @@ func (t *teamMemberApiImpl) UpdateMember(ctx *gin.Context, opt *prototeamrequest
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.OperationFailed"))
 	}
 
+	before := auditservice.Fields{"role": targetMember.Role, "status": targetMember.Status}
 	var needModify bool
 	// 如果目标权限和新权限不一致才会更新权限
 	if opt.Role != "" && targetMember.Role != opt.Role {
@@ -107,6 +110,14 @@ func (t *teamMemberApiImpl) UpdateMember(ctx *gin.Context, opt *prototeamrequest
 			slog.ErrorContext(ctx, "targetMember.Update", "err", err)
 			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.OperationFailed"))
 		}
+		auditservice.Record(ctx, &auditservice.Entry{
+			Action:     audit.ActionUpdate,
+			TargetType: audit.TargetTeamMember,
+			TargetID:   targetMember.ID,
+			TargetName: userInfo.Name,
+			Before:     before,
+			After:      auditservice.Fields{"role": targetMember.Role, "status": targetMember.Status},
+		})
 	}
 
 	return relations.ConvertModelTeamMember(ctx, targetMember, userInfo), nil
@@ -146,6 +157,17 @@ func (t *teamMemberApiImpl) DeleteMember(ctx *gin.Context, opt *prototeamrequest
 		slog.ErrorContext(ctx, "target.Quit", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("teamMember.RemoveFailed"))
 	}
+	name := ""
+	if u, err := target.UserInfo(ctx, true); err == nil {
+		name = u.Name
+	}
+	auditservice.Record(ctx, &auditservice.Entry{
+		Action:     audit.ActionDelete,
+		TargetType: audit.TargetTeamMember,
+		TargetID:   target.ID,
+		TargetName: name,
+		Before:     auditservice.Fields{"role": target.Role, "status": target.Status},
+	})
 
 	return &ginrpc.Empty{}, nil
 }
@@ -0,0 +1,133 @@
+package team
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/embedding"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	prototeam "github.com/apicat/apicat/v2/backend/route/proto/team"
+	prototeamrequest "github.com/apicat/apicat/v2/backend/route/proto/team/request"
+	prototeamresponse "github.com/apicat/apicat/v2/backend/route/proto/team/response"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+	embeddingservice "github.com/apicat/apicat/v2/backend/service/embedding"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultSearchLimit = 10
+
+type teamSearchApiImpl struct{}
+
+func NewTeamSearchApi() prototeam.TeamSearchApi {
+	return &teamSearchApiImpl{}
+}
+
+// Search 按语义搜索当前成员可访问项目中的接口、文档和模型，回收站中的内容不返回
+func (tsai *teamSearchApiImpl) Search(ctx *gin.Context, opt *prototeamrequest.SemanticSearchOption) (*prototeamresponse.SemanticSearchResult, error) {
+	projects, err := project.GetProjects(ctx, access.GetSelfTeamMember(ctx))
+	if err != nil {
+		slog.ErrorContext(ctx, "project.GetProjects", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("semanticSearch.Failed"))
+	}
+	projectMap := make(map[string]*project.Project)
+	for _, p := range projects {
+		if opt.ProjectID == "" || opt.ProjectID == p.ID {
+			projectMap[p.ID] = p
+		}
+	}
+	if opt.ProjectID != "" && len(projectMap) == 0 {
+		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("project.DoesNotExist"))
+	}
+	projectIDs := make([]string, 0, len(projectMap))
+	for id := range projectMap {
+		projectIDs = append(projectIDs, id)
+	}
+
+	limit := opt.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	// 多取一些，过滤掉已删除的内容后仍能返回足够的结果
+	hits, err := embeddingservice.Search(ctx, projectIDs, opt.Query, limit*2)
+	if err != nil {
+		if errors.Is(err, embeddingservice.ErrNotConfigured) {
+			return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("semanticSearch.NotConfigured"))
+		}
+		if errors.Is(err, aiusage.ErrQuotaExceeded) {
+			return nil, ginrpc.NewError(http.StatusTooManyRequests, i18n.NewErr("aiUsage.QuotaExceeded"))
+		}
+		slog.ErrorContext(ctx, "embeddingservice.Search", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("semanticSearch.Failed"))
+	}
+
+	collectionIDs := make(map[string][]uint)
+	schemaIDs := make(map[string][]uint)
+	for _, h := range hits {
+		if h.TargetType == embedding.TargetSchema {
+			schemaIDs[h.ProjectID] = append(schemaIDs[h.ProjectID], h.TargetID)
+		} else {
+			collectionIDs[h.ProjectID] = append(collectionIDs[h.ProjectID], h.TargetID)
+		}
+	}
+
+	items := make(map[string]map[uint]*prototeamresponse.SemanticSearchItem)
+	items[embedding.TargetCollection] = make(map[uint]*prototeamresponse.SemanticSearchItem)
+	items[embedding.TargetSchema] = make(map[uint]*prototeamresponse.SemanticSearchItem)
+	for pID, ids := range collectionIDs {
+		list, err := collection.GetCollections(ctx, pID, ids...)
+		if err != nil {
+			slog.ErrorContext(ctx, "collection.GetCollections", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("semanticSearch.Failed"))
+		}
+		for _, c := range list {
+			items[embedding.TargetCollection][c.ID] = &prototeamresponse.SemanticSearchItem{
+				ProjectID:    pID,
+				ProjectTitle: projectMap[pID].Title,
+				TargetType:   embedding.TargetCollection,
+				TargetID:     c.ID,
+				Type:         c.Type,
+				Title:        c.Title,
+				Method:       c.Method,
+				Path:         c.Path,
+			}
+		}
+	}
+	for pID, ids := range schemaIDs {
+		list, err := definition.GetDefinitionSchemas(ctx, pID, ids...)
+		if err != nil {
+			slog.ErrorContext(ctx, "definition.GetDefinitionSchemas", "err", err)
+			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("semanticSearch.Failed"))
+		}
+		for _, ds := range list {
+			items[embedding.TargetSchema][ds.ID] = &prototeamresponse.SemanticSearchItem{
+				ProjectID:    pID,
+				ProjectTitle: projectMap[pID].Title,
+				TargetType:   embedding.TargetSchema,
+				TargetID:     ds.ID,
+				Type:         definition.SchemaSchema,
+				Title:        ds.Name,
+			}
+		}
+	}
+
+	res := make(prototeamresponse.SemanticSearchResult, 0, limit)
+	for _, h := range hits {
+		item, ok := items[h.TargetType][h.TargetID]
+		if !ok {
+			continue
+		}
+		item.Score = h.Score
+		res = append(res, item)
+		if len(res) >= limit {
+			break
+		}
+	}
+	return &res, nil
+}
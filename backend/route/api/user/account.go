@@ -1,10 +1,8 @@
 package user
 
 import (
-	"fmt"
 	"log/slog"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/apicat/apicat/v2/backend/config"
@@ -44,30 +42,21 @@ func (s *accountApiImpl) buildToken(ctx *gin.Context, usr *user.User) protouserb
 
 // Login 登录
 func (s *accountApiImpl) Login(ctx *gin.Context, opt *protouserrequest.LoginOption) (*protouserbase.TokenResponse, error) {
-	// 按照ip和email组合最大重试次数
-	var number int
-
 	ucache, err := cache.NewCache(config.Get().Cache.ToCfg())
 	if err != nil {
 		slog.ErrorContext(ctx, "cache.NewCache", "err", err)
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("user.LoginFailed"))
 	}
 
-	loginTimeKey := fmt.Sprintf("login-%s/%s", opt.Email, ctx.ClientIP())
-	ts, ok, _ := ucache.Get(loginTimeKey)
-	if ok {
-		var err error
-		number, err = strconv.Atoi(ts)
-		if err != nil {
-			slog.ErrorContext(ctx, "strconv.Atoi", "err", err)
-			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("user.LoginFailed"))
-		}
-		if number > 10 {
-			return nil, ginrpc.NewError(http.StatusTooManyRequests, i18n.NewErr("common.TooManyOperations"))
-		}
+	// 分别按照ip和email限制失败次数，失败越多需要等待的时间越长
+	ipLimiter := newLoginIPLimiter(ucache)
+	emailLimiter := newLoginEmailLimiter(ucache)
+	if locked, d := emailLimiter.Locked(opt.Email); locked {
+		return nil, tooManyOperations(ctx, d, i18n.NewErr("user.AccountLocked"))
+	}
+	if wait := max(ipLimiter.Wait(ctx.ClientIP()), emailLimiter.Wait(opt.Email)); wait > 0 {
+		return nil, tooManyOperations(ctx, wait, i18n.NewErr("common.TooManyOperations"))
 	}
-
-	_ = ucache.Set(loginTimeKey, strconv.Itoa(number+1), time.Hour)
 
 	usr := &user.User{Email: opt.Email}
 	exist, err := usr.Get(ctx)
@@ -76,10 +65,23 @@ func (s *accountApiImpl) Login(ctx *gin.Context, opt *protouserrequest.LoginOpti
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("user.LoginFailed"))
 	}
 	if !exist || !usr.CheckPassword(opt.Password) {
+		if _, err := ipLimiter.Fail(ctx.ClientIP()); err != nil {
+			slog.ErrorContext(ctx, "ipLimiter.Fail", "err", err)
+		}
+		// 不存在的邮箱同样计数，避免通过锁定状态判断邮箱是否已注册
+		locked, err := emailLimiter.Fail(opt.Email)
+		if err != nil {
+			slog.ErrorContext(ctx, "emailLimiter.Fail", "err", err)
+		}
+		if locked && exist {
+			mailer.SendAccountLockedMail(ctx, usr, loginLockDuration)
+		}
 		return nil, ginrpc.NewError(http.StatusNotFound, i18n.NewErr("user.IncorrectEmailOrPassword"))
 	}
 
-	_ = ucache.Del(loginTimeKey)
+	// 登录成功后清除该ip和邮箱的失败记录，与注册一致
+	_ = ipLimiter.Reset(ctx.ClientIP())
+	_ = emailLimiter.Reset(opt.Email)
 
 	if !usr.IsActive {
 		// 还未激活
@@ -111,24 +113,14 @@ func (s *accountApiImpl) Register(ctx *gin.Context, opt *protouserrequest.Regist
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("user.RegisterFailed"))
 	}
 
-	// 按照ip最大重试次数
-	registerTimeKey := fmt.Sprintf("register-%s", ctx.ClientIP())
-	ts, ok, _ := ucache.Get(registerTimeKey)
-	var number int
-	if ok {
-		var err error
-		number, err = strconv.Atoi(ts)
-		if err != nil {
-			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("user.RegisterFailed"))
-		}
-		if number > 10 {
-			return nil, ginrpc.NewError(
-				http.StatusTooManyRequests,
-				i18n.NewErr("common.TooManyOperations"),
-			)
-		}
+	// 按照ip限制注册失败次数
+	registerLimiter := newRegisterLimiter(ucache)
+	if wait := registerLimiter.Wait(ctx.ClientIP()); wait > 0 {
+		return nil, tooManyOperations(ctx, wait, i18n.NewErr("common.TooManyOperations"))
+	}
+	if _, err := registerLimiter.Fail(ctx.ClientIP()); err != nil {
+		slog.ErrorContext(ctx, "registerLimiter.Fail", "err", err)
 	}
-	_ = ucache.Set(registerTimeKey, strconv.Itoa(number+1), time.Hour)
 
 	if _, exist := user.SupportedLanguages[opt.Language]; !exist {
 		opt.Language = user.LanguageEnUS
@@ -145,8 +137,7 @@ func (s *accountApiImpl) Register(ctx *gin.Context, opt *protouserrequest.Regist
 	if err != nil {
 		return nil, err
 	}
-
-	_ = ucache.Del(registerTimeKey)
+	_ = registerLimiter.Reset(ctx.ClientIP())
 
 	// 如果有邀请码则加入团队
 	if opt.InvitationToken != "" {
@@ -229,8 +220,7 @@ func (s *accountApiImpl) RegisterFire(ctx *gin.Context, opt *protouserrequest.Co
 	}
 
 	tokenHelper.DelToken(opt.Code)
-	registerTimeKey := fmt.Sprintf("register-%s", ctx.ClientIP())
-	_ = c.Del(registerTimeKey)
+	_ = newRegisterLimiter(c).Reset(ctx.ClientIP())
 
 	return &protouserresponse.RegisterFireRes{
 		MessageTemplate: protouserbase.MessageTemplate{
@@ -355,22 +345,18 @@ func (s *accountApiImpl) SendResetPasswordMail(ctx *gin.Context, opt *protouserb
 		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.EmailSendFailed"))
 	}
 
-	// 按照ip最大重试次数
-	resetPasswordTimeKey := fmt.Sprintf("resetPassword-%s", ctx.ClientIP())
-	ts, ok, _ := ucache.Get(resetPasswordTimeKey)
-	var number int
-	if ok {
-		var err error
-		number, err = strconv.Atoi(ts)
-		if err != nil {
-			return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("common.EmailSendFailed"))
-		}
-		if number > 10 {
-			return nil, ginrpc.NewError(http.StatusTooManyRequests, i18n.NewErr("common.TooManyOperations"))
-		}
+	// 按照ip和email限制发送次数
+	ipLimiter := newRetrievePasswordIPLimiter(ucache)
+	emailLimiter := newRetrievePasswordEmailLimiter(ucache)
+	if wait := max(ipLimiter.Wait(ctx.ClientIP()), emailLimiter.Wait(opt.Email)); wait > 0 {
+		return nil, tooManyOperations(ctx, wait, i18n.NewErr("common.TooManyOperations"))
+	}
+	if _, err := ipLimiter.Fail(ctx.ClientIP()); err != nil {
+		slog.ErrorContext(ctx, "ipLimiter.Fail", "err", err)
+	}
+	if _, err := emailLimiter.Fail(opt.Email); err != nil {
+		slog.ErrorContext(ctx, "emailLimiter.Fail", "err", err)
 	}
-
-	_ = ucache.Set(resetPasswordTimeKey, strconv.Itoa(number+1), time.Hour)
 
 	u := &user.User{Email: opt.Email}
 	if exist, err := u.Get(ctx); err != nil {
@@ -448,10 +434,10 @@ func (s *accountApiImpl) ResetPassword(ctx *gin.Context, opt *protouserrequest.R
 
 	// 重置完密码这个邮箱连接就失效了
 	tokenHelper.DelToken(opt.Code)
-	loginTimeKey := fmt.Sprintf("login-%s/%s", v.Email, ctx.ClientIP())
-	_ = ucache.Del(loginTimeKey)
-	resetPasswordTimeKey := fmt.Sprintf("resetPassword-%s", ctx.ClientIP())
-	_ = ucache.Del(resetPasswordTimeKey)
+	// 密码已重置，解除该账户的登录锁定
+	_ = newLoginEmailLimiter(ucache).Reset(v.Email)
+	_ = newRetrievePasswordIPLimiter(ucache).Reset(ctx.ClientIP())
+	_ = newRetrievePasswordEmailLimiter(ucache).Reset(v.Email)
 
 	return &protouserbase.MessageTemplate{
 		Emoji:       "🎉",
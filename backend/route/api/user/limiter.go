@@ -0,0 +1,81 @@
+package user
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/module/cache/common"
+	"github.com/apicat/apicat/v2/backend/utils/limiter"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+const loginLockDuration = time.Minute * 30
+
+// newLoginIPLimiter 按ip限制登录失败次数，防止同一来源尝试大量账户
+func newLoginIPLimiter(c common.Cache) *limiter.Limiter {
+	return limiter.NewLimiter(c, "login-ip", limiter.Rule{
+		FreeAttempts: 10,
+		BaseDelay:    time.Second * 2,
+		MaxDelay:     time.Minute * 5,
+		Window:       time.Hour,
+	})
+}
+
+// newLoginEmailLimiter 按邮箱限制登录失败次数，失败过多时锁定账户
+func newLoginEmailLimiter(c common.Cache) *limiter.Limiter {
+	return limiter.NewLimiter(c, "login-email", limiter.Rule{
+		FreeAttempts: 3,
+		BaseDelay:    time.Second * 2,
+		MaxDelay:     time.Minute,
+		LockAttempts: 10,
+		LockDuration: loginLockDuration,
+		Window:       time.Hour,
+	})
+}
+
+// newRegisterLimiter 按ip限制注册失败次数，注册成功后清除
+func newRegisterLimiter(c common.Cache) *limiter.Limiter {
+	return limiter.NewLimiter(c, "register-ip", limiter.Rule{
+		FreeAttempts: 10,
+		BaseDelay:    time.Minute,
+		MaxDelay:     time.Hour,
+		Window:       time.Hour,
+	})
+}
+
+// newRetrievePasswordIPLimiter 按ip限制找回密码邮件的发送次数
+func newRetrievePasswordIPLimiter(c common.Cache) *limiter.Limiter {
+	return limiter.NewLimiter(c, "retrieve-password-ip", limiter.Rule{
+		FreeAttempts: 10,
+		BaseDelay:    time.Minute,
+		MaxDelay:     time.Hour,
+		Window:       time.Hour,
+	})
+}
+
+// newRetrievePasswordEmailLimiter 按邮箱限制找回密码邮件的发送次数，避免对同一邮箱频繁发信
+func newRetrievePasswordEmailLimiter(c common.Cache) *limiter.Limiter {
+	return limiter.NewLimiter(c, "retrieve-password-email", limiter.Rule{
+		FreeAttempts: 3,
+		BaseDelay:    time.Minute,
+		MaxDelay:     time.Minute * 30,
+		Window:       time.Hour,
+	})
+}
+
+// tooManyOperations 返回需要等待的错误，并告知客户端重试时间
+func tooManyOperations(ctx *gin.Context, wait time.Duration, err error) error {
+	seconds := int(math.Ceil(wait.Seconds()))
+	ctx.Header("Retry-After", strconv.Itoa(seconds))
+	return &ginrpc.Error{
+		Code: http.StatusTooManyRequests,
+		Err:  err,
+		Attrs: map[string]any{
+			"retryAfter": seconds,
+		},
+	}
+}
@@ -24,6 +24,7 @@ import (
 	protouserresponse "github.com/apicat/apicat/v2/backend/route/proto/user/response"
 	"github.com/apicat/apicat/v2/backend/service/relations"
 	imgutil "github.com/apicat/apicat/v2/backend/utils/image"
+	"github.com/apicat/apicat/v2/backend/utils/limiter"
 
 	"github.com/apicat/ginrpc"
 	"github.com/gin-gonic/gin"
@@ -61,10 +62,23 @@ func (*userApiImpl) GetList(ctx *gin.Context, opt *protouserrequest.UserListOpti
 			TotalPage:   int(math.Ceil(float64(count) / float64(opt.PageSize))),
 			CurrentPage: opt.Page,
 		},
-		Items: make([]protouserresponse.User, len(items)),
+		Items: make([]protouserresponse.UserListItem, len(items)),
 	}
+
+	var emailLimiter *limiter.Limiter
+	if c, err := cache.NewCache(config.Get().Cache.ToCfg()); err != nil {
+		slog.ErrorContext(ctx, "cache.NewCache", "err", err)
+	} else {
+		emailLimiter = newLoginEmailLimiter(c)
+	}
+
 	for k, v := range items {
-		list.Items[k] = relations.ConvertModelUser(ctx, v)
+		list.Items[k] = protouserresponse.UserListItem{
+			User: relations.ConvertModelUser(ctx, v),
+		}
+		if emailLimiter != nil {
+			list.Items[k].Locked, _ = emailLimiter.Locked(v.Email)
+		}
 	}
 	return list, nil
 }
@@ -116,6 +130,32 @@ func (ua *userApiImpl) DelUser(ctx *gin.Context, opt *protouserrequest.UserIDOpt
 	return &ginrpc.Empty{}, nil
 }
 
+// Unlock 解除用户因登录失败次数过多导致的锁定
+func (ua *userApiImpl) Unlock(ctx *gin.Context, opt *protouserrequest.UserIDOption) (*ginrpc.Empty, error) {
+	u := user.User{ID: opt.UserID}
+	exist, err := u.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "u.Get", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("user.FailedToUnlock"))
+	}
+	if !exist {
+		return nil, ginrpc.NewError(http.StatusBadRequest, i18n.NewErr("user.DoesNotExist"))
+	}
+
+	c, err := cache.NewCache(config.Get().Cache.ToCfg())
+	if err != nil {
+		slog.ErrorContext(ctx, "cache.NewCache", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("user.FailedToUnlock"))
+	}
+
+	if err := newLoginEmailLimiter(c).Reset(u.Email); err != nil {
+		slog.ErrorContext(ctx, "emailLimiter.Reset", "err", err)
+		return nil, ginrpc.NewError(http.StatusInternalServerError, i18n.NewErr("user.FailedToUnlock"))
+	}
+
+	return &ginrpc.Empty{}, nil
+}
+
 // GetSelf 当前登录的用户信息
 func (*userApiImpl) GetSelf(ctx *gin.Context, _ *ginrpc.Empty) (*protouserresponse.User, error) {
 	u := jwt.GetUser(ctx)
@@ -78,8 +78,21 @@ func Init() error {
 			{Method: []string{http.MethodPost}, Path: "/api/projects/:projectID/collections/:collectionID/share/check"},
 			// 导出项目
 			{Method: []string{http.MethodGet}, Path: "/api/projects/:projectID/export/:code"},
+			// 发布版本列表、详情、对比和导出
+			{Method: []string{http.MethodGet}, Path: "/api/projects/:projectID/releases"},
+			{Method: []string{http.MethodGet}, Path: "/api/projects/:projectID/releases/:version"},
+			{Method: []string{http.MethodGet}, Path: "/api/projects/:projectID/releases/:version/diff"},
+			{Method: []string{http.MethodGet}, Path: "/api/projects/:projectID/releases/:version/export"},
+			{Method: []string{http.MethodGet}, Path: "/api/projects/:projectID/releases/:version/export/:code"},
+			// 文档问答助手
+			{Method: []string{http.MethodPost}, Path: "/api/projects/:projectID/assistant/ask"},
+			// 导出变更日志
+			{Method: []string{http.MethodGet}, Path: "/api/projects/:projectID/changelog/export/:code"},
 			// 导出集合
 			{Method: []string{http.MethodGet}, Path: "/api/projects/:projectID/collections/:collectionID/export/:code"},
+			// 导出审计日志
+			{Method: []string{http.MethodGet}, Path: "/api/teams/:teamID/audit-logs/export/:code"},
+			{Method: []string{http.MethodGet}, Path: "/api/sysconfigs/audit-logs/export/:code"},
 			// mock
 			{Method: []string{"all"}, Path: "/api/mock/:projectID/*"},
 			// Get GitHub client id
@@ -91,26 +104,46 @@ func Init() error {
 	registerAccount(g)
 	registerTeam(g)
 	registerTeamMember(g)
+	registerCustomRole(g)
 	registerProjectGroup(g)
 	registerProject(g)
 	registerProjectShare(g)
+	registerProjectChangelog(g)
+	registerProjectRelease(g)
+	registerProjectProposal(g)
+	registerProjectTimeline(g)
+	registerProjectHistoryRetention(g)
 	registerProjectGlobalParameter(g)
+	registerProjectGlobalParameterHistory(g)
 	registerProjectServer(g)
+	registerProjectWebhook(g)
+	registerProjectJob(g)
+	registerProjectAIReview(g)
+	registerTeamSearch(g)
+	registerProjectAssistant(g)
+	registerProjectNotificationChannel(g)
 	registerProjectMember(g)
 	registerProjectDefinitionSchema(g)
 	registerProjectDefinitionSchemaHistory(g)
 	registerProjectDefinitionResponse(g)
+	registerProjectDefinitionResponseHistory(g)
 	registerCollection(g)
 	registerCollectionMock(g)
 	registerCollectionShare(g)
 	registerCollectionHistory(g)
 	registerTestCase(g)
 	registerIteration(g)
+	registerIterationProgress(g)
+	registerIterationReport(g)
+	registerIterationBranch(g)
+	registerIterationMergeRequest(g)
 	registerOauthSysconfig(g)
 	registerServiceSysconfig(g)
 	registerStorageSysconfig(g)
 	registerEmailSysconfig(g)
 	registerModelSysconfig(g)
+	registerAuditLog(g)
+	registerAIUsage(g)
 	registerJsonSchema(g)
 
 	slog.Info("init router", "bind", conf.App.AppServerBind)
@@ -0,0 +1,41 @@
+package access
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AIUsage 记录 AI 调用的归属并检查团队配额，需要在项目或团队权限检查之后使用
+func AIUsage(feature string) func(*gin.Context) {
+	return func(ctx *gin.Context) {
+		s := aiusage.Scope{Feature: feature}
+		if p := GetSelfProject(ctx); p != nil {
+			s.ProjectID = p.ID
+			s.TeamID = p.TeamID
+		} else if t := GetSelfTeam(ctx); t != nil {
+			s.TeamID = t.ID
+		}
+		if u := jwt.GetUser(ctx); u != nil {
+			s.UserID = u.ID
+		}
+
+		if err := aiusage.Check(ctx, s.TeamID); err != nil {
+			if errors.Is(err, aiusage.ErrQuotaExceeded) {
+				ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"message": i18n.NewTran("aiUsage.QuotaExceeded").Translate(ctx)})
+				return
+			}
+			slog.ErrorContext(ctx, "aiusage.Check", "err", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": i18n.NewTran("common.GenericError").Translate(ctx)})
+			return
+		}
+
+		ctx.Request = ctx.Request.WithContext(aiusage.WithScope(ctx.Request.Context(), s))
+	}
+}
@@ -0,0 +1,31 @@
+package access
+
+import (
+	"net/http"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/model/project"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireCapability 检查项目成员是否拥有指定能力，需要在BelongToProject之后使用
+// 只读成员没有任何能力，会被直接拒绝
+func RequireCapability(c project.Capability) func(*gin.Context) {
+	return func(ctx *gin.Context) {
+		pm := GetSelfProjectMember(ctx)
+		if pm == nil {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": i18n.NewTran("common.PermissionDenied").Translate(ctx)})
+			return
+		}
+		ok, err := pm.HasCapability(ctx, c)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": i18n.NewTran("common.GenericError").Translate(ctx)})
+			return
+		}
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": i18n.NewTran("common.PermissionDenied").Translate(ctx)})
+			return
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package access
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/model/project"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireCapabilityDeniesReadMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.GET("/",
+		func(ctx *gin.Context) {
+			setSelfProjectMember(ctx, &project.ProjectMember{Permission: project.ProjectMemberRead})
+		},
+		RequireCapability(project.CapabilityExport),
+		func(ctx *gin.Context) { ctx.Status(http.StatusOK) },
+	)
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("read member = %d, want 403", w.Code)
+	}
+}
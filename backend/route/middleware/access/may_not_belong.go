@@ -4,6 +4,7 @@ import (
 	"crypto/md5"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/apicat/apicat/v2/backend/i18n"
 	"github.com/apicat/apicat/v2/backend/model/project"
@@ -71,7 +72,25 @@ func relationInProject(ctx *gin.Context) (p *project.Project, inProject bool) {
 }
 
 func AllowGuestByShareCode() func(*gin.Context) {
+	return allowGuestByShareCode(false)
+}
+
+// AllowGuestByProjectShareCode 只允许成员、公开项目和项目分享令牌访问
+// 用于包含项目全部内容的接口，文档分享令牌只能访问分享的文档
+func AllowGuestByProjectShareCode() func(*gin.Context) {
+	return allowGuestByShareCode(true)
+}
+
+func allowGuestByShareCode(projectOnly bool) func(*gin.Context) {
 	return func(ctx *gin.Context) {
+		if projectOnly && strings.HasPrefix(ctx.Query("shareCode"), "d") {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"message": i18n.NewTran("share.SharedKeyError").Translate(ctx),
+				"action":  "verify",
+			})
+			return
+		}
+
 		p, inProject := relationInProject(ctx)
 		if p == nil {
 			return
@@ -0,0 +1,40 @@
+package dump
+
+import (
+	"net/http"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	EventDelta  = "delta"
+	EventResult = "result"
+	EventError  = "error"
+)
+
+// StartEvents 开始 SSE 响应，需在发送任何事件前调用
+func StartEvents(ctx *gin.Context) {
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// 关闭 nginx 等反向代理的缓冲
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+}
+
+// Event 发送 SSE 事件，客户端断开后返回错误，调用方可据此中止生成
+func Event(ctx *gin.Context, name string, data any) error {
+	if err := ctx.Request.Context().Err(); err != nil {
+		return err
+	}
+	ctx.SSEvent(name, data)
+	ctx.Writer.Flush()
+	return nil
+}
+
+// EventErr 以 error 事件返回错误信息，错误信息按用户语言翻译
+func EventErr(ctx *gin.Context, err *ginrpc.Error) {
+	Event(ctx, EventError, gin.H{"message": transValidErr(ctx, err.Err).Error()})
+}
@@ -0,0 +1,35 @@
+package aiusage
+
+import (
+	"github.com/apicat/apicat/v2/backend/route/proto/aiusage/request"
+	"github.com/apicat/apicat/v2/backend/route/proto/aiusage/response"
+
+	"github.com/apicat/ginrpc"
+	"github.com/gin-gonic/gin"
+)
+
+type AIUsageApi interface {
+	// Report AI 用量统计
+	// @route GET /sysconfigs/ai-usage
+	Report(*gin.Context, *request.GetAIUsageReportOption) (*response.AIUsageReport, error)
+
+	// GetPricing 获取用于估算费用的模型价格
+	// @route GET /sysconfigs/ai-usage/pricing
+	GetPricing(*gin.Context, *ginrpc.Empty) (*response.AIPricing, error)
+
+	// UpdatePricing 更新模型价格
+	// @route PUT /sysconfigs/ai-usage/pricing
+	UpdatePricing(*gin.Context, *request.AIPricingOption) (*ginrpc.Empty, error)
+
+	// QuotaList 团队配额列表
+	// @route GET /sysconfigs/ai-quotas
+	QuotaList(*gin.Context, *ginrpc.Empty) (*response.AIQuotaList, error)
+
+	// UpdateQuota 设置团队配额，不指定团队时设置默认配额
+	// @route PUT /sysconfigs/ai-quotas
+	UpdateQuota(*gin.Context, *request.AIQuotaOption) (*ginrpc.Empty, error)
+
+	// DeleteQuota 删除团队配额，不指定团队时删除默认配额
+	// @route DELETE /sysconfigs/ai-quotas
+	DeleteQuota(*gin.Context, *request.DeleteAIQuotaOption) (*ginrpc.Empty, error)
+}
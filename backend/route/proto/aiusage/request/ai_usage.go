@@ -0,0 +1,26 @@
+package request
+
+type GetAIUsageReportOption struct {
+	GroupBy   string `query:"groupBy" json:"groupBy" binding:"omitempty,oneof=team project user feature day"`
+	TeamID    string `query:"teamID" json:"teamID" binding:"omitempty,len=24"`
+	ProjectID string `query:"projectID" json:"projectID" binding:"omitempty,len=24"`
+	UserID    uint   `query:"userID" json:"userID" binding:"omitempty,numeric,gte=0"`
+	StartTime int64  `query:"startTime" json:"startTime" binding:"omitempty,numeric,gte=0"`
+	EndTime   int64  `query:"endTime" json:"endTime" binding:"omitempty,numeric,gte=0"`
+}
+
+type AIPricingOption struct {
+	Currency        string  `json:"currency" binding:"required,lte=16"`
+	PromptPrice     float64 `json:"promptPrice" binding:"gte=0"`
+	CompletionPrice float64 `json:"completionPrice" binding:"gte=0"`
+}
+
+type AIQuotaOption struct {
+	TeamID        string `json:"teamID" binding:"omitempty,len=24"`
+	DailyTokens   int64  `json:"dailyTokens" binding:"gte=0"`
+	MonthlyTokens int64  `json:"monthlyTokens" binding:"gte=0"`
+}
+
+type DeleteAIQuotaOption struct {
+	TeamID string `query:"teamID" json:"teamID" binding:"omitempty,len=24"`
+}
@@ -0,0 +1,32 @@
+package response
+
+type AIUsageSummary struct {
+	Key              string  `json:"key"`
+	Calls            int64   `json:"calls"`
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	TotalTokens      int64   `json:"totalTokens"`
+	Cost             float64 `json:"cost"`
+}
+
+type AIUsageReport struct {
+	GroupBy  string            `json:"groupBy"`
+	Currency string            `json:"currency"`
+	Total    AIUsageSummary    `json:"total"`
+	Items    []*AIUsageSummary `json:"items"`
+}
+
+type AIPricing struct {
+	Currency        string  `json:"currency"`
+	PromptPrice     float64 `json:"promptPrice"`
+	CompletionPrice float64 `json:"completionPrice"`
+}
+
+type AIQuota struct {
+	TeamID        string `json:"teamID"`
+	DailyTokens   int64  `json:"dailyTokens"`
+	MonthlyTokens int64  `json:"monthlyTokens"`
+	UpdatedAt     int64  `json:"updatedAt"`
+}
+
+type AIQuotaList []*AIQuota
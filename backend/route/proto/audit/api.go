@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"github.com/apicat/apicat/v2/backend/route/proto/audit/request"
+	"github.com/apicat/apicat/v2/backend/route/proto/audit/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditLogApi interface {
+	// TeamList 团队审计日志列表
+	// @route GET /teams/{teamID}/audit-logs
+	TeamList(*gin.Context, *request.GetTeamAuditLogListOption) (*response.AuditLogList, error)
+
+	// TeamExportPath 获取团队审计日志导出地址
+	// @route GET /teams/{teamID}/audit-logs/export
+	TeamExportPath(*gin.Context, *request.GetTeamAuditLogExportPathOption) (*response.ExportAuditLog, error)
+
+	// SysList 系统配置审计日志列表
+	// @route GET /sysconfigs/audit-logs
+	SysList(*gin.Context, *request.GetSysAuditLogListOption) (*response.AuditLogList, error)
+
+	// SysExportPath 获取系统配置审计日志导出地址
+	// @route GET /sysconfigs/audit-logs/export
+	SysExportPath(*gin.Context, *request.AuditLogFilterOption) (*response.ExportAuditLog, error)
+}
@@ -0,0 +1,40 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type AuditLogFilterOption struct {
+	ProjectID  string `query:"projectID" json:"projectID" binding:"omitempty,len=24"`
+	Action     string `query:"action" json:"action" binding:"omitempty,oneof=create update delete move copy restore reset"`
+	TargetType string `query:"targetType" json:"targetType" binding:"omitempty,lte=64"`
+	TargetID   string `query:"targetID" json:"targetID" binding:"omitempty,lte=255"`
+	StartTime  int64  `query:"startTime" json:"startTime" binding:"omitempty,numeric,gte=0"`
+	EndTime    int64  `query:"endTime" json:"endTime" binding:"omitempty,numeric,gte=0"`
+	UserID     uint   `query:"userID" json:"userID" binding:"omitempty,numeric,gte=0"`
+}
+
+type TeamAuditLogFilterOption struct {
+	AuditLogFilterOption
+	MemberID uint `query:"memberID" json:"memberID" binding:"omitempty,numeric,gte=0"`
+}
+
+type GetTeamAuditLogListOption struct {
+	protobase.TeamIdOption
+	protobase.PaginationOption
+	TeamAuditLogFilterOption
+}
+
+type GetTeamAuditLogExportPathOption struct {
+	protobase.TeamIdOption
+	TeamAuditLogFilterOption
+}
+
+type GetSysAuditLogListOption struct {
+	protobase.PaginationOption
+	AuditLogFilterOption
+}
+
+type ExportAuditLogCodeOption struct {
+	Code string `uri:"code" binding:"required"`
+}
@@ -0,0 +1,38 @@
+package response
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type AuditLogOperator struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type AuditLogChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+type AuditLog struct {
+	protobase.IdCreateTimeInfo
+	ProjectID  string            `json:"projectID"`
+	Operator   AuditLogOperator  `json:"operator"`
+	Action     string            `json:"action"`
+	TargetType string            `json:"targetType"`
+	TargetID   string            `json:"targetID"`
+	TargetName string            `json:"targetName"`
+	Changes    []*AuditLogChange `json:"changes"`
+	IP         string            `json:"ip"`
+}
+
+type AuditLogList struct {
+	protobase.PaginationInfo
+	Items []*AuditLog `json:"items"`
+}
+
+type ExportAuditLog struct {
+	Path string `json:"path"`
+}
@@ -15,6 +15,23 @@ type IdCreateTimeInfo struct {
 	CreatedAt int64 `json:"createdAt"`
 }
 
+// LangOption 查看指定语言的译文，为空时使用当前用户的语言
+type LangOption struct {
+	Lang string `query:"lang" binding:"omitempty,oneof=zh-CN en-US"`
+}
+
+const (
+	WriteStatusUpdated           = "updated"
+	WriteStatusProposalSubmitted = "proposal_submitted"
+)
+
+// WriteResult 修改结果，需要审批的内容提交为修改提案而不直接修改
+type WriteResult struct {
+	Status     string `json:"status"`
+	ProposalID uint   `json:"proposalID,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
 type PaginationOption struct {
 	Page     int `query:"page"`
 	PageSize int `query:"pageSize"`
@@ -39,3 +56,18 @@ type DiffOption struct {
 	OriginalID uint `query:"originalID" json:"originalID" binding:"required,numeric,gt=0"`
 	TargetID   uint `query:"targetID" json:"targetID" binding:"omitempty,numeric,gte=0"`
 }
+
+type BreakingChange struct {
+	Level   string   `json:"level"`
+	Rule    string   `json:"rule"`
+	Pointer string   `json:"pointer"`
+	Params  []string `json:"params"`
+	Message string   `json:"message"`
+}
+
+type BreakingChangeReport struct {
+	Breaking            int               `json:"breaking"`
+	PotentiallyBreaking int               `json:"potentiallyBreaking"`
+	NonBreaking         int               `json:"nonBreaking"`
+	Changes             []*BreakingChange `json:"changes"`
+}
@@ -15,3 +15,8 @@ type ProjectVisibilityOption struct {
 type ProjectMemberPermission struct {
 	Permission project.Permission `json:"permission" binding:"required,oneof=manage write read"`
 }
+
+type ProjectMemberRoleOption struct {
+	// 自定义角色id，0表示不使用自定义角色，仅可分配给可写成员
+	RoleID uint `json:"roleID" binding:"omitempty,gte=0"`
+}
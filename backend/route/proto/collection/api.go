@@ -22,11 +22,11 @@ type CollectionApi interface {
 
 	// Get 集合详情
 	// @route GET /projects/{projectID}/collections/{collectionID}
-	Get(*gin.Context, *base.ProjectCollectionIDOption) (*response.Collection, error)
+	Get(*gin.Context, *request.GetCollectionOption) (*response.Collection, error)
 
 	// Update 编辑集合
 	// @route PUT /projects/{projectID}/collections/{collectionID}
-	Update(*gin.Context, *request.UpdateCollectionOption) (*ginrpc.Empty, error)
+	Update(*gin.Context, *request.UpdateCollectionOption) (*protobase.WriteResult, error)
 
 	// Delete 删除集合
 	// @route DELETE /projects/{projectID}/collections/{collectionID}
@@ -90,7 +90,7 @@ type CollectionHistoryApi interface {
 
 	// Restore 恢复集合历史
 	// @route PUT /projectss/{projectID}/collectionss/{collectionID}/histories/{historyID}/restore
-	Restore(*gin.Context, *request.CollectionHistoryIDOption) (*ginrpc.Empty, error)
+	Restore(*gin.Context, *request.CollectionHistoryIDOption) (*protobase.WriteResult, error)
 
 	// Diff 集合历史对比
 	// @route GET /projectss/{projectID}/collectionss/{collectionID}/histories/diff
@@ -104,9 +104,9 @@ type CollectionMockApi interface {
 }
 
 type TestCaseApi interface {
-	// Generate 生成测试用例
+	// Generate 创建测试用例生成任务
 	// @route POST /projects/{projectID}/collections/{collectionID}/testcases
-	Generate(*gin.Context, *request.GenerateTestCaseOption) (*ginrpc.Empty, error)
+	Generate(*gin.Context, *request.GenerateTestCaseOption) (*response.TestCaseGeneration, error)
 
 	// List 获取测试用例列表
 	// @route GET /projects/{projectID}/collections/{collectionID}/testcases
@@ -123,4 +123,16 @@ type TestCaseApi interface {
 	// Delete 删除测试用例
 	// @route DELETE /projects/{projectID}/collections/{collectionID}/testcases/{testCaseID}
 	Delete(*gin.Context, *request.DeleteTestCaseOption) (*ginrpc.Empty, error)
+
+	// UpdateSpec 更新测试用例的可执行步骤
+	// @route PUT /projects/{projectID}/collections/{collectionID}/testcases/{testCaseID}/spec
+	UpdateSpec(*gin.Context, *request.UpdateTestCaseSpecOption) (*response.TestCaseDetail, error)
+
+	// Run 在项目服务地址或内置 mock 服务上执行测试用例
+	// @route POST /projects/{projectID}/collections/{collectionID}/testcases/{testCaseID}/run
+	Run(*gin.Context, *request.RunTestCaseOption) (*response.TestCaseRun, error)
+
+	// RunAll 创建后台任务执行接口下所有可执行的测试用例，执行结果通过任务获取
+	// @route POST /projects/{projectID}/collections/{collectionID}/testcases/run
+	RunAll(*gin.Context, *request.RunTestCasesOption) (*response.TestCaseRunJob, error)
 }
@@ -64,9 +64,16 @@ type ExportCodeOption struct {
 	Code string `uri:"code" binding:"required,len=32"`
 }
 
+type GetCollectionOption struct {
+	base.ProjectCollectionIDOption
+	protobase.LangOption
+}
+
 type AIGenerateCollectionOption struct {
 	protobase.ProjectIdOption
 	base.CollectionParentIDOption
+	// Mode 为 code 时 Prompt 为 handler 代码片段
+	Mode   string `json:"mode" binding:"omitempty,oneof=prompt code"`
 	Prompt string `json:"prompt" binding:"required"`
 	IterationIDNotRequiredOption
 }
@@ -1,6 +1,7 @@
 package request
 
 import (
+	"github.com/apicat/apicat/v2/backend/module/testcase"
 	"github.com/apicat/apicat/v2/backend/route/proto/collection/base"
 )
 
@@ -25,3 +26,21 @@ type DeleteTestCaseOption struct {
 	base.ProjectCollectionIDOption
 	TestCaseID uint `uri:"testCaseID" json:"testCaseID" binding:"required,gt=0"`
 }
+
+type UpdateTestCaseSpecOption struct {
+	base.ProjectCollectionIDOption
+	TestCaseID uint           `uri:"testCaseID" json:"testCaseID" binding:"required,gt=0"`
+	Spec       *testcase.Case `json:"spec" binding:"required"`
+}
+
+type RunTestCaseOption struct {
+	base.ProjectCollectionIDOption
+	TestCaseID uint `uri:"testCaseID" json:"testCaseID" binding:"required,gt=0"`
+	// ServerID 执行的项目服务地址，为 0 时使用内置的 mock 服务
+	ServerID uint `json:"serverID" binding:"omitempty"`
+}
+
+type RunTestCasesOption struct {
+	base.ProjectCollectionIDOption
+	ServerID uint `json:"serverID" binding:"omitempty"`
+}
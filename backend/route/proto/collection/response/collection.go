@@ -14,6 +14,8 @@ type Collection struct {
 	base.CollectionTypeOption
 	base.CollectionParentIDOption
 	projectbase.OperatorID
+	// Lang 返回译文时为译文的语言
+	Lang string `json:"lang,omitempty"`
 }
 
 type CollectionTree []*CollectionNode
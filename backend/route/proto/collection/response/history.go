@@ -28,4 +28,6 @@ type CollectionHistoryItem struct {
 type DiffCollectionHistories struct {
 	Doc1 *CollectionHistory `json:"doc1"`
 	Doc2 *CollectionHistory `json:"doc2"`
+	// BreakingChanges doc1到doc2的兼容性检查报告
+	BreakingChanges *protobase.BreakingChangeReport `json:"breakingChanges"`
 }
@@ -1,21 +1,42 @@
 package response
 
 import (
+	"github.com/apicat/apicat/v2/backend/module/testcase"
 	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
 )
 
 type TestCase struct {
 	Title string `json:"title"`
+	// Executable 是否包含可执行的测试步骤
+	Executable bool `json:"executable"`
 	protobase.IdCreateTimeInfo
 }
 
 type TestCaseList struct {
-	Generating bool        `json:"generating"`
-	Records    []*TestCase `json:"records"`
+	Generating bool `json:"generating"`
+	// JobID 生成中的任务ID，可通过任务接口查询进度
+	JobID   uint        `json:"jobID,omitempty"`
+	Records []*TestCase `json:"records"`
+}
+
+type TestCaseGeneration struct {
+	JobID uint `json:"jobID"`
 }
 
 type TestCaseDetail struct {
-	ID      uint   `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	ID      uint           `json:"id"`
+	Title   string         `json:"title"`
+	Content string         `json:"content"`
+	Spec    *testcase.Case `json:"spec,omitempty"`
+}
+
+type TestCaseRun struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	*testcase.Result
+}
+
+// TestCaseRunJob 执行任务的结果为 testrun.Report
+type TestCaseRunJob struct {
+	JobID uint `json:"jobID"`
 }
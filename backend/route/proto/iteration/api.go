@@ -4,6 +4,7 @@ import (
 	"github.com/apicat/apicat/v2/backend/route/proto/iteration/base"
 	"github.com/apicat/apicat/v2/backend/route/proto/iteration/request"
 	"github.com/apicat/apicat/v2/backend/route/proto/iteration/response"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
 
 	"github.com/apicat/ginrpc"
 	"github.com/gin-gonic/gin"
@@ -30,3 +31,95 @@ type IterationApi interface {
 	// @route DELETE /iterations/{iterationID}
 	Delete(*gin.Context, *base.IterationIDOption) (*ginrpc.Empty, error)
 }
+
+type IterationProgressApi interface {
+	// ListApis 迭代中接口的进度列表，可按状态和负责人筛选
+	// @route GET /iterations/{iterationID}/apis
+	ListApis(*gin.Context, *request.GetIterationApiListOption) (*response.IterationApiList, error)
+
+	// UpdateApi 更新接口在迭代中的状态、负责人和备注
+	// @route PUT /iterations/{iterationID}/apis/{collectionID}
+	UpdateApi(*gin.Context, *request.UpdateIterationApiOption) (*response.IterationApi, error)
+
+	// Burndown 迭代进度汇总和燃尽图
+	// @route GET /iterations/{iterationID}/burndown
+	Burndown(*gin.Context, *base.IterationIDOption) (*response.Burndown, error)
+}
+
+type IterationReportApi interface {
+	// Get 迭代变更报告，对比迭代中的接口及其引用的模型在迭代创建时与当前的差异
+	// @route GET /iterations/{iterationID}/report
+	Get(*gin.Context, *base.IterationIDOption) (*projectresponse.ProjectDiff, error)
+
+	// GetExportPath 获取迭代变更报告导出path
+	// @route GET /iterations/{iterationID}/report/export
+	GetExportPath(*gin.Context, *request.GetIterationReportExportPathOption) (*projectresponse.ExportProject, error)
+}
+
+type IterationBranchApi interface {
+	// List 迭代分支中修改过的接口和模型
+	// @route GET /iterations/{iterationID}/branch
+	List(*gin.Context, *base.IterationIDOption) (*response.BranchItemList, error)
+
+	// GetCollection 获取分支中的接口，未修改时返回主线的内容
+	// @route GET /iterations/{iterationID}/branch/collections/{collectionID}
+	GetCollection(*gin.Context, *request.BranchCollectionIDOption) (*response.BranchItem, error)
+
+	// UpdateCollection 在分支中编辑接口，首次编辑时复制主线的内容
+	// @route PUT /iterations/{iterationID}/branch/collections/{collectionID}
+	UpdateCollection(*gin.Context, *request.UpdateBranchCollectionOption) (*response.BranchItem, error)
+
+	// DeleteCollection 在分支中删除接口
+	// @route DELETE /iterations/{iterationID}/branch/collections/{collectionID}
+	DeleteCollection(*gin.Context, *request.BranchCollectionIDOption) (*ginrpc.Empty, error)
+
+	// GetSchema 获取分支中的模型，未修改时返回主线的内容
+	// @route GET /iterations/{iterationID}/branch/schemas/{schemaID}
+	GetSchema(*gin.Context, *request.BranchSchemaIDOption) (*response.BranchItem, error)
+
+	// UpdateSchema 在分支中编辑模型，首次编辑时复制主线的内容
+	// @route PUT /iterations/{iterationID}/branch/schemas/{schemaID}
+	UpdateSchema(*gin.Context, *request.UpdateBranchSchemaOption) (*response.BranchItem, error)
+
+	// DeleteSchema 在分支中删除模型
+	// @route DELETE /iterations/{iterationID}/branch/schemas/{schemaID}
+	DeleteSchema(*gin.Context, *request.BranchSchemaIDOption) (*ginrpc.Empty, error)
+
+	// CreateItem 在分支中新建接口或模型
+	// @route POST /iterations/{iterationID}/branch/items
+	CreateItem(*gin.Context, *request.CreateBranchItemOption) (*response.BranchItem, error)
+
+	// GetItem 获取分支中的修改
+	// @route GET /iterations/{iterationID}/branch/items/{itemID}
+	GetItem(*gin.Context, *request.BranchItemIDOption) (*response.BranchItem, error)
+
+	// UpdateItem 编辑分支中的修改
+	// @route PUT /iterations/{iterationID}/branch/items/{itemID}
+	UpdateItem(*gin.Context, *request.UpdateBranchItemOption) (*response.BranchItem, error)
+
+	// DiscardItem 放弃分支中的修改
+	// @route DELETE /iterations/{iterationID}/branch/items/{itemID}
+	DiscardItem(*gin.Context, *request.BranchItemIDOption) (*ginrpc.Empty, error)
+}
+
+type IterationMergeRequestApi interface {
+	// Create 创建合并请求
+	// @route POST /iterations/{iterationID}/merge-requests
+	Create(*gin.Context, *request.CreateMergeRequestOption) (*response.MergeRequest, error)
+
+	// List 合并请求列表
+	// @route GET /iterations/{iterationID}/merge-requests
+	List(*gin.Context, *base.IterationIDOption) (*response.MergeRequestList, error)
+
+	// Get 合并请求详情
+	// @route GET /iterations/{iterationID}/merge-requests/{mergeRequestID}
+	Get(*gin.Context, *request.MergeRequestIDOption) (*response.MergeRequestDetail, error)
+
+	// Merge 将分支合并到主线，存在未解决的冲突时合并失败
+	// @route POST /iterations/{iterationID}/merge-requests/{mergeRequestID}/merge
+	Merge(*gin.Context, *request.MergeOption) (*response.MergeRequest, error)
+
+	// Close 关闭合并请求
+	// @route POST /iterations/{iterationID}/merge-requests/{mergeRequestID}/close
+	Close(*gin.Context, *request.MergeRequestIDOption) (*response.MergeRequest, error)
+}
@@ -8,3 +8,10 @@ type IterationData struct {
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description"`
 }
+
+// IterationScheduleData 迭代的计划日期和状态，日期为unix时间戳，0表示未设置
+type IterationScheduleData struct {
+	StartDate int64  `json:"startDate" binding:"omitempty,gte=0"`
+	DueDate   int64  `json:"dueDate" binding:"omitempty,gte=0"`
+	State     string `json:"state" binding:"omitempty,oneof=planning active completed"`
+}
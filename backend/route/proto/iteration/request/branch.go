@@ -0,0 +1,80 @@
+package request
+
+import (
+	collectionbase "github.com/apicat/apicat/v2/backend/route/proto/collection/base"
+	"github.com/apicat/apicat/v2/backend/route/proto/iteration/base"
+)
+
+type BranchCollectionIDOption struct {
+	base.IterationIDOption
+	collectionbase.CollectionIDOption
+}
+
+type UpdateBranchCollectionOption struct {
+	BranchCollectionIDOption
+	collectionbase.CollectionData
+}
+
+type BranchSchemaIDOption struct {
+	base.IterationIDOption
+	SchemaID uint `uri:"schemaID" json:"schemaID" query:"schemaID" binding:"required"`
+}
+
+type BranchSchemaData struct {
+	Name        string `json:"name" binding:"required,lte=255"`
+	Description string `json:"description" binding:"lte=255"`
+	Schema      string `json:"schema"`
+}
+
+type UpdateBranchSchemaOption struct {
+	BranchSchemaIDOption
+	BranchSchemaData
+}
+
+type BranchItemIDOption struct {
+	base.IterationIDOption
+	ItemID uint `uri:"itemID" json:"itemID" query:"itemID" binding:"required"`
+}
+
+// BranchItemData 分支中的内容，Title对于模型是名称，Description只用于模型
+type BranchItemData struct {
+	Title       string `json:"title" binding:"required,lte=255"`
+	Description string `json:"description" binding:"lte=255"`
+	Content     string `json:"content"`
+}
+
+type CreateBranchItemOption struct {
+	base.IterationIDOption
+	BranchItemData
+	TargetType string `json:"targetType" binding:"required,oneof=collection schema"`
+	Type       string `json:"type" binding:"required,oneof=doc http schema"`
+	ParentID   uint   `json:"parentID" binding:"gte=0"`
+}
+
+type UpdateBranchItemOption struct {
+	BranchItemIDOption
+	BranchItemData
+}
+
+type MergeRequestIDOption struct {
+	base.IterationIDOption
+	MergeRequestID uint `uri:"mergeRequestID" json:"mergeRequestID" query:"mergeRequestID" binding:"required"`
+}
+
+type CreateMergeRequestOption struct {
+	base.IterationIDOption
+	Title       string `json:"title" binding:"required,lte=255"`
+	Description string `json:"description" binding:"omitempty,lte=10000"`
+}
+
+// MergeResolution 冲突的解决方式，Field为*时表示一方删除而另一方修改的冲突
+type MergeResolution struct {
+	ItemID uint   `json:"itemID" binding:"required"`
+	Field  string `json:"field" binding:"required"`
+	Choice string `json:"choice" binding:"required,oneof=mainline branch"`
+}
+
+type MergeOption struct {
+	MergeRequestIDOption
+	Resolutions []*MergeResolution `json:"resolutions" binding:"omitempty,dive"`
+}
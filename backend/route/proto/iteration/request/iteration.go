@@ -10,6 +10,7 @@ type CreateIterationOption struct {
 	protobase.TeamIdOption
 	protobase.ProjectIdOption
 	base.IterationData
+	base.IterationScheduleData
 	collectionbase.CollectionIDsOption
 }
 
@@ -17,10 +18,26 @@ type GetIterationListOption struct {
 	protobase.TeamIdOption
 	protobase.PaginationOption
 	ProjectID string `uri:"projectID" json:"projectID" query:"projectID"`
+	State     string `json:"state" query:"state" binding:"omitempty,oneof=planning active completed"`
 }
 
 type UpdateIterationOption struct {
 	base.IterationIDOption
 	base.IterationData
+	base.IterationScheduleData
 	collectionbase.CollectionIDsOption
 }
+
+type GetIterationApiListOption struct {
+	base.IterationIDOption
+	Status     string `json:"status" query:"status" binding:"omitempty,oneof=todo designing developing testing done"`
+	AssigneeID uint   `json:"assigneeID" query:"assigneeID" binding:"omitempty,gte=0"`
+}
+
+type UpdateIterationApiOption struct {
+	base.IterationIDOption
+	CollectionID uint   `uri:"collectionID" json:"collectionID" query:"collectionID" binding:"required,gt=0"`
+	Status       string `json:"status" binding:"required,oneof=todo designing developing testing done"`
+	AssigneeID   uint   `json:"assigneeID" binding:"omitempty,gte=0"`
+	Note         string `json:"note" binding:"omitempty,lte=255"`
+}
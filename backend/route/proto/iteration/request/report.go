@@ -0,0 +1,16 @@
+package request
+
+import (
+	"github.com/apicat/apicat/v2/backend/route/proto/iteration/base"
+)
+
+type GetIterationReportExportPathOption struct {
+	base.IterationIDOption
+	Type     string `query:"type" json:"type" binding:"required,oneof=HTML md"`
+	Download bool   `query:"download" json:"download"`
+}
+
+type IterationReportExportCodeOption struct {
+	base.IterationIDOption
+	Code string `uri:"code" binding:"required,len=32"`
+}
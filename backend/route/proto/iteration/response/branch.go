@@ -0,0 +1,53 @@
+package response
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+)
+
+// BranchItem 分支中的接口或模型，ID为0表示分支中未修改，内容与主线一致
+// Status为相对主线的状态：added、removed、changed，未修改时为空
+type BranchItem struct {
+	ID          uint   `json:"id"`
+	TargetType  string `json:"targetType"`
+	TargetID    uint   `json:"targetID"`
+	ParentID    uint   `json:"parentID"`
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Content     string `json:"content,omitempty"`
+	Status      string `json:"status"`
+	UpdatedAt   int64  `json:"updatedAt"`
+}
+
+type BranchItemList []*BranchItem
+
+type MergeRequest struct {
+	protobase.IdCreateTimeInfo
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	CreatedBy   string `json:"createdBy"`
+	ClosedBy    string `json:"closedBy"`
+	ClosedAt    int64  `json:"closedAt"`
+}
+
+type MergeRequestList []*MergeRequest
+
+// MergeConflict 合并冲突，值为nil表示该方没有这个字段或已删除
+type MergeConflict struct {
+	ItemID     uint    `json:"itemID"`
+	TargetType string  `json:"targetType"`
+	Title      string  `json:"title"`
+	Field      string  `json:"field"`
+	Base       *string `json:"base"`
+	Mainline   *string `json:"mainline"`
+	Branch     *string `json:"branch"`
+}
+
+// MergeRequestDetail 合并请求详情，打开中的请求包含分支与主线的对比和冲突
+type MergeRequestDetail struct {
+	MergeRequest
+	Diff      *projectresponse.ProjectDiff `json:"diff"`
+	Conflicts []*MergeConflict             `json:"conflicts"`
+}
@@ -9,6 +9,7 @@ import (
 type Iteration struct {
 	protobase.IdCreateTimeInfo
 	base.IterationData
+	base.IterationScheduleData
 	Project *IterationProject `json:"project"`
 }
 
@@ -21,8 +22,11 @@ type IterationProject struct {
 type IterationListItem struct {
 	protobase.IdCreateTimeInfo
 	base.IterationData
-	ApisCount int64                 `json:"apisCount"`
-	Project   *IterationListProject `json:"project"`
+	base.IterationScheduleData
+	ApisCount     int64                 `json:"apisCount"`
+	DoneApisCount int64                 `json:"doneApisCount"`
+	Overdue       bool                  `json:"overdue"`
+	Project       *IterationListProject `json:"project"`
 }
 
 type IterationListProject struct {
@@ -35,3 +39,36 @@ type IterationList struct {
 	protobase.PaginationInfo
 	Items []*IterationListItem `json:"items"`
 }
+
+// IterationApi 迭代中接口的进度，AssigneeID为0表示未指派
+type IterationApi struct {
+	CollectionID uint   `json:"collectionID"`
+	Title        string `json:"title"`
+	Type         string `json:"type"`
+	Status       string `json:"status"`
+	AssigneeID   uint   `json:"assigneeID"`
+	Assignee     string `json:"assignee"`
+	Note         string `json:"note"`
+	DoneAt       int64  `json:"doneAt"`
+	UpdatedAt    int64  `json:"updatedAt"`
+}
+
+type IterationApiList []*IterationApi
+
+// Burndown 迭代进度汇总和燃尽图
+type Burndown struct {
+	Total     int            `json:"total"`
+	Done      int            `json:"done"`
+	Statuses  map[string]int `json:"statuses"`
+	Overdue   bool           `json:"overdue"`
+	StartDate int64          `json:"startDate"`
+	DueDate   int64          `json:"dueDate"`
+	Days      []*BurndownDay `json:"days"`
+}
+
+// BurndownDay 燃尽图中的一天，Remaining为当天结束时剩余的接口数，尚未到来的日期为null
+type BurndownDay struct {
+	Date      string  `json:"date"`
+	Remaining *int    `json:"remaining"`
+	Ideal     float64 `json:"ideal"`
+}
@@ -56,6 +56,14 @@ type ProjectApi interface {
 	// GetExportPath 获取导出path
 	// @route GET /projects/{projectID}/export
 	GetExportPath(*gin.Context, *request.GetExportPathOption) (*response.ExportProject, error)
+
+	// Trashes 回收站中的项目
+	// @route GET /teams/{teamID}/projects/trashes
+	Trashes(*gin.Context, *protobase.TeamIdOption) (*response.ProjectTrashList, error)
+
+	// Restore 从回收站恢复项目
+	// @route PUT /teams/{teamID}/projects/restore
+	Restore(*gin.Context, *request.RestoreProjectsOption) (*response.RestoreNum, error)
 }
 
 type ProjectMemberApi interface {
@@ -130,6 +138,14 @@ type DefinitionResponseApi interface {
 	// Copy 复制定义响应
 	// @route POST /projects/{projectID}/definition/responses/{responseID}/copy
 	Copy(*gin.Context, *request.GetDefinitionResponseOption) (*response.DefinitionResponse, error)
+
+	// Trashes 回收站中的定义响应
+	// @route GET /projects/{projectID}/definition/responses/trashes
+	Trashes(*gin.Context, *protobase.ProjectIdOption) (*response.DefinitionTrashList, error)
+
+	// Restore 从回收站恢复定义响应
+	// @route PUT /projects/{projectID}/definition/responses/restore
+	Restore(*gin.Context, *request.RestoreDefinitionResponsesOption) (*response.RestoreNum, error)
 }
 
 type DefinitionSchemaApi interface {
@@ -143,11 +159,11 @@ type DefinitionSchemaApi interface {
 
 	// Get 定义模型详情
 	// @route GET /projects/{projectID}/definition/schemas/{schemaID}
-	Get(*gin.Context, *request.GetDefinitionSchemaOption) (*response.DefinitionSchema, error)
+	Get(*gin.Context, *request.GetDefinitionSchemaDetailOption) (*response.DefinitionSchema, error)
 
 	// Update 编辑定义模型
 	// @route PUT /projects/{projectID}/definition/schemas/{schemaID}
-	Update(*gin.Context, *request.UpdateDefinitionSchemaOption) (*ginrpc.Empty, error)
+	Update(*gin.Context, *request.UpdateDefinitionSchemaOption) (*protobase.WriteResult, error)
 
 	// Delete 删除定义模型
 	// @route DELETE /projects/{projectID}/definition/schemas/{schemaID}
@@ -164,6 +180,14 @@ type DefinitionSchemaApi interface {
 	// AIGenerate AI 生成模型
 	// @route POST /projects/{projectID}/definition/ai/schemas
 	AIGenerate(*gin.Context, *request.AIGenerateSchemaOption) (*response.DefinitionSchema, error)
+
+	// Trashes 回收站中的定义模型
+	// @route GET /projects/{projectID}/definition/schemas/trashes
+	Trashes(*gin.Context, *protobase.ProjectIdOption) (*response.DefinitionTrashList, error)
+
+	// Restore 从回收站恢复定义模型
+	// @route PUT /projects/{projectID}/definition/schemas/restore
+	Restore(*gin.Context, *request.RestoreDefinitionSchemasOption) (*response.RestoreNum, error)
 }
 
 // GlobalParameterApi 全局参数相关
@@ -244,9 +268,229 @@ type DefinitionSchemaHistoryAPi interface {
 
 	// Restore 恢复定义模型历史
 	// @route PUT /projects/{projectID}/definition/schemas/{schemaID}/histories/{historyID}/restore
-	Restore(*gin.Context, *request.DefinitionSchemaHistoryIDOption) (*ginrpc.Empty, error)
+	Restore(*gin.Context, *request.DefinitionSchemaHistoryIDOption) (*protobase.WriteResult, error)
 
 	// Diff 定义模型历史对比
 	// @route GET /projects/{projectID}/definition/schemas/{schemaID}/histories/diff
 	Diff(*gin.Context, *request.DiffDefinitionSchemaHistoriesOption) (*response.DiffDefinitionSchemaHistories, error)
 }
+
+type DefinitionResponseHistoryApi interface {
+	// List 获取公共响应历史列表
+	// @route GET /projects/{projectID}/definition/responses/{responseID}/histories
+	List(*gin.Context, *request.GetDefinitionResponseHistoryListOption) (*response.DefinitionResponseHistoryList, error)
+
+	// Get 获取公共响应历史详情
+	// @route GET /projects/{projectID}/definition/responses/{responseID}/histories/{historyID}
+	Get(*gin.Context, *request.DefinitionResponseHistoryIDOption) (*response.DefinitionResponseHistory, error)
+
+	// Restore 恢复公共响应历史
+	// @route PUT /projects/{projectID}/definition/responses/{responseID}/histories/{historyID}/restore
+	Restore(*gin.Context, *request.DefinitionResponseHistoryIDOption) (*ginrpc.Empty, error)
+
+	// Diff 公共响应历史对比
+	// @route GET /projects/{projectID}/definition/responses/{responseID}/histories/diff
+	Diff(*gin.Context, *request.DiffDefinitionResponseHistoriesOption) (*response.DiffDefinitionResponseHistories, error)
+}
+
+type GlobalParameterHistoryApi interface {
+	// List 获取全局参数历史列表
+	// @route GET /projects/{projectID}/global/parameters/{parameterID}/histories
+	List(*gin.Context, *request.GetGlobalParameterHistoryListOption) (*response.GlobalParameterHistoryList, error)
+
+	// Get 获取全局参数历史详情
+	// @route GET /projects/{projectID}/global/parameters/{parameterID}/histories/{historyID}
+	Get(*gin.Context, *request.GlobalParameterHistoryIDOption) (*response.GlobalParameterHistory, error)
+
+	// Restore 恢复全局参数历史
+	// @route PUT /projects/{projectID}/global/parameters/{parameterID}/histories/{historyID}/restore
+	Restore(*gin.Context, *request.GlobalParameterHistoryIDOption) (*ginrpc.Empty, error)
+
+	// Diff 全局参数历史对比
+	// @route GET /projects/{projectID}/global/parameters/{parameterID}/histories/diff
+	Diff(*gin.Context, *request.DiffGlobalParameterHistoriesOption) (*response.DiffGlobalParameterHistories, error)
+}
+
+type ProjectTimelineApi interface {
+	// List 获取项目最近的变更，合并接口、模型、响应和全局参数的历史记录
+	// @route GET /projects/{projectID}/timeline
+	List(*gin.Context, *request.GetProjectTimelineOption) (*response.ProjectTimeline, error)
+}
+
+type ProjectHistoryRetentionApi interface {
+	// Get 获取项目历史记录保留策略
+	// @route GET /projects/{projectID}/history-retention
+	Get(*gin.Context, *protobase.ProjectIdOption) (*response.HistoryRetention, error)
+
+	// Update 修改项目历史记录保留策略
+	// @route PUT /projects/{projectID}/history-retention
+	Update(*gin.Context, *request.UpdateHistoryRetentionOption) (*response.HistoryRetention, error)
+}
+
+type ProjectWebhookApi interface {
+	// Create 创建项目webhook
+	// @route POST /projects/{projectID}/webhooks
+	Create(*gin.Context, *request.CreateProjectWebhookOption) (*response.ProjectWebhook, error)
+
+	// List 获取项目webhook列表
+	// @route GET /projects/{projectID}/webhooks
+	List(*gin.Context, *protobase.ProjectIdOption) (*response.ProjectWebhookList, error)
+
+	// Update 修改项目webhook
+	// @route PUT /projects/{projectID}/webhooks/{webhookID}
+	Update(*gin.Context, *request.UpdateProjectWebhookOption) (*ginrpc.Empty, error)
+
+	// Delete 删除项目webhook
+	// @route DELETE /projects/{projectID}/webhooks/{webhookID}
+	Delete(*gin.Context, *request.GetProjectWebhookOption) (*ginrpc.Empty, error)
+
+	// Test 发送一条测试事件
+	// @route POST /projects/{projectID}/webhooks/{webhookID}/test
+	Test(*gin.Context, *request.GetProjectWebhookOption) (*response.WebhookDelivery, error)
+
+	// DeliveryList 获取投递记录
+	// @route GET /projects/{projectID}/webhooks/{webhookID}/deliveries
+	DeliveryList(*gin.Context, *request.GetWebhookDeliveryListOption) (*response.WebhookDeliveryList, error)
+
+	// GetDelivery 获取投递详情
+	// @route GET /projects/{projectID}/webhooks/{webhookID}/deliveries/{deliveryID}
+	GetDelivery(*gin.Context, *request.GetWebhookDeliveryOption) (*response.WebhookDeliveryDetail, error)
+
+	// Redeliver 重新投递
+	// @route POST /projects/{projectID}/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver
+	Redeliver(*gin.Context, *request.GetWebhookDeliveryOption) (*ginrpc.Empty, error)
+}
+
+type ProjectJobApi interface {
+	// List 获取项目最近的后台任务
+	// @route GET /projects/{projectID}/jobs
+	List(*gin.Context, *request.GetProjectJobListOption) (*response.ProjectJobList, error)
+
+	// Get 获取后台任务状态
+	// @route GET /projects/{projectID}/jobs/{jobID}
+	Get(*gin.Context, *request.GetProjectJobOption) (*response.ProjectJobDetail, error)
+
+	// Cancel 取消后台任务
+	// @route PUT /projects/{projectID}/jobs/{jobID}/cancel
+	Cancel(*gin.Context, *request.GetProjectJobOption) (*ginrpc.Empty, error)
+
+	// Export 创建项目导出任务，完成后通过 /projects/{projectID}/jobs/{jobID}/result 下载
+	// @route POST /projects/{projectID}/jobs/export
+	Export(*gin.Context, *request.CreateProjectExportJobOption) (*response.ProjectJob, error)
+
+	// Translate 创建项目翻译任务，将接口、文档和模型翻译为目标语言
+	// @route POST /projects/{projectID}/jobs/translate
+	Translate(*gin.Context, *request.CreateProjectTranslateJobOption) (*response.ProjectJob, error)
+}
+
+type ProjectAIReviewApi interface {
+	// Review AI 按设计规范评审接口和模型，不指定接口时评审整个项目
+	// @route POST /projects/{projectID}/ai/reviews
+	Review(*gin.Context, *request.AIReviewOption) (*response.AIReview, error)
+
+	// Apply 将评审建议的修改应用到接口或模型，需要审批的目标会提交为修改提案
+	// @route PUT /projects/{projectID}/ai/reviews/apply
+	Apply(*gin.Context, *request.ApplyAIReviewPatchOption) (*protobase.WriteResult, error)
+}
+
+type ProjectAssistantApi interface {
+	// Ask 根据项目文档回答问题，访客可通过分享码使用
+	// @route POST /projects/{projectID}/assistant/ask
+	Ask(*gin.Context, *request.AskAssistantOption) (*response.AssistantAnswer, error)
+}
+
+type ProjectNotificationChannelApi interface {
+	// Create 创建项目聊天通知渠道
+	// @route POST /projects/{projectID}/notification-channels
+	Create(*gin.Context, *request.CreateNotificationChannelOption) (*response.NotificationChannel, error)
+
+	// List 获取项目聊天通知渠道列表
+	// @route GET /projects/{projectID}/notification-channels
+	List(*gin.Context, *protobase.ProjectIdOption) (*response.NotificationChannelList, error)
+
+	// Update 修改项目聊天通知渠道
+	// @route PUT /projects/{projectID}/notification-channels/{channelID}
+	Update(*gin.Context, *request.UpdateNotificationChannelOption) (*ginrpc.Empty, error)
+
+	// Delete 删除项目聊天通知渠道
+	// @route DELETE /projects/{projectID}/notification-channels/{channelID}
+	Delete(*gin.Context, *request.GetNotificationChannelOption) (*ginrpc.Empty, error)
+
+	// Test 发送一条测试消息
+	// @route POST /projects/{projectID}/notification-channels/{channelID}/test
+	Test(*gin.Context, *request.GetNotificationChannelOption) (*ginrpc.Empty, error)
+}
+
+type ProjectChangelogApi interface {
+	// Diff 项目对比
+	// @route GET /projects/{projectID}/diff
+	Diff(*gin.Context, *request.ProjectDiffOption) (*response.ProjectDiff, error)
+
+	// GetExportPath 获取变更日志导出path
+	// @route GET /projects/{projectID}/changelog/export
+	GetExportPath(*gin.Context, *request.GetChangelogExportPathOption) (*response.ExportProject, error)
+}
+
+type ProjectReleaseApi interface {
+	// Create 发布版本
+	// @route POST /projects/{projectID}/releases
+	Create(*gin.Context, *request.CreateReleaseOption) (*response.ReleaseItem, error)
+
+	// List 获取发布版本列表
+	// @route GET /projects/{projectID}/releases
+	List(*gin.Context, *protobase.ProjectIdOption) (*response.ReleaseList, error)
+
+	// Get 获取发布版本详情
+	// @route GET /projects/{projectID}/releases/{version}
+	Get(*gin.Context, *request.ReleaseVersionOption) (*response.Release, error)
+
+	// Delete 删除发布版本
+	// @route DELETE /projects/{projectID}/releases/{version}
+	Delete(*gin.Context, *request.ReleaseVersionOption) (*ginrpc.Empty, error)
+
+	// Diff 与另一个发布版本对比
+	// @route GET /projects/{projectID}/releases/{version}/diff
+	Diff(*gin.Context, *request.DiffReleaseOption) (*response.ProjectDiff, error)
+
+	// GetExportPath 获取发布版本导出path
+	// @route GET /projects/{projectID}/releases/{version}/export
+	GetExportPath(*gin.Context, *request.GetReleaseExportPathOption) (*response.ExportProject, error)
+}
+
+type ProjectProposalApi interface {
+	// ListProtections 获取需要审批才能修改的接口和模型
+	// @route GET /projects/{projectID}/protections
+	ListProtections(*gin.Context, *protobase.ProjectIdOption) (*response.ProtectionList, error)
+
+	// CreateProtection 设置接口或模型需要审批
+	// @route POST /projects/{projectID}/protections
+	CreateProtection(*gin.Context, *request.CreateProtectionOption) (*response.Protection, error)
+
+	// DeleteProtection 取消审批
+	// @route DELETE /projects/{projectID}/protections/{protectionID}
+	DeleteProtection(*gin.Context, *request.ProtectionIDOption) (*ginrpc.Empty, error)
+
+	// List 获取修改提案列表
+	// @route GET /projects/{projectID}/proposals
+	List(*gin.Context, *request.GetProposalListOption) (*response.ProposalList, error)
+
+	// Get 获取修改提案详情
+	// @route GET /projects/{projectID}/proposals/{proposalID}
+	Get(*gin.Context, *request.ProposalIDOption) (*response.ProposalDetail, error)
+
+	// Comment 评论修改提案
+	// @route POST /projects/{projectID}/proposals/{proposalID}/comments
+	Comment(*gin.Context, *request.CommentProposalOption) (*response.ProposalComment, error)
+
+	// Approve 批准修改提案并写入
+	// @route POST /projects/{projectID}/proposals/{proposalID}/approve
+	Approve(*gin.Context, *request.ProposalIDOption) (*ginrpc.Empty, error)
+
+	// Reject 驳回修改提案
+	// @route POST /projects/{projectID}/proposals/{proposalID}/reject
+	Reject(*gin.Context, *request.RejectProposalOption) (*ginrpc.Empty, error)
+
+	// Withdraw 撤回修改提案
+	// @route POST /projects/{projectID}/proposals/{proposalID}/withdraw
+	Withdraw(*gin.Context, *request.ProposalIDOption) (*ginrpc.Empty, error)
+}
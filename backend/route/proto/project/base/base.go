@@ -72,3 +72,22 @@ type ProjectServerDataOption struct {
 	URL         string `json:"url" binding:"required,startswith=http://|startswith=https://"`
 	Description string `json:"description"`
 }
+
+type ProjectWebhookDataOption struct {
+	Name          string   `json:"name" binding:"required,lte=255"`
+	URL           string   `json:"url" binding:"required,url,lte=1024"`
+	Secret        string   `json:"secret" binding:"lte=255"`
+	Events        []string `json:"events" binding:"omitempty,dive,required"`
+	Active        bool     `json:"active"`
+	MaxRetries    int      `json:"maxRetries" binding:"gte=0,lte=10"`
+	RetryInterval int      `json:"retryInterval" binding:"gte=0,lte=3600"`
+}
+
+type NotificationChannelDataOption struct {
+	Name   string   `json:"name" binding:"required,lte=255"`
+	Type   string   `json:"type" binding:"required,oneof=slack dingtalk feishu wecom webhook"`
+	URL    string   `json:"url" binding:"required,url,lte=1024"`
+	Secret string   `json:"secret" binding:"lte=255"`
+	Events []string `json:"events" binding:"omitempty,dive,required"`
+	Active bool     `json:"active"`
+}
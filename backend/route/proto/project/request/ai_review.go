@@ -0,0 +1,19 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	"github.com/apicat/apicat/v2/backend/utils/jsonpatch"
+)
+
+type AIReviewOption struct {
+	protobase.ProjectIdOption
+	CollectionID uint   `json:"collectionID" binding:"omitempty,gt=0"`
+	Rules        string `json:"rules" binding:"omitempty,lte=2000"`
+}
+
+type ApplyAIReviewPatchOption struct {
+	protobase.ProjectIdOption
+	TargetType string                `json:"targetType" binding:"required,oneof=collection schema"`
+	TargetID   uint                  `json:"targetID" binding:"required,gt=0"`
+	Patch      []jsonpatch.Operation `json:"patch" binding:"required,min=1"`
+}
@@ -0,0 +1,17 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type AssistantMessage struct {
+	Role    string `json:"role" binding:"required,oneof=user assistant"`
+	Content string `json:"content" binding:"required,lte=4000"`
+}
+
+type AskAssistantOption struct {
+	protobase.ProjectIdOption
+	Question string `json:"question" binding:"required,lte=1000"`
+	// History 之前的对话，按时间顺序
+	History []AssistantMessage `json:"history" binding:"omitempty,lte=20,dive"`
+}
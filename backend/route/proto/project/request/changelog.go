@@ -0,0 +1,19 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+// ProjectDiffOption 项目对比，与另一个项目或当前项目的某个时间点对比
+type ProjectDiffOption struct {
+	protobase.ProjectIdOption
+	BaseProjectID string `query:"baseProjectID" json:"baseProjectID" binding:"omitempty,len=24"`
+	StartTime     int64  `query:"startTime" json:"startTime" binding:"omitempty,numeric,gte=0"`
+	EndTime       int64  `query:"endTime" json:"endTime" binding:"omitempty,numeric,gte=0"`
+}
+
+type GetChangelogExportPathOption struct {
+	ProjectDiffOption
+	Type     string `query:"type" json:"type" binding:"required,oneof=HTML md"`
+	Download bool   `query:"download" json:"download"`
+}
@@ -0,0 +1,20 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type DefinitionResponseHistoryIDOption struct {
+	GetDefinitionResponseOption
+	HistoryID uint `uri:"historyID" json:"historyID" query:"historyID" binding:"required,numeric,gt=0"`
+}
+
+type GetDefinitionResponseHistoryListOption struct {
+	GetDefinitionResponseOption
+	protobase.TimeIntervalOption
+}
+
+type DiffDefinitionResponseHistoriesOption struct {
+	GetDefinitionResponseOption
+	protobase.DiffOption
+}
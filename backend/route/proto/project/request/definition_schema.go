@@ -10,6 +10,11 @@ type GetDefinitionSchemaOption struct {
 	SchemaID uint `uri:"schemaID" json:"schemaID" query:"schemaID" binding:"required,numeric,gt=0"`
 }
 
+type GetDefinitionSchemaDetailOption struct {
+	GetDefinitionSchemaOption
+	protobase.LangOption
+}
+
 type CreateDefinitionSchemaOption struct {
 	protobase.ProjectIdOption
 	projectbase.DefinitionSchemaDataOption
@@ -34,5 +39,7 @@ type SortDefinitionSchemaOption struct {
 type AIGenerateSchemaOption struct {
 	protobase.ProjectIdOption
 	projectbase.DefinitionSchemaParentIDOption
+	// Mode 为空或 prompt 时 Prompt 为需求描述，其他模式下 Prompt 为对应格式的源码
+	Mode   string `json:"mode" binding:"omitempty,oneof=prompt json sql go typescript"`
 	Prompt string `json:"prompt" binding:"required"`
 }
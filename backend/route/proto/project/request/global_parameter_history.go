@@ -0,0 +1,20 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type GlobalParameterHistoryIDOption struct {
+	GetGlobalParameterOption
+	HistoryID uint `uri:"historyID" json:"historyID" query:"historyID" binding:"required,numeric,gt=0"`
+}
+
+type GetGlobalParameterHistoryListOption struct {
+	GetGlobalParameterOption
+	protobase.TimeIntervalOption
+}
+
+type DiffGlobalParameterHistoriesOption struct {
+	GetGlobalParameterOption
+	protobase.DiffOption
+}
@@ -0,0 +1,14 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type UpdateHistoryRetentionOption struct {
+	protobase.ProjectIdOption
+	// 各项为0表示不限制
+	KeepCount      int  `json:"keepCount" binding:"omitempty,gte=0,lte=10000"`
+	KeepDays       int  `json:"keepDays" binding:"omitempty,gte=0,lte=3650"`
+	DailyAfterDays int  `json:"dailyAfterDays" binding:"omitempty,gte=0,lte=3650"`
+	DeltaStorage   bool `json:"deltaStorage"`
+}
@@ -0,0 +1,26 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type GetProjectJobOption struct {
+	protobase.ProjectIdOption
+	JobID uint `uri:"jobID" json:"jobID" query:"jobID" binding:"required,gt=0"`
+}
+
+type GetProjectJobListOption struct {
+	protobase.ProjectIdOption
+	Type   string `query:"type" binding:"omitempty,oneof=testcase_generate project_export project_translate testcase_run"`
+	Status string `query:"status" binding:"omitempty,oneof=pending running succeeded failed canceled"`
+}
+
+type CreateProjectExportJobOption struct {
+	protobase.ProjectIdOption
+	Type string `json:"type" binding:"required,oneof=apicat swagger openapi3.0.0 openapi3.0.1 openapi3.0.2 openapi3.1.0 HTML md"`
+}
+
+type CreateProjectTranslateJobOption struct {
+	protobase.ProjectIdOption
+	Lang string `json:"lang" binding:"required,oneof=zh-CN en-US"`
+}
@@ -0,0 +1,21 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	projectbase "github.com/apicat/apicat/v2/backend/route/proto/project/base"
+)
+
+type GetNotificationChannelOption struct {
+	protobase.ProjectIdOption
+	ChannelID uint `uri:"channelID" json:"channelID" query:"channelID" binding:"required,gt=0"`
+}
+
+type CreateNotificationChannelOption struct {
+	protobase.ProjectIdOption
+	projectbase.NotificationChannelDataOption
+}
+
+type UpdateNotificationChannelOption struct {
+	GetNotificationChannelOption
+	projectbase.NotificationChannelDataOption
+}
@@ -12,10 +12,12 @@ type GetProjectMemberListOption struct {
 type CreateProjectMemberOption struct {
 	protobase.ProjectIdOption
 	protobase.ProjectMemberPermission
+	protobase.ProjectMemberRoleOption
 	MemberIDs []uint `json:"memberIDs" binding:"omitempty,dive,gt=0"`
 }
 
 type UpdateProjectMemberOption struct {
 	ProjectMemberIDOption
 	protobase.ProjectMemberPermission
+	protobase.ProjectMemberRoleOption
 }
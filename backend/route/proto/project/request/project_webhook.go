@@ -0,0 +1,32 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	projectbase "github.com/apicat/apicat/v2/backend/route/proto/project/base"
+)
+
+type GetProjectWebhookOption struct {
+	protobase.ProjectIdOption
+	WebhookID uint `uri:"webhookID" json:"webhookID" query:"webhookID" binding:"required,gt=0"`
+}
+
+type CreateProjectWebhookOption struct {
+	protobase.ProjectIdOption
+	projectbase.ProjectWebhookDataOption
+}
+
+type UpdateProjectWebhookOption struct {
+	GetProjectWebhookOption
+	projectbase.ProjectWebhookDataOption
+}
+
+type GetWebhookDeliveryListOption struct {
+	GetProjectWebhookOption
+	protobase.PaginationOption
+	Status string `query:"status" binding:"omitempty,oneof=pending success failed"`
+}
+
+type GetWebhookDeliveryOption struct {
+	GetProjectWebhookOption
+	DeliveryID uint `uri:"deliveryID" json:"deliveryID" query:"deliveryID" binding:"required,gt=0"`
+}
@@ -0,0 +1,36 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type ProtectionIDOption struct {
+	protobase.ProjectIdOption
+	ProtectionID uint `uri:"protectionID" json:"protectionID" query:"protectionID" binding:"required,gt=0"`
+}
+
+type CreateProtectionOption struct {
+	protobase.ProjectIdOption
+	TargetType string `json:"targetType" binding:"required,oneof=collection schema"`
+	TargetID   uint   `json:"targetID" binding:"required,gt=0"`
+}
+
+type ProposalIDOption struct {
+	protobase.ProjectIdOption
+	ProposalID uint `uri:"proposalID" json:"proposalID" query:"proposalID" binding:"required,gt=0"`
+}
+
+type GetProposalListOption struct {
+	protobase.ProjectIdOption
+	Status string `query:"status" json:"status" binding:"omitempty,oneof=pending approved rejected withdrawn"`
+}
+
+type CommentProposalOption struct {
+	ProposalIDOption
+	Content string `json:"content" binding:"required,lte=10000"`
+}
+
+type RejectProposalOption struct {
+	ProposalIDOption
+	Reason string `json:"reason" binding:"omitempty,lte=10000"`
+}
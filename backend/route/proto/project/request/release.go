@@ -0,0 +1,32 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type ReleaseVersionOption struct {
+	protobase.ProjectIdOption
+	Version string `uri:"version" json:"version" query:"version" binding:"required,lte=64"`
+}
+
+type CreateReleaseOption struct {
+	protobase.ProjectIdOption
+	Version     string `json:"version" binding:"required,lte=64"`
+	Description string `json:"description" binding:"omitempty,lte=10000"`
+}
+
+type DiffReleaseOption struct {
+	ReleaseVersionOption
+	BaseVersion string `query:"baseVersion" json:"baseVersion" binding:"required,lte=64"`
+}
+
+type GetReleaseExportPathOption struct {
+	ReleaseVersionOption
+	Type     string `query:"type" json:"type" binding:"required,oneof=apicat swagger openapi3.0.0 openapi3.0.1 openapi3.0.2 openapi3.1.0 HTML md"`
+	Download bool   `query:"download" json:"download"`
+}
+
+type ReleaseExportCodeOption struct {
+	ReleaseVersionOption
+	Code string `uri:"code" binding:"required,len=32"`
+}
@@ -0,0 +1,13 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type GetProjectTimelineOption struct {
+	protobase.ProjectIdOption
+	// StartTime和EndTime同时指定时只返回该时间段内的变更
+	StartTime int64 `query:"startTime" json:"startTime" binding:"omitempty,numeric,gt=1584374400"`
+	EndTime   int64 `query:"endTime" json:"endTime" binding:"omitempty,numeric,gt=1584374400"`
+	Limit     int   `query:"limit" json:"limit" binding:"omitempty,numeric,gte=1,lte=200"`
+}
@@ -0,0 +1,20 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type RestoreDefinitionSchemasOption struct {
+	protobase.ProjectIdOption
+	SchemaIDs []uint `json:"schemaIDs" binding:"required,min=1,dive,gt=0"`
+}
+
+type RestoreDefinitionResponsesOption struct {
+	protobase.ProjectIdOption
+	ResponseIDs []uint `json:"responseIDs" binding:"required,min=1,dive,gt=0"`
+}
+
+type RestoreProjectsOption struct {
+	protobase.TeamIdOption
+	ProjectIDs []string `json:"projectIDs" binding:"required,min=1,dive,len=24"`
+}
@@ -0,0 +1,21 @@
+package response
+
+import "github.com/apicat/apicat/v2/backend/utils/jsonpatch"
+
+type AIReviewFinding struct {
+	Category   string                `json:"category"`
+	Severity   string                `json:"severity"`
+	TargetType string                `json:"targetType"`
+	TargetID   uint                  `json:"targetID"`
+	TargetName string                `json:"targetName"`
+	Pointer    string                `json:"pointer"`
+	Message    string                `json:"message"`
+	Suggestion string                `json:"suggestion"`
+	Patch      []jsonpatch.Operation `json:"patch"`
+}
+
+type AIReview struct {
+	Findings []*AIReviewFinding `json:"findings"`
+	// Truncated 接口或模型过多时只评审前面的一部分
+	Truncated bool `json:"truncated"`
+}
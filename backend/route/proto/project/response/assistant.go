@@ -0,0 +1,15 @@
+package response
+
+type AssistantCitation struct {
+	// TargetType collection 或 schema
+	TargetType string `json:"targetType"`
+	TargetID   uint   `json:"targetID"`
+	Title      string `json:"title"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+}
+
+type AssistantAnswer struct {
+	Answer    string               `json:"answer"`
+	Citations []*AssistantCitation `json:"citations"`
+}
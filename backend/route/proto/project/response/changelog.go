@@ -0,0 +1,34 @@
+package response
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type ProjectDiffChange struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+}
+
+type ProjectDiffEndpoint struct {
+	Status          string                          `json:"status"`
+	Method          string                          `json:"method"`
+	Path            string                          `json:"path"`
+	Title           string                          `json:"title"`
+	Changes         []*ProjectDiffChange            `json:"changes"`
+	BreakingChanges *protobase.BreakingChangeReport `json:"breakingChanges"`
+}
+
+type ProjectDiffModel struct {
+	Status          string                          `json:"status"`
+	Name            string                          `json:"name"`
+	Changes         []*ProjectDiffChange            `json:"changes"`
+	BreakingChanges *protobase.BreakingChangeReport `json:"breakingChanges"`
+}
+
+type ProjectDiff struct {
+	From        string                 `json:"from"`
+	To          string                 `json:"to"`
+	HasBreaking bool                   `json:"hasBreaking"`
+	Endpoints   []*ProjectDiffEndpoint `json:"endpoints"`
+	Models      []*ProjectDiffModel    `json:"models"`
+}
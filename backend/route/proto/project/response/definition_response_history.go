@@ -0,0 +1,31 @@
+package response
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	projectbase "github.com/apicat/apicat/v2/backend/route/proto/project/base"
+)
+
+type DefinitionResponseHistory struct {
+	protobase.IdCreateTimeInfo
+	DefinitionResponseHistoryData
+	CreatedBy string `json:"createdBy"`
+}
+
+type DefinitionResponseHistoryData struct {
+	projectbase.DefinitionResponseDataOption
+	ResponseID uint `json:"responseID"`
+}
+
+type DefinitionResponseHistoryList []*DefinitionResponseHistoryItem
+
+type DefinitionResponseHistoryItem struct {
+	protobase.IdCreateTimeInfo
+	CreatedBy string `json:"createdBy"`
+}
+
+type DiffDefinitionResponseHistories struct {
+	Response1 *DefinitionResponseHistory `json:"response1"`
+	Response2 *DefinitionResponseHistory `json:"response2"`
+	// BreakingChanges response1到response2的兼容性检查报告
+	BreakingChanges *protobase.BreakingChangeReport `json:"breakingChanges"`
+}
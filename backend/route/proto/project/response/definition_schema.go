@@ -11,6 +11,8 @@ type DefinitionSchema struct {
 	projectbase.DefinitionSchemaParentIDOption
 	projectbase.DefinitionSchemaTypeOption
 	projectbase.OperatorID
+	// Lang 返回译文时为译文的语言
+	Lang string `json:"lang,omitempty"`
 }
 
 type DefinitionSchemaTree []*DefinitionSchemaNode
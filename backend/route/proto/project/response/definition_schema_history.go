@@ -26,4 +26,6 @@ type DefinitionSchemaHistoryItem struct {
 type DiffDefinitionSchemaHistories struct {
 	Schema1 *DefinitionSchemaHistory `json:"schema1"`
 	Schema2 *DefinitionSchemaHistory `json:"schema2"`
+	// BreakingChanges schema1到schema2的兼容性检查报告
+	BreakingChanges *protobase.BreakingChangeReport `json:"breakingChanges"`
 }
@@ -0,0 +1,31 @@
+package response
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	projectbase "github.com/apicat/apicat/v2/backend/route/proto/project/base"
+)
+
+type GlobalParameterHistory struct {
+	protobase.IdCreateTimeInfo
+	GlobalParameterHistoryData
+	CreatedBy string `json:"createdBy"`
+}
+
+type GlobalParameterHistoryData struct {
+	projectbase.GlobalParameterDataOption
+	ParameterID uint `json:"parameterID"`
+}
+
+type GlobalParameterHistoryList []*GlobalParameterHistoryItem
+
+type GlobalParameterHistoryItem struct {
+	protobase.IdCreateTimeInfo
+	CreatedBy string `json:"createdBy"`
+}
+
+type DiffGlobalParameterHistories struct {
+	Parameter1 *GlobalParameterHistory `json:"parameter1"`
+	Parameter2 *GlobalParameterHistory `json:"parameter2"`
+	// BreakingChanges parameter1到parameter2的兼容性检查报告
+	BreakingChanges *protobase.BreakingChangeReport `json:"breakingChanges"`
+}
@@ -0,0 +1,9 @@
+package response
+
+type HistoryRetention struct {
+	KeepCount      int   `json:"keepCount"`
+	KeepDays       int   `json:"keepDays"`
+	DailyAfterDays int   `json:"dailyAfterDays"`
+	DeltaStorage   bool  `json:"deltaStorage"`
+	CompactedAt    int64 `json:"compactedAt"`
+}
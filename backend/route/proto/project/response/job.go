@@ -0,0 +1,26 @@
+package response
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type ProjectJob struct {
+	protobase.IdCreateTimeInfo
+	Type        string `json:"type"`
+	TargetID    uint   `json:"targetID"`
+	Status      string `json:"status"`
+	Progress    int    `json:"progress"`
+	Attempts    int    `json:"attempts"`
+	MaxAttempts int    `json:"maxAttempts"`
+	Error       string `json:"error"`
+	StartedAt   int64  `json:"startedAt"`
+	FinishedAt  int64  `json:"finishedAt"`
+}
+
+type ProjectJobDetail struct {
+	ProjectJob
+	// Result 任务结果，导出任务的内容需通过下载接口获取
+	Result string `json:"result"`
+}
+
+type ProjectJobList []*ProjectJob
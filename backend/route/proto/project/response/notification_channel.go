@@ -0,0 +1,17 @@
+package response
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type NotificationChannel struct {
+	protobase.IdCreateTimeInfo
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	URL       string   `json:"url"`
+	HasSecret bool     `json:"hasSecret"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+}
+
+type NotificationChannelList []*NotificationChannel
@@ -1,6 +1,7 @@
 package response
 
 import (
+	"github.com/apicat/apicat/v2/backend/model/project"
 	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
 	projectbase "github.com/apicat/apicat/v2/backend/route/proto/project/base"
 )
@@ -9,6 +10,8 @@ type ProjectSelfMemberInfo struct {
 	GroupID    uint `json:"groupID"`
 	IsFollowed bool `json:"isFollowed"`
 	protobase.ProjectMemberPermission
+	protobase.ProjectMemberRoleOption
+	Capabilities []project.Capability `json:"capabilities,omitempty"`
 }
 
 type ProjectListItem struct {
@@ -10,6 +10,7 @@ type ProjectMember struct {
 	protobase.IdCreateTimeInfo
 	teambase.TeamMemberStatusOption
 	protobase.ProjectMemberPermission
+	protobase.ProjectMemberRoleOption
 	User userresponse.UserData `json:"user"`
 }
 
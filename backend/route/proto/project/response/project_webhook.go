@@ -0,0 +1,40 @@
+package response
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type ProjectWebhook struct {
+	protobase.IdCreateTimeInfo
+	Name          string   `json:"name"`
+	URL           string   `json:"url"`
+	HasSecret     bool     `json:"hasSecret"`
+	Events        []string `json:"events"`
+	Active        bool     `json:"active"`
+	MaxRetries    int      `json:"maxRetries"`
+	RetryInterval int      `json:"retryInterval"`
+}
+
+type ProjectWebhookList []*ProjectWebhook
+
+type WebhookDelivery struct {
+	protobase.IdCreateTimeInfo
+	Event          string `json:"event"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	NextAttemptAt  int64  `json:"nextAttemptAt"`
+	ResponseStatus int    `json:"responseStatus"`
+	Duration       int64  `json:"duration"`
+}
+
+type WebhookDeliveryDetail struct {
+	WebhookDelivery
+	Payload      string `json:"payload"`
+	ResponseBody string `json:"responseBody"`
+	Error        string `json:"error"`
+}
+
+type WebhookDeliveryList struct {
+	protobase.PaginationInfo
+	Items []*WebhookDelivery `json:"items"`
+}
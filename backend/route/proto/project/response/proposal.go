@@ -0,0 +1,48 @@
+package response
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type Protection struct {
+	protobase.IdCreateTimeInfo
+	TargetType string `json:"targetType"`
+	TargetID   uint   `json:"targetID"`
+	Title      string `json:"title"`
+	CreatedBy  string `json:"createdBy"`
+}
+
+type ProtectionList []*Protection
+
+type Proposal struct {
+	protobase.IdCreateTimeInfo
+	TargetType string `json:"targetType"`
+	TargetID   uint   `json:"targetID"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	CreatedBy  string `json:"createdBy"`
+	ClosedBy   string `json:"closedBy"`
+	ClosedAt   int64  `json:"closedAt"`
+}
+
+type ProposalList []*Proposal
+
+type ProposalComment struct {
+	protobase.IdCreateTimeInfo
+	Content   string `json:"content"`
+	CreatedBy string `json:"createdBy"`
+}
+
+// ProposalDetail 提案详情，包含提交时和提案的内容以及二者的差异
+// Base开头的字段为提交时的内容，Title对于模型是名称，Description只用于模型
+type ProposalDetail struct {
+	Proposal
+	BaseTitle       string                          `json:"baseTitle"`
+	BaseDescription string                          `json:"baseDescription"`
+	BaseContent     string                          `json:"baseContent"`
+	Description     string                          `json:"description"`
+	Content         string                          `json:"content"`
+	Changes         []*ProjectDiffChange            `json:"changes"`
+	BreakingChanges *protobase.BreakingChangeReport `json:"breakingChanges"`
+	Comments        []*ProposalComment              `json:"comments"`
+}
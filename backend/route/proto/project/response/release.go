@@ -0,0 +1,20 @@
+package response
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type ReleaseItem struct {
+	protobase.IdCreateTimeInfo
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	CreatedBy   string `json:"createdBy"`
+}
+
+type ReleaseList []*ReleaseItem
+
+type Release struct {
+	ReleaseItem
+	// Spec 发布时冻结的完整spec，接口已解引用
+	Spec string `json:"spec"`
+}
@@ -0,0 +1,14 @@
+package response
+
+type ProjectTimeline []*ProjectTimelineItem
+
+// ProjectTimelineItem 项目动态中的一条变更，对应某一类条目的一条历史记录
+type ProjectTimelineItem struct {
+	// Type 条目类型：collection、schema、response、parameter
+	Type      string `json:"type"`
+	TargetID  uint   `json:"targetID"`
+	HistoryID uint   `json:"historyID"`
+	Title     string `json:"title"`
+	CreatedAt int64  `json:"createdAt"`
+	CreatedBy string `json:"createdBy"`
+}
@@ -0,0 +1,30 @@
+package response
+
+import (
+	"time"
+)
+
+type TrashDeleteInfo struct {
+	DeletedAt time.Time `json:"deletedAt"`
+	DeletedBy string    `json:"deletedBy"`
+}
+
+type DefinitionTrash struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	TrashDeleteInfo
+}
+
+type DefinitionTrashList []*DefinitionTrash
+
+type ProjectTrash struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	TrashDeleteInfo
+}
+
+type ProjectTrashList []*ProjectTrash
+
+type RestoreNum struct {
+	Num int `json:"num"`
+}
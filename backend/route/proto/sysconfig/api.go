@@ -76,4 +76,16 @@ type ModelApi interface {
 	// Update Update Azure OpenAI model config
 	// @route PUT /sysconfigs/models/azure-openai
 	UpdateAzureOpenAI(*gin.Context, *sysconfigrequest.AzureOpenAIOption) (*ginrpc.Empty, error)
+
+	// Update Update OpenAI compatible model config, e.g. vLLM or LM Studio
+	// @route PUT /sysconfigs/models/openai-compatible
+	UpdateOpenAICompatible(*gin.Context, *sysconfigrequest.OpenAICompatibleOption) (*ginrpc.Empty, error)
+
+	// Update Update Ollama model config
+	// @route PUT /sysconfigs/models/ollama
+	UpdateOllama(*gin.Context, *sysconfigrequest.OllamaOption) (*ginrpc.Empty, error)
+
+	// Update Update Anthropic model config
+	// @route PUT /sysconfigs/models/anthropic
+	UpdateAnthropic(*gin.Context, *sysconfigrequest.AnthropicOption) (*ginrpc.Empty, error)
 }
@@ -17,3 +17,20 @@ type OpenAIOption struct {
 	ApiBase        string `json:"apiBase" binding:"omitempty"`
 	ModelNameOption
 }
+
+type OpenAICompatibleOption struct {
+	ApiKey  string `json:"apiKey" binding:"omitempty"`
+	ApiBase string `json:"apiBase" binding:"required,url"`
+	ModelNameOption
+}
+
+type OllamaOption struct {
+	Host string `json:"host" binding:"required,url"`
+	ModelNameOption
+}
+
+type AnthropicOption struct {
+	ApiKey  string `json:"apiKey" binding:"required,gt=1"`
+	ApiBase string `json:"apiBase" binding:"omitempty,url"`
+	LLMName string `json:"llmName" binding:"required,gt=1"`
+}
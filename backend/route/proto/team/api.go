@@ -78,3 +78,27 @@ type TeamMemberApi interface {
 	// @route DELETE /teams/{teamID}/members
 	Quit(*gin.Context, *protobase.TeamIdOption) (*ginrpc.Empty, error)
 }
+
+type CustomRoleApi interface {
+	// List 团队的自定义角色列表
+	// @route GET /teams/{teamID}/roles
+	List(*gin.Context, *protobase.TeamIdOption) (*teamresponse.CustomRoles, error)
+
+	// Create 创建自定义角色
+	// @route POST /teams/{teamID}/roles
+	Create(*gin.Context, *teamrequest.CreateCustomRoleOption) (*teamresponse.CustomRole, error)
+
+	// Update 编辑自定义角色
+	// @route PUT /teams/{teamID}/roles/{roleID}
+	Update(*gin.Context, *teamrequest.UpdateCustomRoleOption) (*ginrpc.Empty, error)
+
+	// Delete 删除自定义角色，使用该角色的项目成员恢复为普通可写成员
+	// @route DELETE /teams/{teamID}/roles/{roleID}
+	Delete(*gin.Context, *teamrequest.CustomRoleIDOption) (*ginrpc.Empty, error)
+}
+
+type TeamSearchApi interface {
+	// Search 按语义搜索当前成员可访问项目中的接口、文档和模型
+	// @route GET /teams/{teamID}/search
+	Search(*gin.Context, *teamrequest.SemanticSearchOption) (*teamresponse.SemanticSearchResult, error)
+}
@@ -1,6 +1,7 @@
 package base
 
 import (
+	"github.com/apicat/apicat/v2/backend/model/project"
 	"github.com/apicat/apicat/v2/backend/model/team"
 )
 
@@ -17,3 +18,9 @@ type TeamMemberData struct {
 	Role team.Role `json:"role" binding:"omitempty,oneof=admin member"`
 	TeamMemberStatusOption
 }
+
+type CustomRoleDataOption struct {
+	Name         string               `json:"name" binding:"required,lte=255"`
+	Description  string               `json:"description" binding:"omitempty,lte=255"`
+	Capabilities []project.Capability `json:"capabilities" binding:"omitempty,dive,oneof=edit_collection edit_schema manage_share ai_generate export manage_iteration delete run_test"`
+}
@@ -0,0 +1,21 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	teambase "github.com/apicat/apicat/v2/backend/route/proto/team/base"
+)
+
+type CustomRoleIDOption struct {
+	protobase.TeamIdOption
+	RoleID uint `uri:"roleID" json:"roleID" query:"roleID" binding:"required,gt=0"`
+}
+
+type CreateCustomRoleOption struct {
+	protobase.TeamIdOption
+	teambase.CustomRoleDataOption
+}
+
+type UpdateCustomRoleOption struct {
+	CustomRoleIDOption
+	teambase.CustomRoleDataOption
+}
@@ -0,0 +1,12 @@
+package request
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+)
+
+type SemanticSearchOption struct {
+	protobase.TeamIdOption
+	Query     string `query:"q" json:"q" binding:"required,lte=500"`
+	ProjectID string `query:"projectID" json:"projectID" binding:"omitempty,len=24"`
+	Limit     int    `query:"limit" json:"limit" binding:"omitempty,gt=0,lte=50"`
+}
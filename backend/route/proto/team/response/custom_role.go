@@ -0,0 +1,13 @@
+package response
+
+import (
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+	teambase "github.com/apicat/apicat/v2/backend/route/proto/team/base"
+)
+
+type CustomRole struct {
+	protobase.EmbedInfo
+	teambase.CustomRoleDataOption
+}
+
+type CustomRoles []*CustomRole
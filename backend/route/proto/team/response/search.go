@@ -0,0 +1,17 @@
+package response
+
+type SemanticSearchItem struct {
+	ProjectID    string `json:"projectID"`
+	ProjectTitle string `json:"projectTitle"`
+	// TargetType collection 或 schema
+	TargetType string `json:"targetType"`
+	TargetID   uint   `json:"targetID"`
+	// Type 接口为 http，文档为 doc，模型为 schema
+	Type   string  `json:"type"`
+	Title  string  `json:"title"`
+	Method string  `json:"method"`
+	Path   string  `json:"path"`
+	Score  float32 `json:"score"`
+}
+
+type SemanticSearchResult []*SemanticSearchItem
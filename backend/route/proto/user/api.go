@@ -54,6 +54,10 @@ type UserApi interface {
 	// @route DELETE /users/{userID}
 	DelUser(*gin.Context, *request.UserIDOption) (*ginrpc.Empty, error)
 
+	// Unlock 管理员解除用户的登录锁定
+	// @route PUT /users/{userID}/unlock
+	Unlock(*gin.Context, *request.UserIDOption) (*ginrpc.Empty, error)
+
 	// GetSelf 当前登录的用户
 	// @route GET /user
 	GetSelf(*gin.Context, *ginrpc.Empty) (*response.User, error)
@@ -12,7 +12,12 @@ type User struct {
 	Github bool `json:"github"`
 }
 
+type UserListItem struct {
+	User
+	Locked bool `json:"locked"`
+}
+
 type UserList struct {
 	protobase.PaginationInfo
-	Items []User `json:"items"`
+	Items []UserListItem `json:"items"`
 }
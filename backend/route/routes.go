@@ -1,6 +1,9 @@
 package route
 
 import (
+	modelproject "github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/route/api/aiusage"
+	"github.com/apicat/apicat/v2/backend/route/api/audit"
 	"github.com/apicat/apicat/v2/backend/route/api/collection"
 	"github.com/apicat/apicat/v2/backend/route/api/iteration"
 	"github.com/apicat/apicat/v2/backend/route/api/jsonschema"
@@ -9,6 +12,7 @@ import (
 	"github.com/apicat/apicat/v2/backend/route/api/team"
 	"github.com/apicat/apicat/v2/backend/route/api/user"
 	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	aiusageservice "github.com/apicat/apicat/v2/backend/service/aiusage"
 
 	"github.com/apicat/ginrpc"
 	"github.com/gin-gonic/gin"
@@ -19,6 +23,7 @@ func registerUser(g *gin.RouterGroup) {
 	g.GET("/users", access.SysAdmin(), ginrpc.Handle(srv.GetList))
 	g.PATCH("/users/:userID", access.SysAdmin(), ginrpc.Handle(srv.ChangePasswordByAdmin))
 	g.DELETE("/users/:userID", access.SysAdmin(), ginrpc.Handle(srv.DelUser))
+	g.PUT("/users/:userID/unlock", access.SysAdmin(), ginrpc.Handle(srv.Unlock))
 
 	r := g.Group("/user")
 	r.GET("", ginrpc.Handle(srv.GetSelf))
@@ -70,6 +75,30 @@ func registerTeamMember(g *gin.RouterGroup) {
 	r.DELETE("", ginrpc.Handle(srv.Quit))
 }
 
+func registerCustomRole(g *gin.RouterGroup) {
+	srv := team.NewCustomRoleApi()
+	r := g.Group("/teams/:teamID/roles", access.BelongToTeam())
+	r.GET("", ginrpc.Handle(srv.List))
+	r.POST("", ginrpc.Handle(srv.Create))
+	r.PUT("/:roleID", ginrpc.Handle(srv.Update))
+	r.DELETE("/:roleID", ginrpc.Handle(srv.Delete))
+}
+
+func registerAuditLog(g *gin.RouterGroup) {
+	srv := audit.NewAuditLogApi()
+
+	// 导出审计日志，需返回不同的 Content-Type，单独处理
+	g.GET("/teams/:teamID/audit-logs/export/:code", audit.Export)
+	g.GET("/sysconfigs/audit-logs/export/:code", audit.Export)
+
+	r := g.Group("/teams/:teamID/audit-logs", access.BelongToTeam())
+	r.GET("", ginrpc.Handle(srv.TeamList))
+	r.GET("/export", ginrpc.Handle(srv.TeamExportPath))
+
+	g.GET("/sysconfigs/audit-logs", access.SysAdmin(), ginrpc.Handle(srv.SysList))
+	g.GET("/sysconfigs/audit-logs/export", access.SysAdmin(), ginrpc.Handle(srv.SysExportPath))
+}
+
 func registerProjectGroup(g *gin.RouterGroup) {
 	srv := project.NewProjectGroupApi()
 	r := g.Group("/teams/:teamID/project-groups", access.BelongToTeam())
@@ -88,6 +117,8 @@ func registerProject(g *gin.RouterGroup) {
 	r := g.Group("/teams/:teamID/projects", access.BelongToTeam())
 	r.POST("", ginrpc.Handle(srv.Create))
 	r.GET("", ginrpc.Handle(srv.List))
+	r.GET("/trashes", ginrpc.Handle(srv.Trashes))
+	r.PUT("/restore", ginrpc.Handle(srv.Restore))
 
 	noAuth := g.Group("/projects/:projectID")
 	noAuth.GET("", access.AllowGuestByShareCode(), ginrpc.Handle(srv.Get))
@@ -102,7 +133,66 @@ func registerProject(g *gin.RouterGroup) {
 	p.DELETE("/follow", ginrpc.Handle(srv.UnFollow))
 	p.PUT("/transfer", ginrpc.Handle(srv.Transfer))
 	p.DELETE("/exit", ginrpc.Handle(srv.Exit))
-	p.GET("/export", ginrpc.Handle(srv.GetExportPath))
+	p.GET("/export", access.RequireCapability(modelproject.CapabilityExport), ginrpc.Handle(srv.GetExportPath))
+}
+
+func registerProjectChangelog(g *gin.RouterGroup) {
+	srv := project.NewProjectChangelogApi()
+
+	// 导出变更日志，需返回不同的 Content-Type，单独处理
+	g.GET("/projects/:projectID/changelog/export/:code", project.ExportChangelog)
+
+	r := g.Group("/projects/:projectID", access.BelongToTeam(), access.BelongToProject())
+	r.GET("/diff", ginrpc.Handle(srv.Diff))
+	r.GET("/changelog/export", access.RequireCapability(modelproject.CapabilityExport), ginrpc.Handle(srv.GetExportPath))
+}
+
+func registerProjectRelease(g *gin.RouterGroup) {
+	srv := project.NewProjectReleaseApi()
+
+	// 导出发布版本，需返回不同的 Content-Type，单独处理，token中的成员需拥有导出能力
+	g.GET("/projects/:projectID/releases/:version/export/:code", project.ExportRelease)
+
+	// 发布版本对外只读，包含项目的全部内容，访客只能通过项目分享访问
+	guest := g.Group("/projects/:projectID/releases", access.AllowGuestByProjectShareCode())
+	guest.GET("", ginrpc.Handle(srv.List))
+	guest.GET("/:version", ginrpc.Handle(srv.Get))
+	guest.GET("/:version/diff", ginrpc.Handle(srv.Diff))
+
+	r := g.Group("/projects/:projectID/releases", access.BelongToTeam(), access.BelongToProject())
+	r.POST("", ginrpc.Handle(srv.Create))
+	r.DELETE("/:version", ginrpc.Handle(srv.Delete))
+	r.GET("/:version/export", access.RequireCapability(modelproject.CapabilityExport), ginrpc.Handle(srv.GetExportPath))
+}
+
+func registerProjectTimeline(g *gin.RouterGroup) {
+	srv := project.NewProjectTimelineApi()
+
+	r := g.Group("/projects/:projectID/timeline", access.BelongToTeam(), access.BelongToProject())
+	r.GET("", ginrpc.Handle(srv.List))
+}
+
+func registerProjectHistoryRetention(g *gin.RouterGroup) {
+	srv := project.NewProjectHistoryRetentionApi()
+
+	r := g.Group("/projects/:projectID/history-retention", access.BelongToTeam(), access.BelongToProject())
+	r.GET("", ginrpc.Handle(srv.Get))
+	r.PUT("", ginrpc.Handle(srv.Update))
+}
+
+func registerProjectProposal(g *gin.RouterGroup) {
+	srv := project.NewProjectProposalApi()
+
+	r := g.Group("/projects/:projectID", access.BelongToTeam(), access.BelongToProject())
+	r.GET("/protections", ginrpc.Handle(srv.ListProtections))
+	r.POST("/protections", ginrpc.Handle(srv.CreateProtection))
+	r.DELETE("/protections/:protectionID", ginrpc.Handle(srv.DeleteProtection))
+	r.GET("/proposals", ginrpc.Handle(srv.List))
+	r.GET("/proposals/:proposalID", ginrpc.Handle(srv.Get))
+	r.POST("/proposals/:proposalID/comments", ginrpc.Handle(srv.Comment))
+	r.POST("/proposals/:proposalID/approve", ginrpc.Handle(srv.Approve))
+	r.POST("/proposals/:proposalID/reject", ginrpc.Handle(srv.Reject))
+	r.POST("/proposals/:proposalID/withdraw", ginrpc.Handle(srv.Withdraw))
 }
 
 func registerProjectShare(g *gin.RouterGroup) {
@@ -114,8 +204,18 @@ func registerProjectShare(g *gin.RouterGroup) {
 
 	r := g.Group("/projects/:projectID/share", access.BelongToTeam(), access.BelongToProject())
 	r.GET("", ginrpc.Handle(srv.Detail))
-	r.PUT("", ginrpc.Handle(srv.Switch))
-	r.PUT("/reset", ginrpc.Handle(srv.Reset))
+	r.PUT("", access.RequireCapability(modelproject.CapabilityManageShare), ginrpc.Handle(srv.Switch))
+	r.PUT("/reset", access.RequireCapability(modelproject.CapabilityManageShare), ginrpc.Handle(srv.Reset))
+}
+
+func registerProjectGlobalParameterHistory(g *gin.RouterGroup) {
+	srv := project.NewGlobalParameterHistoryApi()
+
+	r := g.Group("/projects/:projectID/global/parameters/:parameterID/histories", access.BelongToTeam(), access.BelongToProject())
+	r.GET("", ginrpc.Handle(srv.List))
+	r.GET("/:historyID", ginrpc.Handle(srv.Get))
+	r.PUT("/:historyID/restore", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Restore))
+	r.GET("/diff", ginrpc.Handle(srv.Diff))
 }
 
 func registerProjectGlobalParameter(g *gin.RouterGroup) {
@@ -125,10 +225,10 @@ func registerProjectGlobalParameter(g *gin.RouterGroup) {
 	noAuth.GET("", ginrpc.Handle(srv.List))
 
 	r := g.Group("/projects/:projectID/global/parameters", access.BelongToTeam(), access.BelongToProject())
-	r.POST("", ginrpc.Handle(srv.Create))
-	r.PUT("/:parameterID", ginrpc.Handle(srv.Update))
-	r.DELETE("/:parameterID", ginrpc.Handle(srv.Delete))
-	r.PUT("/sort", ginrpc.Handle(srv.Sort))
+	r.POST("", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Create))
+	r.PUT("/:parameterID", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Update))
+	r.DELETE("/:parameterID", access.RequireCapability(modelproject.CapabilityDelete), ginrpc.Handle(srv.Delete))
+	r.PUT("/sort", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Sort))
 }
 
 func registerProjectServer(g *gin.RouterGroup) {
@@ -138,10 +238,35 @@ func registerProjectServer(g *gin.RouterGroup) {
 	noAuth.GET("", ginrpc.Handle(srv.List))
 
 	r := g.Group("/projects/:projectID/servers", access.BelongToTeam(), access.BelongToProject())
+	r.POST("", access.RequireCapability(modelproject.CapabilityEditCollection), ginrpc.Handle(srv.Create))
+	r.PUT("/:serverID", access.RequireCapability(modelproject.CapabilityEditCollection), ginrpc.Handle(srv.Update))
+	r.DELETE("/:serverID", access.RequireCapability(modelproject.CapabilityDelete), ginrpc.Handle(srv.Delete))
+	r.PUT("/sort", access.RequireCapability(modelproject.CapabilityEditCollection), ginrpc.Handle(srv.Sort))
+}
+
+func registerProjectWebhook(g *gin.RouterGroup) {
+	srv := project.NewProjectWebhookApi()
+
+	r := g.Group("/projects/:projectID/webhooks", access.BelongToTeam(), access.BelongToProject())
+	r.GET("", ginrpc.Handle(srv.List))
 	r.POST("", ginrpc.Handle(srv.Create))
-	r.PUT("/:serverID", ginrpc.Handle(srv.Update))
-	r.DELETE("/:serverID", ginrpc.Handle(srv.Delete))
-	r.PUT("/sort", ginrpc.Handle(srv.Sort))
+	r.PUT("/:webhookID", ginrpc.Handle(srv.Update))
+	r.DELETE("/:webhookID", ginrpc.Handle(srv.Delete))
+	r.POST("/:webhookID/test", ginrpc.Handle(srv.Test))
+	r.GET("/:webhookID/deliveries", ginrpc.Handle(srv.DeliveryList))
+	r.GET("/:webhookID/deliveries/:deliveryID", ginrpc.Handle(srv.GetDelivery))
+	r.POST("/:webhookID/deliveries/:deliveryID/redeliver", ginrpc.Handle(srv.Redeliver))
+}
+
+func registerProjectNotificationChannel(g *gin.RouterGroup) {
+	srv := project.NewProjectNotificationChannelApi()
+
+	r := g.Group("/projects/:projectID/notification-channels", access.BelongToTeam(), access.BelongToProject())
+	r.GET("", ginrpc.Handle(srv.List))
+	r.POST("", ginrpc.Handle(srv.Create))
+	r.PUT("/:channelID", ginrpc.Handle(srv.Update))
+	r.DELETE("/:channelID", ginrpc.Handle(srv.Delete))
+	r.POST("/:channelID/test", ginrpc.Handle(srv.Test))
 }
 
 func registerProjectMember(g *gin.RouterGroup) {
@@ -161,13 +286,17 @@ func registerProjectDefinitionSchema(g *gin.RouterGroup) {
 	noAuth.GET("", ginrpc.Handle(srv.List))
 	noAuth.GET("/:schemaID", ginrpc.Handle(srv.Get))
 
-	g.POST("/projects/:projectID/definition/ai/schemas", access.BelongToTeam(), access.BelongToProject(), ginrpc.Handle(srv.AIGenerate))
+	g.POST("/projects/:projectID/definition/ai/schemas", access.BelongToTeam(), access.BelongToProject(), access.RequireCapability(modelproject.CapabilityAIGenerate), access.AIUsage(aiusageservice.FeatureSchemaGenerate), ginrpc.Handle(srv.AIGenerate))
+	// 流式生成模型，以 SSE 返回，单独处理
+	g.POST("/projects/:projectID/definition/ai/schemas/stream", access.BelongToTeam(), access.BelongToProject(), access.RequireCapability(modelproject.CapabilityAIGenerate), access.AIUsage(aiusageservice.FeatureSchemaGenerate), project.AIGenerateSchemaStream)
 	r := g.Group("/projects/:projectID/definition/schemas", access.BelongToTeam(), access.BelongToProject())
-	r.POST("", ginrpc.Handle(srv.Create))
-	r.PUT("/:schemaID", ginrpc.Handle(srv.Update))
-	r.DELETE("/:schemaID", ginrpc.Handle(srv.Delete))
-	r.PUT("/move", ginrpc.Handle(srv.Move))
-	r.POST("/:schemaID/copy", ginrpc.Handle(srv.Copy))
+	r.POST("", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Create))
+	r.PUT("/:schemaID", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Update))
+	r.DELETE("/:schemaID", access.RequireCapability(modelproject.CapabilityDelete), ginrpc.Handle(srv.Delete))
+	r.PUT("/move", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Move))
+	r.POST("/:schemaID/copy", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Copy))
+	r.GET("/trashes", ginrpc.Handle(srv.Trashes))
+	r.PUT("/restore", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Restore))
 }
 
 func registerProjectDefinitionSchemaHistory(g *gin.RouterGroup) {
@@ -176,7 +305,17 @@ func registerProjectDefinitionSchemaHistory(g *gin.RouterGroup) {
 	r := g.Group("/projects/:projectID/definition/schemas/:schemaID/histories", access.BelongToTeam(), access.BelongToProject())
 	r.GET("", ginrpc.Handle(srv.List))
 	r.GET("/:historyID", ginrpc.Handle(srv.Get))
-	r.PUT("/:historyID/restore", ginrpc.Handle(srv.Restore))
+	r.PUT("/:historyID/restore", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Restore))
+	r.GET("/diff", ginrpc.Handle(srv.Diff))
+}
+
+func registerProjectDefinitionResponseHistory(g *gin.RouterGroup) {
+	srv := project.NewDefinitionResponseHistoryApi()
+
+	r := g.Group("/projects/:projectID/definition/responses/:responseID/histories", access.BelongToTeam(), access.BelongToProject())
+	r.GET("", ginrpc.Handle(srv.List))
+	r.GET("/:historyID", ginrpc.Handle(srv.Get))
+	r.PUT("/:historyID/restore", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Restore))
 	r.GET("/diff", ginrpc.Handle(srv.Diff))
 }
 
@@ -188,11 +327,13 @@ func registerProjectDefinitionResponse(g *gin.RouterGroup) {
 	noAuth.GET("/:responseID", ginrpc.Handle(srv.Get))
 
 	r := g.Group("/projects/:projectID/definition/responses", access.BelongToTeam(), access.BelongToProject())
-	r.POST("", ginrpc.Handle(srv.Create))
-	r.PUT("/:responseID", ginrpc.Handle(srv.Update))
-	r.DELETE("/:responseID", ginrpc.Handle(srv.Delete))
-	r.PUT("/move", ginrpc.Handle(srv.Move))
-	r.POST("/:responseID/copy", ginrpc.Handle(srv.Copy))
+	r.POST("", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Create))
+	r.PUT("/:responseID", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Update))
+	r.DELETE("/:responseID", access.RequireCapability(modelproject.CapabilityDelete), ginrpc.Handle(srv.Delete))
+	r.PUT("/move", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Move))
+	r.POST("/:responseID/copy", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Copy))
+	r.GET("/trashes", ginrpc.Handle(srv.Trashes))
+	r.PUT("/restore", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.Restore))
 }
 
 func registerCollection(g *gin.RouterGroup) {
@@ -203,16 +344,18 @@ func registerCollection(g *gin.RouterGroup) {
 	// 导出集合内容，需返回不同的 Content-Type，单独处理
 	noAuth.GET("/:collectionID/export/:code", collection.Export)
 
-	g.POST("/projects/:projectID/ai/collections", access.BelongToTeam(), access.BelongToProject(), ginrpc.Handle(srv.AIGenerate))
+	g.POST("/projects/:projectID/ai/collections", access.BelongToTeam(), access.BelongToProject(), access.RequireCapability(modelproject.CapabilityAIGenerate), access.AIUsage(aiusageservice.FeatureCollectionGenerate), ginrpc.Handle(srv.AIGenerate))
+	// 流式生成集合，以 SSE 返回，单独处理
+	g.POST("/projects/:projectID/ai/collections/stream", access.BelongToTeam(), access.BelongToProject(), access.RequireCapability(modelproject.CapabilityAIGenerate), access.AIUsage(aiusageservice.FeatureCollectionGenerate), collection.AIGenerateStream)
 	r := g.Group("/projects/:projectID/collections", access.BelongToTeam(), access.BelongToProject())
-	r.POST("", ginrpc.Handle(srv.Create))
-	r.PUT("/:collectionID", ginrpc.Handle(srv.Update))
-	r.DELETE("/:collectionID", ginrpc.Handle(srv.Delete))
-	r.PUT("/move", ginrpc.Handle(srv.Move))
-	r.POST("/:collectionID/copy", ginrpc.Handle(srv.Copy))
+	r.POST("", access.RequireCapability(modelproject.CapabilityEditCollection), ginrpc.Handle(srv.Create))
+	r.PUT("/:collectionID", access.RequireCapability(modelproject.CapabilityEditCollection), ginrpc.Handle(srv.Update))
+	r.DELETE("/:collectionID", access.RequireCapability(modelproject.CapabilityDelete), ginrpc.Handle(srv.Delete))
+	r.PUT("/move", access.RequireCapability(modelproject.CapabilityEditCollection), ginrpc.Handle(srv.Move))
+	r.POST("/:collectionID/copy", access.RequireCapability(modelproject.CapabilityEditCollection), ginrpc.Handle(srv.Copy))
 	r.GET("/trashes", ginrpc.Handle(srv.Trashes))
-	r.PUT("/restore", ginrpc.Handle(srv.Restore))
-	r.GET("/:collectionID/export", ginrpc.Handle(srv.GetExportPath))
+	r.PUT("/restore", access.RequireCapability(modelproject.CapabilityEditCollection), ginrpc.Handle(srv.Restore))
+	r.GET("/:collectionID/export", access.RequireCapability(modelproject.CapabilityExport), ginrpc.Handle(srv.GetExportPath))
 }
 
 func registerCollectionMock(g *gin.RouterGroup) {
@@ -227,8 +370,8 @@ func registerCollectionShare(g *gin.RouterGroup) {
 
 	r := g.Group("/projects/:projectID/collections/:collectionID/share", access.BelongToTeam(), access.BelongToProject())
 	r.GET("", ginrpc.Handle(srv.Detail))
-	r.PUT("", ginrpc.Handle(srv.Switch))
-	r.PUT("/reset", ginrpc.Handle(srv.Reset))
+	r.PUT("", access.RequireCapability(modelproject.CapabilityManageShare), ginrpc.Handle(srv.Switch))
+	r.PUT("/reset", access.RequireCapability(modelproject.CapabilityManageShare), ginrpc.Handle(srv.Reset))
 }
 
 func registerCollectionHistory(g *gin.RouterGroup) {
@@ -237,7 +380,7 @@ func registerCollectionHistory(g *gin.RouterGroup) {
 	r := g.Group("/projects/:projectID/collections/:collectionID/histories", access.BelongToTeam(), access.BelongToProject())
 	r.GET("", ginrpc.Handle(srv.List))
 	r.GET("/:historyID", ginrpc.Handle(srv.Get))
-	r.PUT("/:historyID/restore", ginrpc.Handle(srv.Restore))
+	r.PUT("/:historyID/restore", access.RequireCapability(modelproject.CapabilityEditCollection), ginrpc.Handle(srv.Restore))
 	r.GET("/diff", ginrpc.Handle(srv.Diff))
 }
 
@@ -245,11 +388,14 @@ func registerTestCase(g *gin.RouterGroup) {
 	srv := collection.NewTestCaseApi()
 
 	r := g.Group("/projects/:projectID/collections/:collectionID/testcases", access.BelongToTeam(), access.BelongToProject())
-	r.POST("", ginrpc.Handle(srv.Generate))
+	r.POST("", access.RequireCapability(modelproject.CapabilityAIGenerate), access.AIUsage(aiusageservice.FeatureTestCaseGenerate), ginrpc.Handle(srv.Generate))
 	r.GET("", ginrpc.Handle(srv.List))
 	r.GET("/:testCaseID", ginrpc.Handle(srv.Get))
-	r.PUT("/:testCaseID", ginrpc.Handle(srv.Regenerate))
-	r.DELETE("/:testCaseID", ginrpc.Handle(srv.Delete))
+	r.PUT("/:testCaseID", access.RequireCapability(modelproject.CapabilityAIGenerate), access.AIUsage(aiusageservice.FeatureTestCaseRegenerate), ginrpc.Handle(srv.Regenerate))
+	r.DELETE("/:testCaseID", access.RequireCapability(modelproject.CapabilityDelete), ginrpc.Handle(srv.Delete))
+	r.PUT("/:testCaseID/spec", access.RequireCapability(modelproject.CapabilityEditCollection), ginrpc.Handle(srv.UpdateSpec))
+	r.POST("/:testCaseID/run", access.RequireCapability(modelproject.CapabilityRunTest), ginrpc.Handle(srv.Run))
+	r.POST("/run", access.RequireCapability(modelproject.CapabilityRunTest), ginrpc.Handle(srv.RunAll))
 }
 
 func registerIteration(g *gin.RouterGroup) {
@@ -261,8 +407,57 @@ func registerIteration(g *gin.RouterGroup) {
 
 	i := g.Group("/iterations/:iterationID", access.BelongToTeam(), access.BelongToProject())
 	i.GET("", ginrpc.Handle(srv.Get))
-	i.PUT("", ginrpc.Handle(srv.Update))
-	i.DELETE("", ginrpc.Handle(srv.Delete))
+	i.PUT("", access.RequireCapability(modelproject.CapabilityManageIteration), ginrpc.Handle(srv.Update))
+	i.DELETE("", access.RequireCapability(modelproject.CapabilityManageIteration), ginrpc.Handle(srv.Delete))
+}
+
+func registerIterationProgress(g *gin.RouterGroup) {
+	srv := iteration.NewIterationProgressApi()
+
+	r := g.Group("/iterations/:iterationID", access.BelongToTeam(), access.BelongToProject())
+	r.GET("/apis", ginrpc.Handle(srv.ListApis))
+	r.PUT("/apis/:collectionID", ginrpc.Handle(srv.UpdateApi))
+	r.GET("/burndown", ginrpc.Handle(srv.Burndown))
+}
+
+func registerIterationReport(g *gin.RouterGroup) {
+	srv := iteration.NewIterationReportApi()
+
+	// 导出迭代变更报告，需返回不同的 Content-Type，单独处理
+	g.GET("/iterations/:iterationID/report/export/:code", iteration.ExportReport)
+
+	r := g.Group("/iterations/:iterationID/report", access.BelongToTeam(), access.BelongToProject())
+	r.GET("", ginrpc.Handle(srv.Get))
+	r.GET("/export", access.RequireCapability(modelproject.CapabilityExport), ginrpc.Handle(srv.GetExportPath))
+}
+
+func registerIterationBranch(g *gin.RouterGroup) {
+	srv := iteration.NewIterationBranchApi()
+
+	b := g.Group("/iterations/:iterationID/branch", access.BelongToTeam(), access.BelongToProject())
+	b.GET("", ginrpc.Handle(srv.List))
+	b.GET("/collections/:collectionID", ginrpc.Handle(srv.GetCollection))
+	b.PUT("/collections/:collectionID", access.RequireCapability(modelproject.CapabilityEditCollection), ginrpc.Handle(srv.UpdateCollection))
+	b.DELETE("/collections/:collectionID", access.RequireCapability(modelproject.CapabilityDelete), ginrpc.Handle(srv.DeleteCollection))
+	b.GET("/schemas/:schemaID", ginrpc.Handle(srv.GetSchema))
+	b.PUT("/schemas/:schemaID", access.RequireCapability(modelproject.CapabilityEditSchema), ginrpc.Handle(srv.UpdateSchema))
+	b.DELETE("/schemas/:schemaID", access.RequireCapability(modelproject.CapabilityDelete), ginrpc.Handle(srv.DeleteSchema))
+	// 条目的类型在请求或条目中，由接口按照类型检查编辑或删除能力
+	b.POST("/items", ginrpc.Handle(srv.CreateItem))
+	b.GET("/items/:itemID", ginrpc.Handle(srv.GetItem))
+	b.PUT("/items/:itemID", ginrpc.Handle(srv.UpdateItem))
+	b.DELETE("/items/:itemID", ginrpc.Handle(srv.DiscardItem))
+}
+
+func registerIterationMergeRequest(g *gin.RouterGroup) {
+	srv := iteration.NewIterationMergeRequestApi()
+
+	r := g.Group("/iterations/:iterationID/merge-requests", access.BelongToTeam(), access.BelongToProject())
+	r.POST("", ginrpc.Handle(srv.Create))
+	r.GET("", ginrpc.Handle(srv.List))
+	r.GET("/:mergeRequestID", ginrpc.Handle(srv.Get))
+	r.POST("/:mergeRequestID/merge", access.RequireCapability(modelproject.CapabilityManageIteration), ginrpc.Handle(srv.Merge))
+	r.POST("/:mergeRequestID/close", access.RequireCapability(modelproject.CapabilityManageIteration), ginrpc.Handle(srv.Close))
 }
 
 func registerOauthSysconfig(g *gin.RouterGroup) {
@@ -298,9 +493,57 @@ func registerModelSysconfig(g *gin.RouterGroup) {
 	g.GET("/sysconfigs/models", access.SysAdmin(), ginrpc.Handle(srv.Get))
 	g.PUT("/sysconfigs/models/openai", access.SysAdmin(), ginrpc.Handle(srv.UpdateOpenAI))
 	g.PUT("/sysconfigs/models/azure-openai", access.SysAdmin(), ginrpc.Handle(srv.UpdateAzureOpenAI))
+	g.PUT("/sysconfigs/models/openai-compatible", access.SysAdmin(), ginrpc.Handle(srv.UpdateOpenAICompatible))
+	g.PUT("/sysconfigs/models/ollama", access.SysAdmin(), ginrpc.Handle(srv.UpdateOllama))
+	g.PUT("/sysconfigs/models/anthropic", access.SysAdmin(), ginrpc.Handle(srv.UpdateAnthropic))
 }
 
 func registerJsonSchema(g *gin.RouterGroup) {
 	srv := jsonschema.NewJsonSchemaApi()
 	g.POST("/jsonschema/parse", ginrpc.Handle(srv.Parse))
 }
+
+func registerProjectJob(g *gin.RouterGroup) {
+	srv := project.NewProjectJobApi()
+
+	r := g.Group("/projects/:projectID/jobs", access.BelongToTeam(), access.BelongToProject())
+	r.GET("", ginrpc.Handle(srv.List))
+	r.GET("/:jobID", ginrpc.Handle(srv.Get))
+	r.PUT("/:jobID/cancel", ginrpc.Handle(srv.Cancel))
+	r.POST("/export", access.RequireCapability(modelproject.CapabilityExport), ginrpc.Handle(srv.Export))
+	r.POST("/translate", access.RequireCapability(modelproject.CapabilityAIGenerate), access.AIUsage(aiusageservice.FeatureTranslate), ginrpc.Handle(srv.Translate))
+	// 下载导出任务的结果，需返回不同的 Content-Type，单独处理
+	r.GET("/:jobID/result", access.RequireCapability(modelproject.CapabilityExport), project.JobResult)
+}
+
+func registerProjectAIReview(g *gin.RouterGroup) {
+	srv := project.NewProjectAIReviewApi()
+
+	r := g.Group("/projects/:projectID/ai/reviews", access.BelongToTeam(), access.BelongToProject(), access.RequireCapability(modelproject.CapabilityAIGenerate))
+	r.POST("", access.AIUsage(aiusageservice.FeatureAPIReview), ginrpc.Handle(srv.Review))
+	// 应用评审建议按照目标类型在接口中检查编辑接口或编辑模型的能力
+	r.PUT("/apply", ginrpc.Handle(srv.Apply))
+}
+
+func registerTeamSearch(g *gin.RouterGroup) {
+	srv := team.NewTeamSearchApi()
+	g.GET("/teams/:teamID/search", access.BelongToTeam(), access.AIUsage(aiusageservice.FeatureEmbedding), ginrpc.Handle(srv.Search))
+}
+
+func registerProjectAssistant(g *gin.RouterGroup) {
+	srv := project.NewProjectAssistantApi()
+	// 访客可通过分享码使用
+	g.POST("/projects/:projectID/assistant/ask", access.AllowGuestByShareCode(), access.AIUsage(aiusageservice.FeatureDocsAsk), ginrpc.Handle(srv.Ask))
+}
+
+func registerAIUsage(g *gin.RouterGroup) {
+	srv := aiusage.NewAIUsageApi()
+
+	r := g.Group("/sysconfigs", access.SysAdmin())
+	r.GET("/ai-usage", ginrpc.Handle(srv.Report))
+	r.GET("/ai-usage/pricing", ginrpc.Handle(srv.GetPricing))
+	r.PUT("/ai-usage/pricing", ginrpc.Handle(srv.UpdatePricing))
+	r.GET("/ai-quotas", ginrpc.Handle(srv.QuotaList))
+	r.PUT("/ai-quotas", ginrpc.Handle(srv.UpdateQuota))
+	r.DELETE("/ai-quotas", ginrpc.Handle(srv.DeleteQuota))
+}
@@ -0,0 +1,33 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReleaseRejectsDocShareCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	registerProjectRelease(e.Group("/api"))
+
+	for _, path := range []string{
+		"/api/projects/p1/releases",
+		"/api/projects/p1/releases/1.0.0",
+		"/api/projects/p1/releases/1.0.0/diff?baseVersion=0.9.0",
+	} {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path+sep+"shareCode=d"+strings.Repeat("0", 32), nil)
+		e.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("GET %s with doc share code = %d, want 401", path, w.Code)
+		}
+	}
+}
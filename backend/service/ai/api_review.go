@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+
+	llmcommon "github.com/apicat/apicat/v2/backend/module/llm/common"
+	"github.com/apicat/apicat/v2/backend/utils/jsonpatch"
+)
+
+// 设计评审的问题类别
+const (
+	ReviewNaming      = "naming"
+	ReviewDescription = "description"
+	ReviewErrorFormat = "error_format"
+	ReviewRESTfulPath = "restful_path"
+	ReviewPagination  = "pagination"
+)
+
+const (
+	ReviewTargetCollection = "collection"
+	ReviewTargetSchema     = "schema"
+)
+
+var reviewSeverities = map[string]bool{"error": true, "warning": true, "info": true}
+
+var reviewCategories = map[string]bool{
+	ReviewNaming:      true,
+	ReviewDescription: true,
+	ReviewErrorFormat: true,
+	ReviewRESTfulPath: true,
+	ReviewPagination:  true,
+}
+
+// ReviewAPI 待评审的接口，Content 为文档原始内容，修改建议基于该内容生成
+type ReviewAPI struct {
+	ID      uint
+	Title   string
+	Summary string
+	Content string
+}
+
+// ReviewSchema 待评审的模型
+type ReviewSchema struct {
+	ID     uint
+	Name   string
+	Schema string
+}
+
+type ReviewOption struct {
+	APIs    []ReviewAPI
+	Schemas []ReviewSchema
+	// Rules 团队额外的规范
+	Rules string
+}
+
+type ReviewFinding struct {
+	Category   string                `json:"category"`
+	Severity   string                `json:"severity"`
+	TargetType string                `json:"targetType"`
+	TargetID   uint                  `json:"targetID"`
+	Pointer    string                `json:"pointer"`
+	Message    string                `json:"message"`
+	Suggestion string                `json:"suggestion"`
+	Patch      []jsonpatch.Operation `json:"patch"`
+}
+
+// APIReview 按规范评审接口和模型的设计
+func APIReview(ctx context.Context, language string, opt *ReviewOption) ([]*ReviewFinding, error) {
+	messages, err := NewTpl("api_review.tmpl", language, opt).Prompt()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := chatCompletion(ctx, &llmcommon.ChatCompletionRequest{
+		Temperature: 0.2,
+		MaxTokens:   4000,
+		Messages:    messages,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if result == "" {
+		return nil, errors.New("empty content")
+	}
+
+	result = strings.TrimSuffix(strings.TrimSpace(result), "```")
+	var findings []*ReviewFinding
+	if err := json.Unmarshal([]byte(result), &findings); err != nil {
+		slog.ErrorContext(ctx, "json.Unmarshal", "result", result)
+		return nil, err
+	}
+	return checkReviewFindings(findings, opt), nil
+}
+
+// checkReviewFindings 丢弃指向不存在目标的问题，无法应用到当前内容的修改建议置空
+func checkReviewFindings(findings []*ReviewFinding, opt *ReviewOption) []*ReviewFinding {
+	docs := map[string]map[uint]string{
+		ReviewTargetCollection: make(map[uint]string),
+		ReviewTargetSchema:     make(map[uint]string),
+	}
+	for _, v := range opt.APIs {
+		docs[ReviewTargetCollection][v.ID] = v.Content
+	}
+	for _, v := range opt.Schemas {
+		docs[ReviewTargetSchema][v.ID] = v.Schema
+	}
+
+	res := make([]*ReviewFinding, 0, len(findings))
+	for _, f := range findings {
+		if f == nil || f.Message == "" || !reviewCategories[f.Category] {
+			continue
+		}
+		targets, ok := docs[f.TargetType]
+		if !ok {
+			continue
+		}
+		doc, ok := targets[f.TargetID]
+		if !ok {
+			continue
+		}
+		if !reviewSeverities[f.Severity] {
+			f.Severity = "warning"
+		}
+		if len(f.Patch) > 0 {
+			if _, err := jsonpatch.Apply([]byte(doc), f.Patch); err != nil {
+				f.Patch = nil
+			}
+		}
+		if f.Patch == nil {
+			f.Patch = []jsonpatch.Operation{}
+		}
+		res = append(res, f)
+	}
+	return res
+}
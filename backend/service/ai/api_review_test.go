@@ -0,0 +1,41 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/utils/jsonpatch"
+)
+
+func TestCheckReviewFindings(t *testing.T) {
+	opt := &ReviewOption{
+		APIs:    []ReviewAPI{{ID: 1, Content: `[{"type":"apicat-http-url","attrs":{"path":"/getUsers","method":"get"}}]`}},
+		Schemas: []ReviewSchema{{ID: 2, Schema: `{"type":"object","properties":{"user_name":{"type":"string"}}}`}},
+	}
+	findings := []*ReviewFinding{
+		{
+			Category: ReviewRESTfulPath, Severity: "error", TargetType: ReviewTargetCollection, TargetID: 1, Message: "verb in path",
+			Patch: []jsonpatch.Operation{{Op: jsonpatch.OpReplace, Path: "/0/attrs/path", Value: "/users"}},
+		},
+		{
+			Category: ReviewDescription, Severity: "critical", TargetType: ReviewTargetSchema, TargetID: 2, Message: "missing description",
+			Patch: []jsonpatch.Operation{{Op: jsonpatch.OpReplace, Path: "/properties/missing/description", Value: "x"}},
+		},
+		{Category: ReviewNaming, TargetType: ReviewTargetSchema, TargetID: 3, Message: "unknown target"},
+		{Category: "other", TargetType: ReviewTargetCollection, TargetID: 1, Message: "unknown category"},
+		nil,
+	}
+
+	res := checkReviewFindings(findings, opt)
+	if len(res) != 2 {
+		t.Fatalf("got %d findings, want 2", len(res))
+	}
+	if len(res[0].Patch) != 1 {
+		t.Errorf("valid patch should be kept")
+	}
+	if res[1].Severity != "warning" {
+		t.Errorf("unknown severity should fall back to warning, got %s", res[1].Severity)
+	}
+	if res[1].Patch == nil || len(res[1].Patch) != 0 {
+		t.Errorf("invalid patch should be cleared")
+	}
+}
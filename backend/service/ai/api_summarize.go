@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,10 +9,9 @@ import (
 
 	"github.com/apicat/apicat/v2/backend/module/spec"
 	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
-	"github.com/gin-gonic/gin"
 )
 
-func APISummarize(ctx *gin.Context, collection *spec.Collection) (string, error) {
+func APISummarize(ctx context.Context, collection *spec.Collection) (string, error) {
 	if collection.Content == nil {
 		return "", errors.New("collection content is nil")
 	}
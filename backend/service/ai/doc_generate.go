@@ -6,33 +6,40 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/apicat/apicat/v2/backend/config"
 	"github.com/apicat/apicat/v2/backend/model/collection"
 	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
 
-	"github.com/apicat/apicat/v2/backend/module/llm"
 	llmcommon "github.com/apicat/apicat/v2/backend/module/llm/common"
 	"github.com/apicat/apicat/v2/backend/module/spec/plugin/openapi"
 	"github.com/gin-gonic/gin"
 )
 
 func DocGenerate(ctx *gin.Context, prompt string) (*collection.Collection, error) {
-	tpl := NewTpl("api_generate.tmpl", jwt.GetUser(ctx).Language, prompt)
-	messages, err := tpl.Prompt()
-	if err != nil {
-		return nil, err
-	}
+	return docGenerate(ctx, "api_generate.tmpl", prompt, nil)
+}
 
-	a, err := llm.NewLLM(config.Get().LLM.ToCfg())
+// DocGenerateStream 流式生成，每收到一段内容调用一次 onDelta，完成后返回解析后的结果
+func DocGenerateStream(ctx *gin.Context, prompt string, onDelta func(delta string) error) (*collection.Collection, error) {
+	return docGenerate(ctx, "api_generate.tmpl", prompt, onDelta)
+}
+
+// DocGenerateFromCode 根据 handler 代码片段推断接口文档，onDelta 为 nil 时不流式返回
+func DocGenerateFromCode(ctx *gin.Context, code string, onDelta func(delta string) error) (*collection.Collection, error) {
+	return docGenerate(ctx, "api_generate_code.tmpl", code, onDelta)
+}
+
+func docGenerate(ctx *gin.Context, tplName, prompt string, onDelta func(delta string) error) (*collection.Collection, error) {
+	tpl := NewTpl(tplName, jwt.GetUser(ctx).Language, prompt)
+	messages, err := tpl.Prompt()
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := a.ChatCompletionRequest(&llmcommon.ChatCompletionRequest{
+	result, err := chatCompletion(ctx, &llmcommon.ChatCompletionRequest{
 		Temperature: 0.3,
 		MaxTokens:   3000,
 		Messages:    messages,
-	})
+	}, onDelta)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+
+	llmcommon "github.com/apicat/apicat/v2/backend/module/llm/common"
+)
+
+var docsCitationRegexp = regexp.MustCompile(`\[(collection|schema):(\d+)\]`)
+
+// DocsCollection 回答问题参考的接口或文档，接口内容为解引用后的摘要
+type DocsCollection struct {
+	ID      uint
+	Title   string
+	Content string
+}
+
+type DocsSchema struct {
+	ID     uint
+	Name   string
+	Schema string
+}
+
+type DocsMessage struct {
+	// Role user 或 assistant
+	Role    string
+	Content string
+}
+
+type DocsAskOption struct {
+	ProjectTitle string
+	Collections  []DocsCollection
+	Schemas      []DocsSchema
+	// History 之前的对话，按时间顺序
+	History  []DocsMessage
+	Question string
+}
+
+// DocsAnswer 回答内容和引用的接口、模型ID
+type DocsAnswer struct {
+	Content       string
+	CollectionIDs []uint
+	SchemaIDs     []uint
+}
+
+// DocsAsk 根据检索到的文档回答问题
+func DocsAsk(ctx context.Context, language string, opt *DocsAskOption) (*DocsAnswer, error) {
+	messages, err := NewTpl("docs_ask.tmpl", language, opt).Prompt()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := chatCompletion(ctx, &llmcommon.ChatCompletionRequest{
+		Temperature: 0.2,
+		MaxTokens:   2000,
+		Messages:    messages,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if result == "" {
+		return nil, errors.New("empty content")
+	}
+
+	answer := &DocsAnswer{Content: result}
+	answer.CollectionIDs, answer.SchemaIDs = docsCitations(result, opt)
+	return answer, nil
+}
+
+// docsCitations 解析回答中引用的文档，只保留检索结果中存在的，按首次出现的顺序去重
+func docsCitations(answer string, opt *DocsAskOption) (collectionIDs, schemaIDs []uint) {
+	known := map[string]map[uint]bool{"collection": {}, "schema": {}}
+	for _, c := range opt.Collections {
+		known["collection"][c.ID] = true
+	}
+	for _, s := range opt.Schemas {
+		known["schema"][s.ID] = true
+	}
+
+	collectionIDs, schemaIDs = make([]uint, 0), make([]uint, 0)
+	for _, m := range docsCitationRegexp.FindAllStringSubmatch(answer, -1) {
+		id, err := strconv.ParseUint(m[2], 10, 64)
+		if err != nil || !known[m[1]][uint(id)] {
+			continue
+		}
+		delete(known[m[1]], uint(id))
+		if m[1] == "collection" {
+			collectionIDs = append(collectionIDs, uint(id))
+		} else {
+			schemaIDs = append(schemaIDs, uint(id))
+		}
+	}
+	return collectionIDs, schemaIDs
+}
@@ -0,0 +1,49 @@
+package ai
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocsCitations(t *testing.T) {
+	opt := &DocsAskOption{
+		Collections: []DocsCollection{{ID: 1}, {ID: 2}},
+		Schemas:     []DocsSchema{{ID: 3}},
+	}
+	answer := "Use `page` [collection:2] and `size` [collection:2][collection:1]. See [schema:3], not [collection:9] or [schema:1]."
+
+	collectionIDs, schemaIDs := docsCitations(answer, opt)
+	if !reflect.DeepEqual(collectionIDs, []uint{2, 1}) {
+		t.Errorf("collectionIDs = %v, want [2 1]", collectionIDs)
+	}
+	if !reflect.DeepEqual(schemaIDs, []uint{3}) {
+		t.Errorf("schemaIDs = %v, want [3]", schemaIDs)
+	}
+}
+
+func TestDocsAskPrompt(t *testing.T) {
+	messages, err := NewTpl("docs_ask.tmpl", "English", &DocsAskOption{
+		ProjectTitle: "Shop",
+		Collections:  []DocsCollection{{ID: 1, Title: "List orders", Content: "GET /orders"}},
+		History: []DocsMessage{
+			{Role: "user", Content: "hi"},
+			{Role: "assistant", Content: "hello"},
+		},
+		Question: "how do I paginate orders?",
+	}).Prompt()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roles := make([]string, len(messages))
+	for i, m := range messages {
+		roles[i] = m.Role
+	}
+	if !reflect.DeepEqual(roles, []string{"system", "user", "assistant", "user"}) {
+		t.Fatalf("roles = %v", roles)
+	}
+	if strings.TrimSpace(messages[3].Content) != "how do I paginate orders?" {
+		t.Errorf("question = %q", messages[3].Content)
+	}
+}
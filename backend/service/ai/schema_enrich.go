@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+
+	llmcommon "github.com/apicat/apicat/v2/backend/module/llm/common"
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+
+	"github.com/apicat/datagen"
+)
+
+const enrichSourceMaxLen = 6000
+
+// enrichMocks 各类型可用的 mock 规则，LLM 返回其他规则时会被忽略
+var enrichMocks = map[string][]string{
+	jsonschema.T_STR: {
+		"string", "word", "title", "phrase", "sentence", "paragraph", "markdown",
+		"name", "firstname", "lastname", "gender", "phone", "idcard",
+		"uuid", "domain", "url", "email", "httpmethod", "date", "time", "datetime",
+		"color", "imageurl", "city", "provinceorstate", "street", "zipcode", "address", "oneof", "regexp",
+	},
+	jsonschema.T_INT:  {"integer", "autoincrement", "timestamp", "httpcode", "oneof"},
+	jsonschema.T_NUM:  {"float", "integer", "longitude", "latitude", "oneof"},
+	jsonschema.T_BOOL: {"boolean"},
+}
+
+type schemaEnrichField struct {
+	Path        string
+	Type        string
+	Description string
+}
+
+type schemaEnrichContext struct {
+	Format      string
+	Source      string
+	Title       string
+	Description string
+	Fields      []schemaEnrichField
+	Mocks       map[string][]string
+}
+
+type schemaEnrichment struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Fields      map[string]struct {
+		Description string `json:"description"`
+		Mock        string `json:"mock"`
+	} `json:"fields"`
+}
+
+// SchemaEnrich 为确定性转换得到的模型补充标题、描述和 mock 规则，不会修改模型结构
+func SchemaEnrich(ctx context.Context, language, format, source string, s *jsonschema.Schema) error {
+	tplCtx := &schemaEnrichContext{
+		Format:      format,
+		Source:      source,
+		Title:       s.Title,
+		Description: s.Description,
+		Mocks:       enrichMocks,
+	}
+	if r := []rune(source); len(r) > enrichSourceMaxLen {
+		tplCtx.Source = string(r[:enrichSourceMaxLen])
+	}
+	fields := make(map[string]*jsonschema.Schema)
+	walkSchemaFields(s, "", func(path string, prop *jsonschema.Schema) {
+		fields[path] = prop
+		tplCtx.Fields = append(tplCtx.Fields, schemaEnrichField{Path: path, Type: prop.Type.First(), Description: prop.Description})
+	})
+
+	messages, err := NewTpl("schema_enrich.tmpl", language, tplCtx).Prompt()
+	if err != nil {
+		return err
+	}
+	result, err := chatCompletion(ctx, &llmcommon.ChatCompletionRequest{
+		Temperature: 0.2,
+		MaxTokens:   3000,
+		Messages:    messages,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	if result == "" {
+		return errors.New("empty content")
+	}
+
+	result = strings.TrimSuffix(strings.TrimSpace(result), "```")
+	var e schemaEnrichment
+	if err := json.Unmarshal([]byte(result), &e); err != nil {
+		slog.ErrorContext(ctx, "json.Unmarshal", "result", result)
+		return err
+	}
+	applySchemaEnrichment(s, fields, &e)
+	return nil
+}
+
+// applySchemaEnrichment 只填充为空的描述和 mock，源码中已有的注释优先
+func applySchemaEnrichment(s *jsonschema.Schema, fields map[string]*jsonschema.Schema, e *schemaEnrichment) {
+	if s.Title == "" {
+		s.Title = strings.TrimSpace(e.Title)
+	}
+	if s.Description == "" {
+		s.Description = strings.TrimSpace(e.Description)
+	}
+	for path, v := range e.Fields {
+		prop, ok := fields[path]
+		if !ok {
+			continue
+		}
+		if prop.Description == "" {
+			prop.Description = strings.TrimSpace(v.Description)
+		}
+		if prop.XMock == "" && len(prop.Enum) == 0 && validMock(prop.Type.First(), v.Mock) {
+			prop.XMock = v.Mock
+		}
+	}
+}
+
+func validMock(typ, mock string) bool {
+	if mock == "" {
+		return false
+	}
+	name := datagen.ParseFunction(mock).Name
+	for _, v := range enrichMocks[typ] {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// walkSchemaFields 按属性顺序遍历所有字段，路径形如 user.tags[] 或 items[].name
+func walkSchemaFields(s *jsonschema.Schema, prefix string, fn func(path string, prop *jsonschema.Schema)) {
+	if s == nil {
+		return
+	}
+	if s.Items != nil && !s.Items.IsBool() {
+		path := prefix + "[]"
+		fn(path, s.Items.Value())
+		walkSchemaFields(s.Items.Value(), path, fn)
+	}
+	names := s.XOrder
+	if len(names) == 0 {
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	for _, name := range names {
+		prop, ok := s.Properties[name]
+		if !ok {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		fn(path, prop)
+		walkSchemaFields(prop, path, fn)
+	}
+}
@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+)
+
+func TestApplySchemaEnrichment(t *testing.T) {
+	s, err := jsonschema.NewSchemaFromJson(`{"type":"object","x-apicat-orders":["id","email","tags"],"properties":{"id":{"type":"integer","description":"primary key"},"email":{"type":"string","x-apicat-mock":"email"},"tags":{"type":"array","items":{"type":"object","properties":{"name":{"type":"string"}}}}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := make(map[string]*jsonschema.Schema)
+	var paths []string
+	walkSchemaFields(s, "", func(path string, prop *jsonschema.Schema) {
+		fields[path] = prop
+		paths = append(paths, path)
+	})
+	if want := `["id","email","tags","tags[]","tags[].name"]`; toJSON(paths) != want {
+		t.Fatalf("walkSchemaFields = %s, want %s", toJSON(paths), want)
+	}
+
+	var e schemaEnrichment
+	if err := json.Unmarshal([]byte(`{"title":"User","description":"a user","fields":{
+		"id":{"description":"ID","mock":"autoincrement"},
+		"email":{"description":"email address","mock":"url"},
+		"tags[].name":{"description":"tag name","mock":"boolean"},
+		"missing":{"description":"x","mock":"word"}
+	}}`), &e); err != nil {
+		t.Fatal(err)
+	}
+	applySchemaEnrichment(s, fields, &e)
+
+	if s.Title != "User" || s.Description != "a user" {
+		t.Errorf("title and description should be filled, got %q %q", s.Title, s.Description)
+	}
+	if p := s.Properties["id"]; p.Description != "primary key" || p.XMock != "autoincrement" {
+		t.Errorf("existing description should be kept and mock filled, got %q %q", p.Description, p.XMock)
+	}
+	if p := s.Properties["email"]; p.Description != "email address" || p.XMock != "email" {
+		t.Errorf("existing mock should be kept, got %q %q", p.Description, p.XMock)
+	}
+	if p := fields["tags[].name"]; p.Description != "tag name" || p.XMock != "" {
+		t.Errorf("mock not matching the type should be ignored, got %q %q", p.Description, p.XMock)
+	}
+	if _, ok := s.Properties["missing"]; ok {
+		t.Errorf("unknown fields must not be added")
+	}
+}
+
+func toJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -4,35 +4,60 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 
-	"github.com/apicat/apicat/v2/backend/config"
 	"github.com/apicat/apicat/v2/backend/model/definition"
 	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
 
-	"github.com/apicat/apicat/v2/backend/module/llm"
 	llmcommon "github.com/apicat/apicat/v2/backend/module/llm/common"
 	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+	"github.com/apicat/apicat/v2/backend/module/spec/plugin/schemaconv"
 	"github.com/gin-gonic/gin"
 )
 
 func SchemaGenerate(ctx *gin.Context, prompt string) (*definition.DefinitionSchema, error) {
-	tpl := NewTpl("schema_generate.tmpl", jwt.GetUser(ctx).Language, prompt)
-	messages, err := tpl.Prompt()
+	return schemaGenerate(ctx, prompt, nil)
+}
+
+// SchemaGenerateStream 流式生成，每收到一段内容调用一次 onDelta，完成后返回解析后的结果
+func SchemaGenerateStream(ctx *gin.Context, prompt string, onDelta func(delta string) error) (*definition.DefinitionSchema, error) {
+	return schemaGenerate(ctx, prompt, onDelta)
+}
+
+// SchemaGenerateFromSource 先将源码确定性地转换为模型，再由 LLM 补充描述和 mock 规则，补充失败时返回转换结果
+func SchemaGenerateFromSource(ctx *gin.Context, format, source string) (*definition.DefinitionSchema, error) {
+	s, err := schemaconv.Convert(format, source)
 	if err != nil {
 		return nil, err
 	}
+	if err := SchemaEnrich(ctx, jwt.GetUser(ctx).Language, format, source, s); err != nil {
+		slog.WarnContext(ctx, "ai.SchemaEnrich", "err", err)
+	}
+	if s.Title == "" {
+		s.Title = "Untitled"
+	}
 
-	a, err := llm.NewLLM(config.Get().LLM.ToCfg())
+	return &definition.DefinitionSchema{
+		Name:        s.Title,
+		Description: s.Description,
+		Type:        "schema",
+		Schema:      s.ToJson(),
+	}, nil
+}
+
+func schemaGenerate(ctx *gin.Context, prompt string, onDelta func(delta string) error) (*definition.DefinitionSchema, error) {
+	tpl := NewTpl("schema_generate.tmpl", jwt.GetUser(ctx).Language, prompt)
+	messages, err := tpl.Prompt()
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := a.ChatCompletionRequest(&llmcommon.ChatCompletionRequest{
+	result, err := chatCompletion(ctx, &llmcommon.ChatCompletionRequest{
 		Temperature: 0.3,
 		MaxTokens:   3000,
 		Messages:    messages,
-	})
+	}, onDelta)
 	if err != nil {
 		return nil, err
 	}
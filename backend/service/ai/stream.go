@@ -0,0 +1,36 @@
+package ai
+
+import (
+	"context"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/module/llm"
+	llmcommon "github.com/apicat/apicat/v2/backend/module/llm/common"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+)
+
+// chatCompletion onDelta 不为空时使用流式请求，返回完整内容
+// 调用前检查团队配额，成功后按 ctx 中的归属记录用量
+func chatCompletion(ctx context.Context, r *llmcommon.ChatCompletionRequest, onDelta func(delta string) error) (string, error) {
+	if err := aiusage.Check(ctx, aiusage.ScopeFrom(ctx).TeamID); err != nil {
+		return "", err
+	}
+
+	cfg := config.Get().LLM
+	a, err := llm.NewLLM(cfg.ToCfg())
+	if err != nil {
+		return "", err
+	}
+
+	var result string
+	if onDelta == nil {
+		result, err = a.ChatCompletionRequest(r)
+	} else {
+		result, err = a.ChatCompletionStream(r, onDelta)
+	}
+	if err != nil {
+		return "", err
+	}
+	aiusage.Record(ctx, cfg.ModelName(), r, result)
+	return result, nil
+}
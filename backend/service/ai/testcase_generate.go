@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
@@ -8,17 +9,12 @@ import (
 	"log/slog"
 	"reflect"
 	"strings"
-	"time"
 
-	"github.com/apicat/apicat/v2/backend/config"
 	"github.com/apicat/apicat/v2/backend/i18n"
 	"github.com/apicat/apicat/v2/backend/model/collection"
-	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
 
-	"github.com/apicat/apicat/v2/backend/module/cache"
-	"github.com/apicat/apicat/v2/backend/module/llm"
 	llmcommon "github.com/apicat/apicat/v2/backend/module/llm/common"
-	"github.com/gin-gonic/gin"
+	"github.com/apicat/apicat/v2/backend/module/testcase"
 )
 
 type TSGenListOption struct {
@@ -31,6 +27,7 @@ type TSGenDetailOption struct {
 	APISummary      string
 	TestCaseTitle   string
 	TestCaseContent string
+	TestCaseSpec    string
 	Prompt          string
 }
 
@@ -41,14 +38,16 @@ type TestCase struct {
 	Steps       string `xml:"steps"`
 	Input       string `xml:"input"`
 	Output      string `xml:"output"`
+	// Spec 可执行的测试步骤，解析失败时为空
+	Spec string `xml:"spec"`
 }
 
-func TestCaseListGenerate(ctx *gin.Context, apiSummary string, testCases []string, prompt string) ([]string, error) {
+func TestCaseListGenerate(ctx context.Context, language, apiSummary string, testCases []string, prompt string) ([]string, error) {
 	var tpl *tpl
 	if len(testCases) == 0 {
-		tpl = NewTpl("testcase_generate.tmpl", jwt.GetUser(ctx).Language, apiSummary)
+		tpl = NewTpl("testcase_generate.tmpl", language, apiSummary)
 	} else {
-		tpl = NewTpl("testcase_more_generate.tmpl", jwt.GetUser(ctx).Language, TSGenListOption{
+		tpl = NewTpl("testcase_more_generate.tmpl", language, TSGenListOption{
 			APISummary: apiSummary,
 			TestCases:  testCases,
 			Prompt:     prompt,
@@ -60,16 +59,11 @@ func TestCaseListGenerate(ctx *gin.Context, apiSummary string, testCases []strin
 		return nil, err
 	}
 
-	a, err := llm.NewLLM(config.Get().LLM.ToCfg())
-	if err != nil {
-		return nil, err
-	}
-
-	result, err := a.ChatCompletionRequest(&llmcommon.ChatCompletionRequest{
+	result, err := chatCompletion(ctx, &llmcommon.ChatCompletionRequest{
 		Temperature: 0.3,
 		MaxTokens:   4000,
 		Messages:    messages,
-	})
+	}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -86,65 +80,22 @@ func TestCaseListGenerate(ctx *gin.Context, apiSummary string, testCases []strin
 	return list, nil
 }
 
-func TestCaseGenerate(language, projectID, apiSummary string, collectionID uint, testCaseTitleList []string) {
-	c, err := cache.NewCache(config.Get().Cache.ToCfg())
-	if err != nil {
-		return
-	}
-	cacheKey := GetGenerationKey(projectID, collectionID)
-	_, ok, _ := c.Get(cacheKey)
-	if ok {
-		return
-	}
-
-	for _, testCaseTitle := range testCaseTitleList {
-		c.Set(cacheKey, "generating", time.Second*30)
-		result, err := TestCaseDetailGenerate(language, apiSummary, testCaseTitle)
-		if err != nil {
-			slog.Error("TestCaseDetailGenerate", "err", err)
-			continue
-		}
-
-		content := fmt.Sprintf(
-			"`%s`<br>\n>%s\n### %s\n%s\n### %s\n%s\n### %s\n%s",
-			result.Type,
-			result.Description,
-			i18n.NewTran("testCase.Steps").TranslateIn(language),
-			result.Steps,
-			i18n.NewTran("testCase.Input").TranslateIn(language),
-			result.Input,
-			i18n.NewTran("testCase.Output").TranslateIn(language),
-			result.Output,
-		)
-
-		tc := &collection.TestCase{
-			ProjectID:    projectID,
-			CollectionID: collectionID,
-			Title:        result.Purpose,
-			Content:      content,
-		}
-		if err := tc.CreateWithoutCtx(); err != nil {
-			slog.Error("tc.CreateWithoutCtx", "err", err)
-		}
-	}
-}
-
-func TestCaseGeneratingStatus(projectID string, collectionID uint) bool {
-	c, err := cache.NewCache(config.Get().Cache.ToCfg())
-	if err != nil {
-		return false
-	}
-
-	cacheKey := GetGenerationKey(projectID, collectionID)
-	_, ok, _ := c.Get(cacheKey)
-	return ok
+// Markdown 生成的测试用例保存为 Markdown 内容
+func (t *TestCase) Markdown(language string) string {
+	return fmt.Sprintf(
+		"`%s`<br>\n>%s\n### %s\n%s\n### %s\n%s\n### %s\n%s",
+		t.Type,
+		t.Description,
+		i18n.NewTran("testCase.Steps").TranslateIn(language),
+		t.Steps,
+		i18n.NewTran("testCase.Input").TranslateIn(language),
+		t.Input,
+		i18n.NewTran("testCase.Output").TranslateIn(language),
+		t.Output,
+	)
 }
 
-func GetGenerationKey(projectID string, collectionID uint) string {
-	return fmt.Sprintf("ai_test_case_generate_%s_%d", projectID, collectionID)
-}
-
-func TestCaseDetailGenerate(language, apiSummary, testCaseTitle string) (*TestCase, error) {
+func TestCaseDetailGenerate(ctx context.Context, language, apiSummary, testCaseTitle string) (*TestCase, error) {
 	tpl := NewTpl("testcase_detail_generate.tmpl", langMap[language], TSGenDetailOption{
 		APISummary:    apiSummary,
 		TestCaseTitle: testCaseTitle,
@@ -154,16 +105,11 @@ func TestCaseDetailGenerate(language, apiSummary, testCaseTitle string) (*TestCa
 		return nil, err
 	}
 
-	a, err := llm.NewLLM(config.Get().LLM.ToCfg())
-	if err != nil {
-		return nil, err
-	}
-
-	result, err := a.ChatCompletionRequest(&llmcommon.ChatCompletionRequest{
+	result, err := chatCompletion(ctx, &llmcommon.ChatCompletionRequest{
 		Temperature: 0.3,
 		MaxTokens:   2000,
 		Messages:    messages,
-	})
+	}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -180,25 +126,21 @@ func TestCaseDetailGenerate(language, apiSummary, testCaseTitle string) (*TestCa
 		return nil, err
 	}
 
-	v := reflect.ValueOf(ts)
-	value := v.Elem()
-	for i := 0; i < value.NumField(); i++ {
-		field := value.Field(i)
-		fieldName := value.Type().Field(i).Name
-		if field.Kind() == reflect.String && field.String() == "" {
-			return nil, fmt.Errorf("missing element: %s, original dats: %s", fieldName, result)
-		}
+	if err := ts.check(result); err != nil {
+		return nil, err
 	}
 	ts.removeIndent()
+	ts.Spec = normalizeSpec(ts.Spec)
 
 	return ts, nil
 }
 
-func TestCaseDetailRegenerate(testCase *collection.TestCase, language, apiSummary, prompt string) (*TestCase, error) {
+func TestCaseDetailRegenerate(ctx context.Context, testCase *collection.TestCase, language, apiSummary, prompt string) (*TestCase, error) {
 	tpl := NewTpl("testcase_detail_regenerate.tmpl", langMap[language], TSGenDetailOption{
 		APISummary:      apiSummary,
 		TestCaseTitle:   testCase.Title,
 		TestCaseContent: testCase.Content,
+		TestCaseSpec:    testCase.Spec,
 		Prompt:          prompt,
 	})
 	messages, err := tpl.Prompt()
@@ -206,16 +148,11 @@ func TestCaseDetailRegenerate(testCase *collection.TestCase, language, apiSummar
 		return nil, err
 	}
 
-	a, err := llm.NewLLM(config.Get().LLM.ToCfg())
-	if err != nil {
-		return nil, err
-	}
-
-	result, err := a.ChatCompletionRequest(&llmcommon.ChatCompletionRequest{
+	result, err := chatCompletion(ctx, &llmcommon.ChatCompletionRequest{
 		Temperature: 0.3,
 		MaxTokens:   4000,
 		Messages:    messages,
-	})
+	}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -232,17 +169,44 @@ func TestCaseDetailRegenerate(testCase *collection.TestCase, language, apiSummar
 		return nil, err
 	}
 
-	v := reflect.ValueOf(ts)
-	value := v.Elem()
+	if err := ts.check(result); err != nil {
+		return nil, err
+	}
+	ts.removeIndent()
+	ts.Spec = normalizeSpec(ts.Spec)
+	return ts, nil
+}
+
+// check 除可执行步骤外的元素都不能为空
+func (t *TestCase) check(result string) error {
+	value := reflect.ValueOf(t).Elem()
 	for i := 0; i < value.NumField(); i++ {
 		field := value.Field(i)
 		fieldName := value.Type().Field(i).Name
+		if fieldName == "Spec" {
+			continue
+		}
 		if field.Kind() == reflect.String && field.String() == "" {
-			return nil, fmt.Errorf("missing element: %s, original dats: %s", fieldName, result)
+			return fmt.Errorf("missing element: %s, original dats: %s", fieldName, result)
 		}
 	}
-	ts.removeIndent()
-	return ts, nil
+	return nil
+}
+
+// normalizeSpec 校验生成的可执行步骤，不合法时丢弃，仅保留文字描述
+func normalizeSpec(spec string) string {
+	spec = strings.TrimSpace(spec)
+	spec = strings.TrimPrefix(spec, "```json")
+	spec = strings.TrimSuffix(strings.TrimPrefix(spec, "```"), "```")
+	if strings.TrimSpace(spec) == "" {
+		return ""
+	}
+	c, err := testcase.Parse(spec)
+	if err != nil {
+		slog.Warn("testcase.Parse", "err", err, "spec", spec)
+		return ""
+	}
+	return c.String()
 }
 
 func (t *TestCase) removeIndent() {
@@ -0,0 +1,55 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	llmcommon "github.com/apicat/apicat/v2/backend/module/llm/common"
+)
+
+// Translate 将文本翻译为目标语言，返回结果与输入一一对应
+func Translate(ctx context.Context, locale string, texts []string) ([]string, error) {
+	if len(texts) == 0 {
+		return []string{}, nil
+	}
+
+	language := langMap[locale]
+	if language == "" {
+		language = locale
+	}
+	input, err := json.Marshal(texts)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := NewTpl("translate.tmpl", language, string(input)).Prompt()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := chatCompletion(ctx, &llmcommon.ChatCompletionRequest{
+		Temperature: 0.1,
+		MaxTokens:   4000,
+		Messages:    messages,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if result == "" {
+		return nil, errors.New("empty content")
+	}
+
+	result = strings.TrimSuffix(strings.TrimSpace(result), "```")
+	var translated []string
+	if err := json.Unmarshal([]byte(result), &translated); err != nil {
+		slog.ErrorContext(ctx, "json.Unmarshal", "result", result)
+		return nil, err
+	}
+	if len(translated) != len(texts) {
+		return nil, fmt.Errorf("got %d translations for %d texts", len(translated), len(texts))
+	}
+	return translated, nil
+}
@@ -0,0 +1,184 @@
+package aiusage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+
+	"github.com/apicat/apicat/v2/backend/model/aiusage"
+	"github.com/apicat/apicat/v2/backend/model/sysconfig"
+	llmcommon "github.com/apicat/apicat/v2/backend/module/llm/common"
+)
+
+const (
+	FeatureCollectionGenerate = "collection_generate"
+	FeatureSchemaGenerate     = "schema_generate"
+	FeatureTestCaseGenerate   = "testcase_generate"
+	FeatureTestCaseRegenerate = "testcase_regenerate"
+	FeatureAPIReview          = "api_review"
+	FeatureDocsAsk            = "docs_ask"
+	FeatureTranslate          = "project_translate"
+	FeatureEmbedding          = "embedding"
+)
+
+const pricingType = "ai_pricing"
+
+// ErrQuotaExceeded 团队当前周期的 token 用量已达到配额
+var ErrQuotaExceeded = errors.New("ai quota exceeded")
+
+type scopeKey struct{}
+
+// Scope AI 调用的归属，用于用量统计和配额限制
+type Scope struct {
+	TeamID    string
+	ProjectID string
+	UserID    uint
+	Feature   string
+}
+
+// Pricing 每百万 token 的价格，用于在报表中估算费用
+type Pricing struct {
+	Currency        string  `json:"currency"`
+	PromptPrice     float64 `json:"promptPrice"`
+	CompletionPrice float64 `json:"completionPrice"`
+}
+
+func WithScope(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+func ScopeFrom(ctx context.Context) Scope {
+	s, _ := ctx.Value(scopeKey{}).(Scope)
+	return s
+}
+
+// Check 调用模型前检查团队配额，未归属团队的调用不限制
+func Check(ctx context.Context, teamID string) error {
+	if teamID == "" {
+		return nil
+	}
+	q, err := aiusage.GetTeamQuota(ctx, teamID)
+	if err != nil || q == nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, limit := range []struct {
+		tokens int64
+		since  time.Time
+	}{
+		{q.DailyTokens, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())},
+		{q.MonthlyTokens, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())},
+	} {
+		if limit.tokens <= 0 {
+			continue
+		}
+		used, err := aiusage.SumTeamTokens(ctx, teamID, limit.since)
+		if err != nil {
+			return err
+		}
+		if used >= limit.tokens {
+			return ErrQuotaExceeded
+		}
+	}
+	return nil
+}
+
+// Record 记录一次调用的用量，模型未返回用量时按内容长度估算
+func Record(ctx context.Context, model string, r *llmcommon.ChatCompletionRequest, result string) {
+	s := ScopeFrom(ctx)
+	u := &aiusage.AIUsage{
+		TeamID:           s.TeamID,
+		ProjectID:        s.ProjectID,
+		UserID:           s.UserID,
+		Feature:          s.Feature,
+		Model:            model,
+		PromptTokens:     r.Usage.PromptTokens,
+		CompletionTokens: r.Usage.CompletionTokens,
+	}
+	if u.Feature == "" {
+		u.Feature = "unknown"
+	}
+	if u.PromptTokens == 0 && u.CompletionTokens == 0 {
+		for _, m := range r.Messages {
+			u.PromptTokens += EstimateTokens(m.Content)
+		}
+		u.CompletionTokens = EstimateTokens(result)
+		u.Estimated = true
+	}
+	create(ctx, u)
+}
+
+// RecordEmbedding 记录一次生成向量的用量，向量只消耗输入 token
+func RecordEmbedding(ctx context.Context, model string, r *llmcommon.EmbeddingRequest) {
+	s := ScopeFrom(ctx)
+	u := &aiusage.AIUsage{
+		TeamID:       s.TeamID,
+		ProjectID:    s.ProjectID,
+		UserID:       s.UserID,
+		Feature:      FeatureEmbedding,
+		Model:        model,
+		PromptTokens: r.Usage.PromptTokens,
+	}
+	if u.PromptTokens == 0 {
+		for _, v := range r.Input {
+			u.PromptTokens += EstimateTokens(v)
+		}
+		u.Estimated = true
+	}
+	create(ctx, u)
+}
+
+func create(ctx context.Context, u *aiusage.AIUsage) {
+	if err := u.Create(context.WithoutCancel(ctx)); err != nil {
+		slog.ErrorContext(ctx, "aiusage.Create", "err", err)
+	}
+}
+
+// EstimateTokens 粗略估算 token 数量，英文约 4 个字符一个 token，中日韩文字约一个字一个 token
+func EstimateTokens(s string) int {
+	var ascii, other int
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+func GetPricing(ctx context.Context) (*Pricing, error) {
+	p := &Pricing{Currency: "USD"}
+	sc := &sysconfig.Sysconfig{Type: pricingType}
+	exist, err := sc.GetByUse(ctx)
+	if err != nil || !exist {
+		return p, err
+	}
+	if err := json.Unmarshal([]byte(sc.Config), p); err != nil {
+		return nil, fmt.Errorf("invalid pricing config: %w", err)
+	}
+	return p, nil
+}
+
+func SetPricing(ctx context.Context, p *Pricing) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return sysconfig.UpdateOrCreate(ctx, &sysconfig.Sysconfig{
+		Type:      pricingType,
+		Driver:    "default",
+		BeingUsed: true,
+		Config:    string(b),
+	})
+}
+
+// Cost 按价格估算费用
+func (p *Pricing) Cost(promptTokens, completionTokens int64) float64 {
+	return (float64(promptTokens)*p.PromptPrice + float64(completionTokens)*p.CompletionPrice) / 1e6
+}
@@ -0,0 +1,27 @@
+package aiusage
+
+import "testing"
+
+func TestEstimateTokens(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abcd", 1},
+		{"hello world", 3},
+		{"接口文档", 4},
+		{"get 用户", 3},
+	} {
+		if got := EstimateTokens(tc.in); got != tc.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestPricingCost(t *testing.T) {
+	p := &Pricing{PromptPrice: 3, CompletionPrice: 15}
+	if got := p.Cost(1000000, 200000); got != 6 {
+		t.Fatalf("cost = %v, want 6", got)
+	}
+}
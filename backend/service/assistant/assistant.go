@@ -0,0 +1,166 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/embedding"
+	"github.com/apicat/apicat/v2/backend/service/ai"
+	embeddingservice "github.com/apicat/apicat/v2/backend/service/embedding"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+)
+
+const (
+	// maxDocs 最多参考的文档数量，受模型上下文长度限制
+	maxDocs = 8
+	// maxDocLength 单个文档的最大长度，超出部分截断
+	maxDocLength = 6000
+)
+
+// Docs 检索到的与问题相关的接口、文档和模型
+type Docs struct {
+	Collections []*collection.Collection
+	Schemas     []*definition.DefinitionSchema
+}
+
+// Retrieve 检索项目中与问题相关的内容，配置了向量模型时按语义检索，否则按关键词匹配
+// collectionID 不为0时只使用该接口，用于单个文档的分享
+func Retrieve(ctx context.Context, projectID string, collectionID uint, question string) (*Docs, error) {
+	if collectionID != 0 {
+		list, err := collection.GetCollections(ctx, projectID, collectionID)
+		if err != nil {
+			return nil, err
+		}
+		return &Docs{Collections: list}, nil
+	}
+
+	docs, err := semanticRetrieve(ctx, projectID, question)
+	if err == nil && len(docs.Collections)+len(docs.Schemas) > 0 {
+		return docs, nil
+	}
+	if err != nil && !errors.Is(err, embeddingservice.ErrNotConfigured) {
+		slog.WarnContext(ctx, "assistant.semanticRetrieve", "err", err)
+	}
+	return keywordRetrieve(ctx, projectID, question)
+}
+
+func semanticRetrieve(ctx context.Context, projectID, question string) (*Docs, error) {
+	hits, err := embeddingservice.Search(ctx, []string{projectID}, question, maxDocs)
+	if err != nil {
+		return nil, err
+	}
+
+	var collectionIDs, schemaIDs []uint
+	for _, h := range hits {
+		if h.TargetType == embedding.TargetSchema {
+			schemaIDs = append(schemaIDs, h.TargetID)
+		} else {
+			collectionIDs = append(collectionIDs, h.TargetID)
+		}
+	}
+
+	docs := &Docs{}
+	if len(collectionIDs) > 0 {
+		if docs.Collections, err = collection.GetCollections(ctx, projectID, collectionIDs...); err != nil {
+			return nil, err
+		}
+	}
+	if len(schemaIDs) > 0 {
+		if docs.Schemas, err = definition.GetDefinitionSchemas(ctx, projectID, schemaIDs...); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// keywordRetrieve 按问题中的关键词在标题、路径和内容中出现的次数排序
+func keywordRetrieve(ctx context.Context, projectID, question string) (*Docs, error) {
+	collections, err := collection.GetCollections(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	words := keywords(question)
+	type scored struct {
+		c     *collection.Collection
+		score int
+	}
+	list := make([]scored, 0, len(collections))
+	for _, c := range collections {
+		if c.Type == collection.CategoryType {
+			continue
+		}
+		title := strings.ToLower(c.Title + " " + c.Path)
+		content := strings.ToLower(c.Content)
+		score := 0
+		for _, w := range words {
+			// 标题和路径中的匹配更重要
+			score += strings.Count(title, w)*5 + strings.Count(content, w)
+		}
+		list = append(list, scored{c: c, score: score})
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].score > list[j].score
+	})
+
+	docs := &Docs{}
+	for _, v := range list {
+		if len(docs.Collections) >= maxDocs {
+			break
+		}
+		docs.Collections = append(docs.Collections, v.c)
+	}
+	return docs, nil
+}
+
+// keywords 拆分问题中的英文单词和连续的非英文字符，忽略过短的词
+func keywords(question string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(question), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '-' && r != '_'
+	})
+
+	res := make([]string, 0, len(fields))
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		if len([]rune(f)) < 2 || seen[f] {
+			continue
+		}
+		seen[f] = true
+		res = append(res, f)
+	}
+	return res
+}
+
+// BuildAskOption 将检索到的内容解引用后作为回答问题的参考
+func BuildAskOption(ctx context.Context, docs *Docs) *ai.DocsAskOption {
+	opt := &ai.DocsAskOption{}
+	for _, c := range docs.Collections {
+		content := c.Content
+		if c.Type == collection.HttpType {
+			if sc, err := relations.CollectionDerefWithSpec(ctx, c); err == nil {
+				if summary, err := ai.APISummarize(ctx, sc); err == nil {
+					content = summary
+				}
+			}
+		}
+		opt.Collections = append(opt.Collections, ai.DocsCollection{ID: c.ID, Title: c.Title, Content: truncate(content)})
+	}
+	for _, ds := range docs.Schemas {
+		opt.Schemas = append(opt.Schemas, ai.DocsSchema{ID: ds.ID, Name: ds.Name, Schema: truncate(ds.Schema)})
+	}
+	return opt
+}
+
+func truncate(s string) string {
+	r := []rune(s)
+	if len(r) > maxDocLength {
+		return string(r[:maxDocLength])
+	}
+	return s
+}
@@ -0,0 +1,14 @@
+package assistant
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeywords(t *testing.T) {
+	got := keywords("Which endpoints need the X-Tenant header? Which 订单 接口?")
+	want := []string{"which", "endpoints", "need", "the", "x-tenant", "header", "订单", "接口"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("keywords() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Entry 一次变更操作
+type Entry struct {
+	Action     string
+	TargetType string
+	TargetID   any
+	TargetName string
+	Before     Fields
+	After      Fields
+}
+
+// Record 记录审计日志，操作人、团队、项目从请求上下文中获取
+// 记录失败只打印日志，不影响业务请求
+func Record(ctx *gin.Context, e *Entry) {
+	u := jwt.GetUser(ctx)
+	if u == nil {
+		return
+	}
+
+	log := &audit.AuditLog{
+		UserID:     u.ID,
+		UserName:   u.Name,
+		UserEmail:  u.Email,
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   fmt.Sprint(e.TargetID),
+		TargetName: e.TargetName,
+		IP:         ctx.ClientIP(),
+	}
+	if t := access.GetSelfTeam(ctx); t != nil {
+		log.TeamID = t.ID
+	}
+	if p := access.GetSelfProject(ctx); p != nil {
+		log.ProjectID = p.ID
+		log.TeamID = p.TeamID
+	}
+
+	if changes := Diff(e.Before, e.After); len(changes) > 0 {
+		if b, err := json.Marshal(changes); err == nil {
+			log.Changes = string(b)
+		}
+	}
+
+	if err := log.Create(ctx); err != nil {
+		slog.ErrorContext(ctx, "audit.Create", "err", err, "action", e.Action, "target", e.TargetType)
+	}
+}
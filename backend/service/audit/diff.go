@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/apicat/apicat/v2/backend/model/audit"
+)
+
+// Fields 参与审计的字段快照
+type Fields map[string]any
+
+const maxValueLength = 120
+
+// 字段名包含以下关键字时脱敏
+var sensitiveKeywords = []string{"password", "secret", "token", "key"}
+
+// Diff 比较前后两个字段快照，返回变更摘要，before为空表示创建，after为空表示删除
+func Diff(before, after Fields) []audit.Change {
+	keys := make(map[string]struct{})
+	for k := range before {
+		keys[k] = struct{}{}
+	}
+	for k := range after {
+		keys[k] = struct{}{}
+	}
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	changes := make([]audit.Change, 0)
+	for _, k := range names {
+		b, bok := before[k]
+		a, aok := after[k]
+		bs, as := stringify(b, bok), stringify(a, aok)
+		if bok == aok && bs == as {
+			continue
+		}
+		if isSensitive(k) {
+			bs, as = mask(bs), mask(as)
+		}
+		changes = append(changes, audit.Change{
+			Field:  k,
+			Before: truncate(bs),
+			After:  truncate(as),
+		})
+	}
+	return changes
+}
+
+func stringify(v any, ok bool) string {
+	if !ok || v == nil {
+		return ""
+	}
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func isSensitive(field string) bool {
+	f := strings.ToLower(field)
+	for _, k := range sensitiveKeywords {
+		if strings.Contains(f, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func mask(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "******"
+}
+
+func truncate(s string) string {
+	r := []rune(s)
+	if len(r) <= maxValueLength {
+		return s
+	}
+	return string(r[:maxValueLength]) + "..."
+}
@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	changes := Diff(
+		Fields{"title": "pets", "content": "a", "secretKey": "abc", "same": 1},
+		Fields{"title": "pets v2", "content": strings.Repeat("b", 200), "secretKey": "def", "same": 1, "public": true},
+	)
+
+	want := map[string][2]string{
+		"content":   {"a", strings.Repeat("b", maxValueLength) + "..."},
+		"public":    {"", "true"},
+		"secretKey": {"******", "******"},
+		"title":     {"pets", "pets v2"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d: %+v", len(changes), len(want), changes)
+	}
+	for i, c := range changes {
+		w, ok := want[c.Field]
+		if !ok {
+			t.Fatalf("unexpected change %+v", c)
+		}
+		if c.Before != w[0] || c.After != w[1] {
+			t.Errorf("change %s = (%q, %q), want (%q, %q)", c.Field, c.Before, c.After, w[0], w[1])
+		}
+		if i > 0 && changes[i-1].Field > c.Field {
+			t.Errorf("changes not sorted: %s before %s", changes[i-1].Field, c.Field)
+		}
+	}
+}
+
+func TestDiffCreateAndDelete(t *testing.T) {
+	if c := Diff(nil, Fields{"title": "pets"}); len(c) != 1 || c[0].Before != "" || c[0].After != "pets" {
+		t.Errorf("create diff = %+v", c)
+	}
+	if c := Diff(Fields{"title": "pets"}, nil); len(c) != 1 || c[0].Before != "pets" || c[0].After != "" {
+		t.Errorf("delete diff = %+v", c)
+	}
+	if c := Diff(Fields{"title": "pets"}, Fields{"title": "pets"}); len(c) != 0 {
+		t.Errorf("unchanged diff = %+v", c)
+	}
+}
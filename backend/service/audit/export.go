@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model/audit"
+)
+
+var csvHeader = []string{
+	"Time", "Project ID", "Operator", "Operator Email", "Action",
+	"Target Type", "Target ID", "Target Name", "Changes", "IP",
+}
+
+// WriteCSV 将符合条件的审计日志以CSV格式写入w
+func WriteCSV(ctx context.Context, w io.Writer, opt *audit.ListOption) error {
+	// UTF-8 BOM，避免Excel打开中文乱码
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	err := audit.EachAuditLogs(ctx, opt, func(list []*audit.AuditLog) error {
+		for _, l := range list {
+			record := []string{
+				l.CreatedAt.Format(time.RFC3339),
+				l.ProjectID,
+				l.UserName,
+				l.UserEmail,
+				l.Action,
+				l.TargetType,
+				l.TargetID,
+				l.TargetName,
+				formatChanges(l.GetChanges()),
+				l.IP,
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatChanges(changes []audit.Change) string {
+	list := make([]string, len(changes))
+	for i, c := range changes {
+		list[i] = fmt.Sprintf("%s: %q -> %q", c.Field, c.Before, c.After)
+	}
+	return strings.Join(list, "; ")
+}
@@ -0,0 +1,326 @@
+package branch
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/iteration"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/proposal"
+	"github.com/apicat/apicat/v2/backend/model/team"
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	proposalservice "github.com/apicat/apicat/v2/backend/service/proposal"
+	"github.com/apicat/apicat/v2/backend/service/reference"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+)
+
+// BaseFields 创建分支时主线的内容，分支中新建的条目没有基准
+func BaseFields(item *iteration.IterationBranchItem) Fields {
+	if item.IsCreated() {
+		return nil
+	}
+	if item.TargetType == iteration.BranchTargetSchema {
+		return SchemaFields(item.BaseTitle, item.BaseDescription, item.BaseContent)
+	}
+	return CollectionFields(item.BaseTitle, item.BaseContent)
+}
+
+// BranchFields 分支中的内容，分支中删除时为nil
+func BranchFields(item *iteration.IterationBranchItem) Fields {
+	if item.Deleted {
+		return nil
+	}
+	if item.TargetType == iteration.BranchTargetSchema {
+		return SchemaFields(item.Title, item.Description, item.Content)
+	}
+	return CollectionFields(item.Title, item.Content)
+}
+
+// Spec 迭代分支的快照，在主线当前的快照上应用分支中的修改，用于合并请求展示项目对比
+func Spec(ctx context.Context, p *project.Project, items []*iteration.IterationBranchItem) (*spec.Spec, error) {
+	s, err := relations.ProjectSpec(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		switch item.TargetType {
+		case iteration.BranchTargetCollection:
+			if err := applyCollection(s, item); err != nil {
+				return nil, err
+			}
+		case iteration.BranchTargetSchema:
+			if err := applySchema(s, item); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s, nil
+}
+
+func applyCollection(s *spec.Spec, item *iteration.IterationBranchItem) error {
+	index := -1
+	if !item.IsCreated() {
+		for i, c := range s.Collections {
+			if c.ID == int64(item.TargetID) {
+				index = i
+				break
+			}
+		}
+	}
+
+	if item.Deleted {
+		if index >= 0 {
+			s.Collections = append(s.Collections[:index], s.Collections[index+1:]...)
+		}
+		return nil
+	}
+
+	c := &collection.Collection{
+		ID:       item.TargetID,
+		ParentID: item.ParentID,
+		Title:    item.Title,
+		Type:     item.Type,
+		Content:  item.Content,
+	}
+	sc, err := c.ToSpec()
+	if err != nil {
+		return err
+	}
+	if index >= 0 {
+		s.Collections[index] = sc
+	} else {
+		s.Collections = append(s.Collections, sc)
+	}
+	return nil
+}
+
+func applySchema(s *spec.Spec, item *iteration.IterationBranchItem) error {
+	if !item.IsCreated() {
+		s.Definitions.Schemas.DelByID(int64(item.TargetID))
+	}
+	if item.Deleted {
+		return nil
+	}
+
+	ds := &definition.DefinitionSchema{
+		ID:          item.TargetID,
+		ParentID:    item.ParentID,
+		Name:        item.Title,
+		Description: item.Description,
+		Type:        item.Type,
+		Schema:      item.Content,
+	}
+	m, err := ds.ToSpec()
+	if err != nil {
+		return err
+	}
+	s.Definitions.Schemas = append(s.Definitions.Schemas, m)
+	return nil
+}
+
+// Change 分支中一个条目的合并结果
+// Collection和Schema为主线当前的接口和模型，主线中不存在时为nil，合并写入后为写入的内容
+type Change struct {
+	Item       *iteration.IterationBranchItem
+	Collection *collection.Collection
+	Schema     *definition.DefinitionSchema
+	Mainline   Fields
+	Merged     Fields
+	Conflicts  []Conflict
+}
+
+// Prepare 计算分支中每个条目与主线的合并结果，resolutions按条目ID指定各字段冲突的解决方式
+func Prepare(ctx context.Context, projectID string, items []*iteration.IterationBranchItem, resolutions map[uint]map[string]string) ([]*Change, error) {
+	changes := make([]*Change, 0, len(items))
+	for _, item := range items {
+		c := &Change{Item: item}
+		if !item.IsCreated() {
+			if err := c.loadMainline(ctx, projectID); err != nil {
+				return nil, err
+			}
+		}
+		c.Merged, c.Conflicts = Merge(BaseFields(item), c.Mainline, BranchFields(item), resolutions[item.ID])
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+func (c *Change) loadMainline(ctx context.Context, projectID string) error {
+	if c.Item.TargetType == iteration.BranchTargetSchema {
+		ds := &definition.DefinitionSchema{ID: c.Item.TargetID, ProjectID: projectID}
+		exist, err := ds.Get(ctx)
+		if err != nil {
+			return err
+		}
+		if exist {
+			c.Schema = ds
+			c.Mainline = SchemaFields(ds.Name, ds.Description, ds.Schema)
+		}
+		return nil
+	}
+
+	col := &collection.Collection{ID: c.Item.TargetID, ProjectID: projectID}
+	exist, err := col.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if exist {
+		c.Collection = col
+		c.Mainline = CollectionFields(col.Title, col.Content)
+	}
+	return nil
+}
+
+// HasConflicts 是否存在未解决的冲突
+func HasConflicts(changes []*Change) bool {
+	for _, c := range changes {
+		if len(c.Conflicts) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Action 合并时对主线的操作，不需要修改时返回空
+func (c *Change) Action() string {
+	switch {
+	case c.Merged == nil && c.Mainline == nil:
+		return ""
+	case c.Merged == nil:
+		return audit.ActionDelete
+	case c.Mainline == nil:
+		return audit.ActionCreate
+	case c.Merged.Equal(c.Mainline):
+		return ""
+	}
+	return audit.ActionUpdate
+}
+
+// Capability 将修改合并到主线所需的能力
+func (c *Change) Capability() project.Capability {
+	switch {
+	case c.Action() == audit.ActionDelete:
+		return project.CapabilityDelete
+	case c.Item.TargetType == iteration.BranchTargetSchema:
+		return project.CapabilityEditSchema
+	}
+	return project.CapabilityEditCollection
+}
+
+// CheckProtected 受保护的主线条目只能通过修改提案修改，合并时不能直接覆盖或删除
+func (c *Change) CheckProtected(ctx context.Context, projectID string) error {
+	switch action := c.Action(); {
+	case action == audit.ActionDelete && c.Item.TargetType == iteration.BranchTargetSchema:
+		return proposalservice.CheckSchemaDelete(ctx, c.Schema)
+	case action == audit.ActionDelete:
+		return proposalservice.CheckCollectionDelete(ctx, c.Collection)
+	case action == audit.ActionUpdate && c.Item.TargetType == iteration.BranchTargetSchema:
+		return proposalservice.CheckProtected(ctx, projectID, proposal.TargetSchema, c.Schema.ID)
+	case action == audit.ActionUpdate:
+		return proposalservice.CheckProtected(ctx, projectID, proposal.TargetCollection, c.Collection.ID)
+	}
+	return nil
+}
+
+// Apply 将合并结果写入主线，与直接编辑主线一样维护历史记录和引用关系
+func (c *Change) Apply(ctx context.Context, projectID string, tm *team.TeamMember) error {
+	action := c.Action()
+	if action == "" {
+		return nil
+	}
+	if err := c.CheckProtected(ctx, projectID); err != nil {
+		return err
+	}
+	if c.Item.TargetType == iteration.BranchTargetSchema {
+		return c.applySchema(ctx, action, projectID, tm)
+	}
+	return c.applyCollection(ctx, action, projectID, tm)
+}
+
+func (c *Change) applyCollection(ctx context.Context, action, projectID string, tm *team.TeamMember) error {
+	switch action {
+	case audit.ActionDelete:
+		return relations.DeleteCollections(ctx, projectID, c.Collection, tm)
+	case audit.ActionCreate:
+		title, content := c.Merged.Collection()
+		c.Collection = &collection.Collection{
+			ProjectID: projectID,
+			ParentID:  collectionParentID(ctx, projectID, c.Item.ParentID),
+			Title:     title,
+			Type:      c.Item.Type,
+			Content:   content,
+		}
+		if err := c.Collection.Create(ctx, tm); err != nil {
+			return err
+		}
+		if err := reference.UpdateCollectionRef(ctx, c.Collection, nil, nil, nil); err != nil {
+			slog.ErrorContext(ctx, "reference.UpdateCollectionRef", "err", err)
+		}
+		return nil
+	}
+
+	title, content := c.Merged.Collection()
+	return relations.UpdateCollection(ctx, c.Collection, title, content, tm.ID)
+}
+
+func (c *Change) applySchema(ctx context.Context, action, projectID string, tm *team.TeamMember) error {
+	switch action {
+	case audit.ActionDelete:
+		if err := c.Schema.Delete(ctx, tm); err != nil {
+			return err
+		}
+		// 引用了该模型的地方展开引用，避免合并后内容丢失
+		if err := reference.DerefSchema(ctx, c.Schema, true); err != nil {
+			slog.ErrorContext(ctx, "reference.DerefSchema", "err", err)
+		}
+		return nil
+	case audit.ActionCreate:
+		name, description, schema := c.Merged.Schema()
+		c.Schema = &definition.DefinitionSchema{
+			ProjectID:   projectID,
+			ParentID:    schemaParentID(ctx, projectID, c.Item.ParentID),
+			Name:        name,
+			Description: description,
+			Type:        c.Item.Type,
+			Schema:      schema,
+		}
+		if err := c.Schema.Create(ctx, tm); err != nil {
+			return err
+		}
+		if err := reference.UpdateSchemaRef(ctx, c.Schema, nil); err != nil {
+			slog.ErrorContext(ctx, "reference.UpdateSchemaRef", "err", err)
+		}
+		return nil
+	}
+
+	name, description, schema := c.Merged.Schema()
+	return relations.UpdateSchema(ctx, c.Schema, name, description, schema, tm.ID)
+}
+
+// collectionParentID 新建接口的父级目录，目录已不存在时放到根目录
+func collectionParentID(ctx context.Context, projectID string, parentID uint) uint {
+	if parentID == 0 {
+		return 0
+	}
+	parent := &collection.Collection{ID: parentID, ProjectID: projectID}
+	if exist, err := parent.Get(ctx); err != nil || !exist || parent.Type != collection.CategoryType {
+		return 0
+	}
+	return parentID
+}
+
+// schemaParentID 新建模型的父级目录，目录已不存在时放到根目录
+func schemaParentID(ctx context.Context, projectID string, parentID uint) uint {
+	if parentID == 0 {
+		return 0
+	}
+	parent := &definition.DefinitionSchema{ID: parentID, ProjectID: projectID}
+	if exist, err := parent.Get(ctx); err != nil || !exist || parent.Type != definition.SchemaCategory {
+		return 0
+	}
+	return parentID
+}
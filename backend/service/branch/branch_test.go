@@ -0,0 +1,30 @@
+package branch
+
+import (
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/model/iteration"
+	"github.com/apicat/apicat/v2/backend/model/project"
+)
+
+func TestChangeCapability(t *testing.T) {
+	mainline := SchemaFields("a", "", `{"type":"object"}`)
+	merged := SchemaFields("b", "", `{"type":"object"}`)
+	cases := []struct {
+		targetType       string
+		mainline, merged Fields
+		want             project.Capability
+	}{
+		{iteration.BranchTargetSchema, mainline, merged, project.CapabilityEditSchema},
+		{iteration.BranchTargetSchema, nil, merged, project.CapabilityEditSchema},
+		{iteration.BranchTargetSchema, mainline, nil, project.CapabilityDelete},
+		{iteration.BranchTargetCollection, mainline, merged, project.CapabilityEditCollection},
+		{iteration.BranchTargetCollection, mainline, nil, project.CapabilityDelete},
+	}
+	for _, tc := range cases {
+		c := &Change{Item: &iteration.IterationBranchItem{TargetType: tc.targetType}, Mainline: tc.mainline, Merged: tc.merged}
+		if got := c.Capability(); got != tc.want {
+			t.Errorf("%s %s: capability = %s, want %s", tc.targetType, c.Action(), got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package branch
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+)
+
+// 冲突的解决方式
+const (
+	ResolveMainline = "mainline"
+	ResolveBranch   = "branch"
+)
+
+// FieldExistence 一方删除而另一方修改时冲突的字段，解决时决定保留还是删除
+const FieldExistence = "*"
+
+// Fields 用于合并的内容，按字段拆分，值为规范化后的文本
+// 接口拆分为标题和各个内容节点，模型拆分为名称、描述和结构
+type Fields map[string]string
+
+// Conflict 合并冲突，nil表示该方没有这个字段或条目已删除
+type Conflict struct {
+	Field    string  `json:"field"`
+	Base     *string `json:"base"`
+	Mainline *string `json:"mainline"`
+	Branch   *string `json:"branch"`
+}
+
+func (f Fields) get(key string) *string {
+	if f == nil {
+		return nil
+	}
+	if v, ok := f[key]; ok {
+		return &v
+	}
+	return nil
+}
+
+// Equal 两份内容是否相同
+func (f Fields) Equal(o Fields) bool {
+	if (f == nil) != (o == nil) || len(f) != len(o) {
+		return false
+	}
+	for k, v := range f {
+		if ov, ok := o[k]; !ok || ov != v {
+			return false
+		}
+	}
+	return true
+}
+
+func equal(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Merge 三方合并，base为创建分支时主线的内容，mainline为主线当前的内容，branch为分支的内容
+// 参数为nil表示条目不存在，返回nil表示合并后条目应当删除
+// 只有一方修改的字段直接采用修改后的内容，双方修改不一致时根据resolutions解决，未解决的作为冲突返回
+func Merge(base, mainline, branch Fields, resolutions map[string]string) (Fields, []Conflict) {
+	if mainline == nil || branch == nil {
+		return mergeExistence(base, mainline, branch, resolutions)
+	}
+
+	keys := make(map[string]struct{})
+	for _, f := range []Fields{base, mainline, branch} {
+		for k := range f {
+			keys[k] = struct{}{}
+		}
+	}
+
+	merged := make(Fields)
+	conflicts := make([]Conflict, 0)
+	for k := range keys {
+		b, m, br := base.get(k), mainline.get(k), branch.get(k)
+
+		var v *string
+		switch {
+		case equal(br, b), equal(m, br):
+			v = m
+		case equal(m, b):
+			v = br
+		case resolutions[k] == ResolveMainline:
+			v = m
+		case resolutions[k] == ResolveBranch:
+			v = br
+		default:
+			conflicts = append(conflicts, Conflict{Field: k, Base: b, Mainline: m, Branch: br})
+			continue
+		}
+		if v != nil {
+			merged[k] = *v
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Field < conflicts[j].Field
+	})
+	return merged, conflicts
+}
+
+// mergeExistence 至少一方已删除时的合并
+func mergeExistence(base, mainline, branch Fields, resolutions map[string]string) (Fields, []Conflict) {
+	switch {
+	case mainline == nil && branch == nil:
+		return nil, nil
+	case branch == nil && (base == nil || mainline.Equal(base)):
+		// 分支删除且主线未修改
+		return nil, nil
+	case mainline == nil && (base == nil || branch.Equal(base)):
+		// 分支中新建，或主线删除且分支未修改
+		return branch, nil
+	}
+
+	switch resolutions[FieldExistence] {
+	case ResolveMainline:
+		return mainline, nil
+	case ResolveBranch:
+		return branch, nil
+	}
+	return mainline, []Conflict{{
+		Field:    FieldExistence,
+		Base:     base.text(),
+		Mainline: mainline.text(),
+		Branch:   branch.text(),
+	}}
+}
+
+// text 整个条目的文本，用于展示删除冲突
+func (f Fields) text() *string {
+	if f == nil {
+		return nil
+	}
+	b, _ := json.Marshal(f)
+	s := string(b)
+	return &s
+}
+
+// 接口内容节点的排列顺序，未知的节点排在最后
+var nodeOrders = map[string]int{
+	spec.NODE_HTTP_URL:      1,
+	spec.NODE_DOC:           2,
+	spec.NODE_HTTP_REQUEST:  3,
+	spec.NODE_HTTP_RESPONSE: 4,
+}
+
+const contentPrefix = "content."
+
+// CollectionFields 拆分接口，内容按节点类型拆分以便分别合并请求和响应，无法拆分时整体作为一个字段
+func CollectionFields(title, content string) Fields {
+	f := Fields{"title": title}
+	if content == "" {
+		return f
+	}
+
+	var nodes []json.RawMessage
+	if err := json.Unmarshal([]byte(content), &nodes); err == nil {
+		parts := make(Fields)
+		for _, n := range nodes {
+			var node struct {
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal(n, &node); err != nil || node.Type == "" {
+				break
+			}
+			if _, ok := parts[contentPrefix+node.Type]; ok {
+				break
+			}
+			parts[contentPrefix+node.Type] = normalize(string(n))
+		}
+		if len(parts) == len(nodes) {
+			for k, v := range parts {
+				f[k] = v
+			}
+			return f
+		}
+	}
+
+	f["content"] = normalize(content)
+	return f
+}
+
+// Collection 还原接口的标题和内容
+func (f Fields) Collection() (title, content string) {
+	title = f["title"]
+	if c, ok := f["content"]; ok {
+		return title, c
+	}
+
+	keys := make([]string, 0)
+	for k := range f {
+		if strings.HasPrefix(k, contentPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return title, ""
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		oi, oj := nodeOrder(keys[i]), nodeOrder(keys[j])
+		if oi != oj {
+			return oi < oj
+		}
+		return keys[i] < keys[j]
+	})
+
+	nodes := make([]string, len(keys))
+	for i, k := range keys {
+		nodes[i] = f[k]
+	}
+	return title, "[" + strings.Join(nodes, ",") + "]"
+}
+
+func nodeOrder(key string) int {
+	if o, ok := nodeOrders[strings.TrimPrefix(key, contentPrefix)]; ok {
+		return o
+	}
+	return len(nodeOrders) + 1
+}
+
+// SchemaFields 拆分模型
+func SchemaFields(name, description, schema string) Fields {
+	f := Fields{
+		"name":        name,
+		"description": description,
+	}
+	if schema != "" {
+		f["schema"] = normalize(schema)
+	}
+	return f
+}
+
+// Schema 还原模型的名称、描述和结构
+func (f Fields) Schema() (name, description, schema string) {
+	return f["name"], f["description"], f["schema"]
+}
+
+// normalize 规范化JSON，避免键顺序和空白不同被当作修改，无法解析时原样返回
+func normalize(s string) string {
+	d := json.NewDecoder(strings.NewReader(s))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return s
+	}
+
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return s
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
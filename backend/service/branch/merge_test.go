@@ -0,0 +1,83 @@
+package branch
+
+import (
+	"testing"
+)
+
+const (
+	urlNode      = `{"type":"apicat-http-url","attrs":{"path":"/users","method":"get"}}`
+	requestNode  = `{"type":"apicat-http-request","attrs":{"parameters":{}}}`
+	responseNode = `{"type":"apicat-http-response","attrs":{"list":[{"code":200}]}}`
+)
+
+func TestMerge(t *testing.T) {
+	base := CollectionFields("list users", "["+urlNode+","+requestNode+","+responseNode+"]")
+	mainline := CollectionFields("List users", "["+urlNode+","+requestNode+","+responseNode+"]")
+	branch := CollectionFields("list users", "["+urlNode+`,{"attrs":{"parameters":{"query":[]}},"type":"apicat-http-request"}`+","+responseNode+"]")
+
+	// 双方修改了不同字段，自动合并
+	merged, conflicts := Merge(base, mainline, branch, nil)
+	if len(conflicts) != 0 {
+		t.Fatalf("conflicts = %+v, want none", conflicts)
+	}
+	title, content := merged.Collection()
+	if title != "List users" {
+		t.Errorf("title = %q, want mainline title", title)
+	}
+	if want := normalize(`{"type":"apicat-http-request","attrs":{"parameters":{"query":[]}}}`); CollectionFields(title, content)["content.apicat-http-request"] != want {
+		t.Errorf("content = %s, want request %s", content, want)
+	}
+
+	// 双方修改了同一字段
+	branch["title"] = "Users"
+	_, conflicts = Merge(base, mainline, branch, nil)
+	if len(conflicts) != 1 || conflicts[0].Field != "title" || *conflicts[0].Mainline != "List users" || *conflicts[0].Branch != "Users" {
+		t.Fatalf("conflicts = %+v, want title conflict", conflicts)
+	}
+	merged, conflicts = Merge(base, mainline, branch, map[string]string{"title": ResolveBranch})
+	if len(conflicts) != 0 || merged["title"] != "Users" {
+		t.Errorf("resolved title = %q, conflicts = %+v", merged["title"], conflicts)
+	}
+
+	// 分支删除，主线未修改
+	if merged, conflicts := Merge(base, base, nil, nil); merged != nil || len(conflicts) != 0 {
+		t.Errorf("delete in branch = %v, %+v", merged, conflicts)
+	}
+	// 分支删除，主线已修改
+	merged, conflicts = Merge(base, mainline, nil, nil)
+	if len(conflicts) != 1 || conflicts[0].Field != FieldExistence || conflicts[0].Branch != nil {
+		t.Fatalf("delete conflicts = %+v", conflicts)
+	}
+	if !merged.Equal(mainline) {
+		t.Errorf("unresolved delete should keep mainline")
+	}
+	if merged, _ := Merge(base, mainline, nil, map[string]string{FieldExistence: ResolveBranch}); merged != nil {
+		t.Errorf("resolved delete = %v, want nil", merged)
+	}
+
+	// 分支中新建
+	created := SchemaFields("User", "", `{"type":"object"}`)
+	if merged, conflicts := Merge(nil, nil, created, nil); !merged.Equal(created) || len(conflicts) != 0 {
+		t.Errorf("created = %v, %+v", merged, conflicts)
+	}
+}
+
+func TestCollectionFields(t *testing.T) {
+	f := CollectionFields("a", "["+responseNode+","+urlNode+"]")
+	if _, ok := f["content"]; ok {
+		t.Fatalf("fields = %v, want split by node", f)
+	}
+	if _, content := f.Collection(); content != "["+normalize(urlNode)+","+normalize(responseNode)+"]" {
+		t.Errorf("content = %s", content)
+	}
+
+	// 节点类型重复时不拆分
+	f = CollectionFields("a", "["+urlNode+","+urlNode+"]")
+	if _, content := f.Collection(); content != normalize("["+urlNode+","+urlNode+"]") {
+		t.Errorf("content = %s", content)
+	}
+
+	if normalize(`{"b":1, "a":"<x>"}`) != `{"a":"<x>","b":1}` {
+		t.Errorf("normalize = %s", normalize(`{"b":1, "a":"<x>"}`))
+	}
+}
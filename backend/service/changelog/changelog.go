@@ -0,0 +1,167 @@
+package changelog
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strconv"
+
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+
+	"github.com/gomarkdown/markdown"
+	mdhtml "github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// Changelog 根据项目对比结果生成的变更日志
+// From和To为对比双方的描述，如项目名称或时间
+type Changelog struct {
+	Title  string
+	From   string
+	To     string
+	Report *diff.SpecReport
+}
+
+var fieldOps = map[string]string{
+	diff.DIFF_NEW:    "changelog.FieldAdded",
+	diff.DIFF_REMOVE: "changelog.FieldRemoved",
+	diff.DIFF_UPDATE: "changelog.FieldUpdated",
+}
+
+type section struct {
+	status string
+	key    string
+}
+
+var endpointSections = []section{
+	{diff.StatusAdded, "changelog.AddedEndpoints"},
+	{diff.StatusRemoved, "changelog.RemovedEndpoints"},
+	{diff.StatusChanged, "changelog.ChangedEndpoints"},
+}
+
+var modelSections = []section{
+	{diff.StatusAdded, "changelog.AddedModels"},
+	{diff.StatusRemoved, "changelog.RemovedModels"},
+	{diff.StatusChanged, "changelog.ChangedModels"},
+}
+
+// Markdown 按指定语言生成Markdown格式的变更日志
+func (c *Changelog) Markdown(lang string) []byte {
+	t := func(key string, params ...string) string {
+		return i18n.NewTran(key, params...).TranslateIn(lang)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n\n", t("changelog.Title", c.Title))
+	fmt.Fprintf(&buf, "%s\n\n", t("changelog.Range", c.From, c.To))
+
+	r := c.Report
+	if len(r.Endpoints) == 0 && len(r.Models) == 0 {
+		fmt.Fprintf(&buf, "%s\n", t("changelog.NoChanges"))
+		return buf.Bytes()
+	}
+
+	if r.HasBreaking() {
+		fmt.Fprintf(&buf, "> **%s**\n\n", t("changelog.Breaking"))
+	}
+	addedE, addedM := r.Count(diff.StatusAdded)
+	removedE, removedM := r.Count(diff.StatusRemoved)
+	changedE, changedM := r.Count(diff.StatusChanged)
+	fmt.Fprintf(&buf, "%s\n\n", t("changelog.Summary",
+		strconv.Itoa(addedE), strconv.Itoa(removedE), strconv.Itoa(changedE),
+		strconv.Itoa(addedM), strconv.Itoa(removedM), strconv.Itoa(changedM),
+	))
+
+	for _, s := range endpointSections {
+		list := make([]*diff.EndpointChange, 0)
+		for _, e := range r.Endpoints {
+			if e.Status == s.status {
+				list = append(list, e)
+			}
+		}
+		if len(list) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "## %s\n\n", t(s.key))
+		for _, e := range list {
+			if s.status != diff.StatusChanged {
+				fmt.Fprintf(&buf, "- `%s %s` %s\n", e.Method, e.Path, e.Title)
+				continue
+			}
+			fmt.Fprintf(&buf, "### `%s %s` %s\n\n", e.Method, e.Path, e.Title)
+			writeChanges(&buf, t, e.Changes, e.Compatibility)
+		}
+		buf.WriteString("\n")
+	}
+
+	for _, s := range modelSections {
+		list := make([]*diff.ModelChange, 0)
+		for _, m := range r.Models {
+			if m.Status == s.status {
+				list = append(list, m)
+			}
+		}
+		if len(list) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "## %s\n\n", t(s.key))
+		for _, m := range list {
+			if s.status != diff.StatusChanged {
+				fmt.Fprintf(&buf, "- `%s`\n", m.Name)
+				continue
+			}
+			fmt.Fprintf(&buf, "### `%s`\n\n", m.Name)
+			writeChanges(&buf, t, m.Changes, m.Compatibility)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+func writeChanges(buf *bytes.Buffer, t func(string, ...string) string, changes []diff.Change, r *diff.Report) {
+	for _, c := range changes {
+		fmt.Fprintf(buf, "- %s\n", t(fieldOps[c.Op], "`"+c.Path+"`"))
+	}
+	buf.WriteString("\n")
+
+	if r == nil || len(r.Changes) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "**%s**\n\n", t("changelog.Compatibility"))
+	for _, c := range r.Changes {
+		fmt.Fprintf(buf, "- [%s] %s\n", t("changelog."+c.Level), t("breakingChange."+c.Rule, c.Params...))
+	}
+	buf.WriteString("\n")
+}
+
+// HTML 按指定语言生成HTML格式的变更日志
+func (c *Changelog) HTML(lang string) []byte {
+	p := parser.NewWithExtensions(parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock)
+	renderer := mdhtml.NewRenderer(mdhtml.RendererOptions{Flags: mdhtml.CommonFlags | mdhtml.SkipHTML})
+	body := markdown.Render(p.Parse(c.Markdown(lang)), renderer)
+
+	title := i18n.NewTran("changelog.Title", c.Title).TranslateIn(lang)
+	return []byte(fmt.Sprintf(htmllayout, html.EscapeString(title), body))
+}
+
+var htmllayout = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>%s</title>
+<style>
+body{max-width:960px;margin:0 auto;padding:24px;font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;line-height:1.6;color:#1f2328}
+code{padding:2px 4px;background:#f6f8fa;border-radius:4px;font-size:90%%}
+blockquote{margin:0;padding:8px 16px;border-left:4px solid #d1242f;background:#fff5f5}
+h2{border-bottom:1px solid #d0d7de;padding-bottom:4px}
+</style>
+</head>
+<body>
+%s
+</body>
+</html>
+`
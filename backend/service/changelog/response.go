@@ -0,0 +1,47 @@
+package changelog
+
+import (
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	projectresponse "github.com/apicat/apicat/v2/backend/route/proto/project/response"
+	"github.com/apicat/apicat/v2/backend/service/compatibility"
+
+	"github.com/gin-gonic/gin"
+)
+
+func diffChanges(changes []diff.Change) []*projectresponse.ProjectDiffChange {
+	res := make([]*projectresponse.ProjectDiffChange, len(changes))
+	for i, c := range changes {
+		res[i] = &projectresponse.ProjectDiffChange{Op: c.Op, Path: c.Path}
+	}
+	return res
+}
+
+// Response 转换为接口返回的项目对比结果，兼容性提示按请求语言翻译
+func (c *Changelog) Response(ctx *gin.Context) *projectresponse.ProjectDiff {
+	res := &projectresponse.ProjectDiff{
+		From:        c.From,
+		To:          c.To,
+		HasBreaking: c.Report.HasBreaking(),
+		Endpoints:   make([]*projectresponse.ProjectDiffEndpoint, len(c.Report.Endpoints)),
+		Models:      make([]*projectresponse.ProjectDiffModel, len(c.Report.Models)),
+	}
+	for i, e := range c.Report.Endpoints {
+		res.Endpoints[i] = &projectresponse.ProjectDiffEndpoint{
+			Status:          e.Status,
+			Method:          e.Method,
+			Path:            e.Path,
+			Title:           e.Title,
+			Changes:         diffChanges(e.Changes),
+			BreakingChanges: compatibility.Translate(ctx, e.Compatibility),
+		}
+	}
+	for i, m := range c.Report.Models {
+		res.Models[i] = &projectresponse.ProjectDiffModel{
+			Status:          m.Status,
+			Name:            m.Name,
+			Changes:         diffChanges(m.Changes),
+			BreakingChanges: compatibility.Translate(ctx, m.Compatibility),
+		}
+	}
+	return res
+}
@@ -0,0 +1,74 @@
+package changelog
+
+import (
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+)
+
+// ScopeSpec 只保留快照中指定的接口，返回这些接口直接或间接引用的公共模型名称
+// 公共模型仍保留在快照中，用于对比时解引用
+func ScopeSpec(s *spec.Spec, collectionIDs []uint) map[string]bool {
+	ids := make(map[int64]bool, len(collectionIDs))
+	for _, id := range collectionIDs {
+		ids[int64(id)] = true
+	}
+
+	collections := make(spec.Collections, 0)
+	for _, c := range s.Collections {
+		for _, item := range c.ItemsTreeToList() {
+			if item.Type == spec.TYPE_HTTP && ids[item.ID] {
+				collections = append(collections, item)
+			}
+		}
+	}
+	s.Collections = collections
+
+	models := make(spec.DefinitionModels, 0)
+	responses := make(spec.DefinitionResponses, 0)
+	if s.Definitions != nil {
+		for _, m := range s.Definitions.Schemas {
+			models = append(models, m.ItemsTreeToList()...)
+		}
+		for _, r := range s.Definitions.Responses {
+			responses = append(responses, r.ItemsTreeToList()...)
+		}
+	}
+
+	pending := make([]int64, 0)
+	for _, c := range collections {
+		pending = append(pending, c.Content.GetRefModelIDs()...)
+		for _, id := range c.Content.GetRefResponseIDs() {
+			if r := responses.FindByID(id); r != nil {
+				pending = append(pending, r.RefIDs()...)
+			}
+		}
+	}
+
+	// 模型之间也会相互引用，逐层找出所有被引用的模型
+	visited := make(map[int64]bool)
+	names := make(map[string]bool)
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		if m := models.FindByID(id); m != nil {
+			names[m.Name] = true
+			pending = append(pending, m.RefIDs()...)
+		}
+	}
+	return names
+}
+
+// ScopeReport 只保留报告中指定名称的公共模型
+func ScopeReport(r *diff.SpecReport, models map[string]bool) {
+	list := make([]*diff.ModelChange, 0, len(r.Models))
+	for _, m := range r.Models {
+		if models[m.Name] {
+			list = append(list, m)
+		}
+	}
+	r.Models = list
+}
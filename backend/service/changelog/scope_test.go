@@ -0,0 +1,63 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+)
+
+const scopeProject = `{
+	"definitions":{
+		"schemas":[
+			{"id":1,"name":"User","type":"schema","schema":{"type":"object","properties":{"address":{"$ref":"#/definitions/schemas/2"}}}},
+			{"id":2,"name":"Address","type":"schema","schema":{"type":"object","properties":{"city":{"type":"string"}}}},
+			{"id":3,"name":"Order","type":"schema","schema":{"type":"object","properties":{"id":{"type":"integer"}}}},
+			{"id":4,"name":"Error","type":"schema","schema":{"type":"object","properties":{"code":{"type":"integer"}}}}
+		],
+		"responses":[
+			{"id":1,"name":"Failed","type":"response","content":{"application/json":{"schema":{"$ref":"#/definitions/schemas/4"}}}}
+		]
+	},
+	"collections":[
+		{"id":1,"title":"get user","type":"http","content":[
+			{"type":"apicat-http-url","attrs":{"path":"/users/{id}","method":"get"}},
+			{"type":"apicat-http-request","attrs":{"parameters":{},"content":{}}},
+			{"type":"apicat-http-response","attrs":{"list":[
+				{"code":200,"name":"ok","content":{"application/json":{"schema":{"$ref":"#/definitions/schemas/1"}}}},
+				{"code":400,"$ref":"#/definitions/responses/1"}
+			]}}
+		]},
+		{"id":2,"title":"list orders","type":"http","content":[
+			{"type":"apicat-http-url","attrs":{"path":"/orders","method":"get"}},
+			{"type":"apicat-http-request","attrs":{"parameters":{},"content":{}}},
+			{"type":"apicat-http-response","attrs":{"list":[{"code":200,"name":"ok","content":{"application/json":{"schema":{"$ref":"#/definitions/schemas/3"}}}}]}}
+		]}
+	]
+}`
+
+func TestScopeSpec(t *testing.T) {
+	s, err := spec.NewSpecFromJson([]byte(scopeProject))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := ScopeSpec(s, []uint{1})
+	if len(s.Collections) != 1 || s.Collections[0].ID != 1 {
+		t.Fatalf("collections = %+v, want only get user", s.Collections)
+	}
+	for _, name := range []string{"User", "Address", "Error"} {
+		if !names[name] {
+			t.Errorf("model %s should be in scope", name)
+		}
+	}
+	if names["Order"] {
+		t.Error("model Order is not referenced by the iteration")
+	}
+
+	r := &diff.SpecReport{Models: []*diff.ModelChange{{Name: "Address"}, {Name: "Order"}}}
+	ScopeReport(r, names)
+	if len(r.Models) != 1 || r.Models[0].Name != "Address" {
+		t.Errorf("models = %+v, want only Address", r.Models)
+	}
+}
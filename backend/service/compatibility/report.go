@@ -0,0 +1,51 @@
+package compatibility
+
+import (
+	"github.com/apicat/apicat/v2/backend/i18n"
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	protobase "github.com/apicat/apicat/v2/backend/route/proto/base"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Collection 对比两个版本的接口，返回按请求语言翻译的兼容性报告
+// 需要在diff.Diff之前调用，diff.Diff会修改target
+func Collection(ctx *gin.Context, original, target *spec.Collection) *protobase.BreakingChangeReport {
+	return Translate(ctx, diff.Classify(original, target))
+}
+
+// Model 对比两个版本的公共模型，返回按请求语言翻译的兼容性报告
+func Model(ctx *gin.Context, original, target *spec.DefinitionModel) *protobase.BreakingChangeReport {
+	return Translate(ctx, diff.ClassifyModel(original, target))
+}
+
+// Response 对比两个版本的公共响应，返回按请求语言翻译的兼容性报告
+func Response(ctx *gin.Context, original, target *spec.DefinitionResponse) *protobase.BreakingChangeReport {
+	return Translate(ctx, diff.ClassifyResponse(original, target))
+}
+
+// Parameter 对比两个版本的全局参数，返回按请求语言翻译的兼容性报告
+func Parameter(ctx *gin.Context, in string, original, target *spec.Parameter) *protobase.BreakingChangeReport {
+	return Translate(ctx, diff.ClassifyParameter(in, original, target))
+}
+
+// Translate 为报告中的每处差异生成提示信息
+func Translate(ctx *gin.Context, r *diff.Report) *protobase.BreakingChangeReport {
+	res := &protobase.BreakingChangeReport{
+		Breaking:            r.Breaking,
+		PotentiallyBreaking: r.PotentiallyBreaking,
+		NonBreaking:         r.NonBreaking,
+		Changes:             make([]*protobase.BreakingChange, len(r.Changes)),
+	}
+	for i, c := range r.Changes {
+		res.Changes[i] = &protobase.BreakingChange{
+			Level:   c.Level,
+			Rule:    c.Rule,
+			Pointer: c.Pointer,
+			Params:  c.Params,
+			Message: i18n.NewTran("breakingChange."+c.Rule, c.Params...).Translate(ctx),
+		}
+	}
+	return res
+}
@@ -0,0 +1,281 @@
+package embedding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/embedding"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/module/llm"
+	llmcommon "github.com/apicat/apicat/v2/backend/module/llm/common"
+	"github.com/apicat/apicat/v2/backend/service/ai"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+)
+
+const (
+	pollInterval  = time.Minute
+	pruneInterval = time.Hour
+	batchSize     = 32
+	// maxTextLength 向量模型的输入长度有限，超出部分截断
+	maxTextLength = 6000
+)
+
+var wakeup = make(chan struct{}, 1)
+
+// Wake 唤醒worker立即同步修改过的内容
+func Wake() {
+	select {
+	case wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// cursor 增量同步的位置，按修改时间和ID排序
+type cursor struct {
+	at time.Time
+	id uint
+}
+
+type document struct {
+	projectID  string
+	targetType string
+	targetID   uint
+	text       string
+	hash       string
+}
+
+type indexer struct {
+	model       string
+	collections cursor
+	schemas     cursor
+	prunedAt    time.Time
+}
+
+// Run 后台向量同步worker，定时为修改过的接口、文档和模型重新生成向量，直到ctx结束
+// 进程启动或向量模型变更后会重新扫描全部内容，内容未变化的不会重复生成
+func Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	idx := &indexer{}
+	for {
+		idx.run(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wakeup:
+		}
+	}
+}
+
+func (idx *indexer) run(ctx context.Context) {
+	// 未配置向量模型时不同步
+	e, err := llm.NewEmbedder(config.Get().LLM.ToCfg())
+	if err != nil {
+		return
+	}
+	if e.EmbeddingModel() != idx.model {
+		*idx = indexer{model: e.EmbeddingModel(), prunedAt: idx.prunedAt}
+	}
+
+	if err := idx.syncCollections(ctx, e); err != nil {
+		slog.ErrorContext(ctx, "embedding.syncCollections", "err", err)
+	}
+	if err := idx.syncSchemas(ctx, e); err != nil {
+		slog.ErrorContext(ctx, "embedding.syncSchemas", "err", err)
+	}
+
+	if time.Since(idx.prunedAt) >= pruneInterval {
+		if n, err := embedding.DeleteOrphanEmbeddings(ctx); err != nil {
+			slog.ErrorContext(ctx, "embedding.DeleteOrphanEmbeddings", "err", err)
+		} else if n > 0 {
+			slog.InfoContext(ctx, "delete orphan embeddings", "count", n)
+		}
+		idx.prunedAt = time.Now()
+	}
+}
+
+func (idx *indexer) syncCollections(ctx context.Context, e llmcommon.Embedder) error {
+	for {
+		list, err := collection.GetCollectionsUpdatedAfter(ctx, idx.collections.at, idx.collections.id, batchSize)
+		if err != nil {
+			return err
+		}
+		docs := make([]*document, 0, len(list))
+		for _, c := range list {
+			docs = append(docs, &document{
+				projectID:  c.ProjectID,
+				targetType: embedding.TargetCollection,
+				targetID:   c.ID,
+				text:       collectionText(ctx, c),
+			})
+		}
+		if err := save(ctx, e, embedding.TargetCollection, docs); err != nil {
+			return err
+		}
+		if len(list) > 0 {
+			last := list[len(list)-1]
+			idx.collections = cursor{at: last.UpdatedAt, id: last.ID}
+		}
+		if len(list) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (idx *indexer) syncSchemas(ctx context.Context, e llmcommon.Embedder) error {
+	for {
+		list, err := definition.GetDefinitionSchemasUpdatedAfter(ctx, idx.schemas.at, idx.schemas.id, batchSize)
+		if err != nil {
+			return err
+		}
+		docs := make([]*document, 0, len(list))
+		for _, ds := range list {
+			docs = append(docs, &document{
+				projectID:  ds.ProjectID,
+				targetType: embedding.TargetSchema,
+				targetID:   ds.ID,
+				text:       schemaText(ds),
+			})
+		}
+		if err := save(ctx, e, embedding.TargetSchema, docs); err != nil {
+			return err
+		}
+		if len(list) > 0 {
+			last := list[len(list)-1]
+			idx.schemas = cursor{at: last.UpdatedAt, id: last.ID}
+		}
+		if len(list) < batchSize {
+			return nil
+		}
+	}
+}
+
+// save 只为内容或向量模型变化的目标重新生成向量
+func save(ctx context.Context, e llmcommon.Embedder, targetType string, docs []*document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(docs))
+	for i, d := range docs {
+		ids[i] = d.targetID
+	}
+	existing, err := embedding.GetTargetEmbeddings(ctx, targetType, ids)
+	if err != nil {
+		return err
+	}
+
+	// 按项目分组，用量记录到项目所属的团队
+	var projectIDs []string
+	changed := make(map[string][]*document)
+	for _, d := range docs {
+		if strings.TrimSpace(d.text) == "" {
+			continue
+		}
+		d.hash = textHash(d.text)
+		if old, ok := existing[d.targetID]; ok && old.Hash == d.hash && old.Model == e.EmbeddingModel() && old.ProjectID == d.projectID {
+			continue
+		}
+		if _, ok := changed[d.projectID]; !ok {
+			projectIDs = append(projectIDs, d.projectID)
+		}
+		changed[d.projectID] = append(changed[d.projectID], d)
+	}
+
+	for _, projectID := range projectIDs {
+		if err := saveProject(ctx, e, projectID, changed[projectID]); err != nil {
+			// 超出配额的项目本轮跳过，内容再次修改或重新扫描时再生成
+			if errors.Is(err, aiusage.ErrQuotaExceeded) {
+				slog.WarnContext(ctx, "embedding.saveProject", "projectID", projectID, "err", err)
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// saveProject 为同一项目的内容生成向量，生成前检查团队配额
+func saveProject(ctx context.Context, e llmcommon.Embedder, projectID string, docs []*document) error {
+	p := &project.Project{ID: projectID}
+	exist, err := p.Get(ctx)
+	if err != nil || !exist {
+		return err
+	}
+	if err := aiusage.Check(ctx, p.TeamID); err != nil {
+		return err
+	}
+
+	r := &llmcommon.EmbeddingRequest{Input: make([]string, len(docs))}
+	for i, d := range docs {
+		r.Input[i] = d.text
+	}
+	vectors, err := e.Embeddings(r)
+	if err != nil {
+		return fmt.Errorf("e.Embeddings: %w", err)
+	}
+	aiusage.RecordEmbedding(aiusage.WithScope(ctx, aiusage.Scope{TeamID: p.TeamID, ProjectID: p.ID}), e.EmbeddingModel(), r)
+
+	for i, d := range docs {
+		v := &embedding.Embedding{
+			ProjectID:  d.projectID,
+			TargetType: d.targetType,
+			TargetID:   d.targetID,
+			Model:      e.EmbeddingModel(),
+			Hash:       d.hash,
+			Vector:     encodeVector(vectors[i]),
+		}
+		if err := v.Save(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectionText 接口使用标题、请求地址和接口摘要，文档使用标题和内容
+func collectionText(ctx context.Context, c *collection.Collection) string {
+	lines := []string{c.Title}
+	if c.Type == collection.DocType {
+		lines = append(lines, c.Content)
+		return truncate(strings.Join(lines, "\n"))
+	}
+
+	if c.Method != "" || c.Path != "" {
+		lines = append(lines, strings.ToUpper(c.Method)+" "+c.Path)
+	}
+	if sc, err := relations.CollectionDerefWithSpec(ctx, c); err == nil {
+		if summary, err := ai.APISummarize(ctx, sc); err == nil {
+			lines = append(lines, summary)
+		}
+	}
+	return truncate(strings.Join(lines, "\n"))
+}
+
+func schemaText(ds *definition.DefinitionSchema) string {
+	return truncate(strings.Join([]string{ds.Name, ds.Description, ds.Schema}, "\n"))
+}
+
+func truncate(s string) string {
+	r := []rune(s)
+	if len(r) > maxTextLength {
+		return string(r[:maxTextLength])
+	}
+	return s
+}
+
+func textHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,76 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/model/embedding"
+	"github.com/apicat/apicat/v2/backend/module/llm"
+	llmcommon "github.com/apicat/apicat/v2/backend/module/llm/common"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+)
+
+var ErrNotConfigured = errors.New("embedding model not configured")
+
+// Hit 搜索命中的接口、文档或模型
+type Hit struct {
+	ProjectID  string
+	TargetType string
+	TargetID   uint
+	Score      float32
+}
+
+// Search 在项目范围内按语义搜索，逐个计算余弦相似度，返回相似度最高的limit个结果
+// 生成问题向量前检查ctx中归属团队的配额，并按向量功能记录用量
+func Search(ctx context.Context, projectIDs []string, query string, limit int) ([]*Hit, error) {
+	e, err := llm.NewEmbedder(config.Get().LLM.ToCfg())
+	if err != nil {
+		return nil, ErrNotConfigured
+	}
+	if len(projectIDs) == 0 {
+		return []*Hit{}, nil
+	}
+
+	if err := aiusage.Check(ctx, aiusage.ScopeFrom(ctx).TeamID); err != nil {
+		return nil, err
+	}
+	r := &llmcommon.EmbeddingRequest{Input: []string{truncate(query)}}
+	vectors, err := e.Embeddings(r)
+	if err != nil {
+		return nil, fmt.Errorf("e.Embeddings: %w", err)
+	}
+	aiusage.RecordEmbedding(ctx, e.EmbeddingModel(), r)
+	q, err := decodeVector(encodeVector(vectors[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := embedding.GetProjectsEmbeddings(ctx, projectIDs, e.EmbeddingModel())
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]*Hit, 0, len(list))
+	for _, v := range list {
+		vector, err := decodeVector(v.Vector)
+		if err != nil {
+			continue
+		}
+		hits = append(hits, &Hit{
+			ProjectID:  v.ProjectID,
+			TargetType: v.TargetType,
+			TargetID:   v.TargetID,
+			Score:      cosine(q, vector),
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		return hits[i].Score > hits[j].Score
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
@@ -0,0 +1,48 @@
+package embedding
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// encodeVector 将向量归一化后编码为小端 float32，搜索时点积即为余弦相似度
+func encodeVector(v []float32) []byte {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	norm = math.Sqrt(norm)
+
+	buf := make([]byte, 4*len(v))
+	for i, x := range v {
+		if norm > 0 {
+			x = float32(float64(x) / norm)
+		}
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(x))
+	}
+	return buf
+}
+
+func decodeVector(b []byte) ([]float32, error) {
+	if len(b)%4 != 0 {
+		return nil, errors.New("invalid vector length")
+	}
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:]))
+	}
+	return v, nil
+}
+
+// cosine 两个已归一化向量的余弦相似度，维度不同时返回0
+func cosine(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
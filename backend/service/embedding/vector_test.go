@@ -0,0 +1,32 @@
+package embedding
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVector(t *testing.T) {
+	a, err := decodeVector(encodeVector([]float32{3, 4}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(float64(a[0])-0.6) > 1e-6 || math.Abs(float64(a[1])-0.8) > 1e-6 {
+		t.Fatalf("vector should be normalized, got %v", a)
+	}
+
+	b, _ := decodeVector(encodeVector([]float32{6, 8}))
+	if s := cosine(a, b); math.Abs(float64(s)-1) > 1e-6 {
+		t.Errorf("cosine of parallel vectors = %f, want 1", s)
+	}
+	c, _ := decodeVector(encodeVector([]float32{-4, 3}))
+	if s := cosine(a, c); math.Abs(float64(s)) > 1e-6 {
+		t.Errorf("cosine of orthogonal vectors = %f, want 0", s)
+	}
+	if s := cosine(a, []float32{1}); s != 0 {
+		t.Errorf("cosine of different dimensions = %f, want 0", s)
+	}
+
+	if _, err := decodeVector([]byte{1, 2, 3}); err == nil {
+		t.Error("decode invalid vector should fail")
+	}
+}
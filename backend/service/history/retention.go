@@ -0,0 +1,50 @@
+package history
+
+import "time"
+
+// Policy 保留策略，各项为0表示不限制
+type Policy struct {
+	KeepCount      int
+	KeepDays       int
+	DailyAfterDays int
+}
+
+// Record 参与保留计算的历史记录
+type Record struct {
+	ID        uint
+	CreatedAt time.Time
+}
+
+// Plan 计算一份文档需要删除的历史记录，records按时间升序排列
+// 最新一条记录总是保留；超过KeepCount条或早于KeepDays天的记录删除；
+// 早于DailyAfterDays天的记录每天只保留当天最后一条
+func Plan(records []Record, p Policy, now time.Time) []uint {
+	var remove []uint
+	n := len(records)
+	for i, r := range records {
+		if i == n-1 {
+			break
+		}
+		if p.KeepCount > 0 && n-1-i >= p.KeepCount {
+			remove = append(remove, r.ID)
+			continue
+		}
+		if p.KeepDays > 0 && r.CreatedAt.Before(now.AddDate(0, 0, -p.KeepDays)) {
+			remove = append(remove, r.ID)
+			continue
+		}
+		if p.DailyAfterDays > 0 && r.CreatedAt.Before(now.AddDate(0, 0, -p.DailyAfterDays)) {
+			next := records[i+1].CreatedAt
+			if next.Before(now.AddDate(0, 0, -p.DailyAfterDays)) && sameDay(r.CreatedAt, next) {
+				remove = append(remove, r.ID)
+			}
+		}
+	}
+	return remove
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Local().Date()
+	by, bm, bd := b.Local().Date()
+	return ay == by && am == bm && ad == bd
+}
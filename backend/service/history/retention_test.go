@@ -0,0 +1,47 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlan(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	day := func(d, h int) time.Time { return now.AddDate(0, 0, -d).Add(time.Duration(h) * time.Hour) }
+	records := []Record{
+		{1, day(40, 0)},
+		{2, day(10, 1)},
+		{3, day(10, 2)},
+		{4, day(10, 3)},
+		{5, day(9, 0)},
+		{6, day(1, 0)},
+		{7, day(1, 1)},
+		{8, day(0, 0)},
+	}
+
+	cases := []struct {
+		name string
+		p    Policy
+		want []uint
+	}{
+		{"unlimited", Policy{}, nil},
+		{"keep count", Policy{KeepCount: 3}, []uint{1, 2, 3, 4, 5}},
+		{"keep days", Policy{KeepDays: 30}, []uint{1}},
+		{"daily", Policy{DailyAfterDays: 7}, []uint{2, 3}},
+		{"combined", Policy{KeepCount: 6, KeepDays: 30, DailyAfterDays: 7}, []uint{1, 2, 3}},
+	}
+	for _, c := range cases {
+		if got := Plan(records, c.p, now); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: Plan() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestPlanKeepsLatest(t *testing.T) {
+	now := time.Now()
+	records := []Record{{1, now.AddDate(-1, 0, 0)}}
+	if got := Plan(records, Policy{KeepCount: 1, KeepDays: 1}, now); got != nil {
+		t.Errorf("Plan() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,144 @@
+package history
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/global"
+	"github.com/apicat/apicat/v2/backend/model/project"
+)
+
+const (
+	pollInterval    = time.Hour
+	compactInterval = 24 * time.Hour
+)
+
+var wakeup = make(chan struct{}, 1)
+
+// Wake 唤醒worker立即检查需要压缩的项目，保留策略修改后调用
+func Wake() {
+	select {
+	case wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// Run 后台压缩worker，定时按项目保留策略清理和压缩历史记录，直到ctx结束
+func Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		process(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wakeup:
+		}
+	}
+}
+
+func process(ctx context.Context) {
+	list, err := project.GetHistoryRetentions(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "project.GetHistoryRetentions", "err", err)
+		return
+	}
+	now := time.Now()
+	for _, hr := range list {
+		// 策略修改后或距上次压缩超过一天才需要再次压缩
+		if hr.CompactedAt != nil && !hr.UpdatedAt.After(*hr.CompactedAt) && now.Sub(*hr.CompactedAt) < compactInterval {
+			continue
+		}
+		if err := Compact(ctx, hr, now); err != nil {
+			slog.ErrorContext(ctx, "history.Compact", "err", err, "project", hr.ProjectID)
+			continue
+		}
+		if err := hr.Compacted(ctx, now); err != nil {
+			slog.ErrorContext(ctx, "hr.Compacted", "err", err, "project", hr.ProjectID)
+		}
+	}
+}
+
+// Compact 按保留策略清理项目中接口、模型、响应和全局参数的历史记录
+func Compact(ctx context.Context, hr *project.HistoryRetention, now time.Time) error {
+	p := Policy{KeepCount: hr.KeepCount, KeepDays: hr.KeepDays, DailyAfterDays: hr.DailyAfterDays}
+
+	collectionIDs, err := collection.GetHistoryCollectionIDs(ctx, hr.ProjectID)
+	if err != nil {
+		return err
+	}
+	for _, id := range collectionIDs {
+		list, err := collection.GetCollectionHistoryTimes(ctx, id)
+		if err != nil {
+			return err
+		}
+		records := make([]Record, len(list))
+		for i, v := range list {
+			records[i] = Record{ID: v.ID, CreatedAt: v.CreatedAt}
+		}
+		if err := collection.CompactCollectionHistories(ctx, id, Plan(records, p, now), hr.DeltaStorage); err != nil {
+			return err
+		}
+	}
+
+	schemaIDs, err := definition.GetHistorySchemaIDs(ctx, hr.ProjectID)
+	if err != nil {
+		return err
+	}
+	for _, id := range schemaIDs {
+		list, err := definition.GetDefinitionSchemaHistoryTimes(ctx, id)
+		if err != nil {
+			return err
+		}
+		records := make([]Record, len(list))
+		for i, v := range list {
+			records[i] = Record{ID: v.ID, CreatedAt: v.CreatedAt}
+		}
+		if err := definition.CompactDefinitionSchemaHistories(ctx, id, Plan(records, p, now), hr.DeltaStorage); err != nil {
+			return err
+		}
+	}
+
+	// 响应和全局参数内容较小，只按策略清理不做差量存储
+	responseIDs, err := definition.GetHistoryResponseIDs(ctx, hr.ProjectID)
+	if err != nil {
+		return err
+	}
+	for _, id := range responseIDs {
+		list, err := definition.GetDefinitionResponseHistoryTimes(ctx, id)
+		if err != nil {
+			return err
+		}
+		records := make([]Record, len(list))
+		for i, v := range list {
+			records[i] = Record{ID: v.ID, CreatedAt: v.CreatedAt}
+		}
+		if err := definition.DeleteDefinitionResponseHistories(ctx, id, Plan(records, p, now)); err != nil {
+			return err
+		}
+	}
+
+	parameterIDs, err := global.GetHistoryParameterIDs(ctx, hr.ProjectID)
+	if err != nil {
+		return err
+	}
+	for _, id := range parameterIDs {
+		list, err := global.GetGlobalParameterHistoryTimes(ctx, id)
+		if err != nil {
+			return err
+		}
+		records := make([]Record, len(list))
+		for i, v := range list {
+			records[i] = Record{ID: v.ID, CreatedAt: v.CreatedAt}
+		}
+		if err := global.DeleteGlobalParameterHistories(ctx, id, Plan(records, p, now)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
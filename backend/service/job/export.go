@@ -0,0 +1,42 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/apicat/apicat/v2/backend/model/job"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+)
+
+// ExportPayload 项目导出任务参数
+type ExportPayload struct {
+	Type string `json:"type"`
+}
+
+// projectExport 导出项目，导出内容保存为任务结果
+func projectExport(ctx context.Context, j *job.Job, progress func(int)) (string, error) {
+	var payload ExportPayload
+	if err := json.Unmarshal([]byte(j.Payload), &payload); err != nil {
+		return "", err
+	}
+
+	p := &project.Project{ID: j.ProjectID}
+	exist, err := p.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", errors.New("project does not exist")
+	}
+
+	s := relations.ProjectExportSpec(ctx, p)
+	progress(50)
+
+	content, err := relations.ExportContent(s, payload.Type)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
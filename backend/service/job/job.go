@@ -0,0 +1,54 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apicat/apicat/v2/backend/model/job"
+)
+
+// Handler 任务处理函数，progress 用于上报进度百分比，返回值保存为任务结果
+type Handler func(ctx context.Context, j *job.Job, progress func(percent int)) (string, error)
+
+type definition struct {
+	handler     Handler
+	maxAttempts int
+}
+
+var definitions = map[string]definition{
+	job.TypeTestCaseGenerate: {handler: testCaseGenerate, maxAttempts: 3},
+	job.TypeProjectExport:    {handler: projectExport, maxAttempts: 2},
+	job.TypeProjectTranslate: {handler: projectTranslate, maxAttempts: 2},
+	job.TypeTestCaseRun:      {handler: testCaseRun, maxAttempts: 1}, // 测试请求可能修改被测服务的数据，失败后不重试
+}
+
+// Enqueue 创建任务并唤醒worker
+func Enqueue(ctx context.Context, j *job.Job, payload any) error {
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		j.Payload = string(b)
+	}
+	if d, ok := definitions[j.Type]; ok && j.MaxAttempts == 0 {
+		j.MaxAttempts = d.maxAttempts
+	}
+	if err := j.Create(ctx); err != nil {
+		return err
+	}
+	Wake()
+	return nil
+}
+
+// Cancel 取消任务，运行中的任务会中断执行
+func Cancel(ctx context.Context, j *job.Job) (bool, error) {
+	ok, err := j.Cancel(ctx)
+	if err != nil || !ok {
+		return ok, err
+	}
+	if cancel, exist := running.Load(j.ID); exist {
+		cancel.(context.CancelFunc)()
+	}
+	return true, nil
+}
@@ -0,0 +1,102 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/job"
+	"github.com/apicat/apicat/v2/backend/service/ai"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+)
+
+// TestCasePayload 测试用例生成任务参数
+type TestCasePayload struct {
+	Language string `json:"language"`
+	Prompt   string `json:"prompt"`
+}
+
+// TestCaseResult 测试用例生成任务结果
+type TestCaseResult struct {
+	Created int `json:"created"`
+	Failed  int `json:"failed"`
+}
+
+// testCaseGenerate 生成接口的测试用例，已有的用例会作为上下文避免重复，重试时不会重复生成已保存的用例
+func testCaseGenerate(ctx context.Context, j *job.Job, progress func(int)) (string, error) {
+	var payload TestCasePayload
+	if err := json.Unmarshal([]byte(j.Payload), &payload); err != nil {
+		return "", err
+	}
+
+	c := &collection.Collection{ID: j.TargetID, ProjectID: j.ProjectID}
+	exist, err := c.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", errors.New("collection does not exist")
+	}
+
+	sc, err := relations.CollectionDerefWithSpec(ctx, c)
+	if err != nil {
+		return "", fmt.Errorf("relations.CollectionDerefWithSpec: %w", err)
+	}
+	apiSummary, err := ai.APISummarize(ctx, sc)
+	if err != nil {
+		return "", fmt.Errorf("ai.APISummarize: %w", err)
+	}
+
+	existing := make([]string, 0)
+	if testCases, err := collection.GetTestCases(ctx, j.ProjectID, j.TargetID); err == nil {
+		for _, tc := range testCases {
+			existing = append(existing, tc.Title)
+		}
+	}
+
+	titles, err := ai.TestCaseListGenerate(ctx, payload.Language, apiSummary, existing, payload.Prompt)
+	if err != nil {
+		return "", fmt.Errorf("ai.TestCaseListGenerate: %w", err)
+	}
+	progress(10)
+
+	var result TestCaseResult
+	for i, title := range titles {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		detail, err := ai.TestCaseDetailGenerate(ctx, payload.Language, apiSummary, title)
+		if errors.Is(err, aiusage.ErrQuotaExceeded) {
+			return "", err
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "ai.TestCaseDetailGenerate", "err", err, "job", j.ID)
+			result.Failed++
+		} else {
+			tc := &collection.TestCase{
+				ProjectID:    j.ProjectID,
+				CollectionID: j.TargetID,
+				Title:        detail.Purpose,
+				Content:      detail.Markdown(payload.Language),
+				Spec:         detail.Spec,
+			}
+			if err := tc.Create(ctx); err != nil {
+				return "", err
+			}
+			result.Created++
+		}
+		progress(10 + 90*(i+1)/len(titles))
+	}
+
+	if result.Created == 0 && result.Failed > 0 {
+		return "", errors.New("all test cases failed to generate")
+	}
+
+	b, err := json.Marshal(result)
+	return string(b), err
+}
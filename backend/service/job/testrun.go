@@ -0,0 +1,42 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/apicat/apicat/v2/backend/model/job"
+	"github.com/apicat/apicat/v2/backend/service/testrun"
+)
+
+// TestRunPayload 测试用例执行任务参数
+type TestRunPayload struct {
+	// ServerID 执行的项目服务地址，为 0 时使用内置的 mock 服务
+	ServerID uint `json:"serverID"`
+}
+
+// testCaseRun 执行接口下所有可执行的测试用例，执行结果保存为任务结果
+func testCaseRun(ctx context.Context, j *job.Job, progress func(int)) (string, error) {
+	var payload TestRunPayload
+	if err := json.Unmarshal([]byte(j.Payload), &payload); err != nil {
+		return "", err
+	}
+
+	t, exist, err := testrun.GetTarget(ctx, j.ProjectID, payload.ServerID)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", errors.New("server does not exist")
+	}
+
+	report, err := testrun.RunAll(ctx, j.ProjectID, j.TargetID, t, progress)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,104 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	modeldefinition "github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/job"
+	"github.com/apicat/apicat/v2/backend/model/translation"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+	translationservice "github.com/apicat/apicat/v2/backend/service/translation"
+)
+
+// TranslatePayload 项目翻译任务参数
+type TranslatePayload struct {
+	Locale string `json:"locale"`
+}
+
+// TranslateResult 项目翻译任务结果
+type TranslateResult struct {
+	Translated int `json:"translated"`
+	Skipped    int `json:"skipped"`
+	Failed     int `json:"failed"`
+}
+
+// projectTranslate 将项目的接口、文档和模型翻译为目标语言，内容未变化的跳过，重试时不会重复翻译已保存的内容
+func projectTranslate(ctx context.Context, j *job.Job, progress func(int)) (string, error) {
+	var payload TranslatePayload
+	if err := json.Unmarshal([]byte(j.Payload), &payload); err != nil {
+		return "", err
+	}
+
+	collections, err := collection.GetCollections(ctx, j.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	schemas, err := modeldefinition.GetDefinitionSchemas(ctx, j.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	collectionHashes, err := translation.GetSourceHashes(ctx, j.ProjectID, translation.TargetCollection, payload.Locale)
+	if err != nil {
+		return "", err
+	}
+	schemaHashes, err := translation.GetSourceHashes(ctx, j.ProjectID, translation.TargetSchema, payload.Locale)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		result TranslateResult
+		total  = len(collections) + len(schemas)
+		done   int
+	)
+	save := func(t *translation.Translation, err error) error {
+		if err == nil {
+			err = t.Save(ctx)
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if errors.Is(err, aiusage.ErrQuotaExceeded) {
+				return err
+			}
+			slog.ErrorContext(ctx, "translate", "err", err, "job", j.ID)
+			result.Failed++
+		} else {
+			result.Translated++
+		}
+		return nil
+	}
+	step := func() {
+		done++
+		progress(100 * done / total)
+	}
+
+	for _, c := range collections {
+		if collectionHashes[c.ID] == translationservice.CollectionHash(c) {
+			result.Skipped++
+		} else if err := save(translationservice.TranslateCollection(ctx, c, payload.Locale)); err != nil {
+			return "", err
+		}
+		step()
+	}
+	for _, ds := range schemas {
+		if ds.Type == modeldefinition.SchemaCategory || schemaHashes[ds.ID] == translationservice.SchemaHash(ds) {
+			result.Skipped++
+		} else if err := save(translationservice.TranslateSchema(ctx, ds, payload.Locale)); err != nil {
+			return "", err
+		}
+		step()
+	}
+
+	if result.Translated == 0 && result.Failed > 0 {
+		return "", errors.New("all contents failed to translate")
+	}
+
+	b, err := json.Marshal(result)
+	return string(b), err
+}
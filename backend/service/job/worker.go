@@ -0,0 +1,227 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/model/job"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/team"
+	"github.com/apicat/apicat/v2/backend/service/aiusage"
+)
+
+const (
+	pollInterval      = 10 * time.Second
+	claimLease        = 2 * time.Minute
+	heartbeatInterval = 30 * time.Second
+	retryInterval     = 30 * time.Second
+	maxRetryInterval  = 10 * time.Minute
+	maxErrorLength    = 1024
+)
+
+var (
+	wakeup = make(chan struct{}, 1)
+	// running 本进程中运行中任务的取消函数
+	running sync.Map
+)
+
+// Wake 唤醒worker立即处理待运行任务
+func Wake() {
+	select {
+	case wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// Run 后台任务worker，按配置的并发数运行到期的任务，直到ctx结束
+func Run(ctx context.Context) {
+	concurrency := config.Get().App.JobConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		dispatch(ctx, slots)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wakeup:
+		}
+	}
+}
+
+func dispatch(ctx context.Context, slots chan struct{}) {
+	free := cap(slots) - len(slots)
+	if free <= 0 {
+		return
+	}
+
+	list, err := job.GetDueJobs(ctx, free)
+	if err != nil {
+		slog.ErrorContext(ctx, "job.GetDueJobs", "err", err)
+		return
+	}
+	for _, j := range list {
+		if ok, err := j.Claim(ctx, claimLease); err != nil || !ok {
+			continue
+		}
+		slots <- struct{}{}
+		go func(j *job.Job) {
+			defer func() {
+				<-slots
+				Wake()
+			}()
+			execute(ctx, j)
+		}(j)
+	}
+}
+
+func execute(ctx context.Context, j *job.Job) {
+	d, ok := definitions[j.Type]
+	if !ok {
+		finish(j, fmt.Errorf("unknown job type: %s", j.Type), false)
+		return
+	}
+	// 租约过期被重新抢占的任务也会计入次数，避免反复导致进程退出的任务无限重试
+	if j.Attempts > j.MaxAttempts {
+		finish(j, errors.New("job was interrupted too many times"), false)
+		return
+	}
+
+	runCtx, cancel := context.WithCancel(aiusage.WithScope(ctx, usageScope(ctx, j)))
+	defer cancel()
+	running.Store(j.ID, cancel)
+	defer running.Delete(j.ID)
+
+	go heartbeat(runCtx, j.ID, cancel)
+
+	result, err := handle(runCtx, d.handler, j)
+	if ctx.Err() != nil {
+		// 服务退出，租约过期后由其他worker继续
+		return
+	}
+	if err != nil && runCtx.Err() != nil {
+		// 任务已被取消
+		return
+	}
+	if err == nil {
+		j.Result = result
+	}
+	// 配额用尽时重试也无法成功
+	finish(j, err, !errors.Is(err, aiusage.ErrQuotaExceeded))
+}
+
+// usageScope 任务中 AI 调用的用量归属到任务所在的团队、项目和创建人
+func usageScope(ctx context.Context, j *job.Job) aiusage.Scope {
+	s := aiusage.Scope{ProjectID: j.ProjectID, Feature: j.Type}
+	p := &project.Project{ID: j.ProjectID}
+	if exist, err := p.Get(ctx); err != nil {
+		slog.ErrorContext(ctx, "p.Get", "err", err, "job", j.ID)
+	} else if exist {
+		s.TeamID = p.TeamID
+	}
+	if j.MemberID != 0 {
+		if m, err := team.GetMember(ctx, j.MemberID); err == nil {
+			s.UserID = m.UserID
+		}
+	}
+	return s
+}
+
+// handle 运行任务，处理函数panic时作为错误返回
+func handle(ctx context.Context, h Handler, j *job.Job) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return h(ctx, j, func(percent int) {
+		if percent < 0 {
+			percent = 0
+		} else if percent > 100 {
+			percent = 100
+		}
+		if err := j.UpdateProgress(ctx, percent); err != nil {
+			slog.ErrorContext(ctx, "job.UpdateProgress", "err", err, "job", j.ID)
+		}
+	})
+}
+
+// heartbeat 定时延长租约，任务在数据库中已被取消时中断执行
+func heartbeat(ctx context.Context, id uint, cancel context.CancelFunc) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	j := &job.Job{ID: id}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := j.Heartbeat(ctx, claimLease)
+			if err != nil {
+				slog.ErrorContext(ctx, "job.Heartbeat", "err", err, "job", id)
+				continue
+			}
+			if !ok {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func finish(j *job.Job, err error, retry bool) {
+	if err == nil {
+		j.Status = job.StatusSucceeded
+		j.Progress = 100
+		j.Error = ""
+	} else {
+		j.Error = truncate(err.Error(), maxErrorLength)
+		if retry && j.Attempts < j.MaxAttempts {
+			j.Status = job.StatusPending
+			j.RunAt = time.Now().Add(Backoff(j.Attempts))
+		} else {
+			j.Status = job.StatusFailed
+		}
+		slog.Error("job failed", "job", j.ID, "type", j.Type, "attempts", j.Attempts, "err", err)
+	}
+
+	if err := j.Finish(context.Background()); err != nil {
+		slog.Error("job.Finish", "err", err, "job", j.ID)
+	}
+}
+
+// Backoff 第n次失败后的重试间隔，指数增长，最长10分钟
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	d := retryInterval
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= maxRetryInterval {
+			return maxRetryInterval
+		}
+	}
+	return d
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
@@ -141,5 +141,43 @@ func SendModifyEmailMail(ctx *gin.Context, usr *user.User, newEmail string) {
 	AsyncSend("Verify Your New Email Address for ApiCat", content, newEmail)
 }
 
+// SendAccountLockedMail 发送账户锁定通知邮件
+func SendAccountLockedMail(ctx *gin.Context, usr *user.User, lockDuration time.Duration) {
+	content := createContent("account_locked.tmpl", contentData{
+		Link: fmt.Sprintf(
+			"%s/forget_pass",
+			config.Get().App.AppUrl,
+		),
+		Data: gin.H{
+			"IP":      ctx.ClientIP(),
+			"Minutes": int(lockDuration.Minutes()),
+			"usr":     usr,
+		},
+	})
+	AsyncSend("Your ApiCat Account Has Been Temporarily Locked", content, usr.Email)
+}
+
+// ProposalMail 修改提案通知邮件的内容
+type ProposalMail struct {
+	Subject string
+	Actor   string
+	Action  string
+	Project string
+	Target  string
+	Comment string
+	Link    string
+}
+
+// SendProposalMail 发送修改提案通知邮件，每个收件人单独发送，避免暴露其他成员的邮箱
+func SendProposalMail(m *ProposalMail, to ...string) {
+	content := createContent("proposal.tmpl", contentData{
+		Link: m.Link,
+		Data: m,
+	})
+	for _, email := range to {
+		AsyncSend(m.Subject, content, email)
+	}
+}
+
 // SendTeamInviteMail 发送团队邀请邮件
 func SendTeamInviteMail() {}
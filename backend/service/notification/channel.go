@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model/notification"
+	"github.com/apicat/apicat/v2/backend/utils/safehttp"
+)
+
+const maxResponseBody = 4096
+
+// client 不允许发送到本机和内网地址，不跟随重定向
+var client = safehttp.NewClient(10 * time.Second)
+
+// Send 按渠道类型格式化消息并发送
+func Send(ctx context.Context, ch *notification.Channel, msg *Message) error {
+	target := ch.URL
+	var body any
+	now := time.Now()
+
+	switch ch.Type {
+	case notification.ChannelSlack:
+		body = map[string]any{"text": msg.Mrkdwn()}
+	case notification.ChannelDingTalk:
+		body = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]string{"title": msg.Title(), "text": msg.Markdown()},
+		}
+		if ch.Secret != "" {
+			u, err := url.Parse(ch.URL)
+			if err != nil {
+				return err
+			}
+			ts := strconv.FormatInt(now.UnixMilli(), 10)
+			q := u.Query()
+			q.Set("timestamp", ts)
+			q.Set("sign", DingTalkSign(ch.Secret, ts))
+			u.RawQuery = q.Encode()
+			target = u.String()
+		}
+	case notification.ChannelFeishu:
+		m := map[string]any{
+			"msg_type": "text",
+			"content":  map[string]string{"text": msg.Text()},
+		}
+		if ch.Secret != "" {
+			ts := strconv.FormatInt(now.Unix(), 10)
+			m["timestamp"] = ts
+			m["sign"] = FeishuSign(ch.Secret, ts)
+		}
+		body = m
+	case notification.ChannelWeCom:
+		body = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]string{"content": msg.Markdown()},
+		}
+	case notification.ChannelWebhook:
+		body = map[string]any{
+			"text":    msg.Text(),
+			"message": msg,
+		}
+	default:
+		return fmt.Errorf("unsupported channel type %s", ch.Type)
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if ch.Type == notification.ChannelWebhook && ch.Secret != "" {
+		req.Header.Set("X-Apicat-Signature", WebhookSign(ch.Secret, b))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	rb, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, rb)
+	}
+	return checkResponse(ch.Type, rb)
+}
+
+// checkResponse 钉钉、飞书、企业微信出错时也返回200，需要检查返回的错误码
+func checkResponse(typ string, body []byte) error {
+	if typ != notification.ChannelDingTalk && typ != notification.ChannelFeishu && typ != notification.ChannelWeCom {
+		return nil
+	}
+	var r struct {
+		ErrCode *int   `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+		Code    *int   `json:"code"`
+		Msg     string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil
+	}
+	if r.ErrCode != nil && *r.ErrCode != 0 {
+		return fmt.Errorf("errcode %d: %s", *r.ErrCode, r.ErrMsg)
+	}
+	if r.Code != nil && *r.Code != 0 {
+		return fmt.Errorf("code %d: %s", *r.Code, r.Msg)
+	}
+	return nil
+}
+
+// DingTalkSign 钉钉机器人加签：base64(hmac_sha256(secret, timestamp+"\n"+secret))
+func DingTalkSign(secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// FeishuSign 飞书机器人签名校验：以timestamp+"\n"+secret为key对空字符串做hmac_sha256
+func FeishuSign(secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookSign 通用webhook渠道的请求体签名
+func WebhookSign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/notification"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	"github.com/apicat/apicat/v2/backend/utils/safehttp"
+)
+
+func testMessage() *Message {
+	return &Message{
+		Event:        "collection.updated",
+		ProjectTitle: "Demo",
+		Operator:     "Alice",
+		Action:       audit.ActionUpdate,
+		TargetType:   audit.TargetCollection,
+		TargetName:   "Get users",
+		Changes: []diff.Change{
+			{Op: diff.DIFF_NEW, Path: "request.query.keyword"},
+			{Op: diff.DIFF_REMOVE, Path: "response.200.body.name"},
+		},
+		Link: "http://localhost:8000/projects/p1/collection/1/history/2",
+	}
+}
+
+func TestMessageText(t *testing.T) {
+	want := "[Demo] Alice updated API \"Get users\"\n" +
+		"- Added request.query.keyword\n" +
+		"- Removed response.200.body.name\n" +
+		"http://localhost:8000/projects/p1/collection/1/history/2"
+	if got := testMessage().Text(); got != want {
+		t.Fatalf("Text() = %q, want %q", got, want)
+	}
+}
+
+// useTestClient 测试服务在本机，使用不检查地址的客户端
+func useTestClient(t *testing.T, srv *httptest.Server) {
+	old := client
+	client = srv.Client()
+	t.Cleanup(func() { client = old })
+}
+
+func TestSendRejectsLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	ch := &notification.Channel{Type: notification.ChannelWebhook, URL: srv.URL}
+	if err := Send(context.Background(), ch, testMessage()); !errors.Is(err, safehttp.ErrForbiddenAddress) {
+		t.Fatalf("Send to loopback err = %v, want %v", err, safehttp.ErrForbiddenAddress)
+	}
+}
+
+func TestSendWebhook(t *testing.T) {
+	var (
+		body      []byte
+		signature string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get("X-Apicat-Signature")
+	}))
+	defer srv.Close()
+	useTestClient(t, srv)
+
+	ch := &notification.Channel{Type: notification.ChannelWebhook, URL: srv.URL, Secret: "secret"}
+	if err := Send(context.Background(), ch, testMessage()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Text    string  `json:"text"`
+		Message Message `json:"message"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Message.Event != "collection.updated" || len(got.Message.Changes) != 2 {
+		t.Errorf("unexpected message: %s", body)
+	}
+	if signature != WebhookSign("secret", body) {
+		t.Errorf("signature = %s, want %s", signature, WebhookSign("secret", body))
+	}
+}
+
+func TestSendDingTalk(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts, sign := r.URL.Query().Get("timestamp"), r.URL.Query().Get("sign")
+		if sign == "" || sign != DingTalkSign("secret", ts) {
+			w.Write([]byte(`{"errcode":310000,"errmsg":"sign not match"}`))
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(b), `"msgtype":"markdown"`) {
+			w.Write([]byte(`{"errcode":40035,"errmsg":"bad msgtype"}`))
+			return
+		}
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer srv.Close()
+	useTestClient(t, srv)
+
+	ch := &notification.Channel{Type: notification.ChannelDingTalk, URL: srv.URL + "/robot/send?access_token=t", Secret: "secret"}
+	if err := Send(context.Background(), ch, testMessage()); err != nil {
+		t.Fatal(err)
+	}
+
+	ch.Secret = "wrong"
+	if err := Send(context.Background(), ch, testMessage()); err == nil {
+		t.Fatal("expected error when server rejects the signature")
+	}
+}
@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+)
+
+// 单条消息最多展示的差异条数，超出部分只显示数量
+const maxMessageChanges = 20
+
+var opNames = map[string]string{
+	diff.DIFF_NEW:    "Added",
+	diff.DIFF_REMOVE: "Removed",
+	diff.DIFF_UPDATE: "Modified",
+}
+
+var actionNames = map[string]string{
+	audit.ActionCreate:  "created",
+	audit.ActionUpdate:  "updated",
+	audit.ActionDelete:  "deleted",
+	audit.ActionMove:    "moved",
+	audit.ActionCopy:    "copied",
+	audit.ActionRestore: "restored",
+	audit.ActionMerge:   "merged",
+}
+
+var targetNames = map[string]string{
+	audit.TargetCollection:         "API",
+	audit.TargetDefinitionSchema:   "schema",
+	audit.TargetDefinitionResponse: "response",
+	audit.TargetGlobalParameter:    "global parameter",
+	audit.TargetIteration:          "iteration",
+	audit.TargetRelease:            "release",
+}
+
+// Message 发送到聊天渠道的变更通知
+type Message struct {
+	Event        string         `json:"event"`
+	ProjectID    string         `json:"projectID"`
+	ProjectTitle string         `json:"projectTitle"`
+	Operator     string         `json:"operator"`
+	Action       string         `json:"action"`
+	TargetType   string         `json:"targetType"`
+	TargetID     string         `json:"targetID"`
+	TargetName   string         `json:"targetName"`
+	Changes      []diff.Change  `json:"changes"`
+	Fields       []audit.Change `json:"fields"`
+	Link         string         `json:"link"`
+	Time         time.Time      `json:"time"`
+}
+
+// Title 消息标题，如：[Demo] Alice updated API "Get users"
+func (m *Message) Title() string {
+	operator := m.Operator
+	if operator == "" {
+		operator = "Someone"
+	}
+	action, ok := actionNames[m.Action]
+	if !ok {
+		action = m.Action
+	}
+	target, ok := targetNames[m.TargetType]
+	if !ok {
+		target = m.TargetType
+	}
+	return fmt.Sprintf("[%s] %s %s %s \"%s\"", m.ProjectTitle, operator, action, target, m.TargetName)
+}
+
+// Lines 变更摘要，每行一处差异
+func (m *Message) Lines() []string {
+	lines := make([]string, 0)
+	for _, f := range m.Fields {
+		lines = append(lines, fmt.Sprintf("Modified %s: %s -> %s", f.Field, quote(f.Before), quote(f.After)))
+	}
+	for _, c := range m.Changes {
+		lines = append(lines, fmt.Sprintf("%s %s", opNames[c.Op], c.Path))
+	}
+	if len(lines) > maxMessageChanges {
+		more := len(lines) - maxMessageChanges
+		lines = append(lines[:maxMessageChanges], fmt.Sprintf("... and %d more changes", more))
+	}
+	return lines
+}
+
+// Text 纯文本格式
+func (m *Message) Text() string {
+	var b strings.Builder
+	b.WriteString(m.Title())
+	for _, l := range m.Lines() {
+		b.WriteString("\n- ")
+		b.WriteString(l)
+	}
+	if m.Link != "" {
+		b.WriteString("\n")
+		b.WriteString(m.Link)
+	}
+	return b.String()
+}
+
+// Markdown 标准markdown格式，钉钉和企业微信使用
+func (m *Message) Markdown() string {
+	var b strings.Builder
+	b.WriteString("**")
+	b.WriteString(m.Title())
+	b.WriteString("**\n")
+	for _, l := range m.Lines() {
+		b.WriteString("\n- ")
+		b.WriteString(l)
+	}
+	if m.Link != "" {
+		b.WriteString("\n\n[View history](")
+		b.WriteString(m.Link)
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+// Mrkdwn slack的mrkdwn格式
+func (m *Message) Mrkdwn() string {
+	var b strings.Builder
+	b.WriteString("*")
+	b.WriteString(m.Title())
+	b.WriteString("*")
+	for _, l := range m.Lines() {
+		b.WriteString("\n• ")
+		b.WriteString(l)
+	}
+	if m.Link != "" {
+		b.WriteString("\n<")
+		b.WriteString(m.Link)
+		b.WriteString("|View history>")
+	}
+	return b.String()
+}
+
+func quote(s string) string {
+	if s == "" {
+		return "(empty)"
+	}
+	return "\"" + s + "\""
+}
@@ -0,0 +1,169 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/notification"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/diff"
+	"github.com/apicat/apicat/v2/backend/module/spec/jsonschema"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+	webhookservice "github.com/apicat/apicat/v2/backend/service/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 内容字段的差异由Detail.Changes描述，不在字段变更中重复展示
+var contentFields = map[string]bool{
+	"content": true,
+	"schema":  true,
+	"header":  true,
+}
+
+// Detail 通知中的差异明细和跳转链接
+type Detail struct {
+	Changes []diff.Change
+	Link    string
+}
+
+// Notify 把变更操作发送到项目订阅了该事件的聊天渠道
+// detail只在有渠道需要发送时才调用，发送在后台进行，失败只打印日志
+func Notify(ctx *gin.Context, projectID string, e *auditservice.Entry, detail func() *Detail) {
+	event := webhookservice.EventOf(e.TargetType, e.Action)
+	if event == "" {
+		return
+	}
+
+	p := access.GetSelfProject(ctx)
+	if p == nil || (projectID != "" && p.ID != projectID) {
+		p = &project.Project{ID: projectID}
+		if exist, err := p.Get(ctx); err != nil || !exist {
+			return
+		}
+	}
+
+	channels, err := notification.GetSubscribedChannels(ctx, p.ID, event)
+	if err != nil {
+		slog.ErrorContext(ctx, "notification.GetSubscribedChannels", "err", err, "project", p.ID)
+		return
+	}
+	if len(channels) == 0 {
+		return
+	}
+
+	msg := &Message{
+		Event:        event,
+		ProjectID:    p.ID,
+		ProjectTitle: p.Title,
+		Action:       e.Action,
+		TargetType:   e.TargetType,
+		TargetID:     fmt.Sprint(e.TargetID),
+		TargetName:   e.TargetName,
+		Changes:      make([]diff.Change, 0),
+		Fields:       make([]audit.Change, 0),
+		Link:         ProjectLink(p.ID),
+		Time:         time.Now(),
+	}
+	if u := jwt.GetUser(ctx); u != nil {
+		msg.Operator = u.Name
+	}
+	if e.Action == audit.ActionUpdate {
+		for _, c := range auditservice.Diff(e.Before, e.After) {
+			if !contentFields[c.Field] {
+				msg.Fields = append(msg.Fields, c)
+			}
+		}
+	}
+	if detail != nil {
+		if d := detail(); d != nil {
+			if d.Changes != nil {
+				msg.Changes = d.Changes
+			}
+			if d.Link != "" {
+				msg.Link = d.Link
+			}
+		}
+	}
+
+	go func() {
+		for _, ch := range channels {
+			if err := Send(context.Background(), ch, msg); err != nil {
+				slog.Error("notification.Send", "err", err, "channel", ch.ID, "event", event)
+			}
+		}
+	}()
+}
+
+// CollectionChanges 对比文档修改前后的内容
+func CollectionChanges(before, after string) ([]diff.Change, error) {
+	original, err := spec.NewCollectionNodesFromJson(before)
+	if err != nil {
+		return nil, err
+	}
+	target, err := spec.NewCollectionNodesFromJson(after)
+	if err != nil {
+		return nil, err
+	}
+	a, b := &spec.Collection{Content: original}, &spec.Collection{Content: target}
+	if err := diff.Diff(a, b); err != nil {
+		return nil, err
+	}
+	return diff.Summarize(b), nil
+}
+
+// SchemaChanges 对比模型修改前后的内容
+func SchemaChanges(before, after string) ([]diff.Change, error) {
+	a, err := jsonschema.NewSchemaFromJson(before)
+	if err != nil {
+		return nil, err
+	}
+	b, err := jsonschema.NewSchemaFromJson(after)
+	if err != nil {
+		return nil, err
+	}
+	original, target := &spec.DefinitionModel{Schema: a}, &spec.DefinitionModel{Schema: b}
+	if err := diff.DiffModel(original, target); err != nil {
+		return nil, err
+	}
+	return diff.SummarizeModel(target), nil
+}
+
+func appURL() string {
+	return strings.TrimSuffix(config.Get().App.AppUrl, "/")
+}
+
+func ProjectLink(projectID string) string {
+	return fmt.Sprintf("%s/projects/%s", appURL(), projectID)
+}
+
+// ProposalLink 修改提案页面
+func ProposalLink(projectID string, proposalID uint) string {
+	return fmt.Sprintf("%s/projects/%s/proposals/%d", appURL(), projectID, proposalID)
+}
+
+// CollectionHistoryLink 文档历史页面，historyID为0时打开最新历史
+func CollectionHistoryLink(projectID string, collectionID, historyID uint) string {
+	link := fmt.Sprintf("%s/projects/%s/collection/%d/history", appURL(), projectID, collectionID)
+	if historyID > 0 {
+		link += fmt.Sprintf("/%d", historyID)
+	}
+	return link
+}
+
+// SchemaHistoryLink 模型历史页面，historyID为0时打开最新历史
+func SchemaHistoryLink(projectID string, schemaID, historyID uint) string {
+	link := fmt.Sprintf("%s/projects/%s/model/%d/history", appURL(), projectID, schemaID)
+	if historyID > 0 {
+		link += fmt.Sprintf("/%d", historyID)
+	}
+	return link
+}
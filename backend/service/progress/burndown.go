@@ -0,0 +1,100 @@
+package progress
+
+import (
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model/iteration"
+)
+
+// maxBurndownDays 燃尽图最多展示的天数，避免日期跨度过大时返回过多数据
+const maxBurndownDays = 366
+
+// Summary 迭代进度汇总
+type Summary struct {
+	Total    int
+	Done     int
+	Statuses map[string]int
+	Overdue  bool
+	Days     []*Day
+}
+
+// Day 燃尽图中的一天，Remaining为当天结束时剩余未完成的接口数，尚未到来的日期为nil
+type Day struct {
+	Date      time.Time
+	Remaining *int
+	Ideal     float64
+}
+
+// Burndown 计算迭代的燃尽图
+// 没有开始日期时从迭代创建当天开始，没有截止日期时到今天结束
+// 接口在加入迭代之后才计入剩余数量，完成时间取最近一次变为done的时间
+func Burndown(i *iteration.Iteration, apis []*iteration.IterationApi, now time.Time) *Summary {
+	s := &Summary{
+		Total:    len(apis),
+		Statuses: make(map[string]int, len(iteration.ApiStatuses)),
+	}
+	for _, v := range iteration.ApiStatuses {
+		s.Statuses[v] = 0
+	}
+	for _, v := range apis {
+		s.Statuses[v.Status]++
+		if v.Status == iteration.ApiStatusDone {
+			s.Done++
+		}
+	}
+	// 接口已全部完成时即使未关闭迭代也不算逾期
+	s.Overdue = i.IsOverdue(now) && (s.Total == 0 || s.Done < s.Total)
+
+	start := i.CreatedAt
+	if i.StartDate != nil {
+		start = *i.StartDate
+	}
+	end := now
+	if i.DueDate != nil {
+		end = *i.DueDate
+	}
+	start = truncateDay(start)
+	end = truncateDay(end)
+	if end.Before(start) {
+		end = start
+	}
+
+	n := int(end.Sub(start).Hours()/24) + 1
+	if n > maxBurndownDays {
+		n = maxBurndownDays
+	}
+	today := truncateDay(now)
+	s.Days = make([]*Day, n)
+	for d := 0; d < n; d++ {
+		date := start.AddDate(0, 0, d)
+		day := &Day{Date: date, Ideal: float64(s.Total)}
+		if n > 1 {
+			day.Ideal = float64(s.Total) * float64(n-1-d) / float64(n-1)
+		}
+		if !date.After(today) {
+			remaining := remainingAt(apis, date.AddDate(0, 0, 1))
+			day.Remaining = &remaining
+		}
+		s.Days[d] = day
+	}
+	return s
+}
+
+// remainingAt 某一时间点之前已加入迭代且尚未完成的接口数量
+func remainingAt(apis []*iteration.IterationApi, t time.Time) int {
+	remaining := 0
+	for _, v := range apis {
+		if !v.CreatedAt.Before(t) {
+			continue
+		}
+		if v.Status == iteration.ApiStatusDone && v.DoneAt != nil && v.DoneAt.Before(t) {
+			continue
+		}
+		remaining++
+	}
+	return remaining
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,60 @@
+package progress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/iteration"
+)
+
+func TestBurndown(t *testing.T) {
+	day := func(d, h int) time.Time {
+		return time.Date(2026, 10, d, h, 0, 0, 0, time.UTC)
+	}
+	api := func(created time.Time, status string, done *time.Time) *iteration.IterationApi {
+		return &iteration.IterationApi{Status: status, DoneAt: done, TimeModel: model.TimeModel{CreatedAt: created}}
+	}
+	doneAt := day(2, 15)
+
+	start, due := day(1, 0), day(5, 0)
+	i := &iteration.Iteration{StartDate: &start, DueDate: &due, State: iteration.StateActive}
+	apis := []*iteration.IterationApi{
+		api(day(1, 9), iteration.ApiStatusDone, &doneAt),
+		api(day(1, 9), iteration.ApiStatusTesting, nil),
+		// 迭代开始后才加入
+		api(day(3, 9), iteration.ApiStatusTodo, nil),
+	}
+
+	s := Burndown(i, apis, day(3, 12))
+	if s.Total != 3 || s.Done != 1 || s.Statuses[iteration.ApiStatusTesting] != 1 || s.Statuses[iteration.ApiStatusDesigning] != 0 {
+		t.Fatalf("summary = %+v", s)
+	}
+	if s.Overdue {
+		t.Error("overdue before due date")
+	}
+	if len(s.Days) != 5 {
+		t.Fatalf("days = %d, want 5", len(s.Days))
+	}
+
+	want := []int{2, 1, 2}
+	for d, w := range want {
+		if s.Days[d].Remaining == nil || *s.Days[d].Remaining != w {
+			t.Errorf("day %d remaining = %v, want %d", d+1, s.Days[d].Remaining, w)
+		}
+	}
+	if s.Days[3].Remaining != nil || s.Days[4].Remaining != nil {
+		t.Error("future days should have no remaining count")
+	}
+	if s.Days[0].Ideal != 3 || s.Days[2].Ideal != 1.5 || s.Days[4].Ideal != 0 {
+		t.Errorf("ideal = %v, %v, %v", s.Days[0].Ideal, s.Days[2].Ideal, s.Days[4].Ideal)
+	}
+
+	if s := Burndown(i, apis, day(6, 0)); !s.Overdue {
+		t.Error("unfinished iteration after due date should be overdue")
+	}
+	i.State = iteration.StateCompleted
+	if s := Burndown(i, apis, day(6, 0)); s.Overdue {
+		t.Error("completed iteration should not be overdue")
+	}
+}
@@ -0,0 +1,220 @@
+package proposal
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/proposal"
+	"github.com/apicat/apicat/v2/backend/model/team"
+	"github.com/apicat/apicat/v2/backend/service/mailer"
+	notifyservice "github.com/apicat/apicat/v2/backend/service/notification"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+)
+
+// Target 提案修改的接口或模型，二者只有一个不为nil
+type Target struct {
+	Collection *collection.Collection
+	Schema     *definition.DefinitionSchema
+}
+
+// GetTarget 获取接口或模型，不存在时返回nil
+func GetTarget(ctx context.Context, projectID, targetType string, targetID uint) (*Target, error) {
+	if targetType == proposal.TargetSchema {
+		ds := &definition.DefinitionSchema{ID: targetID, ProjectID: projectID}
+		exist, err := ds.Get(ctx)
+		if err != nil || !exist {
+			return nil, err
+		}
+		return &Target{Schema: ds}, nil
+	}
+
+	c := &collection.Collection{ID: targetID, ProjectID: projectID}
+	exist, err := c.Get(ctx)
+	if err != nil || !exist {
+		return nil, err
+	}
+	return &Target{Collection: c}, nil
+}
+
+// Name 接口标题或模型名称
+func (t *Target) Name() string {
+	if t.Schema != nil {
+		return t.Schema.Name
+	}
+	return t.Collection.Title
+}
+
+// IsCategory 是否为目录，目录没有内容，不需要审批
+func (t *Target) IsCategory() bool {
+	if t.Schema != nil {
+		return t.Schema.Type == definition.SchemaCategory
+	}
+	return t.Collection.Type == collection.CategoryType
+}
+
+// NewProposal 以目标当前的内容为基准创建提案
+func (t *Target) NewProposal(title, description, content string) *proposal.Proposal {
+	p := &proposal.Proposal{
+		Title:       title,
+		Description: description,
+		Content:     content,
+	}
+	if t.Schema != nil {
+		p.ProjectID = t.Schema.ProjectID
+		p.TargetType = proposal.TargetSchema
+		p.TargetID = t.Schema.ID
+		p.BaseTitle = t.Schema.Name
+		p.BaseDescription = t.Schema.Description
+		p.BaseContent = t.Schema.Schema
+	} else {
+		p.ProjectID = t.Collection.ProjectID
+		p.TargetType = proposal.TargetCollection
+		p.TargetID = t.Collection.ID
+		p.BaseTitle = t.Collection.Title
+		p.BaseContent = t.Collection.Content
+	}
+	return p
+}
+
+// Outdated 提交提案后目标是否已被修改
+func (t *Target) Outdated(p *proposal.Proposal) bool {
+	if t.Schema != nil {
+		return t.Schema.Name != p.BaseTitle || t.Schema.Description != p.BaseDescription || t.Schema.Schema != p.BaseContent
+	}
+	return t.Collection.Title != p.BaseTitle || t.Collection.Content != p.BaseContent
+}
+
+// Apply 将提案的内容写入目标，与直接编辑一样维护历史记录和引用关系
+func (t *Target) Apply(ctx context.Context, p *proposal.Proposal, memberID uint) error {
+	if t.Schema != nil {
+		return relations.UpdateSchema(ctx, t.Schema, p.Title, p.Description, p.Content, memberID)
+	}
+	return relations.UpdateCollection(ctx, t.Collection, p.Title, p.Content, memberID)
+}
+
+// IsProtected 修改目标是否需要审批
+func IsProtected(ctx context.Context, t *Target) (bool, error) {
+	if t.IsCategory() {
+		return false, nil
+	}
+	if t.Schema != nil {
+		return proposal.IsProtected(ctx, t.Schema.ProjectID, proposal.TargetSchema, t.Schema.ID)
+	}
+	return proposal.IsProtected(ctx, t.Collection.ProjectID, proposal.TargetCollection, t.Collection.ID)
+}
+
+// Submit 提交修改提案，提交人已有待审批的提案时继续修改该提案，新提案邮件通知审核人
+// 内容没有变化时不提交，返回nil
+func Submit(ctx context.Context, pj *project.Project, t *Target, tm *team.TeamMember, title, description, content string) (*proposal.Proposal, error) {
+	np := t.NewProposal(title, description, content)
+	p, err := proposal.GetPendingProposal(ctx, np.ProjectID, np.TargetType, np.TargetID, tm.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.Outdated(&proposal.Proposal{BaseTitle: title, BaseDescription: description, BaseContent: content}) {
+		// 改回了当前内容，之前的提案也不再需要
+		if p != nil {
+			// 提案可能已被同时处理
+			if err := p.Finish(ctx, proposal.StatusWithdrawn, tm.ID); err != nil && !errors.Is(err, proposal.ErrNotPending) {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+
+	if p != nil {
+		return p, p.Update(ctx, np)
+	}
+	if err := np.Create(ctx, tm); err != nil {
+		return nil, err
+	}
+	Notify(ctx, pj, np, tm, ActionSubmit, "")
+	return np, nil
+}
+
+// IsReviewer 拥有写权限的项目成员可以审核提案
+func IsReviewer(pm *project.ProjectMember) bool {
+	return pm.Permission.GreaterOrEqual(project.ProjectMemberWrite)
+}
+
+const (
+	ActionSubmit  = "submit"
+	ActionComment = "comment"
+	ActionApprove = "approve"
+	ActionReject  = "reject"
+)
+
+var mailSubjects = map[string]string{
+	ActionSubmit:  "Change Proposal Awaiting Your Review",
+	ActionComment: "New Comment on Change Proposal",
+	ActionApprove: "Your Change Proposal Has Been Approved",
+	ActionReject:  "Your Change Proposal Has Been Rejected",
+}
+
+var mailActions = map[string]string{
+	ActionSubmit:  "submitted",
+	ActionComment: "commented on",
+	ActionApprove: "approved",
+	ActionReject:  "rejected",
+}
+
+// Notify 邮件通知提案的相关成员，提交时通知审核人，审核和评论时通知提交人，提交人评论时通知审核人
+// 操作人自己不会收到通知，发送失败只打印日志
+func Notify(ctx context.Context, pj *project.Project, p *proposal.Proposal, actor *team.TeamMember, action, comment string) {
+	var to []string
+	if action == ActionSubmit || (action == ActionComment && actor.ID == p.CreatedBy) {
+		to = reviewerEmails(ctx, pj.ID, actor.ID)
+	} else if actor.ID != p.CreatedBy {
+		if usr, err := p.CreatorInfo(ctx); err != nil {
+			slog.ErrorContext(ctx, "p.CreatorInfo", "err", err)
+		} else {
+			to = append(to, usr.Email)
+		}
+	}
+	if len(to) == 0 {
+		return
+	}
+
+	actorName := ""
+	if usr, err := actor.UserInfo(ctx, true); err == nil {
+		actorName = usr.Name
+	}
+
+	mailer.SendProposalMail(&mailer.ProposalMail{
+		Subject: mailSubjects[action],
+		Actor:   actorName,
+		Action:  mailActions[action],
+		Project: pj.Title,
+		Target:  p.BaseTitle,
+		Comment: comment,
+		Link:    notifyservice.ProposalLink(pj.ID, p.ID),
+	}, to...)
+}
+
+// reviewerEmails 项目中可以审核提案的成员邮箱
+func reviewerEmails(ctx context.Context, projectID string, excludeMemberID uint) []string {
+	members, err := project.GetProjectMembers(ctx, projectID, 0, 0, project.ProjectMemberManage, project.ProjectMemberWrite)
+	if err != nil {
+		slog.ErrorContext(ctx, "project.GetProjectMembers", "err", err)
+		return nil
+	}
+
+	emails := make([]string, 0, len(members))
+	for _, pm := range members {
+		if pm.MemberID == excludeMemberID {
+			continue
+		}
+		usr, err := pm.UserInfo(ctx, false)
+		if err != nil {
+			slog.ErrorContext(ctx, "pm.UserInfo", "err", err)
+			continue
+		}
+		emails = append(emails, usr.Email)
+	}
+	return emails
+}
@@ -0,0 +1,84 @@
+package proposal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/proposal"
+	referencerelation "github.com/apicat/apicat/v2/backend/model/reference_relation"
+)
+
+// ErrProtected 受保护的接口或模型只能通过修改提案修改，不能直接删除、恢复或覆盖
+var ErrProtected = errors.New("target is protected")
+
+// CheckProtected ids 中有受保护的接口或模型时返回 ErrProtected
+func CheckProtected(ctx context.Context, projectID, targetType string, ids ...uint) error {
+	protected, err := proposal.AnyProtected(ctx, projectID, targetType, ids)
+	if err != nil {
+		return err
+	}
+	if protected {
+		return ErrProtected
+	}
+	return nil
+}
+
+// CheckCollectionDelete 删除目录时会一并删除目录下的接口，其中有受保护的接口时不能删除
+func CheckCollectionDelete(ctx context.Context, c *collection.Collection) error {
+	ids := []uint{c.ID}
+	if c.Type == collection.CategoryType {
+		list, err := collection.GetCollections(ctx, c.ProjectID)
+		if err != nil {
+			return err
+		}
+		children := make(map[uint][]uint)
+		for _, v := range list {
+			children[v.ParentID] = append(children[v.ParentID], v.ID)
+		}
+		for i := 0; i < len(ids); i++ {
+			ids = append(ids, children[ids[i]]...)
+		}
+	}
+	return CheckProtected(ctx, c.ProjectID, proposal.TargetCollection, ids...)
+}
+
+// CheckSchemaDelete 删除模型时会展开引用它的接口和模型，模型自身或这些内容受保护时不能删除
+func CheckSchemaDelete(ctx context.Context, ds *definition.DefinitionSchema) error {
+	if ds.Type == definition.SchemaCategory {
+		return nil
+	}
+	if err := CheckProtected(ctx, ds.ProjectID, proposal.TargetSchema, ds.ID); err != nil {
+		return err
+	}
+
+	rc := referencerelation.RefSchemaCollections{RefSchemaID: ds.ID}
+	cIDs, err := rc.GetCollectionIDs(ctx)
+	if err != nil {
+		return err
+	}
+	if err := CheckProtected(ctx, ds.ProjectID, proposal.TargetCollection, cIDs...); err != nil {
+		return err
+	}
+
+	rs := referencerelation.RefSchemaSchemas{RefSchemaID: ds.ID}
+	sIDs, err := rs.GetSchemaIDs(ctx)
+	if err != nil {
+		return err
+	}
+	return CheckProtected(ctx, ds.ProjectID, proposal.TargetSchema, sIDs...)
+}
+
+// CheckResponseDelete 删除公共响应时会展开引用它的接口，其中有受保护的接口时不能删除
+func CheckResponseDelete(ctx context.Context, dr *definition.DefinitionResponse) error {
+	if dr.Type == definition.ResponseCategory {
+		return nil
+	}
+	rc := referencerelation.RefResponseCollections{RefResponserID: dr.ID}
+	cIDs, err := rc.GetCollectionIDs(ctx)
+	if err != nil {
+		return err
+	}
+	return CheckProtected(ctx, dr.ProjectID, proposal.TargetCollection, cIDs...)
+}
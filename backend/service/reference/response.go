@@ -76,7 +76,13 @@ func DerefResponse(ctx context.Context, r *definition.DefinitionResponse, deref
 	}
 
 	// 在collection中解引用response
-	if err := derefResponseFromCollections(ctx, r, cIDs, deref); err != nil {
+	snapshots, err := derefResponseFromCollections(ctx, r, cIDs, deref)
+	if err != nil {
+		return err
+	}
+
+	// 记录被解引用条目的原内容，从回收站恢复时还原引用
+	if err := referencerelation.BatchCreateDerefSnapshots(ctx, snapshots); err != nil {
 		return err
 	}
 
@@ -97,23 +103,34 @@ func DerefResponse(ctx context.Context, r *definition.DefinitionResponse, deref
 }
 
 // derefResponseFromCollections 解开collection中引用response的地方
-func derefResponseFromCollections(ctx context.Context, r *definition.DefinitionResponse, collectionIDs []uint, deref bool) error {
+func derefResponseFromCollections(ctx context.Context, r *definition.DefinitionResponse, collectionIDs []uint, deref bool) ([]*referencerelation.DerefSnapshot, error) {
 	if len(collectionIDs) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	collections, err := collection.GetCollections(ctx, r.ProjectID, collectionIDs...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var snapshots []*referencerelation.DerefSnapshot
 	for _, c := range collections {
+		before := c.Content
 		if err := c.DelRefResponse(ctx, r, deref); err != nil {
 			slog.ErrorContext(ctx, "c.DelRefResponse", "err", err)
+		} else if c.Content != before {
+			snapshots = append(snapshots, &referencerelation.DerefSnapshot{
+				RefType:    referencerelation.SnapshotResponse,
+				RefID:      r.ID,
+				TargetType: referencerelation.SnapshotCollection,
+				TargetID:   c.ID,
+				Before:     before,
+				After:      c.Content,
+			})
 		}
 	}
 
-	return nil
+	return snapshots, nil
 }
 
 // clearRefCollectionsToResponse 清除collections引用response的引用关系
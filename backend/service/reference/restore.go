@@ -0,0 +1,211 @@
+package reference
+
+import (
+	"context"
+
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	referencerelation "github.com/apicat/apicat/v2/backend/model/reference_relation"
+	arrutil "github.com/apicat/apicat/v2/backend/utils/array"
+)
+
+// RestoreSchemaRef 从回收站恢复公共模型后重建引用关系
+// 删除时清除了自身引用的公共模型关系，以及其他条目引用自身的关系，
+// 先按删除时的快照还原被解引用的条目，再为内容中引用该模型的条目重建关系
+func RestoreSchemaRef(ctx context.Context, s *definition.DefinitionSchema) error {
+	// 重建引用关系(self -> schemas)
+	if err := UpdateSchemaRef(ctx, s, nil); err != nil {
+		return err
+	}
+
+	if err := restoreDerefSnapshots(ctx, s.ProjectID, referencerelation.SnapshotSchema, s.ID); err != nil {
+		return err
+	}
+
+	collections, err := collection.GetCollections(ctx, s.ProjectID)
+	if err != nil {
+		return err
+	}
+	var refCollections []*referencerelation.RefSchemaCollections
+	for _, c := range collections {
+		ids, err := ParseRefSchemasFromCollection(c)
+		if err != nil || !arrutil.InArray(s.ID, ids) {
+			continue
+		}
+		exist, err := referencerelation.GetRefSchemaCollection(ctx, c.ID, s.ID)
+		if err != nil {
+			return err
+		}
+		if len(exist) == 0 {
+			refCollections = append(refCollections, &referencerelation.RefSchemaCollections{RefSchemaID: s.ID, CollectionID: c.ID})
+		}
+	}
+	if err := referencerelation.BatchCreateRefSchemaCollections(ctx, refCollections); err != nil {
+		return err
+	}
+
+	responses, err := definition.GetDefinitionResponses(ctx, s.ProjectID)
+	if err != nil {
+		return err
+	}
+	var refResponses []*referencerelation.RefSchemaResponses
+	for _, r := range responses {
+		ids, err := ParseRefSchemasFromResponse(r)
+		if err != nil || !arrutil.InArray(s.ID, ids) {
+			continue
+		}
+		exist, err := referencerelation.GetRefSchemaResponse(ctx, r.ID, s.ID)
+		if err != nil {
+			return err
+		}
+		if len(exist) == 0 {
+			refResponses = append(refResponses, &referencerelation.RefSchemaResponses{RefSchemaID: s.ID, ResponseID: r.ID})
+		}
+	}
+	if err := referencerelation.BatchCreateRefSchemaResponses(ctx, refResponses); err != nil {
+		return err
+	}
+
+	schemas, err := definition.GetDefinitionSchemas(ctx, s.ProjectID)
+	if err != nil {
+		return err
+	}
+	var refSchemas []*referencerelation.RefSchemaSchemas
+	for _, v := range schemas {
+		if v.ID == s.ID {
+			continue
+		}
+		ids, err := ParseRefSchemasFromSchema(v)
+		if err != nil || !arrutil.InArray(s.ID, ids) {
+			continue
+		}
+		exist, err := referencerelation.GetRefSchemaSchema(ctx, v.ID, s.ID)
+		if err != nil {
+			return err
+		}
+		if len(exist) == 0 {
+			refSchemas = append(refSchemas, &referencerelation.RefSchemaSchemas{RefSchemaID: s.ID, SchemaID: v.ID})
+		}
+	}
+	return referencerelation.BatchCreateRefSchemaSchemas(ctx, refSchemas)
+}
+
+// RestoreResponseRef 从回收站恢复公共响应后重建引用关系，规则同RestoreSchemaRef
+func RestoreResponseRef(ctx context.Context, r *definition.DefinitionResponse) error {
+	// 重建引用关系(self -> schemas)
+	if err := UpdateResponseRef(ctx, r, nil); err != nil {
+		return err
+	}
+
+	if err := restoreDerefSnapshots(ctx, r.ProjectID, referencerelation.SnapshotResponse, r.ID); err != nil {
+		return err
+	}
+
+	collections, err := collection.GetCollections(ctx, r.ProjectID)
+	if err != nil {
+		return err
+	}
+	var refCollections []*referencerelation.RefResponseCollections
+	for _, c := range collections {
+		ids, err := ParseRefResponsesFromCollection(c)
+		if err != nil || !arrutil.InArray(r.ID, ids) {
+			continue
+		}
+		exist, err := referencerelation.GetRefResponseCollection(ctx, c.ID, r.ID)
+		if err != nil {
+			return err
+		}
+		if len(exist) == 0 {
+			refCollections = append(refCollections, &referencerelation.RefResponseCollections{RefResponserID: r.ID, CollectionID: c.ID})
+		}
+	}
+	return referencerelation.BatchCreateRefResponseCollections(ctx, refCollections)
+}
+
+// restoreDerefSnapshots 将删除时被解引用的条目还原为原内容，删除后又被修改过的条目保持展开后的内容
+func restoreDerefSnapshots(ctx context.Context, projectID, refType string, refID uint) error {
+	snapshots, err := referencerelation.GetDerefSnapshots(ctx, refType, refID)
+	if err != nil {
+		return err
+	}
+	for _, v := range snapshots {
+		if err := restoreDerefSnapshot(ctx, projectID, v); err != nil {
+			return err
+		}
+	}
+	return referencerelation.DelDerefSnapshots(ctx, refType, refID)
+}
+
+func restoreDerefSnapshot(ctx context.Context, projectID string, v *referencerelation.DerefSnapshot) error {
+	switch v.TargetType {
+	case referencerelation.SnapshotCollection:
+		c := &collection.Collection{ID: v.TargetID, ProjectID: projectID}
+		if exist, err := c.Get(ctx); err != nil || !exist {
+			return err
+		}
+		stale, ok := restorableContent(v, c.Content, func(content string) ([]uint, error) {
+			return ParseRefSchemasFromCollection(&collection.Collection{Content: content})
+		})
+		if !ok {
+			return nil
+		}
+		if err := model.DB(ctx).Model(c).UpdateColumn("content", v.Before).Error; err != nil {
+			return err
+		}
+		return referencerelation.DelRefSchemaCollection(ctx, c.ID, stale...)
+	case referencerelation.SnapshotResponse:
+		r := &definition.DefinitionResponse{ID: v.TargetID, ProjectID: projectID}
+		if exist, err := r.Get(ctx); err != nil || !exist {
+			return err
+		}
+		stale, ok := restorableContent(v, r.Content, func(content string) ([]uint, error) {
+			return ParseRefSchemasFromResponse(&definition.DefinitionResponse{Content: content})
+		})
+		if !ok {
+			return nil
+		}
+		if err := model.DB(ctx).Model(r).UpdateColumn("content", v.Before).Error; err != nil {
+			return err
+		}
+		return referencerelation.DelRefSchemaResponse(ctx, r.ID, stale...)
+	case referencerelation.SnapshotSchema:
+		ds := &definition.DefinitionSchema{ID: v.TargetID, ProjectID: projectID}
+		if exist, err := ds.Get(ctx); err != nil || !exist {
+			return err
+		}
+		stale, ok := restorableContent(v, ds.Schema, func(content string) ([]uint, error) {
+			return ParseRefSchemasFromSchema(&definition.DefinitionSchema{Schema: content})
+		})
+		if !ok {
+			return nil
+		}
+		if err := model.DB(ctx).Model(ds).UpdateColumn("schema", v.Before).Error; err != nil {
+			return err
+		}
+		return referencerelation.DelRefSchemaSchema(ctx, ds.ID, stale...)
+	}
+	return nil
+}
+
+// restorableContent 条目内容与解引用后一致时可以还原，返回解引用时展开进来、还原后不再直接引用的公共模型
+func restorableContent(v *referencerelation.DerefSnapshot, current string, parse func(string) ([]uint, error)) ([]uint, bool) {
+	if current != v.After {
+		return nil, false
+	}
+	before, err := parse(v.Before)
+	if err != nil {
+		return nil, false
+	}
+	after, err := parse(v.After)
+	if err != nil {
+		return nil, false
+	}
+	var stale []uint
+	for _, id := range after {
+		if !arrutil.InArray(id, before) {
+			stale = append(stale, id)
+		}
+	}
+	return stale, true
+}
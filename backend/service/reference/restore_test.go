@@ -0,0 +1,67 @@
+package reference
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	referencerelation "github.com/apicat/apicat/v2/backend/model/reference_relation"
+	arrutil "github.com/apicat/apicat/v2/backend/utils/array"
+)
+
+func parseSchemaRefs(content string) ([]uint, error) {
+	return ParseRefSchemasFromSchema(&definition.DefinitionSchema{Schema: content})
+}
+
+// TestRestorableContent 删除模型1时展开了引用它的模型3，恢复后模型3应重新引用模型1，并去掉展开时带进来的模型2
+func TestRestorableContent(t *testing.T) {
+	deleted := &definition.DefinitionSchema{ID: 1, Schema: `{"type":"object","properties":{"child":{"$ref":"#/definitions/schemas/2"}}}`}
+	referencer := &definition.DefinitionSchema{ID: 3, Schema: `{"type":"object","properties":{"user":{"$ref":"#/definitions/schemas/1"}}}`}
+
+	// 与DefinitionSchema.DelRef相同的展开过程
+	rs, err := referencer.ToSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := deleted.ToSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.Deref(ds); err != nil {
+		t.Fatal(err)
+	}
+	after, err := json.Marshal(rs.Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs, _ := parseSchemaRefs(string(after))
+	if arrutil.InArray(uint(1), refs) || !arrutil.InArray(uint(2), refs) {
+		t.Fatalf("deref refs = %v, want [2]", refs)
+	}
+
+	snapshot := &referencerelation.DerefSnapshot{
+		RefType:    referencerelation.SnapshotSchema,
+		RefID:      deleted.ID,
+		TargetType: referencerelation.SnapshotSchema,
+		TargetID:   referencer.ID,
+		Before:     referencer.Schema,
+		After:      string(after),
+	}
+	stale, ok := restorableContent(snapshot, string(after), parseSchemaRefs)
+	if !ok {
+		t.Fatal("unchanged content should be restorable")
+	}
+	if len(stale) != 1 || stale[0] != 2 {
+		t.Errorf("stale = %v, want [2]", stale)
+	}
+	// 还原后的内容重新引用模型1，RestoreSchemaRef据此重建引用关系
+	if refs, _ := parseSchemaRefs(snapshot.Before); !arrutil.InArray(deleted.ID, refs) {
+		t.Errorf("restored refs = %v, want to contain 1", refs)
+	}
+
+	// 删除后又修改过的内容不还原
+	if _, ok := restorableContent(snapshot, `{"type":"object"}`, parseSchemaRefs); ok {
+		t.Error("modified content should not be restored")
+	}
+}
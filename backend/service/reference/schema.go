@@ -75,7 +75,8 @@ func DerefSchema(ctx context.Context, s *definition.DefinitionSchema, deref bool
 	}
 
 	// 在collection中解引用schema
-	if err := derefSchemaFromCollections(ctx, s, cIDs, deref); err != nil {
+	snapshots, err := derefSchemaFromCollections(ctx, s, cIDs, deref)
+	if err != nil {
 		return err
 	}
 
@@ -86,9 +87,11 @@ func DerefSchema(ctx context.Context, s *definition.DefinitionSchema, deref bool
 	}
 
 	// 在response中解引用schema
-	if err := derefSchemaFromResponses(ctx, s, rIDs, deref); err != nil {
+	list, err := derefSchemaFromResponses(ctx, s, rIDs, deref)
+	if err != nil {
 		return err
 	}
+	snapshots = append(snapshots, list...)
 
 	rs := referencerelation.RefSchemaSchemas{RefSchemaID: s.ID}
 	schemaIDs, err := rs.GetSchemaIDs(ctx)
@@ -96,7 +99,14 @@ func DerefSchema(ctx context.Context, s *definition.DefinitionSchema, deref bool
 		return err
 	}
 	// 在schema中解引用schema
-	if err := derefSchemaFromSchemas(ctx, s, schemaIDs, deref); err != nil {
+	list, err = derefSchemaFromSchemas(ctx, s, schemaIDs, deref)
+	if err != nil {
+		return err
+	}
+	snapshots = append(snapshots, list...)
+
+	// 记录被解引用条目的原内容，从回收站恢复时还原引用
+	if err := referencerelation.BatchCreateDerefSnapshots(ctx, snapshots); err != nil {
 		return err
 	}
 
@@ -137,57 +147,80 @@ func DerefSchema(ctx context.Context, s *definition.DefinitionSchema, deref bool
 }
 
 // derefSchemaFromSchemas 从公共模型中解引用公共模型
-func derefSchemaFromSchemas(ctx context.Context, s *definition.DefinitionSchema, schemaIDs []uint, deref bool) error {
+func derefSchemaFromSchemas(ctx context.Context, s *definition.DefinitionSchema, schemaIDs []uint, deref bool) ([]*referencerelation.DerefSnapshot, error) {
 	if len(schemaIDs) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	schemas, err := definition.GetDefinitionSchemas(ctx, s.ProjectID, schemaIDs...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var snapshots []*referencerelation.DerefSnapshot
 	for _, schema := range schemas {
-		schema.DelRef(ctx, s, deref)
+		before := schema.Schema
+		if err := schema.DelRef(ctx, s, deref); err == nil && schema.Schema != before {
+			snapshots = append(snapshots, newSchemaSnapshot(s.ID, referencerelation.SnapshotSchema, schema.ID, before, schema.Schema))
+		}
 	}
 
-	return nil
+	return snapshots, nil
 }
 
 // derefSchemaFromResponses 从公共响应中解引用公共模型
-func derefSchemaFromResponses(ctx context.Context, s *definition.DefinitionSchema, responseIDs []uint, deref bool) error {
+func derefSchemaFromResponses(ctx context.Context, s *definition.DefinitionSchema, responseIDs []uint, deref bool) ([]*referencerelation.DerefSnapshot, error) {
 	if len(responseIDs) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	responses, err := definition.GetDefinitionResponses(ctx, s.ProjectID, responseIDs...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var snapshots []*referencerelation.DerefSnapshot
 	for _, response := range responses {
-		response.DelRef(ctx, s, deref)
+		before := response.Content
+		if err := response.DelRef(ctx, s, deref); err == nil && response.Content != before {
+			snapshots = append(snapshots, newSchemaSnapshot(s.ID, referencerelation.SnapshotResponse, response.ID, before, response.Content))
+		}
 	}
 
-	return nil
+	return snapshots, nil
 }
 
 // derefSchemaFromCollections 从集合中解引用公共模型
-func derefSchemaFromCollections(ctx context.Context, s *definition.DefinitionSchema, collectionIDs []uint, deref bool) error {
+func derefSchemaFromCollections(ctx context.Context, s *definition.DefinitionSchema, collectionIDs []uint, deref bool) ([]*referencerelation.DerefSnapshot, error) {
 	if len(collectionIDs) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	collections, err := collection.GetCollections(ctx, s.ProjectID, collectionIDs...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var snapshots []*referencerelation.DerefSnapshot
 	for _, c := range collections {
-		c.DelRefSchema(ctx, s, deref)
+		before := c.Content
+		if err := c.DelRefSchema(ctx, s, deref); err == nil && c.Content != before {
+			snapshots = append(snapshots, newSchemaSnapshot(s.ID, referencerelation.SnapshotCollection, c.ID, before, c.Content))
+		}
 	}
 
-	return nil
+	return snapshots, nil
+}
+
+func newSchemaSnapshot(sID uint, targetType string, targetID uint, before, after string) *referencerelation.DerefSnapshot {
+	return &referencerelation.DerefSnapshot{
+		RefType:    referencerelation.SnapshotSchema,
+		RefID:      sID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     before,
+		After:      after,
+	}
 }
 
 // clearRefCollectionsToSchema 清除collections引用schema的引用关系
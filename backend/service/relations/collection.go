@@ -107,6 +107,33 @@ func DeleteCollections(ctx context.Context, pID string, c *collection.Collection
 	return collection.BatchDeleteCollections(ctx, tm.ID, ids...)
 }
 
+// UpdateCollection 修改文档内容并维护引用关系，与直接编辑文档一样记录历史
+func UpdateCollection(ctx context.Context, c *collection.Collection, title, content string, memberID uint) error {
+	oldSchemaIDs, err := reference.ParseRefSchemasFromCollection(c)
+	if err != nil {
+		return err
+	}
+	oldResponseIDs, err := reference.ParseRefResponsesFromCollection(c)
+	if err != nil {
+		return err
+	}
+	oldParamIDs, err := except.ParseExceptParamsFromCollection(c)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Update(ctx, title, content, memberID); err != nil {
+		return err
+	}
+	c.Title = title
+	if c.Type != collection.CategoryType {
+		if err := reference.UpdateCollectionRef(ctx, c, oldSchemaIDs, oldResponseIDs, oldParamIDs); err != nil {
+			slog.ErrorContext(ctx, "reference.UpdateCollectionRef", "err", err)
+		}
+	}
+	return nil
+}
+
 // CollectionDerefWithSpec 将集合解引用并转为spec.collection结构
 func CollectionDerefWithSpec(ctx context.Context, c *collection.Collection) (*spec.Collection, error) {
 	collectionSpec, err := c.ToSpec()
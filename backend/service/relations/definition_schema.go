@@ -63,3 +63,24 @@ func ImportDefinitionSchemas(ctx context.Context, projectID string, schemas spec
 
 	return res
 }
+
+// UpdateSchema 修改模型并维护引用关系，与直接编辑模型一样记录历史
+func UpdateSchema(ctx context.Context, ds *definition.DefinitionSchema, name, description, schema string, memberID uint) error {
+	oldRefSchemaIDs, err := reference.ParseRefSchemasFromSchema(ds)
+	if err != nil {
+		return err
+	}
+
+	if err := ds.Update(ctx, name, description, schema, memberID); err != nil {
+		return err
+	}
+	ds.Name = name
+	ds.Description = description
+	ds.Schema = schema
+	if ds.Type != definition.SchemaCategory {
+		if err := reference.UpdateSchemaRef(ctx, ds, oldRefSchemaIDs); err != nil {
+			slog.ErrorContext(ctx, "reference.UpdateSchemaRef", "err", err)
+		}
+	}
+	return nil
+}
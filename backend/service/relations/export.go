@@ -0,0 +1,43 @@
+package relations
+
+import (
+	"context"
+
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/module/spec"
+	"github.com/apicat/apicat/v2/backend/module/spec/plugin/export"
+	"github.com/apicat/apicat/v2/backend/module/spec/plugin/openapi"
+)
+
+// ProjectExportSpec 项目导出使用的完整spec，保留目录结构
+func ProjectExportSpec(ctx context.Context, p *project.Project) *spec.Spec {
+	s := spec.NewEmptySpec()
+	SpecFillInfo(ctx, s, p)
+	SpecFillServers(ctx, s, p.ID)
+	SpecFillGlobals(ctx, s, p.ID)
+	SpecFillDefinitions(ctx, s, p.ID)
+	SpecFillCollections(ctx, s, p.ID)
+	return s
+}
+
+// ExportContent 将spec转换为指定的导出格式
+func ExportContent(s *spec.Spec, typ string) ([]byte, error) {
+	switch typ {
+	case "swagger":
+		return openapi.Generate(s, "2.0", "json")
+	case "openapi3.0.0":
+		return openapi.Generate(s, "3.0.0", "json")
+	case "openapi3.0.1":
+		return openapi.Generate(s, "3.0.1", "json")
+	case "openapi3.0.2":
+		return openapi.Generate(s, "3.0.2", "json")
+	case "openapi3.1.0":
+		return openapi.Generate(s, "3.1.0", "json")
+	case "HTML":
+		return export.HTML(s)
+	case "md":
+		return export.Markdown(s)
+	default:
+		return s.ToJSON(spec.JSONOption{Indent: "  "})
+	}
+}
@@ -1,6 +1,8 @@
 package relations
 
 import (
+	"context"
+
 	"github.com/apicat/apicat/v2/backend/model/collection"
 	"github.com/apicat/apicat/v2/backend/model/definition"
 	"github.com/apicat/apicat/v2/backend/model/global"
@@ -36,7 +38,7 @@ func NewApiCatPopulatePublicData(ctx *gin.Context, p *project.Project) *spec.Spe
 }
 
 // SpecFillInfo 填充Info数据到spec
-func SpecFillInfo(ctx *gin.Context, s *spec.Spec, p *project.Project) {
+func SpecFillInfo(ctx context.Context, s *spec.Spec, p *project.Project) {
 	s.Info = spec.Info{
 		ID:          p.ID,
 		Title:       p.Title,
@@ -45,43 +47,43 @@ func SpecFillInfo(ctx *gin.Context, s *spec.Spec, p *project.Project) {
 }
 
 // SpecFillServers 填充Servers数据到spec
-func SpecFillServers(ctx *gin.Context, s *spec.Spec, pID string) {
+func SpecFillServers(ctx context.Context, s *spec.Spec, pID string) {
 	s.Servers = project.ExportServers(ctx, pID)
 }
 
 // SpecFillGlobals 填充Globals数据到spec
-func SpecFillGlobals(ctx *gin.Context, s *spec.Spec, pID string) {
+func SpecFillGlobals(ctx context.Context, s *spec.Spec, pID string) {
 	SpecFillGlobalParameters(ctx, s, pID)
 }
 
 // SpecFillDefinitions 填充Definitions数据到spec
-func SpecFillDefinitions(ctx *gin.Context, s *spec.Spec, pID string) {
+func SpecFillDefinitions(ctx context.Context, s *spec.Spec, pID string) {
 	SpecFillDefinitionSchemas(ctx, s, pID)
 	// SpecFillDefinitionParameters(ctx, s, pID)
 	SpecFillDefinitionResponses(ctx, s, pID)
 }
 
 // SpecFillGlobalParameters 填充Global.parameters数据到spec
-func SpecFillGlobalParameters(ctx *gin.Context, s *spec.Spec, pID string) {
+func SpecFillGlobalParameters(ctx context.Context, s *spec.Spec, pID string) {
 	s.Globals.Parameters = global.ExportGlobalParameters(ctx, pID)
 }
 
 // SpecFillDefinitionSchemas 填充Definitions.schemas数据到spec
-func SpecFillDefinitionSchemas(ctx *gin.Context, s *spec.Spec, pID string) {
+func SpecFillDefinitionSchemas(ctx context.Context, s *spec.Spec, pID string) {
 	s.Definitions.Schemas = definition.ExportDefinitionSchemas(ctx, &project.Project{ID: pID})
 }
 
 // SpecFillDefinitionParameters 填充Definitions.parameters数据到spec
-// func SpecFillDefinitionParameters(ctx *gin.Context, s *spec.Spec, pID string) {
+// func SpecFillDefinitionParameters(ctx context.Context, s *spec.Spec, pID string) {
 // 	s.Definitions.Parameters = definition.ExportDefinitionParameters(ctx, pID)
 // }
 
 // SpecFillDefinitionResponses 填充Definitions.responses数据到spec
-func SpecFillDefinitionResponses(ctx *gin.Context, s *spec.Spec, pID string) {
+func SpecFillDefinitionResponses(ctx context.Context, s *spec.Spec, pID string) {
 	s.Definitions.Responses = definition.ExportDefinitionResponses(ctx, &project.Project{ID: pID})
 }
 
 // SpecFillCollections 填充Collections数据到spec
-func SpecFillCollections(ctx *gin.Context, s *spec.Spec, pID string) {
+func SpecFillCollections(ctx context.Context, s *spec.Spec, pID string) {
 	s.Collections = collection.ExportCollections(ctx, pID)
 }
@@ -0,0 +1,200 @@
+package relations
+
+import (
+	"context"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/global"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/module/spec"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectSpec 项目当前的快照，用于项目对比，接口和模型没有目录结构
+func ProjectSpec(ctx context.Context, p *project.Project) (*spec.Spec, error) {
+	s, err := newSnapshot(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Definitions.Schemas, err = definition.GetDefinitionSchemasWithSpec(ctx, p.ID); err != nil {
+		return nil, err
+	}
+
+	collections, err := collection.GetCollections(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range collections {
+		if c.Type == collection.CategoryType {
+			continue
+		}
+		sc, err := c.ToSpec()
+		if err != nil {
+			return nil, err
+		}
+		s.Collections = append(s.Collections, sc)
+	}
+	return s, nil
+}
+
+// ProjectSpecAt 项目在某一时间点的快照
+// 接口和公共模型从历史记录中还原，公共响应和全局参数没有历史记录，使用当前的内容
+func ProjectSpecAt(ctx context.Context, p *project.Project, t time.Time) (*spec.Spec, error) {
+	if !t.Before(time.Now()) {
+		return ProjectSpec(ctx, p)
+	}
+
+	s, err := newSnapshot(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := snapshotSchemasAt(ctx, s, p.ID, t); err != nil {
+		return nil, err
+	}
+	if err := snapshotCollectionsAt(ctx, s, p.ID, t); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ProjectReleaseSpec 项目当前完整的spec，用于发布版本
+// 接口中的公共模型、公共响应和全局参数都已展开，所以不再保留全局参数，避免导出时重复
+func ProjectReleaseSpec(ctx *gin.Context, p *project.Project) (*spec.Spec, error) {
+	s := spec.NewEmptySpec()
+	SpecFillInfo(ctx, s, p)
+	SpecFillServers(ctx, s, p.ID)
+	SpecFillGlobals(ctx, s, p.ID)
+	SpecFillDefinitions(ctx, s, p.ID)
+	SpecFillCollections(ctx, s, p.ID)
+
+	definitions := &spec.Definitions{
+		Schemas:   make(spec.DefinitionModels, 0),
+		Responses: make(spec.DefinitionResponses, 0),
+	}
+	for _, m := range s.Definitions.Schemas {
+		definitions.Schemas = append(definitions.Schemas, m.ItemsTreeToList()...)
+	}
+	for _, r := range s.Definitions.Responses {
+		definitions.Responses = append(definitions.Responses, r.ItemsTreeToList()...)
+	}
+	if err := derefCollections(s.Collections, s.Globals.Parameters, definitions); err != nil {
+		return nil, err
+	}
+	s.Globals.Parameters = spec.NewGlobalParameters()
+	return s, nil
+}
+
+func derefCollections(collections spec.Collections, params *spec.GlobalParameters, definitions *spec.Definitions) error {
+	for _, c := range collections {
+		if c.Type == spec.TYPE_CATEGORY {
+			if err := derefCollections(c.Items, params, definitions); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := c.Content.DeepDerefAll(params, definitions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newSnapshot(ctx context.Context, p *project.Project) (*spec.Spec, error) {
+	s := spec.NewEmptySpec()
+	s.Info = spec.Info{
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+	}
+
+	var err error
+	if s.Globals.Parameters, err = global.GetGlobalParametersWithSpec(ctx, p.ID); err != nil {
+		return nil, err
+	}
+	if s.Definitions.Responses, err = definition.GetDefinitionResponsesWithSpec(ctx, p.ID); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// snapshotSchemasAt 模型历史保存的是修改前的内容，时间点之后的第一条历史就是当时的模型，没有则说明之后未修改过
+func snapshotSchemasAt(ctx context.Context, s *spec.Spec, pID string, t time.Time) error {
+	schemas, err := definition.GetDefinitionSchemasAt(ctx, pID, t)
+	if err != nil || len(schemas) == 0 {
+		return err
+	}
+
+	ids := make([]uint, len(schemas))
+	for i, ds := range schemas {
+		ids[i] = ds.ID
+	}
+	histories, err := definition.GetDefinitionSchemaHistoriesAfter(ctx, ids, t)
+	if err != nil {
+		return err
+	}
+
+	for _, ds := range schemas {
+		if h, ok := histories[ds.ID]; ok {
+			ds.Name = h.Name
+			ds.Description = h.Description
+			ds.Schema = h.Schema
+		}
+		m, err := ds.ToSpec()
+		if err != nil {
+			return err
+		}
+		s.Definitions.Schemas = append(s.Definitions.Schemas, m)
+	}
+	return nil
+}
+
+// snapshotCollectionsAt 接口历史保存的是修改后的内容，时间点之前的最后一条历史就是当时的接口
+// 没有则说明当时还未修改过，此时若之后也未修改过则使用当前内容，否则创建时的内容已无记录，使用最早的历史近似
+func snapshotCollectionsAt(ctx context.Context, s *spec.Spec, pID string, t time.Time) error {
+	collections, err := collection.GetCollectionsAt(ctx, pID, t)
+	if err != nil || len(collections) == 0 {
+		return err
+	}
+
+	ids := make([]uint, len(collections))
+	for i, c := range collections {
+		ids[i] = c.ID
+	}
+	histories, err := collection.GetCollectionHistoriesBefore(ctx, ids, t)
+	if err != nil {
+		return err
+	}
+
+	missing := make([]uint, 0)
+	for _, c := range collections {
+		if _, ok := histories[c.ID]; !ok && c.UpdatedAt.After(t) {
+			missing = append(missing, c.ID)
+		}
+	}
+	if len(missing) > 0 {
+		first, err := collection.GetFirstCollectionHistories(ctx, missing)
+		if err != nil {
+			return err
+		}
+		for id, h := range first {
+			histories[id] = h
+		}
+	}
+
+	for _, c := range collections {
+		if h, ok := histories[c.ID]; ok {
+			c.Title = h.Title
+			c.Content = h.Content
+		}
+		sc, err := c.ToSpec()
+		if err != nil {
+			return err
+		}
+		s.Collections = append(s.Collections, sc)
+	}
+	return nil
+}
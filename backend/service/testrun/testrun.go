@@ -0,0 +1,163 @@
+package testrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/module/testcase"
+	"github.com/apicat/apicat/v2/backend/service/relations"
+	"github.com/apicat/apicat/v2/backend/utils/safehttp"
+)
+
+const requestTimeout = 30 * time.Second
+
+var (
+	// mockClient 请求内置的 mock 服务，地址来自配置
+	mockClient = &http.Client{
+		Timeout:       requestTimeout,
+		CheckRedirect: safehttp.NoRedirect,
+	}
+	// client 请求项目服务地址，不允许访问本机和内网地址，不跟随重定向，由断言判断
+	client = safehttp.NewClient(requestTimeout)
+)
+
+// Target 测试用例的执行地址
+type Target struct {
+	BaseURL string
+	// Mock 是否为内置的 mock 服务
+	Mock bool
+}
+
+func (t *Target) client() *http.Client {
+	if t.Mock {
+		return mockClient
+	}
+	return client
+}
+
+// GetTarget 测试用例的执行地址，serverID 为 0 时使用内置的 mock 服务
+func GetTarget(ctx context.Context, projectID string, serverID uint) (*Target, bool, error) {
+	if serverID == 0 {
+		return &Target{
+			BaseURL: fmt.Sprintf("%s/mock/%s", strings.TrimSuffix(config.Get().App.MockUrl, "/"), projectID),
+			Mock:    true,
+		}, true, nil
+	}
+	s := &project.Server{ID: serverID}
+	exist, err := s.Get(ctx)
+	if err != nil || !exist || s.ProjectID != projectID {
+		return nil, false, err
+	}
+	return &Target{BaseURL: s.URL}, true, nil
+}
+
+// Record 单个测试用例的执行结果
+type Record struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	*testcase.Result
+}
+
+// Report 接口下所有测试用例的执行结果
+type Report struct {
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+	// Skipped 没有可执行步骤或执行出错的用例数量
+	Skipped int       `json:"skipped"`
+	Records []*Record `json:"records"`
+}
+
+// Run 执行测试用例，未指定接口的步骤请求用例所属的接口
+func Run(ctx context.Context, tc *collection.TestCase, t *Target) (*testcase.Result, error) {
+	c, err := testcase.Parse(tc.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make(map[uint]*testcase.Endpoint)
+	r := &testcase.Runner{
+		Client:  t.client(),
+		BaseURL: t.BaseURL,
+		Endpoint: func(ctx context.Context, collectionID uint) (*testcase.Endpoint, error) {
+			if collectionID == 0 {
+				collectionID = tc.CollectionID
+			}
+			if ep, ok := endpoints[collectionID]; ok {
+				return ep, nil
+			}
+			ep, err := endpoint(ctx, tc.ProjectID, collectionID)
+			if err != nil {
+				return nil, err
+			}
+			endpoints[collectionID] = ep
+			return ep, nil
+		},
+	}
+	return r.Run(ctx, c), nil
+}
+
+// RunAll 依次执行接口下所有可执行的测试用例
+func RunAll(ctx context.Context, projectID string, collectionID uint, t *Target, progress func(int)) (*Report, error) {
+	testCases, err := collection.GetTestCases(ctx, projectID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Report{Records: make([]*Record, 0, len(testCases))}
+	for k, tc := range testCases {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		progress(k * 100 / len(testCases))
+		if tc.Spec == "" {
+			res.Skipped++
+			continue
+		}
+		result, err := Run(ctx, tc, t)
+		if err != nil {
+			slog.ErrorContext(ctx, "testrun.Run", "err", err, "testCase", tc.ID)
+			res.Skipped++
+			continue
+		}
+		if result.Passed {
+			res.Passed++
+		} else {
+			res.Failed++
+		}
+		res.Records = append(res.Records, &Record{ID: tc.ID, Title: tc.Title, Result: result})
+	}
+	return res, nil
+}
+
+func endpoint(ctx context.Context, projectID string, collectionID uint) (*testcase.Endpoint, error) {
+	c := &collection.Collection{ID: collectionID, ProjectID: projectID}
+	exist, err := c.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || c.Type != collection.HttpType {
+		return nil, fmt.Errorf("collection %d does not exist", collectionID)
+	}
+
+	sc, err := relations.CollectionDerefWithSpec(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	url := sc.Content.GetUrl()
+	if url == nil {
+		return nil, errors.New("collection url is empty")
+	}
+	ep := &testcase.Endpoint{Method: url.Attrs.Method, Path: url.Attrs.Path}
+	if res := sc.Content.GetResponse(); res != nil && res.Attrs != nil {
+		ep.Responses = res.Attrs.List
+	}
+	return ep, nil
+}
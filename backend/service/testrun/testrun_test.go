@@ -0,0 +1,36 @@
+package testrun
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apicat/apicat/v2/backend/utils/safehttp"
+)
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/target", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// 项目服务地址不能指向本机
+	if _, err := client.Get(srv.URL); !errors.Is(err, safehttp.ErrForbiddenAddress) {
+		t.Errorf("client.Get loopback err = %v, want %v", err, safehttp.ErrForbiddenAddress)
+	}
+
+	// 不跟随重定向
+	resp, err := mockClient.Get(srv.URL + "/redirect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+}
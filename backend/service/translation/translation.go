@@ -0,0 +1,288 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/translation"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
+	"github.com/apicat/apicat/v2/backend/service/ai"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	batchMaxTexts = 40
+	batchMaxRunes = 4000
+)
+
+// translatableKeys 需要翻译的字段，文档节点的文本和接口、参数、模型的描述
+var translatableKeys = map[string]bool{
+	"text":        true,
+	"description": true,
+	"summary":     true,
+}
+
+// skipKeys 示例值和 mock 规则属于数据，不翻译
+var skipKeys = map[string]bool{
+	"example":       true,
+	"examples":      true,
+	"default":       true,
+	"enum":          true,
+	"x-apicat-mock": true,
+}
+
+// CollectionHash 集合标题和内容的摘要，内容变化后旧译文失效
+func CollectionHash(c *collection.Collection) string {
+	return hash(c.Title, c.Content)
+}
+
+// SchemaHash 模型描述和内容的摘要
+func SchemaHash(ds *definition.DefinitionSchema) string {
+	return hash(ds.Description, ds.Schema)
+}
+
+func hash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// TranslateCollection 翻译集合的标题和文档内容
+func TranslateCollection(ctx context.Context, c *collection.Collection, locale string) (*translation.Translation, error) {
+	tr := newTranslator()
+	tr.add(c.Title)
+	content, err := tr.collect(c.Content)
+	if err != nil {
+		return nil, err
+	}
+	if err := tr.translate(ctx, locale); err != nil {
+		return nil, err
+	}
+
+	t := &translation.Translation{
+		ProjectID:  c.ProjectID,
+		TargetType: translation.TargetCollection,
+		TargetID:   c.ID,
+		Locale:     locale,
+		Title:      tr.get(c.Title),
+		SourceHash: CollectionHash(c),
+	}
+	if t.Content, err = tr.apply(content); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// TranslateSchema 翻译模型的描述和属性描述，模型名称保持不变
+func TranslateSchema(ctx context.Context, ds *definition.DefinitionSchema, locale string) (*translation.Translation, error) {
+	tr := newTranslator()
+	tr.add(ds.Description)
+	schema, err := tr.collect(ds.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := tr.translate(ctx, locale); err != nil {
+		return nil, err
+	}
+
+	t := &translation.Translation{
+		ProjectID:   ds.ProjectID,
+		TargetType:  translation.TargetSchema,
+		TargetID:    ds.ID,
+		Locale:      locale,
+		Description: tr.get(ds.Description),
+		SourceHash:  SchemaHash(ds),
+	}
+	if t.Content, err = tr.apply(schema); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+type translator struct {
+	texts  []string
+	result map[string]string
+}
+
+func newTranslator() *translator {
+	return &translator{result: make(map[string]string)}
+}
+
+// add 记录需要翻译的文本，重复的文本只翻译一次
+func (tr *translator) add(s string) {
+	if strings.TrimSpace(s) == "" {
+		return
+	}
+	if _, ok := tr.result[s]; ok {
+		return
+	}
+	tr.result[s] = s
+	tr.texts = append(tr.texts, s)
+}
+
+func (tr *translator) get(s string) string {
+	if v, ok := tr.result[s]; ok {
+		return v
+	}
+	return s
+}
+
+// collect 解析 JSON 内容并记录其中需要翻译的文本
+func (tr *translator) collect(content string) (any, error) {
+	if content == "" {
+		return nil, nil
+	}
+	dec := json.NewDecoder(strings.NewReader(content))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	walkTexts(v, func(s string) string {
+		tr.add(s)
+		return s
+	})
+	return v, nil
+}
+
+// apply 将译文替换回内容，结构和其他字段保持不变
+func (tr *translator) apply(v any) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	walkTexts(v, tr.get)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// translate 分批翻译所有记录的文本
+func (tr *translator) translate(ctx context.Context, locale string) error {
+	for _, batch := range batches(tr.texts) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		res, err := ai.Translate(ctx, locale, batch)
+		if err != nil {
+			return err
+		}
+		for i, s := range batch {
+			if v := strings.TrimSpace(res[i]); v != "" {
+				tr.result[s] = res[i]
+			}
+		}
+	}
+	return nil
+}
+
+func batches(texts []string) [][]string {
+	var (
+		res   [][]string
+		cur   []string
+		runes int
+	)
+	for _, s := range texts {
+		n := len([]rune(s))
+		if len(cur) > 0 && (len(cur) >= batchMaxTexts || runes+n > batchMaxRunes) {
+			res = append(res, cur)
+			cur, runes = nil, 0
+		}
+		cur = append(cur, s)
+		runes += n
+	}
+	if len(cur) > 0 {
+		res = append(res, cur)
+	}
+	return res
+}
+
+// walkTexts 遍历 JSON 中需要翻译的字符串，代码块和行内代码跳过
+func walkTexts(v any, fn func(string) string) {
+	switch x := v.(type) {
+	case map[string]any:
+		if isCode(x) {
+			return
+		}
+		for k, val := range x {
+			if skipKeys[k] {
+				continue
+			}
+			if s, ok := val.(string); ok {
+				if translatableKeys[k] && s != "" {
+					x[k] = fn(s)
+				}
+				continue
+			}
+			walkTexts(val, fn)
+		}
+	case []any:
+		for _, val := range x {
+			walkTexts(val, fn)
+		}
+	}
+}
+
+func isCode(node map[string]any) bool {
+	if t, _ := node["type"].(string); t == "codeBlock" || t == "code_block" {
+		return true
+	}
+	for _, key := range []string{"mark", "marks"} {
+		marks, _ := node[key].([]any)
+		for _, m := range marks {
+			if mm, ok := m.(map[string]any); ok && mm["type"] == "code" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Locale 确定查看时使用的语言，优先使用请求指定的语言
+// 项目成员未指定时返回空，编辑器总是拿到源内容，避免保存时用译文覆盖源内容
+// 非项目成员使用登录用户的语言，访客未指定时返回空
+func Locale(ctx *gin.Context, lang string) string {
+	if lang != "" {
+		return lang
+	}
+	if access.GetSelfProjectMember(ctx) != nil {
+		return ""
+	}
+	if u := jwt.GetUser(ctx); u != nil {
+		return u.Language
+	}
+	return ""
+}
+
+// GetCollectionTranslation 获取集合在指定语言下的译文，没有译文或源内容已变化时返回 nil
+func GetCollectionTranslation(ctx context.Context, c *collection.Collection, locale string) (*translation.Translation, error) {
+	return getTranslation(ctx, translation.TargetCollection, c.ID, locale, CollectionHash(c))
+}
+
+// GetSchemaTranslation 获取模型在指定语言下的译文，没有译文或源内容已变化时返回 nil
+func GetSchemaTranslation(ctx context.Context, ds *definition.DefinitionSchema, locale string) (*translation.Translation, error) {
+	return getTranslation(ctx, translation.TargetSchema, ds.ID, locale, SchemaHash(ds))
+}
+
+func getTranslation(ctx context.Context, targetType string, targetID uint, locale, sourceHash string) (*translation.Translation, error) {
+	if locale == "" {
+		return nil, nil
+	}
+	t := &translation.Translation{TargetType: targetType, TargetID: targetID, Locale: locale}
+	exist, err := t.Get(ctx)
+	if err != nil || !exist || t.SourceHash != sourceHash {
+		return nil, err
+	}
+	return t, nil
+}
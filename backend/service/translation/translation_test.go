@@ -0,0 +1,64 @@
+package translation
+
+import "testing"
+
+func TestTranslatorApply(t *testing.T) {
+	content := `[{"type":"paragraph","content":[{"type":"text","text":"获取用户"},{"type":"text","text":"id","mark":[{"type":"code"}]}]},` +
+		`{"type":"codeBlock","content":[{"type":"text","text":"curl <url>"}]},` +
+		`{"type":"apicat-http-request","attrs":{"parameters":{"query":[{"name":"page","schema":{"type":"integer","description":"页码","examples":{"description":"示例"}}}]}}},` +
+		`{"type":"apicat-http-url","attrs":{"path":"/users","method":"get"}}]`
+
+	tr := newTranslator()
+	tr.add("用户")
+	v, err := tr.collect(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"用户", "获取用户", "页码"}; !equal(tr.texts, want) {
+		t.Fatalf("collected %v, want %v", tr.texts, want)
+	}
+
+	tr.result["获取用户"] = "Get user"
+	tr.result["页码"] = "Page number"
+	got, err := tr.apply(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"content":[{"text":"Get user","type":"text"},{"mark":[{"type":"code"}],"text":"id","type":"text"}],"type":"paragraph"},` +
+		`{"content":[{"text":"curl <url>","type":"text"}],"type":"codeBlock"},` +
+		`{"attrs":{"parameters":{"query":[{"name":"page","schema":{"description":"Page number","examples":{"description":"示例"},"type":"integer"}}]}},"type":"apicat-http-request"},` +
+		`{"attrs":{"method":"get","path":"/users"},"type":"apicat-http-url"}]`
+	if got != want {
+		t.Errorf("apply =\n%s\nwant\n%s", got, want)
+	}
+	if tr.get("用户") != "用户" {
+		t.Errorf("untranslated text should be kept")
+	}
+}
+
+func TestBatches(t *testing.T) {
+	texts := make([]string, 0, batchMaxTexts+1)
+	for i := 0; i <= batchMaxTexts; i++ {
+		texts = append(texts, "a")
+	}
+	if got := batches(texts); len(got) != 2 || len(got[0]) != batchMaxTexts {
+		t.Errorf("batches by count = %d", len(got))
+	}
+
+	long := string(make([]rune, batchMaxRunes))
+	if got := batches([]string{"a", long, "b"}); len(got) != 3 {
+		t.Errorf("batches by length = %d, want 3", len(got))
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package trash
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/config"
+	"github.com/apicat/apicat/v2/backend/model"
+	"github.com/apicat/apicat/v2/backend/model/collection"
+	"github.com/apicat/apicat/v2/backend/model/definition"
+	"github.com/apicat/apicat/v2/backend/model/embedding"
+	"github.com/apicat/apicat/v2/backend/model/global"
+	"github.com/apicat/apicat/v2/backend/model/iteration"
+	"github.com/apicat/apicat/v2/backend/model/job"
+	"github.com/apicat/apicat/v2/backend/model/notification"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/proposal"
+	referencerelation "github.com/apicat/apicat/v2/backend/model/reference_relation"
+	"github.com/apicat/apicat/v2/backend/model/release"
+	"github.com/apicat/apicat/v2/backend/model/share"
+	"github.com/apicat/apicat/v2/backend/model/translation"
+	"github.com/apicat/apicat/v2/backend/model/webhook"
+
+	"gorm.io/gorm"
+)
+
+const pollInterval = 6 * time.Hour
+
+// Run 后台清理worker，定时彻底删除回收站中超过保留天数的内容，直到ctx结束
+func Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if before := config.Get().App.TrashDeadline(time.Now()); !before.IsZero() {
+			Purge(ctx, before)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge 彻底删除before之前删除的公共模型、公共响应和项目，并清理已失效的译文
+func Purge(ctx context.Context, before time.Time) {
+	if n, err := definition.PurgeDefinitionSchemas(ctx, before); err != nil {
+		slog.ErrorContext(ctx, "definition.PurgeDefinitionSchemas", "err", err)
+	} else if n > 0 {
+		slog.InfoContext(ctx, "purge definition schemas", "count", n)
+	}
+
+	if n, err := definition.PurgeDefinitionResponses(ctx, before); err != nil {
+		slog.ErrorContext(ctx, "definition.PurgeDefinitionResponses", "err", err)
+	} else if n > 0 {
+		slog.InfoContext(ctx, "purge definition responses", "count", n)
+	}
+
+	if n, err := translation.DeleteOrphanTranslations(ctx); err != nil {
+		slog.ErrorContext(ctx, "translation.DeleteOrphanTranslations", "err", err)
+	} else if n > 0 {
+		slog.InfoContext(ctx, "purge translations", "count", n)
+	}
+
+	projects, err := project.GetExpiredDeletedProjects(ctx, before)
+	if err != nil {
+		slog.ErrorContext(ctx, "project.GetExpiredDeletedProjects", "err", err)
+		return
+	}
+	for _, p := range projects {
+		if err := purgeProject(ctx, p); err != nil {
+			slog.ErrorContext(ctx, "trash.purgeProject", "err", err, "project", p.ID)
+			continue
+		}
+		slog.InfoContext(ctx, "purge project", "project", p.ID)
+	}
+}
+
+// purgeProject 彻底删除项目及其所有内容，审计日志保留
+func purgeProject(ctx context.Context, p *project.Project) error {
+	return model.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		sub := func(m interface{}, column string) *gorm.DB {
+			return tx.Session(&gorm.Session{NewDB: true}).Unscoped().Model(m).Select(column).Where("project_id = ?", p.ID)
+		}
+		collectionIDs := sub(&collection.Collection{}, "id")
+		schemaIDs := sub(&definition.DefinitionSchema{}, "id")
+		responseIDs := sub(&definition.DefinitionResponse{}, "id")
+		parameterIDs := sub(&global.GlobalParameter{}, "id")
+		iterationIDs := sub(&iteration.Iteration{}, "id")
+		proposalIDs := sub(&proposal.Proposal{}, "id")
+
+		// 先删除依赖项目内容的数据，再删除项目内容本身
+		children := []struct {
+			model interface{}
+			query string
+			sub   *gorm.DB
+		}{
+			{&collection.CollectionHistory{}, "collection_id IN (?)", collectionIDs},
+			{&collection.TagToCollection{}, "collection_id IN (?)", collectionIDs},
+			{&referencerelation.RefSchemaCollections{}, "collection_id IN (?)", collectionIDs},
+			{&referencerelation.RefResponseCollections{}, "collection_id IN (?)", collectionIDs},
+			{&referencerelation.ExceptParamCollection{}, "collection_id IN (?)", collectionIDs},
+			{&referencerelation.RefSchemaSchemas{}, "schema_id IN (?)", schemaIDs},
+			{&referencerelation.RefSchemaResponses{}, "response_id IN (?)", responseIDs},
+			{&referencerelation.DerefSnapshot{}, "ref_type = '" + referencerelation.SnapshotSchema + "' AND ref_id IN (?)", schemaIDs},
+			{&referencerelation.DerefSnapshot{}, "ref_type = '" + referencerelation.SnapshotResponse + "' AND ref_id IN (?)", responseIDs},
+			{&definition.DefinitionSchemaHistory{}, "schema_id IN (?)", schemaIDs},
+			{&definition.DefinitionResponseHistory{}, "response_id IN (?)", responseIDs},
+			{&global.GlobalParameterHistory{}, "parameter_id IN (?)", parameterIDs},
+			{&iteration.IterationApi{}, "iteration_id IN (?)", iterationIDs},
+			{&iteration.IterationBranchItem{}, "iteration_id IN (?)", iterationIDs},
+			{&iteration.IterationMergeRequest{}, "iteration_id IN (?)", iterationIDs},
+			{&proposal.ProposalComment{}, "proposal_id IN (?)", proposalIDs},
+		}
+		for _, c := range children {
+			if err := tx.Unscoped().Where(c.query, c.sub).Delete(c.model).Error; err != nil {
+				return err
+			}
+		}
+
+		owned := []interface{}{
+			&collection.Collection{},
+			&collection.Tag{},
+			&collection.TestCase{},
+			&definition.DefinitionSchema{},
+			&definition.DefinitionResponse{},
+			&definition.DefinitionParameter{},
+			&global.GlobalParameter{},
+			&iteration.Iteration{},
+			&proposal.Proposal{},
+			&proposal.Protection{},
+			&release.Release{},
+			&webhook.Delivery{},
+			&webhook.Webhook{},
+			&notification.Channel{},
+			&share.ShareTmpToken{},
+			&project.Server{},
+			&project.HistoryRetention{},
+			&embedding.Embedding{},
+			&translation.Translation{},
+			&job.Job{},
+			&project.ProjectMember{},
+		}
+		for _, m := range owned {
+			if err := tx.Unscoped().Where("project_id = ?", p.ID).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(p).Error
+	})
+}
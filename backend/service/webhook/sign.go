@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Apicat-Event"
+	HeaderDelivery  = "X-Apicat-Delivery"
+	HeaderTimestamp = "X-Apicat-Timestamp"
+	HeaderSignature = "X-Apicat-Signature"
+)
+
+// 重试间隔上限
+const maxBackoff = time.Hour
+
+// Sign 使用HMAC-SHA256对"timestamp.body"签名，接收方可以据此校验请求来源和防止重放
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff 第attempt次失败后的重试间隔，每次翻倍
+func Backoff(interval int, attempt int) time.Duration {
+	if interval <= 0 {
+		interval = 1
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := time.Duration(interval) * time.Second
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	if d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	got := Sign("secret", 1700000000, []byte(`{"event":"ping"}`))
+	want := "sha256=4d39bd2442f073b6bc62e95d0297ce25475582a17389ab860abdc778fe1d9f77"
+	if got != want {
+		t.Fatalf("Sign() = %s, want %s", got, want)
+	}
+	if got == Sign("other", 1700000000, []byte(`{"event":"ping"}`)) {
+		t.Fatal("signature should depend on secret")
+	}
+	if got == Sign("secret", 1700000001, []byte(`{"event":"ping"}`)) {
+		t.Fatal("signature should depend on timestamp")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := []struct {
+		interval int
+		attempt  int
+		want     time.Duration
+	}{
+		{10, 1, 10 * time.Second},
+		{10, 2, 20 * time.Second},
+		{10, 4, 80 * time.Second},
+		{0, 1, time.Second},
+		{600, 10, time.Hour},
+	}
+	for _, c := range cases {
+		if got := Backoff(c.interval, c.attempt); got != c.want {
+			t.Errorf("Backoff(%d, %d) = %v, want %v", c.interval, c.attempt, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model/audit"
+	"github.com/apicat/apicat/v2/backend/model/project"
+	"github.com/apicat/apicat/v2/backend/model/webhook"
+	"github.com/apicat/apicat/v2/backend/route/middleware/access"
+	"github.com/apicat/apicat/v2/backend/route/middleware/jwt"
+	auditservice "github.com/apicat/apicat/v2/backend/service/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PayloadProject struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type PayloadOperator struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type PayloadTarget struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Payload 投递给webhook的请求体
+type Payload struct {
+	Event     string           `json:"event"`
+	Timestamp int64            `json:"timestamp"`
+	Project   PayloadProject   `json:"project"`
+	Operator  *PayloadOperator `json:"operator,omitempty"`
+	Target    *PayloadTarget   `json:"target,omitempty"`
+	Changes   []audit.Change   `json:"changes,omitempty"`
+}
+
+// 变更对象和操作对应的事件
+var events = map[string]map[string]string{
+	audit.TargetCollection: {
+		audit.ActionCreate:  webhook.EventCollectionCreated,
+		audit.ActionCopy:    webhook.EventCollectionCreated,
+		audit.ActionUpdate:  webhook.EventCollectionUpdated,
+		audit.ActionMove:    webhook.EventCollectionUpdated,
+		audit.ActionRestore: webhook.EventCollectionUpdated,
+		audit.ActionDelete:  webhook.EventCollectionDeleted,
+	},
+	audit.TargetDefinitionSchema: {
+		audit.ActionCreate:  webhook.EventDefinitionSchemaCreated,
+		audit.ActionCopy:    webhook.EventDefinitionSchemaCreated,
+		audit.ActionUpdate:  webhook.EventDefinitionSchemaUpdated,
+		audit.ActionMove:    webhook.EventDefinitionSchemaUpdated,
+		audit.ActionRestore: webhook.EventDefinitionSchemaUpdated,
+		audit.ActionDelete:  webhook.EventDefinitionSchemaDeleted,
+	},
+	audit.TargetDefinitionResponse: {
+		audit.ActionCreate:  webhook.EventDefinitionResponseCreated,
+		audit.ActionCopy:    webhook.EventDefinitionResponseCreated,
+		audit.ActionUpdate:  webhook.EventDefinitionResponseUpdated,
+		audit.ActionMove:    webhook.EventDefinitionResponseUpdated,
+		audit.ActionRestore: webhook.EventDefinitionResponseUpdated,
+		audit.ActionDelete:  webhook.EventDefinitionResponseDeleted,
+	},
+	audit.TargetGlobalParameter: {
+		audit.ActionCreate: webhook.EventGlobalParameterCreated,
+		audit.ActionUpdate: webhook.EventGlobalParameterUpdated,
+		audit.ActionDelete: webhook.EventGlobalParameterDeleted,
+	},
+	audit.TargetIteration: {
+		audit.ActionCreate: webhook.EventIterationCreated,
+		audit.ActionUpdate: webhook.EventIterationUpdated,
+		audit.ActionDelete: webhook.EventIterationDeleted,
+		audit.ActionMerge:  webhook.EventIterationMerged,
+	},
+	audit.TargetRelease: {
+		audit.ActionCreate: webhook.EventReleaseCreated,
+		audit.ActionDelete: webhook.EventReleaseDeleted,
+	},
+}
+
+// EventOf 获取变更操作对应的webhook事件，没有对应事件时返回空
+func EventOf(targetType, action string) string {
+	if m, ok := events[targetType]; ok {
+		return m[action]
+	}
+	return ""
+}
+
+// Notify 根据变更操作触发项目webhook，projectID为空时从请求上下文中获取
+// 只写入待投递记录，由后台worker负责发送，失败只打印日志
+func Notify(ctx *gin.Context, projectID string, e *auditservice.Entry) {
+	event := EventOf(e.TargetType, e.Action)
+	if event == "" {
+		return
+	}
+
+	p := access.GetSelfProject(ctx)
+	if p == nil || (projectID != "" && p.ID != projectID) {
+		p = &project.Project{ID: projectID}
+		if exist, err := p.Get(ctx); err != nil || !exist {
+			return
+		}
+	}
+
+	list, err := webhook.GetSubscribedWebhooks(ctx, p.ID, event)
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook.GetSubscribedWebhooks", "err", err, "project", p.ID)
+		return
+	}
+	if len(list) == 0 {
+		return
+	}
+
+	payload := &Payload{
+		Event:     event,
+		Timestamp: time.Now().Unix(),
+		Project:   PayloadProject{ID: p.ID, Title: p.Title},
+		Target: &PayloadTarget{
+			Type: e.TargetType,
+			ID:   fmt.Sprint(e.TargetID),
+			Name: e.TargetName,
+		},
+		Changes: auditservice.Diff(e.Before, e.After),
+	}
+	if u := jwt.GetUser(ctx); u != nil {
+		payload.Operator = &PayloadOperator{ID: u.ID, Name: u.Name, Email: u.Email}
+	}
+
+	for _, w := range list {
+		if _, err := Enqueue(ctx, w, payload); err != nil {
+			slog.ErrorContext(ctx, "webhook.Enqueue", "err", err, "webhook", w.ID, "event", event)
+		}
+	}
+}
+
+// Ping 向webhook发送一条测试事件
+func Ping(ctx *gin.Context, w *webhook.Webhook) (*webhook.Delivery, error) {
+	payload := &Payload{
+		Event:     webhook.EventPing,
+		Timestamp: time.Now().Unix(),
+		Project:   PayloadProject{ID: w.ProjectID},
+	}
+	if p := access.GetSelfProject(ctx); p != nil {
+		payload.Project.Title = p.Title
+	}
+	if u := jwt.GetUser(ctx); u != nil {
+		payload.Operator = &PayloadOperator{ID: u.ID, Name: u.Name, Email: u.Email}
+	}
+	return Enqueue(ctx, w, payload)
+}
+
+// Enqueue 写入一条待投递记录并唤醒worker
+func Enqueue(ctx *gin.Context, w *webhook.Webhook, payload *Payload) (*webhook.Delivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	d := &webhook.Delivery{
+		WebhookID: w.ID,
+		ProjectID: w.ProjectID,
+		Event:     payload.Event,
+		Payload:   string(body),
+	}
+	if err := d.Create(ctx); err != nil {
+		return nil, err
+	}
+	Wake()
+	return d, nil
+}
@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/model/webhook"
+	"github.com/apicat/apicat/v2/backend/utils/safehttp"
+)
+
+const (
+	pollInterval    = 10 * time.Second
+	batchSize       = 50
+	claimLease      = time.Minute
+	requestTimeout  = 10 * time.Second
+	maxResponseBody = 4096
+	maxErrorLength  = 1024
+)
+
+var (
+	wakeup = make(chan struct{}, 1)
+	// client 不允许投递到本机和内网地址，不跟随重定向，避免通过投递记录读取内网服务
+	client = safehttp.NewClient(requestTimeout)
+)
+
+// Wake 唤醒worker立即处理待投递记录
+func Wake() {
+	select {
+	case wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// Run 后台投递worker，定时轮询到期的投递记录，直到ctx结束
+func Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		process(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wakeup:
+		}
+	}
+}
+
+func process(ctx context.Context) {
+	for {
+		list, err := webhook.GetDueDeliveries(ctx, batchSize)
+		if err != nil {
+			slog.ErrorContext(ctx, "webhook.GetDueDeliveries", "err", err)
+			return
+		}
+		for _, d := range list {
+			if ok, err := d.Claim(ctx, claimLease); err != nil || !ok {
+				continue
+			}
+			deliver(ctx, d)
+		}
+		if len(list) < batchSize {
+			return
+		}
+	}
+}
+
+func deliver(ctx context.Context, d *webhook.Delivery) {
+	w := &webhook.Webhook{ID: d.WebhookID}
+	exist, err := w.Get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook.Get", "err", err, "webhook", d.WebhookID)
+		return
+	}
+	if !exist || !w.Active {
+		d.Status = webhook.DeliveryFailed
+		d.Error = "webhook is disabled or deleted"
+		if err := d.SaveAttempt(ctx); err != nil {
+			slog.ErrorContext(ctx, "delivery.SaveAttempt", "err", err, "delivery", d.ID)
+		}
+		return
+	}
+
+	d.Attempts++
+	status, body, duration, err := send(ctx, w, d)
+	d.ResponseStatus = status
+	d.ResponseBody = body
+	d.Duration = duration.Milliseconds()
+	d.Error = ""
+
+	if err == nil && status >= 200 && status < 300 {
+		d.Status = webhook.DeliverySuccess
+	} else {
+		if err != nil {
+			d.Error = truncate(err.Error(), maxErrorLength)
+		} else {
+			d.Error = "unexpected status code " + strconv.Itoa(status)
+		}
+		if d.Attempts > w.MaxRetries {
+			d.Status = webhook.DeliveryFailed
+		} else {
+			d.NextAttemptAt = time.Now().Add(Backoff(w.RetryInterval, d.Attempts))
+		}
+	}
+
+	if err := d.SaveAttempt(ctx); err != nil {
+		slog.ErrorContext(ctx, "delivery.SaveAttempt", "err", err, "delivery", d.ID)
+	}
+}
+
+func send(ctx context.Context, w *webhook.Webhook, d *webhook.Delivery) (int, string, time.Duration, error) {
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", 0, err
+	}
+
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ApiCat-Webhook")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(d.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	if w.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(w.Secret, ts, body))
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		return 0, "", duration, err
+	}
+	defer resp.Body.Close()
+
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	return resp.StatusCode, string(b), duration, nil
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
@@ -0,0 +1,75 @@
+package delta
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 差量格式: <前缀长度>:<后缀长度>:<中间替换内容>，长度均为字节数
+// 历史记录多为局部修改，只保存与基准内容不同的中间部分即可
+
+var ErrInvalidDelta = errors.New("invalid delta")
+
+// Diff 计算由base得到target的差量
+func Diff(base, target string) string {
+	prefix := 0
+	for prefix < len(base) && prefix < len(target) && base[prefix] == target[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(base)-prefix && suffix < len(target)-prefix &&
+		base[len(base)-1-suffix] == target[len(target)-1-suffix] {
+		suffix++
+	}
+	// 边界退回到字符起始位置，避免拆分多字节字符产生非法 UTF-8
+	for prefix > 0 && (prefix < len(base) && !utf8.RuneStart(base[prefix]) || prefix < len(target) && !utf8.RuneStart(target[prefix])) {
+		prefix--
+	}
+	for suffix > 0 && !utf8.RuneStart(target[len(target)-suffix]) {
+		suffix--
+	}
+	return strconv.Itoa(prefix) + ":" + strconv.Itoa(suffix) + ":" + target[prefix:len(target)-suffix]
+}
+
+// Patch 将差量应用到base上还原内容
+func Patch(base, d string) (string, error) {
+	parts := strings.SplitN(d, ":", 3)
+	if len(parts) != 3 {
+		return "", ErrInvalidDelta
+	}
+	prefix, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", ErrInvalidDelta
+	}
+	suffix, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", ErrInvalidDelta
+	}
+	if prefix < 0 || suffix < 0 || prefix+suffix > len(base) {
+		return "", ErrInvalidDelta
+	}
+	return base[:prefix] + parts[2] + base[len(base)-suffix:], nil
+}
+
+// Worthwhile 差量明显小于原内容时才值得保存差量
+func Worthwhile(d, target string) bool {
+	return len(d)*2 < len(target)
+}
+
+// KeyframeInterval 差量存储时每隔多少条记录完整保存一次
+const KeyframeInterval = 10
+
+// BaseIndex 按时间升序排列的n条记录中，第i条记录应作为差量基准的记录下标，返回-1表示完整保存
+// 最新的两条记录总是完整保存，因为最新记录可能还会被合并修改，不能作为基准
+func BaseIndex(n, i int) int {
+	if i >= n-2 {
+		return -1
+	}
+	pos := n - 2 - i
+	if pos%KeyframeInterval == 0 {
+		return -1
+	}
+	return n - 2 - pos/KeyframeInterval*KeyframeInterval
+}
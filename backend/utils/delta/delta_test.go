@@ -0,0 +1,66 @@
+package delta
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDiffPatch(t *testing.T) {
+	cases := [][2]string{
+		{"", ""},
+		{"", "abc"},
+		{"abc", ""},
+		{"abc", "abc"},
+		{`{"a":1,"b":2}`, `{"a":1,"b":3}`},
+		{`{"a":1}`, `{"a":1,"c":"x:y"}`},
+		{"aaaa", "aa"},
+		{"aa", "aaaa"},
+		{"中文内容", "中文的内容"},
+		{`{"title":"价格"}`, `{"title":"价值"}`},
+		{"价格", "价"},
+		{"a价", "a值"},
+	}
+	for _, c := range cases {
+		d := Diff(c[0], c[1])
+		if !utf8.ValidString(d) {
+			t.Errorf("Diff(%q, %q) = %q is not valid UTF-8", c[0], c[1], d)
+		}
+		got, err := Patch(c[0], d)
+		if err != nil {
+			t.Fatalf("Patch(%q, %q): %v", c[0], d, err)
+		}
+		if got != c[1] {
+			t.Errorf("Patch(%q, Diff(%q, %q)) = %q", c[0], c[0], c[1], got)
+		}
+	}
+}
+
+func TestPatchInvalid(t *testing.T) {
+	for _, d := range []string{"", "1", "x:1:", "1:x:", "3:3:", "-1:0:"} {
+		if _, err := Patch("abcd", d); err == nil {
+			t.Errorf("Patch(%q) expected error", d)
+		}
+	}
+}
+
+func TestBaseIndex(t *testing.T) {
+	n := 25
+	for i := 0; i < n; i++ {
+		b := BaseIndex(n, i)
+		if i >= n-2 {
+			if b != -1 {
+				t.Errorf("BaseIndex(%d, %d) = %d, want -1", n, i, b)
+			}
+			continue
+		}
+		if b == -1 {
+			if (n-2-i)%KeyframeInterval != 0 {
+				t.Errorf("BaseIndex(%d, %d) unexpected keyframe", n, i)
+			}
+			continue
+		}
+		if b <= i || b >= n-1 || BaseIndex(n, b) != -1 {
+			t.Errorf("BaseIndex(%d, %d) = %d, invalid base", n, i, b)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 实现 RFC 6902 中的 add、remove、replace 操作，满足文档修改建议的需要
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+type Operation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+var ErrInvalidPath = errors.New("invalid json pointer")
+
+// Apply 将操作依次应用到JSON文档上，任一操作失败则返回错误
+func Apply(doc []byte, ops []Operation) ([]byte, error) {
+	var root any
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, err
+	}
+
+	for _, op := range ops {
+		tokens, err := parsePointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if root, err = apply(root, tokens, op); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(root)
+}
+
+// parsePointer 解析 JSON Pointer(RFC 6901)
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, ErrInvalidPath
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func apply(node any, tokens []string, op Operation) (any, error) {
+	if len(tokens) == 0 {
+		switch op.Op {
+		case OpAdd, OpReplace:
+			return op.Value, nil
+		case OpRemove:
+			return nil, errors.New("cannot remove the whole document")
+		default:
+			return nil, fmt.Errorf("unsupported operation")
+		}
+	}
+
+	key, rest := tokens[0], tokens[1:]
+	switch v := node.(type) {
+	case map[string]any:
+		child, ok := v[key]
+		if len(rest) > 0 {
+			if !ok {
+				return nil, ErrInvalidPath
+			}
+			newChild, err := apply(child, rest, op)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = newChild
+			return v, nil
+		}
+		switch op.Op {
+		case OpAdd:
+			v[key] = op.Value
+		case OpReplace:
+			if !ok {
+				return nil, ErrInvalidPath
+			}
+			v[key] = op.Value
+		case OpRemove:
+			if !ok {
+				return nil, ErrInvalidPath
+			}
+			delete(v, key)
+		default:
+			return nil, fmt.Errorf("unsupported operation")
+		}
+		return v, nil
+	case []any:
+		if len(rest) == 0 && op.Op == OpAdd && key == "-" {
+			return append(v, op.Value), nil
+		}
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 {
+			return nil, ErrInvalidPath
+		}
+		if len(rest) > 0 {
+			if i >= len(v) {
+				return nil, ErrInvalidPath
+			}
+			newChild, err := apply(v[i], rest, op)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = newChild
+			return v, nil
+		}
+		switch op.Op {
+		case OpAdd:
+			if i > len(v) {
+				return nil, ErrInvalidPath
+			}
+			v = append(v, nil)
+			copy(v[i+1:], v[i:])
+			v[i] = op.Value
+		case OpReplace:
+			if i >= len(v) {
+				return nil, ErrInvalidPath
+			}
+			v[i] = op.Value
+		case OpRemove:
+			if i >= len(v) {
+				return nil, ErrInvalidPath
+			}
+			v = append(v[:i], v[i+1:]...)
+		default:
+			return nil, fmt.Errorf("unsupported operation")
+		}
+		return v, nil
+	default:
+		return nil, ErrInvalidPath
+	}
+}
@@ -0,0 +1,40 @@
+package jsonpatch
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	doc := `{"a":{"b":1},"list":[1,2,3],"x/y":"z"}`
+	cases := []struct {
+		ops  []Operation
+		want string
+	}{
+		{[]Operation{{Op: OpReplace, Path: "/a/b", Value: 2}}, `{"a":{"b":2},"list":[1,2,3],"x/y":"z"}`},
+		{[]Operation{{Op: OpAdd, Path: "/a/c", Value: "d"}}, `{"a":{"b":1,"c":"d"},"list":[1,2,3],"x/y":"z"}`},
+		{[]Operation{{Op: OpRemove, Path: "/list/1"}}, `{"a":{"b":1},"list":[1,3],"x/y":"z"}`},
+		{[]Operation{{Op: OpAdd, Path: "/list/0", Value: 0}}, `{"a":{"b":1},"list":[0,1,2,3],"x/y":"z"}`},
+		{[]Operation{{Op: OpAdd, Path: "/list/-", Value: 4}}, `{"a":{"b":1},"list":[1,2,3,4],"x/y":"z"}`},
+		{[]Operation{{Op: OpReplace, Path: "/x~1y", Value: "w"}}, `{"a":{"b":1},"list":[1,2,3],"x/y":"w"}`},
+	}
+	for _, c := range cases {
+		got, err := Apply([]byte(doc), c.ops)
+		if err != nil {
+			t.Fatalf("Apply(%v) error: %s", c.ops, err)
+		}
+		if string(got) != c.want {
+			t.Errorf("Apply(%v) = %s, want %s", c.ops, got, c.want)
+		}
+	}
+
+	invalid := [][]Operation{
+		{{Op: OpReplace, Path: "/a/missing", Value: 1}},
+		{{Op: OpRemove, Path: "/list/5"}},
+		{{Op: OpAdd, Path: "a/b", Value: 1}},
+		{{Op: "move", Path: "/a/b"}},
+		{{Op: OpReplace, Path: "/a/b/c", Value: 1}},
+	}
+	for _, ops := range invalid {
+		if _, err := Apply([]byte(doc), ops); err == nil {
+			t.Errorf("Apply(%v) should fail", ops)
+		}
+	}
+}
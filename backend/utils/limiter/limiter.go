@@ -0,0 +1,160 @@
+package limiter
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/module/cache/common"
+)
+
+// Rule 限制规则
+type Rule struct {
+	// 不触发延迟的最大失败次数
+	FreeAttempts int
+	// 第一次延迟的时长，之后每次失败翻倍
+	BaseDelay time.Duration
+	// 最大延迟时长
+	MaxDelay time.Duration
+	// 达到此失败次数后锁定，0表示不锁定
+	LockAttempts int
+	// 锁定时长
+	LockDuration time.Duration
+	// 失败记录的保存时长
+	Window time.Duration
+}
+
+type Limiter struct {
+	c      common.Cache
+	prefix string
+	rule   Rule
+}
+
+func NewLimiter(c common.Cache, prefix string, rule Rule) *Limiter {
+	return &Limiter{
+		c:      c,
+		prefix: prefix,
+		rule:   rule,
+	}
+}
+
+// 失败次数、最近一次失败时间和锁定截止时间分开保存，失败次数通过缓存的原子计数累加
+const (
+	failuresKey = "failures"
+	lastKey     = "last"
+	lockedKey   = "locked"
+)
+
+func (l *Limiter) key(k, field string) string {
+	return fmt.Sprintf("limiter-%s-%s-%s", l.prefix, k, field)
+}
+
+// loadInt 读取保存的整数，不存在或无法解析时返回0
+func (l *Limiter) loadInt(k, field string) int64 {
+	v, ok, _ := l.c.Get(l.key(k, field))
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func (l *Limiter) saveInt(k, field string, n int64, ttl time.Duration) error {
+	return l.c.Set(l.key(k, field), strconv.FormatInt(n, 10), ttl)
+}
+
+// lockedUntil 返回未过期的锁定截止时间，未锁定时返回零值
+func (l *Limiter) lockedUntil(k string) time.Time {
+	v := l.loadInt(k, lockedKey)
+	if v == 0 {
+		return time.Time{}
+	}
+	if until := time.Unix(v, 0); until.After(time.Now()) {
+		return until
+	}
+	return time.Time{}
+}
+
+// delay 根据失败次数计算需要等待的时长
+func (l *Limiter) delay(failures int) time.Duration {
+	n := failures - l.rule.FreeAttempts
+	if n <= 0 || l.rule.BaseDelay <= 0 {
+		return 0
+	}
+	d := l.rule.BaseDelay
+	for i := 1; i < n; i++ {
+		d *= 2
+		if l.rule.MaxDelay > 0 && d >= l.rule.MaxDelay {
+			return l.rule.MaxDelay
+		}
+	}
+	if l.rule.MaxDelay > 0 && d > l.rule.MaxDelay {
+		return l.rule.MaxDelay
+	}
+	return d
+}
+
+// Wait 返回再次尝试前需要等待的时长，0表示可以立即尝试
+func (l *Limiter) Wait(k string) time.Duration {
+	if until := l.lockedUntil(k); !until.IsZero() {
+		return time.Until(until)
+	}
+	if d := l.delay(int(l.loadInt(k, failuresKey))); d > 0 {
+		if next := time.Unix(l.loadInt(k, lastKey), 0).Add(d); next.After(time.Now()) {
+			return time.Until(next)
+		}
+	}
+	return 0
+}
+
+// Locked 是否处于锁定状态，以及剩余的锁定时长
+func (l *Limiter) Locked(k string) (bool, time.Duration) {
+	until := l.lockedUntil(k)
+	if until.IsZero() {
+		return false, 0
+	}
+	d := time.Until(until)
+	if d <= 0 {
+		return false, 0
+	}
+	return true, d
+}
+
+// Fail 记录一次失败，返回本次失败是否触发了锁定
+func (l *Limiter) Fail(k string) (bool, error) {
+	if !l.lockedUntil(k).IsZero() {
+		// 锁定期间不再计数，锁定过期后重新计数
+		return false, nil
+	}
+
+	failures, err := l.c.Incr(l.key(k, failuresKey), l.rule.Window)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	if err := l.saveInt(k, lastKey, now.Unix(), l.rule.Window); err != nil {
+		return false, err
+	}
+
+	// 计数是原子递增的，只有恰好达到锁定次数的那一次失败会触发锁定
+	if l.rule.LockAttempts <= 0 || failures != int64(l.rule.LockAttempts) {
+		return false, nil
+	}
+	if err := l.saveInt(k, lockedKey, now.Add(l.rule.LockDuration).Unix(), l.rule.LockDuration); err != nil {
+		return false, err
+	}
+	return true, l.c.Del(l.key(k, failuresKey))
+}
+
+// Reset 清除失败记录和锁定状态
+func (l *Limiter) Reset(k string) error {
+	for _, field := range []string{failuresKey, lastKey, lockedKey} {
+		if err := l.c.Del(l.key(k, field)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
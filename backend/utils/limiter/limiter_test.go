@@ -0,0 +1,130 @@
+package limiter
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apicat/apicat/v2/backend/module/cache"
+)
+
+func newTestLimiter(t *testing.T, rule Rule) *Limiter {
+	cfg := cache.Cache{Driver: cache.MEMORY}
+	if err := cache.Init(cfg); err != nil {
+		t.Fatal(err)
+	}
+	c, err := cache.NewCache(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewLimiter(c, "test", rule)
+}
+
+func TestDelay(t *testing.T) {
+	l := newTestLimiter(t, Rule{
+		FreeAttempts: 2,
+		BaseDelay:    time.Second,
+		MaxDelay:     time.Second * 5,
+	})
+
+	cases := map[int]time.Duration{
+		0: 0,
+		2: 0,
+		3: time.Second,
+		4: time.Second * 2,
+		5: time.Second * 4,
+		6: time.Second * 5,
+		9: time.Second * 5,
+	}
+	for failures, want := range cases {
+		if got := l.delay(failures); got != want {
+			t.Errorf("delay(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
+
+func TestWaitAndLock(t *testing.T) {
+	l := newTestLimiter(t, Rule{
+		FreeAttempts: 1,
+		BaseDelay:    time.Minute,
+		MaxDelay:     time.Hour,
+		LockAttempts: 3,
+		LockDuration: time.Hour,
+		Window:       time.Hour,
+	})
+
+	key := "user@example.com"
+	if w := l.Wait(key); w != 0 {
+		t.Fatalf("wait before failure = %s, want 0", w)
+	}
+
+	if locked, err := l.Fail(key); err != nil || locked {
+		t.Fatalf("first failure locked=%v err=%v", locked, err)
+	}
+	if w := l.Wait(key); w != 0 {
+		t.Fatalf("wait after free failure = %s, want 0", w)
+	}
+
+	if locked, err := l.Fail(key); err != nil || locked {
+		t.Fatalf("second failure locked=%v err=%v", locked, err)
+	}
+	if w := l.Wait(key); w <= 0 || w > time.Minute {
+		t.Fatalf("wait after second failure = %s, want (0, 1m]", w)
+	}
+
+	locked, err := l.Fail(key)
+	if err != nil || !locked {
+		t.Fatalf("third failure locked=%v err=%v", locked, err)
+	}
+	if ok, d := l.Locked(key); !ok || d <= 0 {
+		t.Fatalf("Locked() = %v, %s", ok, d)
+	}
+	if locked, _ := l.Fail(key); locked {
+		t.Fatal("lock notification should only be reported once")
+	}
+
+	if err := l.Reset(key); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := l.Locked(key); ok {
+		t.Fatal("still locked after reset")
+	}
+	if w := l.Wait(key); w != 0 {
+		t.Fatalf("wait after reset = %s, want 0", w)
+	}
+}
+
+func TestConcurrentFail(t *testing.T) {
+	l := newTestLimiter(t, Rule{
+		LockAttempts: 5,
+		LockDuration: time.Hour,
+		Window:       time.Hour,
+	})
+
+	var (
+		wg     sync.WaitGroup
+		locked atomic.Int32
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := l.Fail("127.0.0.1")
+			if err != nil {
+				t.Error(err)
+			}
+			if ok {
+				locked.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := locked.Load(); n != 1 {
+		t.Fatalf("lock triggered %d times, want 1", n)
+	}
+	if ok, _ := l.Locked("127.0.0.1"); !ok {
+		t.Fatal("not locked after concurrent failures")
+	}
+}
@@ -0,0 +1,77 @@
+package safehttp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrForbiddenAddress = errors.New("requests to loopback, link-local or private addresses are not allowed")
+	ErrInvalidURL       = errors.New("url must be an absolute http or https url")
+)
+
+// NewClient 请求用户填写的地址，不允许访问本机和内网地址，也不使用代理，避免绕过地址检查
+// 不跟随重定向，直接返回重定向响应
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: timeout,
+				Control: checkDialAddress,
+			}).DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: NoRedirect,
+	}
+}
+
+// NoRedirect 不跟随重定向，直接返回重定向响应
+func NoRedirect(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// CheckURL 保存地址时检查协议和主机，域名在请求时解析后再检查实际连接的地址
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidURL
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && !AllowedIP(ip) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// checkDialAddress 在域名解析之后检查实际连接的地址
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !AllowedIP(ip) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// AllowedIP 是否为可以访问的公网地址
+func AllowedIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
@@ -0,0 +1,64 @@
+package safehttp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAllowedIP(t *testing.T) {
+	cases := map[string]bool{
+		"8.8.8.8":          true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"0.0.0.0":          false,
+		"::ffff:127.0.0.1": false,
+	}
+	for addr, want := range cases {
+		if got := AllowedIP(net.ParseIP(addr)); got != want {
+			t.Errorf("AllowedIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	cases := map[string]error{
+		"https://example.com/hook":                 nil,
+		"http://8.8.8.8:8080/hook":                 nil,
+		"ftp://example.com":                        ErrInvalidURL,
+		"example.com/hook":                         ErrInvalidURL,
+		"https://":                                 ErrInvalidURL,
+		"http://localhost:8080":                    ErrForbiddenAddress,
+		"http://api.localhost":                     ErrForbiddenAddress,
+		"http://127.0.0.1/hook":                    ErrForbiddenAddress,
+		"http://169.254.169.254/latest/meta-data/": ErrForbiddenAddress,
+		"http://[::1]:9000":                        ErrForbiddenAddress,
+		"http://192.168.1.10":                      ErrForbiddenAddress,
+	}
+	for u, want := range cases {
+		if got := CheckURL(u); !errors.Is(got, want) {
+			t.Errorf("CheckURL(%s) = %v, want %v", u, got, want)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	if _, err := NewClient(time.Second).Get(srv.URL); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Get loopback err = %v, want %v", err, ErrForbiddenAddress)
+	}
+}